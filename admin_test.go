@@ -67,7 +67,7 @@ func TestDropData(t *testing.T) {
 
 			schema, err := client.GetSchema(ctx)
 			require.NoError(t, err, "GetSchema should succeed")
-			require.Contains(t, schema, "type TestEntity")
+			require.Contains(t, schema.String(), "type TestEntity")
 		})
 	}
 }
@@ -125,7 +125,7 @@ func TestDropAll(t *testing.T) {
 
 			schema, err := client.GetSchema(ctx)
 			require.NoError(t, err, "GetSchema should succeed")
-			require.NotContains(t, schema, "type TestEntity")
+			require.NotContains(t, schema.String(), "type TestEntity")
 		})
 	}
 }
@@ -183,8 +183,8 @@ func TestCreateSchema(t *testing.T) {
 
 			schema, err := client.GetSchema(context.Background())
 			require.NoError(t, err, "GetSchema should succeed")
-			require.Contains(t, schema, "type Struct1")
-			require.Contains(t, schema, "type Struct2")
+			require.Contains(t, schema.String(), "type Struct1")
+			require.Contains(t, schema.String(), "type Struct2")
 
 			err = client.DropAll(context.Background())
 			require.NoError(t, err, "DropAll should succeed")
@@ -195,9 +195,9 @@ func TestCreateSchema(t *testing.T) {
 
 			schema, err = client.GetSchema(context.Background())
 			require.NoError(t, err, "GetSchema should succeed")
-			require.Contains(t, schema, "type Struct1")
-			require.Contains(t, schema, "type Struct2")
-			require.Contains(t, schema, "type Struct3")
+			require.Contains(t, schema.String(), "type Struct1")
+			require.Contains(t, schema.String(), "type Struct2")
+			require.Contains(t, schema.String(), "type Struct3")
 		})
 	}
 }
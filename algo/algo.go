@@ -0,0 +1,137 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package algo runs graph algorithms over the uid edges of a modusGraph
+// client: PageRank, weakly connected components and degree centrality. The
+// edges are read with Client.Edges, which streams them from storage on
+// embedded clients, so memory grows with the number of nodes rather than
+// with the number of edges. PageRank reads the edges once per iteration.
+//
+// Each algorithm returns a result per node UID, covering the nodes that hold
+// or are the target of at least one of the followed edges. Write stores a
+// result back on the nodes as a predicate:
+//
+//	ranks, err := algo.PageRank(ctx, client, algo.PageRankOptions{
+//	    Predicates: []string{"cites"},
+//	})
+//	err = algo.Write(ctx, client, "rank", ranks)
+package algo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	mg "github.com/matthewmcneely/modusgraph"
+)
+
+// writeBatchSize is how many values Write sets per transaction.
+const writeBatchSize = 1000
+
+// errNoPredicates is returned by the algorithms when given no edges to follow.
+var errNoPredicates = errors.New("modusgraph/algo: at least one edge predicate is required")
+
+// graph numbers the nodes the followed edges touch, so per-node state can be
+// kept in slices, and counts each node's edges.
+type graph struct {
+	index map[uint64]int
+	uids  []uint64
+	out   []int
+	in    []int
+}
+
+func (g *graph) add(uid uint64) int {
+	i, ok := g.index[uid]
+	if !ok {
+		i = len(g.uids)
+		g.index[uid] = i
+		g.uids = append(g.uids, uid)
+		g.out = append(g.out, 0)
+		g.in = append(g.in, 0)
+	}
+	return i
+}
+
+// scan calls fn with every edge of preds, stopping at the first error.
+func scan(ctx context.Context, c mg.Client, preds []string, fn func(mg.Edge)) error {
+	if len(preds) == 0 {
+		return errNoPredicates
+	}
+	for _, pred := range preds {
+		for e, err := range c.Edges(ctx, pred) {
+			if err != nil {
+				return err
+			}
+			fn(e)
+		}
+	}
+	return nil
+}
+
+// load scans preds once, numbering and counting the edges of every node.
+func load(ctx context.Context, c mg.Client, preds []string) (*graph, error) {
+	g := &graph{index: map[uint64]int{}}
+	err := scan(ctx, c, preds, func(e mg.Edge) {
+		g.out[g.add(e.From)]++
+		g.in[g.add(e.To)]++
+	})
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// scan calls fn with the numbers of each edge's ends, skipping edges that
+// touch nodes added since g was loaded.
+func (g *graph) scan(ctx context.Context, c mg.Client, preds []string, fn func(from, to int)) error {
+	return scan(ctx, c, preds, func(e mg.Edge) {
+		from, ok := g.index[e.From]
+		if !ok {
+			return
+		}
+		if to, ok := g.index[e.To]; ok {
+			fn(from, to)
+		}
+	})
+}
+
+// Write sets pred to each node's value in results, replacing any value it
+// had, in transactions of a thousand nodes. Floats are stored as float and
+// integers as int, which Dgraph takes as the predicate's type when the
+// schema does not declare it.
+func Write[V float64 | int | uint64](ctx context.Context, c mg.Client, pred string, results map[uint64]V) error {
+	dg, cleanup, err := c.DgraphClient()
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+	var nquads strings.Builder
+	n := 0
+	flush := func() error {
+		if n == 0 {
+			return nil
+		}
+		_, err := dg.NewTxn().Mutate(ctx, &api.Mutation{SetNquads: []byte(nquads.String()), CommitNow: true})
+		nquads.Reset()
+		n = 0
+		return err
+	}
+	for uid, v := range results {
+		switch v := any(v).(type) {
+		case float64:
+			fmt.Fprintf(&nquads, "<%s> <%s> \"%g\"^^<xs:float> .\n", mg.UIDFromUint64(uid), pred, v)
+		default:
+			fmt.Fprintf(&nquads, "<%s> <%s> \"%d\"^^<xs:int> .\n", mg.UIDFromUint64(uid), pred, v)
+		}
+		if n++; n == writeBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
@@ -0,0 +1,129 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package algo_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/algo"
+	"github.com/stretchr/testify/require"
+)
+
+type Page struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"name,omitempty" dgraph:"index=exact"`
+	Links []*Page  `json:"links,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+// newGraph stores a, b and c linked in a cycle, d linking to c, e linking to
+// f, and g without links, and returns their UIDs by name.
+func newGraph(t *testing.T) (mg.Client, map[string]uint64) {
+	client, err := mg.NewClient("file://"+t.TempDir(), mg.WithAutoSchema(true))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		client.Close()
+		mg.Shutdown()
+	})
+	ctx := context.Background()
+	require.NoError(t, client.UpdateSchema(ctx, &Page{}))
+
+	dg, cleanup, err := client.DgraphClient()
+	require.NoError(t, err)
+	defer cleanup()
+	var nquads string
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		nquads += `_:` + name + ` <name> "` + name + `" .` + "\n"
+		nquads += `_:` + name + ` <dgraph.type> "Page" .` + "\n"
+	}
+	for _, link := range [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"d", "c"}, {"e", "f"}} {
+		nquads += `_:` + link[0] + ` <links> _:` + link[1] + ` .` + "\n"
+	}
+	resp, err := dg.NewTxn().Mutate(ctx, &api.Mutation{SetNquads: []byte(nquads), CommitNow: true})
+	require.NoError(t, err)
+	uids := map[string]uint64{}
+	for name, uid := range resp.Uids {
+		n, err := mg.ParseUID(uid)
+		require.NoError(t, err)
+		uids[name] = n.Uint64()
+	}
+	return client, uids
+}
+
+func TestPageRank(t *testing.T) {
+	client, uids := newGraph(t)
+	ctx := context.Background()
+	ranks, err := algo.PageRank(ctx, client, algo.PageRankOptions{Predicates: []string{"links"}})
+	require.NoError(t, err)
+
+	require.Len(t, ranks, 6)
+	require.NotContains(t, ranks, uids["g"])
+	var sum float64
+	for _, r := range ranks {
+		sum += r
+	}
+	require.InDelta(t, 1, sum, 1e-9)
+	require.Greater(t, ranks[uids["c"]], ranks[uids["a"]])
+	require.Greater(t, ranks[uids["a"]], ranks[uids["b"]])
+	require.Greater(t, ranks[uids["f"]], ranks[uids["e"]])
+	require.Equal(t, ranks[uids["d"]], ranks[uids["e"]])
+
+	require.NoError(t, algo.Write(ctx, client, "rank", ranks))
+	raw, err := client.QueryRaw(ctx, `{ p(func: eq(name, "c")) { rank } }`, nil)
+	require.NoError(t, err)
+	var resp struct {
+		P []struct{ Rank float64 }
+	}
+	require.NoError(t, json.Unmarshal(raw, &resp))
+	require.Len(t, resp.P, 1)
+	require.InDelta(t, ranks[uids["c"]], resp.P[0].Rank, 1e-9)
+}
+
+func TestConnectedComponents(t *testing.T) {
+	client, uids := newGraph(t)
+	labels, err := algo.ConnectedComponents(context.Background(), client,
+		algo.ComponentsOptions{Predicates: []string{"links"}})
+	require.NoError(t, err)
+
+	require.Len(t, labels, 6)
+	first := min(uids["a"], uids["b"], uids["c"], uids["d"])
+	for _, name := range []string{"a", "b", "c", "d"} {
+		require.Equal(t, first, labels[uids[name]], name)
+	}
+	second := min(uids["e"], uids["f"])
+	require.Equal(t, second, labels[uids["e"]])
+	require.Equal(t, second, labels[uids["f"]])
+}
+
+func TestDegreeCentrality(t *testing.T) {
+	client, uids := newGraph(t)
+	ctx := context.Background()
+	scores, err := algo.DegreeCentrality(ctx, client, algo.CentralityOptions{Predicates: []string{"links"}})
+	require.NoError(t, err)
+	require.InDelta(t, 3.0/5, scores[uids["c"]], 1e-9)
+	require.InDelta(t, 1.0/5, scores[uids["d"]], 1e-9)
+
+	scores, err = algo.DegreeCentrality(ctx, client, algo.CentralityOptions{
+		Predicates: []string{"links"},
+		Direction:  algo.In,
+	})
+	require.NoError(t, err)
+	require.InDelta(t, 2.0/5, scores[uids["c"]], 1e-9)
+	require.Zero(t, scores[uids["d"]])
+}
+
+func TestAlgorithmsRequireUIDPredicates(t *testing.T) {
+	client, _ := newGraph(t)
+	ctx := context.Background()
+	_, err := algo.PageRank(ctx, client, algo.PageRankOptions{})
+	require.ErrorContains(t, err, "edge predicate is required")
+	_, err = algo.ConnectedComponents(ctx, client, algo.ComponentsOptions{Predicates: []string{"name"}})
+	require.ErrorContains(t, err, "not a uid predicate")
+}
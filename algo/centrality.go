@@ -0,0 +1,57 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package algo
+
+import (
+	"context"
+
+	mg "github.com/matthewmcneely/modusgraph"
+)
+
+// Direction selects which edges of a node count towards its degree.
+type Direction int
+
+const (
+	// Both counts incoming and outgoing edges.
+	Both Direction = iota
+	// In counts the edges pointing at the node.
+	In
+	// Out counts the edges leaving the node.
+	Out
+)
+
+// CentralityOptions configures DegreeCentrality.
+type CentralityOptions struct {
+	// Predicates are the uid predicates whose edges are counted.
+	Predicates []string
+	// Direction defaults to Both.
+	Direction Direction
+}
+
+// DegreeCentrality scores each node by its number of edges divided by the
+// number of other nodes, so a node linked with every other node in one
+// direction scores 1. It reads the edges once.
+func DegreeCentrality(ctx context.Context, c mg.Client, opts CentralityOptions) (map[uint64]float64, error) {
+	g, err := load(ctx, c, opts.Predicates)
+	if err != nil {
+		return nil, err
+	}
+	scores := make(map[uint64]float64, len(g.uids))
+	others := float64(max(len(g.uids)-1, 1))
+	for i, uid := range g.uids {
+		var degree int
+		switch opts.Direction {
+		case In:
+			degree = g.in[i]
+		case Out:
+			degree = g.out[i]
+		default:
+			degree = g.in[i] + g.out[i]
+		}
+		scores[uid] = float64(degree) / others
+	}
+	return scores, nil
+}
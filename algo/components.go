@@ -0,0 +1,59 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package algo
+
+import (
+	"context"
+
+	mg "github.com/matthewmcneely/modusgraph"
+)
+
+// ComponentsOptions configures ConnectedComponents.
+type ComponentsOptions struct {
+	// Predicates are the uid predicates that connect nodes. Their direction
+	// is ignored.
+	Predicates []string
+}
+
+// ConnectedComponents groups the nodes joined by paths of edges, ignoring
+// their direction. Each node maps to the smallest UID of its component, so
+// two nodes are connected exactly when their labels are equal.
+func ConnectedComponents(ctx context.Context, c mg.Client, opts ComponentsOptions) (map[uint64]uint64, error) {
+	g, err := load(ctx, c, opts.Predicates)
+	if err != nil {
+		return nil, err
+	}
+	parent := make([]int, len(g.uids))
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	err = g.scan(ctx, c, opts.Predicates, func(from, to int) {
+		a, b := find(from), find(to)
+		if a == b {
+			return
+		}
+		// Keep the smaller UID as the root, so it labels the component.
+		if g.uids[b] < g.uids[a] {
+			a, b = b, a
+		}
+		parent[b] = a
+	})
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[uint64]uint64, len(g.uids))
+	for i, uid := range g.uids {
+		labels[uid] = g.uids[find(i)]
+	}
+	return labels, nil
+}
@@ -0,0 +1,211 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// WithAPIKey authenticates remote (dgraph://) connections with a Dgraph
+// Cloud API key, sent with every request. It is the option form of the
+// apikey URI parameter. Ignored for embedded (file://) URIs.
+func WithAPIKey(key string) ClientOpt {
+	return func(o *clientOptions) {
+		o.apiKey = key
+	}
+}
+
+// WithBearerToken authenticates remote (dgraph://) connections with an
+// access token, sent with every request as a Bearer Authorization header.
+// It is the option form of the bearertoken URI parameter and cannot be
+// combined with WithAPIKey. Ignored for embedded (file://) URIs.
+func WithBearerToken(token string) ClientOpt {
+	return func(o *clientOptions) {
+		o.bearerToken = token
+	}
+}
+
+// WithLogin logs remote (dgraph://) connections in to an ACL-enabled Dgraph
+// cluster as user, in the given namespace (0 for the default). The first
+// call logs in, and fails if the credentials are rejected. When the access
+// token expires it is renewed with the refresh token, and when that has
+// expired too the client logs in again with the password, so a long-lived
+// client stays signed in. Ignored for embedded (file://) URIs.
+func WithLogin(user, password string, namespace uint64) ClientOpt {
+	return func(o *clientOptions) {
+		o.login = &aclLogin{user: user, password: password, namespace: namespace}
+	}
+}
+
+// aclLogin holds the credentials given to WithLogin.
+type aclLogin struct {
+	user      string
+	password  string
+	namespace uint64
+}
+
+// authDialOptions returns the dial options applying the client's API key,
+// bearer token or ACL login.
+func (o clientOptions) authDialOptions() ([]grpc.DialOption, error) {
+	if o.apiKey != "" && o.bearerToken != "" {
+		return nil, errors.New("WithAPIKey and WithBearerToken cannot be combined")
+	}
+	if o.login != nil && (o.login.user == "" || o.login.password == "") {
+		return nil, errors.New("WithLogin requires both a user and a password")
+	}
+	return credentialDialOptions(o.apiKey, o.bearerToken, o.login), nil
+}
+
+// credentialDialOptions returns the dial options sending apiKey or
+// bearerToken with every call, as dgo does, and logging in as login.
+func credentialDialOptions(apiKey, bearerToken string, login *aclLogin) []grpc.DialOption {
+	var opts []grpc.DialOption
+	if apiKey != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials{"Authorization": apiKey}))
+	}
+	if bearerToken != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials{"Authorization": "Bearer " + bearerToken}))
+	}
+	if login != nil {
+		session := &aclSession{login: *login}
+		opts = append(opts, grpc.WithChainUnaryInterceptor(session.unaryInterceptor()))
+	}
+	return opts
+}
+
+// tokenCredentials sends fixed request metadata with every call, over TLS
+// only.
+type tokenCredentials map[string]string
+
+func (c tokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return c, nil
+}
+
+func (c tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+// authKey identifies the client's credentials for the client dedup cache
+// without keeping them in the clear.
+func (o clientOptions) authKey() string {
+	if o.apiKey == "" && o.bearerToken == "" && o.login == nil {
+		return "none"
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s", o.apiKey, o.bearerToken)
+	if o.login != nil {
+		fmt.Fprintf(h, "\x00%s\x00%s\x00%d", o.login.user, o.login.password, o.login.namespace)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)[:8])
+}
+
+// aclSession keeps the JWTs of a WithLogin client, shared by every
+// connection in its pool.
+type aclSession struct {
+	login aclLogin
+
+	mu      sync.Mutex
+	access  string
+	refresh string
+}
+
+// unaryInterceptor attaches the session's access token to each call,
+// logging in first if needed, and renews the token and retries the call
+// once when the server reports it expired.
+func (s *aclSession) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any,
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if method == api.Dgraph_Login_FullMethodName {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		token, err := s.token(ctx, cc, "")
+		if err != nil {
+			return err
+		}
+		err = invoker(withAccessJwt(ctx, token), method, req, reply, cc, opts...)
+		if !isTokenExpired(err) {
+			return err
+		}
+		if token, err = s.token(ctx, cc, token); err != nil {
+			return err
+		}
+		return invoker(withAccessJwt(ctx, token), method, req, reply, cc, opts...)
+	}
+}
+
+// token returns the session's access token, logging in when there is none.
+// Passed the token a call was rejected with, it renews it unless another
+// call already has: with the refresh token if that is still accepted, else
+// with the password.
+func (s *aclSession) token(ctx context.Context, cc *grpc.ClientConn, expired string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.access != "" && s.access != expired {
+		return s.access, nil
+	}
+	dc := api.NewDgraphClient(cc)
+	if s.refresh != "" {
+		if err := s.do(ctx, dc, &api.LoginRequest{RefreshToken: s.refresh}); err == nil {
+			return s.access, nil
+		}
+	}
+	err := s.do(ctx, dc, &api.LoginRequest{
+		Userid:    s.login.user,
+		Password:  s.login.password,
+		Namespace: s.login.namespace,
+	})
+	if err != nil {
+		return "", fmt.Errorf("logging in as %s: %w", s.login.user, err)
+	}
+	return s.access, nil
+}
+
+// do sends a login request and stores the JWTs it returns.
+func (s *aclSession) do(ctx context.Context, dc api.DgraphClient, req *api.LoginRequest) error {
+	resp, err := dc.Login(ctx, req)
+	if err != nil {
+		return err
+	}
+	var jwt api.Jwt
+	if err := proto.Unmarshal(resp.Json, &jwt); err != nil {
+		return err
+	}
+	s.access, s.refresh = jwt.AccessJwt, jwt.RefreshJwt
+	return nil
+}
+
+// withAccessJwt adds token to ctx's outgoing metadata, where Dgraph looks
+// for it.
+func withAccessJwt(ctx context.Context, token string) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.New(nil)
+	}
+	md.Set("accessJwt", token)
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// isTokenExpired reports whether err is Dgraph rejecting an expired JWT.
+func isTokenExpired(err error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := status.FromError(err)
+	return ok && strings.Contains(err.Error(), "Token is expired")
+}
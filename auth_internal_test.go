@@ -0,0 +1,166 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// aclServer is a Dgraph stand-in that requires an ACL login and issues
+// numbered tokens that tests can expire.
+type aclServer struct {
+	api.UnimplementedDgraphServer
+
+	mu        sync.Mutex
+	gen       int
+	access    string
+	refresh   string
+	logins    int
+	refreshes int
+}
+
+func (s *aclServer) issue() (*api.Response, error) {
+	s.gen++
+	s.access = fmt.Sprintf("access-%d", s.gen)
+	s.refresh = fmt.Sprintf("refresh-%d", s.gen)
+	jwt, err := proto.Marshal(&api.Jwt{AccessJwt: s.access, RefreshJwt: s.refresh})
+	return &api.Response{Json: jwt}, err
+}
+
+func (s *aclServer) Login(_ context.Context, req *api.LoginRequest) (*api.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if req.RefreshToken != "" {
+		if req.RefreshToken != s.refresh {
+			return nil, status.Error(codes.Unauthenticated, "unable to authenticate the refresh token: Token is expired")
+		}
+		s.refreshes++
+		return s.issue()
+	}
+	if req.Userid != "groot" || req.Password != "password" || req.Namespace != 2 {
+		return nil, status.Error(codes.Unauthenticated, "invalid username or password")
+	}
+	s.logins++
+	return s.issue()
+}
+
+func (s *aclServer) authorized(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	md, _ := metadata.FromIncomingContext(ctx)
+	if got := md.Get("accessJwt"); len(got) != 1 || got[0] != s.access {
+		return status.Error(codes.Unauthenticated, "unable to parse jwt token: Token is expired")
+	}
+	return nil
+}
+
+func (s *aclServer) CheckVersion(ctx context.Context, _ *api.Check) (*api.Version, error) {
+	if err := s.authorized(ctx); err != nil {
+		return nil, err
+	}
+	return &api.Version{Tag: "test"}, nil
+}
+
+func (s *aclServer) Query(ctx context.Context, _ *api.Request) (*api.Response, error) {
+	if err := s.authorized(ctx); err != nil {
+		return nil, err
+	}
+	return &api.Response{Json: []byte(`{"q":[]}`), Txn: &api.TxnContext{StartTs: 1}}, nil
+}
+
+// expire invalidates the current access token and, with both, the refresh
+// token too.
+func (s *aclServer) expire(both bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.access = "expired"
+	if both {
+		s.refresh = "expired"
+	}
+}
+
+func startACLServer(t *testing.T) (*aclServer, string) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	fake := &aclServer{}
+	api.RegisterDgraphServer(srv, fake)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return fake, "dgraph://" + lis.Addr().String()
+}
+
+func TestWithLoginRefreshesTokens(t *testing.T) {
+	fake, uri := startACLServer(t)
+	c, err := NewClient(uri, WithLogin("groot", "password", 2))
+	require.NoError(t, err)
+	defer c.Close()
+
+	ctx := context.Background()
+	query := func() {
+		t.Helper()
+		_, err := c.QueryRaw(ctx, `{ q(func: uid(0x1)) { uid } }`, nil)
+		require.NoError(t, err)
+	}
+	query()
+	require.Equal(t, 1, fake.logins)
+
+	// An expired access token is renewed with the refresh token.
+	fake.expire(false)
+	query()
+	require.Equal(t, 1, fake.logins)
+	require.Equal(t, 1, fake.refreshes)
+
+	// When the refresh token has expired too, the client logs in again.
+	fake.expire(true)
+	query()
+	require.Equal(t, 2, fake.logins)
+	require.Equal(t, 1, fake.refreshes)
+}
+
+func TestWithLoginRejectsBadCredentials(t *testing.T) {
+	_, uri := startACLServer(t)
+	c, err := NewClient(uri, WithLogin("groot", "wrong", 2))
+	require.NoError(t, err)
+	defer c.Close()
+	_, err = c.QueryRaw(context.Background(), `{ q(func: uid(0x1)) { uid } }`, nil)
+	require.ErrorContains(t, err, "logging in as groot")
+
+	_, err = NewClient(uri, WithLogin("groot", "", 2))
+	require.ErrorContains(t, err, "requires both a user and a password")
+}
+
+func TestAuthOptions(t *testing.T) {
+	_, err := NewClient("dgraph://127.0.0.1:1", WithAPIKey("k"), WithBearerToken("t"))
+	require.ErrorContains(t, err, "cannot be combined")
+
+	// Credentials are part of the dedup key for remote clients only.
+	key := func(uri string, opts ...ClientOpt) string {
+		c := client{uri: uri}
+		for _, opt := range opts {
+			opt(&c.options)
+		}
+		return c.key()
+	}
+	remote := "dgraph://localhost:9080"
+	require.NotEqual(t, key(remote), key(remote, WithAPIKey("k")))
+	require.NotEqual(t, key(remote, WithAPIKey("a")), key(remote, WithAPIKey("b")))
+	require.NotEqual(t, key(remote, WithLogin("u", "a", 0)), key(remote, WithLogin("u", "b", 0)))
+	require.NotContains(t, key(remote, WithBearerToken("secret-token")), "secret-token")
+	require.Equal(t, key("file:///tmp/db"), key("file:///tmp/db", WithAPIKey("k")))
+}
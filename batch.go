@@ -0,0 +1,135 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"reflect"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// defaultBatchWorkers is how many batches InsertBatch writes at once by
+// default.
+const defaultBatchWorkers = 4
+
+// BatchOptions configures InsertBatch.
+type BatchOptions struct {
+	// Size is how many objects are written per batch. Defaults to 1000.
+	Size int
+	// Workers is how many batches are written at once. Defaults to 4.
+	Workers int
+	// Progress, if set, is called after each batch is written. Calls are
+	// not concurrent, though they come from the workers' goroutines.
+	Progress func(BatchProgress)
+}
+
+// BatchProgress reports on an InsertBatch. Batches finish in any order, so
+// the counts cover the batches written so far rather than a prefix of the
+// input.
+type BatchProgress struct {
+	// Batches is the number of batches written.
+	Batches int
+	// Inserted is the number of objects written.
+	Inserted int
+	Elapsed  time.Duration
+}
+
+// InsertBatch implements writing a stream of objects in batches, several at
+// a time.
+func (c client) InsertBatch(ctx context.Context, objs iter.Seq[any], opts BatchOptions) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	size := opts.Size
+	if size <= 0 {
+		size = batchSize
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = defaultBatchWorkers
+	}
+
+	start := time.Now()
+	var mu sync.Mutex
+	var progress BatchProgress
+	written := func(n int) {
+		mu.Lock()
+		defer mu.Unlock()
+		progress.Batches++
+		progress.Inserted += n
+		progress.Elapsed = time.Since(start)
+		if opts.Progress != nil {
+			opts.Progress(progress)
+		}
+	}
+
+	// Go blocks while all workers are busy, so the input is read no faster
+	// than it is written.
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(workers)
+	batches := 0
+	batch := make([]any, 0, size)
+	flush := func() {
+		b, n := batch, batches
+		batch = make([]any, 0, size)
+		batches++
+		g.Go(func() error {
+			if err := c.insertGroups(gctx, b); err != nil {
+				return fmt.Errorf("modusgraph: insert batch %d: %w", n, err)
+			}
+			written(len(b))
+			return nil
+		})
+	}
+	for obj := range objs {
+		if gctx.Err() != nil {
+			break
+		}
+		batch = append(batch, obj)
+		if len(batch) == size {
+			flush()
+		}
+	}
+	if len(batch) > 0 && gctx.Err() == nil {
+		flush()
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	c.log(ctx).V(1).Info("InsertBatch successful", "batches", progress.Batches, "inserted", progress.Inserted)
+	return ctx.Err()
+}
+
+// insertGroups inserts objs, which may be of several types, with one Insert
+// per type.
+func (c client) insertGroups(ctx context.Context, objs []any) error {
+	var order []reflect.Type
+	groups := make(map[reflect.Type]reflect.Value)
+	for _, obj := range objs {
+		obj = UnwrapSchema(obj)
+		v := reflect.ValueOf(obj)
+		if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+			return fmt.Errorf("InsertBatch takes pointers to structs, not %T", obj)
+		}
+		group, ok := groups[v.Type()]
+		if !ok {
+			order = append(order, v.Type())
+			group = reflect.MakeSlice(reflect.SliceOf(v.Type()), 0, len(objs))
+		}
+		groups[v.Type()] = reflect.Append(group, v)
+	}
+	for _, t := range order {
+		if err := c.Insert(ctx, groups[t].Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"os"
+	"slices"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type BatchSensor struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"batch_sensor_name,omitempty" dgraph:"index=exact"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type BatchReading struct {
+	UID    string       `json:"uid,omitempty"`
+	Value  int          `json:"batch_reading_value,omitempty"`
+	Sensor *BatchSensor `json:"batch_reading_sensor,omitempty"`
+	DType  []string     `json:"dgraph.type,omitempty"`
+}
+
+func TestInsertBatch(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "InsertBatchWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "InsertBatchWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+
+			count := func(typ string) int {
+				resp, err := client.QueryRaw(ctx, fmt.Sprintf(`{ q(func: type(%s)) { n: count(uid) } }`, typ), nil)
+				require.NoError(t, err)
+				var result struct {
+					Q []struct {
+						N int `json:"n"`
+					} `json:"q"`
+				}
+				require.NoError(t, json.Unmarshal(resp, &result))
+				return result.Q[0].N
+			}
+
+			// A channel of one type, in several batches written in parallel.
+			ch := make(chan *BatchSensor)
+			go func() {
+				defer close(ch)
+				for i := range 250 {
+					ch <- &BatchSensor{Name: fmt.Sprintf("sensor-%d", i)}
+				}
+			}()
+			var last mg.BatchProgress
+			calls := 0
+			err := mg.InsertBatch(ctx, client, mg.FromChan(ch), mg.BatchOptions{
+				Size:    100,
+				Workers: 2,
+				Progress: func(p mg.BatchProgress) {
+					calls++
+					last = p
+				},
+			})
+			require.NoError(t, err)
+			require.Equal(t, 3, calls)
+			require.Equal(t, 3, last.Batches)
+			require.Equal(t, 250, last.Inserted)
+			require.Equal(t, 250, count("BatchSensor"))
+
+			// Mixed types in one batch, with edges between them.
+			sensor := &BatchSensor{Name: "mixed"}
+			objs := []any{sensor}
+			for i := range 5 {
+				objs = append(objs, &BatchReading{Value: i + 1, Sensor: sensor})
+			}
+			require.NoError(t, client.InsertBatch(ctx, slices.Values(objs), mg.BatchOptions{}))
+			require.NotEmpty(t, sensor.UID)
+			require.Equal(t, 251, count("BatchSensor"))
+			require.Equal(t, 5, count("BatchReading"))
+
+			var reading BatchReading
+			require.NoError(t, client.Get(ctx, &reading, objs[1].(*BatchReading).UID))
+			require.NotNil(t, reading.Sensor)
+			require.Equal(t, sensor.UID, reading.Sensor.UID)
+		})
+	}
+}
+
+func TestInsertBatchErrors(t *testing.T) {
+	client, cleanup := CreateTestClient(t, "file://"+GetTempDir(t))
+	defer cleanup()
+	ctx := context.Background()
+
+	err := client.InsertBatch(ctx, slices.Values([]any{BatchSensor{Name: "by value"}}), mg.BatchOptions{})
+	require.ErrorContains(t, err, "InsertBatch takes pointers to structs, not modusgraph_test.BatchSensor")
+
+	// A failed batch stops the stream from being read further.
+	read := 0
+	var objs iter.Seq[any] = func(yield func(any) bool) {
+		for i := range 100 {
+			read++
+			var obj any = &BatchSensor{Name: fmt.Sprintf("s%d", i)}
+			if i == 5 {
+				obj = "not a struct"
+			}
+			if !yield(obj) {
+				return
+			}
+		}
+	}
+	err = client.InsertBatch(ctx, objs, mg.BatchOptions{Size: 5, Workers: 1})
+	require.ErrorContains(t, err, "modusgraph: insert batch 1:")
+	require.Less(t, read, 100)
+
+	readOnly, err := client.With(mg.WithReadOnly())
+	require.NoError(t, err)
+	err = readOnly.InsertBatch(ctx, slices.Values([]any{&BatchSensor{}}), mg.BatchOptions{})
+	require.ErrorIs(t, err, mg.ErrReadOnly)
+}
@@ -0,0 +1,249 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+var (
+	// ErrNoBlobStore is returned by PutBlob and GetBlob on a client without
+	// WithBlobStore.
+	ErrNoBlobStore = errors.New("modusgraph: no blob store configured")
+	// ErrBlobNotFound is returned when a blob store holds no content under a
+	// key.
+	ErrBlobNotFound = errors.New("modusgraph: blob not found")
+	// ErrBlobCorrupt is returned by the reader of GetBlob, in place of
+	// io.EOF, when the content read does not match the blob's hash or size.
+	ErrBlobCorrupt = errors.New("modusgraph: blob content does not match its hash")
+)
+
+// Blob refers to binary content kept outside the graph in a BlobStore, such
+// as an uploaded file. Only its key, SHA-256 hash and metadata are stored,
+// as a node of type Blob, so a multi-megabyte attachment costs a few
+// predicates rather than a base64 value. Use it as an edge:
+//
+//	type Document struct {
+//	    UID   string   `json:"uid,omitempty"`
+//	    Title string   `json:"title,omitempty"`
+//	    File  *mg.Blob `json:"file,omitempty"`
+//	    DType []string `json:"dgraph.type,omitempty"`
+//	}
+//
+//	blob, err := client.PutBlob(ctx, f, "application/pdf")
+//	err = client.Insert(ctx, &Document{Title: "Q3 report", File: blob})
+//
+// PutBlob creates a Blob and GetBlob reads its content back.
+type Blob struct {
+	UID         string   `json:"uid,omitempty"`
+	Key         string   `json:"blob_key,omitempty" dgraph:"index=exact"`
+	SHA256      string   `json:"blob_sha256,omitempty" dgraph:"index=exact"`
+	Size        int64    `json:"blob_size,omitempty"`
+	ContentType string   `json:"blob_content_type,omitempty"`
+	DType       []string `json:"dgraph.type,omitempty"`
+}
+
+// BlobStore holds blob content under keys chosen by the client. Implement it
+// to keep blobs in S3 or another object store; FileBlobStore keeps them in a
+// directory.
+type BlobStore interface {
+	// Put stores the content read from r under key. It must not leave
+	// partial content under key when r or the store fails.
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Get opens the content stored under key, returning ErrBlobNotFound
+	// when there is none.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the content stored under key, if any.
+	Delete(ctx context.Context, key string) error
+}
+
+// WithBlobStore sets the store PutBlob writes blob content to and GetBlob
+// reads it from.
+func WithBlobStore(store BlobStore) ClientOpt {
+	return func(o *clientOptions) {
+		o.blobStore = store
+	}
+}
+
+// PutBlob implements streaming r into the blob store under a new key.
+func (c client) PutBlob(ctx context.Context, r io.Reader, contentType string) (*Blob, error) {
+	if err := c.writable(); err != nil {
+		return nil, err
+	}
+	if c.options.blobStore == nil {
+		return nil, ErrNoBlobStore
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+	key := hex.EncodeToString(id[:])
+	h := sha256.New()
+	counter := &countingReader{r: io.TeeReader(r, h)}
+	if err := c.options.blobStore.Put(ctx, key, counter); err != nil {
+		return nil, fmt.Errorf("modusgraph: storing blob: %w", err)
+	}
+	return &Blob{
+		Key:         key,
+		SHA256:      hex.EncodeToString(h.Sum(nil)),
+		Size:        counter.n,
+		ContentType: contentType,
+		DType:       []string{"Blob"},
+	}, nil
+}
+
+// GetBlob implements opening the content of blob, checked as it is read.
+func (c client) GetBlob(ctx context.Context, blob *Blob) (io.ReadCloser, error) {
+	if c.options.blobStore == nil {
+		return nil, ErrNoBlobStore
+	}
+	if blob == nil || blob.Key == "" {
+		return nil, fmt.Errorf("%w: blob has no key", ErrBlobNotFound)
+	}
+	rc, err := c.options.blobStore.Get(ctx, blob.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &verifyingReader{rc: rc, h: sha256.New(), blob: blob}, nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+// verifyingReader reads a blob's content, replacing io.EOF with
+// ErrBlobCorrupt when the content does not match the blob.
+type verifyingReader struct {
+	rc   io.ReadCloser
+	h    hash.Hash
+	n    int64
+	blob *Blob
+}
+
+func (vr *verifyingReader) Read(p []byte) (int, error) {
+	n, err := vr.rc.Read(p)
+	vr.h.Write(p[:n])
+	vr.n += int64(n)
+	if err == io.EOF && (vr.n != vr.blob.Size || hex.EncodeToString(vr.h.Sum(nil)) != vr.blob.SHA256) {
+		return n, fmt.Errorf("%w: %s", ErrBlobCorrupt, vr.blob.Key)
+	}
+	return n, err
+}
+
+func (vr *verifyingReader) Close() error {
+	return vr.rc.Close()
+}
+
+// blobKey matches the keys FileBlobStore accepts, which keeps them inside
+// its directory.
+var blobKey = regexp.MustCompile(`^[0-9A-Za-z_-]+$`)
+
+// FileBlobStore is a BlobStore keeping each blob in a file of a directory.
+type FileBlobStore struct {
+	dir string
+}
+
+// NewFileBlobStore returns a store keeping blobs in dir, creating it if
+// needed.
+func NewFileBlobStore(dir string) (*FileBlobStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileBlobStore{dir: dir}, nil
+}
+
+// path returns the file holding the blob under key, in a subdirectory named
+// for the key's first two characters so that no directory grows too large.
+func (s *FileBlobStore) path(key string) (string, error) {
+	if !blobKey.MatchString(key) || len(key) < 3 {
+		return "", fmt.Errorf("modusgraph: invalid blob key %q", key)
+	}
+	return filepath.Join(s.dir, key[:2], key), nil
+}
+
+// Put writes r to a temporary file and renames it into place once complete.
+func (s *FileBlobStore) Put(ctx context.Context, key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".put-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := io.Copy(f, contextReader{ctx, r}); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Get opens the file holding the blob under key.
+func (s *FileBlobStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrBlobNotFound, key)
+	}
+	return f, err
+}
+
+// Delete removes the file holding the blob under key.
+func (s *FileBlobStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// contextReader stops reading once its context is done, so a long copy can
+// be cancelled.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
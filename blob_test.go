@@ -0,0 +1,106 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type BlobDocument struct {
+	UID   string   `json:"uid,omitempty"`
+	Title string   `json:"bd_title,omitempty"`
+	File  *mg.Blob `json:"bd_file,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+func TestBlob(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "BlobWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "BlobWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+
+			_, err := client.PutBlob(ctx, bytes.NewReader(nil), "text/plain")
+			require.ErrorIs(t, err, mg.ErrNoBlobStore)
+
+			dir := t.TempDir()
+			store, err := mg.NewFileBlobStore(dir)
+			require.NoError(t, err)
+			client, err = client.With(mg.WithBlobStore(store))
+			require.NoError(t, err)
+
+			content := bytes.Repeat([]byte("modusGraph "), 200_000) // 2.2 MB
+			blob, err := client.PutBlob(ctx, bytes.NewReader(content), "text/plain")
+			require.NoError(t, err)
+			sum := sha256.Sum256(content)
+			require.Equal(t, hex.EncodeToString(sum[:]), blob.SHA256)
+			require.EqualValues(t, len(content), blob.Size)
+
+			doc := &BlobDocument{Title: "report", File: blob}
+			require.NoError(t, client.Insert(ctx, doc))
+
+			// Only the metadata is in the graph; the content streams back
+			// from the store.
+			var got BlobDocument
+			require.NoError(t, client.Get(ctx, &got, doc.UID))
+			require.NotNil(t, got.File)
+			require.Equal(t, blob.Key, got.File.Key)
+			require.Equal(t, "text/plain", got.File.ContentType)
+			rc, err := client.GetBlob(ctx, got.File)
+			require.NoError(t, err)
+			read, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			require.NoError(t, rc.Close())
+			require.Equal(t, content, read)
+
+			// Tampered content fails at the end of the stream.
+			path := filepath.Join(dir, blob.Key[:2], blob.Key)
+			require.NoError(t, os.WriteFile(path, []byte("tampered"), 0o644))
+			rc, err = client.GetBlob(ctx, got.File)
+			require.NoError(t, err)
+			_, err = io.ReadAll(rc)
+			require.ErrorIs(t, err, mg.ErrBlobCorrupt)
+			require.NoError(t, rc.Close())
+
+			require.NoError(t, store.Delete(ctx, blob.Key))
+			_, err = client.GetBlob(ctx, got.File)
+			require.ErrorIs(t, err, mg.ErrBlobNotFound)
+			_, err = store.Get(ctx, "../escape")
+			require.ErrorContains(t, err, "invalid blob key")
+		})
+	}
+}
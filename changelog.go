@@ -0,0 +1,675 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+)
+
+// ErrChangelogDisabled is returned by Changelog when the client was created
+// without WithChangelog.
+var ErrChangelogDisabled = errors.New("modusgraph: changelog not enabled; create the client WithChangelog")
+
+// ErrChangelogAppend is returned, wrapped, by a write whose mutation
+// committed but whose changelog entry could not be written. The data is
+// stored, so retrying the write would apply it twice.
+var ErrChangelogAppend = errors.New("modusgraph: mutation committed but not written to the changelog")
+
+// ChangeOp classifies a ChangeEvent.
+type ChangeOp string
+
+const (
+	// ChangeInsert is a node created by the mutation (a blank-node UID).
+	ChangeInsert ChangeOp = "insert"
+	// ChangeUpdate is a set on an existing node.
+	ChangeUpdate ChangeOp = "update"
+	// ChangeDelete removes the listed predicates from a node, or the whole
+	// node when Predicates is empty.
+	ChangeDelete ChangeOp = "delete"
+)
+
+// ChangeEvent describes one node touched by a committed mutation. Seq numbers
+// are assigned by the changelog, start at 1, and have no gaps, so a consumer
+// can checkpoint the last Seq it processed and resume after it.
+type ChangeEvent struct {
+	Seq         uint64    `json:"seq"`
+	Op          ChangeOp  `json:"op"`
+	Type        string    `json:"type,omitempty"`
+	UID         string    `json:"uid,omitempty"`
+	Predicates  []string  `json:"predicates,omitempty"`
+	CommittedAt time.Time `json:"committedAt"`
+	// Actor is the WithActor identity of the call that made the change.
+	Actor string `json:"actor,omitempty"`
+}
+
+// changelogFile is the log's file name within the WithChangelog directory.
+const changelogFile = "changes.log"
+
+// changelog is an append-only, file-backed log of ChangeEvents. Each event is
+// one JSON line; the byte offset of every line is kept in memory so readers
+// can seek straight to any Seq.
+type changelog struct {
+	path string
+
+	mu      sync.Mutex
+	f       *os.File
+	offsets []int64 // offsets[i] is where Seq i+1 starts
+	size    int64
+	notify  chan struct{} // closed and replaced on every append
+	refs    int
+
+	// pending buffers events of remote transactions that have mutated but not
+	// yet committed, keyed by start timestamp.
+	pending map[uint64][]ChangeEvent
+}
+
+var (
+	changelogs   = map[string]*changelog{}
+	changelogsMu sync.Mutex
+)
+
+// openChangelog opens (creating if needed) the changelog in dir. Clients
+// configured with the same directory share one log.
+func openChangelog(dir string) (*changelog, error) {
+	path, err := filepath.Abs(filepath.Join(dir, changelogFile))
+	if err != nil {
+		return nil, err
+	}
+	changelogsMu.Lock()
+	defer changelogsMu.Unlock()
+	if l, ok := changelogs[path]; ok {
+		l.refs++
+		return l, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	l := &changelog{
+		path:    path,
+		f:       f,
+		notify:  make(chan struct{}),
+		refs:    1,
+		pending: map[uint64][]ChangeEvent{},
+	}
+	if err := l.recover(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	changelogs[path] = l
+	return l, nil
+}
+
+// recover indexes the existing log and truncates a torn final line left by a
+// crash mid-append.
+func (l *changelog) recover() error {
+	r := bufio.NewReader(l.f)
+	var off int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				if err := l.f.Truncate(off); err != nil {
+					return err
+				}
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+		l.offsets = append(l.offsets, off)
+		off += int64(len(line))
+	}
+	l.size = off
+	_, err := l.f.Seek(off, io.SeekStart)
+	return err
+}
+
+// release drops one client's reference, closing the file with the last.
+func (l *changelog) release() {
+	if l == nil {
+		return
+	}
+	changelogsMu.Lock()
+	defer changelogsMu.Unlock()
+	if l.refs--; l.refs > 0 {
+		return
+	}
+	delete(changelogs, l.path)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.f.Close()
+}
+
+// append assigns sequence numbers to events, stamps them as committed at
+// now and durably writes them. It runs after the mutation has committed, so
+// its write errors wrap ErrChangelogAppend.
+func (l *changelog) append(events []ChangeEvent, now time.Time) error {
+	if len(events) == 0 {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var buf bytes.Buffer
+	offsets := make([]int64, 0, len(events))
+	now = now.UTC()
+	for i := range events {
+		events[i].Seq = uint64(len(l.offsets) + i + 1)
+		events[i].CommittedAt = now
+		offsets = append(offsets, l.size+int64(buf.Len()))
+		line, err := json.Marshal(events[i])
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	if _, err := l.f.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("%w: %w", ErrChangelogAppend, err)
+	}
+	if err := l.f.Sync(); err != nil {
+		return fmt.Errorf("%w: %w", ErrChangelogAppend, err)
+	}
+	l.size += int64(buf.Len())
+	l.offsets = append(l.offsets, offsets...)
+	close(l.notify)
+	l.notify = make(chan struct{})
+	return nil
+}
+
+// head returns the Seq of the last appended event and a channel closed by the
+// next append.
+func (l *changelog) head() (uint64, <-chan struct{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return uint64(len(l.offsets)), l.notify
+}
+
+// readAfter returns up to max events with Seq > after that are already in
+// the log, without waiting.
+func (l *changelog) readAfter(after uint64, max int) ([]ChangeEvent, error) {
+	l.mu.Lock()
+	n := uint64(len(l.offsets))
+	if after >= n {
+		l.mu.Unlock()
+		return nil, nil
+	}
+	end := n
+	if max > 0 && end-after > uint64(max) {
+		end = after + uint64(max)
+	}
+	start := l.offsets[after]
+	stop := l.size
+	if end < n {
+		stop = l.offsets[end]
+	}
+	l.mu.Unlock()
+
+	data := make([]byte, stop-start)
+	if _, err := l.f.ReadAt(data, start); err != nil {
+		return nil, err
+	}
+	events := make([]ChangeEvent, 0, end-after)
+	for line := range bytes.Lines(data) {
+		var ev ChangeEvent
+		if err := json.Unmarshal(line, &ev); err != nil {
+			return nil, fmt.Errorf("modusgraph: corrupt changelog entry: %w", err)
+		}
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+// follow yields every event with Seq > after, then waits for and yields new
+// events as they are committed, until ctx is done.
+func (l *changelog) follow(ctx context.Context, after uint64) iter.Seq2[ChangeEvent, error] {
+	return func(yield func(ChangeEvent, error) bool) {
+		for {
+			_, wake := l.head()
+			events, err := l.readAfter(after, 1024)
+			if err != nil {
+				yield(ChangeEvent{}, err)
+				return
+			}
+			for _, ev := range events {
+				if !yield(ev, nil) {
+					return
+				}
+				after = ev.Seq
+			}
+			if len(events) > 0 {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				yield(ChangeEvent{}, ctx.Err())
+				return
+			case <-wake:
+			}
+		}
+	}
+}
+
+// Changelog returns the committed changes with Seq greater than after, in
+// order. Once it has caught up it waits for new commits, so the iteration
+// runs until ctx is done (yielding ctx's error last) or the consumer stops.
+// Pass 0 to read the log from the beginning.
+func (c client) Changelog(ctx context.Context, after uint64) iter.Seq2[ChangeEvent, error] {
+	if c.changes == nil {
+		return func(yield func(ChangeEvent, error) bool) {
+			yield(ChangeEvent{}, ErrChangelogDisabled)
+		}
+	}
+	return c.changes.follow(ctx, after)
+}
+
+// ReadChangelog returns up to limit committed changes with Seq greater than
+// after, in order, without waiting for new commits; a limit of 0 or less
+// returns all of them. It returns no events once the reader has caught up.
+func (c client) ReadChangelog(ctx context.Context, after uint64, limit int) ([]ChangeEvent, error) {
+	if c.changes == nil {
+		return nil, ErrChangelogDisabled
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.changes.readAfter(after, limit)
+}
+
+// defaultCursorBatchSize is how many events a changeCursor delivers at once
+// unless configured otherwise.
+const defaultCursorBatchSize = 100
+
+// deliveryRetryMax caps the backoff between failed delivery attempts.
+const deliveryRetryMax = 5 * time.Second
+
+// changeCursor feeds changelog batches to a consumer, persisting the Seq of
+// the last delivered event in a checkpoint file. The checkpoint advances only
+// after a batch is delivered, which makes delivery at-least-once.
+type changeCursor struct {
+	changes    *changelog
+	checkpoint string
+	batch      int
+}
+
+// newChangeCursor defaults checkpoint to name in the changelog's directory
+// and batch to defaultCursorBatchSize.
+func newChangeCursor(changes *changelog, checkpoint, name string, batch int) changeCursor {
+	if checkpoint == "" {
+		checkpoint = filepath.Join(filepath.Dir(changes.path), name)
+	}
+	if batch <= 0 {
+		batch = defaultCursorBatchSize
+	}
+	return changeCursor{changes: changes, checkpoint: checkpoint, batch: batch}
+}
+
+// run delivers events after the checkpoint, waiting for new commits once
+// caught up, until ctx is done or deliver or I/O fails.
+func (cur changeCursor) run(ctx context.Context, deliver func(context.Context, []ChangeEvent) error) error {
+	after, err := cur.read()
+	if err != nil {
+		return err
+	}
+	for {
+		_, wake := cur.changes.head()
+		events, err := cur.changes.readAfter(after, cur.batch)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-wake:
+				continue
+			}
+		}
+		if err := deliver(ctx, events); err != nil {
+			return err
+		}
+		after = events[len(events)-1].Seq
+		if err := cur.write(after); err != nil {
+			return err
+		}
+	}
+}
+
+func (cur changeCursor) read() (uint64, error) {
+	data, err := os.ReadFile(cur.checkpoint)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	seq, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("modusgraph: invalid changelog checkpoint %s: %w", cur.checkpoint, err)
+	}
+	return seq, nil
+}
+
+// write replaces the checkpoint file atomically, so a crash leaves either the
+// old or the new value.
+func (cur changeCursor) write(seq uint64) error {
+	return writeFileAtomic(cur.checkpoint, []byte(strconv.FormatUint(seq, 10)+"\n"))
+}
+
+// writeFileAtomic replaces path with data through a synced temporary file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// retryDelivery calls deliver until it succeeds or ctx is done, backing off
+// between attempts and logging each failure with keysAndValues.
+func retryDelivery(ctx context.Context, logger logr.Logger, deliver func() error, keysAndValues ...any) error {
+	backoff := 100 * time.Millisecond
+	for {
+		err := deliver()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		logger.Error(err, "Failed to deliver change events; retrying",
+			append(keysAndValues, "backoff", backoff)...)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, deliveryRetryMax)
+	}
+}
+
+// record logs the events of a successful mutation request. Committed requests
+// are appended immediately, as committed at now; others wait in pending for
+// CommitOrAbort.
+func (l *changelog) record(ctx context.Context, in *api.Request, resp *api.Response, committed bool,
+	now time.Time) error {
+	if l == nil || len(in.Mutations) == 0 {
+		return nil
+	}
+	events := mutationEvents(in.Mutations, resp.GetUids())
+	if actor, ok := ActorFromContext(ctx); ok {
+		for i := range events {
+			events[i].Actor = actor
+		}
+	}
+	if committed {
+		return l.append(events, now)
+	}
+	startTs := resp.GetTxn().GetStartTs()
+	if startTs == 0 {
+		startTs = in.StartTs
+	}
+	l.mu.Lock()
+	l.pending[startTs] = append(l.pending[startTs], events...)
+	l.mu.Unlock()
+	return nil
+}
+
+// finish resolves a remote transaction's pending events, committed at now.
+func (l *changelog) finish(startTs uint64, committed bool, now time.Time) error {
+	l.mu.Lock()
+	events := l.pending[startTs]
+	delete(l.pending, startTs)
+	l.mu.Unlock()
+	if !committed {
+		return nil
+	}
+	return l.append(events, now)
+}
+
+// unaryInterceptor records remote mutations: CommitNow requests when the
+// server acknowledges them, others when their transaction commits. Events
+// are stamped with the time now returns.
+func (l *changelog) unaryInterceptor(now func() time.Time) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		switch method {
+		case api.Dgraph_Query_FullMethodName:
+			if in, ok := req.(*api.Request); ok {
+				if err != nil {
+					_ = l.finish(in.StartTs, false, now())
+					return err
+				}
+				if rerr := l.record(ctx, in, reply.(*api.Response), in.CommitNow, now()); rerr != nil {
+					return fmt.Errorf("modusgraph: recording changelog: %w", rerr)
+				}
+			}
+		case api.Dgraph_CommitOrAbort_FullMethodName:
+			if tc, ok := req.(*api.TxnContext); ok {
+				committed := err == nil && !tc.Aborted
+				if ferr := l.finish(tc.StartTs, committed, now()); ferr != nil && err == nil {
+					return fmt.Errorf("modusgraph: recording changelog: %w", ferr)
+				}
+			}
+		}
+		return err
+	}
+}
+
+// mutationEvents derives one event per node a set of mutations touches.
+// uids maps blank-node labels (without "_:") to assigned UIDs.
+func mutationEvents(mus []*api.Mutation, uids map[string]string) []ChangeEvent {
+	var b eventBuilder
+	b.uids = uids
+	for _, mu := range mus {
+		if len(mu.SetJson) > 0 {
+			b.json(mu.SetJson, false)
+		}
+		if len(mu.DeleteJson) > 0 {
+			b.json(mu.DeleteJson, true)
+		}
+		if len(mu.SetNquads) > 0 {
+			b.nquads(mu.SetNquads, false)
+		}
+		if len(mu.DelNquads) > 0 {
+			b.nquads(mu.DelNquads, true)
+		}
+	}
+	return b.events
+}
+
+// eventBuilder accumulates events, merging repeated touches of one node.
+type eventBuilder struct {
+	uids   map[string]string
+	events []ChangeEvent
+	index  map[string]int // op + "/" + uid -> events index
+}
+
+func (b *eventBuilder) resolve(ref string) (string, ChangeOp) {
+	if label, ok := strings.CutPrefix(ref, "_:"); ok {
+		return b.uids[label], ChangeInsert
+	}
+	if strings.HasPrefix(ref, "uid(") {
+		return "", ChangeUpdate // an upsert variable the server resolved
+	}
+	return ref, ChangeUpdate
+}
+
+func (b *eventBuilder) add(ref string, del bool, typ string, preds []string) {
+	uid, op := b.resolve(ref)
+	if ref == "" {
+		op = ChangeInsert
+	}
+	if del {
+		op = ChangeDelete
+	}
+	key := string(op) + "/" + ref
+	if b.index == nil {
+		b.index = map[string]int{}
+	}
+	if i, ok := b.index[key]; ok && ref != "" {
+		ev := &b.events[i]
+		if ev.Type == "" {
+			ev.Type = typ
+		}
+		for _, p := range preds {
+			if !slices.Contains(ev.Predicates, p) {
+				ev.Predicates = append(ev.Predicates, p)
+			}
+		}
+		return
+	}
+	b.index[key] = len(b.events)
+	b.events = append(b.events, ChangeEvent{Op: op, Type: typ, UID: uid, Predicates: preds})
+}
+
+func (b *eventBuilder) json(data []byte, del bool) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return
+	}
+	b.jsonValue(v, del)
+}
+
+func (b *eventBuilder) jsonValue(v any, del bool) {
+	switch v := v.(type) {
+	case []any:
+		for _, e := range v {
+			b.jsonValue(e, del)
+		}
+	case map[string]any:
+		b.jsonNode(v, del)
+	}
+}
+
+// jsonNode records one mutation object and any nested node objects that carry
+// predicates of their own. Bare {"uid": ...} references are edges, not nodes.
+func (b *eventBuilder) jsonNode(obj map[string]any, del bool) {
+	ref, _ := obj["uid"].(string)
+	typ := ""
+	var preds []string
+	for _, k := range slices.Sorted(maps.Keys(obj)) {
+		v := obj[k]
+		switch k {
+		case "uid":
+			continue
+		case "dgraph.type":
+			typ = firstString(v)
+			continue
+		}
+		if !strings.Contains(k, "|") { // facet keys ride on their edge
+			preds = append(preds, k)
+		}
+		b.nested(v, del)
+	}
+	b.add(ref, del, typ, preds)
+}
+
+func (b *eventBuilder) nested(v any, del bool) {
+	switch v := v.(type) {
+	case []any:
+		for _, e := range v {
+			b.nested(e, del)
+		}
+	case map[string]any:
+		if len(v) > 1 || v["uid"] == nil {
+			b.jsonNode(v, del)
+		}
+	}
+}
+
+func firstString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []any:
+		if len(v) > 0 {
+			s, _ := v[0].(string)
+			return s
+		}
+	}
+	return ""
+}
+
+// nquads records RDF mutations, one event per subject.
+func (b *eventBuilder) nquads(data []byte, del bool) {
+	type subject struct {
+		typ   string
+		preds []string
+		star  bool
+	}
+	var order []string
+	subjects := map[string]*subject{}
+	for line := range bytes.Lines(data) {
+		fields := strings.Fields(string(line))
+		if len(fields) < 3 {
+			continue
+		}
+		ref := strings.Trim(fields[0], "<>")
+		pred := strings.Trim(fields[1], "<>")
+		s, ok := subjects[ref]
+		if !ok {
+			s = &subject{}
+			subjects[ref] = s
+			order = append(order, ref)
+		}
+		switch pred {
+		case "*":
+			s.star = true
+		case "dgraph.type":
+			s.typ = strings.Trim(fields[2], `"`)
+		default:
+			if !slices.Contains(s.preds, pred) {
+				s.preds = append(s.preds, pred)
+			}
+		}
+	}
+	for _, ref := range order {
+		s := subjects[ref]
+		preds := s.preds
+		if del && s.star {
+			preds = nil
+		}
+		slices.Sort(preds)
+		b.add(ref, del, s.typ, preds)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutationEvents(t *testing.T) {
+	tests := []struct {
+		name string
+		mus  []*api.Mutation
+		uids map[string]string
+		want []ChangeEvent
+	}{
+		{
+			"JSON insert with nested node",
+			[]*api.Mutation{{SetJson: []byte(`{"uid": "_:f", "dgraph.type": "Film", "title": "x",
+				"director": {"uid": "_:d", "dgraph.type": ["Person"], "name": "y"}}`)}},
+			map[string]string{"f": "0x1", "d": "0x2"},
+			[]ChangeEvent{
+				{Op: ChangeInsert, Type: "Person", UID: "0x2", Predicates: []string{"name"}},
+				{Op: ChangeInsert, Type: "Film", UID: "0x1", Predicates: []string{"director", "title"}},
+			},
+		},
+		{
+			"JSON update ignores edge references",
+			[]*api.Mutation{{SetJson: []byte(`[{"uid": "0x5", "title": "z", "director": {"uid": "0x2"}}]`)}},
+			nil,
+			[]ChangeEvent{{Op: ChangeUpdate, UID: "0x5", Predicates: []string{"director", "title"}}},
+		},
+		{
+			"JSON delete of a whole node",
+			[]*api.Mutation{{DeleteJson: []byte(`{"uid": "0x5"}`)}},
+			nil,
+			[]ChangeEvent{{Op: ChangeDelete, UID: "0x5"}},
+		},
+		{
+			"NQuads merge per subject",
+			[]*api.Mutation{
+				{SetNquads: []byte("<0x7> <name> \"a\" .\n<0x7> <dgraph.type> \"Person\" .\n<0x7> <age> \"3\" .\n")},
+				{DelNquads: []byte("<0x8> * * .\n")},
+			},
+			nil,
+			[]ChangeEvent{
+				{Op: ChangeUpdate, Type: "Person", UID: "0x7", Predicates: []string{"age", "name"}},
+				{Op: ChangeDelete, UID: "0x8"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, mutationEvents(tt.mus, tt.uids))
+		})
+	}
+}
+
+func TestChangelogRecoversTornLine(t *testing.T) {
+	dir := t.TempDir()
+	l, err := openChangelog(dir)
+	require.NoError(t, err)
+	require.NoError(t, l.append([]ChangeEvent{{Op: ChangeInsert, UID: "0x1"}, {Op: ChangeUpdate, UID: "0x1"}}, time.Now()))
+	l.release()
+
+	// Simulate a crash midway through a third append.
+	f, err := os.OpenFile(filepath.Join(dir, changelogFile), os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"seq":3,"op":"del`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	l, err = openChangelog(dir)
+	require.NoError(t, err)
+	defer l.release()
+	seq, _ := l.head()
+	require.Equal(t, uint64(2), seq)
+
+	require.NoError(t, l.append([]ChangeEvent{{Op: ChangeDelete, UID: "0x1"}}, time.Now()))
+	events, err := l.readAfter(1, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, uint64(2), events[0].Seq)
+	require.Equal(t, uint64(3), events[1].Seq)
+	require.Equal(t, ChangeDelete, events[1].Op)
+}
+
+func TestChangelogPendingUntilCommit(t *testing.T) {
+	l, err := openChangelog(t.TempDir())
+	require.NoError(t, err)
+	defer l.release()
+
+	committedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	set := &api.Request{StartTs: 10, Mutations: []*api.Mutation{{SetJson: []byte(`{"uid": "0x1", "name": "a"}`)}}}
+	ctx := WithActor(context.Background(), "alice")
+	require.NoError(t, l.record(ctx, set, &api.Response{Txn: &api.TxnContext{StartTs: 10}}, false, time.Time{}))
+	seq, _ := l.head()
+	require.Zero(t, seq, "uncommitted mutations must not be logged")
+
+	require.NoError(t, l.finish(10, true, committedAt))
+	seq, _ = l.head()
+	require.Equal(t, uint64(1), seq)
+	events, err := l.readAfter(0, 0)
+	require.NoError(t, err)
+	require.Equal(t, "alice", events[0].Actor, "the actor is kept while the event is pending")
+	require.Equal(t, committedAt, events[0].CommittedAt, "events are stamped when they commit")
+
+	set.StartTs = 11
+	resp := &api.Response{Txn: &api.TxnContext{StartTs: 11}}
+	require.NoError(t, l.record(context.Background(), set, resp, false, time.Time{}))
+	require.NoError(t, l.finish(11, false, committedAt))
+	seq, _ = l.head()
+	require.Equal(t, uint64(1), seq, "aborted mutations must not be logged")
+}
+
+func TestChangelogAppendFailureIsMarked(t *testing.T) {
+	ctx := context.Background()
+	c, err := NewClient("file://"+t.TempDir(), WithAutoSchema(true), WithChangelog(t.TempDir()))
+	require.NoError(t, err)
+	defer c.Close()
+	require.NoError(t, c.Insert(ctx, &SyncFilm{Title: "Alien"}))
+
+	// With the log unwritable, the write still commits, and its error says so.
+	require.NoError(t, c.(client).changes.f.Close())
+	err = c.Insert(ctx, &SyncFilm{Title: "Heat"})
+	require.ErrorIs(t, err, ErrChangelogAppend)
+	var films []SyncFilm
+	require.NoError(t, c.Query(ctx, SyncFilm{}).Filter(`eq(title, "Heat")`).Nodes(&films))
+	require.Len(t, films, 1)
+}
@@ -7,21 +7,27 @@ package modusgraph
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"net/url"
 	"os"
 	"reflect"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/dgraph-io/dgo/v250"
 	"github.com/dgraph-io/dgo/v250/protos/api"
 	dg "github.com/dolan-in/dgman/v2"
 	"github.com/go-logr/logr"
 	"github.com/go-playground/validator/v10"
+	"github.com/matthewmcneely/modusgraph/queryopt"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
@@ -44,37 +50,240 @@ type Client interface {
 	// If no predicates are specified, the first predicate with the `upsert` tag will be used.
 	// If none are specified in the predicates argument, the first predicate with the `upsert` tag
 	// will be used.
+	// Transactions aborted by a concurrent conflict are retried per WithUpsertRetry.
 	Upsert(context.Context, any, ...string) error
 
 	// LoadOrStore stores the object only if no node matches the upsert
 	// predicates, returning loaded=true when an existing node already matched
-	// (the object is then populated from it). Insert-if-absent.
+	// (the object is then populated from it). Insert-if-absent. Aborted
+	// transactions are retried like Upsert.
 	LoadOrStore(ctx context.Context, obj any, predicates ...string) (loaded bool, err error)
 
+	// UpsertIf is a compare-and-set Upsert: the node whose key predicate
+	// equals obj's is updated only if it satisfies cond, as in
+	// UpsertIf(ctx, film, "film_id", Lt("film_version", film.Version)), and
+	// inserted if none exists. applied reports whether obj was written; when
+	// the condition fails nothing is written and obj is left as passed. With
+	// an empty key, the first field tagged dgraph:"upsert" is used.
+	UpsertIf(ctx context.Context, obj any, key string, cond Condition) (applied bool, err error)
+
+	// UpsertWhere is an Upsert that finds the stored node with a condition
+	// instead of upsert predicates: the node of obj's type matching match is
+	// updated, and obj inserted if none does. InsertOnly and UpdateOnly limit
+	// it to one of the two; applied reports whether obj was written. The
+	// match is read and the write made in one transaction, so a concurrent
+	// writer cannot slip in between. It fails when match selects several
+	// nodes.
+	UpsertWhere(ctx context.Context, obj any, match Condition, opts ...UpsertOpt) (applied bool, err error)
+
 	// LoadAndDelete atomically reads the node whose key predicate equals key
 	// into obj and deletes it, returning loaded=false when none matched.
 	// Read-and-consume; concurrent callers elect one winner.
 	LoadAndDelete(ctx context.Context, obj any, key any, predicates ...string) (loaded bool, err error)
 
+	// InsertBatch inserts the objects of objs, pointers to structs of one or
+	// more types, in batches of opts.Size, writing opts.Workers batches at
+	// once. Each batch is written as Insert writes a slice, in one
+	// transaction per type. When a batch fails, InsertBatch stops reading
+	// objs and returns the error; batches already written stay written, as
+	// opts.Progress reports. Use the generic InsertBatch and FromChan for
+	// typed sequences and channels.
+	InsertBatch(ctx context.Context, objs iter.Seq[any], opts BatchOptions) error
+
 	// Update modifies an existing object in the database.
 	// The object must be a pointer to a struct and must have a UID field set.
 	Update(context.Context, any) error
 
 	// Get retrieves a single object by its UID and populates the provided object.
-	// The object parameter must be a pointer to a struct.
-	Get(context.Context, any, string) error
+	// The object parameter must be a pointer to a struct. Edges are expanded
+	// within the client's WithMaxDepth and WithMaxEdgeFanout limits, which
+	// GetMaxDepth and GetMaxEdgeFanout override for the call.
+	Get(ctx context.Context, obj any, uid string, opts ...GetOpt) error
+
+	// GetAt is Get reading the graph as it was at timestamp ts, a value
+	// returned by ReadTs; see ReadAt.
+	GetAt(ctx context.Context, obj any, uid string, ts uint64, opts ...GetOpt) error
+
+	// ReadTs returns the timestamp of the latest committed state of the
+	// graph. Record it to read that state again later with ReadAt or GetAt.
+	ReadTs(ctx context.Context) (uint64, error)
+
+	// Snapshot returns a read handle pinned to the latest committed state,
+	// so that several reads see one consistent view of the graph.
+	Snapshot(ctx context.Context) (*Snapshot, error)
+
+	// GetByUniqueGroup populates obj from the node of its type whose values
+	// for the fields tagged dgraph:"unique_group=<group>" equal those already
+	// set in obj. It returns dgman's ErrNodeNotFound when none matches.
+	GetByUniqueGroup(ctx context.Context, obj any, group string) error
+
+	// CheckPassword reports whether candidate matches the value of the
+	// password predicate (a field tagged dgraph:"type=password") of the node
+	// uid. Dgraph stores such values hashed and never returns them, so Get
+	// and Query leave password fields empty. A node without the predicate
+	// does not match.
+	CheckPassword(ctx context.Context, uid, predicate, candidate string) (bool, error)
+
+	// SetPassword stores password as the value of the password predicate of
+	// the node uid, replacing any earlier value, without writing the rest of
+	// the node. It returns ErrNotPassword unless the schema declares
+	// predicate of type password, so the value is always stored hashed.
+	SetPassword(ctx context.Context, uid, predicate, password string) error
 
 	// Query creates a new query builder for retrieving data from the database.
 	// Returns a *dg.Query that can be further refined with filters, pagination, etc.
 	Query(context.Context, any) *dg.Query
 
-	// Delete removes objects with the specified UIDs from the database.
-	Delete(context.Context, []string) error
+	// UIDs returns the UIDs of the nodes of the model's type matching the
+	// @filter expression (empty matches all), without decoding node bodies.
+	UIDs(ctx context.Context, model any, filter string, params ...any) ([]uint64, error)
+
+	// Edges iterates the edges of a uid predicate, streaming them from
+	// storage on embedded clients rather than loading them all.
+	Edges(ctx context.Context, pred string) iter.Seq2[Edge, error]
+
+	// SimilarTo fills obj with the k nodes of its type whose vectors in the
+	// hnsw-indexed predicate field are nearest to vec, nearest first.
+	SimilarTo(ctx context.Context, obj any, field string, vec []float32, k int) error
+
+	// Paths returns up to opts.MaxPaths paths between two nodes, lightest
+	// first, with each hop's predicate, node types and weight.
+	Paths(ctx context.Context, from, to string, opts PathOptions) ([]Path, error)
+
+	// ExportJSONLD writes typed nodes to w as a JSON-LD document whose
+	// @context maps predicates to IRIs per opts.
+	ExportJSONLD(ctx context.Context, w io.Writer, opts JSONLDOptions) error
+
+	// ImportJSONLD loads the nodes of a JSON-LD document, mapping IRIs back to
+	// predicates per opts, and returns the UID assigned to each document @id.
+	ImportJSONLD(ctx context.Context, r io.Reader, opts JSONLDOptions) (map[string]string, error)
+
+	// ExportRDF writes every node to w as RDF N-Quads, with typed literals,
+	// language tags and facets, for Dgraph's live and bulk loaders.
+	ExportRDF(ctx context.Context, w io.Writer, opts RDFOptions) error
+
+	// ImportRDF writes the RDF N-Quads read from r and returns the UID
+	// assigned to each blank node.
+	ImportRDF(ctx context.Context, r io.Reader, opts RDFOptions) (map[string]string, error)
+
+	// ExportParquet writes the nodes of model's type to w as a Parquet file,
+	// one row per node, with scalar predicates as columns and edges as UID
+	// columns.
+	ExportParquet(ctx context.Context, model any, w io.Writer, opts ParquetOptions) error
+
+	// Load writes the RDF or JSON files (optionally gzipped) at path, as
+	// produced for Dgraph's live loader, applying their schema first.
+	Load(ctx context.Context, path string, opts LoadOptions) error
+
+	// Dump writes the schema and every node of the database to w as a
+	// versioned, self-describing container for Restore.
+	Dump(ctx context.Context, w io.Writer) error
+
+	// Backup writes a gzip-compressed Dump of the database to w, a portable
+	// archive that Restore reads into any backend.
+	Backup(ctx context.Context, w io.Writer) error
+
+	// Restore reads a container written by Dump, or an archive written by
+	// Backup, from r, applying its schema and writing its nodes under new
+	// UIDs.
+	Restore(ctx context.Context, r io.Reader) error
+
+	// PutBlob streams r into the client's BlobStore and returns a Blob
+	// recording its key, SHA-256 hash, size and contentType. Nothing is
+	// written to the graph until the Blob is inserted, typically as an edge
+	// of the node it belongs to.
+	PutBlob(ctx context.Context, r io.Reader, contentType string) (*Blob, error)
+
+	// GetBlob opens the content of blob from the client's BlobStore. The
+	// reader fails with ErrBlobCorrupt at the end of content that does not
+	// match the blob's hash or size. The caller must close it.
+	GetBlob(ctx context.Context, blob *Blob) (io.ReadCloser, error)
+
+	// Export writes typed nodes to w in opts.Format, by default as JSON Lines
+	// that Load reads back: one Dgraph JSON mutation object per node.
+	Export(ctx context.Context, w io.Writer, opts ExportOptions) error
+
+	// ExportGraphML writes typed nodes and the edges between them to w as a
+	// GraphML document for Gephi, yEd, and other graph visualisation tools.
+	ExportGraphML(ctx context.Context, w io.Writer, opts ExportOptions) error
+
+	// Changelog iterates the committed changes after sequence number after,
+	// following new commits until ctx is done. Requires WithChangelog.
+	Changelog(ctx context.Context, after uint64) iter.Seq2[ChangeEvent, error]
+
+	// ReadChangelog returns up to limit committed changes after sequence
+	// number after, without waiting for new ones. Requires WithChangelog.
+	ReadChangelog(ctx context.Context, after uint64, limit int) ([]ChangeEvent, error)
+
+	// Subscribe delivers the committed changes matching spec in order,
+	// following new commits until ctx is done. Requires WithChangelog.
+	Subscribe(ctx context.Context, spec SubscriptionSpec) iter.Seq2[ChangeEvent, error]
+
+	// Watch delivers the creation, change or deletion of nodes of the given
+	// types by any writer, following an embedded store's writes or polling a
+	// cluster, until ctx is done. It needs no changelog.
+	Watch(ctx context.Context, types ...string) iter.Seq2[ChangeEvent, error]
+
+	// Cypher translates a read-only openCypher query to DQL and returns the
+	// matched rows.
+	Cypher(ctx context.Context, query string, params map[string]any) (*CypherResult, error)
+
+	// CheckIntegrity scans the database for dangling edges, nodes without a
+	// dgraph.type, duplicate values of @unique predicates and @reverse edges
+	// present in one direction only, repairing what it can if opts.Repair is
+	// set. It reads every node holding a predicate, so prefer quiet periods
+	// on large databases.
+	CheckIntegrity(ctx context.Context, opts IntegrityOptions) (*IntegrityReport, error)
+
+	// PredicateStats reports, for every predicate, how many nodes and values
+	// it holds, the most values on one node, the average value size and the
+	// size of its indexes, for finding super-nodes and unindexed hot
+	// predicates.
+	PredicateStats(ctx context.Context) ([]PredicateStats, error)
+
+	// Delete removes nodes from the database. obj is either a []string of
+	// UIDs or what Insert takes: a pointer to a struct, or a slice of them,
+	// with the UID set. Edges tagged dgraph:"ondelete=cascade|restrict|setnull"
+	// that point to the nodes delete their holders, block the delete with
+	// ErrDeleteRestricted, or are removed, all in one transaction; see
+	// RegisterModels. DeleteCascade and DeleteDetach extend the delete to the
+	// nodes the structs' edge fields hold and to every edge pointing to the
+	// deleted nodes. Nodes of types with a dgraph:"softdelete" field are
+	// marked deleted instead of removed, unless DeletePurge is given.
+	Delete(ctx context.Context, obj any, opts ...DeleteOpt) error
+
+	// DeleteByUID removes the nodes with the given UIDs, as Delete does,
+	// applying the policies of the models the client has registered.
+	DeleteByUID(ctx context.Context, uids ...string) error
+
+	// RegisterModels records the ondelete and softdelete policies of models,
+	// and of the types their edges point to, for this client's deletes.
+	RegisterModels(models ...any)
+
+	// Undelete restores nodes Delete marked deleted, clearing the
+	// predicate of their type's dgraph:"softdelete" field; obj is what
+	// Delete takes. See SoftDeletePredicate.
+	Undelete(ctx context.Context, obj any) error
+
+	// Tx starts a transaction whose Insert, Update, Delete and reads commit
+	// together with Commit, or are dropped with Discard. It returns
+	// ErrReadOnly on a read-only client.
+	Tx(ctx context.Context) (*Txn, error)
 
 	// Close releases all resources used by the client.
 	// It should be called when the client is no longer needed.
 	Close()
 
+	// With returns a client sharing this one's connection or engine with
+	// opts applied on top of its options, such as a different namespace,
+	// timeout, read-only mode or logger.
+	With(opts ...ClientOpt) (Client, error)
+
+	// Now returns the current time by the client's clock: time.Now, or the
+	// function given to WithClock. The client stamps soft deletes and
+	// changelog entries with it, and the migrate package its lock leases.
+	Now() time.Time
+
 	// UpdateSchema ensures the database schema matches the provided object types.
 	// Pass one or more objects that will be used as templates for the schema.
 	UpdateSchema(context.Context, ...any) error
@@ -85,9 +294,31 @@ type Client interface {
 	// schema migrations that declare predicates no Go type models yet.
 	AlterSchema(ctx context.Context, schema string) error
 
-	// GetSchema retrieves the current schema definition from the database.
-	// Returns a string containing the full schema in Dgraph Schema Definition Language.
-	GetSchema(context.Context) (string, error)
+	// GetSchema retrieves the current schema definition from the database as
+	// typed predicates and node types. Its String method renders the schema in
+	// Dgraph Schema Definition Language.
+	GetSchema(context.Context) (*SchemaInfo, error)
+
+	// ExportSchema writes the live schema to w in Dgraph's .schema text
+	// format, which AlterSchema and Dgraph's Alter accept.
+	ExportSchema(ctx context.Context, w io.Writer) error
+
+	// SchemaVersion returns the schema version recorded in the database, or
+	// 0 if none has been. See WithSchemaVersion.
+	SchemaVersion(ctx context.Context) (int64, error)
+
+	// SetSchemaVersion records version as the database's schema version,
+	// replacing the previous one. The migrate package calls it as it applies
+	// migrations.
+	SetSchemaVersion(ctx context.Context, version int64) error
+
+	// AddIndex adds an index to an existing predicate and builds it from the
+	// stored data, reporting progress through spec.Progress. It returns once
+	// the index is served.
+	AddIndex(ctx context.Context, predicate string, spec IndexSpec) error
+
+	// RemoveIndex removes the index named by spec.Tokenizer from a predicate.
+	RemoveIndex(ctx context.Context, predicate string, spec IndexSpec) error
 
 	// DropAll removes the schema and all data from the database.
 	DropAll(context.Context) error
@@ -95,23 +326,59 @@ type Client interface {
 	// DropData removes all data from the database but keeps the schema intact.
 	DropData(context.Context) error
 
+	// DropPredicate removes a predicate and its values. It requires
+	// DropOptions.Confirm, refuses with ErrDropHasData while nodes hold the
+	// predicate unless DropOptions.Force is set, and returns the number of
+	// nodes holding it.
+	DropPredicate(ctx context.Context, name string, opts DropOptions) (int, error)
+
+	// DropType removes a node type definition, leaving its nodes and
+	// predicates in place. It applies the same safeguards as DropPredicate
+	// and returns the number of nodes of the type.
+	DropType(ctx context.Context, name string, opts DropOptions) (int, error)
+
 	// QueryRaw executes a raw Dgraph query with optional query variables.
 	// The `query` parameter is the Dgraph query string.
 	// The `vars` parameter is a map of variable names to their values, used to parameterize the query.
 	QueryRaw(context.Context, string, map[string]string) ([]byte, error)
 
+	// QueryStream iterates the nodes of model's type, each decoded into a new
+	// pointer to model's struct type, reading pageSize of them at a time (100
+	// when 0 or less) from one read-only transaction. A Page option caps and
+	// skips results in all; the other options apply as for ApplyQueryOptions.
+	// The iteration stops at the first error, which it yields.
+	QueryStream(ctx context.Context, model any, pageSize int, opts ...queryopt.Option) iter.Seq2[any, error]
+
+	// QueryFile executes the DQL query in the file at path, expanding its
+	// #include directives and binding vars, named with or without their $.
+	QueryFile(ctx context.Context, path string, vars map[string]string) ([]byte, error)
+
+	// MutateRDF writes N-Quads in RDF syntax as one transaction and returns
+	// the UIDs assigned to their blank nodes, keyed by name without the _:
+	// prefix. A non-empty cond is a DQL filter every existing subject must
+	// satisfy; otherwise nothing is written and applied is false.
+	MutateRDF(ctx context.Context, nquads string, cond string) (uids map[string]string, applied bool, err error)
+
 	// DgraphClient returns a gRPC Dgraph client from the connection pool and a cleanup function.
 	// The cleanup function must be called when finished with the client to return it to the pool.
 	DgraphClient() (*dgo.Dgraph, func(), error)
 
 	// WithRetry executes fn, retrying on aborted transactions per policy.
 	WithRetry(ctx context.Context, policy RetryPolicy, fn func() error) error
+
+	// Stats reports the health of the client's connections, such as the
+	// circuit breaker state of each endpoint of a URI listing several.
+	Stats() ClientStats
 }
 
 const (
 	// dgraphURIPrefix is the prefix for Dgraph server connections
 	dgraphURIPrefix = "dgraph://"
 
+	// dgraphTLSURIPrefix is the prefix for Dgraph server connections over
+	// TLS, verified by default
+	dgraphTLSURIPrefix = "dgraph+tls://"
+
 	// fileURIPrefix is the prefix for file-based local connections
 	fileURIPrefix = "file://"
 )
@@ -147,23 +414,79 @@ type SelfValidator interface {
 // clientOptions holds configuration options for the client.
 //
 // autoSchema: whether to automatically manage the schema.
+// schemaMode, schemaModels: how written models are reconciled with the live schema,
+// and the models checked when the client is created.
 // poolSize: the size of the dgo client connection pool.
 // maxEdgeTraversal: the maximum number of edges to traverse when querying.
+// maxEdgeFanout: the maximum number of targets loaded per edge when expanding; 0 = unlimited.
 // namespace: the namespace for the client.
 // logger: the logger for the client.
 // validator: the validator instance for struct validation.
 // embeddingProvider: optional provider for automatic SimString vector embeddings.
+// embedder: optional Embedder filling fields tagged embed=from:<predicate>.
+// blobStore: where PutBlob and GetBlob keep blob content; nil = blobs unsupported.
+// computes: derived fields registered with WithCompute.
+// maxConcurrentQueries, maxConcurrentMutations: admission limits; 0 = unlimited.
+// maxQueueDepth: callers allowed to wait for a slot; -1 = same as the limit.
+// queueTimeout: how long a queued caller waits before ErrOverloaded; 0 = until ctx is done.
+// queryHints: planning hints honored by the embedded backend.
+// queryPlanDebug: whether the embedded backend logs full type scans.
+// changelogDir: directory of the committed-mutation changelog; "" = disabled.
+// schemaVersion: schema version the binary supports, checked at startup; 0 = unchecked.
+// upsertRetry: how Upsert and LoadOrStore retry transactions aborted by a conflict.
+// strictPredicates: whether writes setting predicates missing from the schema fail.
+// optimisticLocking: whether writes check and advance the version predicate.
+// watchInterval: how often Watch polls a remote cluster; 0 = default.
+// timeout: the deadline applied to each call; 0 = none.
+// readOnly: whether writes fail with ErrReadOnly.
+// timeLocation: the location times are converted to on reads and writes; nil = as stored.
+// includeZero: predicates whose zero values writes store despite omitempty.
+// apiKey, bearerToken, login: credentials for remote connections.
+// tlsConfig: the TLS settings of remote connections, overriding the URI's; nil = from the URI.
+// breakerFailures, breakerCooldown: when an endpoint's circuit breaker opens and is probed; 0 = default.
+// historyRetention: how long an embedded store keeps past versions for ReadAt; 0 = forever.
+// now: the clock the client stamps soft deletes, changelog entries and Watch and Dump times with; nil = time.Now.
 type clientOptions struct {
-	autoSchema        bool
-	poolSize          int
-	maxEdgeTraversal  int
-	cacheSizeMB       int
-	maxRecvMsgSize    int
-	grpcDialOptions   []grpc.DialOption
-	namespace         string
-	logger            logr.Logger
-	validator         StructValidator
-	embeddingProvider EmbeddingProvider
+	autoSchema             bool
+	schemaMode             SchemaMode
+	schemaModels           []any
+	poolSize               int
+	maxEdgeTraversal       int
+	maxEdgeFanout          int
+	cacheSizeMB            int
+	maxRecvMsgSize         int
+	grpcDialOptions        []grpc.DialOption
+	namespace              string
+	logger                 logr.Logger
+	validator              StructValidator
+	embeddingProvider      EmbeddingProvider
+	embedder               Embedder
+	blobStore              BlobStore
+	computes               []compute
+	maxConcurrentQueries   int
+	maxConcurrentMutations int
+	maxQueueDepth          int
+	queueTimeout           time.Duration
+	queryHints             []QueryHint
+	queryPlanDebug         bool
+	changelogDir           string
+	schemaVersion          int64
+	upsertRetry            RetryPolicy
+	strictPredicates       bool
+	optimisticLocking      bool
+	watchInterval          time.Duration
+	timeout                time.Duration
+	readOnly               bool
+	timeLocation           *time.Location
+	includeZero            []string
+	apiKey                 string
+	bearerToken            string
+	login                  *aclLogin
+	tlsConfig              *tls.Config
+	breakerFailures        int
+	breakerCooldown        time.Duration
+	historyRetention       time.Duration
+	now                    func() time.Time
 }
 
 // ClientOpt is a function that configures a client
@@ -252,13 +575,119 @@ func WithValidator(v StructValidator) ClientOpt {
 // and maintain shadow float32vector predicates for SimString fields tagged with
 // `dgraph:"embedding"`. When set, Insert, Upsert, and Update operations will
 // call the provider to embed any SimString values and persist the resulting
-// vectors alongside the primary string predicates.
+// vectors alongside the primary string predicates. Unless WithEmbedder is
+// also given, the provider fills embed=from fields too.
 func WithEmbeddingProvider(p EmbeddingProvider) ClientOpt {
 	return func(o *clientOptions) {
 		o.embeddingProvider = p
 	}
 }
 
+// WithEmbedder sets the Embedder used to populate vector fields tagged
+// `dgraph:"embed=from:<predicate>"`. On Insert, Upsert, and Update the client
+// embeds the text of the named source field and stores the result in the
+// tagged dgman.VectorFloat32 field, so the vector is written in the same
+// mutation as the node and any hnsw index on it stays current:
+//
+//	type Article struct {
+//	    Body      string            `json:"body,omitempty"`
+//	    Embedding *dg.VectorFloat32 `json:"embedding,omitempty" dgraph:"embed=from:body index=hnsw(metric:\"cosine\")"`
+//	}
+//
+// Objects whose source field is empty are left untouched. Without
+// WithEmbedder, a WithEmbeddingProvider provider fills these fields.
+func WithEmbedder(e Embedder) ClientOpt {
+	return func(o *clientOptions) {
+		o.embedder = e
+	}
+}
+
+// WithMaxConcurrentQueries caps the number of read requests (queries, Get,
+// QueryRaw, and query-builder terminals) the client runs at once. Callers
+// beyond the limit queue for a free slot; when the queue is full, or the
+// queue timeout elapses, the call fails with an error matching ErrOverloaded.
+// Zero (the default) means unlimited. Applies to both file:// and dgraph://.
+func WithMaxConcurrentQueries(n int) ClientOpt {
+	return func(o *clientOptions) {
+		o.maxConcurrentQueries = n
+	}
+}
+
+// WithMaxConcurrentMutations caps the number of mutation requests (Insert,
+// Update, Upsert, Delete, and raw mutations) the client runs at once, with the
+// same queueing and ErrOverloaded behavior as WithMaxConcurrentQueries.
+// Zero (the default) means unlimited.
+func WithMaxConcurrentMutations(n int) ClientOpt {
+	return func(o *clientOptions) {
+		o.maxConcurrentMutations = n
+	}
+}
+
+// WithMaxQueueDepth sets how many callers may wait for a slot when a
+// concurrency limit is saturated. Callers arriving once the queue is full are
+// rejected immediately with ErrOverloaded; zero disables queueing entirely.
+// By default the queue depth equals the concurrency limit.
+func WithMaxQueueDepth(n int) ClientOpt {
+	return func(o *clientOptions) {
+		o.maxQueueDepth = n
+	}
+}
+
+// WithQueueTimeout bounds how long a queued caller waits for a slot before
+// failing with ErrOverloaded. Zero (the default) waits until the caller's
+// context is done.
+func WithQueueTimeout(d time.Duration) ClientOpt {
+	return func(o *clientOptions) {
+		o.queueTimeout = d
+	}
+}
+
+// WithHint installs query planning hints. Hints are honored by the embedded
+// (file://) backend and ignored for dgraph:// URIs, whose server plans
+// queries itself. May be supplied multiple times; hints accumulate.
+func WithHint(hints ...QueryHint) ClientOpt {
+	return func(o *clientOptions) {
+		o.queryHints = append(o.queryHints, hints...)
+	}
+}
+
+// WithQueryPlanDebug makes the embedded backend log, at verbosity 0, every
+// query block that filters on predicates but roots at type(T) — a full scan
+// of the type's nodes — so missing indexes and PreferIndex candidates show up
+// in the logs rather than only in latency.
+func WithQueryPlanDebug(enable bool) ClientOpt {
+	return func(o *clientOptions) {
+		o.queryPlanDebug = enable
+	}
+}
+
+// WithChangelog records every committed mutation as a ChangeEvent in an
+// append-only log kept in dir, readable with Client.Changelog. The log is
+// durable across restarts, so consumers that checkpoint the last Seq they
+// processed can resume without missing a recorded change. Clients sharing a
+// directory share one log.
+//
+// An entry is written after its mutation commits, not with it: a crash
+// between the two loses the entry, and a failure to write it is returned as
+// an error wrapping ErrChangelogAppend although the data is stored. The log
+// is therefore at most once with respect to the data; the sinks reading it
+// deliver its entries at least once.
+func WithChangelog(dir string) ClientOpt {
+	return func(o *clientOptions) {
+		o.changelogDir = dir
+	}
+}
+
+// WithClock sets the clock the client reads the current time from, instead
+// of time.Now, so that tests can control the times it records: soft delete
+// stamps, changelog CommittedAt times, Watch and Dump times and migration
+// lock leases. A nil clock restores time.Now. See modusgraphtest.Clock.
+func WithClock(now func() time.Time) ClientOpt {
+	return func(o *clientOptions) {
+		o.now = now
+	}
+}
+
 // NewValidator creates a new validator instance with default settings.
 // This is a convenience function for creating a validator to use with WithValidator.
 // It returns a *validator.Validate from github.com/go-playground/validator/v10.
@@ -274,12 +703,16 @@ func NewValidator() *validator.Validate {
 //
 // Optional configuration can be provided via the opts parameter:
 //   - WithAutoSchema(bool) - Enable/disable automatic schema creation for inserted objects
+//   - WithSchemaMode(SchemaMode, ...any) - Check, warn about, or additively apply schema drift
 //   - WithPoolSize(int) - Set the connection pool size for better performance under load
 //   - WithMaxEdgeTraversal(int) - Set the maximum number of edges to traverse when fetching an object
+//   - WithMaxDepth(int), WithMaxEdgeFanout(int) - Bound how deep and how wide Get expands edges
 //   - WithNamespace(string) - Set the database namespace for multi-tenant installations
 //   - WithLogger(logr.Logger) - Configure structured logging with custom verbosity levels
 //   - WithCacheSizeMB(int) - Set the memory cache size in MB (only applicable for embedded databases)
 //   - WithValidator(*validator.Validate) - Set a validator instance for struct validation before mutations
+//   - WithMaxConcurrentQueries(int), WithMaxConcurrentMutations(int) - Bound in-flight work; excess
+//     callers queue and then fail with ErrOverloaded
 //
 // The returned Client provides a consistent interface regardless of whether you're
 // connected to a remote Dgraph cluster or a local embedded database. This abstraction
@@ -296,6 +729,8 @@ func NewClient(uri string, opts ...ClientOpt) (Client, error) {
 		maxEdgeTraversal: 10,
 		cacheSizeMB:      64,             // 64 MB
 		logger:           logr.Discard(), // No-op logger by default
+		maxQueueDepth:    -1,             // Queue as many callers as the limit admits
+		upsertRetry:      DefaultRetryPolicy,
 	}
 
 	// Apply provided options
@@ -309,10 +744,17 @@ func NewClient(uri string, opts ...ClientOpt) (Client, error) {
 	}
 
 	client := client{
-		uri:       uri,
-		options:   options,
-		logger:    options.logger,
-		consumeMu: &sync.Mutex{},
+		uri:          uri,
+		options:      options,
+		logger:       options.logger,
+		consumeMu:    &sync.Mutex{},
+		schemaWarned: &sync.Map{},
+		admission:    newAdmissionControl(options),
+		policies:     newDeletePolicies(),
+	}
+	client.policies.register(options.schemaModels...)
+	if options.strictPredicates {
+		client.predicates = &predicateGuard{}
 	}
 
 	clientMapLock.Lock()
@@ -322,8 +764,16 @@ func NewClient(uri string, opts ...ClientOpt) (Client, error) {
 		return clientMap[key], nil
 	}
 
+	if options.changelogDir != "" {
+		changes, err := openChangelog(options.changelogDir)
+		if err != nil {
+			return nil, fmt.Errorf("opening changelog: %w", err)
+		}
+		client.changes = changes
+	}
+
 	switch {
-	case strings.HasPrefix(uri, dgraphURIPrefix):
+	case strings.HasPrefix(uri, dgraphURIPrefix), strings.HasPrefix(uri, dgraphTLSURIPrefix):
 		factory := func() (*dgo.Dgraph, error) {
 			client.logger.V(2).Info("Opening new Dgraph connection", "uri", uri)
 			return dgo.Open(uri)
@@ -335,12 +785,48 @@ func NewClient(uri string, opts ...ClientOpt) (Client, error) {
 			dialOpts = append(dialOpts,
 				grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(options.maxRecvMsgSize)))
 		}
+		if options.tlsConfig != nil {
+			dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(options.tlsConfig)))
+		}
 		dialOpts = append(dialOpts, options.grpcDialOptions...)
-		if len(dialOpts) > 0 {
-			endpoint, dgoOpts, err := parseDgraphURI(uri)
+		if client.predicates != nil {
+			dialOpts = append(dialOpts, grpc.WithChainUnaryInterceptor(client.predicates.unaryInterceptor()))
+		}
+		if client.admission.enabled() {
+			dialOpts = append(dialOpts, grpc.WithChainUnaryInterceptor(client.admission.unaryInterceptor()))
+		}
+		if client.changes != nil {
+			dialOpts = append(dialOpts, grpc.WithChainUnaryInterceptor(client.changes.unaryInterceptor(options.clock())))
+		}
+		dialOpts = append(dialOpts, grpc.WithChainUnaryInterceptor(readAtInterceptor()))
+		authDialOpts, err := options.authDialOptions()
+		if err != nil {
+			client.changes.release()
+			return nil, err
+		}
+		dialOpts = append(dialOpts, authDialOpts...)
+		conn, err := parseDgraphConn(uri)
+		if err != nil {
+			client.changes.release()
+			return nil, err
+		}
+		switch {
+		case len(conn.hosts) > 1:
+			// The endpoints share one set of connections and circuit
+			// breakers, whichever pooled client makes the call.
+			endpoints, err := newFailover(conn.hosts, append(conn.dialOptions(), dialOpts...), options)
 			if err != nil {
+				client.changes.release()
 				return nil, err
 			}
+			client.endpoints = endpoints
+			factory = func() (*dgo.Dgraph, error) {
+				//nolint:staticcheck // dgo.NewDgraphClient is deprecated but required to route calls through failover
+				return dgo.NewDgraphClient(api.NewDgraphClient(endpoints)), nil
+			}
+		// dgo.Open only knows the plain dgraph:// scheme.
+		case len(dialOpts) > 0 || !strings.HasPrefix(uri, dgraphURIPrefix):
+			dgoOpts := conn.dgoOptions()
 			for _, opt := range dialOpts {
 				dgoOpts = append(dgoOpts, dgo.WithGrpcOption(opt))
 			}
@@ -348,25 +834,32 @@ func NewClient(uri string, opts ...ClientOpt) (Client, error) {
 				client.logger.V(2).Info("Opening new Dgraph connection",
 					"uri", uri, "maxRecvMsgSize", options.maxRecvMsgSize,
 					"grpcDialOptions", len(options.grpcDialOptions))
-				return dgo.NewClient(endpoint, dgoOpts...)
+				return dgo.NewClient(conn.hosts[0], dgoOpts...)
 			}
 		}
 		client.pool = newClientPool(options.poolSize, factory, client.logger)
 		dg.SetLogger(client.logger)
+		if err := client.checkStartupSchema(); err != nil {
+			client.Close()
+			return nil, err
+		}
 		clientMap[key] = client
 		return client, nil
 	case strings.HasPrefix(uri, fileURIPrefix):
 		// parse off the file:// prefix
 		uri = uri[len(fileURIPrefix):]
 		if _, err := os.Stat(uri); err != nil {
+			client.changes.release()
 			return nil, err
 		}
 		engine, err := NewEngine(Config{
-			dataDir:     uri,
-			logger:      client.logger,
-			cacheSizeMB: options.cacheSizeMB,
+			dataDir:          uri,
+			logger:           client.logger,
+			cacheSizeMB:      options.cacheSizeMB,
+			historyRetention: options.historyRetention,
 		})
 		if err != nil {
+			client.changes.release()
 			return nil, err
 		}
 		client.engine = engine
@@ -376,110 +869,162 @@ func NewClient(uri string, opts ...ClientOpt) (Client, error) {
 			nsID, err := parseNamespaceID(options.namespace)
 			if err != nil {
 				engine.Close()
+				client.changes.release()
 				return nil, fmt.Errorf("invalid namespace ID %q: %w", options.namespace, err)
 			}
 			ns, err = engine.GetNamespace(nsID)
 			if err != nil {
 				engine.Close()
+				client.changes.release()
 				return nil, fmt.Errorf("failed to get namespace %d: %w", nsID, err)
 			}
 		}
+		client.ns = ns
 		client.pool = newClientPool(1, func() (*dgo.Dgraph, error) {
 			embeddedClient := newEmbeddedDgraphClient(engine, ns)
+			embeddedClient.admission = client.admission
+			embeddedClient.changes = client.changes
+			embeddedClient.now = options.clock()
+			embeddedClient.predicates = client.predicates
+			embeddedClient.planner = newQueryPlanner(options, namespaceTokenizers(ns), namespaceStats(ns))
 			//nolint:staticcheck // dgo.NewDgraphClient is deprecated but required for embedded client
 			return dgo.NewDgraphClient(embeddedClient), nil
 		}, client.logger)
 		dg.SetLogger(client.logger)
+		if err := client.checkStartupSchema(); err != nil {
+			client.Close()
+			return nil, err
+		}
 		clientMap[key] = client
 		return client, nil
 	}
+	client.changes.release()
 	return nil, errors.New("invalid uri")
 
 }
 
-// parseDgraphURI mirrors dgo.Open's connection-string parsing so callers can
+// dgraphConn is a parsed dgraph:// or dgraph+tls:// connection string.
+type dgraphConn struct {
+	hosts       []string
+	apiKey      string
+	bearerToken string
+	tlsConfig   *tls.Config // nil for plaintext
+	namespace   uint64
+	login       *aclLogin // from user:password@; nil without
+}
+
+// parseDgraphConn mirrors dgo.Open's connection-string parsing so callers can
 // route through dgo.NewClient with additional dgo.ClientOption values (e.g.
-// custom grpc.DialOption settings). It returns the host:port endpoint and the
-// dgo client options derived from the URI's auth, sslmode, and namespace params.
-func parseDgraphURI(connStr string) (string, []dgo.ClientOption, error) {
+// custom grpc.DialOption settings), reading the URI's hosts and its auth,
+// TLS, and namespace params. It also accepts the dgraph+tls:// scheme, whose
+// sslmode defaults to verify-ca instead of disable, and a comma-separated
+// list of hosts to fail over between.
+func parseDgraphConn(connStr string) (*dgraphConn, error) {
 	u, err := url.Parse(connStr)
 	if err != nil {
-		return "", nil, fmt.Errorf("invalid connection string: %w", err)
-	}
-	if u.Scheme != "dgraph" {
-		return "", nil, errors.New("invalid scheme: must start with dgraph://")
+		return nil, fmt.Errorf("invalid connection string: %w", err)
 	}
-	if !strings.Contains(u.Host, ":") {
-		return "", nil, errors.New("invalid connection string: host url must have both host and port")
+	if u.Scheme != "dgraph" && u.Scheme != "dgraph+tls" {
+		return nil, errors.New("invalid scheme: must start with dgraph:// or dgraph+tls://")
 	}
-	if strings.Split(u.Host, ":")[1] == "" {
-		return "", nil, errors.New("invalid connection string: missing port after port-separator colon")
+	conn := &dgraphConn{hosts: strings.Split(u.Host, ",")}
+	for _, host := range conn.hosts {
+		if !strings.Contains(host, ":") {
+			return nil, errors.New("invalid connection string: host url must have both host and port")
+		}
+		if strings.Split(host, ":")[1] == "" {
+			return nil, errors.New("invalid connection string: missing port after port-separator colon")
+		}
 	}
 
 	params, err := url.ParseQuery(u.RawQuery)
 	if err != nil {
-		return "", nil, fmt.Errorf("malformed connection string: %w", err)
+		return nil, fmt.Errorf("malformed connection string: %w", err)
 	}
 
-	apiKey := params.Get("apikey")
-	bearerToken := params.Get("bearertoken")
-	if apiKey != "" && bearerToken != "" {
-		return "", nil, errors.New("invalid connection string: both apikey and bearertoken cannot be provided")
-	}
-
-	opts := []dgo.ClientOption{}
-	if apiKey != "" {
-		opts = append(opts, dgo.WithDgraphAPIKey(apiKey))
-	}
-	if bearerToken != "" {
-		opts = append(opts, dgo.WithBearerToken(bearerToken))
+	conn.apiKey = params.Get("apikey")
+	conn.bearerToken = params.Get("bearertoken")
+	if conn.apiKey != "" && conn.bearerToken != "" {
+		return nil, errors.New("invalid connection string: both apikey and bearertoken cannot be provided")
 	}
 
 	sslMode := params.Get("sslmode")
 	if sslMode == "" {
 		sslMode = "disable"
+		if u.Scheme == "dgraph+tls" {
+			sslMode = "verify-ca"
+		}
 	}
-	switch sslMode {
-	case "disable":
-		opts = append(opts, dgo.WithGrpcOption(
-			grpc.WithTransportCredentials(insecure.NewCredentials())))
-	case "require":
-		opts = append(opts, dgo.WithSkipTLSVerify())
-	case "verify-ca":
-		opts = append(opts, dgo.WithSystemCertPool())
-	default:
-		return "", nil, fmt.Errorf(
-			"invalid SSL mode: %s (must be one of disable, require, verify-ca)", sslMode)
+	if conn.tlsConfig, err = uriTLSConfig(params, sslMode); err != nil {
+		return nil, err
 	}
 
 	if nsParam := params.Get("namespace"); nsParam != "" {
-		nsID, err := strconv.ParseUint(nsParam, 10, 64)
-		if err != nil {
-			return "", nil, fmt.Errorf("invalid namespace ID: %w", err)
+		if conn.namespace, err = strconv.ParseUint(nsParam, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid namespace ID: %w", err)
 		}
-		opts = append(opts, dgo.WithNamespace(nsID))
 	}
 
 	if u.User != nil {
 		username := u.User.Username()
 		password, _ := u.User.Password()
 		if username == "" || password == "" {
-			return "", nil, errors.New("invalid connection string: both username and password must be provided")
+			return nil, errors.New("invalid connection string: both username and password must be provided")
 		}
-		opts = append(opts, dgo.WithACLCreds(username, password))
+		conn.login = &aclLogin{user: username, password: password, namespace: conn.namespace}
 	}
+	return conn, nil
+}
 
-	return u.Host, opts, nil
+// transportCredentials returns the dial option securing connections as the
+// URI asks.
+func (conn *dgraphConn) transportCredentials() grpc.DialOption {
+	if conn.tlsConfig == nil {
+		return grpc.WithTransportCredentials(insecure.NewCredentials())
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(conn.tlsConfig))
+}
+
+// dgoOptions returns the dgo client options applying the URI's settings to
+// a connection opened with dgo.NewClient, which logs in itself.
+func (conn *dgraphConn) dgoOptions() []dgo.ClientOption {
+	opts := []dgo.ClientOption{}
+	if conn.apiKey != "" {
+		opts = append(opts, dgo.WithDgraphAPIKey(conn.apiKey))
+	}
+	if conn.bearerToken != "" {
+		opts = append(opts, dgo.WithBearerToken(conn.bearerToken))
+	}
+	opts = append(opts, dgo.WithGrpcOption(conn.transportCredentials()))
+	if conn.namespace != 0 {
+		opts = append(opts, dgo.WithNamespace(conn.namespace))
+	}
+	if conn.login != nil {
+		opts = append(opts, dgo.WithACLCreds(conn.login.user, conn.login.password))
+	}
+	return opts
+}
+
+// dialOptions returns the gRPC dial options applying the URI's settings to
+// a connection opened directly, logging in through an interceptor.
+func (conn *dgraphConn) dialOptions() []grpc.DialOption {
+	opts := []grpc.DialOption{conn.transportCredentials()}
+	opts = append(opts, credentialDialOptions(conn.apiKey, conn.bearerToken, conn.login)...)
+	return opts
 }
 
 type client struct {
-	uri     string
-	engine  *Engine
+	uri    string
+	engine *Engine
+	// ns is the embedded engine namespace the client is bound to; nil for
+	// remote clients.
+	ns      *Namespace
 	options clientOptions
 	pool    *clientPool
 	logger  logr.Logger
-	// consumeMu serializes LoadAndDelete's read-then-delete critical section so
-	// exactly one in-process caller consumes a given node. The client value is
+	// consumeMu serializes LoadAndDelete's read-then-delete and UpsertIf's
+	// read-check-write critical sections so exactly one in-process caller
+	// consumes or conditionally writes a given node. The client value is
 	// copied (value receivers, cached by value in clientMap), so the mutex is a
 	// pointer shared across every copy that shares this client's connection.
 	// Against a real Dgraph cluster the shared read-write transaction would also
@@ -487,6 +1032,27 @@ type client struct {
 	// single-winner semantics against the embedded engine, whose commit path
 	// performs no optimistic-concurrency conflict check.
 	consumeMu *sync.Mutex
+	// admission enforces WithMaxConcurrentQueries/WithMaxConcurrentMutations.
+	// Shared by pointer, like consumeMu, so every copy draws on the same slots.
+	admission *admissionControl
+	// changes is the WithChangelog log; nil when disabled. Shared by pointer
+	// with the embedded client or gRPC interceptor that records into it.
+	changes *changelog
+	// predicates enforces WithStrictPredicates; nil when disabled. Shared by
+	// pointer with the embedded client or gRPC interceptor that applies it.
+	predicates *predicateGuard
+	// policies holds the delete policies of the models the client has seen.
+	// Shared by pointer with derived clients.
+	policies *deletePolicies
+	// schemaWarned records the drift already logged in SchemaWarn and
+	// SchemaAuto modes, so each distinct difference is logged once.
+	schemaWarned *sync.Map
+	// endpoints fails calls over between the hosts of a URI listing several;
+	// nil otherwise. Shared by pointer by every pooled connection.
+	endpoints *failover
+	// derived marks a client returned by With, which shares its parent's
+	// pool and engine and so must not close them.
+	derived bool
 }
 
 func (c client) key() string {
@@ -498,16 +1064,57 @@ func (c client) key() string {
 	if c.options.embeddingProvider != nil {
 		embeddingKey = fmt.Sprintf("%p", c.options.embeddingProvider)
 	}
+	if c.options.embedder != nil {
+		embeddingKey += fmt.Sprintf("/%p", c.options.embedder)
+	}
+	if c.options.blobStore != nil {
+		embeddingKey += fmt.Sprintf("/blobs=%p", c.options.blobStore)
+	}
 	// Custom gRPC dial options only apply to remote (dgraph://) connections;
 	// they are ignored for embedded (file://) URIs, so they only contribute to
 	// the dedup key for remote clients — matching that documented behavior.
 	dialKey := "0"
-	if strings.HasPrefix(c.uri, dgraphURIPrefix) {
-		dialKey = dialOptionsKey(c.options.grpcDialOptions)
-	}
-	return fmt.Sprintf("%s:%t:%d:%d:%d:%d:%s:%s:%s:%s", c.uri, c.options.autoSchema, c.options.poolSize,
-		c.options.maxEdgeTraversal, c.options.cacheSizeMB, c.options.maxRecvMsgSize,
-		c.options.namespace, validatorKey, embeddingKey, dialKey)
+	if !strings.HasPrefix(c.uri, fileURIPrefix) {
+		dialKey = fmt.Sprintf("%s/%s/%p/%d/%s", dialOptionsKey(c.options.grpcDialOptions),
+			c.options.authKey(), c.options.tlsConfig, c.options.breakerFailures, c.options.breakerCooldown)
+	}
+	// One field per line, so that a new option is not left out of the key.
+	var b strings.Builder
+	field := func(name string, value any) {
+		fmt.Fprintf(&b, "%s=%v;", name, value)
+	}
+	field("uri", c.uri)
+	field("autoSchema", c.options.autoSchema)
+	field("schemaMode", schemaModeKey(c.options.schemaMode, c.options.schemaModels))
+	field("poolSize", c.options.poolSize)
+	field("maxEdgeTraversal", c.options.maxEdgeTraversal)
+	field("cacheSizeMB", c.options.cacheSizeMB)
+	field("maxRecvMsgSize", c.options.maxRecvMsgSize)
+	field("namespace", c.options.namespace)
+	field("validator", validatorKey)
+	field("embedding", embeddingKey)
+	field("computes", computesKey(c.options.computes))
+	field("dial", dialKey)
+	field("maxConcurrentQueries", c.options.maxConcurrentQueries)
+	field("maxConcurrentMutations", c.options.maxConcurrentMutations)
+	field("maxQueueDepth", c.options.maxQueueDepth)
+	field("queueTimeout", c.options.queueTimeout)
+	field("queryHints", hintsKey(c.options.queryHints))
+	field("queryPlanDebug", c.options.queryPlanDebug)
+	field("changelogDir", c.options.changelogDir)
+	field("schemaVersion", c.options.schemaVersion)
+	field("upsertRetry", c.options.upsertRetry)
+	field("strictPredicates", c.options.strictPredicates)
+	field("maxEdgeFanout", c.options.maxEdgeFanout)
+	field("timeout", c.options.timeout)
+	field("readOnly", c.options.readOnly)
+	field("timeLocation", locationKey(c.options.timeLocation))
+	field("includeZero", strings.Join(c.options.includeZero, ","))
+	field("historyRetention", c.options.historyRetention)
+	field("optimisticLocking", c.options.optimisticLocking)
+	field("watchInterval", c.options.watchInterval)
+	field("clock", fmt.Sprintf("%p", c.options.now))
+	return b.String()
 }
 
 // dialOptionsKey identifies a set of custom gRPC dial options for the client
@@ -541,7 +1148,9 @@ func checkPointer(obj any) error {
 	return nil
 }
 
-// validateStruct validates a struct using the configured validator.
+// validateStruct checks the dgraph:"required" fields of obj and validates it
+// using the configured validator. Field failures are returned together as a
+// *ValidationError.
 //
 // It does NOT early-return when no StructValidator is configured: a value may
 // implement SelfValidator, which must run on the default client too. The
@@ -557,23 +1166,61 @@ func (c client) validateStruct(ctx context.Context, obj any) error {
 		val = val.Elem()
 	}
 
-	if val.Kind() == reflect.Slice {
-		for i := 0; i < val.Len(); i++ {
-			elem := val.Index(i)
-			if elem.Kind() == reflect.Ptr {
-				if elem.IsNil() {
-					return fmt.Errorf("cannot validate nil pointer at index %d", i)
-				}
-				elem = elem.Elem()
-			}
-			if err := c.validateOne(ctx, elem); err != nil {
-				return err
+	if val.Kind() != reflect.Slice {
+		return c.validateElem(ctx, val, "")
+	}
+
+	// Collect the field errors of every element so one call reports them all.
+	ve := &ValidationError{}
+	for i := 0; i < val.Len(); i++ {
+		elem := val.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				return fmt.Errorf("cannot validate nil pointer at index %d", i)
 			}
+			elem = elem.Elem()
 		}
-	} else {
-		return c.validateOne(ctx, val)
+		err := c.validateElem(ctx, elem, fmt.Sprintf("[%d].", i))
+		var elemErr *ValidationError
+		switch {
+		case err == nil:
+		case errors.As(err, &elemErr):
+			ve.merge(elemErr)
+		default:
+			return err
+		}
+	}
+	if len(ve.Fields) > 0 {
+		return ve
 	}
+	return nil
+}
 
+// validateElem checks the required fields of val and runs validateOne on it,
+// returning field failures as a *ValidationError with paths starting with
+// prefix.
+func (c client) validateElem(ctx context.Context, val reflect.Value, prefix string) error {
+	ve := &ValidationError{}
+	if val.Kind() == reflect.Struct {
+		fields, causes := requiredFieldErrors(val)
+		for _, f := range fields {
+			f.Path = prefix + f.Path
+			ve.Fields = append(ve.Fields, f)
+		}
+		ve.causes = causes
+	}
+	err := newValidationError(c.validateOne(ctx, val), val.Type(), prefix)
+	var fieldErr *ValidationError
+	switch {
+	case err == nil:
+	case errors.As(err, &fieldErr):
+		ve.merge(fieldErr)
+	default:
+		return err
+	}
+	if len(ve.Fields) > 0 {
+		return ve
+	}
 	return nil
 }
 
@@ -600,14 +1247,32 @@ func (c client) validateOne(ctx context.Context, val reflect.Value) error {
 // Insert implements inserting an object or slice of objects in the database.
 // Passed object must be a pointer to a struct with appropriate dgraph tags.
 func (c client) Insert(ctx context.Context, obj any) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 	obj = UnwrapSchema(obj)
+	if err := c.applyComputed(ctx, obj); err != nil {
+		return err
+	}
+	NormalizeTimes(c, obj)
 	// Validate struct before insertion
 	if err := c.validateStruct(ctx, obj); err != nil {
 		return err
 	}
 
-	return c.process(ctx, obj, "Insert", func(tx *dg.TxnContext, obj any) ([]string, error) {
-		return tx.MutateBasic(obj)
+	return c.lockedWrite(ctx, obj, func() error {
+		return c.process(ctx, obj, "Insert", func(tx *dg.TxnContext, obj any) ([]string, error) {
+			defer c.lockUniqueGroups(obj)()
+			if err := c.checkUniqueGroups(ctx, tx, obj, ""); err != nil {
+				return nil, err
+			}
+			if err := c.checkVersions(ctx, tx, obj, ""); err != nil {
+				return nil, err
+			}
+			return tx.MutateBasic(obj)
+		})
 	})
 }
 
@@ -618,14 +1283,32 @@ func (c client) Insert(ctx context.Context, obj any) error {
 //
 // Deprecated: InsertRaw is now identical to Insert. Use Insert instead.
 func (c client) InsertRaw(ctx context.Context, obj any) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 	obj = UnwrapSchema(obj)
+	if err := c.applyComputed(ctx, obj); err != nil {
+		return err
+	}
+	NormalizeTimes(c, obj)
 	// Validate struct before insertion
 	if err := c.validateStruct(ctx, obj); err != nil {
 		return err
 	}
 
-	return c.process(ctx, obj, "Insert", func(tx *dg.TxnContext, obj any) ([]string, error) {
-		return tx.MutateBasic(obj)
+	return c.lockedWrite(ctx, obj, func() error {
+		return c.process(ctx, obj, "Insert", func(tx *dg.TxnContext, obj any) ([]string, error) {
+			defer c.lockUniqueGroups(obj)()
+			if err := c.checkUniqueGroups(ctx, tx, obj, ""); err != nil {
+				return nil, err
+			}
+			if err := c.checkVersions(ctx, tx, obj, ""); err != nil {
+				return nil, err
+			}
+			return tx.MutateBasic(obj)
+		})
 	})
 }
 
@@ -633,14 +1316,63 @@ func (c client) InsertRaw(ctx context.Context, obj any) error {
 // Note that the struct tag `upsert` must be used. One or more predicates can be specified
 // to be used for upserting. If none are specified, the first predicate with the `upsert` tag
 // will be used.
+// A single predicate naming a unique group of the object's type matches on the
+// combination of the group's values instead.
 func (c client) Upsert(ctx context.Context, obj any, predicates ...string) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 	obj = UnwrapSchema(obj)
+	if err := c.applyComputed(ctx, obj); err != nil {
+		return err
+	}
+	NormalizeTimes(c, obj)
 	// Validate struct before upsert
 	if err := c.validateStruct(ctx, obj); err != nil {
 		return err
 	}
 
+	if c.hasVersions(obj) {
+		return c.lockedWrite(ctx, obj, func() error {
+			return c.upsert(ctx, obj, predicates)
+		})
+	}
+	return c.retryUpsert(ctx, obj, func() error {
+		return c.upsert(ctx, obj, predicates)
+	})
+}
+
+// upsert makes one attempt at Upsert.
+func (c client) upsert(ctx context.Context, obj any, predicates []string) error {
 	return c.process(ctx, obj, "Upsert", func(tx *dg.TxnContext, obj any) ([]string, error) {
+		defer c.lockUniqueGroups(obj)()
+		// Upserting on a unique group matches on the combination of its values.
+		if len(predicates) == 1 {
+			if _, ok := findUniqueGroup(obj, predicates[0]); ok {
+				if err := c.resolveUniqueGroup(ctx, tx, obj, predicates[0]); err != nil {
+					return nil, err
+				}
+				if err := c.checkUniqueGroups(ctx, tx, obj, ""); err != nil {
+					return nil, err
+				}
+				if err := c.checkVersions(ctx, tx, obj, ""); err != nil {
+					return nil, err
+				}
+				return tx.MutateBasic(obj)
+			}
+		}
+		match := firstUpsertPredicate(obj)
+		if len(predicates) > 0 {
+			match = predicates[0]
+		}
+		if err := c.checkUniqueGroups(ctx, tx, obj, match); err != nil {
+			return nil, err
+		}
+		if err := c.checkVersions(ctx, tx, obj, match); err != nil {
+			return nil, err
+		}
 		return tx.Upsert(obj, predicates...)
 	})
 }
@@ -651,28 +1383,38 @@ func (c client) Upsert(ctx context.Context, obj any, predicates ...string) error
 // result means an existing node matched, and obj is populated with its fields.
 // With no predicates, the first field tagged dgraph:"upsert" is used.
 func (c client) LoadOrStore(ctx context.Context, obj any, predicates ...string) (loaded bool, err error) {
+	if err := c.writable(); err != nil {
+		return false, err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 	obj = UnwrapSchema(obj)
+	if err := c.applyComputed(ctx, obj); err != nil {
+		return false, err
+	}
+	NormalizeTimes(c, obj)
 	if err := c.validateStruct(ctx, obj); err != nil {
 		return false, err
 	}
 
 	dgClient, err := c.pool.get()
 	if err != nil {
-		c.logger.Error(err, "Failed to get client from pool")
+		c.log(ctx).Error(err, "Failed to get client from pool")
 		return false, err
 	}
 	defer c.pool.put(dgClient)
 
-	tx := dg.NewTxnContext(ctx, dgClient).SetCommitNow()
-	uids, err := tx.MutateOrGet(obj, predicates...)
-	if err != nil {
-		if uniqueErr := parseUniqueError(err); uniqueErr != nil {
-			return false, uniqueErr
+	err = c.retryUpsert(ctx, obj, func() error {
+		tx := dg.NewTxnContext(ctx, dgClient).SetCommitNow()
+		uids, err := tx.MutateOrGet(obj, predicates...)
+		if err != nil {
+			return uniqueConflict(err)
 		}
-		return false, err
-	}
-	// MutateOrGet returns created UIDs only; empty => an existing node matched.
-	return len(uids) == 0, nil
+		// MutateOrGet returns created UIDs only; empty => an existing node matched.
+		loaded = len(uids) == 0
+		return nil
+	})
+	return loaded, err
 }
 
 // firstUpsertPredicate returns the Dgraph predicate name of the first field
@@ -733,6 +1475,11 @@ func uidOf(obj any) string {
 // PostgreSQL's DELETE … RETURNING. With no predicates, the first dgraph:"upsert"
 // field is used.
 func (c client) LoadAndDelete(ctx context.Context, obj any, key any, predicates ...string) (loaded bool, err error) {
+	if err := c.writable(); err != nil {
+		return false, err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 	obj = UnwrapSchema(obj)
 	if err := checkPointer(obj); err != nil {
 		return false, err
@@ -750,7 +1497,7 @@ func (c client) LoadAndDelete(ctx context.Context, obj any, key any, predicates
 
 	dgClient, err := c.pool.get()
 	if err != nil {
-		c.logger.Error(err, "Failed to get client from pool")
+		c.log(ctx).Error(err, "Failed to get client from pool")
 		return false, err
 	}
 	defer c.pool.put(dgClient)
@@ -774,9 +1521,8 @@ func (c client) LoadAndDelete(ctx context.Context, obj any, key any, predicates
 	const maxAttempts = 10
 	for attempt := 0; ; attempt++ {
 		tx := dg.NewTxnContext(ctx, dgClient)
-		getErr := tx.Get(obj).
-			Filter("eq("+pred+", $1)", key).
-			All(c.options.maxEdgeTraversal).
+		getErr := expand(tx.Get(obj).Filter("eq("+pred+", $1)", key),
+			obj, c.options.maxEdgeTraversal, c.options.maxEdgeFanout).
 			Node()
 		if getErr != nil {
 			_ = tx.Discard()
@@ -829,38 +1575,86 @@ func isAbortedErr(err error) bool {
 // Update implements updating an existing object in the database.
 // Passed object must be a pointer to a struct.
 func (c client) Update(ctx context.Context, obj any) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 	obj = UnwrapSchema(obj)
+	if err := c.applyComputed(ctx, obj); err != nil {
+		return err
+	}
+	NormalizeTimes(c, obj)
 	// Validate struct before update
 	if err := c.validateStruct(ctx, obj); err != nil {
 		return err
 	}
 
-	return c.process(ctx, obj, "Update", func(tx *dg.TxnContext, obj any) ([]string, error) {
-		return tx.MutateBasic(obj)
+	return c.lockedWrite(ctx, obj, func() error {
+		return c.process(ctx, obj, "Update", func(tx *dg.TxnContext, obj any) ([]string, error) {
+			defer c.lockUniqueGroups(obj)()
+			if err := c.checkUniqueGroups(ctx, tx, obj, ""); err != nil {
+				return nil, err
+			}
+			if err := c.checkVersions(ctx, tx, obj, ""); err != nil {
+				return nil, err
+			}
+			return tx.MutateBasic(obj)
+		})
 	})
 }
 
-// Delete implements removing objects with the specified UIDs.
-func (c client) Delete(ctx context.Context, uids []string) error {
+// Delete implements removing nodes by UID or by struct.
+func (c client) Delete(ctx context.Context, obj any, opts ...DeleteOpt) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	var o deleteOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	uids, err := deleteTargets(obj, o.cascade)
+	if err != nil {
+		return err
+	}
+	if err := checkUIDs(uids...); err != nil {
+		return err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 	client, err := c.pool.get()
 	if err != nil {
-		c.logger.Error(err, "Failed to get client from pool")
+		c.log(ctx).Error(err, "Failed to get client from pool")
 		return err
 	}
 	defer c.pool.put(client)
 
+	if _, ok := obj.([]string); !ok {
+		c.policies.register(obj)
+	}
+	if !o.purge && len(c.policies.softDeletePredicates()) > 0 {
+		return c.deleteSoft(ctx, client, uids, o)
+	}
+	if rules := c.policies.deleteRules(); len(rules) > 0 || o.detach {
+		return c.deleteWithRules(ctx, client, uids, rules, o.detach)
+	}
 	txn := dg.NewTxnContext(ctx, client).SetCommitNow()
 	return txn.DeleteNode(uids...)
 }
 
 // Get implements retrieving a single object by its UID.
 // Passed object must be a pointer to a struct.
-func (c client) Get(ctx context.Context, obj any, uid string) error {
+func (c client) Get(ctx context.Context, obj any, uid string, opts ...GetOpt) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 	obj = UnwrapSchema(obj)
 	err := checkPointer(obj)
 	if err != nil {
 		return err
 	}
+	if err := checkUIDs(uid); err != nil {
+		return err
+	}
 
 	client, err := c.pool.get()
 	if err != nil {
@@ -869,11 +1663,15 @@ func (c client) Get(ctx context.Context, obj any, uid string) error {
 	defer c.pool.put(client)
 
 	txn := dg.NewReadOnlyTxnContext(ctx, client)
-	return txn.Get(obj).UID(uid).All(c.options.maxEdgeTraversal).Node()
+	if err := getNode(txn, obj, uid, c.getOptions(opts)); err != nil {
+		return err
+	}
+	NormalizeTimes(c, obj)
+	return LoadLocalized(ctx, c, obj)
 }
 
 // Returns a *dg.Query that can be further refined with filters, pagination, etc.
-// The returned query will be limited to the maximum number of edges specified in the options.
+// The returned query will be limited to the maximum edge depth and fan-out specified in the options.
 func (c client) Query(ctx context.Context, model any) *dg.Query {
 	model = UnwrapSchema(model)
 	client, err := c.pool.get()
@@ -883,15 +1681,20 @@ func (c client) Query(ctx context.Context, model any) *dg.Query {
 	defer c.pool.put(client)
 
 	txn := dg.NewReadOnlyTxnContext(ctx, client)
-	return txn.Get(model).All(c.options.maxEdgeTraversal)
+	return expand(txn.Get(model), model, c.options.maxEdgeTraversal, c.options.maxEdgeFanout)
 }
 
 // AlterSchema applies a raw DQL schema string directly via Dgraph Alter,
 // without the object-template inference performed by UpdateSchema.
 func (c client) AlterSchema(ctx context.Context, schema string) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 	dgClient, err := c.pool.get()
 	if err != nil {
-		c.logger.Error(err, "Failed to get client from pool")
+		c.log(ctx).Error(err, "Failed to get client from pool")
 		return err
 	}
 	defer c.pool.put(dgClient)
@@ -904,17 +1707,23 @@ func (c client) AlterSchema(ctx context.Context, schema string) error {
 // If any object contains SimString fields tagged `dgraph:"embedding"`, the
 // corresponding shadow float32vector predicates (<field>__vec) are also registered.
 func (c client) UpdateSchema(ctx context.Context, obj ...any) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 	for i := range obj {
 		obj[i] = UnwrapSchema(obj[i])
 	}
+	c.policies.register(obj...)
 	dgClient, err := c.pool.get()
 	if err != nil {
-		c.logger.Error(err, "Failed to get client from pool")
+		c.log(ctx).Error(err, "Failed to get client from pool")
 		return err
 	}
 	defer c.pool.put(dgClient)
 
-	if _, err = dg.CreateSchema(dgClient, obj...); err != nil {
+	if err = createSchema(ctx, dgClient, obj...); err != nil {
 		return err
 	}
 
@@ -933,23 +1742,16 @@ func (c client) UpdateSchema(ctx context.Context, obj ...any) error {
 	return dgClient.Alter(ctx, &api.Operation{Schema: vecSchema.String()})
 }
 
-// GetSchema implements retrieving the Dgraph schema.
-func (c client) GetSchema(ctx context.Context) (string, error) {
-	client, err := c.pool.get()
-	if err != nil {
-		c.logger.Error(err, "Failed to get client from pool")
-		return "", err
-	}
-	defer c.pool.put(client)
-
-	return dg.GetSchema(client)
-}
-
 // DropAll implements dropping all data and schema from the database.
 func (c client) DropAll(ctx context.Context) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 	client, err := c.pool.get()
 	if err != nil {
-		c.logger.Error(err, "Failed to get client from pool")
+		c.log(ctx).Error(err, "Failed to get client from pool")
 		return err
 	}
 	defer c.pool.put(client)
@@ -959,9 +1761,14 @@ func (c client) DropAll(ctx context.Context) error {
 
 // DropData implements dropping data from the database.
 func (c client) DropData(ctx context.Context) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 	client, err := c.pool.get()
 	if err != nil {
-		c.logger.Error(err, "Failed to get client from pool")
+		c.log(ctx).Error(err, "Failed to get client from pool")
 		return err
 	}
 	defer c.pool.put(client)
@@ -971,9 +1778,11 @@ func (c client) DropData(ctx context.Context) error {
 
 // QueryRaw implements raw querying (DQL syntax) and optional variables.
 func (c client) QueryRaw(ctx context.Context, q string, vars map[string]string) ([]byte, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 	client, err := c.pool.get()
 	if err != nil {
-		c.logger.Error(err, "Failed to get client from pool")
+		c.log(ctx).Error(err, "Failed to get client from pool")
 		return nil, err
 	}
 	defer c.pool.put(client)
@@ -986,15 +1795,35 @@ func (c client) QueryRaw(ctx context.Context, q string, vars map[string]string)
 	return resp.GetJson(), nil
 }
 
+// Now returns the current time by the client's clock.
+func (c client) Now() time.Time {
+	return c.options.clock()()
+}
+
+// clock returns the clock set with WithClock, or time.Now.
+func (o clientOptions) clock() func() time.Time {
+	if o.now == nil {
+		return time.Now
+	}
+	return o.now
+}
+
 // Close releases resources used by the client.
 func (c client) Close() {
+	if c.derived {
+		return
+	}
 	// Add nil check to prevent panic if pool is nil
 	if c.pool != nil {
 		c.pool.close()
 	}
+	if c.endpoints != nil {
+		c.endpoints.close()
+	}
 	if c.engine != nil {
 		c.engine.Close()
 	}
+	c.changes.release()
 }
 
 // DgraphClient returns a Dgraph client from the pool and a cleanup function to put it back.
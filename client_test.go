@@ -15,6 +15,7 @@ import (
 	"time"
 
 	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/modusgraphtest"
 	"github.com/stretchr/testify/require"
 )
 
@@ -453,3 +454,30 @@ func TestClientInvalidNamespace(t *testing.T) {
 		})
 	}
 }
+
+func TestWithClock(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := modusgraphtest.NewClock(start)
+	client, err := mg.NewClient("file://"+t.TempDir(), mg.WithAutoSchema(true),
+		mg.WithChangelog(t.TempDir()), mg.WithClock(clock.Now))
+	require.NoError(t, err)
+	defer client.Close()
+	ctx := context.Background()
+	require.Equal(t, start, client.Now())
+
+	note := &SoftNote{Title: "clocked"}
+	require.NoError(t, client.Insert(ctx, note))
+	deletedAt := clock.Advance(time.Hour)
+	require.NoError(t, client.Delete(ctx, note))
+
+	got, err := mg.Get[SoftNote](ctx, client, note.UID)
+	require.NoError(t, err)
+	require.NotNil(t, got.DeletedAt)
+	require.True(t, got.DeletedAt.Equal(deletedAt), "soft deletes are stamped by the clock, got %v", got.DeletedAt)
+
+	events, err := client.ReadChangelog(ctx, 0, 0)
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	require.Equal(t, start, events[0].CommittedAt)
+	require.Equal(t, deletedAt, events[len(events)-1].CommittedAt)
+}
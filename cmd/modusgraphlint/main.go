@@ -0,0 +1,20 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Command modusgraphlint checks modusGraph entity structs for missing UID
+// and DType fields, conflicting predicates, invalid indexes, unpaired or
+// mismatched reverse edges, colliding predicate= tags and field types
+// Dgraph cannot hold. With -reversefields -fix it adds the reverse fields
+// forward edges imply. See package lint for the checks.
+package main
+
+import (
+	"github.com/matthewmcneely/modusgraph/lint"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(lint.Analyzer)
+}
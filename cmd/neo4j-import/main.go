@@ -0,0 +1,138 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/stdr"
+	"github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/neo4jimport"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func main() {
+	// Define flags
+	dirFlag := flag.String("dir", "", "Directory where the modusGraph database is stored")
+	csvFlag := flag.String("csv", "", "Neo4j export file written by apoc.export.csv.all")
+	boltFlag := flag.String("bolt", "", "Bolt URI of a Neo4j database to read, e.g. neo4j://localhost:7687")
+	userFlag := flag.String("user", "neo4j", "Neo4j user name for --bolt")
+	passwordFlag := flag.String("password", "", "Neo4j password for --bolt (default $NEO4J_PASSWORD)")
+	dbFlag := flag.String("db", "", "Neo4j database name for --bolt (default: the server's default)")
+	stubsFlag := flag.String("stubs", "", "Write generated Go entity structs to this file")
+	pkgFlag := flag.String("package", "models", "Package name for the generated entity structs")
+	idFlag := flag.String("id-predicate", "", "Store each node's Neo4j ID under this predicate")
+	batchFlag := flag.Int("batch", 1000, "Nodes or relationships written per transaction")
+	timeoutFlag := flag.Duration("timeout", 30*time.Minute, "Import timeout duration")
+	flag.Parse()
+
+	// Initialize the stdr logger with the verbosity from -v
+	stdLogger := log.New(os.Stdout, "", log.LstdFlags)
+	logger := stdr.NewWithOptions(stdLogger, stdr.Options{LogCaller: stdr.All}).WithName("mg")
+	vFlag := flag.Lookup("v")
+	if vFlag != nil {
+		val, err := strconv.Atoi(vFlag.Value.String())
+		if err != nil {
+			log.Fatalf("Error: Invalid verbosity level: %s", vFlag.Value.String())
+		}
+		stdr.SetVerbosity(val)
+	}
+
+	// Validate required flags
+	if *dirFlag == "" {
+		log.Println("Error: --dir parameter is required")
+		flag.Usage()
+		os.Exit(1)
+	}
+	if (*csvFlag == "") == (*boltFlag == "") {
+		log.Println("Error: exactly one of --csv or --bolt is required")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeoutFlag)
+	defer cancel()
+
+	// Read the Neo4j graph
+	var g *neo4jimport.Graph
+	var err error
+	if *csvFlag != "" {
+		logger.V(1).Info("Reading Neo4j CSV export", "file", *csvFlag)
+		f, ferr := os.Open(*csvFlag)
+		if ferr != nil {
+			logger.Error(ferr, "Failed to open CSV export")
+			os.Exit(1)
+		}
+		g, err = neo4jimport.ReadCSV(f)
+		f.Close()
+	} else {
+		password := *passwordFlag
+		if password == "" {
+			password = os.Getenv("NEO4J_PASSWORD")
+		}
+		logger.V(1).Info("Reading Neo4j database", "uri", *boltFlag, "database", *dbFlag)
+		driver, derr := neo4j.NewDriverWithContext(*boltFlag, neo4j.BasicAuth(*userFlag, password, ""))
+		if derr != nil {
+			logger.Error(derr, "Failed to create Neo4j driver")
+			os.Exit(1)
+		}
+		g, err = neo4jimport.ReadBolt(ctx, driver, *dbFlag)
+		driver.Close(ctx)
+	}
+	if err != nil {
+		logger.Error(err, "Failed to read Neo4j graph")
+		os.Exit(1)
+	}
+	logger.Info("Read Neo4j graph", "nodes", len(g.Nodes), "relationships", len(g.Relationships))
+
+	opts := neo4jimport.Options{BatchSize: *batchFlag, IDPredicate: *idFlag}
+
+	// Write the entity stubs first so a failed import still leaves them behind
+	if *stubsFlag != "" {
+		src, err := neo4jimport.Stubs(g, *pkgFlag, opts)
+		if err != nil {
+			logger.Error(err, "Failed to generate entity stubs")
+			os.Exit(1)
+		}
+		if err := os.WriteFile(*stubsFlag, src, 0o644); err != nil {
+			logger.Error(err, "Failed to write entity stubs")
+			os.Exit(1)
+		}
+		logger.Info("Wrote entity stubs", "file", *stubsFlag)
+	}
+
+	// Initialize modusGraph client with the directory where data is stored
+	dirPath := filepath.Clean(*dirFlag)
+	if err := os.MkdirAll(dirPath, 0o755); err != nil {
+		logger.Error(err, "Failed to create directory")
+		os.Exit(1)
+	}
+	logger.V(1).Info("Initializing modusGraph client", "directory", dirPath)
+	client, err := modusgraph.NewClient(fmt.Sprintf("file://%s", dirPath),
+		modusgraph.WithLogger(logger))
+	if err != nil {
+		logger.Error(err, "Failed to initialize modusGraph client")
+		os.Exit(1)
+	}
+	defer client.Close()
+
+	start := time.Now()
+	res, err := neo4jimport.Import(ctx, client, g, opts)
+	if err != nil {
+		logger.Error(err, "Import failed")
+		os.Exit(1)
+	}
+	elapsed := time.Since(start)
+	logger.Info("Import completed", "nodes", res.Nodes, "relationships", res.Relationships,
+		"elapsed_ms", float64(elapsed.Nanoseconds())/1e6)
+}
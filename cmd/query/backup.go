@@ -0,0 +1,41 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/matthewmcneely/modusgraph"
+)
+
+// backupOrRestore writes a backup of the database to the file named backup,
+// or restores the file named restore into it. Exactly one of them is set.
+func backupOrRestore(ctx context.Context, client modusgraph.Client, backup, restore string) error {
+	if backup != "" && restore != "" {
+		return errors.New("--backup and --restore cannot be combined")
+	}
+	if restore != "" {
+		f, err := os.Open(restore)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return client.Restore(ctx, f)
+	}
+
+	f, err := os.Create(backup)
+	if err != nil {
+		return err
+	}
+	if err := client.Backup(ctx, f); err != nil {
+		f.Close()
+		os.Remove(backup)
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,51 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/matthewmcneely/modusgraph"
+)
+
+// deleteNodes deletes the comma-separated UIDs in list, with their edges
+// from other nodes when detach is set, once the user confirms on in.
+func deleteNodes(ctx context.Context, client modusgraph.Client, list string, detach bool,
+	in io.Reader, out io.Writer) error {
+	var uids []string
+	for _, s := range strings.Split(list, ",") {
+		uid, err := modusgraph.ParseUID(strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+		uids = append(uids, uid.String())
+	}
+
+	what := "node(s)"
+	if detach {
+		what = "node(s) and the edges pointing to them"
+	}
+	fmt.Fprintf(out, "Delete %d %s: %s? [y/N] ", len(uids), what, strings.Join(uids, ", "))
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+		fmt.Fprintln(out, "Kept.")
+		return nil
+	}
+
+	var opts []modusgraph.DeleteOpt
+	if detach {
+		opts = append(opts, modusgraph.DeleteDetach())
+	}
+	if err := client.Delete(ctx, uids, opts...); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Deleted %d node(s).\n", len(uids))
+	return nil
+}
@@ -0,0 +1,227 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/matthewmcneely/modusgraph"
+)
+
+// editNode fetches node uid as JSON, opens it in $EDITOR, shows the fields
+// changed and, once the user confirms on in, writes only those.
+func editNode(ctx context.Context, client modusgraph.Client, node string, in io.Reader, out io.Writer) error {
+	parsed, err := modusgraph.ParseUID(node)
+	if err != nil {
+		return err
+	}
+	uid := string(parsed)
+	before, err := fetchNode(ctx, client, uid)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(before, "", "  ")
+	if err != nil {
+		return err
+	}
+	edited, err := runEditor(append(data, '\n'))
+	if err != nil {
+		return err
+	}
+	var after map[string]any
+	dec := json.NewDecoder(bytes.NewReader(edited))
+	dec.UseNumber()
+	if err := dec.Decode(&after); err != nil {
+		return fmt.Errorf("edited node is not a JSON object: %w", err)
+	}
+	if fmt.Sprint(after["uid"]) != uid {
+		return errors.New("the uid of a node cannot be edited")
+	}
+
+	set, del := diffNode(before, after)
+	if len(set) == 0 && len(del) == 0 {
+		fmt.Fprintln(out, "No changes.")
+		return nil
+	}
+	keys := make([]string, 0, len(set)+len(del))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	for _, k := range del {
+		if _, ok := set[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	secret, err := passwordFields(ctx, client, keys)
+	if err != nil {
+		return err
+	}
+	if len(secret) > 0 {
+		return fmt.Errorf("%s cannot be edited as JSON; use --set-password", strings.Join(secret, ", "))
+	}
+	for _, k := range keys {
+		if old, ok := before[k]; ok {
+			fmt.Fprintf(out, "- %s: %s\n", k, compactJSON(old))
+		}
+		if v, ok := set[k]; ok {
+			fmt.Fprintf(out, "+ %s: %s\n", k, compactJSON(v))
+		}
+	}
+	fmt.Fprint(out, "Apply these changes? [y/N] ")
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+		fmt.Fprintln(out, "Discarded.")
+		return nil
+	}
+	if err := applyNodeEdit(ctx, client, uid, set, del); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Updated %d field(s) of %s.\n", len(keys), uid)
+	return nil
+}
+
+// fetchNode reads the predicates of node uid declared by its types, with
+// edges as their target UIDs.
+func fetchNode(ctx context.Context, client modusgraph.Client, uid string) (map[string]any, error) {
+	q := fmt.Sprintf(`{ n(func: uid(%s)) @filter(has(dgraph.type)) { uid dgraph.type expand(_all_) { uid } } }`, uid)
+	resp, err := client.QueryRaw(ctx, q, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		N []map[string]any `json:"n"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(resp))
+	dec.UseNumber()
+	if err := dec.Decode(&result); err != nil {
+		return nil, err
+	}
+	if len(result.N) == 0 {
+		return nil, fmt.Errorf("node %s not found or has no dgraph.type", uid)
+	}
+	return result.N[0], nil
+}
+
+// runEditor writes data to a temporary file, opens it in $EDITOR (vi when
+// unset) and returns the file's contents once the editor exits.
+func runEditor(data []byte) ([]byte, error) {
+	f, err := os.CreateTemp("", "modusgraph-edit-*.json")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], f.Name())...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("running %s: %w", editor, err)
+	}
+	return os.ReadFile(f.Name())
+}
+
+// diffNode returns the fields of after that differ from before, and the
+// fields of before that after drops or sets to null. A changed field appears
+// in both, so that its old values are replaced rather than added to.
+func diffNode(before, after map[string]any) (set map[string]any, del []string) {
+	set = map[string]any{}
+	for k, v := range after {
+		if k == "uid" || v == nil {
+			continue
+		}
+		if old, ok := before[k]; !ok || !reflect.DeepEqual(old, v) {
+			set[k] = v
+			if ok {
+				del = append(del, k)
+			}
+		}
+	}
+	for k := range before {
+		if k == "uid" {
+			continue
+		}
+		if v, ok := after[k]; !ok || v == nil {
+			del = append(del, k)
+		}
+	}
+	slices.Sort(del)
+	return set, del
+}
+
+// applyNodeEdit deletes the old values of del and then writes set, in one
+// request, so that an embedded store, which commits each request as it
+// applies it, does not keep the deletes of an edit whose sets fail.
+func applyNodeEdit(ctx context.Context, client modusgraph.Client, uid string, set map[string]any, del []string) error {
+	var mus []*api.Mutation
+	cleared := map[string]any{"uid": uid}
+	for _, k := range del {
+		// Setting a scalar replaces its value; deleting it in the same
+		// request would drop the new value on an embedded store.
+		if v, ok := set[k]; ok {
+			if _, list := v.([]any); !list {
+				continue
+			}
+		}
+		cleared[k] = nil
+	}
+	if len(cleared) > 1 {
+		data, err := json.Marshal(cleared)
+		if err != nil {
+			return err
+		}
+		mus = append(mus, &api.Mutation{DeleteJson: data})
+	}
+	if len(set) > 0 {
+		node := map[string]any{"uid": uid}
+		for k, v := range set {
+			node[k] = v
+		}
+		data, err := json.Marshal(node)
+		if err != nil {
+			return err
+		}
+		mus = append(mus, &api.Mutation{SetJson: data})
+	}
+	dg, cleanup, err := client.DgraphClient()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	_, err = dg.NewTxn().Do(ctx, &api.Request{Mutations: mus, CommitNow: true})
+	return err
+}
+
+// compactJSON renders v on one line for the change summary.
+func compactJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,154 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffNode(t *testing.T) {
+	before := map[string]any{
+		"uid":         "0x1",
+		"dgraph.type": []any{"Film"},
+		"title":       "Alien",
+		"year":        json.Number("1979"),
+		"genre":       []any{map[string]any{"uid": "0x2"}},
+	}
+	tests := []struct {
+		name    string
+		after   map[string]any
+		wantSet map[string]any
+		wantDel []string
+	}{
+		{
+			name: "unchanged",
+			after: map[string]any{
+				"uid": "0x1", "dgraph.type": []any{"Film"}, "title": "Alien", "year": json.Number("1979"),
+				"genre": []any{map[string]any{"uid": "0x2"}},
+			},
+			wantSet: map[string]any{},
+		},
+		{
+			name: "added",
+			after: map[string]any{
+				"uid": "0x1", "dgraph.type": []any{"Film"}, "title": "Alien", "year": json.Number("1979"),
+				"genre": []any{map[string]any{"uid": "0x2"}}, "rating": "R",
+			},
+			wantSet: map[string]any{"rating": "R"},
+		},
+		{
+			name: "removed",
+			after: map[string]any{
+				"uid": "0x1", "dgraph.type": []any{"Film"}, "title": "Alien",
+				"genre": []any{map[string]any{"uid": "0x2"}},
+			},
+			wantSet: map[string]any{},
+			wantDel: []string{"year"},
+		},
+		{
+			name: "set to null",
+			after: map[string]any{
+				"uid": "0x1", "dgraph.type": []any{"Film"}, "title": "Alien", "year": nil,
+				"genre": []any{map[string]any{"uid": "0x2"}},
+			},
+			wantSet: map[string]any{},
+			wantDel: []string{"year"},
+		},
+		{
+			name: "changed",
+			after: map[string]any{
+				"uid": "0x1", "dgraph.type": []any{"Film"}, "title": "Aliens", "year": json.Number("1986"),
+				"genre": []any{map[string]any{"uid": "0x2"}, map[string]any{"uid": "0x3"}},
+			},
+			wantSet: map[string]any{
+				"title": "Aliens", "year": json.Number("1986"),
+				"genre": []any{map[string]any{"uid": "0x2"}, map[string]any{"uid": "0x3"}},
+			},
+			wantDel: []string{"genre", "title", "year"},
+		},
+		{
+			name: "uid ignored",
+			after: map[string]any{
+				"dgraph.type": []any{"Film"}, "title": "Alien", "year": json.Number("1979"),
+				"genre": []any{map[string]any{"uid": "0x2"}},
+			},
+			wantSet: map[string]any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, del := diffNode(before, tt.after)
+			require.Equal(t, tt.wantSet, set)
+			require.Equal(t, tt.wantDel, del)
+		})
+	}
+}
+
+func TestApplyNodeEdit(t *testing.T) {
+	ctx := context.Background()
+	client, err := modusgraph.NewClient("file://" + t.TempDir())
+	require.NoError(t, err)
+	defer client.Close()
+	schema := "title: string .\nyear: int .\ntags: [string] .\ntype Film {\n title\n year\n tags\n}"
+	require.NoError(t, client.AlterSchema(ctx, schema))
+	uids, _, err := client.MutateRDF(ctx, `_:film <dgraph.type> "Film" .
+_:film <title> "Alien" .
+_:film <year> "1979"^^<xs:int> .
+_:film <tags> "horror" .`, "")
+	require.NoError(t, err)
+	uid := uids["film"]
+
+	tests := []struct {
+		name string
+		uid  string
+		set  map[string]any
+		del  []string
+	}{
+		{
+			name: "value that cannot be encoded",
+			uid:  uid,
+			set:  map[string]any{"title": make(chan int)},
+			del:  []string{"title"},
+		},
+		{
+			name: "value the schema rejects",
+			uid:  uid,
+			set:  map[string]any{"year": "nineteen seventy-nine"},
+			del:  []string{"title", "year"},
+		},
+		{
+			name: "malformed uid",
+			uid:  "film",
+			set:  map[string]any{"title": "Aliens"},
+			del:  []string{"title"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Error(t, applyNodeEdit(ctx, client, tt.uid, tt.set, tt.del))
+
+			// The deletes are not committed without the sets.
+			got, err := fetchNode(ctx, client, uid)
+			require.NoError(t, err)
+			require.Equal(t, "Alien", got["title"])
+			require.Equal(t, json.Number("1979"), got["year"])
+		})
+	}
+
+	// A valid edit replaces the changed values and removes the dropped ones.
+	set := map[string]any{"title": "Aliens", "tags": []any{"action"}}
+	require.NoError(t, applyNodeEdit(ctx, client, uid, set, []string{"tags", "title", "year"}))
+	got, err := fetchNode(ctx, client, uid)
+	require.NoError(t, err)
+	require.Equal(t, "Aliens", got["title"])
+	require.Equal(t, []any{"action"}, got["tags"])
+	require.NotContains(t, got, "year")
+}
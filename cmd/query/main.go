@@ -6,14 +6,13 @@
 package main
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
-	"io"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -23,11 +22,63 @@ import (
 	"github.com/matthewmcneely/modusgraph"
 )
 
+// varsFlag collects repeated --var name=value flags.
+type varsFlag map[string]string
+
+func (v varsFlag) String() string {
+	pairs := make([]string, 0, len(v))
+	for name, value := range v {
+		pairs = append(pairs, name+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (v varsFlag) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("expected name=value, got %q", s)
+	}
+	v[name] = value
+	return nil
+}
+
 func main() {
 	// Define flags
 	dirFlag := flag.String("dir", "", "Directory where the modusGraph database is stored")
+	addrFlag := flag.String("addr", "", "Connect to the Dgraph cluster at this host:port instead of a --dir database")
 	prettyFlag := flag.Bool("pretty", true, "Pretty-print the JSON output")
 	timeoutFlag := flag.Duration("timeout", 30*time.Second, "Query timeout duration")
+	schemaFlag := flag.Bool("schema", false, "Print the database schema in .schema format instead of running a query")
+	doctorFlag := flag.Bool("doctor", false,
+		"Check the database for dangling edges, untyped nodes, duplicate unique values and one-sided reverse edges "+
+			"instead of running a query")
+	repairFlag := flag.Bool("repair", false, "With --doctor, repair the problems that can be fixed automatically")
+	fsckFlag := flag.Bool("fsck", false,
+		"Check the storage and indexes of a database no process has open instead of running a query")
+	fileFlag := flag.String("file", "", "Read the query from this file instead of standard input")
+	editFlag := flag.String("edit", "",
+		"Open the node with this UID in $EDITOR as JSON and write back the fields changed, after confirmation")
+	setPasswordFlag := flag.String("set-password", "",
+		"Prompt without echo for a new password of the node with this UID and store it hashed")
+	predicateFlag := flag.String("predicate", "password", "With --set-password, the password predicate to set")
+	deleteFlag := flag.String("delete", "", "Delete the nodes with these comma-separated UIDs, after confirmation")
+	detachFlag := flag.Bool("detach", false, "With --delete, also remove the edges of other nodes that point to them")
+	migrateFlag := flag.String("migrate", "",
+		"Apply the pending migration scripts in this directory instead of running a query")
+	rollbackFlag := flag.Int64("rollback", -1, "With --migrate, revert the applied migrations above this version instead")
+	exportFlag := flag.String("export", "",
+		"Write the nodes of these comma-separated types, or * for every type, to standard output as JSON Lines")
+	backupFlag := flag.String("backup", "",
+		"Write a compressed backup of the database to this file instead of running a query")
+	restoreFlag := flag.String("restore", "",
+		"Restore the backup or dump in this file into the database instead of running a query")
+	watchFlag := flag.String("watch", "",
+		"Print the changes to nodes of this type as JSON Lines until interrupted instead of running a query")
+	vars := varsFlag{}
+	flag.Var(vars, "var", "Bind a query variable as name=value; repeat for several")
+	where := varsFlag{}
+	flag.Var(where, "where",
+		"With --watch, only report nodes whose indexed predicate has this value, as predicate=value; repeat for several")
 	flag.Parse()
 
 	// Initialize the stdr logger with the verbosity from -v
@@ -43,43 +94,156 @@ func main() {
 	}
 
 	// Validate required flags
-	if *dirFlag == "" {
-		log.Println("Error: --dir parameter is required")
+	if (*dirFlag == "") == (*addrFlag == "") {
+		log.Println("Error: exactly one of --dir and --addr is required")
 		flag.Usage()
 		os.Exit(1)
 	}
 
 	// Create clean directory path
 	dirPath := filepath.Clean(*dirFlag)
-	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
-		log.Fatalf("Error: Directory %s does not exist", dirPath)
+	uri := "dgraph://" + *addrFlag
+	if *dirFlag != "" {
+		if _, err := os.Stat(dirPath); os.IsNotExist(err) {
+			log.Fatalf("Error: Directory %s does not exist", dirPath)
+		}
+		uri = "file://" + dirPath
+	}
+
+	// Fsck opens the directory itself, so it runs before the client does
+	if *fsckFlag {
+		if *dirFlag == "" {
+			log.Fatal("Error: --fsck needs --dir")
+		}
+		report, err := modusgraph.Fsck(dirPath, modusgraph.FsckOptions{Logger: logger})
+		if err != nil {
+			logger.Error(err, "Consistency check failed")
+			os.Exit(1)
+		}
+		fmt.Print(report)
+		if !report.Empty() {
+			os.Exit(2)
+		}
+		return
 	}
 
 	// Initialize modusGraph client with the directory where data is stored
-	logger.V(1).Info("Initializing modusGraph client", "directory", dirPath)
-	client, err := modusgraph.NewClient(fmt.Sprintf("file://%s", dirPath),
-		modusgraph.WithLogger(logger))
+	logger.V(1).Info("Initializing modusGraph client", "uri", uri)
+	client, err := modusgraph.NewClient(uri, modusgraph.WithLogger(logger))
 	if err != nil {
 		logger.Error(err, "Failed to initialize modusGraph client")
 		os.Exit(1)
 	}
 	defer client.Close()
 
-	// Read query from stdin
-	reader := bufio.NewReader(os.Stdin)
-	query := ""
-	for {
-		line, err := reader.ReadString('\n')
-		if err != nil && err != io.EOF {
-			logger.Error(err, "Error reading from stdin")
+	if *schemaFlag {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeoutFlag)
+		defer cancel()
+		if err := client.ExportSchema(ctx, os.Stdout); err != nil {
+			logger.Error(err, "Schema export failed")
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *exportFlag != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeoutFlag)
+		defer cancel()
+		var types []string
+		if *exportFlag != "*" {
+			for _, t := range strings.Split(*exportFlag, ",") {
+				types = append(types, strings.TrimSpace(t))
+			}
+		}
+		if err := client.Export(ctx, os.Stdout, modusgraph.ExportOptions{Types: types}); err != nil {
+			logger.Error(err, "Export failed")
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *watchFlag != "" {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if err := watchType(ctx, client, *watchFlag, where, os.Stdout); err != nil {
+			logger.Error(err, "Watch failed")
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *backupFlag != "" || *restoreFlag != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeoutFlag)
+		defer cancel()
+		if err := backupOrRestore(ctx, client, *backupFlag, *restoreFlag); err != nil {
+			logger.Error(err, "Backup or restore failed")
 			os.Exit(1)
 		}
+		return
+	}
+
+	if *doctorFlag {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeoutFlag)
+		defer cancel()
+		report, err := client.CheckIntegrity(ctx, modusgraph.IntegrityOptions{Repair: *repairFlag})
+		if err != nil {
+			logger.Error(err, "Integrity check failed")
+			os.Exit(1)
+		}
+		fmt.Print(report)
+		if !report.Empty() && !*repairFlag {
+			os.Exit(2)
+		}
+		return
+	}
 
-		query += line
+	if *migrateFlag != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeoutFlag)
+		defer cancel()
+		if err := runMigrations(ctx, client, *migrateFlag, *rollbackFlag, os.Stdout); err != nil {
+			logger.Error(err, "Migration failed")
+			os.Exit(1)
+		}
+		return
+	}
 
-		if err == io.EOF {
-			break
+	if *editFlag != "" {
+		if err := editNode(context.Background(), client, *editFlag, os.Stdin, os.Stdout); err != nil {
+			logger.Error(err, "Edit failed")
+			os.Exit(1)
 		}
+		return
+	}
+
+	if *deleteFlag != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeoutFlag)
+		defer cancel()
+		if err := deleteNodes(ctx, client, *deleteFlag, *detachFlag, os.Stdin, os.Stdout); err != nil {
+			logger.Error(err, "Delete failed")
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *setPasswordFlag != "" {
+		err := setPassword(context.Background(), client, *setPasswordFlag, *predicateFlag, os.Stdin, os.Stderr)
+		if err != nil {
+			logger.Error(err, "Setting password failed")
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Read the query from --file or stdin, expanding #include directives
+	var query string
+	if *fileFlag != "" {
+		query, err = modusgraph.ReadQueryFile(*fileFlag)
+	} else {
+		query, err = modusgraph.ReadQuery(os.Stdin, ".")
+	}
+	if err != nil {
+		logger.Error(err, "Error reading query")
+		os.Exit(1)
 	}
 
 	query = strings.TrimSpace(query)
@@ -97,7 +261,7 @@ func main() {
 	start := time.Now()
 
 	// Execute the query
-	resp, err := client.QueryRaw(ctx, query, nil)
+	resp, err := client.QueryRaw(ctx, query, modusgraph.QueryVars(vars))
 	if err != nil {
 		logger.Error(err, "Query execution failed")
 		os.Exit(1)
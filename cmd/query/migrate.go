@@ -0,0 +1,49 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/migrate"
+)
+
+// runMigrations applies the pending migration scripts in dir, or, when
+// rollback is 0 or more, reverts the applied migrations above that version.
+// It prints each version applied or reverted to out.
+func runMigrations(ctx context.Context, client modusgraph.Client, dir string, rollback int64, out io.Writer) error {
+	migrations, err := migrate.LoadDir(os.DirFS(dir))
+	if err != nil {
+		return err
+	}
+	r := migrate.New(client, migrate.Options{})
+	if err := r.Register(migrations...); err != nil {
+		return err
+	}
+
+	verb := "Applied"
+	var versions []int64
+	if rollback >= 0 {
+		verb = "Reverted"
+		versions, err = r.Rollback(ctx, rollback)
+	} else {
+		versions, err = r.Run(ctx)
+	}
+	for _, v := range versions {
+		fmt.Fprintf(out, "%s migration %d\n", verb, v)
+	}
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		fmt.Fprintln(out, "Nothing to do.")
+	}
+	return nil
+}
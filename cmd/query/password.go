@@ -0,0 +1,83 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/matthewmcneely/modusgraph"
+	"golang.org/x/term"
+)
+
+// setPassword prompts for a new value of the password predicate of node and
+// stores it. On a terminal the value is read twice without echo; otherwise
+// it is the first line of in, so scripts can pipe it in.
+func setPassword(ctx context.Context, client modusgraph.Client, node, predicate string,
+	in *os.File, out io.Writer) error {
+	uid, err := modusgraph.ParseUID(node)
+	if err != nil {
+		return err
+	}
+	var password string
+	if term.IsTerminal(int(in.Fd())) {
+		password, err = promptPassword(in, out, fmt.Sprintf("New %s for %s: ", predicate, uid))
+		if err != nil {
+			return err
+		}
+		again, err := promptPassword(in, out, "Repeat it: ")
+		if err != nil {
+			return err
+		}
+		if again != password {
+			return errors.New("the values entered differ")
+		}
+	} else {
+		line, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+	if password == "" {
+		return errors.New("no password given")
+	}
+	if err := client.SetPassword(ctx, string(uid), predicate, password); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Updated %s of %s.\n", predicate, uid)
+	return nil
+}
+
+// promptPassword writes prompt to out and reads a line from the terminal in
+// without echoing it.
+func promptPassword(in *os.File, out io.Writer, prompt string) (string, error) {
+	fmt.Fprint(out, prompt)
+	data, err := term.ReadPassword(int(in.Fd()))
+	fmt.Fprintln(out)
+	return string(data), err
+}
+
+// passwordFields returns the password predicates among fields, which --edit
+// refuses to write: the editor's file would hold the new value in plaintext.
+func passwordFields(ctx context.Context, client modusgraph.Client, fields []string) ([]string, error) {
+	schema, err := client.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var found []string
+	for _, f := range fields {
+		if p, ok := schema.Predicate(f); ok && p.Type == "password" {
+			found = append(found, f)
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,123 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/matthewmcneely/modusgraph"
+)
+
+// watchType writes the changes to nodes of type typ to w as JSON Lines until
+// ctx is done. With where, only nodes whose indexed predicates hold the given
+// values are reported, and deletes only of nodes that matched before.
+func watchType(ctx context.Context, client modusgraph.Client, typ string, where map[string]string, w io.Writer) error {
+	filter, vars, err := whereFilter(ctx, client, where)
+	if err != nil {
+		return err
+	}
+	matched := make(map[string]bool)
+	if filter != "" {
+		uids, err := matchingUIDs(ctx, client, fmt.Sprintf("type(%s)", typ), filter, vars)
+		if err != nil {
+			return err
+		}
+		for _, uid := range uids {
+			matched[uid] = true
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	for ev, err := range client.Watch(ctx, typ) {
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+		}
+		if filter != "" {
+			if ev.Op == modusgraph.ChangeDelete {
+				if !matched[ev.UID] {
+					continue
+				}
+				delete(matched, ev.UID)
+			} else {
+				uids, err := matchingUIDs(ctx, client, fmt.Sprintf("uid(%s)", ev.UID), filter, vars)
+				if err != nil {
+					return err
+				}
+				if len(uids) == 0 {
+					delete(matched, ev.UID)
+					continue
+				}
+				matched[ev.UID] = true
+			}
+		}
+		if err := enc.Encode(ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// whereFilter returns the DQL filter matching the predicate values of where,
+// and its variables. Each predicate must be indexed.
+func whereFilter(ctx context.Context, client modusgraph.Client,
+	where map[string]string) (string, map[string]string, error) {
+	if len(where) == 0 {
+		return "", nil, nil
+	}
+	schema, err := client.GetSchema(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	var terms []string
+	vars := make(map[string]string, len(where))
+	for i, pred := range slices.Sorted(maps.Keys(where)) {
+		j := slices.IndexFunc(schema.Predicates, func(p modusgraph.PredicateInfo) bool { return p.Name == pred })
+		if j < 0 || len(schema.Predicates[j].Indexes) == 0 {
+			return "", nil, fmt.Errorf("--where: predicate %q is not indexed", pred)
+		}
+		name := fmt.Sprintf("$w%d", i)
+		terms = append(terms, fmt.Sprintf("eq(<%s>, %s)", pred, name))
+		vars[name] = where[pred]
+	}
+	return strings.Join(terms, " AND "), vars, nil
+}
+
+// matchingUIDs returns the UIDs of the nodes root selects that pass filter.
+func matchingUIDs(ctx context.Context, client modusgraph.Client, root, filter string,
+	vars map[string]string) ([]string, error) {
+	decls := make([]string, 0, len(vars))
+	for _, name := range slices.Sorted(maps.Keys(vars)) {
+		decls = append(decls, name+": string")
+	}
+	q := fmt.Sprintf("query q(%s) {\n  q(func: %s) @filter(%s) { uid }\n}", strings.Join(decls, ", "), root, filter)
+	data, err := client.QueryRaw(ctx, q, vars)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Q []struct {
+			UID string `json:"uid"`
+		} `json:"q"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	uids := make([]string, len(resp.Q))
+	for i, n := range resp.Q {
+		uids[i] = n.UID
+	}
+	return uids, nil
+}
@@ -0,0 +1,195 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-logr/stdr"
+	"github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/mcp"
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const usage = `Usage: serve <command> [flags]
+
+Commands:
+  mcp    Serve the graph to LLM agents over the Model Context Protocol
+
+Run "serve <command> -h" for a command's flags.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	switch os.Args[1] {
+	case "mcp":
+		serveMCP(os.Args[2:])
+	case "-h", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
+
+func serveMCP(args []string) {
+	// Define flags
+	fs := flag.NewFlagSet("mcp", flag.ExitOnError)
+	dirFlag := fs.String("dir", "", "Directory where the modusGraph database is stored")
+	addrFlag := fs.String("addr", "", "Hostname/port of a Dgraph cluster to serve instead of --dir")
+	httpFlag := fs.String("http", "", "Serve streamable HTTP on this address instead of stdio, e.g. :8080")
+	typesFlag := fs.String("types", "", "Comma-separated types to expose (default: all; disables run_query)")
+	maxResultsFlag := fs.Int("max-results", 50, "Most nodes search_entities returns")
+	maxDepthFlag := fs.Int("max-depth", 2, "Most edges get_node follows")
+	maxBytesFlag := fs.Int("max-response-bytes", 1<<20, "Largest tool result in bytes")
+	timeoutFlag := fs.Duration("timeout", 30*time.Second, "Timeout for each tool call")
+	noQueryFlag := fs.Bool("no-query", false, "Do not offer the run_query tool")
+	apiKeyFileFlag := fs.String("api-key-file", "",
+		"With --http, accept the API keys in this file, one \"key [actor]\" per line")
+	jwtSecretFileFlag := fs.String("jwt-secret-file", "",
+		"With --http, accept JSON Web Tokens signed with the HMAC secret in this file")
+	noAuthFlag := fs.Bool("no-auth", false, "With --http, serve without authentication")
+	vFlag := fs.Int("v", 0, "Verbosity level for logging")
+	_ = fs.Parse(args)
+
+	// Log to stderr: with the stdio transport, stdout carries the protocol.
+	stdLogger := log.New(os.Stderr, "", log.LstdFlags)
+	logger := stdr.NewWithOptions(stdLogger, stdr.Options{LogCaller: stdr.All}).WithName("mg")
+	stdr.SetVerbosity(*vFlag)
+
+	// Validate required flags - either dirFlag or addrFlag must be provided
+	if (*dirFlag == "") == (*addrFlag == "") {
+		stdLogger.Println("Error: exactly one of --dir or --addr is required")
+		fs.Usage()
+		os.Exit(1)
+	}
+	endpoint := fmt.Sprintf("dgraph://%s", *addrFlag)
+	if *dirFlag != "" {
+		dirPath := filepath.Clean(*dirFlag)
+		if _, err := os.Stat(dirPath); os.IsNotExist(err) {
+			stdLogger.Fatalf("Error: Directory %s does not exist", dirPath)
+		}
+		endpoint = fmt.Sprintf("file://%s", dirPath)
+	}
+
+	logger.V(1).Info("Initializing modusGraph client", "endpoint", endpoint)
+	client, err := modusgraph.NewClient(endpoint, modusgraph.WithLogger(logger))
+	if err != nil {
+		logger.Error(err, "Failed to initialize modusGraph client")
+		os.Exit(1)
+	}
+	defer client.Close()
+
+	opts := mcp.Options{
+		MaxResults:       *maxResultsFlag,
+		MaxDepth:         *maxDepthFlag,
+		MaxResponseBytes: *maxBytesFlag,
+		Timeout:          *timeoutFlag,
+		DisableQuery:     *noQueryFlag,
+	}
+	if *typesFlag != "" {
+		opts.Types = strings.Split(*typesFlag, ",")
+	}
+	server := mcp.NewServer(client, opts)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *httpFlag == "" {
+		logger.Info("Serving MCP over stdio")
+		if err := server.Run(ctx, &sdk.StdioTransport{}); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Error(err, "MCP server failed")
+			os.Exit(1)
+		}
+		return
+	}
+
+	var handler http.Handler = sdk.NewStreamableHTTPHandler(func(*http.Request) *sdk.Server { return server }, nil)
+	if !*noAuthFlag {
+		verify, err := verifier(*apiKeyFileFlag, *jwtSecretFileFlag)
+		if err != nil {
+			logger.Error(err, "Failed to configure authentication")
+			os.Exit(1)
+		}
+		if handler, err = mcp.NewHTTPHandler(server, verify); err != nil {
+			logger.Error(err, "Refusing to serve HTTP without authentication; "+
+				"pass --api-key-file, --jwt-secret-file or --no-auth")
+			os.Exit(1)
+		}
+	}
+	httpServer := &http.Server{
+		Addr:    *httpFlag,
+		Handler: handler,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+	logger.Info("Serving MCP over HTTP", "addr", *httpFlag)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error(err, "MCP server failed")
+		os.Exit(1)
+	}
+}
+
+// verifier returns the bearer token verifier the flags configure, or nil
+// when they configure none.
+func verifier(apiKeyFile, jwtSecretFile string) (auth.TokenVerifier, error) {
+	switch {
+	case apiKeyFile != "" && jwtSecretFile != "":
+		return nil, errors.New("pass only one of --api-key-file and --jwt-secret-file")
+	case jwtSecretFile != "":
+		secret, err := os.ReadFile(jwtSecretFile)
+		if err != nil {
+			return nil, err
+		}
+		secret = bytes.TrimSpace(secret)
+		if len(secret) == 0 {
+			return nil, fmt.Errorf("%s is empty", jwtSecretFile)
+		}
+		return mcp.JWT(secret), nil
+	case apiKeyFile != "":
+		data, err := os.ReadFile(apiKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		keys := map[string]string{}
+		for i, line := range strings.Split(string(data), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+				continue
+			}
+			actor := fmt.Sprintf("key%d", i+1)
+			if len(fields) > 1 {
+				actor = fields[1]
+			}
+			keys[fields[0]] = actor
+		}
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("%s holds no keys", apiKeyFile)
+		}
+		return mcp.APIKeys(keys), nil
+	}
+	return nil, nil
+}
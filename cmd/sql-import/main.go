@@ -0,0 +1,145 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/stdr"
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/sqlimport"
+	_ "modernc.org/sqlite"
+)
+
+// drivers maps each --driver value to its database/sql driver name and dialect.
+var drivers = map[string]struct {
+	name    string
+	dialect sqlimport.Dialect
+}{
+	"sqlite":   {"sqlite", sqlimport.SQLite},
+	"postgres": {"pgx", sqlimport.Postgres},
+	"mysql":    {"mysql", sqlimport.MySQL},
+}
+
+func main() {
+	// Define flags
+	dirFlag := flag.String("dir", "", "Directory where the modusGraph database is stored")
+	driverFlag := flag.String("driver", "sqlite", "Source database: sqlite, postgres, or mysql")
+	dsnFlag := flag.String("dsn", "", "Source database file (sqlite) or connection string")
+	tablesFlag := flag.String("tables", "", "Comma-separated tables to import (default: all)")
+	inferFlag := flag.Bool("infer-fks", false, "Treat undeclared <name>_id columns as foreign keys")
+	keepJoinsFlag := flag.Bool("keep-join-tables", false, "Import join tables as types instead of edges")
+	schemaFlag := flag.Bool("schema", false, "Print the introspected tables and keys instead of importing")
+	batchFlag := flag.Int("batch", 1000, "Rows or edges written per transaction")
+	timeoutFlag := flag.Duration("timeout", 30*time.Minute, "Import timeout duration")
+	flag.Parse()
+
+	// Initialize the stdr logger with the verbosity from -v
+	stdLogger := log.New(os.Stdout, "", log.LstdFlags)
+	logger := stdr.NewWithOptions(stdLogger, stdr.Options{LogCaller: stdr.All}).WithName("mg")
+	vFlag := flag.Lookup("v")
+	if vFlag != nil {
+		val, err := strconv.Atoi(vFlag.Value.String())
+		if err != nil {
+			log.Fatalf("Error: Invalid verbosity level: %s", vFlag.Value.String())
+		}
+		stdr.SetVerbosity(val)
+	}
+
+	// Validate required flags
+	driver, ok := drivers[*driverFlag]
+	if !ok {
+		log.Printf("Error: unknown --driver %q", *driverFlag)
+		flag.Usage()
+		os.Exit(1)
+	}
+	if *dsnFlag == "" {
+		log.Println("Error: --dsn parameter is required")
+		flag.Usage()
+		os.Exit(1)
+	}
+	if *dirFlag == "" && !*schemaFlag {
+		log.Println("Error: --dir parameter is required")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeoutFlag)
+	defer cancel()
+
+	// Read the source schema
+	db, err := sql.Open(driver.name, *dsnFlag)
+	if err != nil {
+		logger.Error(err, "Failed to open source database")
+		os.Exit(1)
+	}
+	defer db.Close()
+	schema, err := sqlimport.Introspect(ctx, db, driver.dialect)
+	if err != nil {
+		logger.Error(err, "Failed to read source schema")
+		os.Exit(1)
+	}
+	logger.V(1).Info("Read source schema", "tables", len(schema.Tables))
+
+	if *schemaFlag {
+		for _, t := range schema.Tables {
+			fmt.Printf("%s (primary key %s)\n", t.Name, strings.Join(t.PrimaryKey, ", "))
+			for _, c := range t.Columns {
+				fmt.Printf("  %s %s\n", c.Name, c.Type)
+			}
+			for _, fk := range t.ForeignKeys {
+				fmt.Printf("  (%s) -> %s(%s)\n", strings.Join(fk.Columns, ", "), fk.RefTable,
+					strings.Join(fk.RefColumns, ", "))
+			}
+		}
+		return
+	}
+
+	opts := sqlimport.Options{
+		BatchSize:        *batchFlag,
+		InferForeignKeys: *inferFlag,
+		KeepJoinTables:   *keepJoinsFlag,
+	}
+	if *tablesFlag != "" {
+		opts.Tables = strings.Split(*tablesFlag, ",")
+	}
+
+	// Initialize modusGraph client with the directory where data is stored
+	dirPath := filepath.Clean(*dirFlag)
+	if err := os.MkdirAll(dirPath, 0o755); err != nil {
+		logger.Error(err, "Failed to create directory")
+		os.Exit(1)
+	}
+	logger.V(1).Info("Initializing modusGraph client", "directory", dirPath)
+	client, err := modusgraph.NewClient(fmt.Sprintf("file://%s", dirPath),
+		modusgraph.WithLogger(logger))
+	if err != nil {
+		logger.Error(err, "Failed to initialize modusGraph client")
+		os.Exit(1)
+	}
+	defer client.Close()
+
+	start := time.Now()
+	res, err := sqlimport.Import(ctx, client, db, schema, opts)
+	if err != nil {
+		logger.Error(err, "Import failed")
+		os.Exit(1)
+	}
+	elapsed := time.Since(start)
+	logger.Info("Import completed", "nodes", res.Nodes, "edges", res.Edges, "dangling", res.Dangling,
+		"elapsed_ms", float64(elapsed.Nanoseconds())/1e6)
+}
@@ -0,0 +1,108 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Computed lets a type derive some of its fields from others. When a value
+// passed to Insert, Upsert, Update or LoadOrStore implements Computed, the
+// client calls ComputeFields before validating and writing it, so derived
+// predicates such as a full name or a normalized search key never drift from
+// their sources. Generated entities implement it to run their compute hooks.
+type Computed interface {
+	ComputeFields(ctx context.Context) error
+}
+
+// compute is a derived field registered with WithCompute.
+type compute struct {
+	typ   reflect.Type
+	field string
+	// fn is the registered function, identifying it in the client dedup key.
+	fn any
+	// eval calls fn on a pointer to a struct of typ.
+	eval func(reflect.Value) reflect.Value
+}
+
+// WithCompute registers fn to derive the field of T named field, given as its
+// Go name or its predicate. On Insert, Upsert, Update and LoadOrStore the
+// client sets the field to fn's result before validating and writing each T,
+// for example to keep a lowercase search key in step with a display name:
+//
+//	modusgraph.WithCompute("search_key", func(p *Person) string {
+//	    return strings.ToLower(p.First + " " + p.Last)
+//	})
+//
+// fn sees the object as passed, so objects given to Update should carry the
+// fields it reads. Functions run in registration order.
+func WithCompute[T any, V any](field string, fn func(*T) V) ClientOpt {
+	return func(o *clientOptions) {
+		o.computes = append(o.computes, compute{
+			typ:   reflect.TypeFor[T](),
+			field: field,
+			fn:    fn,
+			eval: func(v reflect.Value) reflect.Value {
+				return reflect.ValueOf(fn(v.Interface().(*T)))
+			},
+		})
+	}
+}
+
+// computesKey identifies the registered compute functions for the client
+// dedup key.
+func computesKey(computes []compute) string {
+	parts := make([]string, len(computes))
+	for i, c := range computes {
+		parts[i] = fmt.Sprintf("%s.%s@%x", c.typ, c.field, reflect.ValueOf(c.fn).Pointer())
+	}
+	return strings.Join(parts, ",")
+}
+
+// applyComputed sets the derived fields of every struct in obj: first those
+// registered with WithCompute, then those of types implementing Computed.
+func (c client) applyComputed(ctx context.Context, obj any) error {
+	for _, sv := range structElems(obj) {
+		if !sv.CanAddr() {
+			continue
+		}
+		for _, comp := range c.options.computes {
+			if comp.typ != sv.Type() {
+				continue
+			}
+			field, ok := computeTarget(sv, comp.field)
+			if !ok {
+				return fmt.Errorf("computed field %s.%s not found", comp.typ.Name(), comp.field)
+			}
+			val := comp.eval(sv.Addr())
+			if !val.Type().AssignableTo(field.Type()) {
+				return fmt.Errorf("computed field %s.%s is %s, compute function returns %s",
+					comp.typ.Name(), comp.field, field.Type(), val.Type())
+			}
+			field.Set(val)
+		}
+		if computed, ok := sv.Addr().Interface().(Computed); ok {
+			if err := computed.ComputeFields(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// computeTarget returns the field of sv whose Go name or predicate is name.
+func computeTarget(sv reflect.Value, name string) (reflect.Value, bool) {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		if f := st.Field(i); f.IsExported() && (f.Name == name || fieldPredicate(f) == name) {
+			return sv.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,130 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type ComputedPerson struct {
+	UID       string   `json:"uid,omitempty"`
+	First     string   `json:"cp_first,omitempty"`
+	Last      string   `json:"cp_last,omitempty"`
+	FullName  string   `json:"cp_full_name,omitempty" dgraph:"index=term"`
+	SearchKey string   `json:"cp_search_key,omitempty" dgraph:"index=exact"`
+	DType     []string `json:"dgraph.type,omitempty"`
+}
+
+// ComputedCity derives its own slug through the Computed interface.
+type ComputedCity struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"cc_name,omitempty"`
+	Slug  string   `json:"cc_slug,omitempty" dgraph:"index=exact"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+func (c *ComputedCity) ComputeFields(ctx context.Context) error {
+	c.Slug = strings.ReplaceAll(strings.ToLower(c.Name), " ", "-")
+	return nil
+}
+
+func createComputeClient(t *testing.T, uri string, computes ...mg.ClientOpt) (mg.Client, func()) {
+	t.Helper()
+	client, err := mg.NewClient(uri, append([]mg.ClientOpt{mg.WithAutoSchema(true)}, computes...)...)
+	require.NoError(t, err)
+	require.NoError(t, client.DropAll(context.Background()))
+	return client, func() {
+		_ = client.DropAll(context.Background())
+		client.Close()
+		mg.Shutdown()
+	}
+}
+
+func TestCompute(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "ComputeWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "ComputeWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := createComputeClient(t, tc.uri,
+				mg.WithCompute("FullName", func(p *ComputedPerson) string {
+					return strings.TrimSpace(p.First + " " + p.Last)
+				}),
+				mg.WithCompute("cp_search_key", func(p *ComputedPerson) string {
+					return strings.ToLower(p.FullName)
+				}))
+			defer cleanup()
+			ctx := context.Background()
+
+			ada := &ComputedPerson{First: "Ada", Last: "Byron"}
+			require.NoError(t, client.Insert(ctx, ada))
+			require.Equal(t, "ada byron", ada.SearchKey, "functions run in registration order")
+
+			ada.Last = "Lovelace"
+			require.NoError(t, client.Update(ctx, ada))
+
+			var people []ComputedPerson
+			require.NoError(t, client.Query(ctx, ComputedPerson{}).
+				Filter(`eq(cp_search_key, "ada lovelace")`).Nodes(&people))
+			require.Len(t, people, 1)
+			require.Equal(t, "Ada Lovelace", people[0].FullName)
+
+			// Slices are computed element by element.
+			require.NoError(t, client.Insert(ctx, []*ComputedPerson{
+				{First: "Grace", Last: "Hopper"},
+				{First: "Alan", Last: "Turing"},
+			}))
+			people = nil
+			require.NoError(t, client.Query(ctx, ComputedPerson{}).
+				Filter(`anyofterms(cp_full_name, "turing")`).Nodes(&people))
+			require.Len(t, people, 1)
+			require.Equal(t, "alan turing", people[0].SearchKey)
+
+			city := &ComputedCity{Name: "New York"}
+			require.NoError(t, client.Insert(ctx, city))
+			var got ComputedCity
+			require.NoError(t, client.Get(ctx, &got, city.UID))
+			require.Equal(t, "new-york", got.Slug)
+		})
+	}
+}
+
+func TestComputeMismatchedField(t *testing.T) {
+	client, cleanup := createComputeClient(t, "file://"+GetTempDir(t),
+		mg.WithCompute("cp_full_name", func(p *ComputedPerson) int { return len(p.First) }),
+		mg.WithCompute("Missing", func(p *ComputedCity) string { return "" }))
+	defer cleanup()
+	ctx := context.Background()
+
+	err := client.Insert(ctx, &ComputedPerson{First: "Ada"})
+	require.ErrorContains(t, err, "compute function returns int")
+	err = client.Insert(ctx, &ComputedCity{Name: "Oslo"})
+	require.ErrorContains(t, err, "computed field ComputedCity.Missing not found")
+}
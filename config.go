@@ -6,6 +6,8 @@
 package modusgraph
 
 import (
+	"time"
+
 	"github.com/go-logr/logr"
 )
 
@@ -13,6 +15,7 @@ type Config struct {
 	dataDir            string
 	cacheSizeMB        int
 	limitNormalizeNode int
+	historyRetention   time.Duration
 
 	// logger is used for structured logging
 	logger logr.Logger
@@ -45,6 +48,13 @@ func (cc Config) WithCacheSizeMB(size int) Config {
 	return cc
 }
 
+// WithHistoryRetention sets how long past versions are kept for reads at an
+// earlier timestamp; zero keeps every version
+func (cc Config) WithHistoryRetention(d time.Duration) Config {
+	cc.historyRetention = d
+	return cc
+}
+
 func (cc Config) validate() error {
 	if cc.dataDir == "" {
 		return ErrEmptyDataDir
@@ -0,0 +1,1061 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CypherResult is the result of a Cypher query: one column per RETURN item,
+// named by its AS alias or its expression text (e.g. "f.film_title"), and
+// one row per match.
+type CypherResult struct {
+	Columns []string
+	Rows    [][]any
+}
+
+// Cypher translates a read-only openCypher query to DQL, runs it, and returns
+// the matched rows. The supported subset is:
+//
+//	MATCH (f:Film {film_title: "Alien"})-[:written_by]->(a)<-[:directed]-(d)
+//	WHERE a.author_name = $n AND (f.year >= 1980 OR f.tagline IS NULL)
+//	RETURN DISTINCT f.film_title AS title, a
+//	ORDER BY title DESC SKIP 10 LIMIT 5
+//
+// A single path pattern of labelled (type) or unlabelled nodes, joined by
+// directed relationships naming one edge predicate; <- traverses the edge in
+// reverse and needs the predicate's @reverse index. Every WHERE condition
+// must refer to a single node variable and compare a property with =, <>, <,
+// <=, >, >=, IS NULL, or IS NOT NULL, combined with AND, OR, NOT, and
+// parentheses. RETURN takes node properties and whole nodes (a map of the
+// node's scalar predicates and uid).
+//
+// Properties are predicates, labels are Dgraph types, and $name parameters
+// are bound from params. Relationship properties, OPTIONAL MATCH, WITH,
+// aggregation, and writes are not supported and return an error.
+func (c client) Cypher(ctx context.Context, query string, params map[string]any) (*CypherResult, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	cq, err := parseCypher(query)
+	if err != nil {
+		return nil, err
+	}
+	dql, vars, err := cq.dql(params)
+	if err != nil {
+		return nil, err
+	}
+	c.log(ctx).V(2).Info("Translated Cypher", "cypher", query, "dql", dql)
+	resp, err := c.QueryRaw(ctx, dql, vars)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Rows []map[string]any `json:"rows"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(resp))
+	dec.UseNumber()
+	if err := dec.Decode(&result); err != nil {
+		return nil, err
+	}
+	return cq.rows(result.Rows), nil
+}
+
+// cypherQuery is a parsed Cypher query.
+type cypherQuery struct {
+	nodes    []cypherNode
+	hops     []cypherHop // hops[i] joins nodes[i] and nodes[i+1]
+	where    *cypherCond
+	distinct bool
+	returns  []cypherReturn
+	order    []cypherOrder
+	skip     int
+	limit    int // -1 for none
+}
+
+type cypherNode struct {
+	name  string
+	label string
+	conds []*cypherCond // from inline {prop: value} maps and WHERE
+}
+
+type cypherHop struct {
+	edge    string
+	reverse bool
+}
+
+type cypherReturn struct {
+	variable string
+	prop     string // "" returns the whole node
+	column   string
+	hidden   bool // fetched only for ORDER BY
+}
+
+type cypherOrder struct {
+	column string
+	desc   bool
+}
+
+// cypherCond is a WHERE condition tree. Leaves compare variable.prop.
+type cypherCond struct {
+	op       string // "and", "or", "not", or a comparison: "=", "<>", "<", "<=", ">", ">=", "null", "notnull"
+	args     []*cypherCond
+	variable string
+	prop     string
+	value    cypherValue
+}
+
+// cypherValue is a literal or a $parameter.
+type cypherValue struct {
+	param string
+	lit   any // string, int64, float64, or bool
+}
+
+var cypherCompareFuncs = map[string]string{"=": "eq", "<>": "eq", "<": "lt", "<=": "le", ">": "gt", ">=": "ge"}
+
+// dql renders the query as DQL, returning the query variables to bind.
+func (cq *cypherQuery) dql(params map[string]any) (string, map[string]string, error) {
+	vars := map[string]string{}
+	var decls []string
+	bind := func(v cypherValue) (string, error) {
+		if v.param == "" {
+			return cypherLiteral(v.lit), nil
+		}
+		p, ok := params[v.param]
+		if !ok {
+			return "", fmt.Errorf("modusgraph: Cypher parameter $%s not provided", v.param)
+		}
+		name := "$" + v.param
+		if _, seen := vars[name]; !seen {
+			typ, val := cypherParamType(p)
+			vars[name] = val
+			decls = append(decls, name+": "+typ)
+		}
+		return name, nil
+	}
+
+	var render func(c *cypherCond) (string, error)
+	render = func(c *cypherCond) (string, error) {
+		switch c.op {
+		case "and", "or":
+			parts := make([]string, len(c.args))
+			for i, a := range c.args {
+				s, err := render(a)
+				if err != nil {
+					return "", err
+				}
+				parts[i] = s
+			}
+			return "(" + strings.Join(parts, " "+strings.ToUpper(c.op)+" ") + ")", nil
+		case "not":
+			s, err := render(c.args[0])
+			return "NOT " + s, err
+		case "null":
+			return "NOT has(" + c.prop + ")", nil
+		case "notnull":
+			return "has(" + c.prop + ")", nil
+		}
+		v, err := bind(c.value)
+		if err != nil {
+			return "", err
+		}
+		s := cypherCompareFuncs[c.op] + "(" + c.prop + ", " + v + ")"
+		if c.op == "<>" {
+			s = "NOT " + s
+		}
+		return s, nil
+	}
+
+	filters := make([]string, len(cq.nodes))
+	for i, n := range cq.nodes {
+		var parts []string
+		if n.label != "" && i > 0 {
+			parts = append(parts, "type("+n.label+")")
+		}
+		for _, c := range n.conds {
+			s, err := render(c)
+			if err != nil {
+				return "", nil, err
+			}
+			parts = append(parts, s)
+		}
+		if len(parts) > 0 {
+			filters[i] = " @filter(" + strings.Join(parts, " AND ") + ")"
+		}
+	}
+
+	var b strings.Builder
+	root := "has(dgraph.type)"
+	if cq.nodes[0].label != "" {
+		root = "type(" + cq.nodes[0].label + ")"
+	}
+	root += cq.pushdown()
+	fmt.Fprintf(&b, "rows(func: %s)%s", root, filters[0])
+	for i := range cq.nodes {
+		if i < len(cq.hops) {
+			fmt.Fprintf(&b, " @cascade(%s)", cq.hops[i].predicate())
+		}
+		b.WriteString(" { uid")
+		for _, p := range cq.selection(i) {
+			b.WriteString(" " + p)
+		}
+		if i < len(cq.hops) {
+			fmt.Fprintf(&b, " %s%s", cq.hops[i].predicate(), filters[i+1])
+		}
+	}
+	b.WriteString(strings.Repeat(" }", len(cq.nodes)))
+
+	q := "{ " + b.String() + " }"
+	if len(decls) > 0 {
+		q = "query rows(" + strings.Join(decls, ", ") + ") " + q
+	}
+	return q, vars, nil
+}
+
+// pushdown returns root ordering and pagination arguments when they can be
+// applied by Dgraph: a single-node pattern without DISTINCT, ordered only by
+// properties of that node. Pushed-down clauses are cleared from cq; the rest
+// are applied to the joined rows.
+func (cq *cypherQuery) pushdown() string {
+	if len(cq.nodes) != 1 || cq.distinct {
+		return ""
+	}
+	var args strings.Builder
+	for _, o := range cq.order {
+		r, ok := cq.column(o.column)
+		if !ok || r.prop == "" {
+			return ""
+		}
+		dir := "orderasc"
+		if o.desc {
+			dir = "orderdesc"
+		}
+		fmt.Fprintf(&args, ", %s: %s", dir, r.prop)
+	}
+	if cq.limit >= 0 {
+		fmt.Fprintf(&args, ", first: %d", cq.limit)
+	}
+	if cq.skip > 0 {
+		fmt.Fprintf(&args, ", offset: %d", cq.skip)
+	}
+	cq.order, cq.skip, cq.limit = nil, 0, -1
+	return args.String()
+}
+
+func (h cypherHop) predicate() string {
+	if h.reverse {
+		return "~" + h.edge
+	}
+	return h.edge
+}
+
+// selection lists the predicates fetched for node i. A whole-node return
+// fetches expand(_all_) alone, since Dgraph rejects predicates repeated
+// alongside it.
+func (cq *cypherQuery) selection(i int) []string {
+	var preds []string
+	for _, r := range cq.returns {
+		if r.variable != cq.nodes[i].name {
+			continue
+		}
+		if r.prop == "" {
+			return []string{"expand(_all_)"}
+		}
+		if !slices.Contains(preds, r.prop) {
+			preds = append(preds, r.prop)
+		}
+	}
+	return preds
+}
+
+func (cq *cypherQuery) column(name string) (cypherReturn, bool) {
+	for _, r := range cq.returns {
+		if r.column == name {
+			return r, true
+		}
+	}
+	return cypherReturn{}, false
+}
+
+// rows flattens the nested response into one row per pattern match and
+// applies DISTINCT, ORDER BY, SKIP, and LIMIT.
+func (cq *cypherQuery) rows(roots []map[string]any) *CypherResult {
+	res := &CypherResult{}
+	columns := make([]string, len(cq.returns))
+	for i, r := range cq.returns {
+		columns[i] = r.column
+	}
+	index := make(map[string]int, len(cq.nodes))
+	for i, n := range cq.nodes {
+		index[n.name] = i
+	}
+	path := make([]map[string]any, len(cq.nodes))
+	var walk func(depth int, node map[string]any)
+	walk = func(depth int, node map[string]any) {
+		path[depth] = node
+		if depth == len(cq.nodes)-1 {
+			row := make([]any, len(cq.returns))
+			for i, r := range cq.returns {
+				n := path[index[r.variable]]
+				if r.prop == "" {
+					row[i] = cypherNodeValue(n, cq.hops, index[r.variable])
+				} else {
+					row[i] = cypherScalar(n[r.prop])
+				}
+			}
+			res.Rows = append(res.Rows, row)
+			return
+		}
+		var children []any
+		switch v := node[cq.hops[depth].predicate()].(type) {
+		case []any:
+			children = v
+		case map[string]any: // a single-valued edge
+			children = []any{v}
+		}
+		for _, child := range children {
+			if m, ok := child.(map[string]any); ok {
+				walk(depth+1, m)
+			}
+		}
+	}
+	for _, root := range roots {
+		walk(0, root)
+	}
+
+	if cq.distinct {
+		seen := map[string]bool{}
+		res.Rows = slices.DeleteFunc(res.Rows, func(row []any) bool {
+			key, _ := json.Marshal(row)
+			if seen[string(key)] {
+				return true
+			}
+			seen[string(key)] = true
+			return false
+		})
+	}
+	if len(cq.order) > 0 {
+		cols := make([]int, len(cq.order))
+		for i, o := range cq.order {
+			cols[i] = slices.Index(columns, o.column)
+		}
+		sort.SliceStable(res.Rows, func(a, b int) bool {
+			for i, o := range cq.order {
+				if c := cypherCompare(res.Rows[a][cols[i]], res.Rows[b][cols[i]]); c != 0 {
+					return (c < 0) != o.desc
+				}
+			}
+			return false
+		})
+	}
+	if cq.skip > 0 {
+		res.Rows = res.Rows[min(cq.skip, len(res.Rows)):]
+	}
+	if cq.limit >= 0 && cq.limit < len(res.Rows) {
+		res.Rows = res.Rows[:cq.limit]
+	}
+	visible := len(cq.returns)
+	for visible > 0 && cq.returns[visible-1].hidden {
+		visible--
+	}
+	res.Columns = columns[:visible]
+	for i := range res.Rows {
+		res.Rows[i] = res.Rows[i][:visible]
+	}
+	return res
+}
+
+// cypherNodeValue returns a whole node as a map of its scalar predicates,
+// without the pattern's traversal edge.
+func cypherNodeValue(n map[string]any, hops []cypherHop, i int) map[string]any {
+	out := make(map[string]any, len(n))
+	for k, v := range n {
+		if i < len(hops) && k == hops[i].predicate() || isEdgeValue(v) {
+			continue
+		}
+		out[k] = cypherScalar(v)
+	}
+	return out
+}
+
+func isEdgeValue(v any) bool {
+	switch v := v.(type) {
+	case map[string]any:
+		return true
+	case []any:
+		if len(v) > 0 {
+			_, ok := v[0].(map[string]any)
+			return ok
+		}
+	}
+	return false
+}
+
+// cypherScalar converts json.Number values to int64 or float64.
+func cypherScalar(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = cypherScalar(e)
+		}
+		return out
+	}
+	return v
+}
+
+// cypherCompare orders nulls last, then numbers, strings, and booleans.
+func cypherCompare(a, b any) int {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return 1
+		}
+		return -1
+	}
+	if fa, ok := cypherFloat(a); ok {
+		if fb, ok := cypherFloat(b); ok {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	if ba, ok := a.(bool); ok {
+		if bb, ok := b.(bool); ok {
+			switch {
+			case ba == bb:
+				return 0
+			case !ba:
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func cypherFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func cypherLiteral(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case nil:
+		return `""`
+	}
+	return fmt.Sprint(v)
+}
+
+// cypherParamType returns the DQL variable type and string form of a
+// parameter value.
+func cypherParamType(v any) (string, string) {
+	switch v := v.(type) {
+	case bool:
+		return "bool", strconv.FormatBool(v)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "int", fmt.Sprint(v)
+	case float32, float64:
+		return "float", fmt.Sprint(v)
+	}
+	return "string", fmt.Sprint(v)
+}
+
+// parseCypher parses the supported Cypher subset.
+func parseCypher(query string) (*cypherQuery, error) {
+	toks, err := lexCypher(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &cypherParser{toks: toks}
+	cq, err := p.query()
+	if err != nil {
+		return nil, fmt.Errorf("modusgraph: Cypher: %w", err)
+	}
+	return cq, nil
+}
+
+type cypherToken struct {
+	kind string // "ident", "string", "number", "param", or the punctuation itself
+	text string
+	pos  int
+}
+
+func lexCypher(s string) ([]cypherToken, error) {
+	var toks []cypherToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'' || c == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(s) && s[j] != c; j++ {
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+				}
+				b.WriteByte(s[j])
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("modusgraph: Cypher: unterminated string at offset %d", i)
+			}
+			toks = append(toks, cypherToken{"string", b.String(), i})
+			i = j + 1
+		case c == '`':
+			j := strings.IndexByte(s[i+1:], '`')
+			if j < 0 {
+				return nil, fmt.Errorf("modusgraph: Cypher: unterminated identifier at offset %d", i)
+			}
+			toks = append(toks, cypherToken{"ident", s[i+1 : i+1+j], i})
+			i += j + 2
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.' && j+1 < len(s) && s[j+1] >= '0' && s[j+1] <= '9') {
+				j++
+			}
+			toks = append(toks, cypherToken{"number", s[i:j], i})
+			i = j
+		case c == '$' || isCypherIdentStart(rune(c)):
+			j := i + 1
+			for j < len(s) && (isCypherIdentStart(rune(s[j])) || s[j] >= '0' && s[j] <= '9') {
+				j++
+			}
+			if c == '$' {
+				toks = append(toks, cypherToken{"param", s[i+1 : j], i})
+			} else {
+				toks = append(toks, cypherToken{"ident", s[i:j], i})
+			}
+			i = j
+		default:
+			two := ""
+			if i+1 < len(s) {
+				two = s[i : i+2]
+			}
+			if two == "<>" || two == "<=" || two == ">=" || two == "!=" {
+				if two == "!=" {
+					two = "<>"
+				}
+				toks = append(toks, cypherToken{two, two, i})
+				i += 2
+				continue
+			}
+			if !strings.ContainsRune("()[]{}:,.-<>=*", rune(c)) {
+				return nil, fmt.Errorf("modusgraph: Cypher: unexpected %q at offset %d", c, i)
+			}
+			toks = append(toks, cypherToken{string(c), string(c), i})
+			i++
+		}
+	}
+	return toks, nil
+}
+
+func isCypherIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+type cypherParser struct {
+	toks []cypherToken
+	pos  int
+}
+
+func (p *cypherParser) peek() cypherToken {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return cypherToken{kind: "eof"}
+}
+
+func (p *cypherParser) next() cypherToken {
+	t := p.peek()
+	if p.pos < len(p.toks) {
+		p.pos++
+	}
+	return t
+}
+
+// keyword consumes the next token if it is the (case-insensitive) keyword.
+func (p *cypherParser) keyword(kw string) bool {
+	if t := p.peek(); t.kind == "ident" && strings.EqualFold(t.text, kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *cypherParser) expect(kind string) (cypherToken, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, p.errorf(t, "expected %s", kind)
+	}
+	return t, nil
+}
+
+func (p *cypherParser) errorf(t cypherToken, format string, args ...any) error {
+	found := t.text
+	if t.kind == "eof" {
+		found = "end of query"
+	}
+	return fmt.Errorf("%s at offset %d (found %q)", fmt.Sprintf(format, args...), t.pos, found)
+}
+
+func (p *cypherParser) query() (*cypherQuery, error) {
+	cq := &cypherQuery{limit: -1}
+	if !p.keyword("MATCH") {
+		return nil, p.errorf(p.peek(), "expected MATCH; only read queries are supported")
+	}
+	if err := p.pattern(cq); err != nil {
+		return nil, err
+	}
+	if p.keyword("WHERE") {
+		cond, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		cq.where = cond
+	}
+	if !p.keyword("RETURN") {
+		return nil, p.errorf(p.peek(), "expected WHERE or RETURN")
+	}
+	cq.distinct = p.keyword("DISTINCT")
+	for {
+		r, err := p.returnItem(cq)
+		if err != nil {
+			return nil, err
+		}
+		cq.returns = append(cq.returns, r)
+		if p.peek().kind != "," {
+			break
+		}
+		p.next()
+	}
+	if p.keyword("ORDER") {
+		if !p.keyword("BY") {
+			return nil, p.errorf(p.peek(), "expected BY")
+		}
+		for {
+			o, err := p.orderItem(cq)
+			if err != nil {
+				return nil, err
+			}
+			cq.order = append(cq.order, o)
+			if p.peek().kind != "," {
+				break
+			}
+			p.next()
+		}
+	}
+	if p.keyword("SKIP") {
+		n, err := p.count()
+		if err != nil {
+			return nil, err
+		}
+		cq.skip = n
+	}
+	if p.keyword("LIMIT") {
+		n, err := p.count()
+		if err != nil {
+			return nil, err
+		}
+		cq.limit = n
+	}
+	if t := p.peek(); t.kind != "eof" {
+		return nil, p.errorf(t, "unsupported clause")
+	}
+	if err := cq.attachWhere(); err != nil {
+		return nil, err
+	}
+	return cq, nil
+}
+
+func (p *cypherParser) pattern(cq *cypherQuery) error {
+	for {
+		n, err := p.node(len(cq.nodes))
+		if err != nil {
+			return err
+		}
+		for _, m := range cq.nodes {
+			if m.name == n.name {
+				return fmt.Errorf("variable %s appears twice in the pattern; cycles are not supported", n.name)
+			}
+		}
+		cq.nodes = append(cq.nodes, n)
+		switch p.peek().kind {
+		case "-", "<":
+		case ",":
+			return p.errorf(p.peek(), "multiple patterns are not supported")
+		default:
+			return nil
+		}
+		h, err := p.hop()
+		if err != nil {
+			return err
+		}
+		cq.hops = append(cq.hops, h)
+	}
+}
+
+// node parses (name:Label {prop: value, ...}).
+func (p *cypherParser) node(i int) (cypherNode, error) {
+	if _, err := p.expect("("); err != nil {
+		return cypherNode{}, err
+	}
+	n := cypherNode{name: fmt.Sprintf("_n%d", i)}
+	if t := p.peek(); t.kind == "ident" {
+		n.name = p.next().text
+	}
+	if p.peek().kind == ":" {
+		p.next()
+		t, err := p.expect("ident")
+		if err != nil {
+			return n, err
+		}
+		n.label = t.text
+		if p.peek().kind == ":" {
+			return n, p.errorf(p.peek(), "multiple labels are not supported")
+		}
+	}
+	if p.peek().kind == "{" {
+		p.next()
+		for p.peek().kind != "}" {
+			key, err := p.expect("ident")
+			if err != nil {
+				return n, err
+			}
+			if _, err := p.expect(":"); err != nil {
+				return n, err
+			}
+			v, err := p.value()
+			if err != nil {
+				return n, err
+			}
+			n.conds = append(n.conds, &cypherCond{op: "=", variable: n.name, prop: key.text, value: v})
+			if p.peek().kind == "," {
+				p.next()
+			}
+		}
+		p.next()
+	}
+	_, err := p.expect(")")
+	return n, err
+}
+
+// hop parses -[:edge]-> or <-[:edge]-.
+func (p *cypherParser) hop() (cypherHop, error) {
+	var h cypherHop
+	if p.peek().kind == "<" {
+		p.next()
+		h.reverse = true
+	}
+	if _, err := p.expect("-"); err != nil {
+		return h, err
+	}
+	if _, err := p.expect("["); err != nil {
+		return h, err
+	}
+	if t := p.peek(); t.kind == "ident" {
+		p.next() // relationship variable; its properties are not supported
+	}
+	if _, err := p.expect(":"); err != nil {
+		return h, fmt.Errorf("relationships must name their type: %w", err)
+	}
+	t, err := p.expect("ident")
+	if err != nil {
+		return h, err
+	}
+	h.edge = t.text
+	switch p.peek().kind {
+	case "]":
+	case "*":
+		return h, p.errorf(p.peek(), "variable-length relationships are not supported")
+	default:
+		return h, p.errorf(p.peek(), "expected ]")
+	}
+	p.next()
+	if _, err := p.expect("-"); err != nil {
+		return h, err
+	}
+	if p.peek().kind == ">" {
+		p.next()
+		if h.reverse {
+			return h, fmt.Errorf("relationship %s points both ways", h.edge)
+		}
+	} else if !h.reverse {
+		return h, fmt.Errorf("relationship %s must be directed", h.edge)
+	}
+	return h, nil
+}
+
+func (p *cypherParser) or() (*cypherCond, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = &cypherCond{op: "or", args: []*cypherCond{left, right}}
+	}
+	return left, nil
+}
+
+func (p *cypherParser) and() (*cypherCond, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND") {
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		left = &cypherCond{op: "and", args: []*cypherCond{left, right}}
+	}
+	return left, nil
+}
+
+func (p *cypherParser) not() (*cypherCond, error) {
+	if p.keyword("NOT") {
+		c, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return &cypherCond{op: "not", args: []*cypherCond{c}}, nil
+	}
+	if p.peek().kind == "(" {
+		p.next()
+		c, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.expect(")")
+		return c, err
+	}
+	return p.comparison()
+}
+
+func (p *cypherParser) comparison() (*cypherCond, error) {
+	v, err := p.expect("ident")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect("."); err != nil {
+		return nil, err
+	}
+	prop, err := p.expect("ident")
+	if err != nil {
+		return nil, err
+	}
+	c := &cypherCond{variable: v.text, prop: prop.text}
+	if p.keyword("IS") {
+		c.op = "null"
+		if p.keyword("NOT") {
+			c.op = "notnull"
+		}
+		if !p.keyword("NULL") {
+			return nil, p.errorf(p.peek(), "expected NULL")
+		}
+		return c, nil
+	}
+	op := p.next()
+	switch op.kind {
+	case "=", "<>", "<", "<=", ">", ">=":
+		c.op = op.kind
+	default:
+		return nil, p.errorf(op, "unsupported operator")
+	}
+	c.value, err = p.value()
+	return c, err
+}
+
+func (p *cypherParser) value() (cypherValue, error) {
+	t := p.next()
+	switch t.kind {
+	case "param":
+		return cypherValue{param: t.text}, nil
+	case "string":
+		return cypherValue{lit: t.text}, nil
+	case "number":
+		if i, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+			return cypherValue{lit: i}, nil
+		}
+		f, err := strconv.ParseFloat(t.text, 64)
+		return cypherValue{lit: f}, err
+	case "-":
+		v, err := p.value()
+		switch n := v.lit.(type) {
+		case int64:
+			v.lit = -n
+		case float64:
+			v.lit = -n
+		default:
+			return v, p.errorf(t, "expected number after -")
+		}
+		return v, err
+	case "ident":
+		switch strings.ToLower(t.text) {
+		case "true":
+			return cypherValue{lit: true}, nil
+		case "false":
+			return cypherValue{lit: false}, nil
+		}
+	}
+	return cypherValue{}, p.errorf(t, "expected a literal or $parameter")
+}
+
+func (p *cypherParser) returnItem(cq *cypherQuery) (cypherReturn, error) {
+	v, err := p.expect("ident")
+	if err != nil {
+		return cypherReturn{}, err
+	}
+	r := cypherReturn{variable: v.text, column: v.text}
+	if p.peek().kind == "(" {
+		return r, p.errorf(v, "functions and aggregation are not supported")
+	}
+	if p.peek().kind == "." {
+		p.next()
+		prop, err := p.expect("ident")
+		if err != nil {
+			return r, err
+		}
+		r.prop = prop.text
+		r.column = v.text + "." + prop.text
+	}
+	if !slices.ContainsFunc(cq.nodes, func(n cypherNode) bool { return n.name == r.variable }) {
+		return r, p.errorf(v, "unknown node variable %s", r.variable)
+	}
+	if p.keyword("AS") {
+		alias, err := p.expect("ident")
+		if err != nil {
+			return r, err
+		}
+		r.column = alias.text
+	}
+	return r, nil
+}
+
+func (p *cypherParser) orderItem(cq *cypherQuery) (cypherOrder, error) {
+	t, err := p.expect("ident")
+	if err != nil {
+		return cypherOrder{}, err
+	}
+	name := t.text
+	if p.peek().kind == "." {
+		p.next()
+		prop, err := p.expect("ident")
+		if err != nil {
+			return cypherOrder{}, err
+		}
+		name += "." + prop.text
+	}
+	if _, ok := cq.column(name); !ok {
+		// Allow ordering by an aliased property through its expression, or
+		// by any property of a pattern node through a hidden column.
+		for _, r := range cq.returns {
+			if r.prop != "" && r.variable+"."+r.prop == name {
+				name, ok = r.column, true
+				break
+			}
+		}
+		variable, prop, isProp := strings.Cut(name, ".")
+		if !ok && isProp && slices.ContainsFunc(cq.nodes, func(n cypherNode) bool { return n.name == variable }) {
+			cq.returns = append(cq.returns, cypherReturn{variable: variable, prop: prop, column: name, hidden: true})
+			ok = true
+		}
+		if !ok {
+			return cypherOrder{}, p.errorf(t, "ORDER BY %s must name a RETURN item or node property", name)
+		}
+	}
+	o := cypherOrder{column: name}
+	if p.keyword("DESC") || p.keyword("DESCENDING") {
+		o.desc = true
+	} else if !p.keyword("ASC") {
+		p.keyword("ASCENDING")
+	}
+	return o, nil
+}
+
+func (p *cypherParser) count() (int, error) {
+	t, err := p.expect("number")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(t.text)
+}
+
+// attachWhere distributes the WHERE clause's top-level conjuncts to the node
+// each refers to.
+func (cq *cypherQuery) attachWhere() error {
+	if cq.where == nil {
+		return nil
+	}
+	var conjuncts []*cypherCond
+	var split func(c *cypherCond)
+	split = func(c *cypherCond) {
+		if c.op == "and" {
+			for _, a := range c.args {
+				split(a)
+			}
+			return
+		}
+		conjuncts = append(conjuncts, c)
+	}
+	split(cq.where)
+	for _, c := range conjuncts {
+		vars := map[string]bool{}
+		c.variables(vars)
+		if len(vars) != 1 {
+			return fmt.Errorf("modusgraph: Cypher: a WHERE condition must refer to exactly one node; split it with AND")
+		}
+		var name string
+		for v := range vars {
+			name = v
+		}
+		i := slices.IndexFunc(cq.nodes, func(n cypherNode) bool { return n.name == name })
+		if i < 0 {
+			return fmt.Errorf("modusgraph: Cypher: unknown node variable %s in WHERE", name)
+		}
+		cq.nodes[i].conds = append(cq.nodes[i].conds, c)
+	}
+	return nil
+}
+
+func (c *cypherCond) variables(into map[string]bool) {
+	if c.variable != "" {
+		into[c.variable] = true
+	}
+	for _, a := range c.args {
+		a.variables(into)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCypherTranslation(t *testing.T) {
+	tests := []struct {
+		name   string
+		cypher string
+		params map[string]any
+		want   string
+		vars   map[string]string
+	}{
+		{
+			"Single hop with parameter",
+			"MATCH (f:Film)-[:written_by]->(a) WHERE a.author_name = $n RETURN f.film_title",
+			map[string]any{"n": "Dan O'Bannon"},
+			`query rows($n: string) { rows(func: type(Film)) @cascade(written_by) { uid film_title ` +
+				`written_by @filter(eq(author_name, $n)) { uid } } }`,
+			map[string]string{"$n": "Dan O'Bannon"},
+		},
+		{
+			"Inline properties, labels, reverse hop, and boolean logic",
+			`MATCH (a:Author {author_name: 'Ridley'})<-[:written_by]-(f:Film) ` +
+				`WHERE NOT f.year < 1980 AND (f.rating >= 7.5 OR f.tagline IS NULL) RETURN a, f.film_title AS title`,
+			nil,
+			`{ rows(func: type(Author)) @filter(eq(author_name, "Ridley")) @cascade(~written_by) { uid expand(_all_) ` +
+				`~written_by @filter(type(Film) AND NOT lt(year, 1980) AND (ge(rating, 7.5) OR NOT has(tagline))) ` +
+				`{ uid film_title } } }`,
+			map[string]string{},
+		},
+		{
+			"Single node pushes ordering and paging down",
+			"MATCH (f:Film) WHERE f.year <> $y RETURN f.film_title ORDER BY f.film_title DESC SKIP 5 LIMIT 10",
+			map[string]any{"y": 1979},
+			`query rows($y: int) { rows(func: type(Film), orderdesc: film_title, first: 10, offset: 5) ` +
+				`@filter(NOT eq(year, $y)) { uid film_title } }`,
+			map[string]string{"$y": "1979"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cq, err := parseCypher(tt.cypher)
+			require.NoError(t, err)
+			dql, vars, err := cq.dql(tt.params)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, dql)
+			require.Equal(t, tt.vars, vars)
+		})
+	}
+}
+
+func TestCypherUnsupported(t *testing.T) {
+	for _, q := range []string{
+		"CREATE (f:Film {title: 'x'})",
+		"MATCH (f:Film)-[:written_by]->(a) WHERE f.year = a.born RETURN f",
+		"MATCH (f:Film)-[:written_by*1..3]->(a) RETURN f",
+		"MATCH (f:Film)-[:written_by]-(a) RETURN f",
+		"MATCH (f:Film) RETURN count(f)",
+		"MATCH (f:Film) WITH f RETURN f",
+		"MATCH (f:Film) RETURN g.title",
+	} {
+		_, err := parseCypher(q)
+		require.Error(t, err, q)
+	}
+}
+
+func TestCypherRows(t *testing.T) {
+	cq, err := parseCypher("MATCH (f:Film)-[:written_by]->(a:Author) " +
+		"RETURN DISTINCT a.author_name AS author, f.year ORDER BY f.year DESC, author LIMIT 3")
+	require.NoError(t, err)
+	_, _, err = cq.dql(nil)
+	require.NoError(t, err)
+	res := cq.rows([]map[string]any{
+		{"uid": "0x1", "year": int64(1979), "written_by": []any{
+			map[string]any{"uid": "0x3", "author_name": "Dan"},
+			map[string]any{"uid": "0x4", "author_name": "Ron"},
+		}},
+		{"uid": "0x2", "year": int64(1986), "written_by": []any{
+			map[string]any{"uid": "0x3", "author_name": "Dan"},
+		}},
+		{"uid": "0x5", "year": int64(1979), "written_by": []any{
+			map[string]any{"uid": "0x3", "author_name": "Dan"},
+		}},
+	})
+	require.Equal(t, []string{"author", "f.year"}, res.Columns)
+	require.Equal(t, [][]any{{"Dan", int64(1986)}, {"Dan", int64(1979)}, {"Ron", int64(1979)}}, res.Rows)
+}
@@ -0,0 +1,107 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type CypherStudio struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"studio_name,omitempty" dgraph:"index=exact"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type CypherAuthor struct {
+	UID    string        `json:"uid,omitempty"`
+	Name   string        `json:"author_name,omitempty" dgraph:"index=exact"`
+	Studio *CypherStudio `json:"signed_to,omitempty"`
+	DType  []string      `json:"dgraph.type,omitempty"`
+}
+
+type CypherFilm struct {
+	UID     string         `json:"uid,omitempty"`
+	Title   string         `json:"film_title,omitempty" dgraph:"index=exact"`
+	Year    int            `json:"year,omitempty" dgraph:"index=int"`
+	Authors []CypherAuthor `json:"written_by,omitempty" dgraph:"reverse"`
+	DType   []string       `json:"dgraph.type,omitempty"`
+}
+
+func TestCypher(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "CypherWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "CypherWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			ctx := context.Background()
+			studio := CypherStudio{Name: "Brandywine"}
+			films := []*CypherFilm{
+				{Title: "Alien", Year: 1979, Authors: []CypherAuthor{
+					{Name: "Dan O'Bannon", Studio: &studio}, {Name: "Ronald Shusett"},
+				}},
+				{Title: "Total Recall", Year: 1990, Authors: []CypherAuthor{{Name: "Ronald Shusett"}}},
+				{Title: "Dark Star", Year: 1974, Authors: []CypherAuthor{{Name: "Dan O'Bannon"}}},
+			}
+			for _, f := range films {
+				require.NoError(t, client.Insert(ctx, f))
+			}
+
+			res, err := client.Cypher(ctx,
+				"MATCH (f:CypherFilm)-[:written_by]->(a) WHERE a.author_name = $n RETURN f.film_title ORDER BY f.film_title",
+				map[string]any{"n": "Ronald Shusett"})
+			require.NoError(t, err)
+			require.Equal(t, []string{"f.film_title"}, res.Columns)
+			require.Equal(t, [][]any{{"Alien"}, {"Total Recall"}}, res.Rows)
+
+			res, err = client.Cypher(ctx,
+				"MATCH (f:CypherFilm)-[:written_by]->(a:CypherAuthor)-[:signed_to]->(s) "+
+					"WHERE f.year < 1980 RETURN f.film_title AS title, a.author_name, s.studio_name", nil)
+			require.NoError(t, err)
+			require.Equal(t, [][]any{{"Alien", "Dan O'Bannon", "Brandywine"}}, res.Rows)
+
+			res, err = client.Cypher(ctx,
+				"MATCH (f:CypherFilm) WHERE f.year >= $y RETURN f ORDER BY f.year DESC LIMIT 1",
+				map[string]any{"y": 1975})
+			require.NoError(t, err)
+			require.Len(t, res.Rows, 1)
+			node, ok := res.Rows[0][0].(map[string]any)
+			require.True(t, ok)
+			require.Equal(t, "Total Recall", node["film_title"])
+			require.Equal(t, int64(1990), node["year"])
+			require.NotContains(t, node, "written_by")
+
+			_, err = client.Cypher(ctx, "MATCH (f:CypherFilm) RETURN f.film_title", nil)
+			require.NoError(t, err)
+			_, err = client.Cypher(ctx, "MATCH (f:CypherFilm) WHERE f.year = $missing RETURN f", nil)
+			require.ErrorContains(t, err, "$missing")
+		})
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"reflect"
+	"regexp"
+	"time"
+)
+
+// WithTimeLocation sets the location of the times the client reads and
+// writes. Dgraph returns a datetime in the offset it was written with, as a
+// fixed zone without a name, so the same instant can come back differently
+// from different writers. With a location set, Get and the generic Query
+// convert every time.Time field to it, and Insert, Upsert, Update,
+// LoadOrStore and UpsertIf convert the values they are given, so a written
+// struct compares equal to the one read back.
+func WithTimeLocation(loc *time.Location) ClientOpt {
+	return func(o *clientOptions) {
+		o.timeLocation = loc
+	}
+}
+
+// locationKey identifies a time location for the client dedup key.
+func locationKey(loc *time.Location) string {
+	if loc == nil {
+		return "nil"
+	}
+	return loc.String()
+}
+
+// dtPrecisionTag matches the dtprecision=<unit> token of a dgraph tag.
+var dtPrecisionTag = regexp.MustCompile(`(?:^|[\s,])dtprecision=(\w+)`)
+
+// dtPrecision returns the precision a time field's dgraph tag declares:
+// "day" truncates values to midnight and "second" drops fractions of a
+// second, which Dgraph does not return. Other fields are left as they are.
+func dtPrecision(f reflect.StructField) string {
+	if m := dtPrecisionTag.FindStringSubmatch(f.Tag.Get("dgraph")); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// NormalizeTimes applies the client's time location and the dtprecision tags
+// of obj's fields to its times, and those of the nodes its edges hold. Get
+// does this itself; call it on nodes decoded by a Query.
+func NormalizeTimes(c Client, obj any) {
+	var loc *time.Location
+	if mc, ok := c.(client); ok {
+		loc = mc.options.timeLocation
+	}
+	normalizeTimes(reflect.ValueOf(UnwrapSchema(obj)), loc, "", make(map[uintptr]bool))
+}
+
+// normalizeTimes rewrites the settable times in v. precision applies to v
+// itself when it is a time.
+func normalizeTimes(v reflect.Value, loc *time.Location, precision string, seen map[uintptr]bool) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || seen[v.Pointer()] {
+			return
+		}
+		seen[v.Pointer()] = true
+		normalizeTimes(v.Elem(), loc, precision, seen)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			normalizeTimes(v.Index(i), loc, precision, seen)
+		}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			if v.CanSet() {
+				v.Set(reflect.ValueOf(normalizeTime(v.Interface().(time.Time), loc, precision)))
+			}
+			return
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.IsExported() {
+				normalizeTimes(v.Field(i), loc, dtPrecision(f), seen)
+			}
+		}
+	}
+}
+
+// normalizeTime returns t in loc, if set, truncated to precision. Days are
+// those of loc, or of t's own zone without one. The zero time is kept, since
+// an omitempty field holding it is not written.
+func normalizeTime(t time.Time, loc *time.Location, precision string) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	if loc != nil {
+		t = t.In(loc)
+	}
+	switch precision {
+	case "day":
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	case "second":
+		return t.Truncate(time.Second)
+	}
+	return t
+}
@@ -0,0 +1,124 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type DatedRelease struct {
+	UID       string        `json:"uid,omitempty"`
+	Title     string        `json:"dated_title,omitempty" dgraph:"index=exact"`
+	Premiere  time.Time     `json:"dated_premiere,omitempty" dgraph:"dtprecision=second"`
+	OnSale    *time.Time    `json:"dated_on_sale,omitempty" dgraph:"index=day dtprecision=day"`
+	Announced time.Time     `json:"dated_announced,omitempty"`
+	Sequel    *DatedRelease `json:"dated_sequel,omitempty"`
+	DType     []string      `json:"dgraph.type,omitempty"`
+}
+
+func TestTimeLocation(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "TimeLocationWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "TimeLocationWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, err := mg.NewClient(tc.uri, mg.WithAutoSchema(true), mg.WithTimeLocation(time.UTC))
+			require.NoError(t, err)
+			defer func() {
+				if strings.HasPrefix(tc.uri, "dgraph://") {
+					_ = client.DropAll(context.Background())
+				}
+				client.Close()
+				mg.Shutdown()
+			}()
+			ctx := context.Background()
+			if strings.HasPrefix(tc.uri, "dgraph://") {
+				require.NoError(t, client.DropAll(ctx))
+			}
+
+			// 23:30 in New York is already the next day in UTC.
+			onSale := time.Date(2024, 3, 9, 23, 30, 0, 0, newYork)
+			release := &DatedRelease{
+				Title:     "Metropolis",
+				Premiere:  time.Date(2024, 3, 10, 12, 30, 45, 123456789, tokyo),
+				OnSale:    &onSale,
+				Announced: time.Date(2023, 1, 2, 3, 4, 5, 0, newYork),
+				Sequel: &DatedRelease{
+					Title:    "Metropolis II",
+					Premiere: time.Date(2025, 6, 1, 20, 0, 0, 999, newYork),
+				},
+			}
+			require.NoError(t, client.Insert(ctx, release))
+
+			// Writes convert to the client's location and truncate tagged fields.
+			require.Equal(t, time.Date(2024, 3, 10, 3, 30, 45, 0, time.UTC), release.Premiere)
+			require.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), *release.OnSale)
+			require.Equal(t, time.UTC, release.Announced.Location())
+			require.Equal(t, time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), release.Sequel.Premiere)
+
+			// Reads come back exactly as written, edges included.
+			var got DatedRelease
+			require.NoError(t, client.Get(ctx, &got, release.UID))
+			require.Equal(t, release.Premiere, got.Premiere)
+			require.Equal(t, release.OnSale, got.OnSale)
+			require.Equal(t, release.Announced, got.Announced)
+			require.NotNil(t, got.Sequel)
+			require.Equal(t, release.Sequel.Premiere, got.Sequel.Premiere)
+
+			releases, err := mg.Query[DatedRelease](ctx, client).Filter("eq(dated_title, ?)", "Metropolis").All()
+			require.NoError(t, err)
+			require.Len(t, releases, 1)
+			require.Equal(t, release.Premiere, releases[0].Premiere)
+			require.Equal(t, release.OnSale, releases[0].OnSale)
+
+			// Derived clients can read the same data in another location.
+			local, err := client.With(mg.WithTimeLocation(tokyo))
+			require.NoError(t, err)
+			got = DatedRelease{}
+			require.NoError(t, local.Get(ctx, &got, release.UID))
+			require.Equal(t, tokyo, got.Premiere.Location())
+			require.True(t, got.Premiere.Equal(release.Premiere))
+			require.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, tokyo), *got.OnSale)
+
+			// Nodes decoded by a raw query are normalized on request.
+			var nodes []DatedRelease
+			require.NoError(t, client.Query(ctx, DatedRelease{}).Filter(`eq(dated_title, "Metropolis")`).Nodes(&nodes))
+			require.Len(t, nodes, 1)
+			mg.NormalizeTimes(client, &nodes)
+			require.Equal(t, release.Announced, nodes[0].Announced)
+		})
+	}
+}
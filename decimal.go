@@ -0,0 +1,233 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidDecimal is returned when a value is not a decimal number, or
+// has more significant digits than a bigfloat predicate holds.
+var ErrInvalidDecimal = errors.New("modusgraph: invalid decimal")
+
+// MaxDecimalDigits is the number of significant digits a Decimal may have.
+// Dgraph's bigfloat holds 200 bits, which represent every decimal of this
+// many digits exactly; longer values are rejected rather than rounded.
+const MaxDecimalDigits = 60
+
+// maxDecimalExponent bounds the exponent of a Decimal, keeping its plain
+// form to a reasonable length.
+const maxDecimalExponent = 1000
+
+// Decimal is an exact decimal number for fields that must not round, such
+// as money. Fields of this type are stored in bigfloat predicates, written
+// as strings and read back digit for digit, and compare numerically in
+// filters given a Decimal parameter:
+//
+//	type Invoice struct {
+//	    Total modusgraph.Decimal `json:"total,omitempty" dgraph:"index=bigfloat"`
+//	    // UID and DType omitted
+//	}
+//
+//	invoice := &Invoice{Total: modusgraph.NewDecimal(1999, 2)} // 19.99
+//	large, err := modusgraph.Query[Invoice](ctx, client).
+//	    Filter("gt(total, ?)", modusgraph.MustParseDecimal("1000")).
+//	    All()
+//
+// Values are kept in canonical form, without exponent or trailing zeros,
+// so equal numbers are equal Decimals: 19.90 is 19.9. The zero Decimal is
+// unset: it reads as 0 but is written as null, which leaves the stored
+// value alone, so an explicit zero is ParseDecimal("0").
+type Decimal struct {
+	s string // canonical form; "" when unset
+}
+
+// ParseDecimal parses a decimal number such as "19.99", "-0.5" or "1.5e3".
+func ParseDecimal(s string) (Decimal, error) {
+	canonical, err := canonicalDecimal(s)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("%w: %q: %w", ErrInvalidDecimal, s, err)
+	}
+	return Decimal{s: canonical}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if s is not a decimal.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// NewDecimal returns unscaled × 10^-scale, so NewDecimal(1999, 2) is 19.99.
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	return MustParseDecimal(strconv.FormatInt(unscaled, 10) + "e" + strconv.FormatInt(int64(-scale), 10))
+}
+
+// DecimalFromRat returns r as a Decimal. It fails if r has no finite
+// decimal expansion, as 1/3 does.
+func DecimalFromRat(r *big.Rat) (Decimal, error) {
+	// A fraction in lowest terms terminates when its denominator has no
+	// prime factors but 2 and 5; the count of the larger gives the scale.
+	den := new(big.Int).Set(r.Denom())
+	scale := 0
+	for _, p := range []int64{2, 5} {
+		n := 0
+		q, m := new(big.Int), new(big.Int)
+		for {
+			q.QuoRem(den, big.NewInt(p), m)
+			if m.Sign() != 0 {
+				break
+			}
+			den.Set(q)
+			n++
+		}
+		scale = max(scale, n)
+	}
+	if den.Cmp(big.NewInt(1)) != 0 {
+		return Decimal{}, fmt.Errorf("%w: %s has no finite decimal form", ErrInvalidDecimal, r.RatString())
+	}
+	return ParseDecimal(r.FloatString(scale))
+}
+
+// String returns d in canonical form, "0" when unset.
+func (d Decimal) String() string {
+	if d.s == "" {
+		return "0"
+	}
+	return d.s
+}
+
+// IsSet reports whether d holds a value.
+func (d Decimal) IsSet() bool {
+	return d.s != ""
+}
+
+// Rat returns d as an exact rational number, for arithmetic.
+func (d Decimal) Rat() *big.Rat {
+	r, _ := new(big.Rat).SetString(d.String())
+	return r
+}
+
+// Cmp compares d and e, returning -1, 0 or +1 as d is less than, equal to
+// or greater than e.
+func (d Decimal) Cmp(e Decimal) int {
+	if d.s == e.s {
+		return 0
+	}
+	return d.Rat().Cmp(e.Rat())
+}
+
+// SchemaType reports the Dgraph type of the predicate to dgman.
+func (Decimal) SchemaType() string {
+	return "bigfloat"
+}
+
+// MarshalText returns d's canonical form, so a Decimal filter parameter
+// binds as a string literal that Dgraph compares numerically.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText parses a decimal number.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	v, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// MarshalJSON writes d as a string, which Dgraph parses into a bigfloat
+// without passing through float64, or null when d is unset.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if d.s == "" {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.s)
+}
+
+// UnmarshalJSON reads a number, as Dgraph returns a bigfloat, or a string.
+// null leaves d unset.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case string(data) == "null":
+		*d = Decimal{}
+		return nil
+	case len(data) > 0 && data[0] == '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return d.UnmarshalText([]byte(s))
+	}
+	return d.UnmarshalText(data)
+}
+
+// canonicalDecimal returns the plain form of the decimal number s, without
+// exponent, leading or trailing zeros, or a sign on zero.
+func canonicalDecimal(s string) (string, error) {
+	mantissa, exp := s, 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil || e > maxDecimalExponent || e < -maxDecimalExponent {
+			return "", errors.New("bad exponent")
+		}
+		mantissa, exp = s[:i], e
+	}
+	neg := false
+	switch {
+	case strings.HasPrefix(mantissa, "-"):
+		neg, mantissa = true, mantissa[1:]
+	case strings.HasPrefix(mantissa, "+"):
+		mantissa = mantissa[1:]
+	}
+	whole, frac, _ := strings.Cut(mantissa, ".")
+	digits := whole + frac
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return "", errors.New("not a number")
+	}
+	exp -= len(frac)
+
+	digits = strings.TrimLeft(digits, "0")
+	if digits == "" {
+		return "0", nil
+	}
+	trimmed := strings.TrimRight(digits, "0")
+	exp += len(digits) - len(trimmed)
+	digits = trimmed
+	if len(digits) > MaxDecimalDigits {
+		return "", fmt.Errorf("more than %d significant digits", MaxDecimalDigits)
+	}
+
+	var b strings.Builder
+	if neg {
+		b.WriteByte('-')
+	}
+	switch {
+	case exp >= 0:
+		b.WriteString(digits)
+		b.WriteString(strings.Repeat("0", exp))
+	case -exp < len(digits):
+		b.WriteString(digits[:len(digits)+exp])
+		b.WriteByte('.')
+		b.WriteString(digits[len(digits)+exp:])
+	default:
+		b.WriteString("0.")
+		b.WriteString(strings.Repeat("0", -exp-len(digits)))
+		b.WriteString(digits)
+	}
+	return b.String(), nil
+}
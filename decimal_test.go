@@ -0,0 +1,159 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type DecimalInvoice struct {
+	UID      string       `json:"uid,omitempty"`
+	Number   string       `json:"dec_number,omitempty" dgraph:"index=exact upsert"`
+	Total    mg.Decimal   `json:"dec_total,omitempty" dgraph:"index=bigfloat"`
+	Discount *mg.Decimal  `json:"dec_discount,omitempty"`
+	Lines    []mg.Decimal `json:"dec_lines,omitempty"`
+	DType    []string     `json:"dgraph.type,omitempty"`
+}
+
+func TestParseDecimal(t *testing.T) {
+	for in, want := range map[string]string{
+		"19.99":        "19.99",
+		"19.90":        "19.9",
+		"+007":         "7",
+		"-0.000":       "0",
+		"1.5e3":        "1500",
+		"1.23E-4":      "0.000123",
+		"-12345.678e2": "-1234567.8",
+		".5":           "0.5",
+		"5.":           "5",
+		"-1e-09":       "-0.000000001",
+	} {
+		d, err := mg.ParseDecimal(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, d.String(), in)
+	}
+	for _, in := range []string{"", "-", ".", "abc", "1.2.3", "1e", "1e99999", "0x10", "1_000",
+		"1" + strings.Repeat("1", mg.MaxDecimalDigits)} {
+		_, err := mg.ParseDecimal(in)
+		require.ErrorIs(t, err, mg.ErrInvalidDecimal, in)
+	}
+
+	require.Equal(t, "19.99", mg.NewDecimal(1999, 2).String())
+	require.Equal(t, "-5000", mg.NewDecimal(-5, -3).String())
+	require.Equal(t, mg.MustParseDecimal("19.9"), mg.MustParseDecimal("19.90"))
+	require.Equal(t, -1, mg.MustParseDecimal("9.99").Cmp(mg.MustParseDecimal("10")))
+	require.Equal(t, 0, mg.Decimal{}.Cmp(mg.MustParseDecimal("0")))
+	require.False(t, mg.Decimal{}.IsSet())
+	require.True(t, mg.MustParseDecimal("0").IsSet())
+
+	sum := new(big.Rat).Add(mg.MustParseDecimal("0.1").Rat(), mg.MustParseDecimal("0.2").Rat())
+	d, err := mg.DecimalFromRat(sum)
+	require.NoError(t, err)
+	require.Equal(t, "0.3", d.String())
+	_, err = mg.DecimalFromRat(big.NewRat(1, 3))
+	require.ErrorIs(t, err, mg.ErrInvalidDecimal)
+
+	data, err := json.Marshal(DecimalInvoice{Total: mg.MustParseDecimal("0.10")})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"dec_total":"0.1"}`, string(data))
+	var inv DecimalInvoice
+	data = []byte(`{"dec_total":1.23456789012345678901234567890123456789e+29,"dec_lines":["1",2.5]}`)
+	require.NoError(t, json.Unmarshal(data, &inv))
+	require.Equal(t, "123456789012345678901234567890.123456789", inv.Total.String())
+	require.Equal(t, []mg.Decimal{mg.MustParseDecimal("1"), mg.MustParseDecimal("2.5")}, inv.Lines)
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"dec_total":true}`), &inv), mg.ErrInvalidDecimal)
+}
+
+func TestDecimalFields(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "DecimalFieldsWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "DecimalFieldsWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			ctx := context.Background()
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			discount := mg.MustParseDecimal("0.1")
+			bulk := &DecimalInvoice{
+				Number:   "INV-1",
+				Total:    mg.MustParseDecimal("123456789012345678901234567890.123456789"),
+				Discount: &discount,
+				Lines:    []mg.Decimal{mg.NewDecimal(1, 2), mg.NewDecimal(2, 2)},
+			}
+			small := &DecimalInvoice{Number: "INV-2", Total: mg.NewDecimal(1999, 2)}
+			zero := &DecimalInvoice{Number: "INV-3", Total: mg.MustParseDecimal("0")}
+			require.NoError(t, client.Insert(ctx, &[]*DecimalInvoice{bulk, small, zero}))
+
+			schema, err := client.GetSchema(ctx)
+			require.NoError(t, err)
+			pred, ok := schema.Predicate("dec_total")
+			require.True(t, ok)
+			require.Equal(t, "bigfloat", pred.Type)
+			require.Equal(t, []string{"bigfloat"}, pred.Indexes)
+
+			// Values come back digit for digit.
+			var got DecimalInvoice
+			require.NoError(t, client.Get(ctx, &got, bulk.UID))
+			require.Equal(t, bulk.Total, got.Total)
+			require.Equal(t, discount, *got.Discount)
+			require.ElementsMatch(t, bulk.Lines, got.Lines)
+			got = DecimalInvoice{}
+			require.NoError(t, client.Get(ctx, &got, zero.UID))
+			require.True(t, got.Total.IsSet())
+			require.Equal(t, "0", got.Total.String())
+
+			// Comparisons are numeric and exact.
+			large, err := mg.Query[DecimalInvoice](ctx, client).
+				Filter("gt(dec_total, ?)", mg.MustParseDecimal("19.98")).
+				OrderAsc("dec_total").
+				All()
+			require.NoError(t, err)
+			require.Len(t, large, 2)
+			require.Equal(t, small.Total, large[0].Total)
+			require.Equal(t, bulk.Total, large[1].Total)
+			exact, err := mg.Query[DecimalInvoice](ctx, client).
+				Filter("eq(dec_total, ?)", mg.MustParseDecimal("19.990")).
+				First()
+			require.NoError(t, err)
+			require.NotNil(t, exact)
+			require.Equal(t, "INV-2", exact.Number)
+
+			// An unset Decimal leaves the stored value alone.
+			require.NoError(t, client.Update(ctx, &DecimalInvoice{UID: small.UID, Number: "INV-2b"}))
+			got = DecimalInvoice{}
+			require.NoError(t, client.Get(ctx, &got, small.UID))
+			require.Equal(t, "INV-2b", got.Number)
+			require.Equal(t, small.Total, got.Total)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// DeleteOpt configures a single Delete.
+type DeleteOpt func(*deleteOptions)
+
+type deleteOptions struct {
+	cascade bool
+	detach  bool
+	purge   bool
+}
+
+// DeleteCascade makes a Delete of structs also delete the nodes held by
+// their edge fields, and by those nodes' edge fields in turn, as far as they
+// are loaded. Each of those nodes must have its UID set. It has no effect on
+// a Delete of UIDs.
+func DeleteCascade() DeleteOpt {
+	return func(o *deleteOptions) {
+		o.cascade = true
+	}
+}
+
+// DeleteDetach makes Delete also remove the edges of other nodes that point
+// to the deleted ones, through every uid predicate in the schema. Without
+// it, edges not tagged with an ondelete policy are left pointing to nodes
+// that no longer have any predicates.
+func DeleteDetach() DeleteOpt {
+	return func(o *deleteOptions) {
+		o.detach = true
+	}
+}
+
+// DeleteByUID implements deleting nodes by UID.
+func (c client) DeleteByUID(ctx context.Context, uids ...string) error {
+	return c.Delete(ctx, uids)
+}
+
+// deleteTargets returns the UIDs a Delete of obj removes: obj itself when it
+// is a []string, otherwise the UIDs of the structs obj points to, with those
+// held by their edge fields when cascade is set.
+func deleteTargets(obj any, cascade bool) ([]string, error) {
+	if uids, ok := obj.([]string); ok {
+		return uids, nil
+	}
+	obj = UnwrapSchema(obj)
+	v := reflect.ValueOf(obj)
+	if v.Kind() == reflect.Pointer && v.Elem().Kind() == reflect.Slice {
+		v = v.Elem()
+	}
+	var roots []reflect.Value
+	switch {
+	case v.Kind() == reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			roots = append(roots, v.Index(i))
+		}
+	case v.Kind() == reflect.Pointer && v.Elem().Kind() == reflect.Struct:
+		roots = append(roots, v)
+	default:
+		return nil, fmt.Errorf("modusgraph: Delete takes UIDs or pointers to structs, not %T", obj)
+	}
+
+	var uids []string
+	seen := make(map[string]bool)
+	var visit func(v reflect.Value, depth int) error
+	visit = func(v reflect.Value, depth int) error {
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			if depth == 0 {
+				return fmt.Errorf("modusgraph: Delete takes UIDs or pointers to structs, not %s", v.Type())
+			}
+			return nil
+		}
+		if depth > 0 && v.IsZero() {
+			// An edge held by value that was not loaded.
+			return nil
+		}
+		uid := v.FieldByName("UID")
+		if !uid.IsValid() || uid.Kind() != reflect.String {
+			if depth == 0 {
+				return fmt.Errorf("modusgraph: %s has no UID field", v.Type())
+			}
+			return nil
+		}
+		if uid.String() == "" {
+			return fmt.Errorf("modusgraph: cannot delete a %s without a UID", v.Type())
+		}
+		if seen[uid.String()] {
+			return nil
+		}
+		seen[uid.String()] = true
+		uids = append(uids, uid.String())
+		if !cascade {
+			return nil
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() || f.Anonymous || fieldPredicate(f) == "" {
+				continue
+			}
+			fv := v.Field(i)
+			if fv.Kind() == reflect.Slice {
+				for j := 0; j < fv.Len(); j++ {
+					if err := visit(fv.Index(j), depth+1); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if err := visit(fv, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, root := range roots {
+		if err := visit(root, 0); err != nil {
+			return nil, err
+		}
+	}
+	return uids, nil
+}
@@ -7,6 +7,7 @@ package modusgraph_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
@@ -15,6 +16,7 @@ import (
 	"time"
 
 	"github.com/dgraph-io/dgo/v250/protos/api"
+	mg "github.com/matthewmcneely/modusgraph"
 	"github.com/stretchr/testify/require"
 )
 
@@ -189,3 +191,103 @@ func TestDeletePredicate(t *testing.T) {
 		})
 	}
 }
+
+type DelChapter struct {
+	UID   string   `json:"uid,omitempty"`
+	Title string   `json:"del_chapter_title,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type DelVolume struct {
+	UID      string        `json:"uid,omitempty"`
+	Title    string        `json:"del_volume_title,omitempty"`
+	Chapters []*DelChapter `json:"del_volume_chapters,omitempty"`
+	DType    []string      `json:"dgraph.type,omitempty"`
+}
+
+type DelShelf struct {
+	UID     string       `json:"uid,omitempty"`
+	Volumes []*DelVolume `json:"del_shelf_volumes,omitempty"`
+	DType   []string     `json:"dgraph.type,omitempty"`
+}
+
+func TestDeleteByStruct(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "DeleteByStructWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "DeleteByStructWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+
+			count := func(q string) int {
+				resp, err := client.QueryRaw(ctx, q, nil)
+				require.NoError(t, err)
+				var result struct {
+					Q []struct {
+						N int `json:"n"`
+					} `json:"q"`
+				}
+				require.NoError(t, json.Unmarshal(resp, &result))
+				if len(result.Q) == 0 {
+					return 0
+				}
+				return result.Q[0].N
+			}
+			chapters := func() int { return count(`{ q(func: type(DelChapter)) { n: count(uid) } }`) }
+			newVolume := func(title string) *DelVolume {
+				return &DelVolume{Title: title, Chapters: []*DelChapter{{Title: title + " 1"}, {Title: title + " 2"}}}
+			}
+
+			plain, cascaded, detached := newVolume("plain"), newVolume("cascaded"), newVolume("detached")
+			shelf := &DelShelf{Volumes: []*DelVolume{plain, cascaded, detached}}
+			require.NoError(t, client.Insert(ctx, shelf))
+			require.Equal(t, 6, chapters())
+			shelfVolumes := func() int {
+				return count(fmt.Sprintf(`{ q(func: uid(%s)) { n: count(del_shelf_volumes) } }`, shelf.UID))
+			}
+
+			// A struct deletes its own node only, leaving the shelf's edge.
+			require.NoError(t, client.Delete(ctx, plain))
+			var got DelVolume
+			require.Error(t, client.Get(ctx, &got, plain.UID))
+			require.Equal(t, 6, chapters())
+			require.Equal(t, 3, shelfVolumes())
+
+			// DeleteCascade takes the loaded chapters with it.
+			require.NoError(t, client.Delete(ctx, cascaded, mg.DeleteCascade()))
+			require.Equal(t, 4, chapters())
+
+			// DeleteDetach removes the edges pointing to the volume.
+			require.NoError(t, mg.Delete(ctx, client, detached, mg.DeleteDetach()))
+			require.Equal(t, 2, shelfVolumes())
+			require.Equal(t, 4, chapters())
+
+			require.NoError(t, client.DeleteByUID(ctx, plain.Chapters[0].UID, plain.Chapters[1].UID))
+			require.Equal(t, 2, chapters())
+
+			require.ErrorContains(t, client.Delete(ctx, &DelChapter{}),
+				"cannot delete a modusgraph_test.DelChapter without a UID")
+			require.ErrorContains(t, client.Delete(ctx, 42), "Delete takes UIDs or pointers to structs, not int")
+		})
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dgraph-io/dgo/v250"
+)
+
+// ErrReadOnly is returned by every write made through a client configured
+// WithReadOnly.
+var ErrReadOnly = errors.New("modusgraph: client is read-only")
+
+// WithTimeout bounds each client call to d, unless the caller's context
+// already has an earlier deadline. Zero, the default, adds no deadline. The
+// query builder returned by Query and the Changelog stream are not bounded,
+// since they outlive the call that creates them.
+func WithTimeout(d time.Duration) ClientOpt {
+	return func(o *clientOptions) {
+		o.timeout = d
+	}
+}
+
+// WithReadOnly makes every write through the client fail with ErrReadOnly
+// before it reaches the database: inserts, updates, upserts, deletes,
+// imports and loads, schema and index changes, drops, and integrity repairs.
+// The raw connection returned by DgraphClient is not restricted.
+func WithReadOnly() ClientOpt {
+	return func(o *clientOptions) {
+		o.readOnly = true
+	}
+}
+
+// With returns a client derived from c that shares its connection or
+// embedded engine, its changelog and its admission limits, with opts applied
+// on top of c's options. It is cheap enough to create per tenant or per
+// request:
+//
+//	tenant, err := client.With(modusgraph.WithNamespace("2"), modusgraph.WithReadOnly())
+//
+// Options that shape individual calls take effect: namespace (embedded
+// clients only), timeout, read-only mode, time location, logger, validator,
+// computed fields, zero-value predicates, expansion limits, schema mode and
+// auto-schema, and upsert retries. Options fixed when the connection opens
+// keep c's values: pool and cache size, gRPC dial options, credentials and
+// TLS settings, circuit breakers, admission limits, changelog, strict
+// predicates, the checked schema version, history retention and the clock.
+// Closing a derived client does nothing; close the client it came from.
+func (c client) With(opts ...ClientOpt) (Client, error) {
+	options := c.options
+	options.computes = append([]compute(nil), options.computes...)
+	options.includeZero = append([]string(nil), options.includeZero...)
+	for _, opt := range opts {
+		opt(&options)
+	}
+	fixed := c.options
+	options.poolSize = fixed.poolSize
+	options.cacheSizeMB = fixed.cacheSizeMB
+	options.maxRecvMsgSize = fixed.maxRecvMsgSize
+	options.grpcDialOptions = fixed.grpcDialOptions
+	options.apiKey = fixed.apiKey
+	options.bearerToken = fixed.bearerToken
+	options.login = fixed.login
+	options.tlsConfig = fixed.tlsConfig
+	options.breakerFailures = fixed.breakerFailures
+	options.breakerCooldown = fixed.breakerCooldown
+	options.maxConcurrentQueries = fixed.maxConcurrentQueries
+	options.maxConcurrentMutations = fixed.maxConcurrentMutations
+	options.maxQueueDepth = fixed.maxQueueDepth
+	options.queueTimeout = fixed.queueTimeout
+	options.changelogDir = fixed.changelogDir
+	options.strictPredicates = fixed.strictPredicates
+	options.schemaVersion = fixed.schemaVersion
+	options.historyRetention = fixed.historyRetention
+	options.now = fixed.now
+
+	derived := c
+	derived.options = options
+	derived.logger = options.logger
+	derived.derived = true
+
+	if options.namespace == fixed.namespace {
+		return derived, nil
+	}
+	if c.engine == nil {
+		derived.logger.Info("Warning, namespace is set, but it is not supported in this version")
+		return derived, nil
+	}
+	ns := c.engine.GetDefaultNamespace()
+	if options.namespace != "" {
+		nsID, err := parseNamespaceID(options.namespace)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace ID %q: %w", options.namespace, err)
+		}
+		if ns, err = c.engine.GetNamespace(nsID); err != nil {
+			return nil, fmt.Errorf("failed to get namespace %d: %w", nsID, err)
+		}
+	}
+	derived.ns = ns
+	derived.pool = newClientPool(1, func() (*dgo.Dgraph, error) {
+		embeddedClient := newEmbeddedDgraphClient(c.engine, ns)
+		embeddedClient.admission = c.admission
+		embeddedClient.changes = c.changes
+		embeddedClient.now = options.clock()
+		embeddedClient.predicates = c.predicates
+		embeddedClient.planner = newQueryPlanner(options, namespaceTokenizers(ns), namespaceStats(ns))
+		//nolint:staticcheck // dgo.NewDgraphClient is deprecated but required for embedded client
+		return dgo.NewDgraphClient(embeddedClient), nil
+	}, derived.logger)
+	return derived, nil
+}
+
+// withTimeout bounds ctx by the client's WithTimeout.
+func (c client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.options.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.options.timeout)
+}
+
+// writable returns ErrReadOnly when the client is read-only.
+func (c client) writable() error {
+	if c.options.readOnly {
+		return ErrReadOnly
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type DerivedNote struct {
+	UID   string   `json:"uid,omitempty"`
+	Text  string   `json:"dnote_text,omitempty" dgraph:"index=exact"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+func TestDerivedClient(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "DerivedClientWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "DerivedClientWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			ctx := context.Background()
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			note := &DerivedNote{Text: "shared"}
+			require.NoError(t, client.Insert(ctx, note))
+
+			// A read-only view reads the same data and refuses every write.
+			ro, err := client.With(mg.WithReadOnly())
+			require.NoError(t, err)
+			var got DerivedNote
+			require.NoError(t, ro.Get(ctx, &got, note.UID))
+			require.Equal(t, "shared", got.Text)
+			require.ErrorIs(t, ro.Insert(ctx, &DerivedNote{Text: "blocked"}), mg.ErrReadOnly)
+			require.ErrorIs(t, ro.Update(ctx, &got), mg.ErrReadOnly)
+			require.ErrorIs(t, ro.Delete(ctx, []string{note.UID}), mg.ErrReadOnly)
+			require.ErrorIs(t, ro.DropAll(ctx), mg.ErrReadOnly)
+			_, err = ro.CheckIntegrity(ctx, mg.IntegrityOptions{Repair: true})
+			require.ErrorIs(t, err, mg.ErrReadOnly)
+
+			// Closing a derived client leaves its parent open.
+			ro.Close()
+			require.NoError(t, client.Insert(ctx, &DerivedNote{Text: "after close"}))
+
+			// A derived timeout bounds calls that would otherwise run unbounded.
+			slow, err := client.With(mg.WithTimeout(time.Nanosecond))
+			require.NoError(t, err)
+			time.Sleep(time.Millisecond)
+			require.Error(t, slow.Get(ctx, &got, note.UID))
+			generous, err := slow.With(mg.WithTimeout(time.Minute))
+			require.NoError(t, err)
+			require.NoError(t, generous.Get(ctx, &got, note.UID))
+
+			// A derived logger receives the derived client's messages only.
+			var mu sync.Mutex
+			var lines []string
+			logger := funcr.New(func(prefix, args string) {
+				mu.Lock()
+				defer mu.Unlock()
+				lines = append(lines, args)
+			}, funcr.Options{Verbosity: 2})
+			logged, err := client.With(mg.WithLogger(logger))
+			require.NoError(t, err)
+			require.NoError(t, logged.Insert(ctx, &DerivedNote{Text: "logged"}))
+			require.NoError(t, client.Insert(ctx, &DerivedNote{Text: "quiet"}))
+			mu.Lock()
+			require.Equal(t, 1, strings.Count(strings.Join(lines, "\n"), "Insert successful"))
+			mu.Unlock()
+
+			if strings.HasPrefix(tc.uri, "file://") {
+				galaxy, err := client.With(mg.WithNamespace("0"))
+				require.NoError(t, err)
+				uids, err := galaxy.UIDs(ctx, DerivedNote{}, "eq(dnote_text, ?)", "shared")
+				require.NoError(t, err)
+				require.Len(t, uids, 1)
+				_, err = client.With(mg.WithNamespace("999"))
+				require.Error(t, err)
+				_, err = client.With(mg.WithNamespace("tenant-a"))
+				require.ErrorContains(t, err, "invalid namespace ID")
+			}
+		})
+	}
+}
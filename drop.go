@@ -0,0 +1,131 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+)
+
+var (
+	// ErrDropNotConfirmed is returned by DropPredicate and DropType when
+	// DropOptions.Confirm is not set.
+	ErrDropNotConfirmed = errors.New("modusgraph: drop not confirmed; set DropOptions.Confirm")
+	// ErrDropHasData is returned by DropPredicate and DropType when nodes
+	// still use the schema element and DropOptions.Force is not set.
+	ErrDropHasData = errors.New("modusgraph: schema element is in use; set DropOptions.Force to drop it anyway")
+)
+
+// DropOptions guards DropPredicate and DropType.
+type DropOptions struct {
+	// Confirm must be set for the drop to happen, so that a zero value
+	// passed by mistake never removes anything.
+	Confirm bool
+	// Force drops the element even when nodes still use it.
+	Force bool
+}
+
+// DropPredicate implements removing a predicate and all its values from the
+// schema and data. It returns the number of nodes holding the predicate.
+func (c client) DropPredicate(ctx context.Context, name string, opts DropOptions) (int, error) {
+	if err := c.writable(); err != nil {
+		return 0, err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	if isInternalName(name) {
+		return 0, fmt.Errorf("modusgraph: predicate %s is internal and cannot be dropped", name)
+	}
+	schema, err := c.GetSchema(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := schema.Predicate(name); !ok {
+		return 0, fmt.Errorf("modusgraph: predicate %s does not exist", name)
+	}
+	count, err := c.countNodes(ctx, fmt.Sprintf("has(<%s>)", name))
+	if err != nil {
+		return 0, err
+	}
+	if err := checkDrop("predicate", name, count, opts); err != nil {
+		return count, err
+	}
+	return count, c.alter(ctx, &api.Operation{DropAttr: name})
+}
+
+// DropType implements removing a node type definition. Dgraph keeps the
+// nodes and their predicates; only the type and its field list go away. It
+// returns the number of nodes of the type.
+func (c client) DropType(ctx context.Context, name string, opts DropOptions) (int, error) {
+	if err := c.writable(); err != nil {
+		return 0, err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	if isInternalName(name) {
+		return 0, fmt.Errorf("modusgraph: type %s is internal and cannot be dropped", name)
+	}
+	schema, err := c.GetSchema(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := schema.Type(name); !ok {
+		return 0, fmt.Errorf("modusgraph: type %s does not exist", name)
+	}
+	count, err := c.countNodes(ctx, fmt.Sprintf("type(<%s>)", name))
+	if err != nil {
+		return 0, err
+	}
+	if err := checkDrop("type", name, count, opts); err != nil {
+		return count, err
+	}
+	return count, c.alter(ctx, &api.Operation{DropOp: api.Operation_TYPE, DropValue: name})
+}
+
+func checkDrop(kind, name string, count int, opts DropOptions) error {
+	if !opts.Confirm {
+		return ErrDropNotConfirmed
+	}
+	if count > 0 && !opts.Force {
+		return fmt.Errorf("%w: %s %s is used by %d nodes", ErrDropHasData, kind, name, count)
+	}
+	return nil
+}
+
+// countNodes returns the number of nodes matched by a root function.
+func (c client) countNodes(ctx context.Context, fn string) (int, error) {
+	raw, err := c.QueryRaw(ctx, fmt.Sprintf("{ q(func: %s) { n: count(uid) } }", fn), nil)
+	if err != nil {
+		return 0, err
+	}
+	var resp struct {
+		Q []struct {
+			N int `json:"n"`
+		} `json:"q"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return 0, err
+	}
+	if len(resp.Q) == 0 {
+		return 0, nil
+	}
+	return resp.Q[0].N, nil
+}
+
+func (c client) alter(ctx context.Context, op *api.Operation) error {
+	dgClient, err := c.pool.get()
+	if err != nil {
+		c.log(ctx).Error(err, "Failed to get client from pool")
+		return err
+	}
+	defer c.pool.put(dgClient)
+
+	return dgClient.Alter(ctx, op)
+}
@@ -0,0 +1,113 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDropPredicateAndType(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "DropWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "DropWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+
+			require.NoError(t, client.AlterSchema(ctx, `
+gadget_name: string @index(exact) .
+gadget_color: string .
+gadget_unused: int .
+type Gadget {
+	gadget_name
+	gadget_color
+}
+`))
+			dg, dgCleanup, err := client.DgraphClient()
+			require.NoError(t, err)
+			defer dgCleanup()
+			_, err = dg.NewTxn().Mutate(ctx, &api.Mutation{
+				SetJson: []byte(`[
+					{"gadget_name": "a", "gadget_color": "red", "dgraph.type": "Gadget"},
+					{"gadget_name": "b", "dgraph.type": "Gadget"}
+				]`),
+				CommitNow: true,
+			})
+			require.NoError(t, err)
+
+			// Nothing happens without Confirm.
+			_, err = client.DropPredicate(ctx, "gadget_unused", mg.DropOptions{})
+			require.ErrorIs(t, err, mg.ErrDropNotConfirmed)
+
+			// An unused predicate drops without Force.
+			n, err := client.DropPredicate(ctx, "gadget_unused", mg.DropOptions{Confirm: true})
+			require.NoError(t, err)
+			require.Zero(t, n)
+
+			// A predicate holding data is refused, reporting how many nodes use it.
+			n, err = client.DropPredicate(ctx, "gadget_color", mg.DropOptions{Confirm: true})
+			require.ErrorIs(t, err, mg.ErrDropHasData)
+			require.Equal(t, 1, n)
+			schema, err := client.GetSchema(ctx)
+			require.NoError(t, err)
+			_, ok := schema.Predicate("gadget_color")
+			require.True(t, ok, "refused drop leaves the predicate")
+
+			n, err = client.DropPredicate(ctx, "gadget_color", mg.DropOptions{Confirm: true, Force: true})
+			require.NoError(t, err)
+			require.Equal(t, 1, n)
+
+			n, err = client.DropType(ctx, "Gadget", mg.DropOptions{Confirm: true})
+			require.ErrorIs(t, err, mg.ErrDropHasData)
+			require.Equal(t, 2, n)
+			n, err = client.DropType(ctx, "Gadget", mg.DropOptions{Confirm: true, Force: true})
+			require.NoError(t, err)
+			require.Equal(t, 2, n)
+
+			schema, err = client.GetSchema(ctx)
+			require.NoError(t, err)
+			_, ok = schema.Predicate("gadget_color")
+			require.False(t, ok)
+			_, ok = schema.Predicate("gadget_unused")
+			require.False(t, ok)
+			_, ok = schema.Type("Gadget")
+			require.False(t, ok)
+			_, ok = schema.Predicate("gadget_name")
+			require.True(t, ok, "dropping a type keeps its predicates")
+
+			_, err = client.DropType(ctx, "Gadget", mg.DropOptions{Confirm: true})
+			require.ErrorContains(t, err, "does not exist")
+			_, err = client.DropPredicate(ctx, "dgraph.type", mg.DropOptions{Confirm: true, Force: true})
+			require.ErrorContains(t, err, "internal")
+		})
+	}
+}
@@ -0,0 +1,438 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/dgo/v250"
+	"github.com/dgraph-io/dgo/v250/protos/api"
+)
+
+const (
+	// dumpFormat and dumpVersion identify a Dump container. Restore reads
+	// every version up to dumpVersion; later versions are rejected.
+	dumpFormat  = "modusgraph.dump"
+	dumpVersion = 1
+
+	// dumpPageSize is how many nodes Dump reads per query, and
+	// restoreBatchSize how many records Restore writes per mutation.
+	dumpPageSize     = 1000
+	restoreBatchSize = 1000
+)
+
+// ErrInvalidDump is returned by Restore for input that is not a complete
+// Dump container of a supported version.
+var ErrInvalidDump = errors.New("modusgraph: invalid dump")
+
+// A Dump container is a stream of JSON values: one dumpHeader, then one
+// dumpRecord per node and predicate (and language), then one dumpTrailer.
+
+// dumpHeader opens a container with the schema, in Dgraph Schema Definition
+// Language, that its records are written under.
+type dumpHeader struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	Schema    string    `json:"schema"`
+}
+
+// dumpRecord holds the values of one predicate of one node. Node is the
+// node's alias, its UID in the dumped database; references to other nodes
+// use their alias too.
+type dumpRecord struct {
+	Node      string      `json:"node"`
+	Predicate string      `json:"predicate"`
+	Lang      string      `json:"lang,omitempty"`
+	Values    []dumpValue `json:"values"`
+}
+
+// dumpValue is one scalar Value or one edge to the node aliased Ref, with its
+// facets.
+type dumpValue struct {
+	Value  any            `json:"value,omitempty"`
+	Ref    string         `json:"ref,omitempty"`
+	Facets map[string]any `json:"facets,omitempty"`
+}
+
+// dumpTrailer closes a container with its record count, so that a truncated
+// dump is told apart from a complete one.
+type dumpTrailer struct {
+	End struct {
+		Records int `json:"records"`
+	} `json:"end"`
+}
+
+// Dump writes the schema, types and data of the database to w as a
+// self-describing container that Restore reads back into any modusGraph
+// database, embedded or remote. It is the canonical programmatic backup
+// format: the container is versioned, and later releases keep restoring
+// earlier versions.
+//
+// Nodes are identified by their UID in the dumped database and receive new
+// UIDs on restore. Edge and value facets are kept, as are language-tagged
+// values, though facets on language-tagged values are not. Password
+// predicates cannot be read and are restored without values. Dump reads
+// through one read-only transaction, a consistent snapshot on Dgraph
+// clusters; writes to an embedded database while it runs may or may not be
+// included.
+func (c client) Dump(ctx context.Context, w io.Writer) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	schema, err := c.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+	dg, cleanup, err := c.DgraphClient()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	txn := dg.NewReadOnlyTxn()
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(dumpHeader{
+		Format:    dumpFormat,
+		Version:   dumpVersion,
+		CreatedAt: c.Now().UTC(),
+		Schema:    schema.String(),
+	}); err != nil {
+		return err
+	}
+	preds := append([]PredicateInfo{{Name: "dgraph.type", Type: "string", List: true}}, schema.Predicates...)
+	var trailer dumpTrailer
+	for _, p := range preds {
+		if p.Type == "password" {
+			continue
+		}
+		n, err := dumpPredicate(ctx, txn, p, func(r dumpRecord) error { return enc.Encode(r) })
+		if err != nil {
+			return fmt.Errorf("modusgraph: dump: %s: %w", p.Name, err)
+		}
+		trailer.End.Records += n
+	}
+	return enc.Encode(trailer)
+}
+
+// dumpPredicate passes emit a record for every node with a value of p, a
+// page of nodes at a time, and returns how many it passed.
+func dumpPredicate(ctx context.Context, txn *dgo.Txn, p PredicateInfo, emit func(dumpRecord) error) (int, error) {
+	field := "<" + p.Name + "> @facets"
+	has := "<" + p.Name + ">"
+	switch {
+	case p.Type == "uid":
+		field += " { uid }"
+	case p.Lang:
+		// has(<p>) misses nodes whose only values are language-tagged.
+		field = "<" + p.Name + ">@*"
+		has += "@."
+	}
+	written := 0
+	after := "0x0"
+	for {
+		q := fmt.Sprintf("{ nodes(func: has(%s), first: %d, after: %s) { uid %s } }",
+			has, dumpPageSize, after, field)
+		resp, err := txn.Query(ctx, q)
+		if err != nil {
+			return written, err
+		}
+		var page struct {
+			Nodes []map[string]any `json:"nodes"`
+		}
+		dec := json.NewDecoder(bytes.NewReader(resp.Json))
+		dec.UseNumber()
+		if err := dec.Decode(&page); err != nil {
+			return written, err
+		}
+		for _, node := range page.Nodes {
+			records := dumpNode(node, p)
+			for _, r := range records {
+				if err := emit(r); err != nil {
+					return written, err
+				}
+			}
+			written += len(records)
+		}
+		if len(page.Nodes) < dumpPageSize {
+			return written, nil
+		}
+		after, _ = page.Nodes[len(page.Nodes)-1]["uid"].(string)
+	}
+}
+
+// dumpNode turns the values of p in one node of a query response into
+// records. Facets of a scalar p are "p|facet" members of the node, indexed
+// by position for lists; facets of an edge are members of the edge's target.
+func dumpNode(node map[string]any, p PredicateInfo) []dumpRecord {
+	uid, _ := node["uid"].(string)
+	if p.Lang {
+		var records []dumpRecord
+		for key, v := range node {
+			name, lang, _ := strings.Cut(key, "@")
+			if name != p.Name {
+				continue
+			}
+			records = append(records, dumpRecord{
+				Node:      uid,
+				Predicate: p.Name,
+				Lang:      lang,
+				Values:    dumpValues(v, p, nil),
+			})
+		}
+		return records
+	}
+	facets := map[string]any{}
+	for key, v := range node {
+		if name, facet, ok := strings.Cut(key, "|"); ok && name == p.Name {
+			facets[facet] = v
+		}
+	}
+	values := dumpValues(node[p.Name], p, facets)
+	if len(values) == 0 {
+		return nil
+	}
+	return []dumpRecord{{Node: uid, Predicate: p.Name, Values: values}}
+}
+
+// dumpValues flattens a single or list value of p into dumpValues.
+func dumpValues(v any, p PredicateInfo, facets map[string]any) []dumpValue {
+	items, ok := v.([]any)
+	if !ok || p.Type == "float32vector" && !p.List {
+		if v == nil {
+			return nil
+		}
+		items = []any{v}
+	}
+	values := make([]dumpValue, 0, len(items))
+	for i, item := range items {
+		var dv dumpValue
+		if p.Type == "uid" {
+			edge, _ := item.(map[string]any)
+			dv.Ref, _ = edge["uid"].(string)
+			for key, f := range edge {
+				if name, facet, ok := strings.Cut(key, "|"); ok && name == p.Name {
+					if dv.Facets == nil {
+						dv.Facets = map[string]any{}
+					}
+					dv.Facets[facet] = f
+				}
+			}
+		} else {
+			dv.Value = item
+			if p.Type == "float32vector" {
+				// Mutations take vectors in their string form.
+				vec, _ := json.Marshal(item)
+				dv.Value = string(vec)
+			}
+			for facet, f := range facets {
+				if byIndex, ok := f.(map[string]any); ok {
+					f, ok = byIndex[fmt.Sprint(i)]
+					if !ok {
+						continue
+					}
+				}
+				if dv.Facets == nil {
+					dv.Facets = map[string]any{}
+				}
+				dv.Facets[facet] = f
+			}
+		}
+		values = append(values, dv)
+	}
+	return values
+}
+
+// Backup writes a Dump of the database to w, gzip-compressed. The archive is
+// portable like the container it holds: Restore reads it back into any
+// modusGraph database, embedded or remote, so a backup is how an embedded
+// database moves to a Dgraph cluster.
+func (c client) Backup(ctx context.Context, w io.Writer) error {
+	zw := gzip.NewWriter(w)
+	if err := c.Dump(ctx, zw); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// Restore reads a container written by Dump from r, or a gzip-compressed one
+// written by Backup, applies its schema and writes its nodes, edges and
+// facets, in batches. Every node is created afresh, so restore into an empty
+// database (or namespace) to reproduce the dumped one. It returns
+// ErrInvalidDump, after writing what it read, when the container is
+// truncated or malformed.
+func (c client) Restore(ctx context.Context, r io.Reader) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	r, err := gunzipped(r)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDump, err)
+	}
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var header dumpHeader
+	if err := dec.Decode(&header); err != nil {
+		return fmt.Errorf("%w: reading header: %v", ErrInvalidDump, err)
+	}
+	if header.Format != dumpFormat {
+		return fmt.Errorf("%w: unknown format %q", ErrInvalidDump, header.Format)
+	}
+	if header.Version < 1 || header.Version > dumpVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidDump, header.Version)
+	}
+	if strings.TrimSpace(header.Schema) != "" {
+		if err := c.AlterSchema(ctx, header.Schema); err != nil {
+			return fmt.Errorf("modusgraph: restore: applying schema: %w", err)
+		}
+	}
+
+	dg, cleanup, err := c.DgraphClient()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	rs := &restorer{dgo: dg, uids: map[string]string{}}
+	read := 0
+	batch := make([]dumpRecord, 0, restoreBatchSize)
+	for {
+		var line struct {
+			dumpRecord
+			End *struct {
+				Records int `json:"records"`
+			} `json:"end"`
+		}
+		if err := dec.Decode(&line); err != nil {
+			if err == io.EOF {
+				err = errors.New("missing trailer")
+			}
+			if werr := rs.write(ctx, batch); werr != nil {
+				return werr
+			}
+			return fmt.Errorf("%w: after %d records: %v", ErrInvalidDump, read, err)
+		}
+		if line.End != nil {
+			if line.End.Records != read {
+				return fmt.Errorf("%w: trailer counts %d records, read %d", ErrInvalidDump, line.End.Records, read)
+			}
+			return rs.write(ctx, batch)
+		}
+		if line.Node == "" || line.Predicate == "" {
+			return fmt.Errorf("%w: record %d names no node or predicate", ErrInvalidDump, read+1)
+		}
+		read++
+		batch = append(batch, line.dumpRecord)
+		if len(batch) == restoreBatchSize {
+			if err := rs.write(ctx, batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+}
+
+// gunzipped returns a reader of r's content, decompressing it when it starts
+// with the gzip magic number.
+func gunzipped(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		return gzip.NewReader(br)
+	}
+	return br, nil
+}
+
+// restorer writes dump records through a dgo client, remembering the UID
+// assigned to each alias across batches.
+type restorer struct {
+	dgo  *dgo.Dgraph
+	uids map[string]string
+}
+
+// write commits one batch of records as a JSON mutation, then records the
+// UIDs assigned to aliases seen for the first time.
+func (rs *restorer) write(ctx context.Context, records []dumpRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	nodes := make([]map[string]any, len(records))
+	for i, r := range records {
+		node := map[string]any{"uid": rs.resolve(r.Node)}
+		key := r.Predicate
+		if r.Lang != "" {
+			key += "@" + r.Lang
+		}
+		var edges []map[string]any
+		var values []any
+		for j, v := range r.Values {
+			if v.Ref != "" {
+				edge := map[string]any{"uid": rs.resolve(v.Ref)}
+				for facet, f := range v.Facets {
+					edge[r.Predicate+"|"+facet] = f
+				}
+				edges = append(edges, edge)
+				continue
+			}
+			values = append(values, v.Value)
+			for facet, f := range v.Facets {
+				facetKey := r.Predicate + "|" + facet
+				if len(r.Values) == 1 {
+					node[facetKey] = f
+					continue
+				}
+				byIndex, _ := node[facetKey].(map[string]any)
+				if byIndex == nil {
+					byIndex = map[string]any{}
+					node[facetKey] = byIndex
+				}
+				byIndex[fmt.Sprint(j)] = f
+			}
+		}
+		switch {
+		case len(edges) == 1:
+			node[key] = edges[0]
+		case len(edges) > 1:
+			node[key] = edges
+		case len(values) == 1:
+			node[key] = values[0]
+		default:
+			node[key] = values
+		}
+		nodes[i] = node
+	}
+	data, err := json.Marshal(nodes)
+	if err != nil {
+		return err
+	}
+	resp, err := rs.dgo.NewTxn().Mutate(ctx, &api.Mutation{SetJson: data, CommitNow: true})
+	if err != nil {
+		return fmt.Errorf("modusgraph: restore: %w", err)
+	}
+	for name, uid := range resp.Uids {
+		rs.uids[strings.TrimPrefix(name, "_:")] = uid
+	}
+	return nil
+}
+
+// resolve maps an alias to the UID its node was restored under, or to a
+// blank node for Dgraph to assign one.
+func (rs *restorer) resolve(alias string) string {
+	if uid, ok := rs.uids["dump"+alias]; ok {
+		return uid
+	}
+	return "_:dump" + alias
+}
@@ -0,0 +1,180 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpRestore(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "DumpRestoreWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "DumpRestoreWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	const schema = `
+		name: string @index(exact) @lang .
+		born: datetime .
+		tags: [string] .
+		rating: float .
+		location: geo .
+		embedding: float32vector .
+		directed: [uid] @reverse .
+		spouse: uid .
+		type Person {
+			name
+			born
+			location
+			directed
+			spouse
+		}
+		type Film {
+			name
+			tags
+			rating
+			embedding
+		}
+	`
+	const data = `
+		_:scott <name> "Ridley Scott" .
+		_:scott <name> "Sir Ridley Scott"@en .
+		_:scott <born> "1937-11-30T00:00:00Z" .
+		_:scott <location> "{'type':'Point','coordinates':[-0.12,51.5]}"^^<geo:geojson> .
+		_:scott <dgraph.type> "Person" .
+		_:giannini <name> "Giannina Facio" .
+		_:giannini <dgraph.type> "Person" .
+		_:scott <spouse> _:giannini (since=2015) .
+		_:alien <name> "Alien" .
+		_:alien <name> "Alien, le huitième passager"@fr .
+		_:alien <tags> "horror" (weight=0.9) .
+		_:alien <tags> "space" .
+		_:alien <rating> "8.5" .
+		_:alien <embedding> "[0.1, 0.2, 0.3]" .
+		_:alien <dgraph.type> "Film" .
+		_:blade <name> "Blade Runner" .
+		_:blade <dgraph.type> "Film" .
+		_:scott <directed> _:alien (year=1979, lead="Weaver") .
+		_:prometheus <name> "Prométhée"@fr .
+		_:prometheus <dgraph.type> "Film" .
+		_:scott <directed> _:prometheus .
+		_:scott <directed> _:blade (year=1982) .
+	`
+	// The query reads everything back by name, so that it compares across
+	// databases that assigned different UIDs.
+	const query = `{
+		people(func: type(Person), orderasc: name) {
+			name name@en born location dgraph.type
+			spouse @facets { name }
+			directed(orderasc: name) @facets { name name@fr tags @facets rating embedding dgraph.type ~directed { name } }
+		}
+	}`
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, err := mg.NewClient(tc.uri)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = client.DropAll(context.Background())
+				client.Close()
+				mg.Shutdown()
+			})
+			ctx := context.Background()
+
+			dg, cleanup, err := client.DgraphClient()
+			require.NoError(t, err)
+			defer cleanup()
+			require.NoError(t, dg.Alter(ctx, &api.Operation{Schema: schema}))
+			_, err = dg.NewTxn().Mutate(ctx, &api.Mutation{CommitNow: true, SetNquads: []byte(data)})
+			require.NoError(t, err)
+			want, err := client.QueryRaw(ctx, query, nil)
+			require.NoError(t, err)
+
+			var dump bytes.Buffer
+			require.NoError(t, client.Dump(ctx, &dump))
+			lines := strings.Split(strings.TrimSpace(dump.String()), "\n")
+			var header struct {
+				Format  string `json:"format"`
+				Version int    `json:"version"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(lines[0]), &header))
+			require.Equal(t, "modusgraph.dump", header.Format)
+			require.Equal(t, 1, header.Version)
+			require.Contains(t, lines[len(lines)-1], `"end"`)
+
+			// The dump restores into an emptied database with the same
+			// schema, values, facets and edges.
+			require.NoError(t, client.DropAll(ctx))
+			require.NoError(t, client.Restore(ctx, bytes.NewReader(dump.Bytes())))
+			got, err := client.QueryRaw(ctx, query, nil)
+			require.NoError(t, err)
+			require.JSONEq(t, string(want), string(got))
+			sch, err := client.GetSchema(ctx)
+			require.NoError(t, err)
+			name, ok := sch.Predicate("name")
+			require.True(t, ok)
+			require.True(t, name.Lang)
+			_, ok = sch.Type("Film")
+			require.True(t, ok)
+
+			// A truncated dump or one from a later version is rejected.
+			require.NoError(t, client.DropAll(ctx))
+			truncated := strings.Join(lines[:len(lines)-1], "\n")
+			err = client.Restore(ctx, strings.NewReader(truncated))
+			require.ErrorIs(t, err, mg.ErrInvalidDump)
+			require.ErrorContains(t, err, "missing trailer")
+			err = client.Restore(ctx, strings.NewReader(`{"format":"modusgraph.dump","version":99}`))
+			require.ErrorIs(t, err, mg.ErrInvalidDump)
+			err = client.Restore(ctx, strings.NewReader(`{"format":"rdf"}`))
+			require.ErrorIs(t, err, mg.ErrInvalidDump)
+
+			// A Backup archive is the dump, gzipped, and restores the same.
+			require.NoError(t, client.DropAll(ctx))
+			require.NoError(t, client.Restore(ctx, bytes.NewReader(dump.Bytes())))
+			var backup bytes.Buffer
+			require.NoError(t, client.Backup(ctx, &backup))
+			zr, err := gzip.NewReader(bytes.NewReader(backup.Bytes()))
+			require.NoError(t, err)
+			unzipped, err := io.ReadAll(zr)
+			require.NoError(t, err)
+			require.Contains(t, string(unzipped), `"format":"modusgraph.dump"`)
+			require.Less(t, backup.Len(), len(unzipped))
+			require.NoError(t, client.DropAll(ctx))
+			require.NoError(t, client.Restore(ctx, bytes.NewReader(backup.Bytes())))
+			got, err = client.QueryRaw(ctx, query, nil)
+			require.NoError(t, err)
+			require.JSONEq(t, string(want), string(got))
+			err = client.Restore(ctx, bytes.NewReader(backup.Bytes()[:backup.Len()/2]))
+			require.ErrorIs(t, err, mg.ErrInvalidDump)
+		})
+	}
+}
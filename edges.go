@@ -0,0 +1,151 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+
+	"github.com/dgraph-io/dgraph/v25/posting"
+	"github.com/dgraph-io/dgraph/v25/protos/pb"
+	"github.com/dgraph-io/dgraph/v25/x"
+)
+
+// edgesBatchSize is how many edges Edges reads before handing them to the
+// consumer: the embedded engine's read lock is released between batches, and
+// remote clients fetch the edges of this many nodes per query.
+const edgesBatchSize = 10000
+
+// Edge is one value of a uid predicate: an edge from node From to node To.
+type Edge struct {
+	From uint64
+	To   uint64
+}
+
+// Edges iterates the edges of the uid predicate pred, ordered by source
+// node, without holding them all in memory. Embedded clients stream them
+// from storage as of the time Edges is called; remote clients page through
+// has(pred) queries, so they see writes made while iterating. The iteration
+// stops at the first error, which it yields.
+func (c client) Edges(ctx context.Context, pred string) iter.Seq2[Edge, error] {
+	return func(yield func(Edge, error) bool) {
+		schema, err := c.GetSchema(ctx)
+		if err != nil {
+			yield(Edge{}, err)
+			return
+		}
+		if p, ok := schema.Predicate(pred); !ok || p.Type != "uid" {
+			yield(Edge{}, fmt.Errorf("modusgraph: %s is not a uid predicate", pred))
+			return
+		}
+		next := func(ctx context.Context, after uint64) ([]Edge, bool, error) {
+			return c.remoteEdges(ctx, pred, after)
+		}
+		if c.engine != nil {
+			next = c.storedEdges(pred)
+		}
+		var after uint64
+		for {
+			edges, more, err := next(ctx, after)
+			if err != nil {
+				yield(Edge{}, err)
+				return
+			}
+			for _, e := range edges {
+				if !yield(e, nil) {
+					return
+				}
+			}
+			if !more {
+				return
+			}
+			after = edges[len(edges)-1].From
+		}
+	}
+}
+
+// storedEdges returns a reader of the embedded store's edges of pred at the
+// current read timestamp. Each call returns the edges of the nodes after
+// after, stopping at the first node past edgesBatchSize edges.
+func (c client) storedEdges(pred string) func(context.Context, uint64) ([]Edge, bool, error) {
+	readTs := c.engine.z.readTs()
+	attr := x.NamespaceAttr(c.ns.ID(), pred)
+	key := x.DataKey(attr, 0)
+	prefix := key[:len(key)-8]
+	return func(ctx context.Context, after uint64) ([]Edge, bool, error) {
+		if err := c.engine.rlock(ctx); err != nil {
+			return nil, false, err
+		}
+		defer c.engine.mutex.RUnlock()
+		if !c.engine.isOpen.Load() {
+			return nil, false, ErrClosedEngine
+		}
+		var edges []Edge
+		more := false
+		err := posting.MemLayerInstance.IterateDisk(ctx, posting.IterateDiskArgs{
+			Prefix:         prefix,
+			AllVersions:    true,
+			ReadTs:         readTs,
+			StartKey:       x.DataKey(attr, after+1),
+			CheckInclusion: func(uint64) error { return nil },
+			Function: func(l *posting.List, pk x.ParsedKey) error {
+				if len(edges) >= edgesBatchSize {
+					more = true
+					return posting.ErrStopIteration
+				}
+				return l.Iterate(readTs, 0, func(p *pb.Posting) error {
+					edges = append(edges, Edge{From: pk.Uid, To: p.Uid})
+					return nil
+				})
+			},
+		})
+		return edges, more && len(edges) > 0, err
+	}
+}
+
+// remoteEdges returns the edges of pred held by up to edgesBatchSize nodes
+// after after.
+func (c client) remoteEdges(ctx context.Context, pred string, after uint64) ([]Edge, bool, error) {
+	page := fmt.Sprintf("first: %d", edgesBatchSize)
+	if after > 0 {
+		page += ", after: " + string(UIDFromUint64(after))
+	}
+	q := fmt.Sprintf(`{ e(func: has(<%s>), %s) { uid <%s> { uid } } }`, pred, page, pred)
+	data, err := c.QueryRaw(ctx, q, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	var resp struct {
+		E []map[string]json.RawMessage `json:"e"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, false, err
+	}
+	var edges []Edge
+	for _, n := range resp.E {
+		var uid string
+		if err := json.Unmarshal(n["uid"], &uid); err != nil {
+			return nil, false, err
+		}
+		from, ok := parseHexUID(uid)
+		if !ok {
+			return nil, false, fmt.Errorf("%w: %q", ErrInvalidUID, uid)
+		}
+		targets, err := appendUIDs(nil, n[pred])
+		if err != nil {
+			return nil, false, err
+		}
+		for _, to := range targets {
+			edges = append(edges, Edge{From: from, To: to})
+		}
+	}
+	if len(edges) == 0 {
+		return nil, false, nil
+	}
+	return edges, len(resp.E) == edgesBatchSize, nil
+}
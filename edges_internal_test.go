@@ -0,0 +1,62 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEdges(t *testing.T) {
+	c, err := NewClient("file://"+t.TempDir(), WithAutoSchema(true))
+	require.NoError(t, err)
+	defer c.Close()
+	ctx := context.Background()
+	require.NoError(t, c.UpdateSchema(ctx, &SyncFilm{}, &SyncDirector{}))
+
+	films := []*SyncFilm{
+		{Title: "Alien", Director: &SyncDirector{Name: "Scott"}},
+		{Title: "Heat", Director: &SyncDirector{Name: "Mann"}},
+	}
+	require.NoError(t, c.Insert(ctx, films))
+	want := []Edge{
+		{From: UID(films[0].UID).Uint64(), To: UID(films[0].Director.UID).Uint64()},
+		{From: UID(films[1].UID).Uint64(), To: UID(films[1].Director.UID).Uint64()},
+	}
+
+	collect := func() []Edge {
+		var edges []Edge
+		for e, err := range c.Edges(ctx, "director") {
+			require.NoError(t, err)
+			edges = append(edges, e)
+		}
+		return edges
+	}
+	require.ElementsMatch(t, want, collect())
+
+	// A removed edge is gone from storage reads, and remote clients page
+	// through the same edges.
+	dg, cleanup, err := c.DgraphClient()
+	require.NoError(t, err)
+	_, err = dg.NewTxn().Mutate(ctx, &api.Mutation{
+		DelNquads: []byte(`<` + films[1].UID + `> <director> * .`),
+		CommitNow: true,
+	})
+	cleanup()
+	require.NoError(t, err)
+	require.Equal(t, want[:1], collect())
+	edges, more, err := c.(client).remoteEdges(ctx, "director", 0)
+	require.NoError(t, err)
+	require.False(t, more)
+	require.Equal(t, want[:1], edges)
+
+	for _, err := range c.Edges(ctx, "title") {
+		require.ErrorContains(t, err, "not a uid predicate")
+	}
+}
@@ -6,11 +6,13 @@
 package modusgraph
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/dgraph-io/dgo/v250/protos/api"
 	"github.com/dgraph-io/dgraph/v25/x"
@@ -22,6 +24,16 @@ import (
 type embeddedDgraphClient struct {
 	engine *Engine
 	ns     *Namespace
+	// admission gates Query and RunDQL calls; nil admits everything.
+	admission *admissionControl
+	// planner applies WithHint and WithQueryPlanDebug; nil leaves queries as-is.
+	planner *queryPlanner
+	// changes records committed mutations for WithChangelog; nil disables it.
+	changes *changelog
+	// now stamps the mutations changes records; set with changes.
+	now func() time.Time
+	// predicates rejects mutations setting unknown predicates; nil disables it.
+	predicates *predicateGuard
 }
 
 // newEmbeddedDgraphClient creates a new embedded client for the given namespace.
@@ -46,6 +58,19 @@ func (c *embeddedDgraphClient) Query(
 	in *api.Request,
 	opts ...grpc.CallOption,
 ) (*api.Response, error) {
+	release, err := c.admission.admit(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if err := c.predicates.checkNamespace(c.ns, in.Mutations); err != nil {
+		return nil, err
+	}
+	if _, ok := readTsFrom(ctx); ok && len(in.Mutations) > 0 {
+		return nil, ErrWriteAtReadTs
+	}
+
 	// Attach namespace context
 	ctx = x.AttachNamespace(ctx, c.ns.ID())
 
@@ -56,7 +81,7 @@ func (c *embeddedDgraphClient) Query(
 
 	// Simple mutation (no query)
 	if len(in.Mutations) > 0 {
-		uids, err := c.ns.Mutate(ctx, in.Mutations)
+		uids, err := c.mutate(ctx, in.Mutations)
 		if err != nil {
 			return nil, err
 		}
@@ -70,14 +95,25 @@ func (c *embeddedDgraphClient) Query(
 			}
 			uidStrings[key] = fmt.Sprintf("0x%x", v)
 		}
-		return &api.Response{
+		resp := &api.Response{
 			Uids: uidStrings,
 			Txn:  &api.TxnContext{StartTs: in.StartTs},
-		}, nil
+		}
+		// Embedded mutations commit on apply, so they are logged immediately,
+		// unless a Txn stages them.
+		if err := c.record(ctx, in, resp); err != nil {
+			return nil, fmt.Errorf("recording changelog: %w", err)
+		}
+		return resp, nil
 	}
 
-	// Query only
-	return c.engine.query(ctx, c.ns, in.Query, in.Vars)
+	// Query only. A read-only txn reads at the timestamp of its first query,
+	// as it does against Dgraph; the response of a later read at the current
+	// timestamp would fail dgo's StartTs check once a write had landed.
+	if _, ok := readTsFrom(ctx); !ok && in.ReadOnly && in.StartTs != 0 {
+		ctx = ReadAt(ctx, in.StartTs)
+	}
+	return c.engine.query(ctx, c.ns, c.planner.plan(in.Query), in.Vars)
 }
 
 // handleUpsert handles upsert requests (query + mutations) for embedded mode.
@@ -107,7 +143,7 @@ func (c *embeddedDgraphClient) handleUpsert(ctx context.Context, in *api.Request
 	}
 
 	// Step 5: Apply mutations using embedded path
-	uids, err := c.ns.Mutate(ctx, in.Mutations)
+	uids, err := c.mutate(ctx, in.Mutations)
 	if err != nil {
 		return nil, err
 	}
@@ -122,11 +158,15 @@ func (c *embeddedDgraphClient) handleUpsert(ctx context.Context, in *api.Request
 		uidStrings[key] = fmt.Sprintf("0x%x", v)
 	}
 
-	return &api.Response{
+	resp := &api.Response{
 		Json: queryResp.Json,
 		Uids: uidStrings,
 		Txn:  &api.TxnContext{StartTs: in.StartTs},
-	}, nil
+	}
+	if err := c.record(ctx, in, resp); err != nil {
+		return nil, fmt.Errorf("recording changelog: %w", err)
+	}
+	return resp, nil
 }
 
 func (c *embeddedDgraphClient) Alter(
@@ -146,6 +186,12 @@ func (c *embeddedDgraphClient) Alter(
 		}
 		return &api.Payload{}, nil
 	}
+	if in.DropOp == api.Operation_TYPE {
+		if err := c.engine.dropType(ctx, c.ns, in.DropValue); err != nil {
+			return nil, err
+		}
+		return &api.Payload{}, nil
+	}
 	if in.DropAttr != "" {
 		if err := c.engine.dropPredicate(ctx, c.ns, in.DropAttr); err != nil {
 			return nil, err
@@ -165,6 +211,9 @@ func (c *embeddedDgraphClient) CommitOrAbort(
 	in *api.TxnContext,
 	opts ...grpc.CallOption,
 ) (*api.TxnContext, error) {
+	if s := stagedFrom(ctx); s != nil {
+		return in, c.commitStaged(ctx, s, in.Aborted)
+	}
 	return c.engine.commitOrAbort(ctx, c.ns, in)
 }
 
@@ -181,7 +230,12 @@ func (c *embeddedDgraphClient) RunDQL(
 	in *api.RunDQLRequest,
 	opts ...grpc.CallOption,
 ) (*api.Response, error) {
-	return c.engine.query(ctx, c.ns, in.DqlQuery, in.Vars)
+	release, err := c.admission.admit(ctx, dqlRequest(in))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return c.engine.query(ctx, c.ns, c.planner.plan(in.DqlQuery), in.Vars)
 }
 
 func (c *embeddedDgraphClient) AllocateIDs(
@@ -243,11 +297,117 @@ func (c *embeddedDgraphClient) ListNamespaces(
 // varAsRegex matches "varname as uid" patterns in query blocks
 var varAsRegex = regexp.MustCompile(`(\w+)\s+as\s+uid`)
 
+// blockVarRegex matches a query block whose selection binds a uid variable,
+// as in q(func: has(name)) @filter(...) { v as uid }, capturing the block
+// and variable names.
+var blockVarRegex = regexp.MustCompile(`(\w+)\s*\([^{}]*\)[^{}]*\{\s*(\w+)\s+as\s+uid\b`)
+
+// varBlockRegex matches the start of a query block bound to a variable as a
+// whole, as in v as var(func: has(name)), capturing the variable name.
+var varBlockRegex = regexp.MustCompile(`^(\w+)\s+as\s+\w+\s*\(`)
+
+// nameVarBlocks rewrites the blocks of query bound to a variable as a whole,
+// v as var(func: ...) { ... }, into blocks named after the variable that
+// select uid, v(func: ...) { uid ... }, so their results carry the UIDs.
+func nameVarBlocks(query string) string {
+	var b strings.Builder
+	depth := 0
+	for i := 0; i < len(query); {
+		c := query[i]
+		if c == '"' {
+			end := skipString(query, i) + 1
+			b.WriteString(query[i:end])
+			i = end
+			continue
+		}
+		if depth == 1 && (i == 0 || !isIdentByte(query[i-1])) {
+			if m := varBlockRegex.FindStringSubmatchIndex(query[i:]); m != nil {
+				// Keep the arguments and any directives, then select uid.
+				end := closing(query, i+m[1]-1)
+				for {
+					next := end
+					for next < len(query) && strings.ContainsRune(" \t\r\n", rune(query[next])) {
+						next++
+					}
+					if next == len(query) || query[next] != '@' {
+						break
+					}
+					for next++; next < len(query) && isIdentByte(query[next]); next++ {
+					}
+					if next < len(query) && query[next] == '(' {
+						next = closing(query, next)
+					}
+					end = next
+				}
+				b.WriteString(query[i+m[2]:i+m[3]] + query[i+m[1]-1:end])
+				i = end
+				for end < len(query) && strings.ContainsRune(" \t\r\n", rune(query[end])) {
+					end++
+				}
+				if end < len(query) && query[end] == '{' {
+					b.WriteString(" {")
+					if !selectsUID(query[end+1 : closing(query, end)]) {
+						b.WriteString(" uid")
+					}
+					i = end + 1
+					depth++
+				} else {
+					b.WriteString(" { uid }")
+				}
+				continue
+			}
+		}
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+		b.WriteByte(c)
+		i++
+	}
+	return b.String()
+}
+
+// closing returns the index just past the parenthesis or brace that closes
+// the one at query[open], or len(query) when there is none.
+func closing(query string, open int) int {
+	if end := matchParen(query, open); end >= 0 {
+		return end + 1
+	}
+	return len(query)
+}
+
+// selectsUID reports whether the selection body of a block, without its
+// braces, selects uid itself rather than in a nested block.
+func selectsUID(body string) bool {
+	for i := 0; i < len(body); {
+		switch c := body[i]; {
+		case c == '{', c == '(':
+			i = closing(body, i)
+		case c == '"':
+			i = skipString(body, i) + 1
+		case isIdentByte(c):
+			start := i
+			for i < len(body) && isIdentByte(body[i]) {
+				i++
+			}
+			if body[start:i] == "uid" && (i == len(body) || body[i] != '(') {
+				return true
+			}
+		default:
+			i++
+		}
+	}
+	return false
+}
+
 // transformUpsertQuery transforms a dgman upsert query to remove variable definitions.
 // Input:  { q_1_0(...) { u_1_0 as uid } }
 // Output: { q_1_0(...) { uid } } and mapping {"q_1_0": "u_1_0"}
 func transformUpsertQuery(query string) (string, map[string]string) {
 	varMappings := make(map[string]string)
+	query = nameVarBlocks(query)
 
 	// Find all "varname as uid" patterns and extract the variable names
 	// dgman uses pattern: q_N_M for query blocks, u_N_M for uid variables
@@ -262,6 +422,12 @@ func transformUpsertQuery(query string) (string, map[string]string) {
 			}
 		}
 	}
+	// Hand-written upsert blocks name their blocks and variables freely.
+	for _, match := range blockVarRegex.FindAllStringSubmatch(query, -1) {
+		if _, ok := varMappings[match[1]]; !ok {
+			varMappings[match[1]] = match[2]
+		}
+	}
 
 	// Replace "varname as uid" with just "uid"
 	transformedQuery := varAsRegex.ReplaceAllString(query, "uid")
@@ -269,10 +435,12 @@ func transformUpsertQuery(query string) (string, map[string]string) {
 	return transformedQuery, varMappings
 }
 
-// extractVarUIDsWithMapping parses query results and maps block names to variable names
-func extractVarUIDsWithMapping(jsonData []byte, varMappings map[string]string) (map[string]string, error) {
+// extractVarUIDsWithMapping parses query results and maps variable names,
+// or block names for blocks binding none, to the UIDs of the nodes their
+// block matched.
+func extractVarUIDsWithMapping(jsonData []byte, varMappings map[string]string) (map[string][]string, error) {
 	if len(jsonData) == 0 {
-		return make(map[string]string), nil
+		return make(map[string][]string), nil
 	}
 
 	var result map[string][]map[string]interface{}
@@ -280,16 +448,15 @@ func extractVarUIDsWithMapping(jsonData []byte, varMappings map[string]string) (
 		return nil, err
 	}
 
-	varUIDs := make(map[string]string)
+	varUIDs := make(map[string][]string)
 	for blockName, nodes := range result {
-		if len(nodes) > 0 {
-			if uid, ok := nodes[0]["uid"].(string); ok {
-				// Map block name to variable name
-				if varName, ok := varMappings[blockName]; ok {
-					varUIDs[varName] = uid
-				} else {
-					varUIDs[blockName] = uid
-				}
+		name := blockName
+		if varName, ok := varMappings[blockName]; ok {
+			name = varName
+		}
+		for _, node := range nodes {
+			if uid, ok := node["uid"].(string); ok {
+				varUIDs[name] = append(varUIDs[name], uid)
 			}
 		}
 	}
@@ -299,8 +466,12 @@ func extractVarUIDsWithMapping(jsonData []byte, varMappings map[string]string) (
 // uidVarRegex matches uid(varname) patterns in mutation data
 var uidVarRegex = regexp.MustCompile(`uid\(([^)]+)\)`)
 
-// substituteUIDVars replaces uid(var) references in mutations with actual UIDs
-func substituteUIDVars(mu *api.Mutation, varUIDs map[string]string) {
+// substituteUIDVars replaces uid(var) references in mutations with actual
+// UIDs. JSON and structured N-Quads take the first UID of each variable.
+// RDF text repeats each N-Quad for every UID. When the variable matched
+// none, a set N-Quad names a new node and a delete N-Quad is dropped, as
+// Dgraph does.
+func substituteUIDVars(mu *api.Mutation, varUIDs map[string][]string) {
 	// Handle SetJson
 	if len(mu.SetJson) > 0 {
 		mu.SetJson = []byte(substituteInString(string(mu.SetJson), varUIDs))
@@ -309,6 +480,12 @@ func substituteUIDVars(mu *api.Mutation, varUIDs map[string]string) {
 	if len(mu.DeleteJson) > 0 {
 		mu.DeleteJson = []byte(substituteInString(string(mu.DeleteJson), varUIDs))
 	}
+	if len(mu.SetNquads) > 0 {
+		mu.SetNquads = expandNquadVars(mu.SetNquads, varUIDs, true)
+	}
+	if len(mu.DelNquads) > 0 {
+		mu.DelNquads = expandNquadVars(mu.DelNquads, varUIDs, false)
+	}
 	// Handle Set NQuads
 	for _, nq := range mu.Set {
 		substituteInNQuad(nq, varUIDs)
@@ -319,31 +496,67 @@ func substituteUIDVars(mu *api.Mutation, varUIDs map[string]string) {
 	}
 }
 
-func substituteInString(s string, varUIDs map[string]string) string {
+func substituteInString(s string, varUIDs map[string][]string) string {
 	return uidVarRegex.ReplaceAllStringFunc(s, func(match string) string {
 		// Extract variable name from uid(varname)
 		varName := match[4 : len(match)-1] // strip "uid(" and ")"
-		if uid, ok := varUIDs[varName]; ok {
-			return uid
+		if uids := varUIDs[varName]; len(uids) > 0 {
+			return uids[0]
 		}
 		// If no UID found, convert to blank node for new entity
 		return "_:uid(" + varName + ")"
 	})
 }
 
-func substituteInNQuad(nq *api.NQuad, varUIDs map[string]string) {
+func substituteInNQuad(nq *api.NQuad, varUIDs map[string][]string) {
 	// Substitute in Subject
 	if strings.HasPrefix(nq.Subject, "uid(") {
 		varName := nq.Subject[4 : len(nq.Subject)-1]
-		if uid, ok := varUIDs[varName]; ok {
-			nq.Subject = uid
+		if uids := varUIDs[varName]; len(uids) > 0 {
+			nq.Subject = uids[0]
 		}
 	}
 	// Substitute in ObjectId
 	if strings.HasPrefix(nq.ObjectId, "uid(") {
 		varName := nq.ObjectId[4 : len(nq.ObjectId)-1]
-		if uid, ok := varUIDs[varName]; ok {
-			nq.ObjectId = uid
+		if uids := varUIDs[varName]; len(uids) > 0 {
+			nq.ObjectId = uids[0]
+		}
+	}
+}
+
+// expandNquadVars rewrites RDF text whose subjects or objects are uid(var)
+// references into one line per UID the variables hold. A variable holding
+// none becomes the blank node _:uid.<var> in set text, so its lines create
+// one new node as in JSON, and drops its lines from delete text.
+func expandNquadVars(data []byte, varUIDs map[string][]string, set bool) []byte {
+	var out bytes.Buffer
+	for line := range bytes.Lines(data) {
+		lines := []string{string(line)}
+		fields := strings.Fields(string(line))
+		for _, i := range []int{0, 2} {
+			if i >= len(fields) || !uidVarRegex.MatchString(fields[i]) || !strings.HasPrefix(fields[i], "uid(") {
+				continue
+			}
+			ref := fields[i][:strings.IndexByte(fields[i], ')')+1]
+			name := ref[4 : len(ref)-1]
+			var expanded []string
+			for _, l := range lines {
+				if len(varUIDs[name]) == 0 && set {
+					expanded = append(expanded, strings.Replace(l, ref, "_:uid."+name, 1))
+				}
+				for _, uid := range varUIDs[name] {
+					expanded = append(expanded, strings.Replace(l, ref, "<"+uid+">", 1))
+				}
+			}
+			lines = expanded
+		}
+		for _, l := range lines {
+			out.WriteString(l)
+			if !strings.HasSuffix(l, "\n") {
+				out.WriteByte('\n')
+			}
 		}
 	}
+	return out.Bytes()
 }
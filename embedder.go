@@ -0,0 +1,145 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// Embedder turns text into a float32 vector. It is the minimal contract used by
+// WithEmbedder; any EmbeddingProvider (including OpenAICompatibleProvider, which
+// covers OpenAI and Ollama) is also an Embedder, and a local model such as an
+// ONNX runtime only needs to implement this single method. A client with an
+// EmbeddingProvider but no Embedder uses the provider.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+// vectorEmbedder returns the Embedder that fills embed=from fields: the one
+// set with WithEmbedder, else the WithEmbeddingProvider provider, else nil.
+func (o clientOptions) vectorEmbedder() Embedder {
+	if o.embedder != nil {
+		return o.embedder
+	}
+	if o.embeddingProvider != nil {
+		return o.embeddingProvider
+	}
+	return nil
+}
+
+// embedTagPrefix introduces the source predicate of an embedded vector field,
+// e.g. `dgraph:"embed=from:description index=hnsw(metric:\"cosine\")"`.
+const embedTagPrefix = "embed=from:"
+
+var vectorFloat32Type = reflect.TypeOf(dg.VectorFloat32{})
+
+// parseEmbedTag returns the source predicate named by an embed=from:<predicate>
+// option in a dgraph struct tag. Options may be separated by spaces (as dgman
+// expects) or commas.
+func parseEmbedTag(tag string) (string, bool) {
+	for _, part := range strings.FieldsFunc(tag, func(r rune) bool { return r == ' ' || r == ',' }) {
+		if strings.HasPrefix(part, embedTagPrefix) {
+			source := strings.TrimPrefix(part, embedTagPrefix)
+			return source, source != ""
+		}
+	}
+	return "", false
+}
+
+// embedTargets lists the structs in obj (pointer to struct, or slice of
+// pointers to structs) that embedded vector fields should be populated on.
+func embedTargets(obj any) []reflect.Value {
+	val := reflect.ValueOf(obj)
+	for val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+	switch val.Kind() {
+	case reflect.Struct:
+		return []reflect.Value{val}
+	case reflect.Slice:
+		structs := make([]reflect.Value, 0, val.Len())
+		for i := 0; i < val.Len(); i++ {
+			elem := val.Index(i)
+			for elem.Kind() == reflect.Ptr && !elem.IsNil() {
+				elem = elem.Elem()
+			}
+			if elem.Kind() == reflect.Struct {
+				structs = append(structs, elem)
+			}
+		}
+		return structs
+	}
+	return nil
+}
+
+// applyEmbeddings fills every dg.VectorFloat32 (or *dg.VectorFloat32) field
+// tagged embed=from:<predicate> by embedding the text of the named source field,
+// so the vector is written in the same mutation as the node. A source left empty
+// is skipped: with omitempty it is absent from the mutation, and re-embedding
+// the empty string would overwrite the vector stored by an earlier write.
+func applyEmbeddings(ctx context.Context, embedder Embedder, obj any) error {
+	for _, sv := range embedTargets(obj) {
+		st := sv.Type()
+		for i := 0; i < st.NumField(); i++ {
+			field := st.Field(i)
+			source, ok := parseEmbedTag(field.Tag.Get("dgraph"))
+			if !ok {
+				continue
+			}
+			if field.Type != vectorFloat32Type && field.Type != reflect.PointerTo(vectorFloat32Type) {
+				return fmt.Errorf("embed field %s.%s must be dgman.VectorFloat32 or *dgman.VectorFloat32, got %s",
+					st.Name(), field.Name, field.Type)
+			}
+			text, err := embedSourceText(sv, source)
+			if err != nil {
+				return fmt.Errorf("embed field %s.%s: %w", st.Name(), field.Name, err)
+			}
+			if text == "" {
+				continue
+			}
+			vec, err := embedder.Embed(ctx, text)
+			if err != nil {
+				return fmt.Errorf("embedding %q into %s: %w", source, fieldPredicate(field), err)
+			}
+			target := sv.Field(i)
+			if field.Type.Kind() == reflect.Ptr {
+				target.Set(reflect.ValueOf(&dg.VectorFloat32{Values: vec}))
+			} else {
+				target.Set(reflect.ValueOf(dg.VectorFloat32{Values: vec}))
+			}
+		}
+	}
+	return nil
+}
+
+// embedSourceText returns the text of the field whose predicate (or Go name)
+// is source. String-kinded fields, including SimString, and *string are accepted.
+func embedSourceText(sv reflect.Value, source string) (string, error) {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		if fieldPredicate(field) != source && field.Name != source {
+			continue
+		}
+		fv := sv.Field(i)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				return "", nil
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() != reflect.String {
+			return "", fmt.Errorf("source field %s must be a string, got %s", field.Name, field.Type)
+		}
+		return fv.String(), nil
+	}
+	return "", fmt.Errorf("source field %q not found", source)
+}
@@ -0,0 +1,127 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"testing"
+
+	dg "github.com/dolan-in/dgman/v2"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type embeddedArticle struct {
+	Title     string            `json:"title,omitempty" dgraph:"index=term"`
+	Summary   string            `json:"summary,omitempty"`
+	Embedding *dg.VectorFloat32 `json:"embedding,omitempty" dgraph:"embed=from:summary index=hnsw(metric:\"cosine\")"`
+
+	UID   string   `json:"uid,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type badEmbedTarget struct {
+	Summary   string    `json:"summary,omitempty"`
+	Embedding []float32 `json:"embedding,omitempty" dgraph:"embed=from:summary"`
+
+	UID   string   `json:"uid,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+func createEmbedderClient(t *testing.T, e mg.Embedder) (mg.Client, func()) {
+	t.Helper()
+	client, err := mg.NewClient("file://"+GetTempDir(t),
+		mg.WithAutoSchema(true),
+		mg.WithEmbedder(e),
+	)
+	require.NoError(t, err)
+	return client, func() {
+		_ = client.DropAll(context.Background())
+		client.Close()
+		mg.Shutdown()
+	}
+}
+
+func TestWithEmbedderInsertAndSearch(t *testing.T) {
+	embedder := newMockProvider(3)
+	embedder.register("red fruit", []float32{0.9, 0.1, 0.1})
+	embedder.register("yellow fruit", []float32{0.1, 0.9, 0.1})
+	embedder.register("green vegetable", []float32{0.1, 0.1, 0.9})
+
+	client, cleanup := createEmbedderClient(t, embedder)
+	defer cleanup()
+	ctx := context.Background()
+
+	articles := []*embeddedArticle{
+		{Title: "Apple", Summary: "red fruit"},
+		{Title: "Banana", Summary: "yellow fruit"},
+		{Title: "Kale", Summary: "green vegetable"},
+	}
+	require.NoError(t, client.Insert(ctx, articles))
+	require.Equal(t, []string{"red fruit", "yellow fruit", "green vegetable"}, embedder.callLog)
+	require.Equal(t, []float32{0.9, 0.1, 0.1}, articles[0].Embedding.Values)
+
+	dgo, release, err := client.DgraphClient()
+	require.NoError(t, err)
+	defer release()
+
+	var nearest embeddedArticle
+	query := dg.NewQuery().Model(&nearest).RootFunc("similar_to(embedding, 1, $vec)")
+	err = dg.NewReadOnlyTxn(dgo).Query(query).
+		Vars("similar_to($vec: string)", map[string]string{"$vec": "[0.2, 0.8, 0.1]"}).Scan()
+	require.NoError(t, err)
+	require.Equal(t, "Banana", nearest.Title)
+}
+
+func TestWithEmbedderUpdateRefreshesVector(t *testing.T) {
+	embedder := newMockProvider(3)
+	embedder.register("first draft", []float32{1, 0, 0})
+	embedder.register("final copy", []float32{0, 0, 1})
+
+	client, cleanup := createEmbedderClient(t, embedder)
+	defer cleanup()
+	ctx := context.Background()
+
+	article := &embeddedArticle{Title: "Doc", Summary: "first draft"}
+	require.NoError(t, client.Insert(ctx, article))
+
+	article.Summary = "final copy"
+	require.NoError(t, client.Update(ctx, article))
+
+	var fetched embeddedArticle
+	require.NoError(t, client.Get(ctx, &fetched, article.UID))
+	require.NotNil(t, fetched.Embedding)
+	require.Equal(t, []float32{0, 0, 1}, fetched.Embedding.Values)
+
+	// A partial update that leaves the source empty keeps the stored vector.
+	require.NoError(t, client.Update(ctx, &embeddedArticle{UID: article.UID, Title: "Renamed"}))
+	require.Len(t, embedder.callLog, 2)
+	fetched = embeddedArticle{}
+	require.NoError(t, client.Get(ctx, &fetched, article.UID))
+	require.Equal(t, "Renamed", fetched.Title)
+	require.Equal(t, []float32{0, 0, 1}, fetched.Embedding.Values)
+}
+
+func TestWithEmbedderRejectsNonVectorField(t *testing.T) {
+	client, cleanup := createEmbedderClient(t, newMockProvider(3))
+	defer cleanup()
+
+	err := client.Insert(context.Background(), &badEmbedTarget{Summary: "text"})
+	require.ErrorContains(t, err, "must be dgman.VectorFloat32")
+}
+
+func TestEmbeddingProviderFillsEmbedFields(t *testing.T) {
+	provider := newMockProvider(3)
+	provider.register("red fruit", []float32{0.9, 0.1, 0.1})
+	client, err := mg.NewClient("file://"+GetTempDir(t), mg.WithAutoSchema(true), mg.WithEmbeddingProvider(provider))
+	require.NoError(t, err)
+	defer client.Close()
+
+	article := &embeddedArticle{Title: "Apple", Summary: "red fruit"}
+	require.NoError(t, client.Insert(context.Background(), article))
+	require.NotNil(t, article.Embedding)
+	require.Equal(t, []float32{0.9, 0.1, 0.1}, article.Embedding.Values)
+}
@@ -27,10 +27,12 @@ import (
 	"github.com/dgraph-io/dgraph/v25/protos/pb"
 	"github.com/dgraph-io/dgraph/v25/query"
 	"github.com/dgraph-io/dgraph/v25/schema"
+	"github.com/dgraph-io/dgraph/v25/types"
 	"github.com/dgraph-io/dgraph/v25/worker"
 	"github.com/dgraph-io/dgraph/v25/x"
 	"github.com/dgraph-io/ristretto/v2/z"
 	"github.com/go-logr/logr"
+	"google.golang.org/protobuf/proto"
 )
 
 var (
@@ -38,6 +40,11 @@ var (
 	singleton atomic.Bool
 	// activeEngine tracks the current Engine instance for global access
 	activeEngine *Engine
+	// postingCache is the posting list cache of an earlier engine, reused by
+	// the next engine with the same cache size: the posting package never
+	// frees the caches it creates.
+	postingCache     *posting.MemoryLayer
+	postingCacheSize int64
 
 	ErrSingletonOnly    = errors.New("only one instance of modusGraph can exist in a process")
 	ErrEmptyDataDir     = errors.New("data directory is required")
@@ -57,6 +64,17 @@ type Engine struct {
 	// points to default / 0 / galaxy namespace
 	db0 *Namespace
 
+	// uniqueMu serializes the check-then-write of objects with unique groups.
+	uniqueMu sync.Mutex
+
+	// predicateStats holds the last PredicateStats result of each namespace,
+	// by ID, for the query planner's debug output.
+	predicateStats sync.Map
+
+	// history discards versions older than WithHistoryRetention; nil keeps
+	// them all.
+	history *historyRetention
+
 	logger logr.Logger
 }
 
@@ -94,8 +112,7 @@ func NewEngine(conf Config) (*Engine, error) {
 	worker.State.InitStorage()
 	worker.InitForLite(worker.State.Pstore)
 	schema.Init(worker.State.Pstore)
-	cacheSizeBytes := conf.cacheSizeMB * 1024 * 1024
-	posting.Init(worker.State.Pstore, int64(cacheSizeBytes), false)
+	initPosting(int64(conf.cacheSizeMB) * 1024 * 1024)
 
 	engine := &Engine{
 		logger: conf.logger,
@@ -159,6 +176,13 @@ func NewEngine(conf Config) (*Engine, error) {
 
 	engine.db0 = &Namespace{id: 0, engine: engine}
 
+	engine.history = newHistoryRetention(conf.historyRetention)
+	engine.history.start(func() uint64 {
+		engine.mutex.RLock()
+		defer engine.mutex.RUnlock()
+		return engine.z.readTs()
+	})
+
 	return engine, nil
 }
 
@@ -172,6 +196,19 @@ func Shutdown() {
 	singleton.Store(false)
 }
 
+// initPosting initializes the posting package on the store just opened,
+// reusing the cache of an earlier engine, emptied, when its size is the same.
+func initPosting(cacheSizeBytes int64) {
+	if postingCache == nil || postingCacheSize != cacheSizeBytes {
+		posting.Init(worker.State.Pstore, cacheSizeBytes, false)
+		postingCache, postingCacheSize = posting.MemLayerInstance, cacheSizeBytes
+		return
+	}
+	posting.Init(worker.State.Pstore, 0, false)
+	posting.MemLayerInstance = postingCache
+	posting.ResetCache()
+}
+
 func (engine *Engine) CreateNamespace() (*Namespace, error) {
 	engine.mutex.RLock()
 	defer engine.mutex.RUnlock()
@@ -290,6 +327,34 @@ func (engine *Engine) dropPredicate(ctx context.Context, ns *Namespace, pred str
 	return posting.DeletePredicate(ctx, nsAttr, startTs)
 }
 
+// dropType deletes a type definition from the embedded engine, the
+// in-process equivalent of a gRPC Alter with DropOp TYPE.
+func (engine *Engine) dropType(ctx context.Context, ns *Namespace, name string) error {
+	engine.mutex.Lock()
+	defer engine.mutex.Unlock()
+
+	if !engine.isOpen.Load() {
+		return ErrClosedEngine
+	}
+
+	startTs, err := engine.z.nextTs()
+	if err != nil {
+		return err
+	}
+
+	// The worker applies a type drop at the proposal's StartTs.
+	p := &pb.Proposal{StartTs: startTs, Mutations: &pb.Mutations{
+		GroupId:   1,
+		StartTs:   startTs,
+		DropOp:    pb.Mutations_TYPE,
+		DropValue: x.NamespaceAttr(ns.ID(), name),
+	}}
+	if err := worker.ApplyMutations(ctx, p); err != nil {
+		return fmt.Errorf("error applying mutation: %w", err)
+	}
+	return nil
+}
+
 func (engine *Engine) alterSchema(ctx context.Context, ns *Namespace, sch string) error {
 	engine.mutex.Lock()
 	defer engine.mutex.Unlock()
@@ -315,10 +380,27 @@ func (engine *Engine) alterSchemaWithParsed(ctx context.Context, sc *schema.Pars
 		return err
 	}
 
+	// The worker rebuilds indexes of existing predicates as a background raft
+	// task, which the embedded engine has no node to run; those predicates
+	// are rebuilt here instead, synchronously.
+	var preds []*pb.SchemaUpdate
+	for _, su := range sc.Preds {
+		rebuilt, err := engine.rebuildIndexes(ctx, su, startTs)
+		if err != nil {
+			return err
+		}
+		if !rebuilt {
+			preds = append(preds, su)
+		}
+	}
+	if len(preds) == 0 && len(sc.Types) == 0 {
+		return nil
+	}
+
 	p := &pb.Proposal{Mutations: &pb.Mutations{
 		GroupId: 1,
 		StartTs: startTs,
-		Schema:  sc.Preds,
+		Schema:  preds,
 		Types:   sc.Types,
 	}}
 	if err := worker.ApplyMutations(ctx, p); err != nil {
@@ -327,14 +409,131 @@ func (engine *Engine) alterSchemaWithParsed(ctx context.Context, sc *schema.Pars
 	return nil
 }
 
+// rebuildIndexes applies a schema update that changes the indexes of an
+// existing predicate: it drops the affected indexes, rebuilds them from the
+// data committed before startTs and persists the new schema. It reports false, doing
+// nothing, when the update needs no rebuild.
+func (engine *Engine) rebuildIndexes(ctx context.Context, su *pb.SchemaUpdate, startTs uint64) (bool, error) {
+	old, ok := schema.State().Get(ctx, su.Predicate)
+	if !ok {
+		return false, nil
+	}
+	rb := posting.IndexRebuild{
+		Attr:          su.Predicate,
+		StartTs:       startTs,
+		OldSchema:     &old,
+		CurrentSchema: su,
+	}
+	if !rb.NeedIndexRebuild() {
+		return false, nil
+	}
+
+	// Serve queries from the old schema minus the dropped indexes while the
+	// new ones build, as the worker does.
+	schema.State().Set(su.Predicate, rb.GetQuerySchema())
+	schema.State().SetMutSchema(su.Predicate, su)
+	build := func() error {
+		if err := rb.DropIndexes(ctx); err != nil {
+			return err
+		}
+		if err := rb.BuildData(ctx); err != nil {
+			return err
+		}
+		if err := rb.BuildIndexes(schema.GetWriteContext(context.Background())); err != nil {
+			return err
+		}
+		return engine.writeSchema(su, rb.StartTs)
+	}
+	if err := build(); err != nil {
+		if loadErr := schema.Load(su.Predicate); loadErr != nil {
+			engine.logger.Error(loadErr, "Failed to restore schema", "predicate", su.Predicate)
+		}
+		return true, fmt.Errorf("error rebuilding indexes of %s: %w", x.ParseAttr(su.Predicate), err)
+	}
+	posting.ResetCache()
+	return true, nil
+}
+
+// writeSchema persists a predicate's schema and makes it the served one.
+func (engine *Engine) writeSchema(su *pb.SchemaUpdate, ts uint64) error {
+	schema.State().Set(su.Predicate, su)
+	schema.State().DeleteMutSchema(su.Predicate)
+	data, err := proto.Marshal(su)
+	if err != nil {
+		return err
+	}
+	txn := worker.State.Pstore.NewTransactionAt(ts, true)
+	defer txn.Discard()
+	e := &badger.Entry{
+		Key:      x.SchemaKey(su.Predicate),
+		Value:    data,
+		UserMeta: posting.BitSchemaPosting,
+	}
+	if err := txn.SetEntry(e.WithDiscard()); err != nil {
+		return err
+	}
+	return txn.CommitAt(ts, nil)
+}
+
+// query runs a read-only query in ns. It stops waiting when ctx ends: a
+// query queued behind a write gives up its place, and one already running
+// returns ctx's error at once while the executor, which checks ctx as it
+// goes, winds down and releases the engine's read lock.
 func (engine *Engine) query(ctx context.Context,
 	ns *Namespace,
 	q string,
 	vars map[string]string) (*api.Response, error) {
-	engine.mutex.RLock()
-	defer engine.mutex.RUnlock()
+	if err := engine.rlock(ctx); err != nil {
+		return nil, err
+	}
+	if ctx.Done() == nil {
+		defer engine.mutex.RUnlock()
+		return engine.queryWithLock(ctx, ns, q, vars)
+	}
 
-	return engine.queryWithLock(ctx, ns, q, vars)
+	type result struct {
+		resp *api.Response
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer engine.mutex.RUnlock()
+		resp, err := engine.queryWithLock(ctx, ns, q, vars)
+		done <- result{resp, err}
+	}()
+	select {
+	case r := <-done:
+		return r.resp, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// rlock takes the engine's read lock, or returns ctx's error if ctx ends
+// first.
+func (engine *Engine) rlock(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if engine.mutex.TryRLock() {
+		return nil
+	}
+	locked := make(chan struct{})
+	go func() {
+		engine.mutex.RLock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		return nil
+	case <-ctx.Done():
+		// Hand the lock back as soon as the waiting goroutine gets it.
+		go func() {
+			<-locked
+			engine.mutex.RUnlock()
+		}()
+		return ctx.Err()
+	}
 }
 
 func (engine *Engine) queryWithLock(ctx context.Context,
@@ -345,14 +544,34 @@ func (engine *Engine) queryWithLock(ctx context.Context,
 		return nil, ErrClosedEngine
 	}
 
-	engine.logger.V(2).Info("Querying namespace", "namespaceID", ns.ID(), "query", q)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	readTs := engine.z.readTs()
+	if ts, ok := readTsFrom(ctx); ok {
+		if ts > readTs {
+			return nil, fmt.Errorf("%w: %d is after %d", ErrReadTsAhead, ts, readTs)
+		}
+		if err := engine.history.check(ts); err != nil {
+			return nil, err
+		}
+		readTs = ts
+	}
+
+	engine.logger.V(2).Info("Querying namespace", "namespaceID", ns.ID(), "query", q, "readTs", readTs)
 	ctx = x.AttachNamespace(ctx, ns.ID())
-	return (&edgraph.Server{}).QueryNoAuth(ctx, &api.Request{
+	resp, err := (&edgraph.Server{}).QueryNoAuth(ctx, &api.Request{
 		ReadOnly: true,
 		Query:    q,
-		StartTs:  engine.z.readTs(),
+		StartTs:  readTs,
 		Vars:     vars,
 	})
+	if err != nil && ctx.Err() != nil {
+		// The executor reports cancellation wrapped; return it plainly.
+		return nil, ctx.Err()
+	}
+	return resp, err
 }
 
 func (engine *Engine) mutate(ctx context.Context, ns *Namespace, ms []*api.Mutation) (map[string]uint64, error) {
@@ -362,23 +581,33 @@ func (engine *Engine) mutate(ctx context.Context, ns *Namespace, ms []*api.Mutat
 
 	engine.mutex.Lock()
 	defer engine.mutex.Unlock()
+	dms, newUids, err := engine.parseMutations(ctx, ms)
+	if err != nil {
+		return nil, err
+	}
+	return engine.mutateWithDqlMutation(ctx, ns, dms, newUids)
+}
+
+// parseMutations parses ms and assigns uids to their blank nodes.
+func (engine *Engine) parseMutations(ctx context.Context,
+	ms []*api.Mutation) ([]*dql.Mutation, map[string]uint64, error) {
 	dms := make([]*dql.Mutation, 0, len(ms))
 	for _, mu := range ms {
 		dm, err := edgraph.ParseMutationObject(ctx, mu)
 		if err != nil {
-			return nil, fmt.Errorf("error parsing mutation: %w", err)
+			return nil, nil, fmt.Errorf("error parsing mutation: %w", err)
 		}
 		dms = append(dms, dm)
 	}
 	newUids, err := query.ExtractBlankUIDs(ctx, dms)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if len(newUids) > 0 {
 		num := &pb.Num{Val: uint64(len(newUids)), Type: pb.Num_UID}
 		res, err := engine.z.nextUIDs(num)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		curId := res.StartId
@@ -388,8 +617,7 @@ func (engine *Engine) mutate(ctx context.Context, ns *Namespace, ms []*api.Mutat
 			curId++
 		}
 	}
-
-	return engine.mutateWithDqlMutation(ctx, ns, dms, newUids)
+	return dms, newUids, nil
 }
 
 func (engine *Engine) mutateWithDqlMutation(ctx context.Context, ns *Namespace, dms []*dql.Mutation,
@@ -398,24 +626,33 @@ func (engine *Engine) mutateWithDqlMutation(ctx context.Context, ns *Namespace,
 	if err != nil {
 		return nil, fmt.Errorf("error converting to directed edges: %w", err)
 	}
+	if err := engine.applyEdges(ctx, ns, edges, newUids); err != nil {
+		return nil, err
+	}
+	return newUids, nil
+}
+
+// applyEdges commits edges in one transaction.
+func (engine *Engine) applyEdges(ctx context.Context, ns *Namespace, edges []*pb.DirectedEdge,
+	newUids map[string]uint64) error {
 	ctx = x.AttachNamespace(ctx, ns.ID())
 
 	if !engine.isOpen.Load() {
-		return nil, ErrClosedEngine
+		return ErrClosedEngine
 	}
 
 	// Check unique constraints before applying mutations
 	if err := engine.verifyUniqueConstraints(ctx, ns, edges, newUids); err != nil {
-		return nil, err
+		return err
 	}
 
 	startTs, err := engine.z.nextTs()
 	if err != nil {
-		return nil, err
+		return err
 	}
 	commitTs, err := engine.z.nextTs()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	m := &pb.Mutations{
@@ -426,7 +663,7 @@ func (engine *Engine) mutateWithDqlMutation(ctx context.Context, ns *Namespace,
 
 	m.Edges, err = query.ExpandEdges(ctx, m)
 	if err != nil {
-		return nil, fmt.Errorf("error expanding edges: %w", err)
+		return fmt.Errorf("error expanding edges: %w", err)
 	}
 
 	for _, edge := range m.Edges {
@@ -435,10 +672,10 @@ func (engine *Engine) mutateWithDqlMutation(ctx context.Context, ns *Namespace,
 
 	p := &pb.Proposal{Mutations: m, StartTs: startTs}
 	if err := worker.ApplyMutations(ctx, p); err != nil {
-		return nil, err
+		return err
 	}
 
-	return newUids, worker.ApplyCommited(ctx, &pb.OracleDelta{
+	return worker.ApplyCommited(ctx, &pb.OracleDelta{
 		Txns: []*pb.TxnStatus{{StartTs: startTs, CommitTs: commitTs}},
 	})
 }
@@ -480,7 +717,16 @@ func (engine *Engine) verifyUniqueConstraints(
 			continue
 		}
 
+		// Non-string values arrive binary encoded; the eq query below needs
+		// their text form.
 		valStr := string(val)
+		if tid := types.TypeID(edge.ValueType); tid != types.StringID && tid != types.DefaultID {
+			sv, err := types.Convert(types.Val{Tid: tid, Value: val}, types.StringID)
+			if err != nil {
+				return fmt.Errorf("error converting value of %s: %w", predName, err)
+			}
+			valStr = sv.Value.(string)
+		}
 		subjectUID := edge.Entity
 
 		// Check for in-batch duplicates first
@@ -595,6 +841,9 @@ func (engine *Engine) LoadData(inCtx context.Context, dataDir string) error {
 
 // Close closes the modusGraph instance.
 func (engine *Engine) Close() {
+	// The sampler takes the read lock, so stop it first.
+	engine.history.close()
+
 	engine.mutex.Lock()
 	defer engine.mutex.Unlock()
 
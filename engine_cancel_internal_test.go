@@ -0,0 +1,56 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngineQueryCancellation(t *testing.T) {
+	c, err := NewClient("file://" + t.TempDir())
+	require.NoError(t, err)
+	defer c.Close()
+
+	engine := c.(client).engine
+	require.NotNil(t, engine)
+	ns := engine.GetDefaultNamespace()
+	const q = `{ q(func: has(dgraph.type)) { uid } }`
+
+	// A cancelled context fails before the query runs.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = engine.query(ctx, ns, q, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = c.QueryRaw(ctx, q, nil)
+	require.ErrorIs(t, err, context.Canceled)
+
+	// A query waiting behind a write gives up when its deadline passes.
+	engine.mutex.Lock()
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	start := time.Now()
+	_, err = engine.query(ctx, ns, q, nil)
+	cancel()
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 5*time.Second)
+	engine.mutex.Unlock()
+
+	// The abandoned wait doesn't keep the read lock.
+	require.Eventually(t, func() bool {
+		if !engine.mutex.TryLock() {
+			return false
+		}
+		engine.mutex.Unlock()
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	resp, err := engine.query(context.Background(), ns, q, nil)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+}
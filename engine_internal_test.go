@@ -0,0 +1,39 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dgraph-io/dgraph/v25/posting"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngineReusesPostingCache(t *testing.T) {
+	ctx := context.Background()
+	c, err := NewClient("file://"+t.TempDir(), WithAutoSchema(true))
+	require.NoError(t, err)
+	require.NoError(t, c.Insert(ctx, &SyncFilm{Title: "Alien"}))
+	var films []SyncFilm
+	require.NoError(t, c.Query(ctx, SyncFilm{}).Filter(`eq(title, "Alien")`).Nodes(&films))
+	require.Len(t, films, 1)
+	cache := posting.MemLayerInstance
+	c.Close()
+
+	// A second engine of the same cache size takes over the cache, without
+	// the posting lists of the first store, even once its timestamps pass
+	// theirs.
+	c, err = NewClient("file://"+t.TempDir(), WithAutoSchema(true))
+	require.NoError(t, err)
+	defer c.Close()
+	require.Same(t, cache, posting.MemLayerInstance)
+	for range 10 {
+		require.NoError(t, c.Insert(ctx, &SyncFilm{Title: "Heat"}))
+	}
+	require.NoError(t, c.Query(ctx, SyncFilm{}).Filter(`eq(title, "Alien")`).Nodes(&films))
+	require.Empty(t, films)
+}
@@ -6,6 +6,7 @@
 package modusgraph
 
 import (
+	"errors"
 	"regexp"
 	"strings"
 
@@ -16,6 +17,36 @@ import (
 // a node that would violate a unique constraint.
 type UniqueError = dg.UniqueError
 
+// ErrUniqueConflict is returned by Insert, Upsert, Update and LoadOrStore when
+// a write would give a @unique predicate a value another node already holds.
+// The same error also unwraps to a *UniqueError naming the predicate, value
+// and conflicting UID:
+//
+//	var uniqueErr *modusgraph.UniqueError
+//	if errors.Is(err, modusgraph.ErrUniqueConflict) && errors.As(err, &uniqueErr) {
+//		log.Printf("%s already taken by %s", uniqueErr.Value, uniqueErr.UID)
+//	}
+var ErrUniqueConflict = errors.New("modusgraph: unique constraint conflict")
+
+// uniqueConflictError carries a UniqueError while also matching
+// ErrUniqueConflict.
+type uniqueConflictError struct {
+	*UniqueError
+}
+
+func (e uniqueConflictError) Unwrap() []error {
+	return []error{e.UniqueError, ErrUniqueConflict}
+}
+
+// uniqueConflict returns err as a unique conflict if it reports a unique
+// constraint violation, and err unchanged otherwise.
+func uniqueConflict(err error) error {
+	if uniqueErr := parseUniqueError(err); uniqueErr != nil {
+		return uniqueConflictError{uniqueErr}
+	}
+	return err
+}
+
 // parseUniqueError attempts to parse a Dgraph unique constraint violation error
 // and convert it to a UniqueError. Returns nil if the error is not a unique constraint violation.
 func parseUniqueError(err error) *UniqueError {
@@ -0,0 +1,215 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	dg "github.com/dolan-in/dgman/v2"
+	"github.com/go-logr/logr"
+)
+
+// EventRoute sends the change events matching it to Publisher.
+//
+// Type restricts the route to one Dgraph type; empty matches every type. Ops
+// restricts it to the listed ops; empty matches every op.
+//
+// Filter is a DQL @filter expression, with $N parameters bound from Params as
+// in Query(...).Filter, e.g. Filter: "ge(year, $1)", Params: []any{2000}. An
+// event matches only if its node satisfies the filter when the batch is
+// routed. Nodes deleted outright can no longer be filtered, so a route with a
+// Filter never receives whole-node deletes.
+type EventRoute struct {
+	Type      string
+	Ops       []ChangeOp
+	Filter    string
+	Params    []any
+	Publisher Publisher
+}
+
+// EventRouterOptions configures NewEventRouter. Checkpoint is the file
+// recording the Seq of the last routed event; it defaults to
+// events.checkpoint in the WithChangelog directory. BatchSize caps how many
+// events are routed at once; it defaults to 100.
+type EventRouterOptions struct {
+	Checkpoint string
+	BatchSize  int
+}
+
+// EventRouter reads the client's changelog and publishes each event to every
+// route it matches. Like KafkaSink, delivery is at-least-once: the checkpoint
+// advances only after every route's publisher accepts a batch.
+type EventRouter struct {
+	client client
+	cursor changeCursor
+	routes []EventRoute
+	logger logr.Logger
+}
+
+// NewEventRouter creates a router over c's changelog. The client must have
+// been created WithChangelog. Call Run to start routing and Close when done.
+func NewEventRouter(c Client, routes []EventRoute, opts EventRouterOptions) (*EventRouter, error) {
+	mc, ok := c.(client)
+	if !ok || mc.changes == nil {
+		return nil, ErrChangelogDisabled
+	}
+	if len(routes) == 0 {
+		return nil, errors.New("modusgraph: event router requires at least one route")
+	}
+	for i, r := range routes {
+		if r.Publisher == nil {
+			return nil, fmt.Errorf("modusgraph: event route %d has no publisher", i)
+		}
+	}
+	return &EventRouter{
+		client: mc,
+		cursor: newChangeCursor(mc.changes, opts.Checkpoint, "events.checkpoint", opts.BatchSize),
+		routes: routes,
+		logger: mc.logger,
+	}, nil
+}
+
+// Run routes events from the one after the checkpoint onward, waiting for new
+// commits once caught up, until ctx is done. A failed publish is retried with
+// backoff rather than skipped.
+func (r *EventRouter) Run(ctx context.Context) error {
+	return r.cursor.run(ctx, func(ctx context.Context, events []ChangeEvent) error {
+		if err := retryDelivery(ctx, r.logger, func() error {
+			return r.client.resolveEventTypes(ctx, events)
+		}, "stage", "resolve types"); err != nil {
+			return err
+		}
+		for i, route := range r.routes {
+			if err := retryDelivery(ctx, r.logger, func() error {
+				matched, err := r.match(ctx, route, events)
+				if err != nil || len(matched) == 0 {
+					return err
+				}
+				return route.Publisher.Publish(ctx, matched)
+			}, "route", i, "type", route.Type); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close closes every route's publisher, once each.
+func (r *EventRouter) Close() error {
+	var errs []error
+	var closed []Publisher
+	for _, route := range r.routes {
+		if slices.Contains(closed, route.Publisher) {
+			continue
+		}
+		closed = append(closed, route.Publisher)
+		errs = append(errs, route.Publisher.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// resolveEventTypes fills in the type of events whose mutation did not state
+// it (typically updates), looking it up on the node.
+func (c client) resolveEventTypes(ctx context.Context, events []ChangeEvent) error {
+	var uids []string
+	for _, ev := range events {
+		if ev.Type == "" && ev.UID != "" && !wholeNodeDelete(ev) {
+			uids = append(uids, ev.UID)
+		}
+	}
+	if len(uids) == 0 {
+		return nil
+	}
+	nodes, err := c.queryEventNodes(ctx, uids, "", nil, "{ uid dgraph.type }")
+	if err != nil {
+		return err
+	}
+	types := make(map[string]string, len(nodes))
+	for _, n := range nodes {
+		if len(n.DType) > 0 {
+			types[n.UID] = n.DType[0]
+		}
+	}
+	for i := range events {
+		if events[i].Type == "" {
+			events[i].Type = types[events[i].UID]
+		}
+	}
+	return nil
+}
+
+// match returns the events route selects.
+func (r *EventRouter) match(ctx context.Context, route EventRoute, events []ChangeEvent) ([]ChangeEvent, error) {
+	var matched []ChangeEvent
+	for _, ev := range events {
+		if route.Type != "" && ev.Type != route.Type {
+			continue
+		}
+		if len(route.Ops) > 0 && !slices.Contains(route.Ops, ev.Op) {
+			continue
+		}
+		if route.Filter != "" && (ev.UID == "" || wholeNodeDelete(ev)) {
+			continue
+		}
+		matched = append(matched, ev)
+	}
+	if route.Filter == "" || len(matched) == 0 {
+		return matched, nil
+	}
+	return r.client.filterEvents(ctx, matched, route.Filter, route.Params)
+}
+
+// filterEvents keeps the events whose node satisfies the DQL filter.
+func (c client) filterEvents(ctx context.Context, events []ChangeEvent, filter string,
+	params []any) ([]ChangeEvent, error) {
+	uids := make([]string, len(events))
+	for i, ev := range events {
+		uids[i] = ev.UID
+	}
+	nodes, err := c.queryEventNodes(ctx, uids, filter, params, "{ uid }")
+	if err != nil {
+		return nil, err
+	}
+	pass := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		pass[n.UID] = true
+	}
+	return slices.DeleteFunc(events, func(ev ChangeEvent) bool { return !pass[ev.UID] }), nil
+}
+
+type routedNode struct {
+	UID   string   `json:"uid"`
+	DType []string `json:"dgraph.type"`
+}
+
+func (c client) queryEventNodes(ctx context.Context, uids []string, filter string, params []any,
+	projection string) ([]routedNode, error) {
+	q := dg.NewQuery().Name("nodes").UID(strings.Join(slices.Compact(slices.Sorted(slices.Values(uids))), ", ")).
+		Query(projection)
+	if filter != "" {
+		q.Filter(filter, params...)
+	}
+	resp, err := c.QueryRaw(ctx, q.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Nodes []routedNode `json:"nodes"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, err
+	}
+	return result.Nodes, nil
+}
+
+func wholeNodeDelete(ev ChangeEvent) bool {
+	return ev.Op == ChangeDelete && len(ev.Predicates) == 0
+}
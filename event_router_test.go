@@ -0,0 +1,227 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type RoutedFilm struct {
+	UID   string   `json:"uid,omitempty"`
+	Title string   `json:"title,omitempty" dgraph:"index=exact"`
+	Year  int      `json:"year,omitempty" dgraph:"index=int"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+// natsMsg is one message captured by fakeNATSServer.
+type natsMsg struct {
+	subject string
+	msgID   string
+	event   mg.ChangeEvent
+}
+
+// fakeNATSServer speaks just enough of the NATS client protocol to accept
+// connections and capture published messages.
+type fakeNATSServer struct {
+	ln   net.Listener
+	mu   sync.Mutex
+	msgs []natsMsg
+}
+
+func newFakeNATSServer(t *testing.T) *fakeNATSServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeNATSServer{ln: ln}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeNATSServer) url() string { return "nats://" + s.ln.Addr().String() }
+
+func (s *fakeNATSServer) serve(conn net.Conn) {
+	defer conn.Close()
+	fmt.Fprintf(conn, "INFO {\"server_id\":\"fake\",\"version\":\"2.10.0\",\"proto\":1,"+
+		"\"headers\":true,\"max_payload\":1048576}\r\n")
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case "HPUB": // HPUB <subject> <header bytes> <total bytes>
+			hdrLen, _ := strconv.Atoi(fields[2])
+			total, _ := strconv.Atoi(fields[3])
+			payload := make([]byte, total+2)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				return
+			}
+			msg := natsMsg{subject: fields[1]}
+			for _, h := range strings.Split(string(payload[:hdrLen]), "\r\n") {
+				if v, ok := strings.CutPrefix(h, "Nats-Msg-Id: "); ok {
+					msg.msgID = v
+				}
+			}
+			_ = json.Unmarshal(payload[hdrLen:total], &msg.event)
+			s.mu.Lock()
+			s.msgs = append(s.msgs, msg)
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *fakeNATSServer) messages() []natsMsg {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]natsMsg(nil), s.msgs...)
+}
+
+func TestEventRouter(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "EventRouterWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "EventRouterWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, err := mg.NewClient(tc.uri, mg.WithAutoSchema(true), mg.WithChangelog(t.TempDir()))
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = client.DropAll(context.Background())
+				client.Close()
+				mg.Shutdown()
+			})
+
+			// The webhook fails its first request; the router must retry it.
+			var (
+				hookMu     sync.Mutex
+				hookCalls  int
+				hookEvents []mg.ChangeEvent
+			)
+			hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hookMu.Lock()
+				defer hookMu.Unlock()
+				hookCalls++
+				require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+				if hookCalls == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				var events []mg.ChangeEvent
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&events))
+				hookEvents = append(hookEvents, events...)
+			}))
+			defer hook.Close()
+
+			natsServer := newFakeNATSServer(t)
+			natsPub, err := mg.NewNATSPublisher(natsServer.url(), "graph.{type}.{op}")
+			require.NoError(t, err)
+
+			router, err := mg.NewEventRouter(client, []mg.EventRoute{
+				{
+					Type:   "RoutedFilm",
+					Filter: "ge(year, $1)",
+					Params: []any{2000},
+					Publisher: mg.NewWebhookPublisher(hook.URL, mg.WebhookOptions{
+						Header: http.Header{"Authorization": {"Bearer secret"}},
+					}),
+				},
+				{
+					Ops:       []mg.ChangeOp{mg.ChangeInsert, mg.ChangeDelete},
+					Publisher: natsPub,
+				},
+			}, mg.EventRouterOptions{})
+			require.NoError(t, err)
+			defer router.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			go func() { done <- router.Run(ctx) }()
+
+			old := &RoutedFilm{Title: "Metropolis", Year: 1927}
+			recent := &RoutedFilm{Title: "Memento", Year: 2000}
+			require.NoError(t, client.Insert(context.Background(), old))
+			require.NoError(t, client.Insert(context.Background(), recent))
+			recent.Title = "Memento (Remastered)"
+			require.NoError(t, client.Update(context.Background(), recent))
+			require.NoError(t, client.Delete(context.Background(), []string{old.UID}))
+
+			require.Eventually(t, func() bool {
+				hookMu.Lock()
+				defer hookMu.Unlock()
+				return len(hookEvents) >= 2 && len(natsServer.messages()) >= 3
+			}, 10*time.Second, 20*time.Millisecond)
+			cancel()
+			require.ErrorIs(t, <-done, context.Canceled)
+
+			hookMu.Lock()
+			require.Len(t, hookEvents, 2, "only the post-2000 film's insert and update pass the filter")
+			for _, ev := range hookEvents {
+				require.Equal(t, recent.UID, ev.UID)
+				require.Equal(t, "RoutedFilm", ev.Type)
+			}
+			require.Equal(t, mg.ChangeInsert, hookEvents[0].Op)
+			require.Equal(t, mg.ChangeUpdate, hookEvents[1].Op)
+			hookMu.Unlock()
+
+			msgs := natsServer.messages()
+			require.Len(t, msgs, 3)
+			require.Equal(t, "graph.RoutedFilm.insert", msgs[0].subject)
+			require.Equal(t, old.UID, msgs[0].event.UID)
+			require.Equal(t, "graph.RoutedFilm.insert", msgs[1].subject)
+			require.Equal(t, "graph._.delete", msgs[2].subject)
+			require.Equal(t, old.UID, msgs[2].event.UID)
+			require.Equal(t, strconv.FormatUint(msgs[2].event.Seq, 10), msgs[2].msgID)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// ExportFormat names an output format of Export.
+type ExportFormat string
+
+const (
+	// JSONL writes one node per line as a JSON object, the default.
+	JSONL ExportFormat = "jsonl"
+	// GraphML writes a GraphML document, as ExportGraphML does.
+	GraphML ExportFormat = "graphml"
+)
+
+// exportPageSize is how many nodes Export reads per query.
+const exportPageSize = 1000
+
+// Export writes typed nodes (or the nodes of opts.Types) to w in
+// opts.Format, JSONL when it is empty.
+//
+// In JSON Lines each node is one object in the form of a Dgraph JSON
+// mutation, so Load reads the file back into any database: the node is the
+// blank node "_:<uid>", its edges refer to their targets the same way, and
+// it holds dgraph.type and the predicates its types declare, with facets
+// ("pred|facet") and language-tagged values ("pred@lang"). Edges to nodes
+// that are not of an exported type are left out; with MaxNodes, edges may
+// still refer to nodes past the cap. Password predicates cannot be read, so
+// their values are not exported. Apply the schema, from ExportSchema, before
+// loading the file elsewhere.
+func (c client) Export(ctx context.Context, w io.Writer, opts ExportOptions) error {
+	switch opts.Format {
+	case "", JSONL:
+		return c.exportJSONL(ctx, w, opts)
+	case GraphML:
+		return c.ExportGraphML(ctx, w, opts)
+	default:
+		return fmt.Errorf("modusgraph: export: unknown format %q", opts.Format)
+	}
+}
+
+// exportJSONL implements Export in the JSONL format.
+func (c client) exportJSONL(ctx context.Context, w io.Writer, opts ExportOptions) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	schema, err := c.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+	types := opts.Types
+	if len(types) == 0 {
+		for _, t := range schema.Types {
+			types = append(types, t.Name)
+		}
+		if len(types) == 0 {
+			return nil
+		}
+	}
+
+	// Select the predicates of every exported type, edges with the types of
+	// their targets so that edges leaving the export can be dropped.
+	var fields []string
+	var vectors, edges []string
+	seen := map[string]bool{}
+	for _, name := range types {
+		t, ok := schema.Type(name)
+		if !ok {
+			return fmt.Errorf("modusgraph: export: unknown type %q", name)
+		}
+		for _, f := range t.Fields {
+			p, ok := schema.Predicate(f)
+			if !ok || seen[f] || p.Type == "password" {
+				continue
+			}
+			seen[f] = true
+			switch {
+			case p.Type == "uid":
+				fields = append(fields, "<"+f+"> @facets { uid dgraph.type }")
+				edges = append(edges, f)
+			case p.Lang:
+				fields = append(fields, "<"+f+">@*")
+			default:
+				fields = append(fields, "<"+f+"> @facets")
+				if p.Type == "float32vector" {
+					vectors = append(vectors, f)
+				}
+			}
+		}
+	}
+	parts := make([]string, len(types))
+	for i, t := range types {
+		parts[i] = fmt.Sprintf("type(%s)", t)
+	}
+	filter := " @filter(" + strings.Join(parts, " OR ") + ")"
+
+	dg, cleanup, err := c.DgraphClient()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	txn := dg.NewReadOnlyTxn()
+
+	enc := json.NewEncoder(w)
+	written := 0
+	after := "0x0"
+	for opts.MaxNodes <= 0 || written < opts.MaxNodes {
+		first := exportPageSize
+		if opts.MaxNodes > 0 {
+			first = min(first, opts.MaxNodes-written)
+		}
+		q := fmt.Sprintf(`{ nodes(func: has(dgraph.type), first: %d, after: %s)%s { uid dgraph.type %s } }`,
+			first, after, filter, strings.Join(fields, " "))
+		resp, err := txn.Query(ctx, q)
+		if err != nil {
+			return err
+		}
+		var page struct {
+			Nodes []map[string]any `json:"nodes"`
+		}
+		dec := json.NewDecoder(bytes.NewReader(resp.Json))
+		dec.UseNumber()
+		if err := dec.Decode(&page); err != nil {
+			return err
+		}
+		for _, node := range page.Nodes {
+			after, _ = node["uid"].(string)
+			node["uid"] = "_:" + after
+			for _, e := range edges {
+				if v, ok := exportEdges(node[e], types); ok {
+					node[e] = v
+				} else {
+					delete(node, e)
+				}
+			}
+			for _, v := range vectors {
+				if vec, ok := node[v]; ok {
+					// Mutations take vectors in their string form.
+					data, _ := json.Marshal(vec)
+					node[v] = string(data)
+				}
+			}
+			if err := enc.Encode(node); err != nil {
+				return err
+			}
+		}
+		written += len(page.Nodes)
+		if len(page.Nodes) < first {
+			break
+		}
+	}
+	return nil
+}
+
+// exportEdges rewrites the targets of a uid predicate's value as blank
+// nodes, dropping those not of one of types. It reports false when no
+// target is left.
+func exportEdges(v any, types []string) (any, bool) {
+	keep := func(edge any) (map[string]any, bool) {
+		target, _ := edge.(map[string]any)
+		if target == nil {
+			return nil, false
+		}
+		var targetTypes []any
+		switch t := target["dgraph.type"].(type) {
+		case []any:
+			targetTypes = t
+		case string:
+			targetTypes = []any{t}
+		}
+		delete(target, "dgraph.type")
+		uid, _ := target["uid"].(string)
+		target["uid"] = "_:" + uid
+		return target, slices.ContainsFunc(targetTypes, func(t any) bool {
+			s, _ := t.(string)
+			return slices.Contains(types, s)
+		})
+	}
+	list, ok := v.([]any)
+	if !ok {
+		return keep(v)
+	}
+	kept := make([]any, 0, len(list))
+	for _, edge := range list {
+		if target, ok := keep(edge); ok {
+			kept = append(kept, target)
+		}
+	}
+	return kept, len(kept) > 0
+}
@@ -0,0 +1,153 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportJSONL(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "ExportJSONLWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "ExportJSONLWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	const schema = `
+		name: string @index(exact) @lang .
+		tags: [string] .
+		embedding: float32vector .
+		directed: [uid] @reverse .
+		sequel: uid .
+		type Person {
+			name
+			directed
+		}
+		type Film {
+			name
+			tags
+			embedding
+			sequel
+		}
+	`
+	const data = `
+		_:scott <name> "Ridley Scott" .
+		_:scott <dgraph.type> "Person" .
+		_:alien <name> "Alien" .
+		_:alien <name> "Alien, le huitième passager"@fr .
+		_:alien <tags> "horror" (weight=0.9) .
+		_:alien <tags> "space" .
+		_:alien <embedding> "[0.5, 0.25]" .
+		_:alien <dgraph.type> "Film" .
+		_:aliens <name> "Aliens" .
+		_:aliens <dgraph.type> "Film" .
+		_:alien <sequel> _:aliens (years=7) .
+		_:scott <directed> _:alien (year=1979) .
+	`
+	const query = `{
+		films(func: type(Film), orderasc: name) {
+			name name@fr tags @facets embedding dgraph.type
+			sequel @facets { name }
+			~directed { name }
+		}
+	}`
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, err := mg.NewClient(tc.uri)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = client.DropAll(context.Background())
+				client.Close()
+				mg.Shutdown()
+			})
+			ctx := context.Background()
+
+			dg, cleanup, err := client.DgraphClient()
+			require.NoError(t, err)
+			defer cleanup()
+			require.NoError(t, dg.Alter(ctx, &api.Operation{Schema: schema}))
+			_, err = dg.NewTxn().Mutate(ctx, &api.Mutation{CommitNow: true, SetNquads: []byte(data)})
+			require.NoError(t, err)
+
+			// Only films are exported, one per line, without the edge from
+			// the director, who is not.
+			var films bytes.Buffer
+			require.NoError(t, client.Export(ctx, &films, mg.ExportOptions{Types: []string{"Film"}, Format: mg.JSONL}))
+			lines := strings.Split(strings.TrimSpace(films.String()), "\n")
+			require.Len(t, lines, 2)
+			for _, line := range lines {
+				var node map[string]any
+				require.NoError(t, json.Unmarshal([]byte(line), &node))
+				require.Equal(t, []any{"Film"}, node["dgraph.type"])
+				require.True(t, strings.HasPrefix(node["uid"].(string), "_:0x"), line)
+				require.NotContains(t, node, "directed")
+			}
+			require.Contains(t, films.String(), `"name@fr":"Alien, le huitième passager"`)
+			require.Contains(t, films.String(), `"tags|weight":{"`)
+			require.Contains(t, films.String(), `"sequel|years":7`)
+
+			// Exporting both types keeps the edge between them, and the
+			// file loads back into an emptied database.
+			var all bytes.Buffer
+			require.NoError(t, client.Export(ctx, &all, mg.ExportOptions{Types: []string{"Film", "Person"}}))
+			require.Len(t, strings.Split(strings.TrimSpace(all.String()), "\n"), 3)
+			require.Contains(t, all.String(), `"directed":[{"directed|year":1979,"uid":"_:0x`)
+			want, err := client.QueryRaw(ctx, query, nil)
+			require.NoError(t, err)
+
+			file := filepath.Join(t.TempDir(), "nodes.jsonl")
+			require.NoError(t, os.WriteFile(file, []byte("\n"+all.String()+"\n"), 0o600))
+			require.NoError(t, client.DropAll(ctx))
+			require.NoError(t, client.AlterSchema(ctx, schema))
+			require.NoError(t, client.Load(ctx, file, mg.LoadOptions{BatchSize: 2}))
+			got, err := client.QueryRaw(ctx, query, nil)
+			require.NoError(t, err)
+			require.JSONEq(t, string(want), string(got))
+
+			// No types exports every typed node; MaxNodes caps them.
+			var every bytes.Buffer
+			require.NoError(t, client.Export(ctx, &every, mg.ExportOptions{}))
+			require.Len(t, strings.Split(strings.TrimSpace(every.String()), "\n"), 3)
+			var capped bytes.Buffer
+			require.NoError(t, client.Export(ctx, &capped, mg.ExportOptions{MaxNodes: 1}))
+			require.Len(t, strings.Split(strings.TrimSpace(capped.String()), "\n"), 1)
+
+			var graphml bytes.Buffer
+			require.NoError(t, client.Export(ctx, &graphml, mg.ExportOptions{Format: mg.GraphML}))
+			require.Contains(t, graphml.String(), "<graphml")
+
+			err = client.Export(ctx, &every, mg.ExportOptions{Types: []string{"Nope"}})
+			require.ErrorContains(t, err, `unknown type "Nope"`)
+			require.ErrorContains(t, client.Export(ctx, &every, mg.ExportOptions{Format: "csv"}), `unknown format "csv"`)
+		})
+	}
+}
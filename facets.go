@@ -0,0 +1,64 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"reflect"
+	"strings"
+)
+
+// isFacetField reports whether sf holds a facet rather than a predicate. Its
+// name has the "<predicate>|<facet>" form Dgraph's JSON gives facets, which
+// dgman writes as is and leaves out of the schema.
+func isFacetField(sf reflect.StructField) bool {
+	return strings.Contains(fieldPredicate(sf), "|")
+}
+
+// facetsSelector returns " @facets" when t, a node type or the type of an
+// edge field, declares a facet field for pred, and "" otherwise. Queries
+// that name a predicate instead of expanding it must ask for its facets,
+// which Dgraph then returns as "<pred>|<facet>" members: on the node for a
+// scalar predicate, and on each target for an edge.
+func facetsSelector(t reflect.Type, pred string) string {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return ""
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if p, _, ok := strings.Cut(fieldPredicate(t.Field(i)), "|"); ok && p == pred {
+			return " @facets"
+		}
+	}
+	return ""
+}
+
+// hasReverseFacets reports whether a node of type t, or one reachable from it
+// through its edges, has a managed reverse edge whose targets declare facet
+// fields for it. dgman's All does not ask for those facets.
+func hasReverseFacets(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || seen[t] {
+		return false
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() || !isEdgeType(sf.Type) {
+			continue
+		}
+		if pred := fieldPredicate(sf); strings.HasPrefix(pred, "~") && facetsSelector(sf.Type, pred) != "" {
+			return true
+		}
+		if hasReverseFacets(sf.Type, seen) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/queryopt"
+	"github.com/stretchr/testify/require"
+)
+
+// FacetPerson carries the facets of the edges that reach it, named
+// "<edge>|<facet>", and a facet of its own name predicate.
+type FacetPerson struct {
+	UID        string         `json:"uid,omitempty"`
+	Name       string         `json:"fct_name,omitempty" dgraph:"index=exact"`
+	NameSource string         `json:"fct_name|source,omitempty"`
+	Friends    []*FacetPerson `json:"fct_friends,omitempty" dgraph:"reverse"`
+	Since      *time.Time     `json:"fct_friends|since,omitempty"`
+	Closeness  float64        `json:"fct_friends|closeness,omitempty"`
+	Followers  []*FacetPerson `json:"~fct_friends,omitempty" dgraph:"reverse"`
+	FollowedAt *time.Time     `json:"~fct_friends|since,omitempty"`
+	DType      []string       `json:"dgraph.type,omitempty"`
+}
+
+func TestEdgeFacets(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "EdgeFacetsWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "EdgeFacetsWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+
+			since := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+			bob := &FacetPerson{Name: "bob", Since: &since, Closeness: 0.8}
+			alice := &FacetPerson{Name: "alice", NameSource: "registry", Friends: []*FacetPerson{bob}}
+			require.NoError(t, client.Insert(ctx, alice))
+
+			checkFriend := func(t *testing.T, got FacetPerson, closeness float64) {
+				t.Helper()
+				require.Len(t, got.Friends, 1)
+				require.Equal(t, "bob", got.Friends[0].Name)
+				require.NotNil(t, got.Friends[0].Since)
+				require.True(t, since.Equal(*got.Friends[0].Since), "since = %v", got.Friends[0].Since)
+				require.Equal(t, closeness, got.Friends[0].Closeness)
+			}
+
+			var got FacetPerson
+			require.NoError(t, client.Get(ctx, &got, alice.UID))
+			require.Equal(t, "registry", got.NameSource)
+			checkFriend(t, got, 0.8)
+
+			// A managed reverse edge carries the facets of the forward one.
+			var gotBob FacetPerson
+			require.NoError(t, client.Get(ctx, &gotBob, bob.UID))
+			require.Len(t, gotBob.Followers, 1)
+			require.Equal(t, "alice", gotBob.Followers[0].Name)
+			require.NotNil(t, gotBob.Followers[0].FollowedAt)
+			require.True(t, since.Equal(*gotBob.Followers[0].FollowedAt))
+
+			// Every way of naming an edge in a read asks for its facets.
+			got = FacetPerson{}
+			require.NoError(t, client.Get(ctx, &got, alice.UID, mg.GetMaxEdgeFanout(1)))
+			checkFriend(t, got, 0.8)
+			got = FacetPerson{}
+			require.NoError(t, client.Get(ctx, &got, alice.UID, mg.ExpandEdges("fct_friends")))
+			checkFriend(t, got, 0.8)
+			people, err := mg.Query[FacetPerson](ctx, client).
+				Filter(`eq(fct_name, "alice")`).
+				With(queryopt.Fields{"fct_name", "fct_friends"}, queryopt.Depth(1)).
+				All()
+			require.NoError(t, err)
+			require.Len(t, people, 1)
+			require.Equal(t, "registry", people[0].NameSource)
+			checkFriend(t, people[0], 0.8)
+
+			// Writing the edge again replaces its facets.
+			require.NoError(t, client.Update(ctx, &FacetPerson{
+				UID:     alice.UID,
+				Friends: []*FacetPerson{{UID: bob.UID, Since: &since, Closeness: 0.5}},
+			}))
+			got = FacetPerson{}
+			require.NoError(t, client.Get(ctx, &got, alice.UID))
+			checkFriend(t, got, 0.5)
+		})
+	}
+}
@@ -0,0 +1,268 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrNoHealthyEndpoint is returned by calls made while the circuit breaker
+// of every endpoint of a client is open.
+var ErrNoHealthyEndpoint = errors.New("modusgraph: no healthy Dgraph endpoint")
+
+const (
+	// defaultBreakerFailures is how many consecutive failed calls open an
+	// endpoint's circuit breaker.
+	defaultBreakerFailures = 3
+
+	// defaultBreakerCooldown is how long an open breaker skips its endpoint
+	// before a probe call is let through.
+	defaultBreakerCooldown = 10 * time.Second
+)
+
+// WithCircuitBreaker tunes failover between the endpoints of a URI listing
+// several, such as dgraph://alpha1:9080,alpha2:9080,alpha3:9080. Each call
+// goes to the first endpoint in the list whose circuit breaker is closed,
+// and moves on to the next when an endpoint is unreachable. After failures
+// consecutive failed calls an endpoint's breaker opens and calls skip it;
+// once cooldown has passed a single probe call is let through, which closes
+// the breaker if it succeeds and reopens it if not. The defaults are 3
+// failures and 10 seconds. Ignored for URIs naming one endpoint.
+func WithCircuitBreaker(failures int, cooldown time.Duration) ClientOpt {
+	return func(o *clientOptions) {
+		o.breakerFailures = failures
+		o.breakerCooldown = cooldown
+	}
+}
+
+// BreakerState is the state of an endpoint's circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets calls through to the endpoint.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen skips the endpoint until its cooldown has passed.
+	BreakerOpen
+	// BreakerHalfOpen lets one probe call through to decide whether the
+	// endpoint has recovered.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("BreakerState(%d)", int(s))
+}
+
+// EndpointStats is the health of one Dgraph endpoint.
+type EndpointStats struct {
+	Address string
+	State   BreakerState
+	// ConsecutiveFailures counts the failed calls since the last success.
+	ConsecutiveFailures int
+	// Calls and Failures count every call made to the endpoint and those
+	// that failed because it was unreachable.
+	Calls    uint64
+	Failures uint64
+	// LastError is the error of the last failed call, LastFailure its time.
+	LastError   error
+	LastFailure time.Time
+}
+
+// ClientStats reports on a client's connections.
+type ClientStats struct {
+	// Endpoints is the health of each endpoint of a URI listing several, in
+	// failover order; empty for other clients.
+	Endpoints []EndpointStats
+}
+
+// Stats reports the health of the client's connections.
+func (c client) Stats() ClientStats {
+	if c.endpoints == nil {
+		return ClientStats{}
+	}
+	return ClientStats{Endpoints: c.endpoints.stats()}
+}
+
+// failover is a gRPC connection over several endpoints of one cluster. Each
+// call goes to the first endpoint whose circuit breaker lets it through, and
+// fails over to the next when the endpoint is unreachable.
+type failover struct {
+	endpoints []*endpoint
+	failures  int
+	cooldown  time.Duration
+	now       func() time.Time
+}
+
+// endpoint is one connection of a failover and its circuit breaker.
+type endpoint struct {
+	addr string
+	conn *grpc.ClientConn
+
+	mu          sync.Mutex
+	state       BreakerState
+	probing     bool
+	openedAt    time.Time
+	consecutive int
+	calls       uint64
+	failures    uint64
+	lastErr     error
+	lastFailure time.Time
+}
+
+// newFailover opens a connection to each of hosts. Connections are made on
+// first use, so unreachable hosts do not fail it.
+func newFailover(hosts []string, dialOpts []grpc.DialOption, options clientOptions) (*failover, error) {
+	f := &failover{
+		failures: options.breakerFailures,
+		cooldown: options.breakerCooldown,
+		now:      time.Now,
+	}
+	if f.failures <= 0 {
+		f.failures = defaultBreakerFailures
+	}
+	if f.cooldown <= 0 {
+		f.cooldown = defaultBreakerCooldown
+	}
+	for _, host := range hosts {
+		conn, err := grpc.NewClient(host, dialOpts...)
+		if err != nil {
+			f.close()
+			return nil, fmt.Errorf("failed to connect to endpoint [%s]: %w", host, err)
+		}
+		f.endpoints = append(f.endpoints, &endpoint{addr: host, conn: conn})
+	}
+	return f, nil
+}
+
+// Invoke implements grpc.ClientConnInterface, trying the endpoints in order
+// until one is reachable.
+func (f *failover) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	var lastErr error
+	for _, ep := range f.endpoints {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !ep.allow(f.now(), f.cooldown) {
+			continue
+		}
+		err := ep.conn.Invoke(ctx, method, args, reply, opts...)
+		if !ep.record(ctx, err, f.failures, f.now()) {
+			return err
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		return ErrNoHealthyEndpoint
+	}
+	return lastErr
+}
+
+// NewStream implements grpc.ClientConnInterface, opening the stream on the
+// first endpoint that is reachable.
+func (f *failover) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string,
+	opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	var lastErr error
+	for _, ep := range f.endpoints {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !ep.allow(f.now(), f.cooldown) {
+			continue
+		}
+		stream, err := ep.conn.NewStream(ctx, desc, method, opts...)
+		if !ep.record(ctx, err, f.failures, f.now()) {
+			return stream, err
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		return nil, ErrNoHealthyEndpoint
+	}
+	return nil, lastErr
+}
+
+// allow reports whether a call may go to the endpoint, turning an open
+// breaker whose cooldown has passed half-open for one probe.
+func (ep *endpoint) allow(now time.Time, cooldown time.Duration) bool {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	switch ep.state {
+	case BreakerOpen:
+		if now.Sub(ep.openedAt) < cooldown {
+			return false
+		}
+		ep.state = BreakerHalfOpen
+	case BreakerHalfOpen:
+		if ep.probing {
+			return false
+		}
+	}
+	ep.probing = ep.state == BreakerHalfOpen
+	ep.calls++
+	return true
+}
+
+// record updates the breaker with the outcome of a call and reports whether
+// the endpoint was unreachable, so the call should move on. Calls ended by
+// their own context say nothing about the endpoint.
+func (ep *endpoint) record(ctx context.Context, err error, failures int, now time.Time) bool {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	ep.probing = false
+	switch {
+	case status.Code(err) == codes.Unavailable && ctx.Err() == nil:
+		ep.consecutive++
+		ep.failures++
+		ep.lastErr, ep.lastFailure = err, now
+		if ep.state == BreakerHalfOpen || ep.consecutive >= failures {
+			ep.state, ep.openedAt = BreakerOpen, now
+		}
+		return true
+	case err != nil && ctx.Err() != nil:
+	default:
+		ep.state, ep.consecutive = BreakerClosed, 0
+	}
+	return false
+}
+
+func (f *failover) stats() []EndpointStats {
+	stats := make([]EndpointStats, len(f.endpoints))
+	for i, ep := range f.endpoints {
+		ep.mu.Lock()
+		stats[i] = EndpointStats{
+			Address:             ep.addr,
+			State:               ep.state,
+			ConsecutiveFailures: ep.consecutive,
+			Calls:               ep.calls,
+			Failures:            ep.failures,
+			LastError:           ep.lastErr,
+			LastFailure:         ep.lastFailure,
+		}
+		ep.mu.Unlock()
+	}
+	return stats
+}
+
+func (f *failover) close() {
+	for _, ep := range f.endpoints {
+		_ = ep.conn.Close()
+	}
+}
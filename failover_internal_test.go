@@ -0,0 +1,130 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// countingServer is a Dgraph stand-in that counts the queries it answers.
+type countingServer struct {
+	api.UnimplementedDgraphServer
+	queries atomic.Int64
+}
+
+func (s *countingServer) Query(context.Context, *api.Request) (*api.Response, error) {
+	s.queries.Add(1)
+	return &api.Response{Json: []byte(`{"q":[]}`), Txn: &api.TxnContext{StartTs: 1}}, nil
+}
+
+// serveCounting serves a countingServer on lis and returns it with a
+// function stopping it.
+func serveCounting(t *testing.T, lis net.Listener) (*countingServer, func()) {
+	srv := grpc.NewServer()
+	fake := &countingServer{}
+	api.RegisterDgraphServer(srv, fake)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return fake, srv.Stop
+}
+
+// freeAddr returns a local address nothing listens on.
+func freeAddr(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close())
+	return addr
+}
+
+func TestFailover(t *testing.T) {
+	primary := freeAddr(t)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	secondary, _ := serveCounting(t, lis)
+
+	c, err := NewClient("dgraph://"+primary+","+lis.Addr().String(), WithCircuitBreaker(2, time.Minute))
+	require.NoError(t, err)
+	defer c.Close()
+	now := time.Now()
+	c.(client).endpoints.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	query := func() error {
+		_, err := c.QueryRaw(ctx, `{ q(func: uid(0x1)) { uid } }`, nil)
+		return err
+	}
+	state := func(i int) EndpointStats {
+		return c.Stats().Endpoints[i]
+	}
+
+	// The dead primary is tried and skipped until its breaker opens.
+	require.NoError(t, query())
+	require.NoError(t, query())
+	require.EqualValues(t, 2, secondary.queries.Load())
+	require.Equal(t, BreakerOpen, state(0).State)
+	require.EqualValues(t, 2, state(0).Failures)
+	require.Error(t, state(0).LastError)
+	require.Equal(t, BreakerClosed, state(1).State)
+
+	// While open, calls go straight to the secondary.
+	require.NoError(t, query())
+	require.EqualValues(t, 2, state(0).Calls)
+
+	// After the cooldown one probe fails and the breaker reopens.
+	now = now.Add(time.Minute)
+	require.NoError(t, query())
+	require.EqualValues(t, 3, state(0).Calls)
+	require.Equal(t, BreakerOpen, state(0).State)
+
+	// Once the primary is back, the next probe closes the breaker and calls
+	// return to it.
+	plis, err := net.Listen("tcp", primary)
+	require.NoError(t, err)
+	recovered, _ := serveCounting(t, plis)
+	now = now.Add(time.Minute)
+	require.Eventually(t, func() bool {
+		now = now.Add(time.Minute)
+		return query() == nil && state(0).State == BreakerClosed
+	}, 10*time.Second, 50*time.Millisecond)
+	require.NoError(t, query())
+	require.GreaterOrEqual(t, recovered.queries.Load(), int64(2))
+	require.Zero(t, state(0).ConsecutiveFailures)
+}
+
+func TestFailoverAllEndpointsDown(t *testing.T) {
+	c, err := NewClient("dgraph://"+freeAddr(t)+","+freeAddr(t), WithCircuitBreaker(1, time.Minute))
+	require.NoError(t, err)
+	defer c.Close()
+
+	ctx := context.Background()
+	_, err = c.QueryRaw(ctx, `{ q(func: uid(0x1)) { uid } }`, nil)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrNoHealthyEndpoint)
+
+	// Both breakers are open now, so calls fail without trying.
+	_, err = c.QueryRaw(ctx, `{ q(func: uid(0x1)) { uid } }`, nil)
+	require.ErrorIs(t, err, ErrNoHealthyEndpoint)
+	for _, ep := range c.Stats().Endpoints {
+		require.Equal(t, BreakerOpen, ep.State)
+		require.EqualValues(t, 1, ep.Calls)
+	}
+}
+
+func TestStatsSingleEndpoint(t *testing.T) {
+	c, err := NewClient("file://" + t.TempDir())
+	require.NoError(t, err)
+	defer c.Close()
+	require.Empty(t, c.Stats().Endpoints)
+}
@@ -0,0 +1,274 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package federation exposes modusGraph models as an Apollo Federation v2
+// subgraph. SDL renders the subgraph schema for a set of Go structs, keyed by
+// UID and by every dgraph:"unique" field, and Resolver answers the router's
+// _entities lookups against a modusgraph.Client.
+//
+// The package emits schema text and resolves references; it does not serve
+// HTTP or embed a GraphQL engine. Wire the SDL and Resolver.Entities into
+// whichever GraphQL server the service already runs (gqlgen, graphql-go, ...).
+//
+//	sdl, err := federation.SDL(&Film{}, &Person{})
+//	resolver, err := federation.NewResolver(client, &Film{}, &Person{})
+//	// in the _entities field resolver:
+//	entities, err := resolver.Entities(ctx, representations)
+package federation
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	mg "github.com/matthewmcneely/modusgraph"
+)
+
+// FederationVersion is the federation spec the emitted SDL links against.
+const FederationVersion = "https://specs.apollo.dev/federation/v2.3"
+
+// IDField is the GraphQL field every entity exposes its UID as; it is also
+// each entity's first @key.
+const IDField = "id"
+
+// entity describes one Go struct as a federated GraphQL type.
+type entity struct {
+	name   string
+	goType reflect.Type
+	fields []field
+	// keys are the GraphQL fields that identify a node: IDField, then each
+	// unique field in declaration order.
+	keys []string
+}
+
+// field maps one struct field to its GraphQL field and Dgraph predicate.
+type field struct {
+	name      string
+	predicate string
+	gqlType   string
+	unique    bool
+	required  bool
+}
+
+var graphQLName = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	decimalType = reflect.TypeOf(mg.Decimal{})
+	geoTypes    = map[reflect.Type]bool{
+		reflect.TypeOf(mg.Point{}):        true,
+		reflect.TypeOf(mg.Polygon{}):      true,
+		reflect.TypeOf(mg.MultiPolygon{}): true,
+	}
+)
+
+// SDL renders the federation subgraph schema for models and every struct type
+// reachable from them through edges. Each type carries @key(fields: "id") and
+// one further @key per dgraph:"unique" field, so a supergraph can reference a
+// node by UID or by any natural key. Fields tagged dgraph:"required" are
+// non-null.
+func SDL(models ...any) (string, error) {
+	entities, err := collect(models)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "extend schema\n  @link(url: %q, import: [\"@key\"])\n", FederationVersion)
+	for _, scalar := range []string{"DateTime", "GeoJSON"} {
+		if usesScalar(entities, scalar) {
+			b.WriteString("\nscalar " + scalar + "\n")
+		}
+	}
+	for _, e := range entities {
+		b.WriteString("\ntype ")
+		b.WriteString(e.name)
+		for _, k := range e.keys {
+			fmt.Fprintf(&b, " @key(fields: %q)", k)
+		}
+		b.WriteString(" {\n")
+		for _, f := range e.fields {
+			fmt.Fprintf(&b, "  %s: %s\n", f.name, f.gqlType)
+		}
+		b.WriteString("}\n")
+	}
+	return b.String(), nil
+}
+
+func usesScalar(entities []*entity, scalar string) bool {
+	for _, e := range entities {
+		for _, f := range e.fields {
+			if strings.Trim(f.gqlType, "[]!") == scalar {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// collect reflects models into entities, following edges, sorted by name.
+func collect(models []any) ([]*entity, error) {
+	byType := map[reflect.Type]*entity{}
+	byName := map[string]*entity{}
+	var visit func(t reflect.Type, name string) (*entity, error)
+	visit = func(t reflect.Type, name string) (*entity, error) {
+		if e, ok := byType[t]; ok {
+			return e, nil
+		}
+		if _, ok := t.FieldByName("UID"); !ok {
+			return nil, fmt.Errorf("federation: %s has no UID field", t)
+		}
+		if !graphQLName.MatchString(name) {
+			return nil, fmt.Errorf("federation: type name %q is not a valid GraphQL name", name)
+		}
+		if other, ok := byName[name]; ok {
+			return nil, fmt.Errorf("federation: %s and %s both map to GraphQL type %s", other.goType, t, name)
+		}
+		e := &entity{name: name, goType: t, keys: []string{IDField}}
+		byType[t], byName[name] = e, e
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() || sf.Name == "DType" {
+				continue
+			}
+			if sf.Name == "UID" {
+				e.fields = append(e.fields, field{name: IDField, predicate: "uid", gqlType: "ID!"})
+				continue
+			}
+			f, target, ok := fieldOf(sf)
+			if !ok {
+				continue
+			}
+			if target != nil {
+				edge, err := visit(target, target.Name())
+				if err != nil {
+					return nil, err
+				}
+				f.gqlType = strings.Replace(f.gqlType, "%s", edge.name, 1)
+			}
+			if f.unique {
+				e.keys = append(e.keys, f.name)
+			}
+			e.fields = append(e.fields, f)
+		}
+		return e, nil
+	}
+	for _, m := range models {
+		t := structType(reflect.TypeOf(mg.UnwrapSchema(m)))
+		if t == nil {
+			return nil, fmt.Errorf("federation: model %T is not a struct", m)
+		}
+		name := t.Name()
+		if s, ok := mg.UnwrapSchema(m).(mg.Schema); ok {
+			name = s.SchemaTypeName()
+		}
+		if _, err := visit(t, name); err != nil {
+			return nil, err
+		}
+	}
+	out := make([]*entity, 0, len(byName))
+	for _, e := range byName {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out, nil
+}
+
+// fieldOf maps a struct field to its GraphQL field. For edges it returns the
+// target struct type and a gqlType with a %s placeholder for its name. Fields
+// of types GraphQL cannot express are skipped.
+func fieldOf(sf reflect.StructField) (field, reflect.Type, bool) {
+	jsonName := strings.Split(sf.Tag.Get("json"), ",")[0]
+	if jsonName == "-" {
+		return field{}, nil, false
+	}
+	f := field{predicate: jsonName, name: jsonName}
+	if f.predicate == "" {
+		f.predicate = sf.Name
+	}
+	for _, directive := range strings.Fields(sf.Tag.Get("dgraph")) {
+		switch {
+		case directive == "unique":
+			f.unique = true
+		case directive == "required":
+			f.required = true
+		case strings.HasPrefix(directive, "predicate="):
+			f.predicate = strings.TrimPrefix(directive, "predicate=")
+		}
+	}
+	if !graphQLName.MatchString(f.name) {
+		f.name = strings.ToLower(sf.Name[:1]) + sf.Name[1:]
+	}
+
+	t := sf.Type
+	list := false
+	if t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+		list = true
+		t = t.Elem()
+	}
+	var target reflect.Type
+	elem := scalarType(t)
+	if elem == "" {
+		target = structType(t)
+		if target == nil {
+			return field{}, nil, false
+		}
+		elem = "%s"
+	}
+	if list {
+		f.gqlType = "[" + elem + "!]"
+	} else {
+		f.gqlType = elem
+	}
+	if f.required {
+		f.gqlType += "!"
+	}
+	return f, target, true
+}
+
+// scalarType returns the GraphQL scalar for t, or "" if t is not a scalar.
+func scalarType(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return "DateTime"
+	case decimalType:
+		// A string keeps every digit; GraphQL's Float would round.
+		return "String"
+	}
+	if geoTypes[t] {
+		return "GeoJSON"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "String"
+	case reflect.Bool:
+		return "Boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "Int"
+	case reflect.Float32, reflect.Float64:
+		return "Float"
+	}
+	return ""
+}
+
+// structType dereferences t to a struct type, or returns nil.
+func structType(t reflect.Type) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == timeType || t == decimalType || geoTypes[t] {
+		return nil
+	}
+	return t
+}
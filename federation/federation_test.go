@@ -0,0 +1,124 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package federation_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/federation"
+)
+
+type film struct {
+	UID      string             `json:"uid,omitempty"`
+	DType    []string           `json:"dgraph.type,omitempty"`
+	Title    string             `json:"title,omitempty" dgraph:"index=exact unique"`
+	Year     int                `json:"year,omitempty"`
+	Budget   modusgraph.Decimal `json:"budget,omitempty"`
+	Released time.Time          `json:"released,omitempty"`
+	Genres   []string           `json:"genres,omitempty"`
+	Director *director          `json:"director,omitempty"`
+}
+
+type director struct {
+	UID   string            `json:"uid,omitempty"`
+	DType []string          `json:"dgraph.type,omitempty"`
+	Email string            `json:"director.email,omitempty" dgraph:"index=hash unique"`
+	Name  string            `json:"name,omitempty" dgraph:"required"`
+	Films []*film           `json:"~director,omitempty" dgraph:"reverse"`
+	Born  *modusgraph.Point `json:"born,omitempty"`
+}
+
+func TestSDL(t *testing.T) {
+	sdl, err := federation.SDL(&film{})
+	if err != nil {
+		t.Fatalf("SDL: %v", err)
+	}
+	want := `extend schema
+  @link(url: "https://specs.apollo.dev/federation/v2.3", import: ["@key"])
+
+scalar DateTime
+
+scalar GeoJSON
+
+type director @key(fields: "id") @key(fields: "email") {
+  id: ID!
+  email: String
+  name: String!
+  films: [film!]
+  born: GeoJSON
+}
+
+type film @key(fields: "id") @key(fields: "title") {
+  id: ID!
+  title: String
+  year: Int
+  budget: String
+  released: DateTime
+  genres: [String!]
+  director: director
+}
+`
+	if sdl != want {
+		t.Fatalf("SDL mismatch\n got:\n%s\nwant:\n%s", sdl, want)
+	}
+}
+
+func TestSDLRejectsModelWithoutUID(t *testing.T) {
+	type keyless struct {
+		Name string `json:"name,omitempty"`
+	}
+	_, err := federation.SDL(&keyless{})
+	if err == nil || !strings.Contains(err.Error(), "no UID field") {
+		t.Fatalf("SDL error = %v, want missing UID", err)
+	}
+}
+
+func TestResolverEntities(t *testing.T) {
+	conn, err := modusgraph.NewClient("file://"+t.TempDir(), modusgraph.WithAutoSchema(true))
+	if err != nil {
+		t.Fatalf("modusgraph.NewClient: %v", err)
+	}
+	t.Cleanup(conn.Close)
+
+	ctx := context.Background()
+	f := &film{Title: "Metropolis", Year: 1927}
+	if err := conn.Insert(ctx, f); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+
+	r, err := federation.NewResolver(conn, &film{})
+	if err != nil {
+		t.Fatalf("NewResolver: %v", err)
+	}
+	got, err := r.Entities(ctx, []map[string]any{
+		{"__typename": "film", "id": f.UID},
+		{"__typename": "film", "title": "Metropolis"},
+		{"__typename": "film", "title": "Missing"},
+	})
+	if err != nil {
+		t.Fatalf("Entities: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("Entities returned %d results, want 3", len(got))
+	}
+	for i, obj := range got[:2] {
+		resolved, ok := obj.(*film)
+		if !ok || resolved.UID != f.UID || resolved.Year != 1927 {
+			t.Fatalf("entity %d = %+v, want film %s", i, obj, f.UID)
+		}
+	}
+	if got[2] != nil {
+		t.Fatalf("unmatched representation resolved to %+v, want nil", got[2])
+	}
+
+	if _, err := r.ResolveReference(ctx, map[string]any{"__typename": "film", "year": 1927}); err == nil {
+		t.Fatal("ResolveReference accepted a representation without a key")
+	}
+}
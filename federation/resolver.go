@@ -0,0 +1,98 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	dg "github.com/dolan-in/dgman/v2"
+	mg "github.com/matthewmcneely/modusgraph"
+)
+
+// Resolver answers federation reference lookups for the entities SDL emits.
+type Resolver struct {
+	client   mg.Client
+	entities map[string]*entity
+}
+
+// NewResolver returns a Resolver for models and every struct type reachable
+// from them, reading through client.
+func NewResolver(client mg.Client, models ...any) (*Resolver, error) {
+	entities, err := collect(models)
+	if err != nil {
+		return nil, err
+	}
+	r := &Resolver{client: client, entities: make(map[string]*entity, len(entities))}
+	for _, e := range entities {
+		r.entities[e.name] = e
+	}
+	return r, nil
+}
+
+// ResolveReference loads the node a representation refers to. The
+// representation is the object the router sends in _entities: "__typename"
+// plus the fields of one of the type's keys. An "id" key is read with Get; a
+// unique-field key is matched with eq on its predicate. It returns a pointer
+// to a new value of the model type, or nil when no node matches.
+func (r *Resolver) ResolveReference(ctx context.Context, representation map[string]any) (any, error) {
+	typename, _ := representation["__typename"].(string)
+	e, ok := r.entities[typename]
+	if !ok {
+		return nil, fmt.Errorf("federation: unknown entity type %q", typename)
+	}
+	obj := reflect.New(e.goType).Interface()
+	if id, ok := representation[IDField]; ok {
+		uid, ok := id.(string)
+		if !ok {
+			return nil, fmt.Errorf("federation: %s.%s must be a string, got %T", typename, IDField, id)
+		}
+		return found(obj, r.client.Get(ctx, obj, uid))
+	}
+	for _, f := range e.fields {
+		if !f.unique {
+			continue
+		}
+		value, ok := representation[f.name]
+		if !ok {
+			continue
+		}
+		q := r.client.Query(ctx, obj)
+		if q == nil {
+			return nil, errors.New("federation: failed to get client from pool")
+		}
+		err := q.Filter(fmt.Sprintf("eq(%s, $1)", f.predicate), value).First(1).Node(obj)
+		return found(obj, err)
+	}
+	return nil, fmt.Errorf("federation: representation of %s carries none of its keys %v", typename, e.keys)
+}
+
+// Entities resolves a batch of representations in order, as the _entities
+// field requires; unmatched representations resolve to nil.
+func (r *Resolver) Entities(ctx context.Context, representations []map[string]any) ([]any, error) {
+	out := make([]any, len(representations))
+	for i, rep := range representations {
+		obj, err := r.ResolveReference(ctx, rep)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = obj
+	}
+	return out, nil
+}
+
+// found maps dgman's not-found error to a nil entity.
+func found(obj any, err error) (any, error) {
+	if errors.Is(err, dg.ErrNodeNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
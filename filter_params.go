@@ -0,0 +1,211 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// ErrFilterParam is returned when a filter parameter cannot be bound: it is
+// nil, not a scalar or list of scalars, or a non-finite float, or the number
+// of ? placeholders does not match the parameters.
+var ErrFilterParam = errors.New("modusgraph: invalid filter parameter")
+
+// BindFilter prepares a DQL @filter expression and its parameters for
+// dgman's Filter, which binds $1, $2, ... positionally. Each ? outside string
+// and regular expression literals becomes the next $N, so
+//
+//	BindFilter("eq(author_name, ?) AND ge(year, ?)", name, 1990)
+//
+// binds name and 1990 in order; an expression may use ? or $N but not both.
+// Each parameter is rendered as a DQL literal of its type, whatever the
+// value: strings, byte slices and encoding.TextMarshaler values (time.Time,
+// UUIDs) as escaped, quoted strings, booleans and numbers bare, and slices
+// and arrays of those as lists. Any other value fails with ErrFilterParam
+// rather than being formatted into the query. Values that implement dgman's
+// ParamFormatter render themselves.
+func BindFilter(expr string, params ...any) (string, []any, error) {
+	bound := make([]any, len(params))
+	for i, p := range params {
+		lit, err := newFilterLiteral(p)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: parameter %d: %w", ErrFilterParam, i+1, err)
+		}
+		bound[i] = lit
+	}
+
+	marks := 0
+	dollars := false
+	expr = rewritePlaceholders(expr, func(tok string) string {
+		if tok != "?" {
+			dollars = true
+			return tok
+		}
+		marks++
+		return "$" + strconv.Itoa(marks)
+	})
+	if marks > 0 {
+		if dollars {
+			return "", nil, fmt.Errorf("%w: expression mixes ? and $N placeholders", ErrFilterParam)
+		}
+		if marks != len(params) {
+			return "", nil, fmt.Errorf("%w: expression has %d ? placeholders but %d parameters",
+				ErrFilterParam, marks, len(params))
+		}
+	}
+	return expr, bound, nil
+}
+
+// rewritePlaceholders returns expr with each ? and $N placeholder outside
+// string and regular expression literals replaced by f(placeholder).
+func rewritePlaceholders(expr string, f func(string) string) string {
+	var b strings.Builder
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; {
+		case c == '"':
+			end := literalEnd(expr, i, '"')
+			b.WriteString(expr[i:end])
+			i = end - 1
+		case c == '/' && regexAllowed(expr[:i]):
+			end := literalEnd(expr, i, '/')
+			b.WriteString(expr[i:end])
+			i = end - 1
+		case c == '?':
+			b.WriteString(f("?"))
+		case c == '$' && i+1 < len(expr) && isDigit(expr[i+1]):
+			j := i + 1
+			for j < len(expr) && isDigit(expr[j]) {
+				j++
+			}
+			b.WriteString(f(expr[i:j]))
+			i = j - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// literalEnd returns the index just past the literal opening at start and
+// closed by an unescaped quote, or len(expr) if it is not closed.
+func literalEnd(expr string, start int, quote byte) int {
+	for i := start + 1; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+	return len(expr)
+}
+
+// regexAllowed reports whether a / after prefix opens a regular expression
+// literal, which in a filter only follows the predicate of regexp(pred, ...).
+func regexAllowed(prefix string) bool {
+	prefix = strings.TrimRight(prefix, " \t\n")
+	return strings.HasSuffix(prefix, ",")
+}
+
+// filterLiteral is a parameter rendered as a DQL literal.
+type filterLiteral []byte
+
+// FormatParams implements dgman's ParamFormatter.
+func (l filterLiteral) FormatParams() []byte {
+	return l
+}
+
+var _ dg.ParamFormatter = filterLiteral(nil)
+
+// newFilterLiteral renders v as a DQL literal.
+func newFilterLiteral(v any) (filterLiteral, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, errors.New("nil value")
+	case filterLiteral:
+		return v, nil
+	case dg.ParamFormatter:
+		return v.FormatParams(), nil
+	case []byte:
+		return quoteFilterString(string(v))
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return quoteFilterString(string(text))
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil, errors.New("nil pointer")
+		}
+		return newFilterLiteral(rv.Elem().Interface())
+	case reflect.String:
+		return quoteFilterString(rv.String())
+	case reflect.Bool:
+		return filterLiteral(strconv.FormatBool(rv.Bool())), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return filterLiteral(strconv.FormatInt(rv.Int(), 10)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return filterLiteral(strconv.FormatUint(rv.Uint(), 10)), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("non-finite float %v", f)
+		}
+		return filterLiteral(strconv.FormatFloat(f, 'f', -1, rv.Type().Bits())), nil
+	case reflect.Slice, reflect.Array:
+		if rv.Len() == 0 {
+			return nil, errors.New("empty list")
+		}
+		var b bytes.Buffer
+		b.WriteByte('[')
+		for i := 0; i < rv.Len(); i++ {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			lit, err := newFilterLiteral(rv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			if bytes.HasPrefix(lit, []byte("[")) {
+				return nil, errors.New("nested list")
+			}
+			b.Write(lit)
+		}
+		b.WriteByte(']')
+		return b.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unsupported type %T", v)
+}
+
+// quoteFilterString renders s as a DQL string literal.
+func quoteFilterString(s string) (filterLiteral, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
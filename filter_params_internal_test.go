@@ -0,0 +1,69 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type filterStatus string
+
+func TestBindFilterPlaceholders(t *testing.T) {
+	expr, params, err := BindFilter(`eq(name, ?) AND regexp(code, /^a?b$/i) AND eq(note, "why?") AND ge(n, ?)`, "x", 3)
+	require.NoError(t, err)
+	assert.Equal(t, `eq(name, $1) AND regexp(code, /^a?b$/i) AND eq(note, "why?") AND ge(n, $2)`, expr)
+	require.Len(t, params, 2)
+
+	expr, _, err = BindFilter("eq(name, $1)", "x")
+	require.NoError(t, err)
+	assert.Equal(t, "eq(name, $1)", expr)
+
+	_, _, err = BindFilter("eq(name, ?) AND eq(n, $2)", "x", 1)
+	assert.ErrorIs(t, err, ErrFilterParam)
+	_, _, err = BindFilter("eq(name, ?)")
+	assert.ErrorIs(t, err, ErrFilterParam)
+}
+
+func TestBindFilterLiterals(t *testing.T) {
+	ts := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	name := "ada"
+	tests := []struct {
+		in   any
+		want string
+	}{
+		{"plain", `"plain"`},
+		{`a"b\c`, `"a\"b\\c"`},
+		{"<tag>", `"<tag>"`},
+		{filterStatus("Active"), `"Active"`},
+		{&name, `"ada"`},
+		{[]byte("raw"), `"raw"`},
+		{true, "true"},
+		{-42, "-42"},
+		{uint8(7), "7"},
+		{1.5, "1.5"},
+		{float32(0.25), "0.25"},
+		{1e21, "1000000000000000000000"},
+		{ts, `"2026-05-01T12:00:00Z"`},
+		{[]string{"a", "b"}, `["a", "b"]`},
+		{[2]int{1, 2}, "[1, 2]"},
+	}
+	for _, tt := range tests {
+		lit, err := newFilterLiteral(tt.in)
+		require.NoError(t, err, "%#v", tt.in)
+		assert.Equal(t, tt.want, string(lit), "%#v", tt.in)
+	}
+
+	var nilName *string
+	for _, bad := range []any{nil, nilName, math.NaN(), math.Inf(1), struct{}{}, map[string]int{}, []int{}, [][]int{{1}}} {
+		_, _, err := BindFilter("eq(p, ?)", bad)
+		assert.ErrorIs(t, err, ErrFilterParam, "%#v", bad)
+	}
+}
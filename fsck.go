@@ -0,0 +1,380 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/dgraph-io/dgo/v250"
+	"github.com/dgraph-io/dgraph/v25/posting"
+	"github.com/dgraph-io/dgraph/v25/protos/pb"
+	"github.com/dgraph-io/dgraph/v25/worker"
+	"github.com/dgraph-io/dgraph/v25/x"
+	"github.com/go-logr/logr"
+	"google.golang.org/protobuf/proto"
+)
+
+// FsckOptions configures Fsck.
+type FsckOptions struct {
+	// SkipGraph stops after the storage checks, without opening the store
+	// as a database.
+	SkipGraph bool
+
+	// Logger receives progress messages; the zero value discards them.
+	Logger logr.Logger
+}
+
+// FsckReport lists the problems Fsck found.
+type FsckReport struct {
+	// Keys is the number of key versions read from storage.
+	Keys int
+
+	// StorageErrors are failed table checksums, unparsable keys and posting
+	// lists that do not decode.
+	StorageErrors []string
+
+	IndexMismatches []IndexMismatch
+	TypeMismatches  []TypeMismatch
+}
+
+// IndexMismatch is a disagreement between a node's value of an indexed
+// predicate and what an eq() lookup of that value through the index returns.
+type IndexMismatch struct {
+	Predicate string
+	Value     string
+	UID       string
+	// MissingFromIndex is true when the node holds Value but the lookup
+	// does not return it, and false when the lookup returns a node that
+	// does not hold Value.
+	MissingFromIndex bool
+}
+
+// TypeMismatch is a disagreement between a node's dgraph.type values and the
+// nodes type() returns, or a node of a type the schema does not declare.
+type TypeMismatch struct {
+	UID  string
+	Type string
+	// Undeclared is true when the schema has no type named Type.
+	Undeclared bool
+	// MissingFromIndex is true when the node has Type but type(Type) does not
+	// return it, and false when type(Type) returns a node without Type.
+	MissingFromIndex bool
+}
+
+// Empty reports whether no problems were found.
+func (r *FsckReport) Empty() bool {
+	return len(r.StorageErrors) == 0 && len(r.IndexMismatches) == 0 && len(r.TypeMismatches) == 0
+}
+
+// String renders the report one problem per line.
+func (r *FsckReport) String() string {
+	if r.Empty() {
+		return fmt.Sprintf("no problems found in %d keys\n", r.Keys)
+	}
+	var sb strings.Builder
+	for _, e := range r.StorageErrors {
+		fmt.Fprintf(&sb, "storage: %s\n", e)
+	}
+	for _, m := range r.IndexMismatches {
+		side := "index entry for a value the node does not hold"
+		if m.MissingFromIndex {
+			side = "value missing from index"
+		}
+		fmt.Fprintf(&sb, "%s: %s %s = %q\n", side, m.UID, m.Predicate, m.Value)
+	}
+	for _, m := range r.TypeMismatches {
+		switch {
+		case m.Undeclared:
+			fmt.Fprintf(&sb, "undeclared type: %s has type %s\n", m.UID, m.Type)
+		case m.MissingFromIndex:
+			fmt.Fprintf(&sb, "type missing from index: %s has type %s\n", m.UID, m.Type)
+		default:
+			fmt.Fprintf(&sb, "type index entry for a node without the type: %s %s\n", m.UID, m.Type)
+		}
+	}
+	return sb.String()
+}
+
+// fsckTokenizers are the equality index tokenizers Fsck checks: eq()
+// through them returns exactly the nodes holding the value.
+var fsckTokenizers = []string{"exact", "hash", "int", "bool"}
+
+// Fsck checks the file:// store in dir, which must not be open in any
+// process: the checksums of its storage tables, that every key parses and
+// every posting list decodes, and then, with the store opened as a database,
+// that equality indexes and the type index agree with the values nodes hold.
+// Run it on a copy of the directory after a crash or disk problem; the graph
+// checks replay the write-ahead log like any open does. Fsck returns
+// ErrSingletonOnly if this process already has an engine open.
+func Fsck(dir string, opts FsckOptions) (*FsckReport, error) {
+	if singleton.Load() {
+		return nil, ErrSingletonOnly
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	logger := opts.Logger
+	report := &FsckReport{}
+
+	logger.V(1).Info("Checking storage", "dir", dir)
+	if err := fsckStorage(path.Join(dir, "p"), report); err != nil {
+		return nil, err
+	}
+	if opts.SkipGraph || len(report.StorageErrors) > 0 {
+		// Opening a corrupt store as a database could fail or panic; report
+		// what the storage checks found instead.
+		return report, nil
+	}
+
+	logger.V(1).Info("Checking graph", "dir", dir)
+	engine, err := NewEngine(NewDefaultConfig(dir).WithLogger(logger))
+	if err != nil {
+		return nil, err
+	}
+	defer Shutdown()
+	ns := engine.GetDefaultNamespace()
+	c := client{
+		uri:     fileURIPrefix + dir,
+		options: clientOptions{logger: logger, maxEdgeTraversal: 10},
+		logger:  logger,
+		engine:  engine,
+		ns:      ns,
+	}
+	c.pool = newClientPool(1, func() (*dgo.Dgraph, error) {
+		//nolint:staticcheck // dgo.NewDgraphClient is deprecated but required for embedded client
+		return dgo.NewDgraphClient(newEmbeddedDgraphClient(engine, ns)), nil
+	}, logger)
+	defer c.pool.close()
+
+	ctx := context.Background()
+	schema, err := c.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.fsckTypes(ctx, schema, report); err != nil {
+		return nil, err
+	}
+	for _, p := range schema.Predicates {
+		if slices.ContainsFunc(p.Indexes, func(tok string) bool { return slices.Contains(fsckTokenizers, tok) }) {
+			if err := c.fsckIndex(ctx, p, report); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return report, nil
+}
+
+// fsckStorage verifies the table checksums of the badger store in dir and
+// reads every version of every key.
+func fsckStorage(dir string, report *FsckReport) error {
+	opt := badger.DefaultOptions(dir).FromSuperFlag(worker.BadgerDefaults).WithLogger(nil)
+	db, err := badger.OpenManaged(opt)
+	if err != nil {
+		return fmt.Errorf("opening storage: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	if err := db.VerifyChecksum(); err != nil {
+		report.StorageErrors = append(report.StorageErrors, err.Error())
+	}
+
+	txn := db.NewTransactionAt(math.MaxUint64, false)
+	defer txn.Discard()
+	iopt := badger.DefaultIteratorOptions
+	iopt.AllVersions = true
+	iopt.PrefetchValues = false
+	it := txn.NewIterator(iopt)
+	defer it.Close()
+	for it.Rewind(); it.Valid(); it.Next() {
+		item := it.Item()
+		report.Keys++
+		key := item.KeyCopy(nil)
+		if _, err := x.Parse(key); err != nil {
+			report.StorageErrors = append(report.StorageErrors, fmt.Sprintf("key %x: %v", key, err))
+			continue
+		}
+		if item.UserMeta()&(posting.BitCompletePosting|posting.BitDeltaPosting) == 0 {
+			continue
+		}
+		err := item.Value(func(val []byte) error {
+			return proto.Unmarshal(val, &pb.PostingList{})
+		})
+		if err != nil {
+			report.StorageErrors = append(report.StorageErrors,
+				fmt.Sprintf("posting list %x at version %d: %v", key, item.Version(), err))
+		}
+	}
+	return nil
+}
+
+// fsckTypes compares the dgraph.type values of nodes with type().
+func (c client) fsckTypes(ctx context.Context, schema *SchemaInfo, report *FsckReport) error {
+	resp, err := c.QueryRaw(ctx, "{\n  q(func: has(dgraph.type)) {\n    uid\n    t: dgraph.type\n  }\n}\n", nil)
+	if err != nil {
+		return fmt.Errorf("reading node types: %w", err)
+	}
+	var result struct {
+		Q []struct {
+			UID   string   `json:"uid"`
+			Types []string `json:"t"`
+		} `json:"q"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return err
+	}
+	members := map[string][]string{}
+	for _, node := range result.Q {
+		for _, t := range node.Types {
+			if _, ok := schema.Type(t); !ok && !isInternalName(t) {
+				report.TypeMismatches = append(report.TypeMismatches, TypeMismatch{UID: node.UID, Type: t, Undeclared: true})
+				continue
+			}
+			members[t] = append(members[t], node.UID)
+		}
+	}
+
+	for _, t := range schema.Types {
+		resp, err := c.QueryRaw(ctx, fmt.Sprintf("{\n  q(func: type(%s)) {\n    uid\n  }\n}\n", t.Name), nil)
+		if err != nil {
+			return fmt.Errorf("reading type %s: %w", t.Name, err)
+		}
+		indexed, err := resultUIDs(resp)
+		if err != nil {
+			return err
+		}
+		missing, stale := compareUIDs(members[t.Name], indexed)
+		for _, uid := range missing {
+			report.TypeMismatches = append(report.TypeMismatches, TypeMismatch{UID: uid, Type: t.Name, MissingFromIndex: true})
+		}
+		for _, uid := range stale {
+			report.TypeMismatches = append(report.TypeMismatches, TypeMismatch{UID: uid, Type: t.Name})
+		}
+	}
+	return nil
+}
+
+// fsckIndex looks up each value of the predicate p through its index and
+// compares the result with the nodes holding the value.
+func (c client) fsckIndex(ctx context.Context, p PredicateInfo, report *FsckReport) error {
+	q := fmt.Sprintf("{\n  q(func: has(<%s>)) {\n    uid\n    v: <%s>\n  }\n}\n", p.Name, p.Name)
+	resp, err := c.QueryRaw(ctx, q, nil)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", p.Name, err)
+	}
+	var result struct {
+		Q []struct {
+			UID   string          `json:"uid"`
+			Value json.RawMessage `json:"v"`
+		} `json:"q"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return err
+	}
+	holders := map[string][]string{}
+	var values []string
+	for _, node := range result.Q {
+		vals, err := scalarValues(node.Value)
+		if err != nil {
+			return fmt.Errorf("decoding %s of %s: %w", p.Name, node.UID, err)
+		}
+		for _, v := range vals {
+			if _, ok := holders[v]; !ok {
+				values = append(values, v)
+			}
+			holders[v] = append(holders[v], node.UID)
+		}
+	}
+
+	query := fmt.Sprintf("query q($v: string) {\n  q(func: eq(<%s>, $v)) {\n    uid\n  }\n}\n", p.Name)
+	for _, v := range values {
+		resp, err := c.QueryRaw(ctx, query, map[string]string{"$v": v})
+		if err != nil {
+			return fmt.Errorf("looking up %s = %q: %w", p.Name, v, err)
+		}
+		indexed, err := resultUIDs(resp)
+		if err != nil {
+			return err
+		}
+		missing, stale := compareUIDs(holders[v], indexed)
+		for _, uid := range missing {
+			report.IndexMismatches = append(report.IndexMismatches, IndexMismatch{p.Name, v, uid, true})
+		}
+		for _, uid := range stale {
+			report.IndexMismatches = append(report.IndexMismatches, IndexMismatch{p.Name, v, uid, false})
+		}
+	}
+	return nil
+}
+
+// scalarValues renders a scalar or list value of a query result as the
+// strings eq() accepts.
+func scalarValues(raw json.RawMessage) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	list, ok := v.([]any)
+	if !ok {
+		list = []any{v}
+	}
+	vals := make([]string, len(list))
+	for i, e := range list {
+		vals[i] = fmt.Sprint(e)
+	}
+	return vals, nil
+}
+
+// resultUIDs returns the uids of the q block of a query result.
+func resultUIDs(resp []byte) ([]string, error) {
+	var result struct {
+		Q []struct {
+			UID string `json:"uid"`
+		} `json:"q"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, err
+	}
+	uids := make([]string, len(result.Q))
+	for i, node := range result.Q {
+		uids[i] = node.UID
+	}
+	return uids, nil
+}
+
+// compareUIDs returns the uids of want missing from got, and those of got
+// not in want.
+func compareUIDs(want, got []string) (missing, extra []string) {
+	in := func(uids []string) map[string]bool {
+		set := make(map[string]bool, len(uids))
+		for _, uid := range uids {
+			set[uid] = true
+		}
+		return set
+	}
+	wantSet, gotSet := in(want), in(got)
+	for _, uid := range want {
+		if !gotSet[uid] {
+			missing = append(missing, uid)
+		}
+	}
+	for _, uid := range got {
+		if !wantSet[uid] {
+			extra = append(extra, uid)
+		}
+	}
+	return missing, extra
+}
@@ -0,0 +1,88 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type FsckItem struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"fsck_name,omitempty" dgraph:"index=exact"`
+	Count int      `json:"fsck_count,omitempty" dgraph:"index=int"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+func TestFsck(t *testing.T) {
+	dir := GetTempDir(t)
+	ctx := context.Background()
+
+	client, err := mg.NewClient("file://"+dir, mg.WithAutoSchema(true))
+	require.NoError(t, err)
+	for i, name := range []string{"lamp", "desk", "lamp"} {
+		require.NoError(t, client.Insert(ctx, &FsckItem{Name: name, Count: i + 1}))
+	}
+
+	// Fsck refuses to run beside an open engine.
+	_, err = mg.Fsck(dir, mg.FsckOptions{})
+	require.ErrorIs(t, err, mg.ErrSingletonOnly)
+
+	// A node of a type the schema does not declare.
+	dgClient, release, err := client.DgraphClient()
+	require.NoError(t, err)
+	_, err = dgClient.NewTxn().Mutate(ctx, &api.Mutation{
+		SetNquads: []byte(`_:g <fsck_name> "ghost" .
+_:g <dgraph.type> "FsckGhost" .`),
+		CommitNow: true,
+	})
+	release()
+	require.NoError(t, err)
+	client.Close()
+	mg.Shutdown()
+
+	report, err := mg.Fsck(dir, mg.FsckOptions{})
+	require.NoError(t, err)
+	require.Positive(t, report.Keys)
+	require.Empty(t, report.StorageErrors)
+	require.Empty(t, report.IndexMismatches)
+	require.Len(t, report.TypeMismatches, 1)
+	require.True(t, report.TypeMismatches[0].Undeclared)
+	require.Equal(t, "FsckGhost", report.TypeMismatches[0].Type)
+	require.Contains(t, report.String(), "undeclared type:")
+
+	// The store opens normally afterwards.
+	client, err = mg.NewClient("file://" + dir)
+	require.NoError(t, err)
+	var items []FsckItem
+	require.NoError(t, client.Query(ctx, FsckItem{}).Nodes(&items))
+	require.Len(t, items, 3)
+	client.Close()
+	mg.Shutdown()
+
+	// A key that is not a Dgraph key is a storage error, and stops Fsck
+	// before the graph checks.
+	db, err := badger.OpenManaged(badger.DefaultOptions(filepath.Join(dir, "p")).WithLogger(nil))
+	require.NoError(t, err)
+	txn := db.NewTransactionAt(math.MaxUint64, true)
+	require.NoError(t, txn.Set([]byte{0x00, 0x01}, []byte("garbage")))
+	require.NoError(t, txn.CommitAt(math.MaxUint64-1, nil))
+	require.NoError(t, db.Close())
+
+	report, err = mg.Fsck(dir, mg.FsckOptions{})
+	require.NoError(t, err)
+	require.Len(t, report.StorageErrors, 1)
+	require.Contains(t, report.StorageErrors[0], "key 0001")
+	require.Empty(t, report.TypeMismatches)
+	require.False(t, report.Empty())
+}
@@ -0,0 +1,224 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"errors"
+	"iter"
+
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// Get loads the T with the given UID. opts override the client's edge depth
+// and fan-out limits for this call.
+func Get[T any](ctx context.Context, client Client, uid string, opts ...GetOpt) (*T, error) {
+	var out T
+	if err := client.Get(ctx, &out, uid, opts...); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Insert inserts recs as new nodes in one transaction, writing the UIDs they
+// are assigned back into them.
+func Insert[T any](ctx context.Context, client Client, recs ...*T) error {
+	switch len(recs) {
+	case 0:
+		return nil
+	case 1:
+		return client.Insert(ctx, recs[0])
+	}
+	return client.Insert(ctx, &recs)
+}
+
+// InsertBatch inserts recs with Client.InsertBatch. Pass FromChan(ch) to
+// insert the records sent on a channel.
+func InsertBatch[T any](ctx context.Context, client Client, recs iter.Seq[*T], opts BatchOptions) error {
+	return client.InsertBatch(ctx, func(yield func(any) bool) {
+		for rec := range recs {
+			if !yield(rec) {
+				return
+			}
+		}
+	}, opts)
+}
+
+// FromChan returns a sequence of the values received from ch until it is
+// closed.
+func FromChan[T any](ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range ch {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Update writes rec, which must have its UID set, over the stored node.
+func Update[T any](ctx context.Context, client Client, rec *T) error {
+	return client.Update(ctx, rec)
+}
+
+// Upsert inserts or updates rec, matching against predicates. With no
+// predicates, the first field tagged dgraph:"upsert" is used.
+func Upsert[T any](ctx context.Context, client Client, rec *T, predicates ...string) error {
+	return client.Upsert(ctx, rec, predicates...)
+}
+
+// Delete removes the node rec, which must have its UID set.
+func Delete[T any](ctx context.Context, client Client, rec *T, opts ...DeleteOpt) error {
+	return client.Delete(ctx, rec, opts...)
+}
+
+// TypedQuery is a query over the nodes of type T, returned by Query. Its
+// builder methods return the query itself so calls chain, and All, First and
+// Stream run it.
+type TypedQuery[T any] struct {
+	ctx     context.Context
+	client  Client
+	q       *dg.Query
+	conds   []Condition
+	limit   int  // the Limit set, which Stream pages within; 0 for none
+	offset  int  // the Offset set, where Stream starts
+	deleted bool // whether soft-deleted nodes are included
+}
+
+// Query returns a query over every node of type T, expanded to the client's
+// edge depth and fan-out limits:
+//
+//	films, err := modusgraph.Query[Film](ctx, client).
+//	    Filter("ge(release_year, ?)", 1990).
+//	    OrderAsc("title").
+//	    All()
+func Query[T any](ctx context.Context, client Client) *TypedQuery[T] {
+	var model T
+	return &TypedQuery[T]{ctx: ctx, client: client, q: client.Query(ctx, &model)}
+}
+
+// Filter restricts the query to nodes matching the DQL filter expression.
+// params bind to its ? or $N placeholders as in BindFilter. Repeated calls
+// are ANDed together.
+func (tq *TypedQuery[T]) Filter(expr string, params ...any) *TypedQuery[T] {
+	tq.conds = append(tq.conds, Cond(expr, params...))
+	return tq
+}
+
+// Near restricts the query to nodes whose geo predicate lies within meters
+// of p.
+func (tq *TypedQuery[T]) Near(predicate string, p Point, meters float64) *TypedQuery[T] {
+	return tq.Filter("near("+predicate+", ?, ?)", p, meters)
+}
+
+// Within restricts the query to nodes whose geo predicate lies inside area,
+// a Polygon or MultiPolygon.
+func (tq *TypedQuery[T]) Within(predicate string, area Geometry) *TypedQuery[T] {
+	return tq.Filter("within("+predicate+", ?)", area)
+}
+
+// Contains restricts the query to nodes whose geo predicate, an area,
+// contains g, a Point or Polygon.
+func (tq *TypedQuery[T]) Contains(predicate string, g Geometry) *TypedQuery[T] {
+	return tq.Filter("contains("+predicate+", ?)", g)
+}
+
+// Intersects restricts the query to nodes whose geo predicate, an area,
+// overlaps area.
+func (tq *TypedQuery[T]) Intersects(predicate string, area Geometry) *TypedQuery[T] {
+	return tq.Filter("intersects("+predicate+", ?)", area)
+}
+
+// IncludeDeleted makes the query also return the nodes Delete marked
+// deleted, when T has a dgraph:"softdelete" field; see SoftDeletePredicate.
+func (tq *TypedQuery[T]) IncludeDeleted() *TypedQuery[T] {
+	tq.deleted = true
+	return tq
+}
+
+// OrderAsc orders results ascending by predicate.
+func (tq *TypedQuery[T]) OrderAsc(predicate string) *TypedQuery[T] {
+	if tq.q != nil {
+		tq.q.OrderAsc(predicate)
+	}
+	return tq
+}
+
+// OrderDesc orders results descending by predicate.
+func (tq *TypedQuery[T]) OrderDesc(predicate string) *TypedQuery[T] {
+	if tq.q != nil {
+		tq.q.OrderDesc(predicate)
+	}
+	return tq
+}
+
+// Limit caps the number of results.
+func (tq *TypedQuery[T]) Limit(n int) *TypedQuery[T] {
+	tq.limit = n
+	if tq.q != nil {
+		tq.q.First(n)
+	}
+	return tq
+}
+
+// Offset skips the first n results.
+func (tq *TypedQuery[T]) Offset(n int) *TypedQuery[T] {
+	tq.offset = n
+	if tq.q != nil {
+		tq.q.Offset(n)
+	}
+	return tq
+}
+
+// All runs the query and returns every matching T.
+func (tq *TypedQuery[T]) All() ([]T, error) {
+	if err := tq.prepare(); err != nil {
+		return nil, err
+	}
+	var out []T
+	if err := tq.q.Nodes(&out); err != nil {
+		return nil, err
+	}
+	NormalizeTimes(tq.client, out)
+	return out, nil
+}
+
+// First runs the query with a limit of one and returns the first T, or nil
+// when nothing matched.
+func (tq *TypedQuery[T]) First() (*T, error) {
+	out, err := tq.Limit(1).All()
+	if err != nil || len(out) == 0 {
+		return nil, err
+	}
+	return &out[0], nil
+}
+
+// prepare applies the accumulated filters to the underlying query.
+func (tq *TypedQuery[T]) prepare() error {
+	if tq.q == nil {
+		return errors.New("modusgraph: query has no connection")
+	}
+	filter, err := tq.filter()
+	if err != nil || filter == "" {
+		return err
+	}
+	tq.q.Filter(filter)
+	return nil
+}
+
+// filter renders the accumulated filters, with the one leaving out
+// soft-deleted nodes, or returns "" when there are none.
+func (tq *TypedQuery[T]) filter() (string, error) {
+	conds := tq.conds
+	var model T
+	if pred, ok := SoftDeletePredicate(&model); ok && !tq.deleted {
+		conds = append([]Condition{Not(Has(pred))}, conds...)
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return And(conds...).render()
+}
@@ -0,0 +1,91 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type GenericFilm struct {
+	UID   string   `json:"uid,omitempty"`
+	Title string   `json:"gfilm_title,omitempty" dgraph:"index=exact upsert"`
+	Year  int      `json:"gfilm_year,omitempty" dgraph:"index=int"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+func TestGenericOperations(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "GenericOperationsWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "GenericOperationsWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			ctx := context.Background()
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			alien := &GenericFilm{Title: "Alien", Year: 1979}
+			require.NoError(t, mg.Insert(ctx, client, alien))
+			require.NotEmpty(t, alien.UID)
+			films := []*GenericFilm{{Title: "Heat", Year: 1995}, {Title: "Dune", Year: 2021}}
+			require.NoError(t, mg.Insert(ctx, client, films...))
+			require.NotEmpty(t, films[1].UID)
+
+			got, err := mg.Get[GenericFilm](ctx, client, alien.UID)
+			require.NoError(t, err)
+			require.Equal(t, "Alien", got.Title)
+
+			got.Year = 1980
+			require.NoError(t, mg.Update(ctx, client, got))
+			require.NoError(t, mg.Upsert(ctx, client, &GenericFilm{Title: "Heat", Year: 1996}))
+
+			all, err := mg.Query[GenericFilm](ctx, client).OrderAsc("gfilm_year").All()
+			require.NoError(t, err)
+			require.Len(t, all, 3)
+			require.Equal(t, []int{1980, 1996, 2021}, []int{all[0].Year, all[1].Year, all[2].Year})
+
+			recent, err := mg.Query[GenericFilm](ctx, client).
+				Filter("ge(gfilm_year, ?)", 1990).
+				Filter("NOT eq(gfilm_title, $1)", "Dune").
+				All()
+			require.NoError(t, err)
+			require.Len(t, recent, 1)
+			require.Equal(t, "Heat", recent[0].Title)
+
+			first, err := mg.Query[GenericFilm](ctx, client).OrderDesc("gfilm_year").First()
+			require.NoError(t, err)
+			require.Equal(t, "Dune", first.Title)
+			none, err := mg.Query[GenericFilm](ctx, client).Filter("eq(gfilm_title, ?)", `x") OR has(gfilm_title`).First()
+			require.NoError(t, err)
+			require.Nil(t, none)
+
+			_, err = mg.Query[GenericFilm](ctx, client).Filter("eq(gfilm_title, ?)").All()
+			require.ErrorIs(t, err, mg.ErrFilterParam)
+		})
+	}
+}
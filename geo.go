@@ -0,0 +1,376 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// ErrInvalidGeo is returned for coordinates out of range, rings too short
+// to enclose an area, and GeoJSON of another geometry type than expected.
+var ErrInvalidGeo = errors.New("modusgraph: invalid geometry")
+
+// Point is a location for geo predicates, as longitude and latitude in
+// degrees. Fields of this type, and of Polygon and MultiPolygon, are stored
+// in geo predicates as GeoJSON and can be queried with Dgraph's geo
+// functions, taking the geometry as a filter parameter:
+//
+//	type Store struct {
+//	    Name     string          `json:"name,omitempty"`
+//	    Location modusgraph.Point `json:"location,omitempty" dgraph:"index=geo"`
+//	    // UID and DType omitted
+//	}
+//
+//	store := &Store{Name: "Ferry Building", Location: modusgraph.NewPoint(-122.3937, 37.7955)}
+//	nearby, err := modusgraph.Query[Store](ctx, client).
+//	    Filter("near(location, ?, 1000)", modusgraph.NewPoint(-122.4, 37.79)).
+//	    All()
+//
+// Like Decimal, the zero Point is unset: it is written as null, which leaves
+// the stored value alone.
+type Point struct {
+	lng, lat float64
+	set      bool
+}
+
+// NewPoint returns the point at longitude lng and latitude lat. Note the
+// order, which is GeoJSON's.
+func NewPoint(lng, lat float64) Point {
+	return Point{lng: lng, lat: lat, set: true}
+}
+
+// Lng returns p's longitude in degrees.
+func (p Point) Lng() float64 { return p.lng }
+
+// Lat returns p's latitude in degrees.
+func (p Point) Lat() float64 { return p.lat }
+
+// IsSet reports whether p holds a value.
+func (p Point) IsSet() bool { return p.set }
+
+// String returns p as GeoJSON.
+func (p Point) String() string { return geoString(p) }
+
+// SchemaType reports the Dgraph type of the predicate to dgman.
+func (Point) SchemaType() string { return "geo" }
+
+// MarshalJSON writes p as a GeoJSON Point, or null when p is unset.
+func (p Point) MarshalJSON() ([]byte, error) {
+	if !p.set {
+		return []byte("null"), nil
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(geoJSON{Type: "Point", Coordinates: p.coordinates()})
+}
+
+// UnmarshalJSON reads a GeoJSON Point. null leaves p unset.
+func (p *Point) UnmarshalJSON(data []byte) error {
+	var c [2]float64
+	ok, err := unmarshalGeo(data, "Point", &c)
+	if err != nil || !ok {
+		*p = Point{}
+		return err
+	}
+	*p = NewPoint(c[0], c[1])
+	return p.validate()
+}
+
+// FormatParams renders p as the [lng, lat] literal of Dgraph's geo
+// functions, such as near and contains.
+func (p Point) FormatParams() []byte {
+	return geoLiteral(p.coordinates())
+}
+
+func (p Point) coordinates() [2]float64 {
+	return [2]float64{p.lng, p.lat}
+}
+
+func (p Point) validate() error {
+	if math.IsNaN(p.lng) || math.IsNaN(p.lat) || math.Abs(p.lng) > 180 || math.Abs(p.lat) > 90 {
+		return fmt.Errorf("%w: point (%v, %v) is not a longitude and latitude", ErrInvalidGeo, p.lng, p.lat)
+	}
+	return nil
+}
+
+// Polygon is an area for geo predicates: an outer ring, optionally followed
+// by rings cutting holes in it. Its zero value is unset, as for Point.
+type Polygon struct {
+	rings [][]Point
+}
+
+// NewPolygon returns the polygon with the given rings, the first enclosing
+// the area and any others holes in it. A ring needs at least three distinct
+// points; it is closed by repeating its first point if it is not already.
+func NewPolygon(rings ...[]Point) (Polygon, error) {
+	if len(rings) == 0 {
+		return Polygon{}, fmt.Errorf("%w: a polygon needs a ring", ErrInvalidGeo)
+	}
+	poly := Polygon{rings: make([][]Point, len(rings))}
+	for i, ring := range rings {
+		if len(ring) > 0 && ring[0] != ring[len(ring)-1] {
+			ring = append(ring[:len(ring):len(ring)], ring[0])
+		}
+		poly.rings[i] = ring
+	}
+	if err := poly.validate(); err != nil {
+		return Polygon{}, err
+	}
+	return poly, nil
+}
+
+// MustPolygon is like NewPolygon but panics if the rings are invalid.
+func MustPolygon(rings ...[]Point) Polygon {
+	poly, err := NewPolygon(rings...)
+	if err != nil {
+		panic(err)
+	}
+	return poly
+}
+
+// Rings returns the rings of poly, each closed.
+func (poly Polygon) Rings() [][]Point {
+	rings := make([][]Point, len(poly.rings))
+	for i, r := range poly.rings {
+		rings[i] = append([]Point(nil), r...)
+	}
+	return rings
+}
+
+// IsSet reports whether poly holds a value.
+func (poly Polygon) IsSet() bool { return len(poly.rings) > 0 }
+
+// String returns poly as GeoJSON.
+func (poly Polygon) String() string { return geoString(poly) }
+
+// SchemaType reports the Dgraph type of the predicate to dgman.
+func (Polygon) SchemaType() string { return "geo" }
+
+// MarshalJSON writes poly as a GeoJSON Polygon, or null when poly is unset.
+func (poly Polygon) MarshalJSON() ([]byte, error) {
+	if !poly.IsSet() {
+		return []byte("null"), nil
+	}
+	if err := poly.validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(geoJSON{Type: "Polygon", Coordinates: poly.coordinates()})
+}
+
+// UnmarshalJSON reads a GeoJSON Polygon. null leaves poly unset.
+func (poly *Polygon) UnmarshalJSON(data []byte) error {
+	var c [][][2]float64
+	ok, err := unmarshalGeo(data, "Polygon", &c)
+	if err != nil || !ok {
+		*poly = Polygon{}
+		return err
+	}
+	*poly = polygonOf(c)
+	return poly.validate()
+}
+
+// FormatParams renders poly as the [[[lng, lat], ...]] literal of Dgraph's
+// geo functions, such as within and intersects.
+func (poly Polygon) FormatParams() []byte {
+	return geoLiteral(poly.coordinates())
+}
+
+func (poly Polygon) coordinates() [][][2]float64 {
+	c := make([][][2]float64, len(poly.rings))
+	for i, ring := range poly.rings {
+		c[i] = make([][2]float64, len(ring))
+		for j, p := range ring {
+			c[i][j] = p.coordinates()
+		}
+	}
+	return c
+}
+
+func polygonOf(c [][][2]float64) Polygon {
+	poly := Polygon{rings: make([][]Point, len(c))}
+	for i, ring := range c {
+		poly.rings[i] = make([]Point, len(ring))
+		for j, p := range ring {
+			poly.rings[i][j] = NewPoint(p[0], p[1])
+		}
+	}
+	return poly
+}
+
+func (poly Polygon) validate() error {
+	if len(poly.rings) == 0 {
+		return fmt.Errorf("%w: a polygon needs a ring", ErrInvalidGeo)
+	}
+	for i, ring := range poly.rings {
+		if len(ring) < 4 || ring[0] != ring[len(ring)-1] {
+			return fmt.Errorf("%w: ring %d needs at least three distinct points and must be closed", ErrInvalidGeo, i)
+		}
+		for _, p := range ring {
+			if err := p.validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MultiPolygon is an area made of several polygons, such as a country with
+// islands. Its zero value is unset, as for Point.
+type MultiPolygon struct {
+	polygons []Polygon
+}
+
+// NewMultiPolygon returns the area covered by polygons.
+func NewMultiPolygon(polygons ...Polygon) (MultiPolygon, error) {
+	mp := MultiPolygon{polygons: append([]Polygon(nil), polygons...)}
+	if err := mp.validate(); err != nil {
+		return MultiPolygon{}, err
+	}
+	return mp, nil
+}
+
+// Polygons returns the polygons of mp.
+func (mp MultiPolygon) Polygons() []Polygon {
+	return append([]Polygon(nil), mp.polygons...)
+}
+
+// IsSet reports whether mp holds a value.
+func (mp MultiPolygon) IsSet() bool { return len(mp.polygons) > 0 }
+
+// String returns mp as GeoJSON.
+func (mp MultiPolygon) String() string { return geoString(mp) }
+
+// SchemaType reports the Dgraph type of the predicate to dgman.
+func (MultiPolygon) SchemaType() string { return "geo" }
+
+// MarshalJSON writes mp as a GeoJSON MultiPolygon, or null when mp is unset.
+func (mp MultiPolygon) MarshalJSON() ([]byte, error) {
+	if !mp.IsSet() {
+		return []byte("null"), nil
+	}
+	if err := mp.validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(geoJSON{Type: "MultiPolygon", Coordinates: mp.coordinates()})
+}
+
+// UnmarshalJSON reads a GeoJSON MultiPolygon. null leaves mp unset.
+func (mp *MultiPolygon) UnmarshalJSON(data []byte) error {
+	var c [][][][2]float64
+	ok, err := unmarshalGeo(data, "MultiPolygon", &c)
+	if err != nil || !ok {
+		*mp = MultiPolygon{}
+		return err
+	}
+	mp.polygons = make([]Polygon, len(c))
+	for i, poly := range c {
+		mp.polygons[i] = polygonOf(poly)
+	}
+	return mp.validate()
+}
+
+// FormatParams renders mp as the [[[[lng, lat], ...]]] literal of Dgraph's
+// geo functions.
+func (mp MultiPolygon) FormatParams() []byte {
+	return geoLiteral(mp.coordinates())
+}
+
+func (mp MultiPolygon) coordinates() [][][][2]float64 {
+	c := make([][][][2]float64, len(mp.polygons))
+	for i, poly := range mp.polygons {
+		c[i] = poly.coordinates()
+	}
+	return c
+}
+
+func (mp MultiPolygon) validate() error {
+	if len(mp.polygons) == 0 {
+		return fmt.Errorf("%w: a multipolygon needs a polygon", ErrInvalidGeo)
+	}
+	for _, poly := range mp.polygons {
+		if err := poly.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Geometry is a Point, Polygon or MultiPolygon, as taken by the geo filters
+// of the query builders.
+type Geometry interface {
+	dg.ParamFormatter
+	geometry()
+}
+
+func (Point) geometry()        {}
+func (Polygon) geometry()      {}
+func (MultiPolygon) geometry() {}
+
+var (
+	_ Geometry = Point{}
+	_ Geometry = Polygon{}
+	_ Geometry = MultiPolygon{}
+)
+
+// isGeoType reports whether t is Point, Polygon or MultiPolygon.
+func isGeoType(t reflect.Type) bool {
+	switch t {
+	case reflect.TypeOf(Point{}), reflect.TypeOf(Polygon{}), reflect.TypeOf(MultiPolygon{}):
+		return true
+	}
+	return false
+}
+
+// geoJSON is the wire form of a geometry, as Dgraph reads and writes it.
+type geoJSON struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+// unmarshalGeo decodes the coordinates of GeoJSON data of type typ into c,
+// reporting false for null.
+func unmarshalGeo(data []byte, typ string, c any) (bool, error) {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return false, nil
+	}
+	var g struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}
+	if err := json.Unmarshal(data, &g); err != nil {
+		return false, err
+	}
+	if g.Type != typ {
+		return false, fmt.Errorf("%w: expected GeoJSON %s, got %q", ErrInvalidGeo, typ, g.Type)
+	}
+	if err := json.Unmarshal(g.Coordinates, c); err != nil {
+		return false, fmt.Errorf("%w: %s coordinates: %w", ErrInvalidGeo, typ, err)
+	}
+	return true, nil
+}
+
+// geoLiteral renders coordinates as nested DQL lists.
+func geoLiteral(coordinates any) []byte {
+	data, _ := json.Marshal(coordinates)
+	return bytes.ReplaceAll(data, []byte(","), []byte(", "))
+}
+
+// geoString returns the GeoJSON of g, or "" when it is unset.
+func geoString(g json.Marshaler) string {
+	data, err := g.MarshalJSON()
+	if err != nil || string(data) == "null" {
+		return ""
+	}
+	return string(data)
+}
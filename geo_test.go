@@ -0,0 +1,158 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type GeoStore struct {
+	UID      string           `json:"uid,omitempty"`
+	Name     string           `json:"geo_name,omitempty" dgraph:"index=exact"`
+	Location mg.Point         `json:"geo_location,omitempty" dgraph:"index=geo"`
+	Area     *mg.Polygon      `json:"geo_area,omitempty" dgraph:"index=geo"`
+	Region   *mg.MultiPolygon `json:"geo_region,omitempty"`
+	DType    []string         `json:"dgraph.type,omitempty"`
+}
+
+// square returns the closed ring of the square of side d whose south-west
+// corner is (lng, lat).
+func square(lng, lat, d float64) []mg.Point {
+	return []mg.Point{
+		mg.NewPoint(lng, lat), mg.NewPoint(lng+d, lat), mg.NewPoint(lng+d, lat+d),
+		mg.NewPoint(lng, lat+d), mg.NewPoint(lng, lat),
+	}
+}
+
+func TestGeoFields(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "GeoFieldsWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "GeoFieldsWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			ctx := context.Background()
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			area := mg.MustPolygon(square(-122.42, 37.77, 0.01), square(-122.418, 37.772, 0.002))
+			region, err := mg.NewMultiPolygon(mg.MustPolygon(square(-123, 37, 1)), mg.MustPolygon(square(-121, 37, 1)))
+			require.NoError(t, err)
+			ferry := &GeoStore{Name: "ferry", Location: mg.NewPoint(-122.3937, 37.7955), Area: &area, Region: &region}
+			mission := &GeoStore{Name: "mission", Location: mg.NewPoint(-122.4148, 37.7599)}
+			oakland := &GeoStore{Name: "oakland", Location: mg.NewPoint(-122.2711, 37.8044)}
+			require.NoError(t, client.Insert(ctx, &[]*GeoStore{ferry, mission, oakland}))
+
+			schema, err := client.GetSchema(ctx)
+			require.NoError(t, err)
+			for _, name := range []string{"geo_location", "geo_area", "geo_region"} {
+				pred, ok := schema.Predicate(name)
+				require.True(t, ok, name)
+				require.Equal(t, "geo", pred.Type, name)
+			}
+
+			var got GeoStore
+			require.NoError(t, client.Get(ctx, &got, ferry.UID))
+			require.Equal(t, ferry.Location, got.Location)
+			require.Equal(t, area.Rings(), got.Area.Rings())
+			require.Len(t, got.Region.Polygons(), 2)
+
+			// Geometries bind as filter parameters of the geo functions.
+			near, err := mg.Query[GeoStore](ctx, client).
+				Filter("near(geo_location, ?, 5000)", mg.NewPoint(-122.41, 37.78)).
+				OrderAsc("geo_name").
+				All()
+			require.NoError(t, err)
+			require.Len(t, near, 2)
+			require.Equal(t, "ferry", near[0].Name)
+			require.Equal(t, "mission", near[1].Name)
+			within, err := mg.Query[GeoStore](ctx, client).
+				Filter("within(geo_location, ?)", mg.MustPolygon(square(-122.3, 37.8, 0.1))).
+				All()
+			require.NoError(t, err)
+			require.Len(t, within, 1)
+			require.Equal(t, "oakland", within[0].Name)
+
+			// The builders' geo helpers render the same functions.
+			near, err = mg.Query[GeoStore](ctx, client).Near("geo_location", mg.NewPoint(-122.41, 37.78), 5000).
+				OrderAsc("geo_name").All()
+			require.NoError(t, err)
+			require.Len(t, near, 2)
+			within, err = mg.Query[GeoStore](ctx, client).Within("geo_location", region).OrderAsc("geo_name").All()
+			require.NoError(t, err)
+			require.Len(t, within, 3)
+			containing, err := mg.Query[GeoStore](ctx, client).Contains("geo_area", mg.NewPoint(-122.413, 37.777)).All()
+			require.NoError(t, err)
+			require.Len(t, containing, 1)
+			require.Equal(t, "ferry", containing[0].Name)
+			crossing, err := mg.Query[GeoStore](ctx, client).
+				Intersects("geo_area", mg.MustPolygon(square(-122.415, 37.775, 0.1))).All()
+			require.NoError(t, err)
+			require.Len(t, crossing, 1)
+
+			// An unset Point leaves the stored value alone.
+			require.NoError(t, client.Update(ctx, &GeoStore{UID: mission.UID, Name: "mission district"}))
+			got = GeoStore{}
+			require.NoError(t, client.Get(ctx, &got, mission.UID))
+			require.Equal(t, "mission district", got.Name)
+			require.Equal(t, mission.Location, got.Location)
+		})
+	}
+}
+
+func TestGeoJSON(t *testing.T) {
+	p := mg.NewPoint(-122.5, 37.5)
+	data, err := json.Marshal(p)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type":"Point","coordinates":[-122.5,37.5]}`, string(data))
+	var back mg.Point
+	require.NoError(t, json.Unmarshal(data, &back))
+	require.Equal(t, p, back)
+	require.Equal(t, "[-122.5, 37.5]", string(p.FormatParams()))
+
+	data, err = json.Marshal(mg.Point{})
+	require.NoError(t, err)
+	require.Equal(t, "null", string(data))
+	require.NoError(t, json.Unmarshal([]byte("null"), &back))
+	require.False(t, back.IsSet())
+
+	// Open rings are closed.
+	poly, err := mg.NewPolygon([]mg.Point{mg.NewPoint(0, 0), mg.NewPoint(1, 0), mg.NewPoint(1, 1)})
+	require.NoError(t, err)
+	require.Len(t, poly.Rings()[0], 4)
+	require.Equal(t, "[[[0, 0], [1, 0], [1, 1], [0, 0]]]", string(poly.FormatParams()))
+	require.Equal(t, `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`, poly.String())
+
+	_, err = mg.NewPolygon([]mg.Point{mg.NewPoint(0, 0), mg.NewPoint(1, 0)})
+	require.ErrorIs(t, err, mg.ErrInvalidGeo)
+	_, err = json.Marshal(mg.NewPoint(200, 0))
+	require.ErrorIs(t, err, mg.ErrInvalidGeo)
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"type":"Point","coordinates":[0,0]}`), &poly), mg.ErrInvalidGeo)
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"type":"Polygon","coordinates":[]}`), &back), mg.ErrInvalidGeo)
+}
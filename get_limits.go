@@ -0,0 +1,209 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// WithMaxDepth sets how many levels of edges Get, Query and the other reads
+// that expand an object follow below the root. It is the same setting as
+// WithMaxEdgeTraversal, named to pair with WithMaxEdgeFanout. The default is
+// 10. Override it for a single call with GetMaxDepth.
+func WithMaxDepth(n int) ClientOpt {
+	return func(o *clientOptions) {
+		o.maxEdgeTraversal = n
+	}
+}
+
+// WithMaxEdgeFanout caps how many targets of each edge, forward or managed
+// reverse, an expanding read loads per node, so fetching a node with a huge
+// number of neighbours (an author with 100k books behind ~author) cannot pull
+// them all into memory. Edges past the cap are left out of the result, in the
+// order Dgraph returns them, without an error. The default, 0, loads every
+// target. Override it for a single call with GetMaxEdgeFanout.
+func WithMaxEdgeFanout(n int) ClientOpt {
+	return func(o *clientOptions) {
+		o.maxEdgeFanout = n
+	}
+}
+
+// GetOpt overrides the client's expansion limits for a single Get.
+type GetOpt func(*getOptions)
+
+type getOptions struct {
+	depth  int
+	fanout int
+	edges  []string
+}
+
+// GetMaxDepth overrides WithMaxDepth for one call. 0 loads only the node
+// itself, without following its edges.
+func GetMaxDepth(n int) GetOpt {
+	return func(o *getOptions) {
+		o.depth = n
+	}
+}
+
+// Expand sets how many levels of edges one Get follows below the node. It is
+// GetMaxDepth under a shorter name: Expand(0) is a shallow fetch of the
+// node's own predicates, Expand(2) also loads its neighbours and theirs.
+func Expand(depth int) GetOpt {
+	return GetMaxDepth(depth)
+}
+
+// ExpandEdges limits one Get to following the named edges of the node, each
+// expanded to the call's depth; the node's other edges are left unset. Name
+// a managed reverse edge with its ~ prefix. The node's scalar predicates are
+// always loaded.
+func ExpandEdges(predicates ...string) GetOpt {
+	return func(o *getOptions) {
+		o.edges = append(o.edges, predicates...)
+	}
+}
+
+// GetMaxEdgeFanout overrides WithMaxEdgeFanout for one call. 0 lifts the
+// client's cap.
+func GetMaxEdgeFanout(n int) GetOpt {
+	return func(o *getOptions) {
+		o.fanout = n
+	}
+}
+
+// getOptions returns the client's expansion limits with opts applied.
+func (c client) getOptions(opts []GetOpt) getOptions {
+	o := getOptions{depth: c.options.maxEdgeTraversal, fanout: c.options.maxEdgeFanout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// expand makes q, a query for model, load depth levels of edges with at most
+// fanout targets per edge. Without a fanout cap it is dgman's All(depth),
+// unless that would drop the facets of managed reverse edges.
+func expand(q *dg.Query, model any, depth, fanout int) *dg.Query {
+	if fanout <= 0 && !hasReverseFacets(reflect.TypeOf(model), map[reflect.Type]bool{}) {
+		return q.All(depth)
+	}
+	return q.Query(expandLimited(model, depth, fanout))
+}
+
+// getNode loads the node uid into obj within txn, expanded per limits.
+func getNode(txn *dg.TxnContext, obj any, uid string, limits getOptions) error {
+	if len(limits.edges) == 0 {
+		return expand(txn.Get(obj).UID(uid), obj, limits.depth, limits.fanout).Node()
+	}
+	// expand(_all_) cannot be combined with named edges in one block, so
+	// load the scalars first and decode the chosen edges over them.
+	if err := txn.Get(obj).UID(uid).Query("{\n\t\tuid\n\t\tdgraph.type\n\t\texpand(_all_)\n\t}").Node(); err != nil {
+		return err
+	}
+	if limits.depth <= 0 {
+		return nil
+	}
+	return txn.Get(obj).UID(uid).Query(expandEdges(obj, limits)).Node()
+}
+
+// expandEdges renders a query body loading only the named edges of model,
+// each expanded to the remaining depth.
+func expandEdges(model any, limits getOptions) string {
+	first := ""
+	if limits.fanout > 0 {
+		first = " (first: " + strconv.Itoa(limits.fanout) + ")"
+	}
+	var b strings.Builder
+	b.WriteString("{\n\t\tuid")
+	for _, pred := range limits.edges {
+		target := edgeTarget(model, pred)
+		b.WriteString("\n\t\t" + pred + first + facetsSelector(reflect.TypeOf(target), pred) +
+			" {\n\t\t\tuid\n\t\t\tdgraph.type\n\t\t\texpand(_all_)" + first)
+		writeExpandLevels(&b, limits.depth-1, first)
+		if target != nil {
+			writeReverseEdges(&b, target, limits.depth-1, 0, first)
+		}
+		b.WriteString("\n\t\t}")
+	}
+	b.WriteString("\n\t}")
+	return b.String()
+}
+
+// edgeTarget returns a zero value of the type model's edge pred points to, or
+// nil if model has no field for pred.
+func edgeTarget(model any, pred string) any {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if sf := t.Field(i); fieldPredicate(sf) == pred {
+			return reflect.New(sf.Type).Interface()
+		}
+	}
+	return nil
+}
+
+// expandLimited renders the query body dgman's All(depth) would for model,
+// with (first: fanout) on every expand(_all_) and managed reverse edge when
+// fanout is positive, and the facets of managed reverse edges.
+func expandLimited(model any, depth, fanout int) string {
+	first := ""
+	if fanout > 0 {
+		first = " (first: " + strconv.Itoa(fanout) + ")"
+	}
+	var b strings.Builder
+	b.WriteString("{\n\t\tuid\n\t\tdgraph.type\n\t\texpand(_all_)" + first)
+	writeExpandLevels(&b, depth, first)
+	writeReverseEdges(&b, model, depth, 0, first)
+	b.WriteString("\n\t}")
+	return b.String()
+}
+
+// writeExpandLevels nests depth levels of expand(_all_) blocks.
+func writeExpandLevels(b *strings.Builder, depth int, first string) {
+	for i := 0; i < depth; i++ {
+		tabs := strings.Repeat("\t", i+1)
+		b.WriteString(" {\n\t\t" + tabs + "uid\n\t\t" + tabs + "dgraph.type\n\t\t" + tabs + "expand(_all_)" + first)
+	}
+	for i := depth - 1; i >= 0; i-- {
+		b.WriteString("\n\t\t" + strings.Repeat("\t", i) + "}")
+	}
+}
+
+// writeReverseEdges adds a block for each managed reverse edge of model
+// (json:"~pred" dgraph:"reverse"), which expand(_all_) does not follow.
+func writeReverseEdges(b *strings.Builder, model any, depth, level int, first string) {
+	t := reflect.TypeOf(model)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || level > depth {
+		return
+	}
+	tabs := strings.Repeat("\t", level+2)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		pred := fieldPredicate(sf)
+		if !strings.HasPrefix(pred, "~") || !slices.Contains(strings.Fields(sf.Tag.Get("dgraph")), "reverse") {
+			continue
+		}
+		b.WriteString("\n" + tabs + pred + first + facetsSelector(sf.Type, pred) + " {\n" +
+			tabs + "\tuid\n" + tabs + "\tdgraph.type\n" + tabs + "\texpand(_all_)" + first)
+		if level < depth {
+			writeExpandLevels(b, depth-level-1, first)
+			writeReverseEdges(b, reflect.New(sf.Type).Interface(), depth, level+1, first)
+		}
+		b.WriteString("\n" + tabs + "}")
+	}
+}
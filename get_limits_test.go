@@ -0,0 +1,188 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type FanoutAuthor struct {
+	UID     string        `json:"uid,omitempty"`
+	Name    string        `json:"fanout_name,omitempty" dgraph:"index=exact"`
+	Aliases []string      `json:"fanout_aliases,omitempty"`
+	Books   []*FanoutBook `json:"~fanout_author,omitempty" dgraph:"reverse"`
+	DType   []string      `json:"dgraph.type,omitempty"`
+}
+
+type FanoutBook struct {
+	UID     string        `json:"uid,omitempty"`
+	Title   string        `json:"fanout_title,omitempty"`
+	Author  *FanoutAuthor `json:"fanout_author,omitempty" dgraph:"reverse"`
+	Related []*FanoutBook `json:"fanout_related,omitempty"`
+	DType   []string      `json:"dgraph.type,omitempty"`
+}
+
+func TestGetExpansionLimits(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "ExpansionLimitsWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "ExpansionLimitsWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, err := mg.NewClient(tc.uri, mg.WithAutoSchema(true),
+				mg.WithMaxDepth(3), mg.WithMaxEdgeFanout(2))
+			require.NoError(t, err)
+			defer func() {
+				if strings.HasPrefix(tc.uri, "dgraph://") {
+					_ = client.DropAll(context.Background())
+				}
+				client.Close()
+				mg.Shutdown()
+			}()
+			ctx := context.Background()
+			if strings.HasPrefix(tc.uri, "dgraph://") {
+				require.NoError(t, client.DropAll(ctx))
+			}
+
+			author := &FanoutAuthor{Name: "prolific", Aliases: []string{"a", "b", "c", "d"}}
+			for i := 0; i < 5; i++ {
+				author.Books = append(author.Books, &FanoutBook{Title: fmt.Sprintf("book %d", i)})
+			}
+			require.NoError(t, client.Insert(ctx, author))
+			book := &FanoutBook{Title: "anthology", Related: author.Books}
+			require.NoError(t, client.Insert(ctx, book))
+
+			// The client default caps every edge, forward and reverse.
+			var got FanoutAuthor
+			require.NoError(t, client.Get(ctx, &got, author.UID))
+			require.Len(t, got.Books, 2)
+			require.Len(t, got.Aliases, 4, "scalar lists are not edges")
+			var gotBook FanoutBook
+			require.NoError(t, client.Get(ctx, &gotBook, book.UID))
+			require.Len(t, gotBook.Related, 2)
+
+			// A per-call override lifts or tightens the cap.
+			got = FanoutAuthor{}
+			require.NoError(t, client.Get(ctx, &got, author.UID, mg.GetMaxEdgeFanout(0)))
+			require.Len(t, got.Books, 5)
+			got = FanoutAuthor{}
+			require.NoError(t, client.Get(ctx, &got, author.UID, mg.GetMaxEdgeFanout(1)))
+			require.Len(t, got.Books, 1)
+
+			// Depth bounds how far below the root edges are followed.
+			gotBook = FanoutBook{}
+			require.NoError(t, client.Get(ctx, &gotBook, book.UID, mg.GetMaxDepth(2)))
+			require.NotEmpty(t, gotBook.Related)
+			require.NotNil(t, gotBook.Related[0].Author)
+			require.Equal(t, "prolific", gotBook.Related[0].Author.Name)
+			gotBook = FanoutBook{}
+			require.NoError(t, client.Get(ctx, &gotBook, book.UID, mg.GetMaxDepth(1)))
+			require.NotEmpty(t, gotBook.Related)
+			require.NotEmpty(t, gotBook.Related[0].Title)
+			require.True(t, gotBook.Related[0].Author == nil || gotBook.Related[0].Author.Name == "")
+			gotBook = FanoutBook{}
+			require.NoError(t, client.Get(ctx, &gotBook, book.UID, mg.GetMaxDepth(0)))
+			require.True(t, len(gotBook.Related) == 0 || gotBook.Related[0].Title == "")
+		})
+	}
+}
+
+func TestGetExpandEdges(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "ExpandEdgesWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "ExpandEdgesWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			ctx := context.Background()
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			author := &FanoutAuthor{Name: "prolific", Aliases: []string{"a", "b"}}
+			sequel := &FanoutBook{Title: "sequel", Author: author}
+			book := &FanoutBook{Title: "original", Author: author, Related: []*FanoutBook{sequel}}
+			require.NoError(t, client.Insert(ctx, book))
+
+			// A shallow fetch loads the node's scalars only.
+			var got FanoutBook
+			require.NoError(t, client.Get(ctx, &got, book.UID, mg.Expand(0)))
+			require.Equal(t, "original", got.Title)
+			require.Nil(t, got.Author)
+			require.Empty(t, got.Related)
+
+			// Only the named edges are followed.
+			got = FanoutBook{}
+			require.NoError(t, client.Get(ctx, &got, book.UID, mg.ExpandEdges("fanout_author")))
+			require.Equal(t, "original", got.Title)
+			require.NotNil(t, got.Author)
+			require.Equal(t, "prolific", got.Author.Name)
+			require.Empty(t, got.Related)
+
+			// Named edges are expanded to the call's depth, reverse edges included.
+			var gotAuthor FanoutAuthor
+			require.NoError(t, client.Get(ctx, &gotAuthor, author.UID,
+				mg.ExpandEdges("~fanout_author"), mg.Expand(2), mg.GetMaxEdgeFanout(1)))
+			require.ElementsMatch(t, []string{"a", "b"}, gotAuthor.Aliases)
+			require.Len(t, gotAuthor.Books, 1)
+			require.NotNil(t, gotAuthor.Books[0].Author)
+			require.Equal(t, "prolific", gotAuthor.Books[0].Author.Name)
+
+			gotAuthor = FanoutAuthor{}
+			require.NoError(t, client.Get(ctx, &gotAuthor, author.UID, mg.ExpandEdges("~fanout_author"), mg.Expand(1)))
+			require.Len(t, gotAuthor.Books, 2)
+			for _, b := range gotAuthor.Books {
+				require.NotEmpty(t, b.Title)
+				require.True(t, b.Author == nil || b.Author.Name == "")
+			}
+
+			// With depth 0 the named edges are not followed either.
+			got = FanoutBook{}
+			require.NoError(t, client.Get(ctx, &got, book.UID, mg.ExpandEdges("fanout_related"), mg.Expand(0)))
+			require.Empty(t, got.Related)
+		})
+	}
+}
@@ -0,0 +1,280 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ExportOptions configures Export and ExportGraphML.
+//
+// Types restricts the export to nodes of the listed Dgraph types; empty
+// exports every typed node. MaxNodes caps how many nodes are exported; zero
+// exports them all. Format selects the output of Export; ExportGraphML
+// ignores it.
+type ExportOptions struct {
+	Types    []string
+	MaxNodes int
+	Format   ExportFormat
+}
+
+// graphmlPageSize is how many nodes ExportGraphML reads per query.
+const graphmlPageSize = 1000
+
+// graphmlNode is one exported node: its UID, types, and scalar attributes.
+type graphmlNode struct {
+	uid   string
+	types []string
+	attrs map[string]any
+}
+
+// graphmlEdge is one exported edge, labelled with its predicate.
+type graphmlEdge struct {
+	source, target, label string
+}
+
+// ExportGraphML writes typed nodes (or the nodes of opts.Types) and the edges
+// between them to w as a GraphML document that Gephi and yEd open directly.
+// Each scalar predicate becomes a node attribute, typed long, double,
+// boolean, or string from the values exported; lists of scalars are written
+// as JSON strings. dgraph.type becomes the "type" attribute. Each edge
+// carries its predicate in the "label" attribute. Edges whose target was not
+// exported are left out, so every edge refers to a node in the document.
+//
+// The nodes are read before anything is written, since GraphML declares its
+// attributes ahead of the graph; use MaxNodes to bound large exports.
+func (c client) ExportGraphML(ctx context.Context, w io.Writer, opts ExportOptions) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	filter := ""
+	if len(opts.Types) > 0 {
+		parts := make([]string, len(opts.Types))
+		for i, t := range opts.Types {
+			parts[i] = fmt.Sprintf("type(%s)", t)
+		}
+		filter = " @filter(" + strings.Join(parts, " OR ") + ")"
+	}
+
+	var nodes []graphmlNode
+	var edges []graphmlEdge
+	after := ""
+	for opts.MaxNodes <= 0 || len(nodes) < opts.MaxNodes {
+		first := graphmlPageSize
+		if opts.MaxNodes > 0 {
+			first = min(first, opts.MaxNodes-len(nodes))
+		}
+		page := ""
+		if after != "" {
+			page = ", after: " + after
+		}
+		q := fmt.Sprintf(`{ nodes(func: has(dgraph.type), first: %d%s)%s { uid dgraph.type expand(_all_) { uid } } }`,
+			first, page, filter)
+		resp, err := c.QueryRaw(ctx, q, nil)
+		if err != nil {
+			return err
+		}
+		var result struct {
+			Nodes []map[string]any `json:"nodes"`
+		}
+		dec := json.NewDecoder(bytes.NewReader(resp))
+		dec.UseNumber()
+		if err := dec.Decode(&result); err != nil {
+			return err
+		}
+		for _, raw := range result.Nodes {
+			node, nodeEdges := graphmlSplit(raw)
+			nodes = append(nodes, node)
+			edges = append(edges, nodeEdges...)
+		}
+		if len(result.Nodes) < first {
+			break
+		}
+		after, _ = result.Nodes[len(result.Nodes)-1]["uid"].(string)
+	}
+
+	keys := graphmlKeys(nodes)
+	exported := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		exported[n.uid] = true
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(xml.Header)
+	bw.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns"` +
+		` xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"` +
+		` xsi:schemaLocation="http://graphml.graphdrawing.org/xmlns` +
+		` http://graphml.graphdrawing.org/xmlns/1.0/graphml.xsd">` + "\n")
+	bw.WriteString(`  <key id="type" for="node" attr.name="type" attr.type="string"/>` + "\n")
+	bw.WriteString(`  <key id="label" for="edge" attr.name="label" attr.type="string"/>` + "\n")
+	ids := make(map[string]string, len(keys))
+	for i, k := range keys {
+		ids[k.name] = fmt.Sprintf("d%d", i)
+		fmt.Fprintf(bw, `  <key id="d%d" for="node" attr.name="%s" attr.type="%s"/>`+"\n",
+			i, graphmlEscape(k.name), k.typ)
+	}
+	bw.WriteString(`  <graph id="G" edgedefault="directed">` + "\n")
+	for _, n := range nodes {
+		fmt.Fprintf(bw, `    <node id="%s">`+"\n", graphmlEscape(n.uid))
+		if len(n.types) > 0 {
+			fmt.Fprintf(bw, `      <data key="type">%s</data>`+"\n", graphmlEscape(strings.Join(n.types, ",")))
+		}
+		for _, k := range keys {
+			if v, ok := n.attrs[k.name]; ok {
+				fmt.Fprintf(bw, `      <data key="%s">%s</data>`+"\n", ids[k.name], graphmlEscape(graphmlText(v)))
+			}
+		}
+		bw.WriteString("    </node>\n")
+	}
+	for i, e := range edges {
+		if !exported[e.target] {
+			continue
+		}
+		fmt.Fprintf(bw, `    <edge id="e%d" source="%s" target="%s">`+"\n", i,
+			graphmlEscape(e.source), graphmlEscape(e.target))
+		fmt.Fprintf(bw, `      <data key="label">%s</data>`+"\n", graphmlEscape(e.label))
+		bw.WriteString("    </edge>\n")
+	}
+	bw.WriteString("  </graph>\n</graphml>\n")
+	return bw.Flush()
+}
+
+// graphmlSplit separates a query result node into its attributes and its
+// outgoing edges.
+func graphmlSplit(raw map[string]any) (graphmlNode, []graphmlEdge) {
+	node := graphmlNode{attrs: map[string]any{}}
+	node.uid, _ = raw["uid"].(string)
+	var edges []graphmlEdge
+	for pred, v := range raw {
+		switch pred {
+		case "uid":
+			continue
+		case "dgraph.type":
+			for _, t := range asSlice(v) {
+				if s, ok := t.(string); ok {
+					node.types = append(node.types, s)
+				}
+			}
+			continue
+		}
+		targets := graphmlTargets(v)
+		if targets == nil {
+			node.attrs[pred] = v
+			continue
+		}
+		for _, target := range targets {
+			edges = append(edges, graphmlEdge{source: node.uid, target: target, label: pred})
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].label != edges[j].label {
+			return edges[i].label < edges[j].label
+		}
+		return edges[i].target < edges[j].target
+	})
+	return node, edges
+}
+
+// graphmlTargets returns the UIDs an edge value points to, or nil when v is
+// not an edge.
+func graphmlTargets(v any) []string {
+	var targets []string
+	for _, e := range asSlice(v) {
+		m, ok := e.(map[string]any)
+		if !ok {
+			return nil
+		}
+		if uid, ok := m["uid"].(string); ok {
+			targets = append(targets, uid)
+		}
+	}
+	return targets
+}
+
+func asSlice(v any) []any {
+	if s, ok := v.([]any); ok {
+		return s
+	}
+	return []any{v}
+}
+
+// graphmlKey declares one node attribute.
+type graphmlKey struct {
+	name string
+	typ  string
+}
+
+// graphmlKeys declares an attribute for every scalar predicate of nodes,
+// sorted by name, with the narrowest GraphML type that fits all its values.
+func graphmlKeys(nodes []graphmlNode) []graphmlKey {
+	types := map[string]string{}
+	for _, n := range nodes {
+		for name, v := range n.attrs {
+			types[name] = graphmlWiden(types[name], graphmlType(v))
+		}
+	}
+	keys := make([]graphmlKey, 0, len(types))
+	for name, typ := range types {
+		keys = append(keys, graphmlKey{name: name, typ: typ})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].name < keys[j].name })
+	return keys
+}
+
+func graphmlType(v any) string {
+	switch v := v.(type) {
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "long"
+		}
+		return "double"
+	}
+	return "string"
+}
+
+// graphmlWiden combines the type seen so far for an attribute with the type
+// of another value: long widens to double, and any other mix to string.
+func graphmlWiden(seen, typ string) string {
+	switch {
+	case seen == "" || seen == typ:
+		return typ
+	case (seen == "long" && typ == "double") || (seen == "double" && typ == "long"):
+		return "double"
+	}
+	return "string"
+}
+
+// graphmlText renders an attribute value as GraphML character data.
+func graphmlText(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	}
+	out, _ := json.Marshal(v)
+	return string(out)
+}
+
+func graphmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,145 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"os"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type GraphMLFilm struct {
+	UID      string           `json:"uid,omitempty"`
+	Title    string           `json:"title,omitempty" dgraph:"index=exact"`
+	Year     int              `json:"year,omitempty"`
+	Rating   float64          `json:"rating,omitempty"`
+	Director *GraphMLDirector `json:"director,omitempty"`
+	DType    []string         `json:"dgraph.type,omitempty"`
+}
+
+type GraphMLDirector struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"name,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type graphmlDoc struct {
+	Keys []struct {
+		ID   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Name string `xml:"attr.name,attr"`
+		Type string `xml:"attr.type,attr"`
+	} `xml:"key"`
+	Graph struct {
+		Nodes []struct {
+			ID   string        `xml:"id,attr"`
+			Data []graphmlData `xml:"data"`
+		} `xml:"node"`
+		Edges []struct {
+			Source string        `xml:"source,attr"`
+			Target string        `xml:"target,attr"`
+			Data   []graphmlData `xml:"data"`
+		} `xml:"edge"`
+	} `xml:"graph"`
+}
+
+type graphmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+func TestExportGraphML(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "ExportGraphMLWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "ExportGraphMLWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			ctx := context.Background()
+			film := &GraphMLFilm{
+				Title:    "Metropolis & Co",
+				Year:     1927,
+				Rating:   8.3,
+				Director: &GraphMLDirector{Name: "Fritz Lang"},
+			}
+			require.NoError(t, client.Insert(ctx, film))
+			require.NoError(t, client.Insert(ctx, &GraphMLFilm{Title: "Sunrise", Year: 1927, Rating: 8}))
+
+			var buf bytes.Buffer
+			require.NoError(t, client.ExportGraphML(ctx, &buf,
+				mg.ExportOptions{Types: []string{"GraphMLFilm", "GraphMLDirector"}}))
+			var doc graphmlDoc
+			require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+
+			keyTypes := map[string]string{}
+			keyIDs := map[string]string{}
+			for _, k := range doc.Keys {
+				keyTypes[k.Name] = k.Type
+				keyIDs[k.ID] = k.Name
+			}
+			require.Equal(t, "long", keyTypes["year"])
+			require.Equal(t, "double", keyTypes["rating"])
+			require.Equal(t, "string", keyTypes["title"])
+
+			require.Len(t, doc.Graph.Nodes, 3)
+			attrs := map[string]map[string]string{}
+			for _, n := range doc.Graph.Nodes {
+				attrs[n.ID] = map[string]string{}
+				for _, d := range n.Data {
+					attrs[n.ID][keyIDs[d.Key]] = d.Value
+				}
+			}
+			require.Equal(t, "Metropolis & Co", attrs[film.UID]["title"])
+			require.Equal(t, "GraphMLFilm", attrs[film.UID]["type"])
+			require.Equal(t, "Fritz Lang", attrs[film.Director.UID]["name"])
+
+			require.Len(t, doc.Graph.Edges, 1)
+			edge := doc.Graph.Edges[0]
+			require.Equal(t, film.UID, edge.Source)
+			require.Equal(t, film.Director.UID, edge.Target)
+			require.Equal(t, []graphmlData{{Key: "label", Value: "director"}}, edge.Data)
+
+			// Without the director's type, the edge to it is dropped.
+			buf.Reset()
+			require.NoError(t, client.ExportGraphML(ctx, &buf, mg.ExportOptions{Types: []string{"GraphMLFilm"}}))
+			doc = graphmlDoc{}
+			require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+			require.Len(t, doc.Graph.Nodes, 2)
+			require.Empty(t, doc.Graph.Edges)
+
+			buf.Reset()
+			require.NoError(t, client.ExportGraphML(ctx, &buf, mg.ExportOptions{MaxNodes: 1}))
+			doc = graphmlDoc{}
+			require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+			require.Len(t, doc.Graph.Nodes, 1)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/dgraph-io/dgraph/v25/worker"
+	"google.golang.org/grpc"
+)
+
+var (
+	// ErrReadTsDiscarded is returned by reads at a timestamp older than the
+	// history an embedded store keeps under WithHistoryRetention.
+	ErrReadTsDiscarded = errors.New("modusgraph: read timestamp is older than the retained history")
+	// ErrReadTsAhead is returned by reads at a timestamp the embedded store
+	// has not reached yet.
+	ErrReadTsAhead = errors.New("modusgraph: read timestamp is ahead of the store")
+	// ErrWriteAtReadTs is returned by writes made with a ReadAt context: the
+	// past cannot be changed.
+	ErrWriteAtReadTs = errors.New("modusgraph: cannot write at a past read timestamp")
+)
+
+type readTsKey struct{}
+
+// ReadAt returns a copy of ctx under which client reads see the graph as it
+// was at timestamp ts, a value returned by ReadTs. It applies to Get, Query,
+// QueryRaw and the query builders alike:
+//
+//	ts, err := client.ReadTs(ctx)
+//	// ... later writes ...
+//	before, err := modusgraph.Query[Film](modusgraph.ReadAt(ctx, ts), client).All()
+//
+// Values are read as they were at ts. Dgraph serves uid lists, the edges
+// and the index entries root functions look up, as they are now, so nodes
+// created or unlinked since ts are matched and followed as they are now.
+// Writes made with the returned context fail with ErrWriteAtReadTs.
+func ReadAt(ctx context.Context, ts uint64) context.Context {
+	return context.WithValue(ctx, readTsKey{}, ts)
+}
+
+// readTsFrom returns the timestamp set on ctx by ReadAt, if any.
+func readTsFrom(ctx context.Context) (uint64, bool) {
+	ts, ok := ctx.Value(readTsKey{}).(uint64)
+	return ts, ok && ts > 0
+}
+
+// WithHistoryRetention makes an embedded (file://) store discard the versions
+// that no read within d of now needs, bounding the disk time travel with
+// ReadAt costs. Reads at an older timestamp then fail with
+// ErrReadTsDiscarded. Zero, the default, keeps every version. A dgraph://
+// cluster keeps history back to its last snapshot, whatever this option says.
+func WithHistoryRetention(d time.Duration) ClientOpt {
+	return func(o *clientOptions) {
+		o.historyRetention = d
+	}
+}
+
+// GetAt implements Get at a past timestamp.
+func (c client) GetAt(ctx context.Context, obj any, uid string, ts uint64, opts ...GetOpt) error {
+	return c.Get(ReadAt(ctx, ts), obj, uid, opts...)
+}
+
+// ReadTs implements returning the timestamp of the latest committed state.
+func (c client) ReadTs(ctx context.Context) (uint64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	if c.engine != nil {
+		if err := c.engine.rlock(ctx); err != nil {
+			return 0, err
+		}
+		defer c.engine.mutex.RUnlock()
+		return c.engine.z.readTs(), nil
+	}
+	dc, err := c.pool.get()
+	if err != nil {
+		return 0, err
+	}
+	defer c.pool.put(dc)
+	resp, err := dc.NewReadOnlyTxn().Query(ctx, `{ q(func: uid(0x1)) { uid } }`)
+	if err != nil {
+		return 0, err
+	}
+	return resp.GetTxn().GetStartTs(), nil
+}
+
+// readAtInterceptor stamps remote (dgraph://) queries made with a ReadAt
+// context with its timestamp, and refuses mutations made with one.
+func readAtInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any,
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if r, ok := req.(*api.Request); ok && method == api.Dgraph_Query_FullMethodName {
+			if ts, ok := readTsFrom(ctx); ok {
+				if len(r.Mutations) > 0 {
+					return ErrWriteAtReadTs
+				}
+				r.StartTs = ts
+				r.ReadOnly = true
+				r.BestEffort = false
+			}
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// historyRetention lets the embedded store discard versions older than the
+// retention period. Timestamps are logical, so it samples the read timestamp
+// at intervals and, once a sample is older than the period, sets it as the
+// store's discard timestamp.
+type historyRetention struct {
+	period time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	samples   []tsSample // oldest first
+	discardTs uint64
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+type tsSample struct {
+	at time.Time
+	ts uint64
+}
+
+// newHistoryRetention returns a retention of period; nil keeps everything.
+func newHistoryRetention(period time.Duration) *historyRetention {
+	if period <= 0 {
+		return nil
+	}
+	return &historyRetention{period: period, now: time.Now}
+}
+
+// start samples readTs every tenth of the period until close.
+func (h *historyRetention) start(readTs func() uint64) {
+	if h == nil {
+		return
+	}
+	h.stop = make(chan struct{})
+	h.done = make(chan struct{})
+	go func() {
+		defer close(h.done)
+		ticker := time.NewTicker(h.period / 10)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if ts := h.sample(readTs()); ts > 0 {
+					worker.State.Pstore.SetDiscardTs(ts)
+				}
+			case <-h.stop:
+				return
+			}
+		}
+	}()
+}
+
+// sample records ts as read now and returns the new discard timestamp, or 0
+// when it has not moved.
+func (h *historyRetention) sample(ts uint64) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := h.now()
+	h.samples = append(h.samples, tsSample{at: now, ts: ts})
+	cutoff := now.Add(-h.period)
+	var discard uint64
+	for len(h.samples) > 0 && !h.samples[0].at.After(cutoff) {
+		discard = h.samples[0].ts
+		h.samples = h.samples[1:]
+	}
+	if discard <= h.discardTs {
+		return 0
+	}
+	h.discardTs = discard
+	return discard
+}
+
+// check returns ErrReadTsDiscarded when ts is older than the history kept.
+func (h *historyRetention) check(ts uint64) error {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if ts < h.discardTs {
+		return fmt.Errorf("%w: %d is before %d", ErrReadTsDiscarded, ts, h.discardTs)
+	}
+	return nil
+}
+
+// close stops sampling. It is safe to call more than once.
+func (h *historyRetention) close() {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	stop, done := h.stop, h.done
+	h.stop = nil
+	h.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
@@ -0,0 +1,123 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type HistoryRecord struct {
+	UID    string   `json:"uid,omitempty"`
+	Name   string   `json:"hist_name,omitempty" dgraph:"index=exact"`
+	Status string   `json:"hist_status,omitempty"`
+	DType  []string `json:"dgraph.type,omitempty"`
+}
+
+func TestReadAt(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "ReadAtWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "ReadAtWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			ctx := context.Background()
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			rec := &HistoryRecord{Name: "order-1", Status: "placed"}
+			require.NoError(t, client.Insert(ctx, rec))
+			placed, err := client.ReadTs(ctx)
+			require.NoError(t, err)
+
+			require.NoError(t, client.Update(ctx, &HistoryRecord{UID: rec.UID, Name: "order-1", Status: "shipped"}))
+			require.NoError(t, client.Insert(ctx, &HistoryRecord{Name: "order-2", Status: "placed"}))
+			shipped, err := client.ReadTs(ctx)
+			require.NoError(t, err)
+			require.Greater(t, shipped, placed)
+
+			var got HistoryRecord
+			require.NoError(t, client.GetAt(ctx, &got, rec.UID, placed))
+			require.Equal(t, "placed", got.Status)
+			got = HistoryRecord{}
+			require.NoError(t, client.GetAt(ctx, &got, rec.UID, shipped))
+			require.Equal(t, "shipped", got.Status)
+
+			// Queries see the same past state: order-2 did not exist yet.
+			past, err := mg.Query[HistoryRecord](mg.ReadAt(ctx, placed), client).All()
+			require.NoError(t, err)
+			require.Len(t, past, 1)
+			require.Equal(t, "placed", past[0].Status)
+			now, err := mg.Query[HistoryRecord](ctx, client).All()
+			require.NoError(t, err)
+			require.Len(t, now, 2)
+
+			// A deleted node's values stay readable at earlier timestamps.
+			require.NoError(t, client.Delete(ctx, []string{rec.UID}))
+			got = HistoryRecord{}
+			require.NoError(t, client.GetAt(ctx, &got, rec.UID, shipped))
+			require.Equal(t, "shipped", got.Status)
+
+			err = client.Insert(mg.ReadAt(ctx, placed), &HistoryRecord{Name: "order-2"})
+			require.ErrorIs(t, err, mg.ErrWriteAtReadTs)
+
+			if strings.HasPrefix(tc.uri, "file://") {
+				err = client.GetAt(ctx, &got, rec.UID, shipped+1_000_000)
+				require.ErrorIs(t, err, mg.ErrReadTsAhead)
+			}
+		})
+	}
+}
+
+func TestHistoryRetention(t *testing.T) {
+	ctx := context.Background()
+	client, err := mg.NewClient("file://"+GetTempDir(t), mg.WithAutoSchema(true),
+		mg.WithHistoryRetention(200*time.Millisecond))
+	require.NoError(t, err)
+	defer mg.Shutdown()
+	defer client.Close()
+
+	rec := &HistoryRecord{Name: "order-1", Status: "placed"}
+	require.NoError(t, client.Insert(ctx, rec))
+	placed, err := client.ReadTs(ctx)
+	require.NoError(t, err)
+	var got HistoryRecord
+	require.NoError(t, client.GetAt(ctx, &got, rec.UID, placed))
+
+	require.NoError(t, client.Update(ctx, &HistoryRecord{UID: rec.UID, Status: "shipped"}))
+	require.Eventually(t, func() bool {
+		return client.GetAt(ctx, &got, rec.UID, placed) != nil
+	}, 5*time.Second, 50*time.Millisecond)
+	require.ErrorIs(t, client.GetAt(ctx, &got, rec.UID, placed), mg.ErrReadTsDiscarded)
+
+	// The latest state is always kept.
+	got = HistoryRecord{}
+	require.NoError(t, client.Get(ctx, &got, rec.UID))
+	require.Equal(t, "shipped", got.Status)
+}
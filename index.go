@@ -0,0 +1,177 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+)
+
+// IndexSpec describes an index for AddIndex and RemoveIndex.
+type IndexSpec struct {
+	// Tokenizer names the index, such as "exact", "hash", "term",
+	// "fulltext", "trigram", "int", "day" or "hnsw".
+	Tokenizer string
+	// Options are tokenizer options, such as {"metric": "cosine"} for hnsw.
+	Options map[string]string
+	// Progress, if set, is called as the index is built.
+	Progress func(IndexProgress)
+	// PollInterval is how often a Dgraph cluster is polled while it builds
+	// the index in the background. Defaults to one second.
+	PollInterval time.Duration
+}
+
+// IndexProgress reports on an index build. Dgraph does not expose how far a
+// build has got, so progress is reported in phases: IndexStarted once the
+// nodes to index are counted, IndexBuilding on every poll of a Dgraph
+// cluster, and IndexDone when the index is served.
+type IndexProgress struct {
+	Predicate string
+	Phase     IndexPhase
+	// Nodes is the number of nodes holding the predicate.
+	Nodes   int
+	Elapsed time.Duration
+}
+
+// IndexPhase is a stage of an index build.
+type IndexPhase string
+
+const (
+	IndexStarted  IndexPhase = "started"
+	IndexBuilding IndexPhase = "building"
+	IndexDone     IndexPhase = "done"
+)
+
+// tokenizer renders the spec in DQL form, e.g. `hnsw(metric: "cosine")`.
+func (s IndexSpec) tokenizer() string {
+	if len(s.Options) == 0 {
+		return s.Tokenizer
+	}
+	keys := make([]string, 0, len(s.Options))
+	for k := range s.Options {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	opts := make([]string, len(keys))
+	for i, k := range keys {
+		opts[i] = fmt.Sprintf("%s: %q", k, s.Options[k])
+	}
+	return s.Tokenizer + "(" + strings.Join(opts, ", ") + ")"
+}
+
+// AddIndex implements adding an index to an existing predicate, building it
+// from the data already stored. Adding an index the predicate already has is
+// a no-op.
+func (c client) AddIndex(ctx context.Context, predicate string, spec IndexSpec) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	p, err := c.indexedPredicate(ctx, predicate, spec)
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(p.Indexes, func(tok string) bool { return indexName(tok) == spec.Tokenizer }) {
+		return nil
+	}
+	p.Indexes = append(p.Indexes, spec.tokenizer())
+	return c.alterIndexes(ctx, p, spec)
+}
+
+// RemoveIndex implements removing an index from a predicate. Only
+// spec.Tokenizer is used; removing an index the predicate lacks is a no-op.
+func (c client) RemoveIndex(ctx context.Context, predicate string, spec IndexSpec) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	p, err := c.indexedPredicate(ctx, predicate, spec)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(p.Indexes, func(tok string) bool { return indexName(tok) == spec.Tokenizer })
+	if i < 0 {
+		return nil
+	}
+	p.Indexes = slices.Delete(p.Indexes, i, i+1)
+	return c.alterIndexes(ctx, p, spec)
+}
+
+func (c client) indexedPredicate(ctx context.Context, predicate string, spec IndexSpec) (PredicateInfo, error) {
+	if spec.Tokenizer == "" {
+		return PredicateInfo{}, fmt.Errorf("modusgraph: index tokenizer is required")
+	}
+	schema, err := c.GetSchema(ctx)
+	if err != nil {
+		return PredicateInfo{}, err
+	}
+	p, ok := schema.Predicate(predicate)
+	if !ok {
+		return PredicateInfo{}, fmt.Errorf("modusgraph: predicate %s does not exist", predicate)
+	}
+	return p, nil
+}
+
+// alterIndexes applies p with its new index list and reports progress until
+// the indexes are served.
+func (c client) alterIndexes(ctx context.Context, p PredicateInfo, spec IndexSpec) error {
+	start := time.Now()
+	report := func(phase IndexPhase, nodes int) {
+		if spec.Progress != nil {
+			spec.Progress(IndexProgress{
+				Predicate: p.Name, Phase: phase, Nodes: nodes, Elapsed: time.Since(start),
+			})
+		}
+	}
+	nodes, err := c.countNodes(ctx, fmt.Sprintf("has(<%s>)", p.Name))
+	if err != nil {
+		return err
+	}
+	report(IndexStarted, nodes)
+
+	// A Dgraph cluster builds the index in the background, serving the
+	// predicate without it until done; the embedded engine builds it before
+	// Alter returns.
+	if err := c.alter(ctx, &api.Operation{Schema: p.String(), RunInBackground: true}); err != nil {
+		return err
+	}
+	want := normalizeIndexes(p.Indexes)
+	interval := spec.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	for {
+		schema, err := c.GetSchema(ctx)
+		if err != nil {
+			return err
+		}
+		have, _ := schema.Predicate(p.Name)
+		if slices.Equal(normalizeIndexes(have.Indexes), want) {
+			report(IndexDone, nodes)
+			return nil
+		}
+		report(IndexBuilding, nodes)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// indexName returns the tokenizer name of an index, without its options.
+func indexName(tok string) string {
+	name, _, _ := strings.Cut(tok, "(")
+	return name
+}
@@ -0,0 +1,113 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddRemoveIndex(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "IndexWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "IndexWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+
+			require.NoError(t, client.AlterSchema(ctx, `
+idx_title: string @index(exact) .
+idx_vec: float32vector .
+`))
+			dg, dgCleanup, err := client.DgraphClient()
+			require.NoError(t, err)
+			defer dgCleanup()
+			_, err = dg.NewTxn().Mutate(ctx, &api.Mutation{
+				SetJson: []byte(`[
+					{"idx_title": "The Third Man", "idx_vec": "[1, 0]"},
+					{"idx_title": "The Thin Man", "idx_vec": "[0, 1]"},
+					{"idx_title": "Manhattan", "idx_vec": "[0.9, 0.1]"}
+				]`),
+				CommitNow: true,
+			})
+			require.NoError(t, err)
+
+			// Trigram search needs an index the predicate does not have yet.
+			_, err = client.QueryRaw(ctx, `{ q(func: regexp(idx_title, /Thin/)) { idx_title } }`, nil)
+			require.Error(t, err)
+
+			var phases []mg.IndexPhase
+			err = client.AddIndex(ctx, "idx_title", mg.IndexSpec{
+				Tokenizer: "trigram",
+				Progress: func(p mg.IndexProgress) {
+					require.Equal(t, "idx_title", p.Predicate)
+					require.Equal(t, 3, p.Nodes)
+					phases = append(phases, p.Phase)
+				},
+			})
+			require.NoError(t, err)
+			require.Equal(t, mg.IndexStarted, phases[0])
+			require.Equal(t, mg.IndexDone, phases[len(phases)-1])
+
+			raw, err := client.QueryRaw(ctx, `{ q(func: regexp(idx_title, /Thin/)) { idx_title } }`, nil)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"q":[{"idx_title":"The Thin Man"}]}`, string(raw))
+
+			// An hnsw index on existing vectors makes similar_to work.
+			require.NoError(t, client.AddIndex(ctx, "idx_vec", mg.IndexSpec{
+				Tokenizer: "hnsw",
+				Options:   map[string]string{"metric": "euclidean"},
+			}))
+			raw, err = client.QueryRaw(ctx, `{ q(func: similar_to(idx_vec, 1, "[1, 0.05]")) { idx_title } }`, nil)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"q":[{"idx_title":"The Third Man"}]}`, string(raw))
+
+			schema, err := client.GetSchema(ctx)
+			require.NoError(t, err)
+			title, _ := schema.Predicate("idx_title")
+			require.ElementsMatch(t, []string{"exact", "trigram"}, title.Indexes)
+
+			// Adding an existing index, or removing a missing one, is a no-op.
+			require.NoError(t, client.AddIndex(ctx, "idx_title", mg.IndexSpec{Tokenizer: "exact"}))
+			require.NoError(t, client.RemoveIndex(ctx, "idx_title", mg.IndexSpec{Tokenizer: "term"}))
+
+			require.NoError(t, client.RemoveIndex(ctx, "idx_title", mg.IndexSpec{Tokenizer: "trigram"}))
+			_, err = client.QueryRaw(ctx, `{ q(func: regexp(idx_title, /Thin/)) { idx_title } }`, nil)
+			require.Error(t, err)
+			raw, err = client.QueryRaw(ctx, `{ q(func: eq(idx_title, "Manhattan")) { idx_title } }`, nil)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"q":[{"idx_title":"Manhattan"}]}`, string(raw))
+
+			require.ErrorContains(t, client.AddIndex(ctx, "idx_missing", mg.IndexSpec{Tokenizer: "hash"}),
+				"does not exist")
+		})
+	}
+}
@@ -0,0 +1,448 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// IntegrityOptions configures CheckIntegrity.
+type IntegrityOptions struct {
+	// Repair fixes the issues that can be fixed without guessing: dangling
+	// edges are deleted, untyped nodes whose predicates fit exactly one type
+	// are given that type, and the reverse index of predicates with one-sided
+	// edges is rebuilt. Duplicate unique values are only reported.
+	Repair bool
+}
+
+// IntegrityReport lists the problems CheckIntegrity found.
+type IntegrityReport struct {
+	DanglingEdges     []DanglingEdge
+	UntypedNodes      []UntypedNode
+	DuplicateValues   []DuplicateValue
+	ReverseMismatches []ReverseMismatch
+}
+
+// DanglingEdge is an edge to a node that holds no predicates, typically
+// one deleted without its incoming edges.
+type DanglingEdge struct {
+	UID       string
+	Predicate string
+	Target    string
+	Repaired  bool
+}
+
+// UntypedNode is a node holding predicates but no dgraph.type, which
+// type-based queries and expand(_all_) never see.
+type UntypedNode struct {
+	UID        string
+	Predicates []string
+	// Type is the only type declaring all of Predicates, or "" if none or
+	// several do.
+	Type     string
+	Repaired bool
+}
+
+// DuplicateValue is a value of a @unique predicate held by several nodes.
+type DuplicateValue struct {
+	Predicate string
+	Value     any
+	UIDs      []string
+}
+
+// ReverseMismatch is an edge of a @reverse predicate present in only one
+// direction.
+type ReverseMismatch struct {
+	UID       string
+	Predicate string
+	Target    string
+	// MissingReverse is true when the forward edge UID -> Target has no
+	// reverse entry, and false when the reverse entry has no forward edge.
+	MissingReverse bool
+	Repaired       bool
+}
+
+// Empty reports whether no problems were found.
+func (r *IntegrityReport) Empty() bool {
+	return len(r.DanglingEdges) == 0 && len(r.UntypedNodes) == 0 &&
+		len(r.DuplicateValues) == 0 && len(r.ReverseMismatches) == 0
+}
+
+// String renders the report one problem per line.
+func (r *IntegrityReport) String() string {
+	if r.Empty() {
+		return "no integrity problems found\n"
+	}
+	repaired := func(ok bool) string {
+		if ok {
+			return " (repaired)"
+		}
+		return ""
+	}
+	var sb strings.Builder
+	for _, e := range r.DanglingEdges {
+		fmt.Fprintf(&sb, "dangling edge: %s %s -> %s%s\n", e.UID, e.Predicate, e.Target, repaired(e.Repaired))
+	}
+	for _, n := range r.UntypedNodes {
+		guess := ""
+		if n.Type != "" {
+			guess = ", fits " + n.Type
+		}
+		fmt.Fprintf(&sb, "untyped node: %s with %s%s%s\n",
+			n.UID, strings.Join(n.Predicates, ", "), guess, repaired(n.Repaired))
+	}
+	for _, d := range r.DuplicateValues {
+		fmt.Fprintf(&sb, "duplicate unique value: %s = %v on %s\n", d.Predicate, d.Value, strings.Join(d.UIDs, ", "))
+	}
+	for _, m := range r.ReverseMismatches {
+		side := "reverse entry without forward edge"
+		if m.MissingReverse {
+			side = "forward edge without reverse entry"
+		}
+		fmt.Fprintf(&sb, "%s: %s %s -> %s%s\n", side, m.UID, m.Predicate, m.Target, repaired(m.Repaired))
+	}
+	return sb.String()
+}
+
+// CheckIntegrity implements scanning the database for dangling edges,
+// untyped nodes, duplicate unique values and one-sided reverse edges.
+func (c client) CheckIntegrity(ctx context.Context, opts IntegrityOptions) (*IntegrityReport, error) {
+	if opts.Repair {
+		if err := c.writable(); err != nil {
+			return nil, err
+		}
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	schema, err := c.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	preds := schema.Predicates
+	var edges []PredicateInfo
+	for _, p := range preds {
+		if p.Type == "uid" {
+			edges = append(edges, p)
+		}
+	}
+
+	report := &IntegrityReport{}
+	if err := c.findDanglingEdges(ctx, preds, edges, report); err != nil {
+		return nil, err
+	}
+	if err := c.findUntypedNodes(ctx, schema, preds, report); err != nil {
+		return nil, err
+	}
+	for _, p := range preds {
+		if p.Unique {
+			if err := c.findDuplicateValues(ctx, p, report); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, p := range edges {
+		if p.Reverse {
+			if err := c.findReverseMismatches(ctx, p, report); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if opts.Repair {
+		if err := c.repairIntegrity(ctx, schema, report); err != nil {
+			return report, fmt.Errorf("repairing integrity problems: %w", err)
+		}
+	}
+	return report, nil
+}
+
+// findDanglingEdges reports the edges whose targets hold none of preds.
+func (c client) findDanglingEdges(ctx context.Context, preds, edges []PredicateInfo, report *IntegrityReport) error {
+	empty := []string{"NOT has(dgraph.type)"}
+	for _, p := range preds {
+		empty = append(empty, fmt.Sprintf("NOT has(<%s>)", p.Name))
+	}
+	filter := strings.Join(empty, " AND ")
+	for _, p := range edges {
+		query := fmt.Sprintf("{\n  q(func: has(<%s>)) {\n    uid\n    t: <%s> @filter(%s) {\n      uid\n    }\n  }\n}\n",
+			p.Name, p.Name, filter)
+		nodes, err := c.queryEdges(ctx, query, "t")
+		if err != nil {
+			return fmt.Errorf("checking edges of %s: %w", p.Name, err)
+		}
+		for _, n := range nodes {
+			for _, target := range n.targets {
+				report.DanglingEdges = append(report.DanglingEdges, DanglingEdge{UID: n.uid, Predicate: p.Name, Target: target})
+			}
+		}
+	}
+	return nil
+}
+
+// findUntypedNodes reports the nodes holding any of preds but no
+// dgraph.type, inferring the type from the schema where only one fits.
+func (c client) findUntypedNodes(ctx context.Context, schema *SchemaInfo, preds []PredicateInfo,
+	report *IntegrityReport) error {
+	held := make(map[string][]string)
+	for _, p := range preds {
+		query := fmt.Sprintf("{\n  q(func: has(<%s>)) @filter(NOT has(dgraph.type)) {\n    uid\n  }\n}\n", p.Name)
+		nodes, err := c.queryEdges(ctx, query, "")
+		if err != nil {
+			return fmt.Errorf("checking types of %s holders: %w", p.Name, err)
+		}
+		for _, n := range nodes {
+			held[n.uid] = append(held[n.uid], p.Name)
+		}
+	}
+	for _, uid := range sortedUIDs(held) {
+		node := UntypedNode{UID: uid, Predicates: held[uid]}
+		for _, t := range schema.Types {
+			if isInternalName(t.Name) || !containsAll(t.Fields, node.Predicates) {
+				continue
+			}
+			if node.Type != "" {
+				node.Type = ""
+				break
+			}
+			node.Type = t.Name
+		}
+		report.UntypedNodes = append(report.UntypedNodes, node)
+	}
+	return nil
+}
+
+// findDuplicateValues reports the values of the @unique predicate p held by
+// more than one node.
+func (c client) findDuplicateValues(ctx context.Context, p PredicateInfo, report *IntegrityReport) error {
+	query := fmt.Sprintf("{\n  q(func: has(<%s>)) {\n    uid\n    v: <%s>\n  }\n}\n", p.Name, p.Name)
+	resp, err := c.QueryRaw(ctx, query, nil)
+	if err != nil {
+		return fmt.Errorf("checking values of %s: %w", p.Name, err)
+	}
+	var result struct {
+		Q []struct {
+			UID string          `json:"uid"`
+			V   json.RawMessage `json:"v"`
+		} `json:"q"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return err
+	}
+	holders := make(map[string][]string)
+	values := make(map[string]any)
+	for _, n := range result.Q {
+		var vs []any
+		if p.List {
+			if err := json.Unmarshal(n.V, &vs); err != nil {
+				return err
+			}
+		} else {
+			var v any
+			if err := json.Unmarshal(n.V, &v); err != nil {
+				return err
+			}
+			vs = []any{v}
+		}
+		for _, v := range vs {
+			key := fmt.Sprint(v)
+			values[key] = v
+			holders[key] = append(holders[key], n.UID)
+		}
+	}
+	keys := make([]string, 0, len(holders))
+	for key, uids := range holders {
+		if len(uids) > 1 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		report.DuplicateValues = append(report.DuplicateValues, DuplicateValue{
+			Predicate: p.Name, Value: values[key], UIDs: holders[key],
+		})
+	}
+	return nil
+}
+
+// findReverseMismatches compares the forward edges of the @reverse
+// predicate p with its reverse index.
+func (c client) findReverseMismatches(ctx context.Context, p PredicateInfo, report *IntegrityReport) error {
+	forward, err := c.queryEdges(ctx, fmt.Sprintf(
+		"{\n  q(func: has(<%s>)) {\n    uid\n    t: <%s> {\n      uid\n    }\n  }\n}\n", p.Name, p.Name), "t")
+	if err != nil {
+		return fmt.Errorf("checking forward edges of %s: %w", p.Name, err)
+	}
+	reverse, err := c.queryEdges(ctx, fmt.Sprintf(
+		"{\n  q(func: has(~%s)) {\n    uid\n    t: ~%s {\n      uid\n    }\n  }\n}\n", p.Name, p.Name), "t")
+	if err != nil {
+		return fmt.Errorf("checking reverse edges of %s: %w", p.Name, err)
+	}
+
+	type pair struct{ from, to string }
+	forwardPairs := make(map[pair]bool)
+	for _, n := range forward {
+		for _, t := range n.targets {
+			forwardPairs[pair{n.uid, t}] = true
+		}
+	}
+	reversePairs := make(map[pair]bool)
+	for _, n := range reverse {
+		for _, t := range n.targets {
+			reversePairs[pair{t, n.uid}] = true
+		}
+	}
+	for _, n := range forward {
+		for _, t := range n.targets {
+			if !reversePairs[pair{n.uid, t}] {
+				report.ReverseMismatches = append(report.ReverseMismatches, ReverseMismatch{
+					UID: n.uid, Predicate: p.Name, Target: t, MissingReverse: true,
+				})
+			}
+		}
+	}
+	for _, n := range reverse {
+		for _, t := range n.targets {
+			if !forwardPairs[pair{t, n.uid}] {
+				report.ReverseMismatches = append(report.ReverseMismatches, ReverseMismatch{
+					UID: t, Predicate: p.Name, Target: n.uid,
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// repairIntegrity fixes the repairable problems of report and marks them.
+func (c client) repairIntegrity(ctx context.Context, schema *SchemaInfo, report *IntegrityReport) error {
+	dgClient, err := c.pool.get()
+	if err != nil {
+		return err
+	}
+	defer c.pool.put(dgClient)
+
+	tx := dg.NewTxnContext(ctx, dgClient)
+	defer func() { _ = tx.Txn().Discard(ctx) }()
+	for _, e := range report.DanglingEdges {
+		if err := tx.DeleteEdge(e.UID, e.Predicate, e.Target); err != nil {
+			return err
+		}
+	}
+	var typed []map[string]any
+	for _, n := range report.UntypedNodes {
+		if n.Type != "" {
+			typed = append(typed, map[string]any{"uid": n.UID, "dgraph.type": n.Type})
+		}
+	}
+	if len(typed) > 0 {
+		setJSON, err := json.Marshal(typed)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Txn().Mutate(ctx, &api.Mutation{SetJson: setJSON}); err != nil {
+			return err
+		}
+	}
+	if err := tx.Txn().Commit(ctx); err != nil {
+		return err
+	}
+	for i := range report.DanglingEdges {
+		report.DanglingEdges[i].Repaired = true
+	}
+	for i := range report.UntypedNodes {
+		report.UntypedNodes[i].Repaired = report.UntypedNodes[i].Type != ""
+	}
+
+	// Dropping and restoring @reverse makes Dgraph rebuild the reverse index
+	// from the forward edges.
+	var rebuilt []string
+	for i, m := range report.ReverseMismatches {
+		if !slices.Contains(rebuilt, m.Predicate) {
+			p, _ := schema.Predicate(m.Predicate)
+			withoutReverse := p
+			withoutReverse.Reverse = false
+			if err := c.AlterSchema(ctx, withoutReverse.String()); err != nil {
+				return err
+			}
+			if err := c.AlterSchema(ctx, p.String()); err != nil {
+				return err
+			}
+			rebuilt = append(rebuilt, m.Predicate)
+		}
+		report.ReverseMismatches[i].Repaired = true
+	}
+	return nil
+}
+
+// edgeNode is a node of an integrity query with the targets of its edge.
+type edgeNode struct {
+	uid     string
+	targets []string
+}
+
+// queryEdges runs query and returns the nodes of its q block, with the uids
+// under edge, if given. Nodes whose edge is empty are skipped.
+func (c client) queryEdges(ctx context.Context, query, edge string) ([]edgeNode, error) {
+	resp, err := c.QueryRaw(ctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Q []map[string]json.RawMessage `json:"q"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, err
+	}
+	var nodes []edgeNode
+	for _, raw := range result.Q {
+		var n edgeNode
+		if err := json.Unmarshal(raw["uid"], &n.uid); err != nil {
+			return nil, err
+		}
+		if edge != "" {
+			if n.targets, err = edgeUIDs(raw[edge]); err != nil {
+				return nil, err
+			}
+			if len(n.targets) == 0 {
+				continue
+			}
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
+
+// containsAll reports whether all of want are in have.
+func containsAll(have, want []string) bool {
+	for _, w := range want {
+		if !slices.Contains(have, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedUIDs returns the keys of m in uid order.
+func sortedUIDs(m map[string][]string) []string {
+	uids := make([]string, 0, len(m))
+	for uid := range m {
+		uids = append(uids, uid)
+	}
+	sort.Slice(uids, func(i, j int) bool {
+		if len(uids[i]) != len(uids[j]) {
+			return len(uids[i]) < len(uids[j])
+		}
+		return uids[i] < uids[j]
+	})
+	return uids
+}
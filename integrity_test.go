@@ -0,0 +1,133 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type IntegAuthor struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"integ_author_name,omitempty" dgraph:"index=exact"`
+	Email string   `json:"integ_author_email,omitempty" dgraph:"index=exact unique"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type IntegBook struct {
+	UID     string         `json:"uid,omitempty"`
+	Title   string         `json:"integ_book_title,omitempty" dgraph:"index=exact"`
+	Authors []*IntegAuthor `json:"integ_book_authors,omitempty" dgraph:"reverse"`
+	DType   []string       `json:"dgraph.type,omitempty"`
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "CheckIntegrityWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "CheckIntegrityWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+			require.NoError(t, client.UpdateSchema(ctx, &IntegBook{}))
+
+			ada := &IntegAuthor{Name: "Ada", Email: "ada@example.com"}
+			charles := &IntegAuthor{Name: "Charles", Email: "charles@example.com"}
+			book := &IntegBook{Title: "Notes", Authors: []*IntegAuthor{ada, charles}}
+			require.NoError(t, client.Insert(ctx, book))
+
+			report, err := client.CheckIntegrity(ctx, mg.IntegrityOptions{})
+			require.NoError(t, err)
+			require.True(t, report.Empty(), report.String())
+
+			// Delete a node behind the client's back, leaving the book's edge
+			// to it dangling, and write a node without a type.
+			dgc, done, err := client.DgraphClient()
+			require.NoError(t, err)
+			defer done()
+			_, err = dgc.NewTxn().Mutate(ctx, &api.Mutation{
+				DeleteJson: []byte(`{"uid": "` + charles.UID + `"}`),
+				CommitNow:  true,
+			})
+			require.NoError(t, err)
+			resp, err := dgc.NewTxn().Mutate(ctx, &api.Mutation{
+				SetJson:   []byte(`{"uid": "_:stray", "integ_author_name": "Stray", "integ_author_email": "stray@example.com"}`),
+				CommitNow: true,
+			})
+			require.NoError(t, err)
+			stray := resp.Uids["stray"]
+
+			// Values written before a predicate is made unique may repeat,
+			// where the server accepts the change.
+			require.NoError(t, client.AlterSchema(ctx, "integ_isbn: string @index(exact) ."))
+			_, err = dgc.NewTxn().Mutate(ctx, &api.Mutation{
+				SetJson: []byte(`[{"uid": "` + book.UID + `", "integ_isbn": "978-0"}, ` +
+					`{"uid": "_:copy", "dgraph.type": "IntegBook", "integ_isbn": "978-0"}]`),
+				CommitNow: true,
+			})
+			require.NoError(t, err)
+			err = client.AlterSchema(ctx, "integ_isbn: string @index(exact) @unique @upsert .")
+			duplicates := err == nil
+
+			report, err = client.CheckIntegrity(ctx, mg.IntegrityOptions{})
+			require.NoError(t, err)
+			require.Equal(t, []mg.DanglingEdge{{UID: book.UID, Predicate: "integ_book_authors", Target: charles.UID}},
+				report.DanglingEdges)
+			require.Equal(t, []mg.UntypedNode{{
+				UID: stray, Predicates: []string{"integ_author_email", "integ_author_name"}, Type: "IntegAuthor",
+			}}, report.UntypedNodes)
+			if duplicates {
+				require.Len(t, report.DuplicateValues, 1)
+				require.Equal(t, "integ_isbn", report.DuplicateValues[0].Predicate)
+				require.Equal(t, "978-0", report.DuplicateValues[0].Value)
+				require.Len(t, report.DuplicateValues[0].UIDs, 2)
+			}
+			require.Empty(t, report.ReverseMismatches, report.String())
+			require.Contains(t, report.String(), "dangling edge: "+book.UID+" integ_book_authors -> "+charles.UID)
+
+			// Repair removes the dangling edge and types the stray node;
+			// duplicates are left for the caller to resolve.
+			report, err = client.CheckIntegrity(ctx, mg.IntegrityOptions{Repair: true})
+			require.NoError(t, err)
+			require.True(t, report.DanglingEdges[0].Repaired)
+			require.True(t, report.UntypedNodes[0].Repaired)
+
+			report, err = client.CheckIntegrity(ctx, mg.IntegrityOptions{})
+			require.NoError(t, err)
+			require.Empty(t, report.DanglingEdges)
+			require.Empty(t, report.UntypedNodes)
+			require.Equal(t, duplicates, len(report.DuplicateValues) == 1)
+
+			var authors []IntegAuthor
+			require.NoError(t, client.Query(ctx, IntegAuthor{}).Filter(`eq(integ_author_name, "Stray")`).Nodes(&authors))
+			require.Len(t, authors, 1)
+		})
+	}
+}
@@ -0,0 +1,407 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+)
+
+// JSONLDOptions configures ExportJSONLD and ImportJSONLD.
+//
+// Context maps Dgraph predicate and type names to IRIs. On export it becomes
+// the document's @context, so each predicate is emitted under its own name and
+// expands to the mapped IRI; on import it is inverted, so a document term
+// that expands to a mapped IRI lands on the corresponding predicate regardless
+// of what the document calls it.
+//
+// Vocab is the @vocab IRI for names Context does not map. On import, IRIs
+// under Vocab map to the remainder of the IRI.
+//
+// Base prefixes node identifiers: a node with UID 0x2a is exported with
+// "@id": Base+"0x2a". It defaults to "_:", which makes exported nodes blank
+// nodes.
+//
+// Types restricts ExportJSONLD to nodes of the listed Dgraph types; empty
+// exports every typed node. ImportJSONLD ignores it.
+type JSONLDOptions struct {
+	Context map[string]string
+	Vocab   string
+	Base    string
+	Types   []string
+}
+
+// jsonldPageSize is how many nodes ExportJSONLD reads per query.
+const jsonldPageSize = 1000
+
+func (o JSONLDOptions) base() string {
+	if o.Base == "" {
+		return "_:"
+	}
+	return o.Base
+}
+
+// ExportJSONLD writes every typed node (or every node of opts.Types) to w as
+// a compacted JSON-LD document: a @context built from opts and a @graph with
+// one object per node. Scalar predicates are emitted as plain JSON values,
+// edges as {"@id": ...} references, and dgraph.type as @type.
+func (c client) ExportJSONLD(ctx context.Context, w io.Writer, opts JSONLDOptions) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	bw := bufio.NewWriter(w)
+	header, err := json.Marshal(jsonldContext(opts))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(bw, `{"@context":%s,"@graph":[`, header)
+
+	filter := ""
+	if len(opts.Types) > 0 {
+		parts := make([]string, len(opts.Types))
+		for i, t := range opts.Types {
+			parts[i] = fmt.Sprintf("type(%s)", t)
+		}
+		filter = " @filter(" + strings.Join(parts, " OR ") + ")"
+	}
+
+	base := opts.base()
+	after := ""
+	first := true
+	for {
+		page := ""
+		if after != "" {
+			page = ", after: " + after
+		}
+		q := fmt.Sprintf(`{ nodes(func: has(dgraph.type), first: %d%s)%s { uid dgraph.type expand(_all_) { uid } } }`,
+			jsonldPageSize, page, filter)
+		resp, err := c.QueryRaw(ctx, q, nil)
+		if err != nil {
+			return err
+		}
+		var result struct {
+			Nodes []map[string]any `json:"nodes"`
+		}
+		if err := json.Unmarshal(resp, &result); err != nil {
+			return err
+		}
+		for _, node := range result.Nodes {
+			if !first {
+				bw.WriteByte(',')
+			}
+			first = false
+			out, err := json.Marshal(jsonldNode(node, base))
+			if err != nil {
+				return err
+			}
+			bw.Write(out)
+		}
+		if len(result.Nodes) < jsonldPageSize {
+			break
+		}
+		after, _ = result.Nodes[len(result.Nodes)-1]["uid"].(string)
+	}
+	bw.WriteString("]}\n")
+	return bw.Flush()
+}
+
+// jsonldContext builds the exported @context from opts.
+func jsonldContext(opts JSONLDOptions) map[string]any {
+	ctx := make(map[string]any, len(opts.Context)+1)
+	for term, iri := range opts.Context {
+		ctx[term] = iri
+	}
+	if opts.Vocab != "" {
+		ctx["@vocab"] = opts.Vocab
+	}
+	return ctx
+}
+
+// jsonldNode converts one query result node to a JSON-LD node object.
+func jsonldNode(node map[string]any, base string) map[string]any {
+	out := make(map[string]any, len(node))
+	for k, v := range node {
+		switch k {
+		case "uid":
+			out["@id"] = base + fmt.Sprint(v)
+		case "dgraph.type":
+			out["@type"] = v
+		default:
+			out[k] = jsonldValue(v, base)
+		}
+	}
+	return out
+}
+
+// jsonldValue rewrites {"uid": ...} edge targets as {"@id": ...} references.
+func jsonldValue(v any, base string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if uid, ok := v["uid"]; ok {
+			return map[string]any{"@id": base + fmt.Sprint(uid)}
+		}
+		return v
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = jsonldValue(e, base)
+		}
+		return out
+	}
+	return v
+}
+
+// ImportJSONLD reads a compacted JSON-LD document from r and writes its nodes
+// in a single transaction. The document may be a node object, an array of
+// node objects, or an object with a @graph. Node @id values become blank
+// nodes, so importing always creates new nodes; the returned map gives the
+// UID assigned to each document @id. Nested node objects are imported as
+// nodes of their own and linked by edge.
+//
+// Each term is expanded to an IRI through the document's own @context and
+// then mapped to a predicate through opts.Context (inverted) or opts.Vocab;
+// a term whose IRI neither maps is stored under the term itself.
+func (c client) ImportJSONLD(ctx context.Context, r io.Reader, opts JSONLDOptions) (map[string]string, error) {
+	if err := c.writable(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	var doc any
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("modusgraph: decoding JSON-LD: %w", err)
+	}
+	imp := newJSONLDImporter(opts)
+	var nodes []any
+	switch d := doc.(type) {
+	case []any:
+		nodes = d
+	case map[string]any:
+		if err := imp.addContext(d["@context"]); err != nil {
+			return nil, err
+		}
+		if g, ok := d["@graph"].([]any); ok {
+			nodes = g
+		} else {
+			nodes = []any{d}
+		}
+	default:
+		return nil, errors.New("modusgraph: JSON-LD document must be an object or array")
+	}
+	var set []map[string]any
+	for _, n := range nodes {
+		obj, ok := n.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("modusgraph: JSON-LD @graph entry is %T, not a node object", n)
+		}
+		if _, err := imp.node(obj, &set); err != nil {
+			return nil, err
+		}
+	}
+	if len(set) == 0 {
+		return map[string]string{}, nil
+	}
+	body, err := json.Marshal(set)
+	if err != nil {
+		return nil, err
+	}
+
+	dgo, cleanup, err := c.DgraphClient()
+	defer cleanup()
+	if err != nil {
+		c.log(ctx).Error(err, "Failed to get client from pool")
+		return nil, err
+	}
+	resp, err := dgo.NewTxn().Mutate(ctx, &api.Mutation{SetJson: body, CommitNow: true})
+	if err != nil {
+		return nil, err
+	}
+	uids := make(map[string]string, len(imp.labels))
+	for id, label := range imp.labels {
+		if uid, ok := resp.Uids[label]; ok {
+			uids[id] = uid
+		}
+	}
+	return uids, nil
+}
+
+// jsonldImporter carries term resolution state across one ImportJSONLD call.
+type jsonldImporter struct {
+	opts    JSONLDOptions
+	terms   map[string]string // document term -> IRI
+	vocab   string            // document @vocab
+	inverse map[string]string // IRI -> predicate, from opts.Context
+	labels  map[string]string // document @id -> blank node label
+	next    int
+}
+
+func newJSONLDImporter(opts JSONLDOptions) *jsonldImporter {
+	imp := &jsonldImporter{
+		opts:    opts,
+		terms:   map[string]string{},
+		inverse: make(map[string]string, len(opts.Context)),
+		labels:  map[string]string{},
+	}
+	// Sort so that when two names map to one IRI the choice is stable.
+	names := make([]string, 0, len(opts.Context))
+	for name := range opts.Context {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, dup := imp.inverse[opts.Context[name]]; !dup {
+			imp.inverse[opts.Context[name]] = name
+		}
+	}
+	return imp
+}
+
+// addContext records a document @context: a map, or an array of maps.
+// Remote context references (strings) are not fetched.
+func (imp *jsonldImporter) addContext(ctx any) error {
+	switch c := ctx.(type) {
+	case nil:
+		return nil
+	case []any:
+		for _, e := range c {
+			if err := imp.addContext(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]any:
+		for term, def := range c {
+			var iri string
+			switch d := def.(type) {
+			case string:
+				iri = d
+			case map[string]any:
+				iri, _ = d["@id"].(string)
+			}
+			if term == "@vocab" {
+				imp.vocab = iri
+			} else if iri != "" {
+				imp.terms[term] = iri
+			}
+		}
+		return nil
+	case string:
+		return fmt.Errorf("modusgraph: remote JSON-LD context %q is not supported; inline it", c)
+	}
+	return fmt.Errorf("modusgraph: invalid JSON-LD @context of type %T", ctx)
+}
+
+// predicate maps a document term to a Dgraph predicate (or type) name.
+func (imp *jsonldImporter) predicate(term string) string {
+	iri := term
+	if t, ok := imp.terms[term]; ok {
+		iri = t
+	} else if prefix, local, ok := strings.Cut(term, ":"); ok && imp.terms[prefix] != "" {
+		iri = imp.terms[prefix] + local
+	} else if imp.vocab != "" && !strings.Contains(term, ":") {
+		iri = imp.vocab + term
+	}
+	if name, ok := imp.inverse[iri]; ok {
+		return name
+	}
+	if imp.opts.Vocab != "" && strings.HasPrefix(iri, imp.opts.Vocab) && len(iri) > len(imp.opts.Vocab) {
+		return iri[len(imp.opts.Vocab):]
+	}
+	return term
+}
+
+// label returns the blank node label for a document @id, allocating one for
+// nodes without an @id.
+func (imp *jsonldImporter) label(id string) string {
+	if id != "" {
+		if l, ok := imp.labels[id]; ok {
+			return l
+		}
+	}
+	imp.next++
+	l := fmt.Sprintf("jsonld%d", imp.next)
+	if id != "" {
+		imp.labels[id] = l
+	}
+	return l
+}
+
+// node converts a JSON-LD node object into a Dgraph JSON mutation object,
+// appending it (and any nested node objects) to set, and returns its
+// blank-node reference.
+func (imp *jsonldImporter) node(obj map[string]any, set *[]map[string]any) (map[string]any, error) {
+	id, _ := obj["@id"].(string)
+	ref := map[string]any{"uid": "_:" + imp.label(id)}
+	if len(obj) == 1 && id != "" {
+		return ref, nil // a bare reference
+	}
+	out := map[string]any{"uid": ref["uid"]}
+	for k, v := range obj {
+		switch k {
+		case "@id", "@context":
+			continue
+		case "@type":
+			var types []string
+			switch t := v.(type) {
+			case string:
+				types = []string{imp.predicate(t)}
+			case []any:
+				for _, e := range t {
+					if s, ok := e.(string); ok {
+						types = append(types, imp.predicate(s))
+					}
+				}
+			}
+			out["dgraph.type"] = types
+			continue
+		}
+		if strings.HasPrefix(k, "@") {
+			continue
+		}
+		val, err := imp.value(v, set)
+		if err != nil {
+			return nil, fmt.Errorf("modusgraph: JSON-LD term %q: %w", k, err)
+		}
+		out[imp.predicate(k)] = val
+	}
+	*set = append(*set, out)
+	return ref, nil
+}
+
+// value converts a JSON-LD value: {"@value": x} unwraps, node objects become
+// edges, arrays convert element-wise.
+func (imp *jsonldImporter) value(v any, set *[]map[string]any) (any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		if lit, ok := v["@value"]; ok {
+			return lit, nil
+		}
+		if list, ok := v["@list"]; ok {
+			return imp.value(list, set)
+		}
+		return imp.node(v, set)
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			val, err := imp.value(e, set)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = val
+		}
+		return out, nil
+	}
+	return v, nil
+}
@@ -0,0 +1,122 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type LinkedFilm struct {
+	UID      string          `json:"uid,omitempty"`
+	Title    string          `json:"title,omitempty" dgraph:"index=exact"`
+	Year     int             `json:"year,omitempty"`
+	Director *LinkedDirector `json:"director,omitempty"`
+	DType    []string        `json:"dgraph.type,omitempty"`
+}
+
+type LinkedDirector struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"name,omitempty" dgraph:"index=exact"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+func TestJSONLDExportImport(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "JSONLDWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "JSONLDWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	opts := mg.JSONLDOptions{
+		Context: map[string]string{
+			"title":      "http://schema.org/name",
+			"LinkedFilm": "http://schema.org/Movie",
+		},
+		Vocab: "http://example.com/vocab#",
+		Types: []string{"LinkedFilm", "LinkedDirector"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			ctx := context.Background()
+			film := &LinkedFilm{
+				Title:    "Metropolis",
+				Year:     1927,
+				Director: &LinkedDirector{Name: "Fritz Lang"},
+			}
+			require.NoError(t, client.Insert(ctx, film))
+
+			var buf bytes.Buffer
+			require.NoError(t, client.ExportJSONLD(ctx, &buf, opts))
+
+			var doc struct {
+				Context map[string]any   `json:"@context"`
+				Graph   []map[string]any `json:"@graph"`
+			}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+			require.Equal(t, "http://schema.org/name", doc.Context["title"])
+			require.Equal(t, "http://example.com/vocab#", doc.Context["@vocab"])
+			require.Len(t, doc.Graph, 2)
+			var exportedFilm map[string]any
+			for _, n := range doc.Graph {
+				if n["@id"] == "_:"+film.UID {
+					exportedFilm = n
+				}
+			}
+			require.NotNil(t, exportedFilm)
+			require.Equal(t, "Metropolis", exportedFilm["title"])
+			require.Equal(t, map[string]any{"@id": "_:" + film.Director.UID}, exportedFilm["director"])
+
+			// Import a document that names the same IRIs with its own terms.
+			require.NoError(t, client.DropData(ctx))
+			linked := `{
+				"@context": {"schema": "http://schema.org/", "ex": "http://example.com/vocab#"},
+				"@graph": [
+					{"@id": "urn:film:1", "@type": "schema:Movie", "schema:name": "Nosferatu",
+					 "ex:year": 1922, "ex:director": {"@id": "urn:person:1"}},
+					{"@id": "urn:person:1", "@type": "ex:LinkedDirector", "ex:name": "F. W. Murnau"}
+				]
+			}`
+			uids, err := client.ImportJSONLD(ctx, strings.NewReader(linked), opts)
+			require.NoError(t, err)
+			require.Len(t, uids, 2)
+
+			var got LinkedFilm
+			require.NoError(t, client.Get(ctx, &got, uids["urn:film:1"]))
+			require.Equal(t, "Nosferatu", got.Title)
+			require.Equal(t, 1922, got.Year)
+			require.NotNil(t, got.Director)
+			require.Equal(t, uids["urn:person:1"], got.Director.UID)
+			require.Equal(t, "F. W. Murnau", got.Director.Name)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"github.com/go-logr/logr"
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaWriter is the subset of *kafka.Writer a KafkaSink publishes through.
+// WriteMessages must return only once the messages are acknowledged.
+type KafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// KafkaSinkOptions configures NewKafkaSink.
+//
+// Types restricts publishing to events of the listed Dgraph types; empty
+// publishes every event. Filtered-out events still advance the checkpoint.
+//
+// Checkpoint is the file recording the Seq of the last published event. It
+// defaults to kafka-<topic>.checkpoint in the WithChangelog directory.
+//
+// BatchSize caps how many events are published per WriteMessages call and
+// checkpoint write; it defaults to 100.
+//
+// Writer replaces the default *kafka.Writer, for custom transport, auth, or
+// tests. When set, brokers is ignored.
+type KafkaSinkOptions struct {
+	Types      []string
+	Checkpoint string
+	BatchSize  int
+	Writer     KafkaWriter
+}
+
+// KafkaSink publishes the client's changelog to a Kafka topic. Each
+// ChangeEvent becomes one message keyed by node UID, so events for a node stay
+// ordered within a partition; the value is the event as JSON and the type
+// and op are repeated as headers for routing without decoding.
+//
+// Delivery is at-least-once: the checkpoint advances only after Kafka
+// acknowledges a batch, so a crash between the two republishes that batch on
+// restart. Consumers should deduplicate on the event's seq.
+type KafkaSink struct {
+	cursor changeCursor
+	writer KafkaWriter
+	topic  string
+	types  []string
+	logger logr.Logger
+}
+
+// NewKafkaSink creates a sink that publishes c's changelog to topic on
+// brokers. The client must have been created WithChangelog. Call Run to start
+// publishing and Close when done.
+func NewKafkaSink(c Client, brokers []string, topic string, opts KafkaSinkOptions) (*KafkaSink, error) {
+	mc, ok := c.(client)
+	if !ok || mc.changes == nil {
+		return nil, ErrChangelogDisabled
+	}
+	if topic == "" {
+		return nil, errors.New("modusgraph: Kafka sink requires a topic")
+	}
+	writer := opts.Writer
+	if writer == nil {
+		if len(brokers) == 0 {
+			return nil, errors.New("modusgraph: Kafka sink requires at least one broker")
+		}
+		writer = &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+		}
+	}
+	return &KafkaSink{
+		cursor: newChangeCursor(mc.changes, opts.Checkpoint, "kafka-"+topic+".checkpoint", opts.BatchSize),
+		writer: writer,
+		topic:  topic,
+		types:  opts.Types,
+		logger: mc.logger,
+	}, nil
+}
+
+// Run publishes events from the one after the checkpoint onward, waiting for
+// new commits once caught up, until ctx is done. A failed publish is retried
+// with backoff rather than skipped, so Run returns only ctx's error or a
+// changelog or checkpoint I/O error.
+func (s *KafkaSink) Run(ctx context.Context) error {
+	return s.cursor.run(ctx, func(ctx context.Context, events []ChangeEvent) error {
+		msgs, err := s.messages(events)
+		if err != nil || len(msgs) == 0 {
+			return err
+		}
+		return retryDelivery(ctx, s.logger, func() error {
+			return s.writer.WriteMessages(ctx, msgs...)
+		}, "sink", "kafka", "topic", s.topic, "messages", len(msgs))
+	})
+}
+
+// Close closes the underlying writer.
+func (s *KafkaSink) Close() error {
+	return s.writer.Close()
+}
+
+func (s *KafkaSink) messages(events []ChangeEvent) ([]kafka.Message, error) {
+	msgs := make([]kafka.Message, 0, len(events))
+	for _, ev := range events {
+		if len(s.types) > 0 && !slices.Contains(s.types, ev.Type) {
+			continue
+		}
+		value, err := json.Marshal(ev)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, kafka.Message{
+			Topic: s.topic,
+			Key:   []byte(ev.UID),
+			Value: value,
+			Headers: []kafka.Header{
+				{Key: "type", Value: []byte(ev.Type)},
+				{Key: "op", Value: []byte(ev.Op)},
+			},
+		})
+	}
+	return msgs, nil
+}
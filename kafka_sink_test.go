@@ -0,0 +1,184 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+)
+
+type SinkPerson struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"name,omitempty" dgraph:"index=exact"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type SinkPet struct {
+	UID   string   `json:"uid,omitempty"`
+	Nick  string   `json:"nick,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+// fakeKafkaWriter records published messages, failing the first failures
+// writes.
+type fakeKafkaWriter struct {
+	mu       sync.Mutex
+	msgs     []kafka.Message
+	failures int
+	wrote    chan struct{}
+}
+
+func newFakeKafkaWriter(failures int) *fakeKafkaWriter {
+	return &fakeKafkaWriter{failures: failures, wrote: make(chan struct{}, 100)}
+}
+
+func (w *fakeKafkaWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.failures > 0 {
+		w.failures--
+		return errors.New("broker unavailable")
+	}
+	w.msgs = append(w.msgs, msgs...)
+	w.wrote <- struct{}{}
+	return nil
+}
+
+func (w *fakeKafkaWriter) Close() error { return nil }
+
+func (w *fakeKafkaWriter) events(t *testing.T) []mg.ChangeEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	events := make([]mg.ChangeEvent, len(w.msgs))
+	for i, m := range w.msgs {
+		require.Equal(t, "changes", m.Topic)
+		require.NoError(t, json.Unmarshal(m.Value, &events[i]))
+		require.Equal(t, events[i].UID, string(m.Key))
+	}
+	return events
+}
+
+// runSink runs sink until want events have been published, then stops it.
+func runSink(t *testing.T, sink *mg.KafkaSink, w *fakeKafkaWriter, want int) []mg.ChangeEvent {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- sink.Run(ctx) }()
+	deadline := time.After(10 * time.Second)
+	for len(w.events(t)) < want {
+		select {
+		case <-w.wrote:
+		case <-deadline:
+			t.Fatalf("timed out waiting for %d events, got %d", want, len(w.events(t)))
+		}
+	}
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	return w.events(t)
+}
+
+func TestKafkaSink(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "KafkaSinkWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "KafkaSinkWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			logDir := t.TempDir()
+			client, err := mg.NewClient(tc.uri, mg.WithAutoSchema(true), mg.WithChangelog(logDir))
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = client.DropAll(context.Background())
+				client.Close()
+				mg.Shutdown()
+			})
+
+			ctx := context.Background()
+			alice := &SinkPerson{Name: "Alice"}
+			require.NoError(t, client.Insert(ctx, alice))
+			require.NoError(t, client.Insert(ctx, &SinkPet{Nick: "Rex"}))
+			alice.Name = "Alicia"
+			require.NoError(t, client.Update(ctx, alice))
+
+			// The first publish fails; the sink must retry rather than skip.
+			w := newFakeKafkaWriter(1)
+			sink, err := mg.NewKafkaSink(client, nil, "changes", mg.KafkaSinkOptions{
+				Types:  []string{"SinkPerson"},
+				Writer: w,
+			})
+			require.NoError(t, err)
+			events := runSink(t, sink, w, 2)
+			require.Len(t, events, 2)
+			require.Equal(t, mg.ChangeInsert, events[0].Op)
+			require.Equal(t, "SinkPerson", events[0].Type)
+			require.Equal(t, alice.UID, events[0].UID)
+			require.Contains(t, events[0].Predicates, "name")
+			require.Equal(t, mg.ChangeUpdate, events[1].Op)
+			require.Equal(t, alice.UID, events[1].UID)
+
+			checkpoint, err := os.ReadFile(filepath.Join(logDir, "kafka-changes.checkpoint"))
+			require.NoError(t, err)
+			require.Equal(t, "3\n", string(checkpoint), "the filtered-out pet event still advances the checkpoint")
+
+			// A restarted sink resumes after the checkpoint.
+			require.NoError(t, client.Delete(ctx, []string{alice.UID}))
+			w = newFakeKafkaWriter(0)
+			sink, err = mg.NewKafkaSink(client, nil, "changes", mg.KafkaSinkOptions{Writer: w})
+			require.NoError(t, err)
+			events = runSink(t, sink, w, 1)
+			require.Len(t, events, 1)
+			require.Equal(t, mg.ChangeDelete, events[0].Op)
+			require.Equal(t, alice.UID, events[0].UID)
+			require.Equal(t, uint64(4), events[0].Seq)
+
+			var seqs []uint64
+			iterCtx, cancel := context.WithTimeout(ctx, time.Second)
+			defer cancel()
+			for ev, err := range client.Changelog(iterCtx, 0) {
+				if err != nil {
+					require.ErrorIs(t, err, context.DeadlineExceeded)
+					break
+				}
+				seqs = append(seqs, ev.Seq)
+			}
+			require.Equal(t, []uint64{1, 2, 3, 4}, seqs)
+		})
+	}
+}
+
+func TestKafkaSinkRequiresChangelog(t *testing.T) {
+	client, cleanup := CreateTestClient(t, "file://"+GetTempDir(t))
+	defer cleanup()
+
+	_, err := mg.NewKafkaSink(client, []string{"localhost:9092"}, "changes", mg.KafkaSinkOptions{})
+	require.ErrorIs(t, err, mg.ErrChangelogDisabled)
+}
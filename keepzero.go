@@ -0,0 +1,117 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// IncludeZero makes writes store the zero values of predicates, such as a
+// rating of 0 or a false flag, which omitempty otherwise drops from the
+// mutation. Pass it to With for a single call:
+//
+//	zeroed, err := client.With(modusgraph.IncludeZero("film_rating"))
+//	...
+//	err = zeroed.Update(ctx, film)
+//
+// or to NewClient for every write. Fields tagged dgraph:"keepzero" always
+// store their zero values. Only scalar fields (strings, numbers, booleans and
+// times) are affected; predicates the written type does not have are
+// ignored.
+func IncludeZero(predicates ...string) ClientOpt {
+	return func(o *clientOptions) {
+		o.includeZero = append(o.includeZero, predicates...)
+	}
+}
+
+// keepsZero reports whether a write stores the zero value of f: it is a
+// scalar field tagged dgraph:"keepzero" or named in include.
+func keepsZero(f reflect.StructField, pred string, include []string) bool {
+	t := f.Type
+	switch {
+	case t == reflect.TypeFor[time.Time]():
+	case t.Implements(reflect.TypeFor[json.Marshaler]()):
+		return false
+	default:
+		switch t.Kind() {
+		case reflect.String, reflect.Bool,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+		default:
+			return false
+		}
+	}
+	return slices.Contains(include, pred) || slices.Contains(strings.Fields(f.Tag.Get("dgraph")), "keepzero")
+}
+
+// zeroPredicates returns the predicates of sv whose zero values a write
+// stores and that are zero. With all set, it returns every such predicate
+// whatever its value.
+func zeroPredicates(sv reflect.Value, include []string, all bool) map[string]any {
+	zeros := make(map[string]any)
+	t := sv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fv := sv.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		if sf.Anonymous && fv.Kind() == reflect.Struct && sf.Tag.Get("json") == "" {
+			for p, v := range zeroPredicates(fv, include, all) {
+				zeros[p] = v
+			}
+			continue
+		}
+		pred := fieldPredicate(sf)
+		if pred == "" || pred == "uid" || !keepsZero(sf, pred, include) || !(all || fv.IsZero()) {
+			continue
+		}
+		zeros[pred] = fv.Interface()
+	}
+	return zeros
+}
+
+// hasZeroPredicates reports whether writes of obj may store zero values.
+func hasZeroPredicates(obj any, include []string) bool {
+	t := reflect.TypeOf(obj)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	return t != nil && t.Kind() == reflect.Struct && len(zeroPredicates(reflect.New(t).Elem(), include, true)) > 0
+}
+
+// injectZeros writes the zero values omitempty dropped from the nodes the
+// preceding mutation of tx wrote, before tx is committed.
+func injectZeros(ctx context.Context, tx *dg.TxnContext, obj any, include []string) error {
+	var nodes []map[string]any
+	for _, sv := range structElems(obj) {
+		uid := uidOf(sv.Addr().Interface())
+		zeros := zeroPredicates(sv, include, false)
+		if uid == "" || len(zeros) == 0 {
+			continue
+		}
+		zeros["uid"] = uid
+		nodes = append(nodes, zeros)
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	setJSON, err := json.Marshal(nodes)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Txn().Mutate(ctx, &api.Mutation{SetJson: setJSON})
+	return err
+}
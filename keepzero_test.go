@@ -0,0 +1,98 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type ZeroFilm struct {
+	UID       string   `json:"uid,omitempty"`
+	Title     string   `json:"zf_title,omitempty" dgraph:"index=exact"`
+	Rating    float64  `json:"zf_rating,omitempty" dgraph:"index=float"`
+	Sequels   int      `json:"zf_sequels,omitempty"`
+	Available bool     `json:"zf_available,omitempty" dgraph:"keepzero"`
+	Tags      []string `json:"zf_tags,omitempty"`
+	DType     []string `json:"dgraph.type,omitempty"`
+}
+
+func TestIncludeZero(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "IncludeZeroWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "IncludeZeroWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			ctx := context.Background()
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			has := func(uid, pred string) bool {
+				t.Helper()
+				var nodes []ZeroFilm
+				require.NoError(t, client.Query(ctx, ZeroFilm{}).
+					Filter(`uid(`+uid+`) AND has(`+pred+`)`).Nodes(&nodes))
+				return len(nodes) == 1
+			}
+
+			film := &ZeroFilm{Title: "Metropolis", Rating: 8.3, Sequels: 2, Available: true}
+			require.NoError(t, client.Insert(ctx, film))
+
+			// Without an override, zero values are dropped, except keepzero
+			// fields, which are stored.
+			film.Rating, film.Sequels, film.Available = 0, 0, false
+			require.NoError(t, client.Update(ctx, film))
+			var got ZeroFilm
+			require.NoError(t, client.Get(ctx, &got, film.UID))
+			require.Equal(t, 8.3, got.Rating)
+			require.Equal(t, 2, got.Sequels)
+			require.False(t, got.Available)
+			require.True(t, has(film.UID, "zf_available"))
+
+			// IncludeZero stores the named predicates for one call.
+			rated, err := client.With(mg.IncludeZero("zf_rating"))
+			require.NoError(t, err)
+			require.NoError(t, rated.Update(ctx, film))
+			got = ZeroFilm{}
+			require.NoError(t, client.Get(ctx, &got, film.UID))
+			require.Zero(t, got.Rating)
+			require.True(t, has(film.UID, "zf_rating"))
+			require.Equal(t, 2, got.Sequels)
+
+			// It applies to inserts too, and ignores non-scalar predicates.
+			zeroed, err := client.With(mg.IncludeZero("zf_sequels", "zf_tags", "zf_unknown"))
+			require.NoError(t, err)
+			fresh := &ZeroFilm{Title: "Sunrise"}
+			require.NoError(t, zeroed.Insert(ctx, fresh))
+			require.True(t, has(fresh.UID, "zf_sequels"))
+			require.True(t, has(fresh.UID, "zf_available"))
+			require.False(t, has(fresh.UID, "zf_rating"))
+			require.False(t, has(fresh.UID, "zf_tags"))
+		})
+	}
+}
@@ -0,0 +1,190 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/dgraph-io/dgraph/v25/dql"
+	"google.golang.org/grpc"
+)
+
+// ErrOverloaded is returned when a query or mutation cannot be admitted
+// because the client's concurrency limit is saturated and the wait queue is
+// full or the queue timeout elapsed. Test for it with errors.Is; the concrete
+// error is an *OverloadedError carrying the limit that was hit.
+var ErrOverloaded = errors.New("modusgraph: client overloaded")
+
+// OverloadedError describes a request rejected by a concurrency limit set with
+// WithMaxConcurrentQueries or WithMaxConcurrentMutations.
+type OverloadedError struct {
+	// Kind is "query" or "mutation".
+	Kind string
+	// Limit is the configured number of concurrent operations of this kind.
+	Limit int
+	// Queued is the number of callers that were already waiting for a slot.
+	Queued int
+	// TimedOut reports whether the caller waited the full queue timeout
+	// rather than being rejected because the queue was full.
+	TimedOut bool
+}
+
+func (e *OverloadedError) Error() string {
+	if e.TimedOut {
+		return fmt.Sprintf("modusgraph: client overloaded: timed out waiting for one of %d %s slots",
+			e.Limit, e.Kind)
+	}
+	return fmt.Sprintf("modusgraph: client overloaded: %d %s slots busy and %d callers queued",
+		e.Limit, e.Kind, e.Queued)
+}
+
+// Is makes errors.Is(err, ErrOverloaded) match.
+func (e *OverloadedError) Is(target error) bool {
+	return target == ErrOverloaded
+}
+
+// limiter admits at most limit concurrent operations of one kind. Callers
+// beyond the limit wait in a bounded queue; once maxQueue callers are already
+// waiting, further callers are rejected immediately with an OverloadedError,
+// so a burst degrades into fast failures instead of an unbounded pile-up of
+// in-flight work. A nil *limiter admits everything.
+type limiter struct {
+	kind     string
+	slots    chan struct{}
+	queued   atomic.Int64
+	maxQueue int
+	timeout  time.Duration
+}
+
+// newLimiter returns a limiter for limit concurrent operations, or nil when
+// limit is not positive (no limit). A negative maxQueue defaults the queue
+// depth to limit.
+func newLimiter(kind string, limit, maxQueue int, timeout time.Duration) *limiter {
+	if limit <= 0 {
+		return nil
+	}
+	if maxQueue < 0 {
+		maxQueue = limit
+	}
+	return &limiter{
+		kind:     kind,
+		slots:    make(chan struct{}, limit),
+		maxQueue: maxQueue,
+		timeout:  timeout,
+	}
+}
+
+// acquire blocks until a slot is free, the queue timeout elapses, or ctx is
+// done. On success it returns the function that releases the slot.
+func (l *limiter) acquire(ctx context.Context) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	release = func() { <-l.slots }
+
+	// Fast path: a slot is free, no queueing needed.
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	if queued := l.queued.Add(1); queued > int64(l.maxQueue) {
+		l.queued.Add(-1)
+		return nil, &OverloadedError{Kind: l.kind, Limit: cap(l.slots), Queued: int(queued - 1)}
+	}
+	defer l.queued.Add(-1)
+
+	var expired <-chan time.Time
+	if l.timeout > 0 {
+		timer := time.NewTimer(l.timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-expired:
+		return nil, &OverloadedError{Kind: l.kind, Limit: cap(l.slots), TimedOut: true}
+	}
+}
+
+// admissionControl holds the query and mutation limiters shared by every copy
+// of a client. Either limiter may be nil (unlimited).
+type admissionControl struct {
+	queries   *limiter
+	mutations *limiter
+}
+
+func newAdmissionControl(o clientOptions) *admissionControl {
+	return &admissionControl{
+		queries:   newLimiter("query", o.maxConcurrentQueries, o.maxQueueDepth, o.queueTimeout),
+		mutations: newLimiter("mutation", o.maxConcurrentMutations, o.maxQueueDepth, o.queueTimeout),
+	}
+}
+
+// enabled reports whether any limit is configured.
+func (a *admissionControl) enabled() bool {
+	return a != nil && (a.queries != nil || a.mutations != nil)
+}
+
+// admit acquires a slot for req: requests carrying mutations (including
+// upserts) count against the mutation limit, everything else against the
+// query limit.
+func (a *admissionControl) admit(ctx context.Context, req *api.Request) (func(), error) {
+	if a == nil {
+		return func() {}, nil
+	}
+	if len(req.GetMutations()) > 0 {
+		return a.mutations.acquire(ctx)
+	}
+	return a.queries.acquire(ctx)
+}
+
+// dqlRequest returns the request a RunDQL call amounts to, carrying the
+// mutations of a mutation or upsert block, so that admit classifies it like
+// the equivalent Query call.
+func dqlRequest(in *api.RunDQLRequest) *api.Request {
+	if req, err := dql.ParseMutation(in.GetDqlQuery()); err == nil {
+		return req
+	}
+	return &api.Request{Query: in.GetDqlQuery()}
+}
+
+// unaryInterceptor applies admission control to remote (dgraph://) calls.
+// Only Query and RunDQL are gated; Alter, Login, and commits pass through so
+// schema management and in-flight transactions are never starved.
+func (a *admissionControl) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any,
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		var (
+			release func()
+			err     error
+		)
+		switch method {
+		case api.Dgraph_Query_FullMethodName:
+			r, _ := req.(*api.Request)
+			release, err = a.admit(ctx, r)
+		case api.Dgraph_RunDQL_FullMethodName:
+			r, _ := req.(*api.RunDQLRequest)
+			release, err = a.admit(ctx, dqlRequest(r))
+		default:
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		if err != nil {
+			return err
+		}
+		defer release()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
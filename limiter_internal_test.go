@@ -0,0 +1,124 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterNilAdmitsEverything(t *testing.T) {
+	require.Nil(t, newLimiter("query", 0, -1, 0))
+
+	var l *limiter
+	release, err := l.acquire(context.Background())
+	require.NoError(t, err)
+	release()
+}
+
+func TestLimiterRejectsWhenQueueFull(t *testing.T) {
+	l := newLimiter("mutation", 1, 0, 0)
+	release, err := l.acquire(context.Background())
+	require.NoError(t, err)
+
+	_, err = l.acquire(context.Background())
+	require.ErrorIs(t, err, ErrOverloaded)
+	var oe *OverloadedError
+	require.True(t, errors.As(err, &oe))
+	require.Equal(t, "mutation", oe.Kind)
+	require.Equal(t, 1, oe.Limit)
+	require.False(t, oe.TimedOut)
+
+	release()
+	release, err = l.acquire(context.Background())
+	require.NoError(t, err, "slot should be free again after release")
+	release()
+}
+
+func TestLimiterQueuesUntilSlotFrees(t *testing.T) {
+	l := newLimiter("query", 1, -1, 0)
+	release, err := l.acquire(context.Background())
+	require.NoError(t, err)
+
+	acquired := make(chan error, 1)
+	go func() {
+		r, err := l.acquire(context.Background())
+		if err == nil {
+			r()
+		}
+		acquired <- err
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("queued caller acquired a slot while the limit was saturated")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release()
+	require.NoError(t, <-acquired)
+}
+
+func TestLimiterQueueTimeout(t *testing.T) {
+	l := newLimiter("query", 1, -1, 20*time.Millisecond)
+	release, err := l.acquire(context.Background())
+	require.NoError(t, err)
+	defer release()
+
+	_, err = l.acquire(context.Background())
+	require.ErrorIs(t, err, ErrOverloaded)
+	var oe *OverloadedError
+	require.True(t, errors.As(err, &oe))
+	require.True(t, oe.TimedOut)
+}
+
+func TestLimiterHonorsContext(t *testing.T) {
+	l := newLimiter("query", 1, -1, 0)
+	release, err := l.acquire(context.Background())
+	require.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = l.acquire(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestAdmissionControlRoutesByRequestKind(t *testing.T) {
+	a := newAdmissionControl(clientOptions{maxConcurrentMutations: 1, maxQueueDepth: 0})
+
+	// Queries are unlimited here; mutations share the single slot.
+	releaseQ, err := a.admit(context.Background(), &api.Request{Query: "{ q(func: uid(1)) { uid } }"})
+	require.NoError(t, err)
+	defer releaseQ()
+
+	mu := &api.Request{Mutations: []*api.Mutation{{SetJson: []byte(`{}`)}}}
+	releaseM, err := a.admit(context.Background(), mu)
+	require.NoError(t, err)
+	_, err = a.admit(context.Background(), mu)
+	require.ErrorIs(t, err, ErrOverloaded)
+
+	// RunDQL is classified by its text: a mutation block takes the mutation
+	// slot, a query does not.
+	_, err = a.admit(context.Background(), dqlRequest(&api.RunDQLRequest{
+		DqlQuery: `{ set { _:n <name> "x" . } }`,
+	}))
+	require.ErrorIs(t, err, ErrOverloaded)
+	_, err = a.admit(context.Background(), dqlRequest(&api.RunDQLRequest{
+		DqlQuery: `upsert { query { q(func: eq(name, "x")) { v as uid } } mutation { delete { uid(v) * * . } } }`,
+	}))
+	require.ErrorIs(t, err, ErrOverloaded)
+	releaseD, err := a.admit(context.Background(), dqlRequest(&api.RunDQLRequest{
+		DqlQuery: "{ q(func: uid(1)) { uid } }",
+	}))
+	require.NoError(t, err)
+	releaseD()
+	releaseM()
+}
@@ -0,0 +1,94 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConcurrencyLimits drives a burst of concurrent inserts and reads through
+// a client with small concurrency limits. With a queue deep enough for the
+// whole burst every call must succeed; the limits cap in-flight work, they do
+// not drop admitted requests.
+func TestConcurrencyLimits(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "ConcurrencyLimitsWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "ConcurrencyLimitsWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			const workers = 16
+			client, err := mg.NewClient(tc.uri,
+				mg.WithAutoSchema(true),
+				mg.WithMaxConcurrentQueries(2),
+				mg.WithMaxConcurrentMutations(2),
+				mg.WithMaxQueueDepth(workers*4),
+				mg.WithQueueTimeout(30*time.Second))
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = client.DropAll(context.Background())
+				client.Close()
+				mg.Shutdown()
+			})
+
+			ctx := context.Background()
+			var wg sync.WaitGroup
+			errs := make(chan error, workers)
+			for i := range workers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					entity := &TestEntity{
+						Name:      fmt.Sprintf("limited-%d", i),
+						CreatedAt: time.Now(),
+					}
+					if err := client.Insert(ctx, entity); err != nil {
+						errs <- err
+						return
+					}
+					var got TestEntity
+					errs <- client.Get(ctx, &got, entity.UID)
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				require.NoError(t, err)
+				require.False(t, errors.Is(err, mg.ErrOverloaded))
+			}
+
+			var all []TestEntity
+			require.NoError(t, client.Query(ctx, TestEntity{}).Nodes(&all))
+			require.Len(t, all, workers)
+		})
+	}
+}
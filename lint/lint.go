@@ -0,0 +1,699 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package lint provides a go/analysis Analyzer that checks modusGraph entity
+// structs for tag mistakes that otherwise surface only at runtime, as a
+// rejected schema or a predicate that silently never fills:
+//
+//   - an entity without a string UID field or a []string DType field
+//   - a predicate declared with different types or indexes by two types
+//   - an index that does not apply to the predicate's type, such as
+//     index=term on an int, or reverse, lang or unique on a type that does
+//     not take them
+//   - a reverse edge (json:"~pred") with no forward edge pred, whose
+//     forward edge is not tagged reverse, or whose element type does not
+//     match the entity that holds the forward edge
+//   - a predicate= value that collides with another field's json name
+//   - a field of a type no Dgraph type holds, such as a map or a generic
+//     struct without a SchemaType method
+//
+// An entity is a struct with a json:"uid" or json:"dgraph.type" field, or
+// any dgraph tag. Structs embedded in another struct of the package are
+// mixins: their fields count toward the embedding entity, and they need no
+// UID or DType of their own. Predicates and reverse edges are matched within
+// one package. The modusgraphlint command runs the Analyzer:
+//
+//	go run github.com/matthewmcneely/modusgraph/cmd/modusgraphlint ./...
+//	go vet -vettool=$(which modusgraphlint) ./...
+//
+// With the reversefields flag, the Analyzer also reports each forward edge
+// tagged reverse whose target entity has no field for the reverse edge, with
+// a suggested fix that adds one. Run with -fix to write the fields rather
+// than mirror them by hand:
+//
+//	modusgraphlint -reversefields -fix ./...
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const (
+	modusgraphPath = "github.com/matthewmcneely/modusgraph"
+	dgmanPath      = "github.com/dolan-in/dgman/v2"
+)
+
+// Analyzer checks the entity structs of a package.
+var Analyzer = newAnalyzer()
+
+// reverseFields enables the reverse field check; see the package doc.
+var reverseFields bool
+
+// newAnalyzer returns the Analyzer with its flags registered.
+func newAnalyzer() *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name: "modusgraphlint",
+		Doc: "check modusGraph entity structs for missing UID and DType fields, conflicting predicates, " +
+			"invalid indexes, unpaired or mismatched reverse edges, colliding predicate= tags and " +
+			"unsupported field types",
+		URL: "https://pkg.go.dev/" + modusgraphPath + "/lint",
+		Run: run,
+	}
+	a.Flags.BoolVar(&reverseFields, "reversefields", false,
+		"report forward edges tagged reverse whose target entity has no ~pred field, with a fix that adds one")
+	return a
+}
+
+// tokenizers lists the indexes each Dgraph scalar type accepts.
+var tokenizers = map[string][]string{
+	"string":        {"exact", "hash", "term", "fulltext", "trigram"},
+	"int":           {"int"},
+	"float":         {"float"},
+	"bool":          {"bool"},
+	"datetime":      {"year", "month", "day", "hour"},
+	"geo":           {"geo"},
+	"bigfloat":      {"bigfloat"},
+	"float32vector": {"hnsw"},
+	"uid":           nil,
+}
+
+// dgraphTag is the parsed dgraph struct tag of a field.
+type dgraphTag struct {
+	index     []string // tokenizer names, without hnsw options
+	reverse   bool
+	lang      bool
+	unique    bool
+	predicate string
+	typ       string
+}
+
+var (
+	hnswIndex = regexp.MustCompile(`index=hnsw\([^)]+\)`)
+	tagFields = regexp.MustCompile(`([\w]+=[^\s"']+|[\w]+\s*=\s*"[^"]*"|[\w]+\s*=\s*'[^']*'|[\w]+|[\w]+=[^\s]+)`)
+)
+
+// parseTag parses a dgraph struct tag the way dgman does.
+func parseTag(tag string) dgraphTag {
+	var t dgraphTag
+	if m := hnswIndex.FindString(tag); m != "" {
+		t.index = []string{"hnsw"}
+		tag = strings.Replace(tag, m, "", 1)
+	}
+	for _, field := range tagFields.FindAllString(tag, -1) {
+		key, value, _ := strings.Cut(field, "=")
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch key {
+		case "index":
+			if t.index == nil {
+				for _, name := range strings.Split(value, ",") {
+					if name = strings.TrimSpace(name); name != "" {
+						t.index = append(t.index, name)
+					}
+				}
+			}
+		case "reverse":
+			t.reverse = true
+		case "lang":
+			t.lang = true
+		case "unique", "upsert":
+			t.unique = true
+		case "predicate":
+			t.predicate = value
+		case "type":
+			t.typ = value
+		}
+	}
+	return t
+}
+
+// field is a tagged field of an entity struct.
+type field struct {
+	v     *types.Var
+	owner string // the struct that declares the field
+	name  string // json name
+	pred  string // predicate; the json name unless predicate= overrides it
+	tag   dgraphTag
+	typ   string // Dgraph type, "[T]" for lists; "" when unknown
+}
+
+// entity is a struct of the package that holds predicates.
+type entity struct {
+	obj    *types.TypeName
+	name   string
+	pos    token.Pos
+	end    token.Pos // closing brace of the struct type
+	own    []field   // fields declared by the struct itself
+	fields []field   // own fields and those of embedded structs
+	mixin  bool
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	entities := collect(pass)
+	reported := make(map[string]bool)
+	emit := func(d analysis.Diagnostic) {
+		key := pass.Fset.Position(d.Pos).String() + d.Message
+		if !reported[key] {
+			reported[key] = true
+			pass.Report(d)
+		}
+	}
+	report := func(pos token.Pos, format string, args ...any) {
+		emit(analysis.Diagnostic{Pos: pos, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, e := range entities {
+		if !e.mixin {
+			checkRequired(e, report)
+		}
+		checkCollisions(e, report)
+		for _, f := range e.own {
+			checkIndex(f, report)
+			checkShape(f, report)
+		}
+	}
+	checkConflicts(entities, report)
+	checkReverse(entities, report)
+	if reverseFields {
+		checkReverseFields(entities, emit)
+	}
+	return nil, nil
+}
+
+// collect returns the entity structs of the package in source order.
+func collect(pass *analysis.Pass) []*entity {
+	var structs []*types.TypeName
+	ends := make(map[*types.TypeName]token.Pos)
+	embedded := make(map[*types.TypeName]bool)
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok || spec.TypeParams != nil {
+				return true
+			}
+			s, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				return true
+			}
+			tn, ok := pass.TypesInfo.Defs[spec.Name].(*types.TypeName)
+			if !ok {
+				return true
+			}
+			structs = append(structs, tn)
+			ends[tn] = s.Fields.Closing
+			st := tn.Type().Underlying().(*types.Struct)
+			for i := 0; i < st.NumFields(); i++ {
+				if inner := embeddedStruct(st, i); inner != nil && inner.Pkg() == pass.Pkg {
+					embedded[inner] = true
+				}
+			}
+			return true
+		})
+	}
+
+	var entities []*entity
+	for _, tn := range structs {
+		st := tn.Type().Underlying().(*types.Struct)
+		e := &entity{obj: tn, name: tn.Name(), pos: tn.Pos(), end: ends[tn], mixin: embedded[tn]}
+		e.own = ownFields(tn.Name(), st)
+		e.fields = flatten(tn.Name(), st, map[*types.Struct]bool{})
+		if isEntity(st) {
+			entities = append(entities, e)
+		}
+	}
+	return entities
+}
+
+// embeddedStruct returns the named struct embedded without a json tag as
+// field i of st, whose fields are promoted into st's predicates.
+func embeddedStruct(st *types.Struct, i int) *types.TypeName {
+	v := st.Field(i)
+	if !v.Embedded() || reflect.StructTag(st.Tag(i)).Get("json") != "" {
+		return nil
+	}
+	t := v.Type()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	n, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return nil
+	}
+	if _, ok := n.Underlying().(*types.Struct); !ok {
+		return nil
+	}
+	return n.Obj()
+}
+
+// ownFields returns the fields of st that map to predicates.
+func ownFields(owner string, st *types.Struct) []field {
+	var fields []field
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		if !v.Exported() || embeddedStruct(st, i) != nil {
+			continue
+		}
+		tag := reflect.StructTag(st.Tag(i))
+		name, _, _ := strings.Cut(tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		f := field{v: v, owner: owner, name: name, pred: name, tag: parseTag(tag.Get("dgraph"))}
+		if f.tag.predicate != "" {
+			f.pred = f.tag.predicate
+		}
+		f.typ = dgraphType(v.Type())
+		if f.tag.typ != "" {
+			f.typ = f.tag.typ
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// flatten returns the fields of st and of the structs it embeds.
+func flatten(owner string, st *types.Struct, seen map[*types.Struct]bool) []field {
+	if seen[st] {
+		return nil
+	}
+	seen[st] = true
+	fields := ownFields(owner, st)
+	for i := 0; i < st.NumFields(); i++ {
+		if inner := embeddedStruct(st, i); inner != nil {
+			fields = append(fields, flatten(inner.Name(), inner.Type().Underlying().(*types.Struct), seen)...)
+		}
+	}
+	return fields
+}
+
+// isEntity reports whether st holds predicates: it has a DType field, or a
+// dgraph tag of its own or from an embedded struct.
+func isEntity(st *types.Struct) bool {
+	for i := 0; i < st.NumFields(); i++ {
+		tag := reflect.StructTag(st.Tag(i))
+		if _, ok := tag.Lookup("dgraph"); ok {
+			return true
+		}
+		if name, _, _ := strings.Cut(tag.Get("json"), ","); name == "dgraph.type" {
+			return true
+		}
+		if inner := embeddedStruct(st, i); inner != nil && isEntity(inner.Type().Underlying().(*types.Struct)) {
+			return true
+		}
+	}
+	return false
+}
+
+// dgraphType returns the Dgraph type dgman gives a field of type t, or ""
+// when it cannot tell, as for a type with its own SchemaType method.
+func dgraphType(t types.Type) string {
+	if p, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	if n, ok := types.Unalias(t).(*types.Named); ok {
+		if obj := n.Obj(); obj.Pkg() != nil {
+			switch obj.Pkg().Path() + "." + obj.Name() {
+			case "time.Time":
+				return "datetime"
+			case "math/big.Float", modusgraphPath + ".Decimal":
+				return "bigfloat"
+			case modusgraphPath + ".Point", modusgraphPath + ".Polygon", modusgraphPath + ".MultiPolygon":
+				return "geo"
+			case modusgraphPath + ".SimString", modusgraphPath + ".Localized":
+				return "string"
+			case dgmanPath + ".VectorFloat32":
+				return "float32vector"
+			case modusgraphPath + ".Nullable":
+				if args := n.TypeArgs(); args.Len() == 1 {
+					return dgraphType(args.At(0))
+				}
+			}
+		}
+		if hasSchemaType(n) {
+			return ""
+		}
+		if _, ok := n.Underlying().(*types.Struct); ok && n.TypeArgs().Len() > 0 {
+			// A generic wrapper such as Optional[T] is no edge; see checkShape.
+			return ""
+		}
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch info := u.Info(); {
+		case info&types.IsBoolean != 0:
+			return "bool"
+		case info&types.IsInteger != 0:
+			return "int"
+		case info&types.IsFloat != 0:
+			return "float"
+		case info&types.IsString != 0:
+			return "string"
+		}
+	case *types.Slice:
+		if b, ok := u.Elem().Underlying().(*types.Basic); ok && b.Kind() == types.Byte {
+			return ""
+		}
+		if elem := dgraphType(u.Elem()); elem != "" && !strings.HasPrefix(elem, "[") {
+			return "[" + elem + "]"
+		}
+	case *types.Struct, *types.Interface:
+		return "uid"
+	}
+	return ""
+}
+
+// hasSchemaType reports whether n has a SchemaType method, through which it
+// tells dgman its Dgraph type.
+func hasSchemaType(n *types.Named) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(n), true, n.Obj().Pkg(), "SchemaType")
+	_, ok := obj.(*types.Func)
+	return ok
+}
+
+// unsupported returns why no Dgraph type holds values of type t, or "".
+func unsupported(t types.Type, q types.Qualifier) string {
+	if dgraphType(t) != "" {
+		return ""
+	}
+	if p, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	if n, ok := types.Unalias(t).(*types.Named); ok {
+		if hasSchemaType(n) {
+			return ""
+		}
+		if _, ok := n.Underlying().(*types.Struct); ok && n.TypeArgs().Len() > 0 {
+			return fmt.Sprintf("generic type %s has no Dgraph type; "+
+				"use modusgraph.Nullable, or give the type a SchemaType method", types.TypeString(t, q))
+		}
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Slice, *types.Array:
+		elem := u.(interface{ Elem() types.Type }).Elem()
+		if b, ok := elem.Underlying().(*types.Basic); ok && b.Kind() == types.Byte {
+			return ""
+		}
+		if p, ok := types.Unalias(elem).(*types.Pointer); ok {
+			elem = p.Elem()
+		}
+		switch elem.Underlying().(type) {
+		case *types.Slice, *types.Array:
+			return fmt.Sprintf("%s is a list of lists, which Dgraph does not store", types.TypeString(t, q))
+		}
+		return unsupported(elem, q)
+	case *types.Map, *types.Chan, *types.Signature:
+	case *types.Basic:
+		if u.Info()&types.IsComplex == 0 {
+			return ""
+		}
+	default:
+		return ""
+	}
+	return fmt.Sprintf("%s has no Dgraph type; give the type a SchemaType method", types.TypeString(t, q))
+}
+
+// special reports whether pred is not a schema predicate of its own: the
+// UID, the type list, a reverse edge, a facet or a language variant.
+func special(pred string) bool {
+	return pred == "uid" || pred == "dgraph.type" || strings.HasPrefix(pred, "~") || strings.ContainsAny(pred, "|@")
+}
+
+// scalar returns the element type of a list type.
+func scalar(typ string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(typ, "["), "]")
+}
+
+// checkRequired reports an entity without a string UID or []string DType.
+func checkRequired(e *entity, report func(token.Pos, string, ...any)) {
+	var uid, dtype *field
+	for i := range e.fields {
+		switch e.fields[i].name {
+		case "uid":
+			uid = &e.fields[i]
+		case "dgraph.type":
+			dtype = &e.fields[i]
+		}
+	}
+	switch {
+	case uid == nil:
+		report(e.pos, "%s has no UID field: add UID string `json:\"uid,omitempty\"`", e.name)
+	case !types.Identical(uid.v.Type(), types.Typ[types.String]):
+		report(uid.v.Pos(), "UID field of %s must be a plain string, not %s", e.name, uid.v.Type())
+	}
+	switch {
+	case dtype == nil:
+		report(e.pos, "%s has no DType field: add DType []string `json:\"dgraph.type,omitempty\"`", e.name)
+	case !types.Identical(dtype.v.Type(), types.NewSlice(types.Typ[types.String])):
+		report(dtype.v.Pos(), "DType field of %s must be a []string, not %s", e.name, dtype.v.Type())
+	}
+}
+
+// checkIndex reports indexes and directives that do not apply to the type
+// of f's predicate.
+func checkIndex(f field, report func(token.Pos, string, ...any)) {
+	if special(f.pred) {
+		return
+	}
+	typ := scalar(f.typ)
+	valid, known := tokenizers[typ]
+	if !known {
+		return
+	}
+	for _, name := range f.tag.index {
+		if typ == "uid" {
+			report(f.v.Pos(), "index=%s on %s: uid edges cannot be indexed", name, f.pred)
+		} else if !slices.Contains(valid, name) {
+			report(f.v.Pos(), "index=%s does not apply to %s predicate %s; use %s", name, typ, f.pred, strings.Join(valid, ", "))
+		}
+	}
+	if f.tag.reverse && typ != "uid" {
+		report(f.v.Pos(), "reverse on %s predicate %s: only uid edges have reverse edges", typ, f.pred)
+	}
+	if f.tag.lang && typ != "string" {
+		report(f.v.Pos(), "lang on %s predicate %s: only string predicates have languages", typ, f.pred)
+	}
+	if f.tag.unique && typ != "string" && typ != "int" {
+		report(f.v.Pos(), "unique on %s predicate %s: only string and int predicates can be unique", typ, f.pred)
+	}
+}
+
+// checkShape reports a field of a type no Dgraph type holds. A type= tag
+// overrides the check.
+func checkShape(f field, report func(token.Pos, string, ...any)) {
+	if special(f.pred) || f.tag.typ != "" {
+		return
+	}
+	if reason := unsupported(f.v.Type(), types.RelativeTo(f.v.Pkg())); reason != "" {
+		report(f.v.Pos(), "field %s.%s: %s", f.owner, f.v.Name(), reason)
+	}
+}
+
+// checkCollisions reports predicate= values that collide with the json name
+// or predicate of another field of e.
+func checkCollisions(e *entity, report func(token.Pos, string, ...any)) {
+	for i, f := range e.fields {
+		if f.tag.predicate == "" {
+			continue
+		}
+		for j, g := range e.fields {
+			if i == j {
+				continue
+			}
+			if g.name == f.tag.predicate || g.pred == f.tag.predicate {
+				report(f.v.Pos(), "predicate=%s of %s.%s collides with %s.%s (json %q)",
+					f.tag.predicate, f.owner, f.v.Name(), g.owner, g.v.Name(), g.name)
+				break
+			}
+		}
+	}
+}
+
+// checkConflicts reports predicates that two types declare with different
+// types or indexes, at the later declaration.
+func checkConflicts(entities []*entity, report func(token.Pos, string, ...any)) {
+	first := make(map[string]field)
+	for _, e := range entities {
+		for _, f := range e.own {
+			if special(f.pred) {
+				continue
+			}
+			prev, ok := first[f.pred]
+			if !ok {
+				first[f.pred] = f
+				continue
+			}
+			if prev.typ != "" && f.typ != "" && prev.typ != f.typ {
+				report(f.v.Pos(), "predicate %s is %s here but %s in %s", f.pred, f.typ, prev.typ, prev.owner)
+				continue
+			}
+			if !slices.Equal(sortedIndex(prev), sortedIndex(f)) {
+				report(f.v.Pos(), "predicate %s has index %q here but %q in %s",
+					f.pred, strings.Join(sortedIndex(f), ","), strings.Join(sortedIndex(prev), ","), prev.owner)
+			}
+		}
+	}
+}
+
+func sortedIndex(f field) []string {
+	return slices.Sorted(slices.Values(f.tag.index))
+}
+
+// checkReverse reports reverse edges whose forward edge no type of the
+// package declares, that no side tags reverse, or whose element type or
+// owner does not match the forward edge.
+func checkReverse(entities []*entity, report func(token.Pos, string, ...any)) {
+	byObj := make(map[*types.TypeName]*entity)
+	forward := make(map[string][]field)
+	holders := make(map[string][]string) // entities that hold each forward edge
+	for _, e := range entities {
+		byObj[e.obj] = e
+		for _, f := range e.own {
+			forward[f.pred] = append(forward[f.pred], f)
+		}
+		if e.mixin {
+			continue
+		}
+		for _, f := range e.fields {
+			if !special(f.pred) && !slices.Contains(holders[f.pred], e.name) {
+				holders[f.pred] = append(holders[f.pred], e.name)
+			}
+		}
+	}
+	for _, e := range entities {
+		for _, f := range e.own {
+			pred, ok := strings.CutPrefix(f.pred, "~")
+			if !ok || strings.Contains(pred, "|") { // a facet of the reverse edge
+				continue
+			}
+			edges := forward[pred]
+			if len(edges) == 0 {
+				report(f.v.Pos(), "reverse edge %s has no forward edge %s in this package", f.pred, pred)
+				continue
+			}
+			if !f.tag.reverse && !slices.ContainsFunc(edges, func(g field) bool { return g.tag.reverse }) {
+				report(f.v.Pos(), "reverse edge %s needs dgraph:\"reverse\" on its forward edge %s.%s",
+					f.pred, edges[0].owner, edges[0].v.Name())
+			}
+			t := byObj[edgeTarget(f.v.Type())]
+			if t != nil && len(holders[pred]) > 0 && !slices.Contains(holders[pred], t.name) {
+				report(f.v.Pos(), "reverse edge %s holds %s, but its forward edge %s is declared by %s",
+					f.pred, t.name, pred, strings.Join(holders[pred], ", "))
+			}
+			if e.mixin {
+				// The reverse edge lands on whichever entity embeds e.
+				continue
+			}
+			var targets []string
+			for _, g := range edges {
+				t := byObj[edgeTarget(g.v.Type())]
+				if t == nil || t.mixin {
+					targets = nil
+					break
+				}
+				targets = append(targets, t.name)
+			}
+			if len(targets) > 0 && !slices.Contains(targets, e.name) {
+				report(f.v.Pos(), "reverse edge %s is on %s, but its forward edge %s.%s points to %s",
+					f.pred, e.name, edges[0].owner, edges[0].v.Name(), targets[0])
+			}
+		}
+	}
+}
+
+// checkReverseFields reports forward edges tagged reverse whose target entity
+// has no field for the reverse edge, with a fix that adds one.
+func checkReverseFields(entities []*entity, emit func(analysis.Diagnostic)) {
+	byObj := make(map[*types.TypeName]*entity)
+	for _, e := range entities {
+		byObj[e.obj] = e
+	}
+	added := make(map[string]bool) // target and predicate, and target and field name
+	for _, e := range entities {
+		if e.mixin {
+			continue
+		}
+		for _, f := range e.fields {
+			if !f.tag.reverse || special(f.pred) {
+				continue
+			}
+			t := byObj[edgeTarget(f.v.Type())]
+			if t == nil || t.mixin || !t.end.IsValid() || added[t.name+" "+f.pred] {
+				continue
+			}
+			if slices.ContainsFunc(t.fields, func(g field) bool { return g.pred == "~"+f.pred }) {
+				continue
+			}
+			added[t.name+" "+f.pred] = true
+			name := reverseName(t, e, f.pred, added)
+			added[t.name+"."+name] = true
+			decl := fmt.Sprintf("%s []*%s `json:\"~%s,omitempty\"`", name, e.name, f.pred)
+			emit(analysis.Diagnostic{
+				Pos:     f.v.Pos(),
+				Message: fmt.Sprintf("forward edge %s.%s has no reverse field on %s: add %s", f.owner, f.v.Name(), t.name, decl),
+				SuggestedFixes: []analysis.SuggestedFix{{
+					Message:   fmt.Sprintf("Add %s to %s", name, t.name),
+					TextEdits: []analysis.TextEdit{{Pos: t.end, End: t.end, NewText: []byte("\t" + decl + "\n")}},
+				}},
+			})
+		}
+	}
+}
+
+// edgeTarget returns the named struct an edge of type t points to, or nil.
+func edgeTarget(t types.Type) *types.TypeName {
+	if s, ok := t.(*types.Slice); ok {
+		t = s.Elem()
+	}
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	n, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return nil
+	}
+	if _, ok := n.Underlying().(*types.Struct); !ok {
+		return nil
+	}
+	return n.Obj()
+}
+
+// reverseName returns the name of a field on to for the reverse edge ~pred
+// from from: the plural of from, with the predicate appended when to already
+// has a field or method of that name, or one is added under it.
+func reverseName(to, from *entity, pred string, added map[string]bool) string {
+	name := plural(from.name)
+	obj, _, _ := types.LookupFieldOrMethod(to.obj.Type(), true, to.obj.Pkg(), name)
+	if obj == nil && !added[to.name+"."+name] {
+		return name
+	}
+	name += "By"
+	words := strings.FieldsFunc(pred, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	for _, part := range words {
+		name += strings.ToUpper(part[:1]) + part[1:]
+	}
+	return name
+}
+
+// plural returns the English plural of a type name.
+func plural(name string) string {
+	switch {
+	case len(name) > 1 && strings.HasSuffix(name, "y") && !strings.ContainsRune("aeiou", rune(name[len(name)-2])):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"),
+		strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
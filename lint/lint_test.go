@@ -0,0 +1,25 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/matthewmcneely/modusgraph/lint"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), lint.Analyzer, "models")
+}
+
+func TestReverseFields(t *testing.T) {
+	if err := lint.Analyzer.Flags.Set("reversefields", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer lint.Analyzer.Flags.Set("reversefields", "false")
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), lint.Analyzer, "reversefields")
+}
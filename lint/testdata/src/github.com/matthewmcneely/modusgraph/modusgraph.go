@@ -0,0 +1,10 @@
+// Package modusgraph stubs the types the analyzer recognizes.
+package modusgraph
+
+type Decimal struct{ s string }
+
+type Nullable[T any] struct{ value T }
+
+type Point struct{ lng, lat float64 }
+
+type Polygon struct{ rings [][]Point }
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"time"
+
+	mg "github.com/matthewmcneely/modusgraph"
+)
+
+type Film struct {
+	UID      string     `json:"uid,omitempty"`
+	Title    string     `json:"title,omitempty" dgraph:"index=exact,term"`
+	Released time.Time  `json:"released,omitempty" dgraph:"index=year"`
+	Budget   mg.Decimal `json:"budget,omitempty" dgraph:"index=bigfloat"`
+	Rating   float64    `json:"rating,omitempty" dgraph:"index=float"`
+	Director *Director  `json:"director,omitempty" dgraph:"reverse"`
+	Credited time.Time  `json:"~director|credited,omitempty"`
+	Genres   []*Genre   `json:"genres,omitempty" dgraph:"count"`
+	Location mg.Point   `json:"location,omitempty" dgraph:"index=geo"`
+	Area     mg.Polygon `json:"area,omitempty" dgraph:"index=exact"` // want `index=exact does not apply to geo predicate area; use geo`
+	DType    []string   `json:"dgraph.type,omitempty"`
+}
+
+type Director struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"name,omitempty" dgraph:"index=exact unique"`
+	Films []*Film  `json:"~director,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type Genre struct {
+	UID      string   `json:"uid,omitempty"`
+	Name     string   `json:"name,omitempty" dgraph:"index=exact unique"`
+	Films    []*Film  `json:"~genres,omitempty"`   // want `reverse edge ~genres needs dgraph:"reverse" on its forward edge Film.Genres`
+	Directed []*Film  `json:"~director,omitempty"` // want `reverse edge ~director is on Genre, but its forward edge Film.Director points to Director`
+	Shows    []*Genre `json:"~series,omitempty"`   // want `reverse edge ~series holds Genre, but its forward edge series is declared by Series`
+	DType    []string `json:"dgraph.type,omitempty"`
+}
+
+type Series struct {
+	UID    string   `json:"uid,omitempty"`
+	Genres []*Genre `json:"series,omitempty" dgraph:"reverse"`
+	DType  []string `json:"dgraph.type,omitempty"`
+}
+
+// Audit is a mixin: it needs no UID or DType of its own.
+type Audit struct {
+	CreatedBy string `json:"created_by,omitempty" dgraph:"index=hash"`
+}
+
+type Review struct {
+	Audit
+	UID    string              `json:"uid,omitempty"`
+	Score  mg.Nullable[int]    `json:"score,omitempty" dgraph:"index=term"` // want `index=term does not apply to int predicate score; use int`
+	Tags   []string            `json:"tags,omitempty" dgraph:"index=exact lang"`
+	Author *Director           `json:"author,omitempty" dgraph:"index=exact"`                // want `index=exact on author: uid edges cannot be indexed`
+	Posted time.Time           `json:"posted,omitempty" dgraph:"index=hour reverse"`         // want `reverse on datetime predicate posted: only uid edges have reverse edges`
+	Title  string              `json:"review_title,omitempty" dgraph:"predicate=created_by"` // want `predicate=created_by of Review.Title collides with Audit.CreatedBy \(json "created_by"\)` `predicate created_by has index "" here but "hash" in Audit`
+	Weight float64             `json:"weight,omitempty" dgraph:"lang unique"`                // want `lang on float predicate weight: only string predicates have languages` `unique on float predicate weight: only string and int predicates can be unique`
+	Vector []float32           `json:"vector,omitempty" dgraph:"type=float32vector index=hnsw(metric:\"cosine\")"`
+	Coords string              `json:"coords,omitempty" dgraph:"type=geo index=geo"`
+	Fans   []*Director         `json:"~fan_of,omitempty" dgraph:"reverse"` // want `reverse edge ~fan_of has no forward edge fan_of in this package`
+	Votes  mg.Nullable[string] `json:"votes,omitempty"`
+	DType  []string            `json:"dgraph.type,omitempty"`
+}
+
+type Studio struct { // want `Studio has no UID field: add UID string .json:"uid,omitempty".`
+	Name  string   `json:"name,omitempty" dgraph:"index=term"` // want `predicate name has index "term" here but "exact" in Director`
+	Title int      `json:"title,omitempty"`                    // want `predicate title is int here but string in Film`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type Label struct { // want `Label has no DType field: add DType \[\]string .json:"dgraph.type,omitempty".`
+	ID   string `json:"uid,omitempty"`
+	Name string `json:"name,omitempty" dgraph:"index=exact unique"`
+}
+
+type Badge struct {
+	UID   int    `json:"uid,omitempty"` // want `UID field of Badge must be a plain string, not int`
+	Label string `json:"badge_label,omitempty" dgraph:"index=hash"`
+	DType string `json:"dgraph.type,omitempty"` // want `DType field of Badge must be a \[\]string, not string`
+}
+
+// Payload has no dgraph tags or DType field, so it is not an entity.
+type Payload struct {
+	UID  string `json:"uid"`
+	Name int    `json:"name"`
+}
+
+type (
+	ID     string
+	Status string
+	Tags   []string
+	Count  = int
+)
+
+// Optional is a generic wrapper without a SchemaType method.
+type Optional[T any] struct {
+	Value T
+}
+
+type Ticket struct {
+	UID    string            `json:"uid,omitempty"`
+	Status Status            `json:"ticket_status,omitempty" dgraph:"index=exact"`
+	Tags   Tags              `json:"ticket_tags,omitempty" dgraph:"index=exact"`
+	Count  Count             `json:"ticket_count,omitempty" dgraph:"index=int"`
+	Level  Status            `json:"ticket_level,omitempty" dgraph:"index=int"` // want `index=int does not apply to string predicate ticket_level; use exact, hash, term, fulltext, trigram`
+	Due    Optional[string]  `json:"ticket_due,omitempty"`                      // want `field Ticket.Due: generic type Optional\[string\] has no Dgraph type; use modusgraph.Nullable, or give the type a SchemaType method`
+	Meta   map[string]string `json:"ticket_meta,omitempty"`                     // want `field Ticket.Meta: map\[string\]string has no Dgraph type; give the type a SchemaType method`
+	Grid   [][]int           `json:"ticket_grid,omitempty"`                     // want `field Ticket.Grid: \[\]\[\]int is a list of lists, which Dgraph does not store`
+	Raw    map[string]string `json:"ticket_raw,omitempty" dgraph:"type=string"`
+	DType  []string          `json:"dgraph.type,omitempty"`
+}
+
+type Queue struct {
+	UID   ID       `json:"uid,omitempty"` // want `UID field of Queue must be a plain string, not models.ID`
+	DType []string `json:"dgraph.type,omitempty"`
+}
@@ -0,0 +1,42 @@
+package reversefields
+
+type Book struct {
+	UID       string     `json:"uid,omitempty"`
+	Title     string     `json:"title,omitempty" dgraph:"index=exact"`
+	Author    *Author    `json:"author,omitempty" dgraph:"reverse"`    // want `forward edge Book.Author has no reverse field on Author: add Books \[\]\*Book .json:"~author,omitempty".`
+	Editor    *Author    `json:"editor,omitempty" dgraph:"reverse"`    // want `forward edge Book.Editor has no reverse field on Author: add BooksByEditor \[\]\*Book .json:"~editor,omitempty".`
+	Publisher *Publisher `json:"publisher,omitempty" dgraph:"reverse"` // the reverse field exists
+	Series    *Series    `json:"series,omitempty"`                     // not tagged reverse
+	DType     []string   `json:"dgraph.type,omitempty"`
+}
+
+type Author struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"name,omitempty" dgraph:"index=exact"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type Publisher struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"name,omitempty" dgraph:"index=exact"`
+	Books []*Book  `json:"~publisher,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type Series struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"name,omitempty" dgraph:"index=exact"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type Library struct {
+	UID      string    `json:"uid,omitempty"`
+	Branches []*Branch `json:"branches,omitempty" dgraph:"reverse"` // want `forward edge Library.Branches has no reverse field on Branch: add Libraries \[\]\*Library .json:"~branches,omitempty".`
+	DType    []string  `json:"dgraph.type,omitempty"`
+}
+
+type Branch struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"name,omitempty" dgraph:"index=exact"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
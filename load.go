@@ -0,0 +1,280 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dgraph-io/dgo/v250"
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/dgraph-io/dgraph/v25/chunker"
+	"github.com/dgraph-io/dgraph/v25/x"
+	"golang.org/x/sync/errgroup"
+)
+
+// LoadOptions configures Load.
+//
+// SchemaPath names a Dgraph schema file (optionally gzipped) applied before
+// any data. When it is empty and Load is given a directory, every .schema
+// and .schema.gz file in the directory is applied instead, so a published
+// dataset directory loads as-is.
+//
+// BatchSize is how many N-Quads are written per mutation; it defaults to
+// 1000, matching the live loader.
+//
+// NewUIDs treats explicit UIDs in the data (<0x1>, "uid": "0x1") like blank
+// nodes and assigns fresh UIDs to them, as the live loader's --new_uids flag
+// does. Without it explicit UIDs are written as-is and must already exist.
+type LoadOptions struct {
+	SchemaPath string
+	BatchSize  int
+	NewUIDs    bool
+}
+
+// dataFileExts are the files Load reads, as produced for Dgraph's live loader
+// and by Export.
+var dataFileExts = []string{".rdf", ".rdf.gz", ".json", ".json.gz", ".jsonl", ".jsonl.gz"}
+
+// Load reads RDF or JSON data files (gzipped or not) of the kind published
+// for Dgraph's live loader and writes them to the database, along with
+// JSON Lines (.jsonl) files of the same objects, one per line, as Export
+// writes them. path may be a single file or a directory, which is searched
+// recursively. Files are loaded in order, one batch at a time.
+//
+// Each blank node (_:name) is assigned a UID the first time it is written,
+// and later references to it in any file resolve to the same node, so a
+// dataset split across files or batches keeps its edges.
+func (c client) Load(ctx context.Context, path string, opts LoadOptions) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("modusgraph: load: %w", err)
+	}
+	files := x.FindDataFiles(path, dataFileExts)
+	if len(files) == 0 {
+		return fmt.Errorf("modusgraph: load: no data files found in %s", path)
+	}
+
+	schemas := []string{}
+	if opts.SchemaPath != "" {
+		schemas = append(schemas, opts.SchemaPath)
+	} else if info.IsDir() {
+		schemas = x.FindDataFiles(path, []string{".schema", ".schema.gz"})
+	}
+	for _, file := range schemas {
+		schema, err := readLoadFile(file)
+		if err != nil {
+			return fmt.Errorf("modusgraph: load: reading schema %s: %w", file, err)
+		}
+		if err := c.AlterSchema(ctx, schema); err != nil {
+			return fmt.Errorf("modusgraph: load: applying schema %s: %w", file, err)
+		}
+	}
+
+	dgo, cleanup, err := c.DgraphClient()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	l := &fileLoader{
+		dgo:       dgo,
+		batchSize: opts.BatchSize,
+		newUIDs:   opts.NewUIDs,
+		uids:      map[string]string{},
+	}
+	if l.batchSize <= 0 {
+		l.batchSize = batchSize
+	}
+	for _, file := range files {
+		c.log(ctx).Info("Loading data file", "filename", file)
+		n, err := l.loadFile(ctx, file)
+		if err != nil {
+			return fmt.Errorf("modusgraph: load: %s: %w", file, err)
+		}
+		c.log(ctx).V(1).Info("Loaded data file", "filename", file, "nquads", n)
+	}
+	return nil
+}
+
+// readLoadFile reads a whole file, decompressing it if gzipped.
+func readLoadFile(path string) (string, error) {
+	if _, err := os.Stat(path); err != nil {
+		return "", err
+	}
+	rd, cleanup := chunker.FileReader(path, nil)
+	defer cleanup()
+	data, err := io.ReadAll(rd)
+	return string(data), err
+}
+
+// fileLoader writes parsed N-Quads through a dgo client, remembering the UID
+// assigned to each blank node across batches and files.
+type fileLoader struct {
+	dgo       *dgo.Dgraph
+	batchSize int
+	newUIDs   bool
+	uids      map[string]string
+}
+
+// loadFile parses one data file and writes it in batches, returning how many
+// N-Quads it wrote.
+func (l *fileLoader) loadFile(ctx context.Context, file string) (int, error) {
+	rd, cleanup := chunker.FileReader(file, nil)
+	defer cleanup()
+
+	if strings.HasSuffix(strings.TrimSuffix(file, ".gz"), ".jsonl") {
+		lines, stop := jsonLinesArray(rd)
+		defer stop()
+		return l.load(ctx, lines, chunker.JsonFormat)
+	}
+	format := chunker.DataFormat(file, "")
+	if format == chunker.UnknownFormat {
+		isJSON, err := chunker.IsJSONData(rd)
+		if err != nil || !isJSON {
+			return 0, fmt.Errorf("unable to figure out data format")
+		}
+		format = chunker.JsonFormat
+	}
+	return l.load(ctx, rd, format)
+}
+
+// load parses data of format from rd and writes it in batches, returning how
+// many N-Quads it wrote.
+func (l *fileLoader) load(ctx context.Context, rd *bufio.Reader, format chunker.InputFormat) (int, error) {
+	ck := chunker.NewChunker(format, l.batchSize)
+	nqbuf := ck.NQuads()
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		defer nqbuf.Flush()
+		for {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+			chunk, errChunk := ck.Chunk(rd)
+			if errChunk != nil && errChunk != io.EOF {
+				return fmt.Errorf("chunking data: %w", errChunk)
+			}
+			if err := ck.Parse(chunk); err != nil {
+				return fmt.Errorf("parsing data: %w", err)
+			}
+			if errChunk == io.EOF {
+				return nil
+			}
+		}
+	})
+
+	written := 0
+	g.Go(func() error {
+		// Keep draining after a failure so the parser never blocks on a full
+		// buffer and can observe the cancelled context.
+		defer func() {
+			for range nqbuf.Ch() {
+			}
+		}()
+		batch := make([]*api.NQuad, 0, l.batchSize)
+		for nqs := range nqbuf.Ch() {
+			batch = append(batch, nqs...)
+			for len(batch) >= l.batchSize {
+				if err := l.write(gctx, batch[:l.batchSize]); err != nil {
+					return err
+				}
+				written += l.batchSize
+				batch = append(batch[:0], batch[l.batchSize:]...)
+			}
+		}
+		if len(batch) > 0 {
+			if err := l.write(gctx, batch); err != nil {
+				return err
+			}
+			written += len(batch)
+		}
+		return nil
+	})
+	err := g.Wait()
+	return written, err
+}
+
+// jsonLinesArray returns a reader of the JSON objects in rd, one per line,
+// as the JSON array the chunker reads, and a function to call once done
+// reading. Blank lines are skipped.
+func jsonLinesArray(rd *bufio.Reader) (*bufio.Reader, func()) {
+	pr, pw := io.Pipe()
+	go func() {
+		sep := "["
+		for {
+			line, err := rd.ReadBytes('\n')
+			if len(bytes.TrimSpace(line)) > 0 {
+				if _, werr := io.WriteString(pw, sep); werr != nil {
+					return
+				}
+				if _, werr := pw.Write(line); werr != nil {
+					return
+				}
+				sep = ","
+			}
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		if sep == "[" {
+			// An empty file is an empty array.
+			io.WriteString(pw, sep)
+		}
+		io.WriteString(pw, "]")
+		pw.Close()
+	}()
+	return bufio.NewReader(pr), func() { pr.Close() }
+}
+
+// write commits one batch, first resolving blank nodes seen in earlier
+// batches, then recording the UIDs assigned to new ones.
+func (l *fileLoader) write(ctx context.Context, nqs []*api.NQuad) error {
+	for _, nq := range nqs {
+		nq.Subject = l.resolve(nq.Subject)
+		if nq.ObjectId != "" {
+			nq.ObjectId = l.resolve(nq.ObjectId)
+		}
+	}
+	resp, err := l.dgo.NewTxn().Mutate(ctx, &api.Mutation{Set: nqs, CommitNow: true})
+	if err != nil {
+		return err
+	}
+	for name, uid := range resp.Uids {
+		l.uids["_:"+strings.TrimPrefix(name, "_:")] = uid
+	}
+	return nil
+}
+
+// resolve maps a node reference to its UID when one has been assigned,
+// leaving new blank nodes for the server to assign.
+func (l *fileLoader) resolve(node string) string {
+	if !strings.HasPrefix(node, "_:") {
+		if !l.newUIDs {
+			return node
+		}
+		node = "_:" + node
+	}
+	if uid, ok := l.uids[node]; ok {
+		return uid
+	}
+	return node
+}
@@ -0,0 +1,91 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+func writeGzip(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	zw := gzip.NewWriter(f)
+	_, err = zw.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "films.schema"), []byte(`
+name: string @index(exact) .
+director.film: [uid] @reverse .
+initial_release_date: datetime .
+`), 0o644))
+	writeGzip(t, filepath.Join(dir, "films.rdf.gz"), `
+_:lang <name> "Fritz Lang" .
+_:lang <dgraph.type> "Director" .
+_:metropolis <name> "Metropolis" .
+_:metropolis <initial_release_date> "1927-01-10" .
+_:metropolis <dgraph.type> "Film" .
+_:lang <director.film> _:metropolis .
+`)
+	// Blank nodes in the JSON file refer to nodes created by the RDF file.
+	writeGzip(t, filepath.Join(dir, "more.json.gz"), `[
+  {"uid": "_:m", "name": "M", "dgraph.type": "Film"},
+  {"uid": "_:lang", "director.film": [{"uid": "_:m"}]}
+]`)
+
+	client, cleanup := CreateTestClient(t, "file://"+GetTempDir(t))
+	defer cleanup()
+	ctx := context.Background()
+
+	// A batch of one N-Quad forces blank nodes to resolve across mutations.
+	require.NoError(t, client.Load(ctx, dir, mg.LoadOptions{BatchSize: 1}))
+
+	resp, err := client.QueryRaw(ctx, `{
+		q(func: eq(name, "Fritz Lang")) {
+			name
+			director.film(orderasc: name) { name }
+		}
+		films(func: type(Film)) { count(uid) }
+	}`, nil)
+	require.NoError(t, err)
+	var result struct {
+		Q []struct {
+			Name  string `json:"name"`
+			Films []struct {
+				Name string `json:"name"`
+			} `json:"director.film"`
+		} `json:"q"`
+		Films []struct {
+			Count int `json:"count"`
+		} `json:"films"`
+	}
+	require.NoError(t, json.Unmarshal(resp, &result))
+	require.Len(t, result.Q, 1)
+	require.Len(t, result.Q[0].Films, 2)
+	require.Equal(t, "M", result.Q[0].Films[0].Name)
+	require.Equal(t, "Metropolis", result.Q[0].Films[1].Name)
+	require.Equal(t, 2, result.Films[0].Count)
+
+	resp, err = client.QueryRaw(ctx, `schema(pred: [initial_release_date]) { type }`, nil)
+	require.NoError(t, err)
+	require.Contains(t, string(resp), "datetime")
+
+	require.ErrorContains(t, client.Load(ctx, filepath.Join(dir, "missing.rdf"), mg.LoadOptions{}),
+		"no such file")
+}
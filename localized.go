@@ -0,0 +1,252 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// Localized holds the language variants of a string predicate, keyed by
+// language tag; the "" key holds the untagged value. Fields of this type get
+// the @lang directive, and Insert, Upsert and Update write every variant, so a
+// struct can express what Dgraph stores as name, name@en and name@fr:
+//
+//	type Film struct {
+//	    Name  modusgraph.Localized[string] `json:"name,omitempty" dgraph:"index=exact"`
+//	    // UID and DType omitted
+//	}
+//
+//	film := &Film{Name: modusgraph.Localized[string]{"": "Metropolis", "de": "Metropolis", "fr": "Métropolis"}}
+//
+// Get loads every variant back; nodes decoded by a Query carry only the
+// untagged value until passed to LoadLocalized. Writes add and replace
+// variants but never remove one that is absent from the map.
+type Localized[T ~string] map[string]T
+
+// SchemaType reports the Dgraph type of the predicate to dgman.
+func (Localized[T]) SchemaType() string {
+	return "string"
+}
+
+// Get returns the value of the first language of chain that has one, using
+// Dgraph's language-list semantics: "." stands for the untagged value, or any
+// language when it is last. With no chain, the untagged value is returned.
+func (l Localized[T]) Get(chain ...string) (T, bool) {
+	if len(chain) == 0 {
+		chain = []string{""}
+	}
+	for i, lang := range chain {
+		if lang == "." {
+			if v, ok := l[""]; ok {
+				return v, true
+			}
+			if i == len(chain)-1 {
+				for _, v := range l {
+					return v, true
+				}
+			}
+			continue
+		}
+		if v, ok := l[lang]; ok {
+			return v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// MarshalJSON writes the untagged value, or null when there is none, which a
+// set mutation ignores. The tagged variants are written separately.
+func (l Localized[T]) MarshalJSON() ([]byte, error) {
+	if v, ok := l[""]; ok {
+		return json.Marshal(string(v))
+	}
+	return []byte("null"), nil
+}
+
+// UnmarshalJSON reads the untagged value of a query result.
+func (l *Localized[T]) UnmarshalJSON(data []byte) error {
+	var v *string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v != nil {
+		if *l == nil {
+			*l = make(Localized[T])
+		}
+		(*l)[""] = T(*v)
+	}
+	return nil
+}
+
+// isLocalizedType reports whether t is an instance of Localized.
+func isLocalizedType(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String &&
+		t.Elem().Kind() == reflect.String && t.PkgPath() == reflect.TypeFor[Localized[string]]().PkgPath() &&
+		strings.HasPrefix(t.Name(), "Localized[")
+}
+
+// localizedFields returns the indexes and predicates of the Localized fields
+// of struct type t.
+func localizedFields(t reflect.Type) (fields []int, predicates []string) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.IsExported() && isLocalizedType(f.Type) {
+			fields = append(fields, i)
+			predicates = append(predicates, fieldPredicate(f))
+		}
+	}
+	return fields, predicates
+}
+
+// hasLocalizedFields reports whether the type of obj has Localized fields.
+func hasLocalizedFields(obj any) bool {
+	fields, _ := localizedFields(reflect.TypeOf(obj))
+	return len(fields) > 0
+}
+
+// applyLocalized gives the Localized predicates of models the @lang
+// directive, which Dgraph requires for language-tagged values.
+func applyLocalized(s *SchemaInfo, models ...any) {
+	for _, m := range models {
+		_, preds := localizedFields(reflect.TypeOf(UnwrapSchema(m)))
+		for _, pred := range preds {
+			for i := range s.Predicates {
+				if s.Predicates[i].Name == pred {
+					s.Predicates[i].Lang = true
+				}
+			}
+		}
+	}
+}
+
+// injectLocalized writes the tagged variants of the Localized fields of obj
+// to the nodes the preceding mutation of tx wrote, before tx is committed.
+func injectLocalized(ctx context.Context, tx *dg.TxnContext, obj any) error {
+	var nodes []map[string]any
+	for _, sv := range structElems(obj) {
+		fields, preds := localizedFields(sv.Type())
+		uid := uidOf(sv.Addr().Interface())
+		if len(fields) == 0 || uid == "" {
+			continue
+		}
+		node := map[string]any{"uid": uid}
+		for i, f := range fields {
+			iter := sv.Field(f).MapRange()
+			for iter.Next() {
+				if lang := iter.Key().String(); lang != "" {
+					node[preds[i]+"@"+lang] = iter.Value().String()
+				}
+			}
+		}
+		if len(node) > 1 {
+			nodes = append(nodes, node)
+		}
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	setJSON, err := json.Marshal(nodes)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Txn().Mutate(ctx, &api.Mutation{SetJson: setJSON})
+	return err
+}
+
+// LoadLocalized fills every language variant of the Localized fields of obj,
+// a pointer to a struct or a slice of them, from the nodes their UIDs name.
+// Get does this itself; call it on nodes decoded by a Query, which carry only
+// the untagged value.
+func LoadLocalized(ctx context.Context, client Client, obj any) error {
+	byUID := make(map[string]reflect.Value)
+	var uids []string
+	var preds []string
+	for _, sv := range structElems(UnwrapSchema(obj)) {
+		_, p := localizedFields(sv.Type())
+		uid := uidOf(sv.Addr().Interface())
+		if len(p) == 0 || !isUIDValue(uid) {
+			continue
+		}
+		preds = p
+		byUID[uid] = sv
+		uids = append(uids, uid)
+	}
+	if len(uids) == 0 {
+		return nil
+	}
+
+	selection := make([]string, len(preds))
+	for i, p := range preds {
+		selection[i] = p + "@*"
+	}
+	query := fmt.Sprintf("{ q(func: uid(%s)) { uid %s } }",
+		strings.Join(uids, ", "), strings.Join(selection, " "))
+	resp, err := client.QueryRaw(ctx, query, nil)
+	if err != nil {
+		return fmt.Errorf("loading language variants: %w", err)
+	}
+	var result struct {
+		Q []map[string]json.RawMessage `json:"q"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return err
+	}
+	for _, node := range result.Q {
+		var uid string
+		if err := json.Unmarshal(node["uid"], &uid); err != nil {
+			return err
+		}
+		sv, ok := byUID[uid]
+		if !ok {
+			continue
+		}
+		fields, preds := localizedFields(sv.Type())
+		for i, f := range fields {
+			field := sv.Field(f)
+			values := reflect.MakeMap(field.Type())
+			for key, raw := range node {
+				lang, ok := strings.CutPrefix(key, preds[i])
+				if !ok || (lang != "" && lang[0] != '@') {
+					continue
+				}
+				var v string
+				if err := json.Unmarshal(raw, &v); err != nil {
+					return fmt.Errorf("decoding %s: %w", key, err)
+				}
+				values.SetMapIndex(reflect.ValueOf(strings.TrimPrefix(lang, "@")),
+					reflect.ValueOf(v).Convert(field.Type().Elem()))
+			}
+			if values.Len() > 0 {
+				field.Set(values)
+			}
+		}
+	}
+	return nil
+}
+
+// LangPredicate renders predicate with a language chain for use in query
+// filters, orderings and selections, such as eq(name@fr:en, "...") or
+// orderasc: name@fr:. ; "." stands for the untagged value, or any language
+// when it is last.
+func LangPredicate(predicate string, chain ...string) string {
+	if len(chain) == 0 {
+		return predicate
+	}
+	return predicate + "@" + strings.Join(chain, ":")
+}
@@ -0,0 +1,107 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type LocalizedFilm struct {
+	UID   string               `json:"uid,omitempty"`
+	Name  mg.Localized[string] `json:"lf_name,omitempty" dgraph:"index=exact"`
+	Year  int                  `json:"lf_year,omitempty"`
+	DType []string             `json:"dgraph.type,omitempty"`
+}
+
+func TestLocalized(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "LocalizedWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "LocalizedWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+			require.NoError(t, client.UpdateSchema(ctx, &LocalizedFilm{}))
+
+			schema, err := client.GetSchema(ctx)
+			require.NoError(t, err)
+			name, ok := schema.Predicate("lf_name")
+			require.True(t, ok)
+			require.True(t, name.Lang)
+
+			film := &LocalizedFilm{
+				Name: mg.Localized[string]{"": "Metropolis", "de": "Metropolis", "fr": "Métropolis"},
+				Year: 1927,
+			}
+			require.NoError(t, client.Insert(ctx, film))
+
+			var got LocalizedFilm
+			require.NoError(t, client.Get(ctx, &got, film.UID))
+			require.Equal(t, film.Name, got.Name)
+			fr, ok := got.Name.Get("it", "fr")
+			require.True(t, ok)
+			require.Equal(t, "Métropolis", fr)
+
+			// Variants are added and replaced on update.
+			got.Name["fr"] = "Métropolis (1927)"
+			got.Name["es"] = "Metrópolis"
+			require.NoError(t, client.Update(ctx, &got))
+
+			// Query results carry the untagged value until loaded.
+			var films []LocalizedFilm
+			require.NoError(t, client.Query(ctx, LocalizedFilm{}).
+				Filter(`eq(`+mg.LangPredicate("lf_name", "es")+`, "Metrópolis")`).Nodes(&films))
+			require.Len(t, films, 1)
+			require.Equal(t, mg.Localized[string]{"": "Metropolis"}, films[0].Name)
+			require.NoError(t, mg.LoadLocalized(ctx, client, &films))
+			require.Equal(t, "Métropolis (1927)", films[0].Name["fr"])
+			require.Equal(t, "Metrópolis", films[0].Name["es"])
+			require.Equal(t, "Metropolis", films[0].Name["de"])
+
+			// A node may have tagged values only.
+			sunrise := &LocalizedFilm{Name: mg.Localized[string]{"en": "Sunrise", "de": "Sonnenaufgang"}, Year: 1927}
+			require.NoError(t, client.Insert(ctx, sunrise))
+			got = LocalizedFilm{}
+			require.NoError(t, client.Get(ctx, &got, sunrise.UID))
+			require.Equal(t, sunrise.Name, got.Name)
+			_, ok = got.Name.Get()
+			require.False(t, ok)
+			v, ok := got.Name.Get("fr", ".")
+			require.True(t, ok)
+			require.Contains(t, []string{"Sunrise", "Sonnenaufgang"}, v)
+
+			// Language chains select a variant in raw queries.
+			resp, err := client.QueryRaw(ctx, `{ q(func: type(LocalizedFilm), orderasc: lf_year) @filter(uid(`+
+				sunrise.UID+`)) { name: `+mg.LangPredicate("lf_name", "fr", "en", ".")+` } }`, nil)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"q":[{"name":"Sunrise"}]}`, string(resp))
+		})
+	}
+}
@@ -0,0 +1,19 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/matthewmcneely/modusgraph/modusgraphtest"
+)
+
+// TestMain runs the dgraph:// cases against a disposable Dgraph container
+// when MODUSGRAPH_TEST_DOCKER is set and MODUSGRAPH_TEST_ADDR is not.
+func TestMain(m *testing.M) {
+	os.Exit(modusgraphtest.RunWithDgraph(m))
+}
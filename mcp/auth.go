@@ -0,0 +1,160 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package mcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ReadCheck decides whether actor may read node uid of one type. actor is
+// nil over transports without authentication, such as stdio. Returning an
+// error fails the tool call.
+type ReadCheck func(ctx context.Context, actor *auth.TokenInfo, uid string) (bool, error)
+
+// ErrNoVerifier is returned by NewHTTPHandler without a token verifier.
+var ErrNoVerifier = errors.New("mcp: an HTTP server needs a token verifier")
+
+// NewHTTPHandler serves server over streamable HTTP, admitting only requests
+// whose bearer token verify accepts. The token's TokenInfo is the actor the
+// tools pass to Options.CanRead. Use APIKeys or JWT for verify, or any
+// auth.TokenVerifier, such as one checking tokens with an OAuth provider.
+func NewHTTPHandler(server *sdk.Server, verify auth.TokenVerifier) (http.Handler, error) {
+	if verify == nil {
+		return nil, ErrNoVerifier
+	}
+	handler := sdk.NewStreamableHTTPHandler(func(*http.Request) *sdk.Server { return server }, nil)
+	return auth.RequireBearerToken(verify, &auth.RequireBearerTokenOptions{AllowMissingExpiration: true})(handler), nil
+}
+
+// APIKeys returns a verifier accepting the keys of keys as bearer tokens,
+// each identifying the actor it maps to.
+func APIKeys(keys map[string]string) auth.TokenVerifier {
+	return func(_ context.Context, token string, _ *http.Request) (*auth.TokenInfo, error) {
+		for key, actor := range keys {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(key)) == 1 {
+				return &auth.TokenInfo{UserID: actor}, nil
+			}
+		}
+		return nil, auth.ErrInvalidToken
+	}
+}
+
+// JWT returns a verifier accepting HMAC-signed JSON Web Tokens under secret
+// that have not expired. The actor is the token's sub claim, its scopes the
+// space-separated scope claim, and every claim is kept in Extra.
+func JWT(secret []byte) auth.TokenVerifier {
+	return func(_ context.Context, token string, _ *http.Request) (*auth.TokenInfo, error) {
+		claims := jwt.MapClaims{}
+		_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) { return secret, nil },
+			jwt.WithValidMethods([]string{"HS256", "HS384", "HS512"}))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", auth.ErrInvalidToken, err)
+		}
+		info := &auth.TokenInfo{Extra: claims}
+		info.UserID, _ = claims.GetSubject()
+		if exp, _ := claims.GetExpirationTime(); exp != nil {
+			info.Expiration = exp.Time
+		}
+		if scope, ok := claims["scope"].(string); ok {
+			info.Scopes = strings.Fields(scope)
+		}
+		return info, nil
+	}
+}
+
+// actor returns the authenticated caller of a tool request, nil when the
+// transport has none.
+func actor(req *sdk.CallToolRequest) *auth.TokenInfo {
+	if req == nil || req.Extra == nil {
+		return nil
+	}
+	return req.Extra.TokenInfo
+}
+
+// canRead reports whether actor may read node uid, which has the given
+// types: every type with a ReadCheck must allow it.
+func (h *handlers) canRead(ctx context.Context, actor *auth.TokenInfo, uid string, types []string) (bool, error) {
+	for _, t := range types {
+		check, ok := h.opts.CanRead[t]
+		if !ok {
+			continue
+		}
+		allowed, err := check(ctx, actor, uid)
+		if err != nil || !allowed {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// redact removes from a node's neighbours, at any depth, the nodes actor may
+// not read.
+func (h *handlers) redact(ctx context.Context, actor *auth.TokenInfo, node map[string]any) error {
+	for key, v := range node {
+		switch v := v.(type) {
+		case map[string]any:
+			ok, err := h.readable(ctx, actor, v)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				delete(node, key)
+			}
+		case []any:
+			kept := v[:0]
+			for _, item := range v {
+				child, isNode := item.(map[string]any)
+				if isNode {
+					ok, err := h.readable(ctx, actor, child)
+					if err != nil {
+						return err
+					}
+					if !ok {
+						continue
+					}
+				}
+				kept = append(kept, item)
+			}
+			node[key] = kept
+		}
+	}
+	return nil
+}
+
+// readable reports whether actor may read node, redacting its neighbours if
+// so.
+func (h *handlers) readable(ctx context.Context, actor *auth.TokenInfo, node map[string]any) (bool, error) {
+	if len(h.opts.CanRead) == 0 {
+		return true, nil
+	}
+	uid, _ := node["uid"].(string)
+	ok, err := h.canRead(ctx, actor, uid, nodeTypes(node))
+	if err != nil || !ok {
+		return false, err
+	}
+	return true, h.redact(ctx, actor, node)
+}
+
+// nodeTypes returns the dgraph.type values of a node in a query result.
+func nodeTypes(node map[string]any) []string {
+	raw, _ := node["dgraph.type"].([]any)
+	types := make([]string, 0, len(raw))
+	for _, t := range raw {
+		if s, ok := t.(string); ok {
+			types = append(types, s)
+		}
+	}
+	return types
+}
@@ -0,0 +1,153 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package mcp_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/matthewmcneely/modusgraph/mcp"
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestCanRead(t *testing.T) {
+	var metropolisUID string
+	session, metropolis := connect(t, mcp.Options{CanRead: map[string]mcp.ReadCheck{
+		"film": func(_ context.Context, _ *auth.TokenInfo, uid string) (bool, error) {
+			return uid == metropolisUID, nil
+		},
+		"director": func(context.Context, *auth.TokenInfo, string) (bool, error) { return false, nil },
+	}})
+	metropolisUID = metropolis.UID
+	ctx := context.Background()
+
+	tools, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	for _, tool := range tools.Tools {
+		if tool.Name == "run_query" {
+			t.Errorf("run_query offered although CanRead is set")
+		}
+	}
+
+	var found mcp.Entities
+	if e := call(t, session, "search_entities", map[string]any{"type": "film"}, &found); e != "" {
+		t.Fatalf("search_entities: %s", e)
+	}
+	if len(found.Entities) != 1 || found.Entities[0]["uid"] != metropolis.UID {
+		t.Errorf("readable films = %+v", found.Entities)
+	}
+
+	var node mcp.Node
+	if e := call(t, session, "get_node", map[string]any{"uid": metropolis.UID}, &node); e != "" {
+		t.Fatalf("get_node: %s", e)
+	}
+	if _, ok := node.Node["director"]; ok || node.Node["title"] != "Metropolis" {
+		t.Errorf("node = %+v, want no director", node.Node)
+	}
+	e := call(t, session, "get_node", map[string]any{"uid": metropolis.Director.UID}, &node)
+	if !strings.Contains(e, "no node") {
+		t.Errorf("unreadable node error = %q", e)
+	}
+}
+
+// bearer adds a bearer token to every request.
+type bearer string
+
+func (b bearer) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+string(b))
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestHTTPAuth(t *testing.T) {
+	conn, metropolis := store(t)
+	var actors []string
+	server := mcp.NewServer(conn, mcp.Options{CanRead: map[string]mcp.ReadCheck{
+		"film": func(_ context.Context, actor *auth.TokenInfo, _ string) (bool, error) {
+			actors = append(actors, actor.UserID)
+			return actor.UserID == "alice", nil
+		},
+	}})
+	if _, err := mcp.NewHTTPHandler(server, nil); !errors.Is(err, mcp.ErrNoVerifier) {
+		t.Errorf("NewHTTPHandler without verifier: %v", err)
+	}
+	handler, err := mcp.NewHTTPHandler(server, mcp.APIKeys(map[string]string{"secret": "alice"}))
+	if err != nil {
+		t.Fatalf("NewHTTPHandler: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Post(srv.URL, "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status without token = %d", resp.StatusCode)
+	}
+
+	ctx := context.Background()
+	transport := &sdk.StreamableClientTransport{Endpoint: srv.URL, HTTPClient: &http.Client{Transport: bearer("secret")}}
+	session, err := sdk.NewClient(&sdk.Implementation{Name: "test"}, nil).Connect(ctx, transport, nil)
+	if err != nil {
+		t.Fatalf("client Connect: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	var node mcp.Node
+	if e := call(t, session, "get_node", map[string]any{"uid": metropolis.UID, "depth": 0}, &node); e != "" {
+		t.Fatalf("get_node: %s", e)
+	}
+	if len(actors) != 1 || actors[0] != "alice" {
+		t.Errorf("actors seen by CanRead = %v", actors)
+	}
+
+	transport = &sdk.StreamableClientTransport{Endpoint: srv.URL, HTTPClient: &http.Client{Transport: bearer("wrong")}}
+	if _, err := sdk.NewClient(&sdk.Implementation{Name: "test"}, nil).Connect(ctx, transport, nil); err == nil {
+		t.Errorf("connected with a wrong key")
+	}
+}
+
+func TestJWT(t *testing.T) {
+	secret := []byte("hmac secret")
+	sign := func(key []byte, claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+		if err != nil {
+			t.Fatalf("SignedString: %v", err)
+		}
+		return token
+	}
+	verify := mcp.JWT(secret)
+	ctx := context.Background()
+
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+	info, err := verify(ctx, sign(secret, jwt.MapClaims{"sub": "alice", "exp": exp.Unix(), "scope": "read films"}), nil)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if info.UserID != "alice" || !info.Expiration.Equal(exp) || len(info.Scopes) != 2 ||
+		info.Extra["scope"] != "read films" {
+		t.Errorf("token info = %+v", info)
+	}
+
+	expired := sign(secret, jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(-time.Hour).Unix()})
+	if _, err := verify(ctx, expired, nil); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("expired token: %v", err)
+	}
+	other := sign([]byte("other"), jwt.MapClaims{"sub": "alice"})
+	if _, err := verify(ctx, other, nil); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("token under another secret: %v", err)
+	}
+}
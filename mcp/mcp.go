@@ -0,0 +1,398 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package mcp exposes a modusGraph store to LLM agents over the Model
+// Context Protocol.
+//
+// NewServer registers four tools:
+//
+//   - describe_schema lists the types, their fields, and each field's type
+//     and indexes, so the agent can plan its calls.
+//   - search_entities finds nodes of a type whose indexed string fields
+//     match some text, choosing the DQL function each field's index supports.
+//   - get_node returns a node by UID, with its neighbours to a bounded depth.
+//   - run_query runs a read-only DQL query.
+//
+// Guardrails keep an agent from overwhelming the store or itself. Every call
+// runs in a read-only transaction under a timeout. Result counts, traversal
+// depth, and response size are capped. Options.Types restricts the tools to
+// named types; because raw DQL can reach any type, run_query is not offered
+// when it is set.
+//
+//	srv := mcp.NewServer(client, mcp.Options{Types: []string{"Film", "Director"}})
+//	err := srv.Run(ctx, &sdk.StdioTransport{}) // sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+//
+// Served over HTTP with NewHTTPHandler, every request must carry a bearer
+// token, such as an API key or a JSON Web Token, and Options.CanRead decides
+// per type which nodes the caller may see:
+//
+//	srv := mcp.NewServer(client, mcp.Options{CanRead: map[string]mcp.ReadCheck{
+//	    "Film": func(ctx context.Context, actor *auth.TokenInfo, uid string) (bool, error) {
+//	        return canReadFilm(ctx, actor.UserID, uid)
+//	    },
+//	}})
+//	handler, err := mcp.NewHTTPHandler(srv, mcp.JWT(secret))
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Options configures the guardrails of a server. Zero values select the
+// defaults.
+type Options struct {
+	// Types restricts every tool to nodes of these types. Empty allows every
+	// type in the schema.
+	Types []string
+	// MaxResults caps the nodes search_entities returns. It defaults to 50.
+	MaxResults int
+	// MaxDepth caps how many edges deep get_node follows. It defaults to 2.
+	MaxDepth int
+	// MaxResponseBytes caps the size of a tool's JSON result; larger results
+	// are rejected with a hint to narrow the request. It defaults to 1 MiB.
+	MaxResponseBytes int
+	// Timeout bounds each tool call. It defaults to 30 seconds.
+	Timeout time.Duration
+	// DisableQuery withholds run_query even when Types is empty.
+	DisableQuery bool
+	// CanRead holds, by type, the checks a node of the type must pass to be
+	// returned to the caller. Nodes failing one are left out of search
+	// results and neighbours, and get_node reports them missing. Raw DQL
+	// bypasses the checks, so run_query is not offered when CanRead is set.
+	CanRead map[string]ReadCheck
+}
+
+const (
+	defaultMaxResults       = 50
+	defaultMaxDepth         = 2
+	defaultMaxResponseBytes = 1 << 20
+	defaultTimeout          = 30 * time.Second
+)
+
+// Version is reported to clients as the server implementation version.
+const Version = "v0.1.0"
+
+// NewServer returns an MCP server whose tools read from client. Run it over
+// any transport, such as sdk.StdioTransport or sdk.NewStreamableHTTPHandler.
+func NewServer(client mg.Client, opts Options) *sdk.Server {
+	opts.MaxResults = cmp.Or(opts.MaxResults, defaultMaxResults)
+	opts.MaxDepth = cmp.Or(opts.MaxDepth, defaultMaxDepth)
+	opts.MaxResponseBytes = cmp.Or(opts.MaxResponseBytes, defaultMaxResponseBytes)
+	opts.Timeout = cmp.Or(opts.Timeout, defaultTimeout)
+	h := &handlers{client: client, opts: opts}
+
+	srv := sdk.NewServer(&sdk.Implementation{Name: "modusgraph", Version: Version}, &sdk.ServerOptions{
+		Instructions: "Tools for reading a modusGraph graph database. Call describe_schema first to " +
+			"learn the types and fields, then search_entities or get_node to read nodes.",
+	})
+	readOnly := &sdk.ToolAnnotations{ReadOnlyHint: true, OpenWorldHint: new(bool)}
+	sdk.AddTool(srv, &sdk.Tool{
+		Name:        "describe_schema",
+		Description: "List the graph's types with each field's name, type, and indexes. Edges have type uid.",
+		Annotations: readOnly,
+	}, h.describeSchema)
+	sdk.AddTool(srv, &sdk.Tool{
+		Name: "search_entities",
+		Description: fmt.Sprintf("Find nodes of a type whose indexed string fields match text. "+
+			"Omit text to list nodes of the type. Returns at most %d nodes.", opts.MaxResults),
+		Annotations: readOnly,
+	}, h.searchEntities)
+	sdk.AddTool(srv, &sdk.Tool{
+		Name: "get_node",
+		Description: fmt.Sprintf("Get a node by UID with its fields and, up to depth %d, the nodes "+
+			"its edges point to.", opts.MaxDepth),
+		Annotations: readOnly,
+	}, h.getNode)
+	if len(opts.Types) == 0 && len(opts.CanRead) == 0 && !opts.DisableQuery {
+		sdk.AddTool(srv, &sdk.Tool{
+			Name: "run_query",
+			Description: "Run a read-only DQL query and return its JSON result. Use first: to " +
+				"bound results; variables map $names to values.",
+			Annotations: readOnly,
+		}, h.runQuery)
+	}
+	return srv
+}
+
+type handlers struct {
+	client mg.Client
+	opts   Options
+}
+
+// FieldInfo describes one field of a type.
+type FieldInfo struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type" jsonschema:"the DQL scalar type, or uid for an edge"`
+	List    bool     `json:"list,omitempty"`
+	Index   []string `json:"index,omitempty" jsonschema:"the field's index tokenizers"`
+	Reverse bool     `json:"reverse,omitempty" jsonschema:"whether the edge can be traversed backwards with ~name"`
+}
+
+// TypeInfo describes a type and its fields.
+type TypeInfo struct {
+	Name   string      `json:"name"`
+	Fields []FieldInfo `json:"fields"`
+}
+
+// SchemaInfo is describe_schema's result.
+type SchemaInfo struct {
+	Types []TypeInfo `json:"types"`
+}
+
+// schema reads the types the server exposes.
+func (h *handlers) schema(ctx context.Context) (map[string]TypeInfo, error) {
+	live, err := h.client.GetSchema(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading schema: %w", err)
+	}
+	types := map[string]TypeInfo{}
+	for _, t := range live.Types {
+		if !h.allowed(t.Name) {
+			continue
+		}
+		ti := TypeInfo{Name: t.Name, Fields: []FieldInfo{}}
+		for _, f := range t.Fields {
+			fi := FieldInfo{Name: f, Type: "default"}
+			if p, ok := live.Predicate(f); ok {
+				fi = FieldInfo{Name: p.Name, Type: p.Type, List: p.List, Index: p.Indexes, Reverse: p.Reverse}
+			}
+			ti.Fields = append(ti.Fields, fi)
+		}
+		types[t.Name] = ti
+	}
+	return types, nil
+}
+
+func (h *handlers) allowed(typ string) bool {
+	return len(h.opts.Types) == 0 || slices.Contains(h.opts.Types, typ)
+}
+
+func (h *handlers) describeSchema(ctx context.Context, _ *sdk.CallToolRequest,
+	_ struct{}) (*sdk.CallToolResult, SchemaInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, h.opts.Timeout)
+	defer cancel()
+	types, err := h.schema(ctx)
+	if err != nil {
+		return nil, SchemaInfo{}, err
+	}
+	out := SchemaInfo{Types: []TypeInfo{}}
+	for _, name := range slices.Sorted(maps.Keys(types)) {
+		out.Types = append(out.Types, types[name])
+	}
+	return nil, out, nil
+}
+
+type searchInput struct {
+	Type  string `json:"type" jsonschema:"the type to search, as named by describe_schema"`
+	Text  string `json:"text,omitempty" jsonschema:"text to match; omit to list nodes of the type"`
+	Field string `json:"field,omitempty" jsonschema:"search only this field; default: every indexed string field"`
+	Limit int    `json:"limit,omitempty" jsonschema:"the most nodes to return"`
+}
+
+// Entities is search_entities's result.
+type Entities struct {
+	Entities []map[string]any `json:"entities"`
+}
+
+// searchFuncs are the DQL functions for matching text, by the index they
+// need, best first.
+var searchFuncs = []struct {
+	tokenizer string
+	fn        func(pred string) string
+}{
+	{"fulltext", func(p string) string { return fmt.Sprintf("anyoftext(<%s>, $text)", p) }},
+	{"term", func(p string) string { return fmt.Sprintf("anyofterms(<%s>, $text)", p) }},
+	{"trigram", nil}, // regexp needs the pattern inline; see searchEntities
+	{"exact", func(p string) string { return fmt.Sprintf("eq(<%s>, $text)", p) }},
+	{"hash", func(p string) string { return fmt.Sprintf("eq(<%s>, $text)", p) }},
+}
+
+func (h *handlers) searchEntities(ctx context.Context, req *sdk.CallToolRequest,
+	in searchInput) (*sdk.CallToolResult, Entities, error) {
+	ctx, cancel := context.WithTimeout(ctx, h.opts.Timeout)
+	defer cancel()
+	types, err := h.schema(ctx)
+	if err != nil {
+		return nil, Entities{}, err
+	}
+	t, ok := types[in.Type]
+	if !ok {
+		return nil, Entities{}, fmt.Errorf("unknown type %q; call describe_schema for the available types", in.Type)
+	}
+	limit := h.opts.MaxResults
+	if in.Limit > 0 && in.Limit < limit {
+		limit = in.Limit
+	}
+
+	var filters, selection []string
+	for _, f := range t.Fields {
+		if f.Type != "uid" {
+			selection = append(selection, "<"+f.Name+">")
+		}
+		if in.Text == "" || f.Type != "string" || (in.Field != "" && f.Name != in.Field) {
+			continue
+		}
+		for _, sf := range searchFuncs {
+			if !slices.Contains(f.Index, sf.tokenizer) {
+				continue
+			}
+			if sf.fn != nil {
+				filters = append(filters, sf.fn(f.Name))
+			} else if len(in.Text) >= 3 {
+				filters = append(filters, fmt.Sprintf("regexp(<%s>, /%s/i)", f.Name, regexpLiteral(in.Text)))
+			} else {
+				continue
+			}
+			break
+		}
+	}
+	if in.Field != "" && !slices.ContainsFunc(t.Fields, func(f FieldInfo) bool { return f.Name == in.Field }) {
+		return nil, Entities{}, fmt.Errorf("type %s has no field %q", in.Type, in.Field)
+	}
+	if in.Text != "" && len(filters) == 0 {
+		return nil, Entities{}, fmt.Errorf("type %s has no indexed string field to search%s; "+
+			"use run_query or omit text to list nodes", in.Type, fieldSuffix(in.Field))
+	}
+
+	q := fmt.Sprintf("query search($text: string) {\n  entities(func: type(<%s>), first: %d)", in.Type, limit)
+	if len(filters) > 0 {
+		q += " @filter(" + strings.Join(filters, " OR ") + ")"
+	}
+	q += " {\n    uid\n    dgraph.type\n"
+	for _, s := range selection {
+		q += "    " + s + "\n"
+	}
+	q += "  }\n}"
+	var resp Entities
+	if err := h.query(ctx, q, map[string]string{"$text": in.Text}, &resp); err != nil {
+		return nil, Entities{}, err
+	}
+	readable := []map[string]any{}
+	for _, e := range resp.Entities {
+		ok, err := h.readable(ctx, actor(req), e)
+		if err != nil {
+			return nil, Entities{}, err
+		}
+		if ok {
+			readable = append(readable, e)
+		}
+	}
+	return nil, Entities{Entities: readable}, nil
+}
+
+func fieldSuffix(field string) string {
+	if field == "" {
+		return ""
+	}
+	return " in " + field
+}
+
+// regexpLiteral escapes text for use inside a DQL /regexp/.
+func regexpLiteral(text string) string {
+	return strings.ReplaceAll(regexp.QuoteMeta(text), "/", `\/`)
+}
+
+type nodeInput struct {
+	UID   string `json:"uid" jsonschema:"the node's UID, such as 0x2a"`
+	Depth *int   `json:"depth,omitempty" jsonschema:"how many edges to follow, 0 for the node alone; default 1"`
+}
+
+// Node is get_node's result.
+type Node struct {
+	Node map[string]any `json:"node"`
+}
+
+var uidPattern = regexp.MustCompile(`^0x[0-9a-fA-F]+$`)
+
+func (h *handlers) getNode(ctx context.Context, req *sdk.CallToolRequest,
+	in nodeInput) (*sdk.CallToolResult, Node, error) {
+	ctx, cancel := context.WithTimeout(ctx, h.opts.Timeout)
+	defer cancel()
+	if !uidPattern.MatchString(in.UID) {
+		return nil, Node{}, fmt.Errorf("invalid uid %q; UIDs look like 0x2a", in.UID)
+	}
+	depth := 1
+	if in.Depth != nil {
+		depth = max(0, min(*in.Depth, h.opts.MaxDepth))
+	}
+
+	// expand accepts only type filters, so the neighbours of an unrestricted
+	// server are left unfiltered.
+	root, expand := "has(dgraph.type)", ""
+	if len(h.opts.Types) > 0 {
+		parts := make([]string, len(h.opts.Types))
+		for i, t := range h.opts.Types {
+			parts[i] = fmt.Sprintf("type(<%s>)", t)
+		}
+		root = strings.Join(parts, " OR ")
+		expand = " @filter(" + root + ")"
+	}
+	block := "uid dgraph.type expand(_all_)"
+	for range depth {
+		block = fmt.Sprintf("uid dgraph.type expand(_all_)%s { %s }", expand, block)
+	}
+	q := fmt.Sprintf("{ node(func: uid(%s)) @filter(%s) { %s } }", in.UID, root, block)
+	var resp struct {
+		Node []map[string]any `json:"node"`
+	}
+	if err := h.query(ctx, q, nil, &resp); err != nil {
+		return nil, Node{}, err
+	}
+	if len(resp.Node) == 0 {
+		return nil, Node{}, fmt.Errorf("no node %s", in.UID)
+	}
+	if ok, err := h.readable(ctx, actor(req), resp.Node[0]); err != nil || !ok {
+		return nil, Node{}, cmp.Or(err, fmt.Errorf("no node %s", in.UID))
+	}
+	return nil, Node{Node: resp.Node[0]}, nil
+}
+
+type queryInput struct {
+	Query     string            `json:"query" jsonschema:"a DQL query; mutations are not allowed"`
+	Variables map[string]string `json:"variables,omitempty" jsonschema:"query variables, keyed with their $"`
+}
+
+func (h *handlers) runQuery(ctx context.Context, _ *sdk.CallToolRequest,
+	in queryInput) (*sdk.CallToolResult, any, error) {
+	ctx, cancel := context.WithTimeout(ctx, h.opts.Timeout)
+	defer cancel()
+	raw, err := h.queryRaw(ctx, in.Query, in.Variables)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &sdk.CallToolResult{Content: []sdk.Content{&sdk.TextContent{Text: string(raw)}}}, nil, nil
+}
+
+// queryRaw runs q read-only and enforces MaxResponseBytes.
+func (h *handlers) queryRaw(ctx context.Context, q string, vars map[string]string) ([]byte, error) {
+	raw, err := h.client.QueryRaw(ctx, q, vars)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > h.opts.MaxResponseBytes {
+		return nil, fmt.Errorf("result is %d bytes, over the %d byte limit; request fewer nodes or fields",
+			len(raw), h.opts.MaxResponseBytes)
+	}
+	return raw, nil
+}
+
+func (h *handlers) query(ctx context.Context, q string, vars map[string]string, out any) error {
+	raw, err := h.queryRaw(ctx, q, vars)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
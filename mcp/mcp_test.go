@@ -0,0 +1,214 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/mcp"
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type film struct {
+	UID      string    `json:"uid,omitempty"`
+	DType    []string  `json:"dgraph.type,omitempty"`
+	Title    string    `json:"title,omitempty" dgraph:"index=term"`
+	Year     int       `json:"year,omitempty"`
+	Director *director `json:"director,omitempty"`
+}
+
+type director struct {
+	UID   string   `json:"uid,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+	Name  string   `json:"name,omitempty" dgraph:"index=trigram"`
+}
+
+// connect inserts two films and returns a client session to a server over
+// them.
+func connect(t *testing.T, opts mcp.Options) (*sdk.ClientSession, *film) {
+	t.Helper()
+	conn, metropolis := store(t)
+	ctx := context.Background()
+	serverTransport, clientTransport := sdk.NewInMemoryTransports()
+	server, err := mcp.NewServer(conn, opts).Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server Connect: %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+	session, err := sdk.NewClient(&sdk.Implementation{Name: "test"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client Connect: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session, metropolis
+}
+
+// store returns a client over two films by one director, Metropolis and M.
+func store(t *testing.T) (modusgraph.Client, *film) {
+	t.Helper()
+	conn, err := modusgraph.NewClient("file://"+t.TempDir(), modusgraph.WithAutoSchema(true))
+	if err != nil {
+		t.Fatalf("modusgraph.NewClient: %v", err)
+	}
+	t.Cleanup(conn.Close)
+
+	ctx := context.Background()
+	lang := &director{Name: "Fritz Lang"}
+	metropolis := &film{Title: "Metropolis", Year: 1927, Director: lang}
+	if err := conn.Insert(ctx, metropolis); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+	if err := conn.Insert(ctx, &film{Title: "M", Year: 1931, Director: &director{UID: lang.UID}}); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+	return conn, metropolis
+}
+
+// call calls a tool and decodes its result into out. If the tool fails it
+// returns the error text instead.
+func call(t *testing.T, s *sdk.ClientSession, tool string, args map[string]any, out any) (errText string) {
+	t.Helper()
+	res, err := s.CallTool(context.Background(), &sdk.CallToolParams{Name: tool, Arguments: args})
+	if err != nil {
+		t.Fatalf("CallTool %s: %v", tool, err)
+	}
+	text := res.Content[0].(*sdk.TextContent).Text
+	if res.IsError {
+		return text
+	}
+	if err := json.Unmarshal([]byte(text), out); err != nil {
+		t.Fatalf("%s result %q: %v", tool, text, err)
+	}
+	return ""
+}
+
+func TestTools(t *testing.T) {
+	session, metropolis := connect(t, mcp.Options{})
+	ctx := context.Background()
+
+	tools, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	var names []string
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"describe_schema", "get_node", "run_query", "search_entities"}) {
+		t.Fatalf("tools = %v", names)
+	}
+
+	var schema mcp.SchemaInfo
+	if e := call(t, session, "describe_schema", nil, &schema); e != "" {
+		t.Fatalf("describe_schema: %s", e)
+	}
+	if len(schema.Types) != 2 || schema.Types[0].Name != "director" || schema.Types[1].Name != "film" {
+		t.Fatalf("types = %+v", schema.Types)
+	}
+	i := slices.IndexFunc(schema.Types[1].Fields, func(f mcp.FieldInfo) bool { return f.Name == "title" })
+	if i < 0 || schema.Types[1].Fields[i].Type != "string" ||
+		!slices.Equal(schema.Types[1].Fields[i].Index, []string{"term"}) {
+		t.Errorf("film fields = %+v", schema.Types[1].Fields)
+	}
+
+	var found mcp.Entities
+	if e := call(t, session, "search_entities", map[string]any{"type": "film", "text": "metropolis"}, &found); e != "" {
+		t.Fatalf("search_entities: %s", e)
+	}
+	if len(found.Entities) != 1 || found.Entities[0]["title"] != "Metropolis" ||
+		found.Entities[0]["uid"] != metropolis.UID {
+		t.Errorf("film search = %+v", found.Entities)
+	}
+	if e := call(t, session, "search_entities", map[string]any{"type": "director", "text": "lan"}, &found); e != "" {
+		t.Fatalf("search_entities: %s", e)
+	}
+	if len(found.Entities) != 1 || found.Entities[0]["name"] != "Fritz Lang" {
+		t.Errorf("trigram search = %+v", found.Entities)
+	}
+	e := call(t, session, "search_entities", map[string]any{"type": "film", "limit": 1}, &found)
+	if e != "" || len(found.Entities) != 1 {
+		t.Errorf("listing with limit 1 = %+v, %s", found.Entities, e)
+	}
+	e = call(t, session, "search_entities", map[string]any{"type": "actor", "text": "x"}, &found)
+	if !strings.Contains(e, "unknown type") {
+		t.Errorf("unknown type error = %q", e)
+	}
+
+	var node mcp.Node
+	if e := call(t, session, "get_node", map[string]any{"uid": metropolis.UID}, &node); e != "" {
+		t.Fatalf("get_node: %s", e)
+	}
+	dir, _ := node.Node["director"].(map[string]any)
+	if node.Node["title"] != "Metropolis" || dir["name"] != "Fritz Lang" {
+		t.Errorf("node = %+v", node.Node)
+	}
+	if e := call(t, session, "get_node", map[string]any{"uid": "1; drop"}, &node); !strings.Contains(e, "invalid uid") {
+		t.Errorf("invalid uid error = %q", e)
+	}
+
+	var rows map[string][]map[string]any
+	q := `query q($y: int) { films(func: type(film)) @filter(gt(year, $y)) { title } }`
+	args := map[string]any{"query": q, "variables": map[string]string{"$y": "1930"}}
+	if e := call(t, session, "run_query", args, &rows); e != "" {
+		t.Fatalf("run_query: %s", e)
+	}
+	if len(rows["films"]) != 1 || rows["films"][0]["title"] != "M" {
+		t.Errorf("run_query = %+v", rows)
+	}
+}
+
+func TestGuardrails(t *testing.T) {
+	session, metropolis := connect(t, mcp.Options{Types: []string{"film"}, MaxResponseBytes: 100})
+	ctx := context.Background()
+
+	tools, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	for _, tool := range tools.Tools {
+		if tool.Name == "run_query" {
+			t.Errorf("run_query offered although Types restricts the server")
+		}
+	}
+
+	var schema mcp.SchemaInfo
+	call(t, session, "describe_schema", nil, &schema)
+	if len(schema.Types) != 1 || schema.Types[0].Name != "film" {
+		t.Errorf("types = %+v", schema.Types)
+	}
+	var found mcp.Entities
+	e := call(t, session, "search_entities", map[string]any{"type": "director"}, &found)
+	if !strings.Contains(e, "unknown type") {
+		t.Errorf("hidden type error = %q", e)
+	}
+
+	// The director is outside Types, so get_node does not follow the edge.
+	var node mcp.Node
+	if e := call(t, session, "get_node", map[string]any{"uid": metropolis.UID}, &node); e != "" {
+		t.Fatalf("get_node: %s", e)
+	}
+	if _, ok := node.Node["director"]; ok {
+		t.Errorf("node = %+v, want no director", node.Node)
+	}
+	if e := call(t, session, "get_node", map[string]any{"uid": metropolis.UID, "depth": 0}, &node); e != "" {
+		t.Fatalf("get_node: %s", e)
+	}
+	if node.Node["title"] != "Metropolis" {
+		t.Errorf("node at depth 0 = %+v", node.Node)
+	}
+
+	// Two films with their fields exceed 100 bytes.
+	e = call(t, session, "search_entities", map[string]any{"type": "film"}, &found)
+	if !strings.Contains(e, "byte limit") {
+		t.Errorf("response size error = %q", e)
+	}
+}
@@ -0,0 +1,530 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package migrate runs versioned schema and data migrations against a
+// modusGraph client.
+//
+// Migrations are registered in code, each with a unique version. A Runner
+// applies the pending ones in version order: first the migration's Schema,
+// through AlterSchema, then its Up function for data backfills. Applied
+// versions are recorded on a single metadata node in the database, which
+// also carries a lease-based lock so that only one instance migrates at a
+// time.
+//
+//	r := migrate.New(client, migrate.Options{LockWait: time.Minute})
+//	err := r.Register(
+//	    migrate.Migration{Version: 1, Name: "people", Schema: `name: string @index(exact) .`},
+//	    migrate.Migration{Version: 2, Name: "backfill names", Up: backfillNames},
+//	)
+//	applied, err := r.Run(ctx)
+//
+// A migration whose Up fails is not recorded and runs again on the next
+// Run, so Up functions should be idempotent. Rollback reverts applied
+// migrations, newest first, with their Down and DownSchema.
+//
+// Migrations can also be written as script files and read with LoadDir, so
+// that they can be applied without a Go build, as the query tool's --migrate
+// flag does.
+//
+// Run also records the highest applied version as the database's schema
+// version, which clients created with modusgraph.WithSchemaVersion check at
+// startup.
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/dgraph-io/dgo/v250"
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	mg "github.com/matthewmcneely/modusgraph"
+)
+
+// ErrLocked is returned by Run when another instance holds the migration
+// lock and Options.LockWait elapses before it is released.
+var ErrLocked = errors.New("migrate: migration lock held by another instance")
+
+// Predicates of the metadata node.
+const (
+	keyPredicate     = "modusgraph.migrations.key"
+	appliedPredicate = "modusgraph.migrations.applied"
+	ownerPredicate   = "modusgraph.migrations.owner"
+	expiresPredicate = "modusgraph.migrations.expires"
+	metadataType     = "ModusGraphMigrations"
+	metadataKey      = "migrations"
+
+	// schemaVersionPredicate is the predicate modusgraph.SetSchemaVersion
+	// writes, which Rollback deletes when it reverts every migration.
+	schemaVersionPredicate = "modusgraph.schema.version"
+)
+
+const metadataSchema = keyPredicate + `: string @index(exact) @upsert .
+` + appliedPredicate + `: [int] .
+` + ownerPredicate + `: string .
+` + expiresPredicate + `: datetime .
+type ` + metadataType + ` {
+	` + keyPredicate + `
+	` + appliedPredicate + `
+	` + ownerPredicate + `
+	` + expiresPredicate + `
+}
+`
+
+// Migration is one versioned change. At least one of Schema and Up must be
+// set.
+type Migration struct {
+	// Version orders migrations; it must be positive and unique.
+	Version int64
+	// Name describes the migration in errors and logs.
+	Name string
+	// Schema, if set, is applied with AlterSchema before Up runs.
+	Schema string
+	// Up, if set, performs data changes such as backfills.
+	Up func(ctx context.Context, client mg.Client) error
+	// Down, if set, undoes Up's data changes when the migration is rolled
+	// back.
+	Down func(ctx context.Context, client mg.Client) error
+	// DownSchema, if set, is applied with AlterSchema when the migration is
+	// rolled back, after Down runs. AlterSchema cannot remove a predicate;
+	// Down can, with DropPredicate.
+	DownSchema string
+}
+
+// Options configures a Runner.
+type Options struct {
+	// Owner identifies this instance in the lock. Defaults to the host name
+	// and process id.
+	Owner string
+	// LockTTL is how long the lock is held without renewal before other
+	// instances may take it over, in case this one dies mid-run, by the
+	// client's clock (see modusgraph.WithClock). The lease is renewed before
+	// each migration. Defaults to 5 minutes.
+	LockTTL time.Duration
+	// LockWait is how long Run waits for another instance's lock before
+	// returning ErrLocked. Zero fails immediately.
+	LockWait time.Duration
+}
+
+// Runner applies registered migrations to a client.
+type Runner struct {
+	client     mg.Client
+	opts       Options
+	migrations []Migration
+}
+
+// New returns a Runner for client with no migrations registered.
+func New(client mg.Client, opts Options) *Runner {
+	if opts.Owner == "" {
+		host, _ := os.Hostname()
+		opts.Owner = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	if opts.LockTTL <= 0 {
+		opts.LockTTL = 5 * time.Minute
+	}
+	return &Runner{client: client, opts: opts}
+}
+
+// Register adds migrations to the runner. They may be registered in any
+// order; they are applied by ascending Version.
+func (r *Runner) Register(migrations ...Migration) error {
+	for _, m := range migrations {
+		if m.Version <= 0 {
+			return fmt.Errorf("migrate: migration %q: version must be positive", m.Name)
+		}
+		if m.Schema == "" && m.Up == nil {
+			return fmt.Errorf("migrate: migration %d: neither Schema nor Up is set", m.Version)
+		}
+		if slices.ContainsFunc(r.migrations, func(o Migration) bool { return o.Version == m.Version }) {
+			return fmt.Errorf("migrate: duplicate migration version %d", m.Version)
+		}
+		r.migrations = append(r.migrations, m)
+	}
+	slices.SortFunc(r.migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return nil
+}
+
+// Applied returns the versions recorded as applied, in ascending order.
+func (r *Runner) Applied(ctx context.Context) ([]int64, error) {
+	if err := r.client.AlterSchema(ctx, metadataSchema); err != nil {
+		return nil, err
+	}
+	st, err := r.readState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return st.Applied, nil
+}
+
+// Pending returns the registered migrations not yet applied, in the order
+// Run would apply them.
+func (r *Runner) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := r.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, m := range r.migrations {
+		if !slices.Contains(applied, m.Version) {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// Run takes the migration lock, applies the pending migrations in order and
+// releases the lock. It stops at the first failing migration and returns the
+// versions applied before it along with the error.
+func (r *Runner) Run(ctx context.Context) ([]int64, error) {
+	if err := r.client.AlterSchema(ctx, metadataSchema); err != nil {
+		return nil, err
+	}
+	uid, err := r.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.unlock(context.WithoutCancel(ctx), uid)
+
+	pending, err := r.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	st, err := r.readState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	version, err := r.client.SchemaVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Databases migrated before schema versions were recorded catch up here.
+	if len(st.Applied) > 0 {
+		if err := r.recordVersion(ctx, &version, st.Applied[len(st.Applied)-1]); err != nil {
+			return nil, err
+		}
+	}
+
+	var applied []int64
+	for _, m := range pending {
+		// Renewing the lease also confirms this instance still holds it.
+		if err := r.renew(ctx, uid); err != nil {
+			return applied, err
+		}
+		if m.Schema != "" {
+			if err := r.client.AlterSchema(ctx, m.Schema); err != nil {
+				return applied, fmt.Errorf("migrate: migration %d (%s): schema: %w", m.Version, m.Name, err)
+			}
+		}
+		if m.Up != nil {
+			if err := m.Up(ctx, r.client); err != nil {
+				return applied, fmt.Errorf("migrate: migration %d (%s): %w", m.Version, m.Name, err)
+			}
+		}
+		if err := r.mutate(ctx, map[string]any{"uid": uid, appliedPredicate: []int64{m.Version}}, nil); err != nil {
+			return applied, fmt.Errorf("migrate: recording migration %d: %w", m.Version, err)
+		}
+		applied = append(applied, m.Version)
+		if err := r.recordVersion(ctx, &version, m.Version); err != nil {
+			return applied, err
+		}
+	}
+	return applied, nil
+}
+
+// Rollback takes the migration lock, reverts the applied migrations with a
+// version above to, newest first, and releases the lock. Each is reverted by
+// running its Down and then applying its DownSchema. It returns the versions
+// reverted. Before reverting anything it checks that every one of them is
+// registered and has a Down or a DownSchema. Like Run, it stops at the first
+// failure, leaving that migration recorded as applied.
+//
+// The recorded schema version is lowered to the highest version still
+// applied, or cleared when none is.
+func (r *Runner) Rollback(ctx context.Context, to int64) ([]int64, error) {
+	if err := r.client.AlterSchema(ctx, metadataSchema); err != nil {
+		return nil, err
+	}
+	uid, err := r.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.unlock(context.WithoutCancel(ctx), uid)
+
+	st, err := r.readState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var revert []Migration
+	for _, v := range slices.Backward(st.Applied) {
+		if v <= to {
+			break
+		}
+		i := slices.IndexFunc(r.migrations, func(m Migration) bool { return m.Version == v })
+		if i < 0 {
+			return nil, fmt.Errorf("migrate: migration %d is applied but not registered", v)
+		}
+		m := r.migrations[i]
+		if m.Down == nil && m.DownSchema == "" {
+			return nil, fmt.Errorf("migrate: migration %d (%s) cannot be rolled back: neither Down nor DownSchema is set",
+				m.Version, m.Name)
+		}
+		revert = append(revert, m)
+	}
+
+	var reverted []int64
+	for _, m := range revert {
+		if err := r.renew(ctx, uid); err != nil {
+			return reverted, err
+		}
+		if m.Down != nil {
+			if err := m.Down(ctx, r.client); err != nil {
+				return reverted, fmt.Errorf("migrate: rolling back migration %d (%s): %w", m.Version, m.Name, err)
+			}
+		}
+		if m.DownSchema != "" {
+			if err := r.client.AlterSchema(ctx, m.DownSchema); err != nil {
+				return reverted, fmt.Errorf("migrate: rolling back migration %d (%s): schema: %w", m.Version, m.Name, err)
+			}
+		}
+		if err := r.mutate(ctx, nil, map[string]any{"uid": uid, appliedPredicate: []int64{m.Version}}); err != nil {
+			return reverted, fmt.Errorf("migrate: recording rollback of migration %d: %w", m.Version, err)
+		}
+		reverted = append(reverted, m.Version)
+		if err := r.lowerVersion(ctx, st.Applied[:len(st.Applied)-len(reverted)]); err != nil {
+			return reverted, err
+		}
+	}
+	return reverted, nil
+}
+
+// lowerVersion records the highest of applied as the schema version, or
+// clears the recorded version when applied is empty.
+func (r *Runner) lowerVersion(ctx context.Context, applied []int64) error {
+	if len(applied) > 0 {
+		v := applied[len(applied)-1]
+		if err := r.client.SetSchemaVersion(ctx, v); err != nil {
+			return fmt.Errorf("migrate: recording schema version %d: %w", v, err)
+		}
+		return nil
+	}
+	version, err := r.client.SchemaVersion(ctx)
+	if err != nil || version == 0 {
+		return err
+	}
+	resp, err := r.client.QueryRaw(ctx, fmt.Sprintf(`{ v(func: has(%s)) { uid } }`, schemaVersionPredicate), nil)
+	if err != nil {
+		return err
+	}
+	var result struct {
+		V []struct {
+			UID string `json:"uid"`
+		} `json:"v"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return err
+	}
+	for _, n := range result.V {
+		if err := r.mutate(ctx, nil, map[string]any{"uid": n.UID, schemaVersionPredicate: nil}); err != nil {
+			return fmt.Errorf("migrate: clearing schema version: %w", err)
+		}
+	}
+	return nil
+}
+
+// recordVersion raises the schema version recorded in the database to v if
+// it is lower, so that clients built with an older modusgraph.WithSchemaVersion
+// refuse to start against it.
+func (r *Runner) recordVersion(ctx context.Context, recorded *int64, v int64) error {
+	if v <= *recorded {
+		return nil
+	}
+	if err := r.client.SetSchemaVersion(ctx, v); err != nil {
+		return fmt.Errorf("migrate: recording schema version %d: %w", v, err)
+	}
+	*recorded = v
+	return nil
+}
+
+// state is the content of the metadata node.
+type state struct {
+	UID     string    `json:"uid"`
+	Applied []int64   `json:"applied"`
+	Owner   string    `json:"owner"`
+	Expires time.Time `json:"expires"`
+}
+
+func (r *Runner) readState(ctx context.Context) (state, error) {
+	dgc, cleanup, err := r.client.DgraphClient()
+	if err != nil {
+		return state{}, err
+	}
+	defer cleanup()
+	txn := dgc.NewReadOnlyTxn()
+	return queryState(ctx, txn)
+}
+
+func queryState(ctx context.Context, txn *dgo.Txn) (state, error) {
+	resp, err := txn.QueryWithVars(ctx, fmt.Sprintf(`query q($key: string) {
+		m(func: eq(%s, $key)) {
+			uid
+			applied: %s
+			owner: %s
+			expires: %s
+		}
+	}`, keyPredicate, appliedPredicate, ownerPredicate, expiresPredicate),
+		map[string]string{"$key": metadataKey})
+	if err != nil {
+		return state{}, err
+	}
+	var result struct {
+		M []state `json:"m"`
+	}
+	if err := json.Unmarshal(resp.Json, &result); err != nil {
+		return state{}, err
+	}
+	if len(result.M) == 0 {
+		return state{}, nil
+	}
+	st := result.M[0]
+	slices.Sort(st.Applied)
+	return st, nil
+}
+
+// lock takes the migration lock, waiting up to LockWait for another owner to
+// release it, and returns the uid of the metadata node. Leases are stamped by
+// the client's clock, but the wait is real time.
+func (r *Runner) lock(ctx context.Context) (string, error) {
+	wait := time.NewTimer(r.opts.LockWait)
+	defer wait.Stop()
+	for {
+		uid, err := r.tryLock(ctx)
+		if !errors.Is(err, ErrLocked) || r.opts.LockWait <= 0 {
+			return uid, err
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-wait.C:
+			return "", err
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// tryLock claims the lock if it is free, expired or already ours. Against a
+// Dgraph cluster, two instances claiming it at once write the same keys, so
+// one transaction aborts; that is reported as ErrLocked.
+func (r *Runner) tryLock(ctx context.Context) (string, error) {
+	dgc, cleanup, err := r.client.DgraphClient()
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	txn := dgc.NewTxn()
+	defer func() { _ = txn.Discard(ctx) }()
+	st, err := queryState(ctx, txn)
+	if err != nil {
+		return "", err
+	}
+	if st.Owner != "" && st.Owner != r.opts.Owner && r.client.Now().Before(st.Expires) {
+		return "", ErrLocked
+	}
+
+	uid := st.UID
+	if uid == "" {
+		uid = "_:meta"
+	}
+	set, err := json.Marshal(map[string]any{
+		"uid":            uid,
+		"dgraph.type":    metadataType,
+		keyPredicate:     metadataKey,
+		ownerPredicate:   r.opts.Owner,
+		expiresPredicate: r.client.Now().Add(r.opts.LockTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+	resp, err := txn.Mutate(ctx, &api.Mutation{SetJson: set})
+	if err == nil {
+		err = txn.Commit(ctx)
+	}
+	if errors.Is(err, dgo.ErrAborted) {
+		return "", ErrLocked
+	}
+	if err != nil {
+		return "", err
+	}
+	if st.UID == "" {
+		uid = resp.Uids["meta"]
+	}
+
+	// Confirm the claim stuck, in case a concurrent claim was not detected.
+	if st, err = r.readState(ctx); err != nil {
+		return "", err
+	}
+	if st.Owner != r.opts.Owner {
+		return "", ErrLocked
+	}
+	return uid, nil
+}
+
+// renew extends the lease, failing with ErrLocked if another instance took
+// the lock over after it expired.
+func (r *Runner) renew(ctx context.Context, uid string) error {
+	st, err := r.readState(ctx)
+	if err != nil {
+		return err
+	}
+	if st.Owner != r.opts.Owner {
+		return ErrLocked
+	}
+	return r.mutate(ctx, map[string]any{
+		"uid":            uid,
+		expiresPredicate: r.client.Now().Add(r.opts.LockTTL),
+	}, nil)
+}
+
+// unlock releases the lock if this instance still holds it.
+func (r *Runner) unlock(ctx context.Context, uid string) {
+	st, err := r.readState(ctx)
+	if err != nil || st.Owner != r.opts.Owner {
+		return
+	}
+	_ = r.mutate(ctx, nil, map[string]any{
+		"uid":            uid,
+		ownerPredicate:   nil,
+		expiresPredicate: nil,
+	})
+}
+
+func (r *Runner) mutate(ctx context.Context, set, del map[string]any) error {
+	dgc, cleanup, err := r.client.DgraphClient()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	mu := &api.Mutation{CommitNow: true}
+	if set != nil {
+		if mu.SetJson, err = json.Marshal(set); err != nil {
+			return err
+		}
+	}
+	if del != nil {
+		if mu.DeleteJson, err = json.Marshal(del); err != nil {
+			return err
+		}
+	}
+	_, err = dgc.NewTxn().Mutate(ctx, mu)
+	return err
+}
@@ -0,0 +1,275 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package migrate_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/migrate"
+	"github.com/matthewmcneely/modusgraph/modusgraphtest"
+	"github.com/stretchr/testify/require"
+)
+
+func setJSON(ctx context.Context, client modusgraph.Client, body string) error {
+	dgc, cleanup, err := client.DgraphClient()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	_, err = dgc.NewTxn().Mutate(ctx, &api.Mutation{SetJson: []byte(body), CommitNow: true})
+	return err
+}
+
+func TestRun(t *testing.T) {
+	client, err := modusgraph.NewClient("file://" + t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	ctx := context.Background()
+
+	var backfills int
+	r := migrate.New(client, migrate.Options{Owner: "a"})
+	require.NoError(t, r.Register(
+		migrate.Migration{
+			Version: 2,
+			Name:    "backfill",
+			Up: func(ctx context.Context, c modusgraph.Client) error {
+				backfills++
+				return setJSON(ctx, c, `{"person_name": "Ada", "dgraph.type": "Person"}`)
+			},
+		},
+		migrate.Migration{Version: 1, Name: "people", Schema: `person_name: string @index(exact) .`},
+	))
+	require.ErrorContains(t, r.Register(migrate.Migration{Version: 1, Schema: "x: int ."}), "duplicate")
+	require.ErrorContains(t, r.Register(migrate.Migration{Version: 9}), "neither Schema nor Up")
+
+	pending, err := r.Pending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	require.Equal(t, int64(1), pending[0].Version)
+
+	applied, err := r.Run(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, applied)
+
+	resp, err := client.QueryRaw(ctx, `{ q(func: eq(person_name, "Ada")) { count(uid) } }`, nil)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"q":[{"count":1}]}`, string(resp))
+
+	// The highest applied version is recorded for WithSchemaVersion checks.
+	version, err := client.SchemaVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), version)
+
+	// Nothing is pending on a second run, including from another runner.
+	applied, err = migrate.New(client, migrate.Options{Owner: "b"}).Run(ctx)
+	require.NoError(t, err)
+	require.Empty(t, applied)
+	require.Equal(t, 1, backfills)
+
+	// A failing migration stops the run and is not recorded.
+	boom := errors.New("boom")
+	require.NoError(t, r.Register(
+		migrate.Migration{Version: 3, Name: "fails", Up: func(context.Context, modusgraph.Client) error { return boom }},
+		migrate.Migration{Version: 4, Name: "after", Schema: `person_age: int .`},
+	))
+	applied, err = r.Run(ctx)
+	require.ErrorIs(t, err, boom)
+	require.Empty(t, applied)
+	versions, err := r.Applied(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, versions)
+	version, err = client.SchemaVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), version)
+}
+
+func TestRunLocked(t *testing.T) {
+	client, err := modusgraph.NewClient("file://" + t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	ctx := context.Background()
+
+	// While runner a is migrating, runner b cannot take the lock.
+	var inner error
+	a := migrate.New(client, migrate.Options{Owner: "a"})
+	require.NoError(t, a.Register(migrate.Migration{
+		Version: 1,
+		Up: func(ctx context.Context, c modusgraph.Client) error {
+			b := migrate.New(c, migrate.Options{Owner: "b"})
+			_, inner = b.Run(ctx)
+			return nil
+		},
+	}))
+	applied, err := a.Run(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1}, applied)
+	require.ErrorIs(t, inner, migrate.ErrLocked)
+
+	// Once a has finished, the lock is free again.
+	_, err = migrate.New(client, migrate.Options{Owner: "b"}).Run(ctx)
+	require.NoError(t, err)
+}
+
+func TestRunLockExpires(t *testing.T) {
+	clock := modusgraphtest.NewClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	client, err := modusgraph.NewClient("file://"+t.TempDir(), modusgraph.WithClock(clock.Now))
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	ctx := context.Background()
+
+	// Once a's lease has expired by the client's clock, b takes the lock
+	// over, and a stops before its next migration.
+	var inner error
+	a := migrate.New(client, migrate.Options{Owner: "a", LockTTL: time.Minute})
+	require.NoError(t, a.Register(
+		migrate.Migration{
+			Version: 1,
+			Up: func(ctx context.Context, c modusgraph.Client) error {
+				clock.Advance(2 * time.Minute)
+				_, inner = migrate.New(c, migrate.Options{Owner: "b"}).Run(ctx)
+				return nil
+			},
+		},
+		migrate.Migration{Version: 2, Schema: `person_age: int .`},
+	))
+	applied, err := a.Run(ctx)
+	require.ErrorIs(t, err, migrate.ErrLocked)
+	require.Equal(t, []int64{1}, applied)
+	require.NoError(t, inner)
+}
+
+func TestRollback(t *testing.T) {
+	client, err := modusgraph.NewClient("file://" + t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	ctx := context.Background()
+
+	var downs []int64
+	down := func(v int64) func(context.Context, modusgraph.Client) error {
+		return func(context.Context, modusgraph.Client) error {
+			downs = append(downs, v)
+			return nil
+		}
+	}
+	r := migrate.New(client, migrate.Options{Owner: "a"})
+	require.NoError(t, r.Register(
+		migrate.Migration{Version: 1, Name: "people", Schema: `person_name: string @index(exact) .`, Down: down(1)},
+		migrate.Migration{Version: 2, Name: "index", Schema: `person_name: string @index(exact, term) .`,
+			DownSchema: `person_name: string @index(exact) .`},
+		migrate.Migration{Version: 3, Name: "ages", Schema: `person_age: int .`, Down: down(3)},
+	))
+	_, err = r.Run(ctx)
+	require.NoError(t, err)
+
+	// Newest first, down to the given version.
+	reverted, err := r.Rollback(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, []int64{3, 2}, reverted)
+	require.Equal(t, []int64{3}, downs)
+	applied, err := r.Applied(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1}, applied)
+	version, err := client.SchemaVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), version)
+	schema, err := client.GetSchema(ctx)
+	require.NoError(t, err)
+	p, ok := schema.Predicate("person_name")
+	require.True(t, ok)
+	require.Equal(t, []string{"exact"}, p.Indexes)
+
+	// Reverted migrations are pending again.
+	pending, err := r.Pending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+
+	// A migration with no down step stops the rollback before anything runs.
+	noDown := migrate.New(client, migrate.Options{Owner: "a"})
+	require.NoError(t, noDown.Register(migrate.Migration{Version: 1, Schema: `person_name: string @index(exact) .`}))
+	_, err = noDown.Rollback(ctx, 0)
+	require.ErrorContains(t, err, "cannot be rolled back")
+
+	// Rolling everything back clears the recorded version.
+	reverted, err = r.Rollback(ctx, 0)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1}, reverted)
+	version, err = client.SchemaVersion(ctx)
+	require.NoError(t, err)
+	require.Zero(t, version)
+}
+
+func TestLoadDir(t *testing.T) {
+	client, err := modusgraph.NewClient("file://" + t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	ctx := context.Background()
+
+	scripts := fstest.MapFS{
+		"README.md":             {Data: []byte("ignored")},
+		"0001_people.up.schema": {Data: []byte("person_name: string @index(exact) .\nperson_team: string @index(exact) .\n")},
+		"0002_backfill.up.dql": {Data: []byte(`# Two people, then a team for everyone without one.
+{
+  set {
+    _:ada <person_name> "Ada {Lovelace}" .
+    _:ada <dgraph.type> "Person" .
+    _:bob <person_name> "Bob" .
+    _:bob <dgraph.type> "Person" .
+  }
+}
+upsert {
+  query {
+    q(func: has(person_name)) { v as uid }
+  }
+  mutation {
+    set { uid(v) <person_team> "core" . }
+  }
+}
+`)},
+		"0002_backfill.down.dql": {Data: []byte(`upsert {
+  query { q(func: has(person_name)) { v as uid } }
+  mutation { delete { uid(v) <person_team> * . } }
+}`)},
+	}
+	migrations, err := migrate.LoadDir(scripts)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	require.Equal(t, "people", migrations[0].Name)
+	require.Equal(t, "backfill", migrations[1].Name)
+
+	r := migrate.New(client, migrate.Options{})
+	require.NoError(t, r.Register(migrations...))
+	_, err = r.Run(ctx)
+	require.NoError(t, err)
+
+	count := func() string {
+		resp, err := client.QueryRaw(ctx, `{ q(func: eq(person_team, "core")) { count(uid) } }`, nil)
+		require.NoError(t, err)
+		return string(resp)
+	}
+	require.JSONEq(t, `{"q":[{"count":2}]}`, count())
+
+	reverted, err := r.Rollback(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, []int64{2}, reverted)
+	require.JSONEq(t, `{"q":[{"count":0}]}`, count())
+
+	for name, body := range map[string]string{
+		"1_x.up.schema":    "",
+		"people.up.schema": "a: int .",
+		"0001_x.up.dql":    "{ set { _:a <p> \"v\" . }",
+		"0002_x.up.dql":    "set { _:a <p> \"v\" . }",
+		"0003_x.down.dql":  "{ set { _:a <p> \"v\" . } }",
+	} {
+		_, err := migrate.LoadDir(fstest.MapFS{name: {Data: []byte(body)}})
+		require.Error(t, err, name)
+	}
+}
@@ -0,0 +1,216 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/dgraph-io/dgraph/v25/dql"
+	mg "github.com/matthewmcneely/modusgraph"
+)
+
+// scriptName matches migration script file names: version, name, direction
+// and kind.
+var scriptName = regexp.MustCompile(`^(\d+)_([^.]+)\.(up|down)\.(schema|dql)$`)
+
+// LoadDir reads the migration scripts at the top of fsys, such as an
+// os.DirFS or an embed.FS, and returns one Migration per version, ready for
+// Register. Scripts are named <version>_<name>.<up|down>.<schema|dql>:
+//
+//	0001_people.up.schema      person_name: string @index(exact) .
+//	0002_backfill.up.dql       upsert { query { ... } mutation { set { ... } } }
+//	0002_backfill.down.dql     upsert { query { ... } mutation { delete { ... } } }
+//
+// A .schema script is a DQL schema alteration, applied with AlterSchema as
+// Schema or DownSchema. A .dql script is a data transform of one or more
+// mutation blocks, each either { set { ... } delete { ... } } or an upsert
+// block; they run as Up or Down, in one transaction. All scripts of a
+// version must share its name. Files with other extensions are ignored.
+func LoadDir(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int64]*Migration)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		file := e.Name()
+		if ext := path.Ext(file); ext != ".schema" && ext != ".dql" {
+			continue
+		}
+		match := scriptName.FindStringSubmatch(file)
+		if match == nil {
+			return nil, fmt.Errorf("migrate: %s: script names must look like 0001_name.up.schema", file)
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migrate: %s: version must be positive", file)
+		}
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migrate: %s: migration %d is already named %q", file, version, m.Name)
+		}
+		body, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		up := match[3] == "up"
+		if match[4] == "schema" {
+			if up {
+				m.Schema = string(body)
+			} else {
+				m.DownSchema = string(body)
+			}
+			continue
+		}
+		transform, err := parseScript(string(body))
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %s: %w", file, err)
+		}
+		if up {
+			m.Up = transform
+		} else {
+			m.Down = transform
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Schema == "" && m.Up == nil {
+			return nil, fmt.Errorf("migrate: migration %d (%s) has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return migrations, nil
+}
+
+// parseScript parses the mutation blocks of a .dql script and returns a
+// function running them in one transaction.
+func parseScript(src string) (func(context.Context, mg.Client) error, error) {
+	blocks, err := splitBlocks(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("no mutation blocks")
+	}
+	reqs := make([]*api.Request, 0, len(blocks))
+	for i, b := range blocks {
+		req, err := dql.ParseMutation(b)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", i+1, err)
+		}
+		reqs = append(reqs, req)
+	}
+	return func(ctx context.Context, client mg.Client) error {
+		dgc, cleanup, err := client.DgraphClient()
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		txn := dgc.NewTxn()
+		defer func() { _ = txn.Discard(ctx) }()
+		for i, req := range reqs {
+			if _, err := txn.Do(ctx, req); err != nil {
+				return fmt.Errorf("block %d: %w", i+1, err)
+			}
+		}
+		return txn.Commit(ctx)
+	}, nil
+}
+
+// splitBlocks splits src into its top-level { ... } and upsert { ... }
+// blocks.
+func splitBlocks(src string) ([]string, error) {
+	var blocks []string
+	i := 0
+	for {
+		i = skipSpace(src, i)
+		if i == len(src) {
+			return blocks, nil
+		}
+		start := i
+		if strings.HasPrefix(src[i:], "upsert") {
+			i = skipSpace(src, i+len("upsert"))
+		}
+		if i == len(src) || src[i] != '{' {
+			rest, _, _ := strings.Cut(src[start:], "\n")
+			return nil, fmt.Errorf("expected a { ... } or upsert { ... } block, found %q", rest)
+		}
+		end, err := closingBrace(src, i)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, src[start:end+1])
+		i = end + 1
+	}
+}
+
+// skipSpace returns the index of the first byte of src at or after i that
+// is neither white space nor part of a # comment.
+func skipSpace(src string, i int) int {
+	for i < len(src) {
+		switch src[i] {
+		case ' ', '\t', '\r', '\n':
+			i++
+		case '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// closingBrace returns the index of the } that closes the { at src[open].
+// Braces inside quoted strings, <IRIs> and # comments do not count.
+func closingBrace(src string, open int) (int, error) {
+	depth := 0
+	for i := open; i < len(src); i++ {
+		switch src[i] {
+		case '"':
+			for i++; i < len(src) && src[i] != '"'; i++ {
+				if src[i] == '\\' {
+					i++
+				}
+			}
+		case '<':
+			if end := strings.IndexAny(src[i:], "> \t\n"); end > 0 && src[i+end] == '>' {
+				i += end
+			}
+		case '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case '{':
+			depth++
+		case '}':
+			if depth--; depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unterminated block")
+}
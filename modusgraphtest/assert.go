@@ -0,0 +1,105 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraphtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+)
+
+// The assertions report a mismatch with t.Errorf, so a test can make several
+// and see every failure; a query error ends the test with t.Fatalf. model is
+// a value or pointer of the node type, as for Client.UIDs, and filter and
+// params follow BindFilter.
+
+// AssertCount checks that the graph holds want nodes of model's type.
+func AssertCount(t testing.TB, client mg.Client, model any, want int) {
+	t.Helper()
+	if got := len(matching(t, client, model, "")); got != want {
+		t.Errorf("modusgraphtest: %s has %d nodes, want %d", typeName(model), got, want)
+	}
+}
+
+// AssertExists checks that a node of model's type matches filter and returns
+// the UID of the first one, or "" if there is none.
+func AssertExists(t testing.TB, client mg.Client, model any, filter string, params ...any) string {
+	t.Helper()
+	uids := matching(t, client, model, filter, params...)
+	if len(uids) == 0 {
+		t.Errorf("modusgraphtest: no %s matches %s", typeName(model), filter)
+		return ""
+	}
+	return fmt.Sprintf("%#x", uids[0])
+}
+
+// AssertNotExists checks that no node of model's type matches filter.
+func AssertNotExists(t testing.TB, client mg.Client, model any, filter string, params ...any) {
+	t.Helper()
+	if uids := matching(t, client, model, filter, params...); len(uids) > 0 {
+		t.Errorf("modusgraphtest: %d %s nodes match %s, want none", len(uids), typeName(model), filter)
+	}
+}
+
+// AssertEdge checks that the node from has an edge predicate to the node to.
+// A predicate prefixed with ~ follows a reverse edge.
+func AssertEdge(t testing.TB, client mg.Client, from, predicate, to string) {
+	t.Helper()
+	if !hasEdge(t, client, from, predicate, to) {
+		t.Errorf("modusgraphtest: no edge %s -%s-> %s", from, predicate, to)
+	}
+}
+
+// AssertNoEdge checks that the node from has no edge predicate to the node to.
+func AssertNoEdge(t testing.TB, client mg.Client, from, predicate, to string) {
+	t.Helper()
+	if hasEdge(t, client, from, predicate, to) {
+		t.Errorf("modusgraphtest: unexpected edge %s -%s-> %s", from, predicate, to)
+	}
+}
+
+func matching(t testing.TB, client mg.Client, model any, filter string, params ...any) []uint64 {
+	t.Helper()
+	uids, err := client.UIDs(context.Background(), model, filter, params...)
+	if err != nil {
+		t.Fatalf("modusgraphtest: querying %s: %v", typeName(model), err)
+	}
+	return uids
+}
+
+func hasEdge(t testing.TB, client mg.Client, from, predicate, to string) bool {
+	t.Helper()
+	q := fmt.Sprintf(`{ q(func: uid(%s)) { e: %s @filter(uid(%s)) { uid } } }`, from, predicate, to)
+	resp, err := client.QueryRaw(context.Background(), q, nil)
+	if err != nil {
+		t.Fatalf("modusgraphtest: querying edge %s -%s-> %s: %v", from, predicate, to, err)
+	}
+	// e is an object for a single-valued edge and a list otherwise, and is
+	// absent when the filter leaves nothing.
+	var found struct {
+		Q []struct {
+			E json.RawMessage `json:"e"`
+		} `json:"q"`
+	}
+	if err := json.Unmarshal(resp, &found); err != nil {
+		t.Fatalf("modusgraphtest: decoding edge query: %v", err)
+	}
+	return len(found.Q) > 0 && bytes.Contains(found.Q[0].E, []byte(`"uid"`))
+}
+
+// typeName returns the name of model's node type for failure messages.
+func typeName(model any) string {
+	model = mg.UnwrapSchema(model)
+	if s, ok := model.(mg.Schema); ok {
+		return s.SchemaTypeName()
+	}
+	return reflect.Indirect(reflect.ValueOf(model)).Type().Name()
+}
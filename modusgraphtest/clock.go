@@ -0,0 +1,54 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraphtest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a settable clock for deterministic timestamps. Code under test
+// takes a func() time.Time, time.Now in production and Clock.Now in tests.
+// WithClock makes the client read it, and computed fields read it the same
+// way:
+//
+//	clock := modusgraphtest.NewClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+//	client := modusgraphtest.NewTestClient(t, modusgraph.WithClock(clock.Now),
+//	    modusgraph.WithCompute("UpdatedAt", func(*Film) time.Time { return clock.Now() }))
+//	clock.Advance(time.Hour)
+//
+// A Clock is safe for concurrent use.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock returns a clock stopped at start.
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+// Now returns the clock's current time. It does not move on its own.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d and returns the new time.
+func (c *Clock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	return c.now
+}
+
+// Set moves the clock to t.
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
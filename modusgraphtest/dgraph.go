@@ -0,0 +1,175 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraphtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	mg "github.com/matthewmcneely/modusgraph"
+)
+
+// DockerEnv names the environment variable that opts ForEachBackend and
+// RunWithDgraph into starting a disposable Dgraph container when AddrEnv is
+// unset.
+const DockerEnv = "MODUSGRAPH_TEST_DOCKER"
+
+// ImageEnv names the environment variable overriding DefaultImage.
+const ImageEnv = "MODUSGRAPH_TEST_DGRAPH_IMAGE"
+
+// DefaultImage is the Dgraph image containers are started from.
+const DefaultImage = "dgraph/standalone:v25.0.0"
+
+// startTimeout bounds how long a container may take to accept queries,
+// pulling its image included.
+const startTimeout = 5 * time.Minute
+
+// ErrNoDocker is returned by RunDgraph when the docker command is not
+// installed.
+var ErrNoDocker = errors.New("modusgraphtest: docker is not installed")
+
+// Dgraph is a disposable Dgraph container started by RunDgraph.
+type Dgraph struct {
+	// Addr is the host:port of the container's gRPC endpoint, for a
+	// dgraph:// URI.
+	Addr string
+	id   string
+}
+
+// RunDgraph starts a Dgraph container from DefaultImage, or the image named
+// by ImageEnv, and waits until it accepts queries. The caller removes it
+// with Close. It runs the docker command, so it needs neither a Go Docker
+// client nor a fixed port: the container's gRPC port is published on a free
+// loopback port.
+func RunDgraph(ctx context.Context) (*Dgraph, error) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return nil, ErrNoDocker
+	}
+	image := os.Getenv(ImageEnv)
+	if image == "" {
+		image = DefaultImage
+	}
+	ctx, cancel := context.WithTimeout(ctx, startTimeout)
+	defer cancel()
+
+	id, err := docker(ctx, "run", "--detach", "--rm", "--publish", "127.0.0.1::9080", image)
+	if err != nil {
+		return nil, err
+	}
+	d := &Dgraph{id: id}
+	ports, err := docker(ctx, "port", id, "9080/tcp")
+	if err != nil {
+		_ = d.Close()
+		return nil, err
+	}
+	d.Addr, _, _ = strings.Cut(ports, "\n")
+	if err := d.wait(ctx); err != nil {
+		_ = d.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+// wait polls the container until a schema query succeeds.
+func (d *Dgraph) wait(ctx context.Context) error {
+	for {
+		err := d.ping(ctx)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("modusgraphtest: Dgraph at %s did not start: %w", d.Addr, err)
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (d *Dgraph) ping(ctx context.Context) error {
+	client, err := mg.NewClient("dgraph://" + d.Addr)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	_, err = client.GetSchema(ctx)
+	return err
+}
+
+// Close removes the container and its data.
+func (d *Dgraph) Close() error {
+	_, err := docker(context.Background(), "rm", "--force", d.id)
+	return err
+}
+
+// StartDgraph starts a Dgraph container for t with RunDgraph, removes it when
+// the test ends, and returns its address. The test is skipped when docker is
+// not installed.
+func StartDgraph(t testing.TB) string {
+	t.Helper()
+	d, err := RunDgraph(context.Background())
+	if errors.Is(err, ErrNoDocker) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("modusgraphtest: starting Dgraph: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := d.Close(); err != nil {
+			t.Errorf("modusgraphtest: removing Dgraph container: %v", err)
+		}
+	})
+	return d.Addr
+}
+
+// RunWithDgraph runs the tests of m, for a TestMain. When DockerEnv is set
+// and AddrEnv is not, it first starts one Dgraph container for all of them
+// and sets AddrEnv to its address, so that the tests that read AddrEnv run
+// their remote cases against it rather than skipping them. The container is
+// removed when the tests end. Without docker, the tests run as if DockerEnv
+// were unset.
+//
+//	func TestMain(m *testing.M) {
+//	    os.Exit(modusgraphtest.RunWithDgraph(m))
+//	}
+func RunWithDgraph(m *testing.M) int {
+	if os.Getenv(DockerEnv) == "" || os.Getenv(AddrEnv) != "" {
+		return m.Run()
+	}
+	d, err := RunDgraph(context.Background())
+	if errors.Is(err, ErrNoDocker) {
+		fmt.Fprintf(os.Stderr, "%v; skipping the dgraph:// cases\n", err)
+		return m.Run()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "modusgraphtest: starting Dgraph: %v\n", err)
+		return 1
+	}
+	defer func() { _ = d.Close() }()
+	if err := os.Setenv(AddrEnv, d.Addr); err != nil {
+		fmt.Fprintf(os.Stderr, "modusgraphtest: %v\n", err)
+		return 1
+	}
+	return m.Run()
+}
+
+// docker runs the docker command and returns its trimmed output.
+func docker(ctx context.Context, args ...string) (string, error) {
+	out, err := exec.CommandContext(ctx, "docker", args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("modusgraphtest: docker %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("modusgraphtest: docker %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
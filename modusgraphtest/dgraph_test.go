@@ -0,0 +1,41 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraphtest_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/matthewmcneely/modusgraph/modusgraphtest"
+)
+
+// TestMain shares one container among the ForEachBackend tests when
+// MODUSGRAPH_TEST_DOCKER is set.
+func TestMain(m *testing.M) {
+	os.Exit(modusgraphtest.RunWithDgraph(m))
+}
+
+func TestRunDgraphWithoutDocker(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	if _, err := modusgraphtest.RunDgraph(context.Background()); !errors.Is(err, modusgraphtest.ErrNoDocker) {
+		t.Fatalf("RunDgraph without docker: got %v, want ErrNoDocker", err)
+	}
+}
+
+func TestStartDgraph(t *testing.T) {
+	if os.Getenv(modusgraphtest.DockerEnv) == "" {
+		t.Skipf("%s not set", modusgraphtest.DockerEnv)
+	}
+	addr := modusgraphtest.StartDgraph(t)
+	client := modusgraphtest.NewTestClientURI(t, "dgraph://"+addr)
+	director := &HarnessDirector{Name: "Agnès Varda"}
+	if err := client.Insert(context.Background(), director); err != nil {
+		t.Fatal(err)
+	}
+	modusgraphtest.AssertCount(t, client, HarnessDirector{}, 1)
+}
@@ -0,0 +1,75 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraphtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"gopkg.in/yaml.v3"
+)
+
+// ReadFixtures decodes the nodes in the file at path into values of type T.
+// The file holds one object or a list of them, as JSON (.json) or YAML
+// (.yaml, .yml). Keys are matched against T's json tags in both formats, so
+// a fixture names predicates just as the stored node does, and nested
+// objects become edges.
+func ReadFixtures[T any](path string) ([]*T, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+	case ".yaml", ".yml":
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("modusgraphtest: %s: %w", path, err)
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, fmt.Errorf("modusgraphtest: %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("modusgraphtest: %s: unsupported fixture format (want .json, .yaml or .yml)", path)
+	}
+
+	var nodes []*T
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		nodes = []*T{new(T)}
+		err = json.Unmarshal(trimmed, nodes[0])
+	} else {
+		err = json.Unmarshal(data, &nodes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("modusgraphtest: %s: %w", path, err)
+	}
+	return nodes, nil
+}
+
+// LoadFixtures reads the nodes in the file at path as ReadFixtures does and
+// inserts them, failing the test on any error. The returned nodes carry the
+// UIDs they were assigned.
+func LoadFixtures[T any](t testing.TB, client mg.Client, path string) []*T {
+	t.Helper()
+	nodes, err := ReadFixtures[T](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) == 0 {
+		return nodes
+	}
+	if err := client.Insert(context.Background(), &nodes); err != nil {
+		t.Fatalf("modusgraphtest: inserting fixtures from %s: %v", path, err)
+	}
+	return nodes
+}
@@ -0,0 +1,139 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package modusgraphtest is a test harness for code built on modusGraph. It
+// opens clients that clean up after themselves, loads fixtures from JSON or
+// YAML files, supplies a controllable clock, and asserts on the state of the
+// graph. StartDgraph and RunWithDgraph start disposable Dgraph containers
+// for the tests of the remote backend.
+//
+//	func TestCatalog(t *testing.T) {
+//	    client := modusgraphtest.NewTestClient(t)
+//	    films := modusgraphtest.LoadFixtures[Film](t, client, "testdata/films.yaml")
+//	    modusgraphtest.AssertCount(t, client, Film{}, len(films))
+//	}
+//
+// The embedded engine is a process-wide singleton, so tests holding an
+// embedded client must not run in parallel with one another.
+package modusgraphtest
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/stdr"
+	mg "github.com/matthewmcneely/modusgraph"
+)
+
+// AddrEnv names the environment variable holding the host:port of the Dgraph
+// cluster ForEachBackend runs against. When it is unset, ForEachBackend
+// starts a container if DockerEnv is set, and skips its dgraph subtest
+// otherwise.
+const AddrEnv = "MODUSGRAPH_TEST_ADDR"
+
+// LogLevelEnv names the environment variable setting the verbosity of the
+// logger given to test clients; only level 0 messages are logged when it is
+// unset.
+const LogLevelEnv = "MODUSGRAPH_TEST_LOG_LEVEL"
+
+// NewTestClient returns a client backed by an embedded engine in a fresh
+// temporary directory, with AutoSchema enabled. opts are applied after the
+// defaults and may override them. The client is closed, the engine shut down
+// and the directory removed when the test ends.
+func NewTestClient(t testing.TB, opts ...mg.ClientOpt) mg.Client {
+	t.Helper()
+	return NewTestClientURI(t, "file://"+TempDir(t), opts...)
+}
+
+// NewTestClientURI is NewTestClient for an arbitrary connection URI. Against
+// a remote URI all data is dropped when the client opens, in case an
+// earlier run was interrupted, and again when the test ends.
+func NewTestClientURI(t testing.TB, uri string, opts ...mg.ClientOpt) mg.Client {
+	t.Helper()
+	defaults := []mg.ClientOpt{mg.WithAutoSchema(true), mg.WithLogger(testLogger())}
+	client, err := mg.NewClient(uri, append(defaults, opts...)...)
+	if err != nil {
+		t.Fatalf("modusgraphtest: opening client for %s: %v", uri, err)
+	}
+	remote := !strings.HasPrefix(uri, "file://")
+	if remote {
+		if err := client.DropAll(context.Background()); err != nil {
+			t.Logf("modusgraphtest: dropping data at start: %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		if remote {
+			if err := client.DropAll(context.Background()); err != nil {
+				t.Errorf("modusgraphtest: dropping data: %v", err)
+			}
+		}
+		client.Close()
+		mg.Shutdown()
+	})
+	return client
+}
+
+// ForEachBackend runs fn as a subtest against each backend: "file" with an
+// embedded engine, and "dgraph" with the cluster named by AddrEnv. Without
+// AddrEnv, the dgraph subtest runs against its own container from
+// StartDgraph when DockerEnv is set, and is skipped otherwise; RunWithDgraph
+// shares one container among all the tests of a package instead. Each
+// subtest gets its own client built from opts.
+func ForEachBackend(t *testing.T, fn func(t *testing.T, client mg.Client), opts ...mg.ClientOpt) {
+	t.Helper()
+	t.Run("file", func(t *testing.T) {
+		fn(t, NewTestClient(t, opts...))
+	})
+	t.Run("dgraph", func(t *testing.T) {
+		addr := os.Getenv(AddrEnv)
+		if addr == "" && os.Getenv(DockerEnv) != "" {
+			addr = StartDgraph(t)
+		}
+		if addr == "" {
+			t.Skipf("neither %s nor %s set", AddrEnv, DockerEnv)
+		}
+		fn(t, NewTestClientURI(t, "dgraph://"+addr, opts...))
+	})
+}
+
+// TempDir returns a directory for the test's database that is removed when
+// the test ends. It is t.TempDir, except on Windows, where the engine's files
+// can outlive Close briefly and removal is retried after a pause rather than
+// failing the test.
+func TempDir(t testing.TB) string {
+	t.Helper()
+	if runtime.GOOS != "windows" {
+		return t.TempDir()
+	}
+	name := strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(t.Name())
+	dir := filepath.Join(os.TempDir(), "modusgraph_test_"+name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("modusgraphtest: creating %s: %v", dir, err)
+	}
+	t.Cleanup(func() {
+		runtime.GC()
+		time.Sleep(200 * time.Millisecond)
+		if err := os.RemoveAll(dir); err != nil {
+			t.Logf("modusgraphtest: removing %s: %v", dir, err)
+		}
+	})
+	return dir
+}
+
+// testLogger returns a stdout logger at the verbosity set by LogLevelEnv.
+func testLogger() logr.Logger {
+	level, _ := strconv.Atoi(os.Getenv(LogLevelEnv))
+	stdr.SetVerbosity(level)
+	return stdr.NewWithOptions(log.New(os.Stdout, "", log.LstdFlags),
+		stdr.Options{LogCaller: stdr.All}).WithName("mg")
+}
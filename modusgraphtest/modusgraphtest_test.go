@@ -0,0 +1,115 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraphtest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/modusgraphtest"
+)
+
+type HarnessDirector struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"harness_name,omitempty" dgraph:"index=exact"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type HarnessFilm struct {
+	UID       string           `json:"uid,omitempty"`
+	Title     string           `json:"harness_title,omitempty" dgraph:"index=exact upsert"`
+	Year      int              `json:"harness_year,omitempty"`
+	Director  *HarnessDirector `json:"harness_director,omitempty"`
+	UpdatedAt time.Time        `json:"harness_updated_at,omitempty"`
+	DType     []string         `json:"dgraph.type,omitempty"`
+}
+
+// recorder collects assertion failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestHarness(t *testing.T) {
+	clock := modusgraphtest.NewClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	stamp := mg.WithCompute("UpdatedAt", func(*HarnessFilm) time.Time { return clock.Now() })
+
+	modusgraphtest.ForEachBackend(t, func(t *testing.T, client mg.Client) {
+		ctx := context.Background()
+
+		films := modusgraphtest.LoadFixtures[HarnessFilm](t, client, "testdata/films.yaml")
+		if len(films) != 2 || films[0].UID == "" || films[0].Director.UID == "" {
+			t.Fatalf("fixtures not inserted: %+v", films)
+		}
+		directors := modusgraphtest.LoadFixtures[HarnessDirector](t, client, "testdata/director.json")
+		if len(directors) != 1 || directors[0].Name != "Denis Villeneuve" {
+			t.Fatalf("single-object fixture not loaded: %+v", directors)
+		}
+
+		modusgraphtest.AssertCount(t, client, HarnessFilm{}, 2)
+		modusgraphtest.AssertCount(t, client, &HarnessDirector{}, 2)
+		uid := modusgraphtest.AssertExists(t, client, HarnessFilm{}, "eq(harness_title, ?)", "Alien")
+		if uid != films[0].UID {
+			t.Fatalf("AssertExists returned %q, want %q", uid, films[0].UID)
+		}
+		modusgraphtest.AssertNotExists(t, client, HarnessFilm{}, "eq(harness_title, ?)", "Dune")
+		modusgraphtest.AssertEdge(t, client, films[0].UID, "harness_director", films[0].Director.UID)
+		modusgraphtest.AssertNoEdge(t, client, films[1].UID, "harness_director", films[0].Director.UID)
+
+		var got HarnessFilm
+		if err := client.Get(ctx, &got, films[0].UID); err != nil {
+			t.Fatal(err)
+		}
+		if !got.UpdatedAt.Equal(clock.Now()) {
+			t.Fatalf("UpdatedAt = %v, want %v", got.UpdatedAt, clock.Now())
+		}
+		later := clock.Advance(time.Hour)
+		if err := client.Update(ctx, &got); err != nil {
+			t.Fatal(err)
+		}
+		got = HarnessFilm{}
+		if err := client.Get(ctx, &got, films[0].UID); err != nil {
+			t.Fatal(err)
+		}
+		if !got.UpdatedAt.Equal(later) {
+			t.Fatalf("UpdatedAt = %v after Advance, want %v", got.UpdatedAt, later)
+		}
+
+		// The assertions report mismatches rather than passing them.
+		probe := &recorder{TB: t}
+		modusgraphtest.AssertCount(probe, client, HarnessFilm{}, 3)
+		modusgraphtest.AssertNotExists(probe, client, HarnessFilm{}, "eq(harness_title, ?)", "Alien")
+		modusgraphtest.AssertEdge(probe, client, films[1].UID, "harness_director", films[0].Director.UID)
+		if len(probe.errors) != 3 {
+			t.Fatalf("got %d assertion failures, want 3: %q", len(probe.errors), probe.errors)
+		}
+	}, stamp)
+}
+
+func TestReadFixturesUnsupportedFormat(t *testing.T) {
+	if _, err := modusgraphtest.ReadFixtures[HarnessFilm]("testdata/films.csv"); err == nil {
+		t.Fatal("expected an error for a .csv fixture")
+	}
+}
+
+func TestClock(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := modusgraphtest.NewClock(start)
+	if got := clock.Advance(90 * time.Second); !got.Equal(start.Add(90 * time.Second)) {
+		t.Fatalf("Advance = %v", got)
+	}
+	clock.Set(start)
+	if !clock.Now().Equal(start) {
+		t.Fatalf("Now = %v after Set, want %v", clock.Now(), start)
+	}
+}
@@ -0,0 +1,247 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraphtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+)
+
+// update is the -update flag. With it set, AssertGolden and AssertSnapshot
+// rewrite their golden files instead of comparing against them:
+//
+//	go test ./... -update
+//
+// A test package that imports modusgraphtest must not define its own -update
+// flag.
+var update = flag.Bool("update", false, "rewrite modusgraphtest golden files with the current results")
+
+// Snapshot returns a deterministic JSON rendering of every node of the given
+// model types, for comparing graph state against a golden file. Nodes are
+// grouped by type and sorted by the first predicate their type tags
+// dgraph:"unique" or dgraph:"upsert", or by their scalar values when it tags
+// none. UIDs are replaced by aliases such as "Film#2", the node's type and
+// its position in that order; edges hold the aliases of their targets, and
+// targets outside the snapshot are aliased "ref#N" in order of appearance.
+// Scalar lists are sorted, since Dgraph does not preserve their order.
+func Snapshot(t testing.TB, client mg.Client, models ...any) []byte {
+	t.Helper()
+	out, err := snapshot(context.Background(), client, models)
+	if err != nil {
+		t.Fatalf("modusgraphtest: snapshot: %v", err)
+	}
+	return out
+}
+
+// AssertSnapshot compares the Snapshot of models with the golden file at
+// path, which -update rewrites.
+func AssertSnapshot(t testing.TB, client mg.Client, path string, models ...any) {
+	t.Helper()
+	AssertGolden(t, Snapshot(t, client, models...), path)
+}
+
+// AssertGolden compares got with the contents of the golden file at path. With
+// -update it writes got to path, creating its directory, instead.
+func AssertGolden(t testing.TB, got []byte, path string) {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("modusgraphtest: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("modusgraphtest: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("modusgraphtest: golden file %s does not exist; run the test with -update to create it", path)
+	}
+	if err != nil {
+		t.Fatalf("modusgraphtest: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("modusgraphtest: result differs from golden file %s (rerun with -update to accept it)\n%s",
+			path, lineDiff(want, got))
+	}
+}
+
+// snapshotType is one model type being snapshotted.
+type snapshotType struct {
+	name  string
+	key   string // sort predicate; "" sorts by scalar values
+	nodes []map[string]any
+}
+
+func snapshot(ctx context.Context, client mg.Client, models []any) ([]byte, error) {
+	types := make([]*snapshotType, 0, len(models))
+	aliases := map[string]string{}
+	for _, model := range models {
+		st := &snapshotType{name: typeName(model), key: uniqueKey(model)}
+		q := fmt.Sprintf(`{ q(func: type(%s)) { uid expand(_all_) { uid } } }`, st.name)
+		resp, err := client.QueryRaw(ctx, q, nil)
+		if err != nil {
+			return nil, fmt.Errorf("querying %s: %w", st.name, err)
+		}
+		var decoded struct {
+			Q []map[string]any `json:"q"`
+		}
+		dec := json.NewDecoder(bytes.NewReader(resp))
+		dec.UseNumber()
+		if err := dec.Decode(&decoded); err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", st.name, err)
+		}
+		st.nodes = decoded.Q
+		slices.SortStableFunc(st.nodes, func(a, b map[string]any) int {
+			if st.key != "" {
+				if c := strings.Compare(canonical(a[st.key]), canonical(b[st.key])); c != 0 {
+					return c
+				}
+			}
+			return strings.Compare(canonical(scalars(a)), canonical(scalars(b)))
+		})
+		for i, n := range st.nodes {
+			if uid, ok := n["uid"].(string); ok {
+				aliases[uid] = fmt.Sprintf("%s#%d", st.name, i+1)
+			}
+		}
+		types = append(types, st)
+	}
+
+	refs := 0
+	alias := func(uid string) string {
+		if a, ok := aliases[uid]; ok {
+			return a
+		}
+		refs++
+		aliases[uid] = fmt.Sprintf("ref#%d", refs)
+		return aliases[uid]
+	}
+	out := map[string][]map[string]any{}
+	for _, st := range types {
+		nodes := make([]map[string]any, 0, len(st.nodes))
+		for _, n := range st.nodes {
+			// Visit predicates in order so ref aliases are numbered stably.
+			for _, pred := range slices.Sorted(maps.Keys(n)) {
+				if pred == "uid" {
+					n[pred] = alias(n[pred].(string))
+					continue
+				}
+				n[pred] = normalizeValue(n[pred], alias)
+			}
+			nodes = append(nodes, n)
+		}
+		out[st.name] = nodes
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// normalizeValue replaces edge targets with their aliases and sorts lists.
+func normalizeValue(v any, alias func(string) string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if uid, ok := v["uid"].(string); ok {
+			return alias(uid)
+		}
+		return v
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = normalizeValue(e, alias)
+		}
+		slices.SortFunc(out, func(a, b any) int {
+			return strings.Compare(canonical(a), canonical(b))
+		})
+		return out
+	}
+	return v
+}
+
+// scalars returns n without its uid and edges, whose UIDs are not stable.
+func scalars(n map[string]any) map[string]any {
+	out := make(map[string]any, len(n))
+	for pred, v := range n {
+		if pred != "uid" && !isEdge(v) {
+			out[pred] = v
+		}
+	}
+	return out
+}
+
+func isEdge(v any) bool {
+	switch v := v.(type) {
+	case map[string]any:
+		_, ok := v["uid"]
+		return ok
+	case []any:
+		return len(v) > 0 && isEdge(v[0])
+	}
+	return false
+}
+
+// canonical renders v as JSON with sorted keys, for ordering.
+func canonical(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// uniqueKey returns the predicate of the first field of model's type tagged
+// dgraph:"unique" or dgraph:"upsert", or "".
+func uniqueKey(model any) string {
+	typ := reflect.Indirect(reflect.ValueOf(mg.UnwrapSchema(model))).Type()
+	if typ.Kind() != reflect.Struct {
+		return ""
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		opts := strings.Fields(sf.Tag.Get("dgraph"))
+		if !slices.Contains(opts, "unique") && !slices.Contains(opts, "upsert") {
+			continue
+		}
+		if pred, _, _ := strings.Cut(sf.Tag.Get("json"), ","); pred != "" && pred != "-" {
+			return pred
+		}
+	}
+	return ""
+}
+
+// lineDiff describes where want and got first differ, with a few lines of
+// each from that point.
+func lineDiff(want, got []byte) string {
+	w := strings.Split(string(want), "\n")
+	g := strings.Split(string(got), "\n")
+	i := 0
+	for i < len(w) && i < len(g) && w[i] == g[i] {
+		i++
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "first difference at line %d:\n", i+1)
+	for j := i; j < min(i+5, len(w)); j++ {
+		fmt.Fprintf(&b, "- %s\n", w[j])
+	}
+	for j := i; j < min(i+5, len(g)); j++ {
+		fmt.Fprintf(&b, "+ %s\n", g[j])
+	}
+	return b.String()
+}
@@ -0,0 +1,47 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraphtest_test
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/modusgraphtest"
+)
+
+func TestSnapshot(t *testing.T) {
+	modusgraphtest.ForEachBackend(t, func(t *testing.T, client mg.Client) {
+		// Insert in an order unlike the snapshot's, which must not matter.
+		more := modusgraphtest.LoadFixtures[HarnessFilm](t, client, "testdata/more_films.json")
+		films := modusgraphtest.LoadFixtures[HarnessFilm](t, client, "testdata/films.yaml")
+		more[1].Director = films[0].Director
+		if err := client.Update(context.Background(), more[1]); err != nil {
+			t.Fatal(err)
+		}
+
+		modusgraphtest.AssertSnapshot(t, client, "testdata/golden/films.json", HarnessFilm{}, HarnessDirector{})
+
+		// Directors are aliased as edge targets even when not snapshotted.
+		modusgraphtest.AssertSnapshot(t, client, "testdata/golden/films_only.json", HarnessFilm{})
+
+		if flag.Lookup("update").Value.String() == "true" {
+			return
+		}
+		golden := filepath.Join(t.TempDir(), "golden.json")
+		if err := os.WriteFile(golden, []byte("{}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		probe := &recorder{TB: t}
+		modusgraphtest.AssertSnapshot(probe, client, golden, HarnessFilm{})
+		if len(probe.errors) != 1 {
+			t.Fatalf("got %d golden mismatches, want 1", len(probe.errors))
+		}
+	})
+}
@@ -60,15 +60,68 @@ func (c client) process(ctx context.Context,
 	obj any, operation string,
 	txFunc func(*dg.TxnContext, any) ([]string, error)) error {
 
+	if err := c.prepare(ctx, obj); err != nil {
+		return err
+	}
+
+	client, err := c.pool.get()
+	if err != nil {
+		c.log(ctx).Error(err, "Failed to get client from pool")
+		return err
+	}
+	defer c.pool.put(client)
+
+	hasEmbedding := c.options.embeddingProvider != nil && hasSimStringFields(obj)
+	deferCommit := hasEmbedding || hasLocalizedFields(obj) || hasNullableFields(obj) ||
+		hasZeroPredicates(obj, c.options.includeZero)
+
+	var tx *dg.TxnContext
+	if deferCommit {
+		// Do not use SetCommitNow: we need to inject shadow vectors,
+		// language variants, zero values and cleared predicates before
+		// committing.
+		tx = dg.NewTxnContext(ctx, client)
+		// Discard is a no-op after a successful Commit but ensures resources are
+		// cleaned up on all paths (error returns, panics, etc.).
+		defer func() { _ = tx.Txn().Discard(ctx) }()
+	} else {
+		tx = dg.NewTxnContext(ctx, client).SetCommitNow()
+	}
+
+	uids, err := c.mutateIn(ctx, tx, obj, txFunc)
+	if err != nil {
+		return err
+	}
+	if deferCommit {
+		if err := tx.Txn().Commit(ctx); err != nil {
+			return fmt.Errorf("committing transaction: %w", err)
+		}
+	}
+
+	c.log(ctx).V(2).Info(operation+" successful", "uidCount", len(uids))
+	return nil
+}
+
+// prepare readies the schema for obj, according to the client's schema mode,
+// and computes the embeddings of its fields.
+func (c client) prepare(ctx context.Context, obj any) error {
 	schemaObj, err := checkObject(obj)
 	if err != nil {
 		return err
 	}
+	if err := checkUIDFields(schemaObj); err != nil {
+		return err
+	}
+	c.policies.register(schemaObj)
 	if c.options.autoSchema {
 		err := c.UpdateSchema(ctx, schemaObj)
 		if err != nil {
 			return err
 		}
+	} else if c.options.schemaMode != SchemaManual {
+		if err := c.reconcileSchema(ctx, schemaObj); err != nil {
+			return err
+		}
 	} else {
 		// When AutoSchema is disabled, check schema consistency
 		currentSchema, err := c.GetSchema(ctx)
@@ -85,52 +138,51 @@ func (c client) process(ctx context.Context,
 
 		// When AutoSchema is disabled, validate that required schema exists
 		// Fail if user schema for the type doesn't exist, even if only system schema exists
-		if typeName != "" && !strings.Contains(currentSchema, "type "+typeName) {
+		if _, ok := currentSchema.Type(typeName); typeName != "" && !ok {
 			return fmt.Errorf("schema validation failed: database schema does not contain type %s", typeName)
 		}
 	}
 
-	client, err := c.pool.get()
-	if err != nil {
-		c.logger.Error(err, "Failed to get client from pool")
-		return err
-	}
-	defer c.pool.put(client)
-
-	provider := c.options.embeddingProvider
-	hasEmbedding := provider != nil && hasSimStringFields(obj)
-
-	var tx *dg.TxnContext
-	if hasEmbedding {
-		// Do not use SetCommitNow: we need to inject shadow vectors before committing.
-		tx = dg.NewTxnContext(ctx, client)
-		// Discard is a no-op after a successful Commit but ensures resources are
-		// cleaned up on all paths (error returns, panics, etc.).
-		defer func() { _ = tx.Txn().Discard(ctx) }()
-	} else {
-		tx = dg.NewTxnContext(ctx, client).SetCommitNow()
+	if embedder := c.options.vectorEmbedder(); embedder != nil {
+		if err := applyEmbeddings(ctx, embedder, obj); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
+// mutateIn writes obj in tx with txFunc, then adds the mutations that write
+// its language variants, zero values, cleared predicates and shadow vectors.
+// It does not commit tx.
+func (c client) mutateIn(ctx context.Context, tx *dg.TxnContext, obj any,
+	txFunc func(*dg.TxnContext, any) ([]string, error)) ([]string, error) {
 	uids, err := txFunc(tx, obj)
 	if err != nil {
-		// Check if this is a unique constraint violation error from Dgraph
-		if uniqueErr := parseUniqueError(err); uniqueErr != nil {
-			return uniqueErr
-		}
-		return err
+		// Surface unique constraint violations from Dgraph as ErrUniqueConflict
+		return nil, uniqueConflict(err)
 	}
 
-	if hasEmbedding {
-		if err := injectShadowVectors(ctx, provider, tx, obj, uids); err != nil {
-			return fmt.Errorf("injecting shadow vectors: %w", err)
+	if hasLocalizedFields(obj) {
+		if err := injectLocalized(ctx, tx, obj); err != nil {
+			return nil, fmt.Errorf("injecting language variants: %w", err)
 		}
-		if err := tx.Txn().Commit(ctx); err != nil {
-			return fmt.Errorf("committing transaction with shadow vectors: %w", err)
+	}
+	if hasZeroPredicates(obj, c.options.includeZero) {
+		if err := injectZeros(ctx, tx, obj, c.options.includeZero); err != nil {
+			return nil, fmt.Errorf("writing zero values: %w", err)
 		}
 	}
-
-	c.logger.V(2).Info(operation+" successful", "uidCount", len(uids))
-	return nil
+	if hasNullableFields(obj) {
+		if err := injectNulls(ctx, tx, obj); err != nil {
+			return nil, fmt.Errorf("clearing null predicates: %w", err)
+		}
+	}
+	if provider := c.options.embeddingProvider; provider != nil && hasSimStringFields(obj) {
+		if err := injectShadowVectors(ctx, provider, tx, obj, uids); err != nil {
+			return nil, fmt.Errorf("injecting shadow vectors: %w", err)
+		}
+	}
+	return uids, nil
 }
 
 func generateUniquePredicateQuery(predicates map[string]interface{}, nodeType string) (string, map[string]string) {
@@ -0,0 +1,116 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/dgraph-io/dgraph/v25/chunker"
+)
+
+// MutateRDF writes N-Quads in RDF syntax as one transaction, for migration
+// scripts and writes the struct-based methods cannot express:
+//
+//	uids, applied, err := client.MutateRDF(ctx, `
+//	    _:film <name> "Metropolis" .
+//	    _:film <dgraph.type> "Film" .
+//	    _:film <directed_by> <0x2a> .
+//	    <0x2a> <name> "Fritz Lang"@de .
+//	`, "")
+//
+// Subjects and objects are UIDs (<0x2a>) or blank nodes (_:film), which are
+// assigned new UIDs; uids maps each blank node's name, without the _:
+// prefix, to its UID. Values take the usual language tags, type hints and
+// facets.
+//
+// When cond is not empty it is a DQL filter expression, such as
+// "NOT has(name)", that every existing node written as a subject must
+// satisfy. If one does not, nothing is written and applied is false. Blank
+// nodes are new, so they are not checked.
+func (c client) MutateRDF(ctx context.Context, nquads string,
+	cond string) (uids map[string]string, applied bool, err error) {
+	if err := c.writable(); err != nil {
+		return nil, false, err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	nqs, _, err := chunker.ParseRDFs([]byte(nquads))
+	if err != nil {
+		return nil, false, fmt.Errorf("MutateRDF: parsing N-Quads: %w", err)
+	}
+	if len(nqs) == 0 {
+		return nil, false, errors.New("MutateRDF: no N-Quads to write")
+	}
+	var subjects []string
+	seen := make(map[string]bool)
+	for _, nq := range nqs {
+		for _, node := range []string{nq.Subject, nq.ObjectId} {
+			if strings.HasPrefix(node, "uid(") || strings.HasPrefix(node, "val(") {
+				return nil, false, fmt.Errorf("MutateRDF: %s needs an upsert query; write a UID or blank node", node)
+			}
+		}
+		if !strings.HasPrefix(nq.Subject, "_:") && !seen[nq.Subject] {
+			seen[nq.Subject] = true
+			subjects = append(subjects, nq.Subject)
+		}
+	}
+
+	// The embedded engine does no commit-time conflict check, so serialize
+	// the check and write as UpsertIf does.
+	if cond != "" && c.engine != nil && c.consumeMu != nil {
+		c.consumeMu.Lock()
+		defer c.consumeMu.Unlock()
+	}
+
+	dgo, cleanup, err := c.DgraphClient()
+	if err != nil {
+		c.log(ctx).Error(err, "Failed to get client from pool")
+		return nil, false, err
+	}
+	defer cleanup()
+
+	err = c.retry(ctx, c.options.upsertRetry, isAbortedErr, func() error {
+		txn := dgo.NewTxn()
+		defer func() { _ = txn.Discard(ctx) }()
+		applied = false
+		if cond != "" && len(subjects) > 0 {
+			resp, err := txn.Query(ctx, fmt.Sprintf("{ c(func: uid(%s)) @filter(%s) { uid } }",
+				strings.Join(subjects, ", "), cond))
+			if err != nil {
+				return fmt.Errorf("MutateRDF: evaluating condition: %w", err)
+			}
+			var found struct {
+				C []struct{ UID string } `json:"c"`
+			}
+			if err := json.Unmarshal(resp.Json, &found); err != nil {
+				return err
+			}
+			if len(found.C) < len(subjects) {
+				return nil
+			}
+		}
+		resp, err := txn.Mutate(ctx, &api.Mutation{Set: nqs, CommitNow: true})
+		if err != nil {
+			return err
+		}
+		uids = make(map[string]string, len(resp.Uids))
+		for name, uid := range resp.Uids {
+			uids[strings.TrimPrefix(name, "_:")] = uid
+		}
+		applied = true
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return uids, applied, nil
+}
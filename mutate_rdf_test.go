@@ -0,0 +1,110 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type RDFFilm struct {
+	UID      string       `json:"uid,omitempty"`
+	Title    string       `json:"rdf_title,omitempty" dgraph:"index=exact"`
+	Year     int          `json:"rdf_year,omitempty"`
+	Director *RDFDirector `json:"rdf_director,omitempty"`
+	DType    []string     `json:"dgraph.type,omitempty"`
+}
+
+type RDFDirector struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"rdf_name,omitempty" dgraph:"index=exact"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+func TestMutateRDF(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "MutateRDFWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "MutateRDFWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			ctx := context.Background()
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			require.NoError(t, client.UpdateSchema(ctx, &RDFFilm{}, &RDFDirector{}))
+
+			// Blank nodes get new UIDs, returned by name, and edges between
+			// them resolve within the write.
+			uids, applied, err := client.MutateRDF(ctx, `
+				_:film <rdf_title> "Metropolis" .
+				_:film <rdf_year> "1927"^^<xs:int> .
+				_:film <dgraph.type> "RDFFilm" .
+				_:film <rdf_director> _:lang .
+				_:lang <rdf_name> "Fritz Lang" .
+				_:lang <dgraph.type> "RDFDirector" .
+			`, "")
+			require.NoError(t, err)
+			require.True(t, applied)
+			require.Len(t, uids, 2)
+			require.NotEmpty(t, uids["film"])
+
+			var film RDFFilm
+			require.NoError(t, client.Get(ctx, &film, uids["film"]))
+			require.Equal(t, "Metropolis", film.Title)
+			require.Equal(t, 1927, film.Year)
+			require.NotNil(t, film.Director)
+			require.Equal(t, uids["lang"], film.Director.UID)
+			require.Equal(t, "Fritz Lang", film.Director.Name)
+
+			// A condition the subject fails writes nothing.
+			_, applied, err = client.MutateRDF(ctx,
+				"<"+uids["film"]+`> <rdf_title> "Metropolis (1927)" .`, "NOT has(rdf_title)")
+			require.NoError(t, err)
+			require.False(t, applied)
+			require.NoError(t, client.Get(ctx, &film, uids["film"]))
+			require.Equal(t, "Metropolis", film.Title)
+
+			// One it satisfies writes the N-Quads.
+			_, applied, err = client.MutateRDF(ctx,
+				"<"+uids["film"]+`> <rdf_title> "Metropolis (1927)" .`, `eq(rdf_title, "Metropolis")`)
+			require.NoError(t, err)
+			require.True(t, applied)
+			require.NoError(t, client.Get(ctx, &film, uids["film"]))
+			require.Equal(t, "Metropolis (1927)", film.Title)
+
+			_, _, err = client.MutateRDF(ctx, `_:a <rdf_title> "unterminated .`, "")
+			require.Error(t, err)
+			_, _, err = client.MutateRDF(ctx, `uid(v) <rdf_title> "x" .`, "")
+			require.ErrorContains(t, err, "upsert query")
+
+			ro, err := client.With(mg.WithReadOnly())
+			require.NoError(t, err)
+			_, _, err = ro.MutateRDF(ctx, `_:a <rdf_title> "x" .`, "")
+			require.ErrorIs(t, err, mg.ErrReadOnly)
+		})
+	}
+}
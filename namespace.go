@@ -7,8 +7,11 @@ package modusgraph
 
 import (
 	"context"
+	"sort"
 
 	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/dgraph-io/dgraph/v25/schema"
+	"github.com/dgraph-io/dgraph/v25/x"
 )
 
 // Namespace is one of the namespaces in modusDB.
@@ -48,3 +51,16 @@ func (ns *Namespace) Query(ctx context.Context, query string) (*api.Response, er
 func (ns *Namespace) QueryWithVars(ctx context.Context, query string, vars map[string]string) (*api.Response, error) {
 	return ns.engine.query(ctx, ns, query, vars)
 }
+
+// predicates returns the names of the predicates in the namespace's schema,
+// sorted.
+func (ns *Namespace) predicates() []string {
+	var names []string
+	for _, attr := range schema.State().Predicates() {
+		if nsID, name := x.ParseNamespaceAttr(attr); nsID == ns.id {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,116 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package neo4jimport
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+const (
+	boltNodesQuery = `MATCH (n) RETURN elementId(n) AS id, labels(n) AS labels, properties(n) AS props`
+	boltRelsQuery  = `MATCH (a)-[r]->(b) RETURN elementId(a) AS start, elementId(b) AS end, ` +
+		`type(r) AS type, properties(r) AS props`
+)
+
+// ReadBolt reads every node and relationship from a Neo4j 5 database over
+// Bolt. db names the database; empty uses the server's default. Create the
+// driver with neo4j.NewDriverWithContext; ReadBolt does not close it.
+//
+// Temporal values become time.Time (times of day and durations become their
+// string form), and spatial points become strings.
+func ReadBolt(ctx context.Context, driver neo4j.DriverWithContext, db string) (*Graph, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: db,
+		AccessMode:   neo4j.AccessModeRead,
+	})
+	defer session.Close(ctx)
+
+	g := &Graph{}
+	nodes, err := session.Run(ctx, boltNodesQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("neo4jimport: reading nodes: %w", err)
+	}
+	for nodes.Next(ctx) {
+		rec := nodes.Record()
+		id, _ := rec.Get("id")
+		labels, _ := rec.Get("labels")
+		props, _ := rec.Get("props")
+		n := Node{ID: fmt.Sprint(id), Properties: boltProperties(props)}
+		for _, l := range asList(labels) {
+			n.Labels = append(n.Labels, fmt.Sprint(l))
+		}
+		g.Nodes = append(g.Nodes, n)
+	}
+	if err := nodes.Err(); err != nil {
+		return nil, fmt.Errorf("neo4jimport: reading nodes: %w", err)
+	}
+
+	rels, err := session.Run(ctx, boltRelsQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("neo4jimport: reading relationships: %w", err)
+	}
+	for rels.Next(ctx) {
+		rec := rels.Record()
+		start, _ := rec.Get("start")
+		end, _ := rec.Get("end")
+		typ, _ := rec.Get("type")
+		props, _ := rec.Get("props")
+		g.Relationships = append(g.Relationships, Relationship{
+			Start:      fmt.Sprint(start),
+			End:        fmt.Sprint(end),
+			Type:       fmt.Sprint(typ),
+			Properties: boltProperties(props),
+		})
+	}
+	if err := rels.Err(); err != nil {
+		return nil, fmt.Errorf("neo4jimport: reading relationships: %w", err)
+	}
+	return g, nil
+}
+
+func asList(v any) []any {
+	l, _ := v.([]any)
+	return l
+}
+
+func boltProperties(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	out := make(map[string]any, len(m))
+	for k, val := range m {
+		if val != nil {
+			out[k] = boltValue(val)
+		}
+	}
+	return out
+}
+
+// boltValue normalizes a driver value to the types Graph documents.
+func boltValue(v any) any {
+	switch v := v.(type) {
+	case int64, float64, bool, string:
+		return v
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = boltValue(e)
+		}
+		return out
+	case time.Time:
+		return v
+	case dbtype.Date:
+		return v.Time()
+	case dbtype.LocalDateTime:
+		return v.Time()
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(v)
+}
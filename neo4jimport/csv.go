@@ -0,0 +1,138 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package neo4jimport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReadCSV reads a graph exported with apoc.export.csv.all, the single-file
+// format whose header is _id, _labels, one column per property name, then
+// _start, _end, and _type. Rows with an _id are nodes and rows with a _start
+// are relationships; an empty cell means the property is absent.
+//
+// APOC writes every value as text, so ReadCSV recovers types: integers,
+// floats, and booleans that round-trip exactly, JSON arrays, and ISO 8601
+// dates and datetimes. Anything else stays a string.
+func ReadCSV(r io.Reader) (*Graph, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("neo4jimport: reading CSV header: %w", err)
+	}
+	cols := map[string]int{}
+	for i, h := range header {
+		cols[h] = i
+	}
+	for _, required := range []string{"_id", "_labels", "_start", "_end", "_type"} {
+		if _, ok := cols[required]; !ok {
+			return nil, fmt.Errorf("neo4jimport: CSV header lacks %s; expected an apoc.export.csv.all export", required)
+		}
+	}
+
+	g := &Graph{}
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return g, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("neo4jimport: reading CSV: %w", err)
+		}
+		cell := func(name string) string {
+			if i := cols[name]; i < len(rec) {
+				return rec[i]
+			}
+			return ""
+		}
+		props := map[string]any{}
+		for i, h := range header {
+			if strings.HasPrefix(h, "_") || i >= len(rec) || rec[i] == "" {
+				continue
+			}
+			props[h] = parseCSVValue(rec[i])
+		}
+		switch {
+		case cell("_id") != "":
+			var labels []string
+			for l := range strings.SplitSeq(cell("_labels"), ":") {
+				if l != "" {
+					labels = append(labels, l)
+				}
+			}
+			g.Nodes = append(g.Nodes, Node{ID: cell("_id"), Labels: labels, Properties: props})
+		case cell("_start") != "":
+			if cell("_end") == "" || cell("_type") == "" {
+				return nil, fmt.Errorf("neo4jimport: CSV line %d: relationship lacks _end or _type", line)
+			}
+			g.Relationships = append(g.Relationships, Relationship{
+				Start: cell("_start"), End: cell("_end"), Type: cell("_type"), Properties: props,
+			})
+		default:
+			return nil, fmt.Errorf("neo4jimport: CSV line %d is neither a node nor a relationship", line)
+		}
+	}
+}
+
+// csvTimeLayouts are the temporal formats APOC emits, most specific first.
+var csvTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02"}
+
+func parseCSVValue(s string) any {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil && strconv.FormatInt(i, 10) == s {
+		return i
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && strconv.FormatFloat(f, 'f', -1, 64) == s {
+		return f
+	}
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if strings.HasPrefix(s, "[") {
+		dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+		dec.UseNumber()
+		var list []any
+		if err := dec.Decode(&list); err == nil {
+			for i, e := range list {
+				list[i] = jsonScalar(e)
+			}
+			return list
+		}
+	}
+	if len(s) >= len("2006-01-02") && s[4] == '-' {
+		for _, layout := range csvTimeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t
+			}
+		}
+	}
+	return s
+}
+
+func jsonScalar(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case string:
+		return parseCSVValue(v)
+	}
+	return v
+}
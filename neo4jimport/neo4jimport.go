@@ -0,0 +1,388 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package neo4jimport migrates a Neo4j graph into modusGraph.
+//
+// Read the graph with ReadCSV (an apoc.export.csv.all export) or ReadBolt (a
+// live database), then Import it:
+//
+//	g, err := neo4jimport.ReadCSV(f)
+//	res, err := neo4jimport.Import(ctx, client, g, neo4jimport.Options{})
+//	stubs, err := neo4jimport.Stubs(g, "models", neo4jimport.Options{})
+//
+// Labels become Dgraph types, properties become predicates, and relationships
+// become [uid] edges named after the relationship type, with @reverse so they
+// can be traversed from either end as in Neo4j. Relationship properties
+// become facets on the edge. Predicate types are inferred from the values.
+// Stubs renders matching Go structs for the typed and dgman APIs.
+package neo4jimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	mg "github.com/matthewmcneely/modusgraph"
+)
+
+// Node is a Neo4j node. ID is the Neo4j identifier (the export's _id or the
+// node's elementId); it is used to resolve relationships and is not stored
+// unless Options.IDPredicate is set.
+type Node struct {
+	ID         string
+	Labels     []string
+	Properties map[string]any
+}
+
+// Relationship is a directed Neo4j relationship between two node IDs.
+type Relationship struct {
+	Start      string
+	End        string
+	Type       string
+	Properties map[string]any
+}
+
+// Graph is a Neo4j graph read into memory. Property values are string,
+// int64, float64, bool, time.Time, or []any of those.
+type Graph struct {
+	Nodes         []Node
+	Relationships []Relationship
+}
+
+// Options configures Import.
+//
+// BatchSize is how many nodes or relationships are written per transaction;
+// it defaults to 1000. IDPredicate, when set, stores each node's Neo4j ID
+// under that predicate so the migration can be audited or re-linked later.
+type Options struct {
+	BatchSize   int
+	IDPredicate string
+}
+
+// Result reports what Import wrote. UIDs maps each Neo4j node ID to the UID
+// it was assigned.
+type Result struct {
+	Nodes         int
+	Relationships int
+	UIDs          map[string]string
+}
+
+const defaultBatchSize = 1000
+
+// Import applies the schema inferred from g and writes its nodes and then
+// its relationships. Each batch commits on its own, so a failed import can
+// leave a partial graph behind; import into an empty database and DropAll to
+// retry.
+func Import(ctx context.Context, client mg.Client, g *Graph, opts Options) (*Result, error) {
+	batch := opts.BatchSize
+	if batch <= 0 {
+		batch = defaultBatchSize
+	}
+	s := inferSchema(g, opts.IDPredicate)
+	if err := client.AlterSchema(ctx, s.dql()); err != nil {
+		return nil, fmt.Errorf("neo4jimport: applying schema: %w", err)
+	}
+
+	dgo, cleanup, err := client.DgraphClient()
+	defer cleanup()
+	if err != nil {
+		return nil, err
+	}
+	mutate := func(objs []map[string]any) (map[string]string, error) {
+		body, err := json.Marshal(objs)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := dgo.NewTxn().Mutate(ctx, &api.Mutation{SetJson: body, CommitNow: true})
+		if err != nil {
+			return nil, err
+		}
+		return resp.Uids, nil
+	}
+
+	res := &Result{UIDs: make(map[string]string, len(g.Nodes))}
+	for start := 0; start < len(g.Nodes); start += batch {
+		nodes := g.Nodes[start:min(start+batch, len(g.Nodes))]
+		objs := make([]map[string]any, len(nodes))
+		for i, n := range nodes {
+			obj := map[string]any{"uid": fmt.Sprintf("_:n%d", i)}
+			if len(n.Labels) > 0 {
+				obj["dgraph.type"] = n.Labels
+			}
+			for k, v := range n.Properties {
+				obj[predicateName(k)] = s.preds[predicateName(k)].convert(v)
+			}
+			if opts.IDPredicate != "" {
+				obj[opts.IDPredicate] = n.ID
+			}
+			objs[i] = obj
+		}
+		uids, err := mutate(objs)
+		if err != nil {
+			return res, fmt.Errorf("neo4jimport: writing nodes: %w", err)
+		}
+		for i, n := range nodes {
+			res.UIDs[n.ID] = uids[fmt.Sprintf("n%d", i)]
+		}
+		res.Nodes += len(nodes)
+	}
+
+	for start := 0; start < len(g.Relationships); start += batch {
+		rels := g.Relationships[start:min(start+batch, len(g.Relationships))]
+		objs := make([]map[string]any, len(rels))
+		for i, r := range rels {
+			from, ok := res.UIDs[r.Start]
+			if !ok {
+				return res, fmt.Errorf("neo4jimport: relationship %s references unknown node %s", r.Type, r.Start)
+			}
+			to, ok := res.UIDs[r.End]
+			if !ok {
+				return res, fmt.Errorf("neo4jimport: relationship %s references unknown node %s", r.Type, r.End)
+			}
+			target := map[string]any{"uid": to}
+			for k, v := range r.Properties {
+				target[r.Type+"|"+k] = facetValue(v)
+			}
+			objs[i] = map[string]any{"uid": from, r.Type: []any{target}}
+		}
+		if _, err := mutate(objs); err != nil {
+			return res, fmt.Errorf("neo4jimport: writing relationships: %w", err)
+		}
+		res.Relationships += len(rels)
+	}
+	return res, nil
+}
+
+// predicateName maps a property name to its predicate, renaming the one
+// property name Dgraph reserves.
+func predicateName(prop string) string {
+	if prop == "uid" {
+		return "uid_"
+	}
+	return prop
+}
+
+// facetValue converts a relationship property to a facet value. Facets hold
+// scalars only, so lists are stored as their JSON encoding.
+func facetValue(v any) any {
+	switch v := v.(type) {
+	case []any:
+		b, _ := json.Marshal(v)
+		return string(b)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return v
+}
+
+// kind is an inferred predicate type.
+type kind int
+
+const (
+	kindNone kind = iota
+	kindInt
+	kindFloat
+	kindBool
+	kindDateTime
+	kindString
+)
+
+// predType is a predicate's inferred type: a scalar kind, optionally a list.
+type predType struct {
+	kind kind
+	list bool
+}
+
+func valueType(v any) predType {
+	switch v := v.(type) {
+	case []any:
+		t := predType{list: true}
+		for _, e := range v {
+			t.kind = mergeKind(t.kind, valueType(e).kind)
+		}
+		if t.kind == kindNone {
+			t.kind = kindString
+		}
+		return t
+	case int64:
+		return predType{kind: kindInt}
+	case float64:
+		return predType{kind: kindFloat}
+	case bool:
+		return predType{kind: kindBool}
+	case time.Time:
+		return predType{kind: kindDateTime}
+	}
+	return predType{kind: kindString}
+}
+
+func mergeKind(a, b kind) kind {
+	switch {
+	case a == kindNone:
+		return b
+	case b == kindNone || a == b:
+		return a
+	case a == kindInt && b == kindFloat, a == kindFloat && b == kindInt:
+		return kindFloat
+	}
+	return kindString
+}
+
+func (t predType) merge(o predType) predType {
+	return predType{kind: mergeKind(t.kind, o.kind), list: t.list || o.list}
+}
+
+func (t predType) dql() string {
+	s := [...]string{
+		kindInt: "int", kindFloat: "float", kindBool: "bool", kindDateTime: "datetime", kindString: "string",
+	}[t.kind]
+	if t.list {
+		return "[" + s + "]"
+	}
+	return s
+}
+
+// convert coerces a value to the predicate's inferred type.
+func (t predType) convert(v any) any {
+	if l, ok := v.([]any); ok {
+		out := make([]any, len(l))
+		for i, e := range l {
+			out[i] = predType{kind: t.kind}.convert(e)
+		}
+		return out
+	}
+	if t.list {
+		return []any{predType{kind: t.kind}.convert(v)}
+	}
+	switch t.kind {
+	case kindFloat:
+		if i, ok := v.(int64); ok {
+			return float64(i)
+		}
+	case kindDateTime:
+		if tm, ok := v.(time.Time); ok {
+			return tm.Format(time.RFC3339Nano)
+		}
+	case kindString:
+		switch v := v.(type) {
+		case string:
+			return v
+		case time.Time:
+			return v.Format(time.RFC3339Nano)
+		}
+		return fmt.Sprint(v)
+	}
+	return v
+}
+
+// schema is the Dgraph schema inferred from a Graph.
+type schema struct {
+	preds map[string]predType
+	edges map[string]bool
+	// types maps each label to its predicates and edges.
+	types map[string]map[string]bool
+	// targets maps label -> edge -> the most common label at the far end.
+	targets map[string]map[string]string
+}
+
+func inferSchema(g *Graph, idPredicate string) *schema {
+	s := &schema{
+		preds:   map[string]predType{},
+		edges:   map[string]bool{},
+		types:   map[string]map[string]bool{},
+		targets: map[string]map[string]string{},
+	}
+	labels := make(map[string][]string, len(g.Nodes))
+	addField := func(label, field string) {
+		if s.types[label] == nil {
+			s.types[label] = map[string]bool{}
+		}
+		s.types[label][field] = true
+	}
+	for _, n := range g.Nodes {
+		labels[n.ID] = n.Labels
+		for k, v := range n.Properties {
+			p := predicateName(k)
+			s.preds[p] = s.preds[p].merge(valueType(v))
+			for _, l := range n.Labels {
+				addField(l, p)
+			}
+		}
+		if idPredicate != "" {
+			s.preds[idPredicate] = predType{kind: kindString}
+			for _, l := range n.Labels {
+				addField(l, idPredicate)
+			}
+		}
+		for _, l := range n.Labels {
+			if s.types[l] == nil {
+				s.types[l] = map[string]bool{}
+			}
+		}
+	}
+	counts := map[[3]string]int{}
+	for _, r := range g.Relationships {
+		s.edges[r.Type] = true
+		for _, l := range labels[r.Start] {
+			addField(l, r.Type)
+			if len(labels[r.End]) > 0 {
+				counts[[3]string{l, r.Type, labels[r.End][0]}]++
+			}
+		}
+	}
+	keys := make([][3]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return strings.Join(keys[i][:], "\x00") < strings.Join(keys[j][:], "\x00") })
+	best := map[[2]string]int{}
+	for _, k := range keys {
+		edge := [2]string{k[0], k[1]}
+		if counts[k] > best[edge] {
+			best[edge] = counts[k]
+			if s.targets[k[0]] == nil {
+				s.targets[k[0]] = map[string]string{}
+			}
+			s.targets[k[0]][k[1]] = k[2]
+		}
+	}
+	return s
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// dql renders the schema in Dgraph Schema Definition Language.
+func (s *schema) dql() string {
+	var b strings.Builder
+	for _, p := range sortedKeys(s.preds) {
+		if s.edges[p] {
+			continue // an edge wins over a same-named property
+		}
+		fmt.Fprintf(&b, "<%s>: %s .\n", p, s.preds[p].dql())
+	}
+	for _, e := range sortedKeys(s.edges) {
+		fmt.Fprintf(&b, "<%s>: [uid] @reverse .\n", e)
+	}
+	for _, t := range sortedKeys(s.types) {
+		fmt.Fprintf(&b, "type <%s> {\n", t)
+		for _, f := range sortedKeys(s.types[t]) {
+			fmt.Fprintf(&b, "  <%s>\n", f)
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,144 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package neo4jimport_test
+
+import (
+	"context"
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/neo4jimport"
+)
+
+// moviesCSV is a small apoc.export.csv.all export of the Neo4j movies graph.
+const moviesCSV = `"_id","_labels","born","name","released","tagline","title","_start","_end","_type","roles"
+"0",":Movie","","","1999","Welcome to the Real World","The Matrix",,,,
+"1",":Person","1964","Keanu Reeves","","","",,,,
+"2",":Person","1967","Carrie-Anne Moss","","","",,,,
+"3",":Person:Director","1965","Lana Wachowski","","","",,,,
+,,,,,,,"1","0","ACTED_IN","[""Neo""]"
+,,,,,,,"2","0","ACTED_IN","[""Trinity""]"
+,,,,,,,"3","0","DIRECTED",""
+`
+
+func TestReadCSV(t *testing.T) {
+	g, err := neo4jimport.ReadCSV(strings.NewReader(moviesCSV))
+	if err != nil {
+		t.Fatalf("ReadCSV: %v", err)
+	}
+	if len(g.Nodes) != 4 || len(g.Relationships) != 3 {
+		t.Fatalf("got %d nodes and %d relationships, want 4 and 3", len(g.Nodes), len(g.Relationships))
+	}
+	lana := g.Nodes[3]
+	if strings.Join(lana.Labels, ",") != "Person,Director" {
+		t.Errorf("labels = %v", lana.Labels)
+	}
+	if born, ok := lana.Properties["born"].(int64); !ok || born != 1965 {
+		t.Errorf("born = %#v, want int64 1965", lana.Properties["born"])
+	}
+	if _, ok := lana.Properties["title"]; ok {
+		t.Errorf("empty cells must not become properties")
+	}
+	if roles, ok := g.Relationships[0].Properties["roles"].([]any); !ok || len(roles) != 1 || roles[0] != "Neo" {
+		t.Errorf("roles = %#v", g.Relationships[0].Properties["roles"])
+	}
+}
+
+func TestImport(t *testing.T) {
+	conn, err := modusgraph.NewClient("file://" + t.TempDir())
+	if err != nil {
+		t.Fatalf("modusgraph.NewClient: %v", err)
+	}
+	t.Cleanup(conn.Close)
+
+	g, err := neo4jimport.ReadCSV(strings.NewReader(moviesCSV))
+	if err != nil {
+		t.Fatalf("ReadCSV: %v", err)
+	}
+	ctx := context.Background()
+	res, err := neo4jimport.Import(ctx, conn, g, neo4jimport.Options{BatchSize: 2, IDPredicate: "neo4j_id"})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if res.Nodes != 4 || res.Relationships != 3 || len(res.UIDs) != 4 {
+		t.Fatalf("result = %+v", res)
+	}
+
+	raw, err := conn.QueryRaw(ctx, `{
+		movies(func: type(Movie)) {
+			title released neo4j_id
+			~ACTED_IN (orderasc: born) @facets(roles) { name born }
+			~DIRECTED { name dgraph.type }
+		}
+	}`, nil)
+	if err != nil {
+		t.Fatalf("QueryRaw: %v", err)
+	}
+	var got struct {
+		Movies []struct {
+			Title    string `json:"title"`
+			Released int    `json:"released"`
+			ID       string `json:"neo4j_id"`
+			Actors   []struct {
+				Name  string `json:"name"`
+				Born  int    `json:"born"`
+				Roles string `json:"~ACTED_IN|roles"`
+			} `json:"~ACTED_IN"`
+			Directors []struct {
+				Name  string   `json:"name"`
+				Types []string `json:"dgraph.type"`
+			} `json:"~DIRECTED"`
+		} `json:"movies"`
+	}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(got.Movies) != 1 {
+		t.Fatalf("movies = %s", raw)
+	}
+	m := got.Movies[0]
+	if m.Title != "The Matrix" || m.Released != 1999 || m.ID != "0" {
+		t.Errorf("movie = %+v", m)
+	}
+	if len(m.Actors) != 2 || m.Actors[0].Name != "Keanu Reeves" || m.Actors[0].Roles != `["Neo"]` {
+		t.Errorf("actors = %+v", m.Actors)
+	}
+	if len(m.Directors) != 1 || len(m.Directors[0].Types) != 2 {
+		t.Errorf("directors = %+v", m.Directors)
+	}
+}
+
+func TestStubs(t *testing.T) {
+	g, err := neo4jimport.ReadCSV(strings.NewReader(moviesCSV))
+	if err != nil {
+		t.Fatalf("ReadCSV: %v", err)
+	}
+	src, err := neo4jimport.Stubs(g, "movies", neo4jimport.Options{})
+	if err != nil {
+		t.Fatalf("Stubs: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "stubs.go", src, 0); err != nil {
+		t.Fatalf("stubs do not parse: %v\n%s", err, src)
+	}
+	flat := strings.Join(strings.Fields(string(src)), " ")
+	for _, want := range []string{
+		"package movies",
+		"type Movie struct {",
+		"Released int64 `json:\"released,omitempty\"`",
+		"type Person struct {",
+		"ActedIn []Movie `json:\"ACTED_IN,omitempty\" dgraph:\"reverse\"`",
+		"type Director struct {",
+		"Directed []Movie `json:\"DIRECTED,omitempty\" dgraph:\"reverse\"`",
+	} {
+		if !strings.Contains(flat, want) {
+			t.Errorf("stubs lack %q:\n%s", want, src)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package neo4jimport
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"unicode"
+)
+
+// Stubs renders a Go source file declaring one struct per label in g, with
+// json and dgraph tags matching the schema Import applies, for use with the
+// typed and dgman APIs. Edges are typed by the label most often found at
+// their far end; edges to unlabelled nodes use a generated Neo4jNode struct.
+// Review the stubs before committing them: Neo4j has no schema, so they
+// describe the data that was exported, not its intent.
+func Stubs(g *Graph, pkg string, opts Options) ([]byte, error) {
+	s := inferSchema(g, opts.IDPredicate)
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by neo4jimport from a Neo4j graph; review before editing.\n\npackage %s\n\n", pkg)
+	if s.usesTime() {
+		b.WriteString("import \"time\"\n\n")
+	}
+	needsNode := false
+	for _, label := range sortedKeys(s.types) {
+		name := goName(label)
+		fmt.Fprintf(&b, "// %s is the Neo4j label %s.\ntype %s struct {\n", name, label, name)
+		b.WriteString("\tUID string `json:\"uid,omitempty\"`\n")
+		used := map[string]bool{"UID": true, "DType": true}
+		for _, field := range sortedKeys(s.types[label]) {
+			fieldName := goName(field)
+			for used[fieldName] {
+				fieldName += "_"
+			}
+			used[fieldName] = true
+			if s.edges[field] {
+				target := s.targets[label][field]
+				if target == "" {
+					target, needsNode = "Neo4jNode", true
+				} else {
+					target = goName(target)
+				}
+				fmt.Fprintf(&b, "\t%s []%s `json:\"%s,omitempty\" dgraph:\"reverse\"`\n", fieldName, target, field)
+				continue
+			}
+			fmt.Fprintf(&b, "\t%s %s `json:\"%s,omitempty\"`\n", fieldName, s.preds[field].goType(), field)
+		}
+		b.WriteString("\tDType []string `json:\"dgraph.type,omitempty\"`\n}\n\n")
+	}
+	if needsNode {
+		b.WriteString("// Neo4jNode is the far end of an edge to a node without labels.\n" +
+			"type Neo4jNode struct {\n\tUID string `json:\"uid,omitempty\"`\n}\n")
+	}
+	out, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("neo4jimport: formatting stubs: %w", err)
+	}
+	return out, nil
+}
+
+func (s *schema) usesTime() bool {
+	for p, t := range s.preds {
+		if t.kind == kindDateTime && !s.edges[p] {
+			return true
+		}
+	}
+	return false
+}
+
+func (t predType) goType() string {
+	s := [...]string{
+		kindInt: "int64", kindFloat: "float64", kindBool: "bool", kindDateTime: "time.Time", kindString: "string",
+	}[t.kind]
+	if t.list {
+		return "[]" + s
+	}
+	if t.kind == kindDateTime {
+		return "*" + s // so omitempty omits unset values
+	}
+	return s
+}
+
+// goName converts a label, property, or relationship type to an exported Go
+// identifier: ACTED_IN -> ActedIn, born -> Born, first-name -> FirstName.
+func goName(s string) string {
+	var b strings.Builder
+	upper := true
+	allCaps := strings.ToUpper(s) == s
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		switch {
+		case b.Len() == 0 && unicode.IsDigit(r):
+			b.WriteString("N")
+			b.WriteRune(r)
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+		case allCaps:
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+		upper = false
+	}
+	if b.Len() == 0 {
+		return "Field"
+	}
+	return b.String()
+}
@@ -0,0 +1,214 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// Nullable holds a scalar predicate that may have no value, telling apart
+// the three states a plain field cannot: a value, including the zero value,
+// which is written; an explicit Null, which Insert, Upsert, Update and
+// UpsertIf clear from the stored node; and unset, the zero Nullable, which
+// writes leave alone:
+//
+//	type Person struct {
+//	    Age modusgraph.Nullable[int] `json:"age,omitempty"`
+//	    // UID and DType omitted
+//	}
+//
+//	p.Age = modusgraph.NullableOf(0)  // stores 0
+//	p.Age = modusgraph.Null[int]()    // removes age
+//
+// Get leaves a Nullable unset when the predicate is absent. A JSON null
+// decodes to Null, so a Nullable in a request body clears the predicate
+// the caller nulled out.
+//
+// Pointer fields tagged dgraph:"nullable" get the same treatment without a
+// wrapper: a nil pointer clears the predicate on write.
+type Nullable[T any] struct {
+	value T
+	state nullState
+}
+
+type nullState uint8
+
+const (
+	nullUnset nullState = iota
+	nullNull
+	nullValid
+)
+
+// NullableOf returns a Nullable holding v.
+func NullableOf[T any](v T) Nullable[T] {
+	return Nullable[T]{value: v, state: nullValid}
+}
+
+// Null returns a Nullable that clears its predicate when written.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{state: nullNull}
+}
+
+// Get returns the value and whether there is one.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.value, n.state == nullValid
+}
+
+// Valid reports whether n holds a value.
+func (n Nullable[T]) Valid() bool {
+	return n.state == nullValid
+}
+
+// IsNull reports whether n is an explicit Null.
+func (n Nullable[T]) IsNull() bool {
+	return n.state == nullNull
+}
+
+// clearsPredicate reports whether writing n removes its predicate.
+func (n Nullable[T]) clearsPredicate() bool {
+	return n.IsNull()
+}
+
+// SchemaType reports the Dgraph type of the predicate to dgman: that of T.
+func (Nullable[T]) SchemaType() string {
+	t := reflect.TypeFor[T]()
+	if st, ok := reflect.New(t).Interface().(dg.SchemaType); ok {
+		return st.SchemaType()
+	}
+	if t == reflect.TypeFor[time.Time]() {
+		return "datetime"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	}
+	return "string"
+}
+
+// MarshalJSON writes the value, or null when there is none, which a set
+// mutation ignores. Predicates to clear are deleted separately.
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if n.state != nullValid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.value)
+}
+
+// UnmarshalJSON reads a value, or Null from a JSON null.
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if strings.TrimSpace(string(data)) == "null" {
+		*n = Null[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*n = NullableOf(v)
+	return nil
+}
+
+// nullClearer is implemented by every Nullable.
+type nullClearer interface {
+	clearsPredicate() bool
+	Valid() bool
+}
+
+var nullClearerType = reflect.TypeFor[nullClearer]()
+
+// isNullableField reports whether writes of f can clear its predicate: it
+// is a Nullable, or a pointer tagged dgraph:"nullable".
+func isNullableField(f reflect.StructField) bool {
+	if f.Type.Implements(nullClearerType) {
+		return true
+	}
+	return f.Type.Kind() == reflect.Ptr && slices.Contains(strings.Fields(f.Tag.Get("dgraph")), "nullable")
+}
+
+// hasNullableFields reports whether the type of obj has fields whose
+// predicates writes can clear.
+func hasNullableFields(obj any) bool {
+	t := reflect.TypeOf(obj)
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	return t != nil && t.Kind() == reflect.Struct && len(clearedPredicates(reflect.New(t).Elem(), true)) > 0
+}
+
+// clearedPredicates returns the predicates of sv that a write clears: those
+// of explicit Nulls and of nil pointers tagged dgraph:"nullable". With all
+// set, it returns every predicate that could be cleared.
+func clearedPredicates(sv reflect.Value, all bool) []string {
+	var preds []string
+	t := sv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fv := sv.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		if sf.Anonymous && fv.Kind() == reflect.Struct && sf.Tag.Get("json") == "" {
+			preds = append(preds, clearedPredicates(fv, all)...)
+			continue
+		}
+		pred := fieldPredicate(sf)
+		if pred == "" || pred == "uid" || pred == "dgraph.type" || strings.HasPrefix(pred, "~") ||
+			isFacetField(sf) || !isNullableField(sf) {
+			continue
+		}
+		switch {
+		case all:
+		case fv.Kind() == reflect.Ptr:
+			if !fv.IsNil() {
+				continue
+			}
+		case !fv.Interface().(nullClearer).clearsPredicate():
+			continue
+		}
+		preds = append(preds, pred)
+	}
+	return preds
+}
+
+// injectNulls deletes the predicates the nodes of obj clear from the nodes
+// the preceding mutation of tx wrote, before tx is committed.
+func injectNulls(ctx context.Context, tx *dg.TxnContext, obj any) error {
+	var nodes []map[string]any
+	for _, sv := range structElems(obj) {
+		uid := uidOf(sv.Addr().Interface())
+		preds := clearedPredicates(sv, false)
+		if uid == "" || len(preds) == 0 {
+			continue
+		}
+		node := map[string]any{"uid": uid}
+		for _, p := range preds {
+			node[p] = nil
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	deleteJSON, err := json.Marshal(nodes)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Txn().Mutate(ctx, &api.Mutation{DeleteJson: deleteJSON})
+	return err
+}
@@ -0,0 +1,151 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type NullablePerson struct {
+	UID      string                  `json:"uid,omitempty"`
+	Name     string                  `json:"np_name,omitempty" dgraph:"index=exact"`
+	Age      *int                    `json:"np_age,omitempty" dgraph:"nullable"`
+	Nickname *string                 `json:"np_nickname,omitempty"`
+	Score    mg.Nullable[float64]    `json:"np_score,omitempty" dgraph:"index=float"`
+	Active   mg.Nullable[bool]       `json:"np_active,omitempty"`
+	Balance  mg.Nullable[mg.Decimal] `json:"np_balance,omitempty"`
+	DType    []string                `json:"dgraph.type,omitempty"`
+}
+
+type NullableRequired struct {
+	UID   string           `json:"uid,omitempty"`
+	Rank  mg.Nullable[int] `json:"nr_rank,omitempty" dgraph:"required"`
+	DType []string         `json:"dgraph.type,omitempty"`
+}
+
+func TestNullableJSON(t *testing.T) {
+	var n mg.Nullable[int]
+	require.False(t, n.Valid())
+	require.False(t, n.IsNull())
+	v, ok := mg.NullableOf(0).Get()
+	require.True(t, ok)
+	require.Equal(t, 0, v)
+	require.True(t, mg.Null[int]().IsNull())
+
+	data, err := json.Marshal(NullablePerson{Score: mg.NullableOf(0.0), Active: mg.Null[bool]()})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"np_score":0,"np_active":null,"np_balance":null}`, string(data))
+
+	var p NullablePerson
+	require.NoError(t, json.Unmarshal([]byte(`{"np_score":1.5,"np_active":null}`), &p))
+	require.Equal(t, mg.NullableOf(1.5), p.Score)
+	require.True(t, p.Active.IsNull())
+	require.False(t, p.Balance.Valid())
+	require.False(t, p.Balance.IsNull())
+	require.Error(t, json.Unmarshal([]byte(`{"np_score":"high"}`), &p))
+}
+
+func TestNullableFields(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "NullableFieldsWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "NullableFieldsWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			ctx := context.Background()
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			zero := 0
+			nick := "Bo"
+			person := &NullablePerson{
+				Name:     "Bob",
+				Age:      &zero,
+				Nickname: &nick,
+				Score:    mg.NullableOf(0.0),
+				Active:   mg.NullableOf(false),
+				Balance:  mg.NullableOf(mg.MustParseDecimal("0")),
+			}
+			require.NoError(t, client.Insert(ctx, person))
+
+			schema, err := client.GetSchema(ctx)
+			require.NoError(t, err)
+			for pred, typ := range map[string]string{"np_score": "float", "np_active": "bool", "np_balance": "bigfloat"} {
+				info, ok := schema.Predicate(pred)
+				require.True(t, ok, pred)
+				require.Equal(t, typ, info.Type, pred)
+			}
+
+			// Zero values are stored and read back as values.
+			var got NullablePerson
+			require.NoError(t, client.Get(ctx, &got, person.UID))
+			require.NotNil(t, got.Age)
+			require.Equal(t, 0, *got.Age)
+			require.Equal(t, mg.NullableOf(0.0), got.Score)
+			require.Equal(t, mg.NullableOf(false), got.Active)
+			require.Equal(t, mg.NullableOf(mg.MustParseDecimal("0")), got.Balance)
+
+			// Absent predicates read back as nil and unset.
+			bare := &NullablePerson{Name: "Ann"}
+			require.NoError(t, client.Insert(ctx, bare))
+			got = NullablePerson{}
+			require.NoError(t, client.Get(ctx, &got, bare.UID))
+			require.Nil(t, got.Age)
+			require.Nil(t, got.Nickname)
+			require.False(t, got.Score.Valid())
+			require.False(t, got.Active.Valid())
+
+			// Update clears nullable pointers left nil and explicit Nulls;
+			// untagged pointers and unset Nullables are left alone.
+			require.NoError(t, client.Update(ctx, &NullablePerson{
+				UID:    person.UID,
+				Name:   "Bob",
+				Score:  mg.Null[float64](),
+				Active: mg.NullableOf(true),
+			}))
+			got = NullablePerson{}
+			require.NoError(t, client.Get(ctx, &got, person.UID))
+			require.Nil(t, got.Age)
+			require.NotNil(t, got.Nickname)
+			require.Equal(t, "Bo", *got.Nickname)
+			require.False(t, got.Score.Valid())
+			require.Equal(t, mg.NullableOf(true), got.Active)
+			require.Equal(t, mg.NullableOf(mg.MustParseDecimal("0")), got.Balance)
+
+			var scored []NullablePerson
+			require.NoError(t, client.Query(ctx, NullablePerson{}).Filter("has(np_score)").Nodes(&scored))
+			require.Empty(t, scored)
+
+			// A required Nullable must hold a value, though a zero one will do.
+			require.ErrorIs(t, client.Insert(ctx, &NullableRequired{}), mg.ErrRequiredField)
+			require.ErrorIs(t, client.Insert(ctx, &NullableRequired{Rank: mg.Null[int]()}), mg.ErrRequiredField)
+			require.NoError(t, client.Insert(ctx, &NullableRequired{Rank: mg.NullableOf(0)}))
+		})
+	}
+}
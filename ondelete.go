@@ -0,0 +1,316 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/dgraph-io/dgo/v250"
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// ErrDeleteRestricted is returned by Delete when a node to delete is still
+// referenced through an edge tagged dgraph:"ondelete=restrict" by a node that
+// is not deleted along with it. Nothing is deleted.
+var ErrDeleteRestricted = errors.New("modusgraph: node is referenced by a restricting edge")
+
+// Policies of the ondelete tag.
+const (
+	onDeleteCascade  = "cascade"
+	onDeleteRestrict = "restrict"
+	onDeleteSetNull  = "setnull"
+)
+
+// onDeleteTag matches the ondelete=<policy> token of a dgraph tag.
+var onDeleteTag = regexp.MustCompile(`(?:^|[\s,])ondelete=(\w+)`)
+
+// deleteRule is an edge field tagged ondelete: when Delete removes the node
+// an edge of predicate points to, the nodes of nodeType holding the edge are
+// deleted too (cascade), keep the node from being deleted (restrict) or lose
+// the edge (setnull).
+type deleteRule struct {
+	nodeType  string
+	field     string
+	predicate string
+	policy    string
+}
+
+// deletePolicies holds the ondelete rules and soft-delete predicates of the
+// models a client has seen. A nil *deletePolicies holds none.
+type deletePolicies struct {
+	mu          sync.RWMutex
+	seen        map[reflect.Type]bool
+	rules       []deleteRule
+	softDeletes map[string]string // soft-delete predicate by node type
+}
+
+func newDeletePolicies() *deletePolicies {
+	return &deletePolicies{seen: make(map[reflect.Type]bool), softDeletes: make(map[string]string)}
+}
+
+// RegisterModels records the dgraph:"ondelete=..." edge policies and
+// dgraph:"softdelete" fields of models and of the types their edges point
+// to, for Delete to enforce. Models are registered automatically when they
+// pass through UpdateSchema, WithSchemaMode, a write or a Delete given
+// structs, and typed clients register their type when created; call
+// RegisterModels before DeleteByUID on types the client has not otherwise
+// used.
+func (c client) RegisterModels(models ...any) {
+	c.policies.register(models...)
+}
+
+// register records the policies of models.
+func (p *deletePolicies) register(models ...any) {
+	if p == nil {
+		return
+	}
+	for _, m := range models {
+		p.registerType(reflect.TypeOf(UnwrapSchema(m)))
+	}
+}
+
+// registerType records the ondelete rules of struct type t and of the
+// struct types its fields refer to.
+func (p *deletePolicies) registerType(t reflect.Type) {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return
+	}
+	p.mu.Lock()
+	if p.seen[t] {
+		p.mu.Unlock()
+		return
+	}
+	p.seen[t] = true
+	nodeType := getNodeType(reflect.New(t).Interface())
+	if pred, ok := SoftDeletePredicate(reflect.New(t).Interface()); ok {
+		p.softDeletes[nodeType] = pred
+	}
+	var refs []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if m := onDeleteTag.FindStringSubmatch(f.Tag.Get("dgraph")); m != nil {
+			p.rules = append(p.rules, deleteRule{
+				nodeType:  nodeType,
+				field:     t.Name() + "." + f.Name,
+				predicate: fieldPredicate(f),
+				policy:    m[1],
+			})
+		}
+		refs = append(refs, f.Type)
+	}
+	p.mu.Unlock()
+
+	for _, ref := range refs {
+		p.registerType(ref)
+	}
+}
+
+// deleteRules returns the rules recorded so far.
+func (p *deletePolicies) deleteRules() []deleteRule {
+	if p == nil {
+		return nil
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return slices.Clone(p.rules)
+}
+
+// softDeletePredicates returns the soft-delete predicates of the registered
+// node types, by type.
+func (p *deletePolicies) softDeletePredicates() map[string]string {
+	if p == nil {
+		return nil
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return maps.Clone(p.softDeletes)
+}
+
+// deleteWithRules deletes uids in one transaction, applying the registered
+// ondelete rules whose predicates exist in the live schema: cascading
+// deletes to dependents, detaching setnull edges, and failing with
+// ErrDeleteRestricted if a restricting dependent would be left behind. With
+// detach, every other edge to the deleted nodes is detached too.
+func (c client) deleteWithRules(ctx context.Context, client *dgo.Dgraph, uids []string, rules []deleteRule,
+	detach bool) error {
+	tx := dg.NewTxnContext(ctx, client)
+	defer func() { _ = tx.Txn().Discard(ctx) }()
+	deleted, detached, err := c.deleteInTxn(ctx, tx, uids, rules, detach)
+	if err != nil {
+		return err
+	}
+	if err := tx.Txn().Commit(ctx); err != nil {
+		return fmt.Errorf("committing delete: %w", err)
+	}
+	c.logger.V(2).Info("Delete successful", "requested", len(uids), "deleted", deleted, "detached", detached)
+	return nil
+}
+
+// deleteInTxn adds the deletes of deleteWithRules to tx without committing
+// it, and returns how many nodes it deletes and edges it detaches.
+func (c client) deleteInTxn(ctx context.Context, tx *dg.TxnContext, uids []string, rules []deleteRule,
+	detach bool) (int, int, error) {
+	schema, err := c.GetSchema(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	rules = slices.DeleteFunc(rules, func(r deleteRule) bool {
+		_, ok := schema.Predicate(r.predicate)
+		return !ok
+	})
+	if detach {
+		// Rules without a node type match the holders of any type. The
+		// registered rules come first, so a restricting or cascading edge
+		// is handled by its own policy.
+		for _, p := range schema.Predicates {
+			if p.Type == "uid" && !strings.HasPrefix(p.Name, "dgraph.") {
+				rules = append(rules, deleteRule{field: p.Name, predicate: p.Name, policy: onDeleteSetNull})
+			}
+		}
+	}
+	for _, r := range rules {
+		if r.policy != onDeleteCascade && r.policy != onDeleteRestrict && r.policy != onDeleteSetNull {
+			return 0, 0, fmt.Errorf("unknown ondelete policy %q on %s; want cascade, restrict or setnull", r.policy, r.field)
+		}
+	}
+
+	deleting := make(map[string]bool, len(uids))
+	for _, uid := range uids {
+		deleting[uid] = true
+	}
+	type reference struct {
+		rule    deleteRule
+		from    string
+		targets []string
+	}
+	var restricted, detached []reference
+
+	// Follow cascades breadth first, so that each round only looks up the
+	// dependents of the nodes the previous round added.
+	for batch := uids; len(batch) > 0; {
+		var next []string
+		for _, r := range rules {
+			dependents, err := queryDependents(ctx, tx, r, batch)
+			if err != nil {
+				return 0, 0, err
+			}
+			for from, targets := range dependents {
+				switch r.policy {
+				case onDeleteCascade:
+					if !deleting[from] {
+						deleting[from] = true
+						next = append(next, from)
+					}
+				case onDeleteRestrict:
+					restricted = append(restricted, reference{r, from, targets})
+				case onDeleteSetNull:
+					detached = append(detached, reference{r, from, targets})
+				}
+			}
+		}
+		batch = next
+	}
+
+	// A restricting dependent deleted by a cascade does not block.
+	for _, ref := range restricted {
+		if !deleting[ref.from] {
+			return 0, 0, fmt.Errorf("%w: %s %s references %s through %s",
+				ErrDeleteRestricted, ref.rule.nodeType, ref.from, strings.Join(ref.targets, ", "), ref.rule.predicate)
+		}
+	}
+	for _, ref := range detached {
+		if deleting[ref.from] {
+			continue
+		}
+		if err := tx.DeleteEdge(ref.from, ref.rule.predicate, ref.targets...); err != nil {
+			return 0, 0, err
+		}
+	}
+	all := make([]string, 0, len(deleting))
+	for uid := range deleting {
+		all = append(all, uid)
+	}
+	slices.Sort(all)
+	if err := tx.DeleteNode(all...); err != nil {
+		return 0, 0, err
+	}
+	return len(all), len(detached), nil
+}
+
+// queryDependents returns the nodes of the rule's type holding an edge of
+// its predicate to any of targets, each with the targets it points to.
+func queryDependents(ctx context.Context, tx *dg.TxnContext, r deleteRule,
+	targets []string) (map[string][]string, error) {
+	list := strings.Join(targets, ", ")
+	root := fmt.Sprintf("type(%s)", r.nodeType)
+	if r.nodeType == "" {
+		root = fmt.Sprintf("has(<%s>)", r.predicate)
+	}
+	query := fmt.Sprintf("{\n  q(func: %s) @filter(uid_in(<%s>, [%s])) {\n    uid\n"+
+		"    refs: <%s> @filter(uid(%s)) {\n      uid\n    }\n  }\n}\n",
+		root, r.predicate, list, r.predicate, list)
+	resp, err := tx.Txn().Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("finding dependents through %s: %w", r.predicate, err)
+	}
+	var result struct {
+		Q []struct {
+			UID  string          `json:"uid"`
+			Refs json.RawMessage `json:"refs"`
+		} `json:"q"`
+	}
+	if err := json.Unmarshal(resp.Json, &result); err != nil {
+		return nil, err
+	}
+	dependents := make(map[string][]string, len(result.Q))
+	for _, node := range result.Q {
+		refs, err := edgeUIDs(node.Refs)
+		if err != nil {
+			return nil, err
+		}
+		dependents[node.UID] = append(dependents[node.UID], refs...)
+	}
+	return dependents, nil
+}
+
+// edgeUIDs decodes the uids of an edge in a query result, which Dgraph
+// returns as an object for single edges and as an array for list edges.
+func edgeUIDs(raw json.RawMessage) ([]string, error) {
+	type ref struct {
+		UID string `json:"uid"`
+	}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var refs []ref
+	if err := json.Unmarshal(raw, &refs); err != nil {
+		var single ref
+		if err := json.Unmarshal(raw, &single); err != nil {
+			return nil, err
+		}
+		refs = []ref{single}
+	}
+	uids := make([]string, len(refs))
+	for i, r := range refs {
+		uids[i] = r.UID
+	}
+	return uids, nil
+}
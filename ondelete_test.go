@@ -0,0 +1,165 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type RefAuthor struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"ref_author_name,omitempty" dgraph:"index=exact"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type RefBook struct {
+	UID    string     `json:"uid,omitempty"`
+	Title  string     `json:"ref_book_title,omitempty" dgraph:"index=exact"`
+	Author *RefAuthor `json:"ref_book_author,omitempty" dgraph:"ondelete=cascade"`
+	DType  []string   `json:"dgraph.type,omitempty"`
+}
+
+type RefReview struct {
+	UID   string   `json:"uid,omitempty"`
+	Text  string   `json:"ref_review_text,omitempty"`
+	Book  *RefBook `json:"ref_review_book,omitempty" dgraph:"ondelete=cascade"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type RefLoan struct {
+	UID      string   `json:"uid,omitempty"`
+	Borrower string   `json:"ref_loan_borrower,omitempty"`
+	Book     *RefBook `json:"ref_loan_book,omitempty" dgraph:"ondelete=restrict"`
+	DType    []string `json:"dgraph.type,omitempty"`
+}
+
+type RefShelf struct {
+	UID   string     `json:"uid,omitempty"`
+	Name  string     `json:"ref_shelf_name,omitempty"`
+	Books []*RefBook `json:"ref_shelf_books,omitempty" dgraph:"ondelete=setnull"`
+	DType []string   `json:"dgraph.type,omitempty"`
+}
+
+func TestDeleteReferentialIntegrity(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "DeleteReferentialIntegrityWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "DeleteReferentialIntegrityWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+			require.NoError(t, client.UpdateSchema(ctx, &RefReview{}, &RefLoan{}, &RefShelf{}))
+
+			count := func(typ string) int {
+				t.Helper()
+				var result struct {
+					Q []struct {
+						Count int `json:"count"`
+					} `json:"q"`
+				}
+				resp, err := client.QueryRaw(ctx, `{ q(func: type(`+typ+`)) { count(uid) } }`, nil)
+				require.NoError(t, err)
+				require.NoError(t, json.Unmarshal(resp, &result))
+				return result.Q[0].Count
+			}
+
+			ursula := &RefAuthor{Name: "Ursula K. Le Guin"}
+			earthsea := &RefBook{Title: "A Wizard of Earthsea", Author: ursula}
+			require.NoError(t, client.Insert(ctx, earthsea))
+			dispossessed := &RefBook{Title: "The Dispossessed", Author: &RefAuthor{UID: ursula.UID}}
+			require.NoError(t, client.Insert(ctx, dispossessed))
+			require.NoError(t, client.Insert(ctx, &RefReview{Text: "Timeless", Book: &RefBook{UID: earthsea.UID}}))
+			shelf := &RefShelf{Name: "Favourites", Books: []*RefBook{{UID: earthsea.UID}, {UID: dispossessed.UID}}}
+			require.NoError(t, client.Insert(ctx, shelf))
+			loan := &RefLoan{Borrower: "Ged", Book: &RefBook{UID: dispossessed.UID}}
+			require.NoError(t, client.Insert(ctx, loan))
+
+			// The loan restricts deleting its book, directly or by cascade
+			// from the author, and nothing is deleted.
+			err := client.Delete(ctx, []string{ursula.UID})
+			require.ErrorIs(t, err, mg.ErrDeleteRestricted)
+			require.ErrorContains(t, err, "RefLoan "+loan.UID+" references "+dispossessed.UID+" through ref_loan_book")
+			require.Equal(t, 2, count("RefBook"))
+			require.Equal(t, 1, count("RefReview"))
+
+			// Deleting the restricting node along with its target is allowed.
+			require.NoError(t, client.Delete(ctx, []string{loan.UID}))
+
+			// Deleting the author cascades to its books and their reviews, and
+			// the shelf loses its edges to them.
+			require.NoError(t, client.Delete(ctx, []string{ursula.UID}))
+			require.Equal(t, 0, count("RefAuthor"))
+			require.Equal(t, 0, count("RefBook"))
+			require.Equal(t, 0, count("RefReview"))
+			var got RefShelf
+			require.NoError(t, client.Get(ctx, &got, shelf.UID))
+			require.Equal(t, "Favourites", got.Name)
+			require.Empty(t, got.Books)
+			resp, err := client.QueryRaw(ctx, `{ q(func: uid(`+shelf.UID+`)) { ref_shelf_books { uid } } }`, nil)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"q":[]}`, string(resp))
+		})
+	}
+}
+
+func TestDeletePoliciesPerClient(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	client, err := mg.NewClient("file://"+dir, mg.WithAutoSchema(true))
+	require.NoError(t, err)
+	ursula := &RefAuthor{Name: "Ursula"}
+	earthsea := &RefBook{Title: "Earthsea", Author: ursula}
+	lathe := &RefBook{Title: "The Lathe of Heaven", Author: &RefAuthor{Name: "Le Guin"}}
+	require.NoError(t, client.Insert(ctx, earthsea))
+	require.NoError(t, client.Insert(ctx, lathe))
+	client.Close()
+
+	// A client that has not seen RefBook does not apply its policies, even
+	// though another client in the process has.
+	client, err = mg.NewClient("file://" + dir)
+	require.NoError(t, err)
+	title := func(uid string) string {
+		t.Helper()
+		resp, err := client.QueryRaw(ctx, `{ q(func: uid(`+uid+`)) { ref_book_title } }`, nil)
+		require.NoError(t, err)
+		return string(resp)
+	}
+	require.NoError(t, client.DeleteByUID(ctx, ursula.UID))
+	require.JSONEq(t, `{"q":[{"ref_book_title":"Earthsea"}]}`, title(earthsea.UID))
+	client.Close()
+
+	// Models given to WithSchemaMode are registered by NewClient.
+	client, err = mg.NewClient("file://"+dir, mg.WithSchemaMode(mg.SchemaWarn, &RefBook{}))
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.DeleteByUID(ctx, lathe.Author.UID))
+	require.JSONEq(t, `{"q":[]}`, title(lathe.UID))
+}
@@ -0,0 +1,133 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type LockedDoc struct {
+	UID     string   `json:"uid,omitempty"`
+	Title   string   `json:"locked_title,omitempty" dgraph:"index=exact upsert"`
+	Body    string   `json:"locked_body,omitempty"`
+	Version int64    `json:"version,omitempty"`
+	DType   []string `json:"dgraph.type,omitempty"`
+}
+
+func TestOptimisticLocking(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "OptimisticLockingWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "OptimisticLockingWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, err := mg.NewClient(tc.uri, mg.WithAutoSchema(true), mg.WithOptimisticLocking())
+			require.NoError(t, err)
+			defer func() {
+				if strings.HasPrefix(tc.uri, "dgraph://") {
+					_ = client.DropAll(context.Background())
+				}
+				client.Close()
+				mg.Shutdown()
+			}()
+			ctx := context.Background()
+			if strings.HasPrefix(tc.uri, "dgraph://") {
+				require.NoError(t, client.DropAll(ctx))
+			}
+
+			doc := &LockedDoc{Title: "plan", Body: "draft"}
+			require.NoError(t, client.Insert(ctx, doc))
+			require.Equal(t, int64(1), doc.Version)
+
+			// Of two writers holding the same version, the second is stale.
+			var first, second LockedDoc
+			require.NoError(t, client.Get(ctx, &first, doc.UID))
+			require.NoError(t, client.Get(ctx, &second, doc.UID))
+			first.Body = "first"
+			require.NoError(t, client.Update(ctx, &first))
+			require.Equal(t, int64(2), first.Version)
+			second.Body = "second"
+			err = client.Update(ctx, &second)
+			require.ErrorIs(t, err, mg.ErrConflict)
+			var conflict *mg.ConflictError
+			require.True(t, errors.As(err, &conflict))
+			require.Equal(t, mg.ConflictError{UID: doc.UID, Version: 1, Stored: 2}, *conflict)
+			require.Equal(t, int64(1), second.Version, "a rejected write keeps its version")
+
+			var got LockedDoc
+			require.NoError(t, client.Get(ctx, &got, doc.UID))
+			require.Equal(t, "first", got.Body)
+			require.Equal(t, int64(2), got.Version)
+
+			// Upsert checks the node its predicate matches.
+			err = client.Upsert(ctx, &LockedDoc{Title: "plan", Body: "blind"})
+			require.ErrorIs(t, err, mg.ErrConflict)
+			upserted := &LockedDoc{Title: "plan", Body: "upserted", Version: 2}
+			require.NoError(t, client.Upsert(ctx, upserted))
+			require.Equal(t, int64(3), upserted.Version)
+			fresh := &LockedDoc{Title: "notes", Body: "new"}
+			require.NoError(t, client.Upsert(ctx, fresh))
+			require.Equal(t, int64(1), fresh.Version)
+
+			// Of concurrent writers from the same version, exactly one wins.
+			var (
+				wg        sync.WaitGroup
+				mu        sync.Mutex
+				wins      int
+				conflicts int
+			)
+			for i := 0; i < 5; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					err := client.Update(ctx, &LockedDoc{UID: doc.UID, Body: fmt.Sprint("writer ", i), Version: 3})
+					mu.Lock()
+					defer mu.Unlock()
+					switch {
+					case err == nil:
+						wins++
+					case errors.Is(err, mg.ErrConflict):
+						conflicts++
+					default:
+						t.Errorf("Update: %v", err)
+					}
+				}(i)
+			}
+			wg.Wait()
+			require.Equal(t, 1, wins)
+			require.Equal(t, 4, conflicts)
+			got = LockedDoc{}
+			require.NoError(t, client.Get(ctx, &got, doc.UID))
+			require.Equal(t, int64(4), got.Version)
+		})
+	}
+}
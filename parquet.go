@@ -0,0 +1,316 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	dg "github.com/dolan-in/dgman/v2"
+	"github.com/parquet-go/parquet-go"
+)
+
+// ParquetOptions configures ExportParquet.
+//
+// Filter and Params restrict the export to matching nodes, with the same
+// @filter syntax and $N binding as Query(...).Filter. BatchSize is how many
+// nodes are read per query and written per row group; it defaults to 10000.
+type ParquetOptions struct {
+	Filter    string
+	Params    []any
+	BatchSize int
+}
+
+const defaultParquetBatchSize = 10000
+
+// parquetColumn maps one struct field to one Parquet column.
+type parquetColumn struct {
+	predicate string
+	edge      bool
+	repeated  bool
+	kind      reflect.Kind // of the scalar (element) type; zero for edges
+	timestamp bool
+	decimal   bool
+	geo       bool
+	index     int // leaf column index in the schema
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	decimalType = reflect.TypeOf(Decimal{})
+)
+
+// ExportParquet writes every node of model's type to w as a Parquet file with
+// one row per node. The uid and each scalar predicate become optional columns
+// named after the predicate; slices of scalars become repeated columns. Edges
+// become UID columns — a string column holding the target's UID, repeated for
+// multi-valued edges — so the file joins back to other exports on uid rather
+// than nesting. Decimal fields become string columns holding the exact
+// value, and Point, Polygon and MultiPolygon fields string columns holding
+// GeoJSON. Fields Parquet cannot represent (maps, vectors) are skipped.
+func (c client) ExportParquet(ctx context.Context, model any, w io.Writer, opts ParquetOptions) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	model = UnwrapSchema(model)
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("modusgraph: ExportParquet model must be a struct, got %T", model)
+	}
+	schema, columns := parquetSchema(t)
+	batch := opts.BatchSize
+	if batch <= 0 {
+		batch = defaultParquetBatchSize
+	}
+
+	selection := make([]string, 0, len(columns))
+	for _, col := range columns {
+		if col.edge {
+			selection = append(selection, col.predicate+" { uid }")
+		} else {
+			selection = append(selection, col.predicate)
+		}
+	}
+	projection := "{ " + strings.Join(selection, " ") + " }"
+
+	pw := parquet.NewWriter(w, schema, parquet.Compression(&parquet.Snappy))
+	after := ""
+	for {
+		q := dg.NewQuery().Model(model).Name("rows").Query(projection).First(batch)
+		if opts.Filter != "" {
+			q.Filter(opts.Filter, opts.Params...)
+		}
+		if after != "" {
+			q.After(after)
+		}
+		resp, err := c.QueryRaw(ctx, q.String(), nil)
+		if err != nil {
+			return err
+		}
+		var result struct {
+			Rows []map[string]any `json:"rows"`
+		}
+		dec := json.NewDecoder(bytes.NewReader(resp))
+		dec.UseNumber()
+		if err := dec.Decode(&result); err != nil {
+			return err
+		}
+		rows := make([]parquet.Row, len(result.Rows))
+		for i, node := range result.Rows {
+			rows[i], err = parquetRow(node, columns)
+			if err != nil {
+				return err
+			}
+		}
+		if _, err := pw.WriteRows(rows); err != nil {
+			return err
+		}
+		if len(result.Rows) < batch {
+			break
+		}
+		if err := pw.Flush(); err != nil {
+			return err
+		}
+		after, _ = result.Rows[len(result.Rows)-1]["uid"].(string)
+	}
+	return pw.Close()
+}
+
+// parquetSchema derives the Parquet schema for a struct type, returning its
+// columns in schema order.
+func parquetSchema(t reflect.Type) (*parquet.Schema, []parquetColumn) {
+	group := parquet.Group{"uid": parquet.Optional(parquet.String())}
+	columns := []parquetColumn{{predicate: "uid", kind: reflect.String}}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() || sf.Name == "UID" || sf.Name == "DType" || isFacetField(sf) {
+			continue
+		}
+		pred := fieldPredicate(sf)
+		if pred == "" || pred == "uid" || strings.HasPrefix(pred, "~") {
+			continue
+		}
+		col := parquetColumn{predicate: pred}
+		ft := sf.Type
+		if ft.Kind() == reflect.Slice && ft.Elem().Kind() != reflect.Uint8 {
+			col.repeated = true
+			ft = ft.Elem()
+		}
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		var node parquet.Node
+		switch {
+		case ft == timeType:
+			col.timestamp = true
+			node = parquet.Timestamp(parquet.Microsecond)
+		case ft == decimalType:
+			col.decimal = true
+			node = parquet.String()
+		case isGeoType(ft):
+			col.geo = true
+			node = parquet.String()
+		case ft.Kind() == reflect.Struct:
+			col.edge = true
+			node = parquet.String()
+		default:
+			col.kind = ft.Kind()
+			switch col.kind {
+			case reflect.String:
+				node = parquet.String()
+			case reflect.Bool:
+				node = parquet.Leaf(parquet.BooleanType)
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+				node = parquet.Int(64)
+			case reflect.Float32, reflect.Float64:
+				if col.repeated && ft.Kind() == reflect.Float32 {
+					continue // []float32 is a vector embedding, not a list
+				}
+				node = parquet.Leaf(parquet.DoubleType)
+			default:
+				continue
+			}
+		}
+		if _, dup := group[pred]; dup {
+			continue
+		}
+		if col.repeated {
+			group[pred] = parquet.Repeated(node)
+		} else {
+			group[pred] = parquet.Optional(node)
+		}
+		columns = append(columns, col)
+	}
+	schema := parquet.NewSchema(t.Name(), group)
+	for i := range columns {
+		leaf, _ := schema.Lookup(columns[i].predicate)
+		columns[i].index = leaf.ColumnIndex
+	}
+	// Rows must list values in column order.
+	sort.Slice(columns, func(i, j int) bool { return columns[i].index < columns[j].index })
+	return schema, columns
+}
+
+// parquetRow converts one query result node into a Parquet row.
+func parquetRow(node map[string]any, columns []parquetColumn) (parquet.Row, error) {
+	row := make(parquet.Row, 0, len(columns))
+	for _, col := range columns {
+		v, ok := node[col.predicate]
+		if !ok || v == nil {
+			row = append(row, parquet.NullValue().Level(0, 0, col.index))
+			continue
+		}
+		values, ok := v.([]any)
+		if !col.repeated || !ok {
+			values = []any{v}
+		}
+		if len(values) == 0 {
+			row = append(row, parquet.NullValue().Level(0, 0, col.index))
+			continue
+		}
+		for i, e := range values {
+			pv, err := col.value(e)
+			if err != nil {
+				return nil, fmt.Errorf("modusgraph: column %s: %w", col.predicate, err)
+			}
+			rep := 0
+			if i > 0 {
+				rep = 1
+			}
+			row = append(row, pv.Level(rep, 1, col.index))
+			if !col.repeated {
+				break
+			}
+		}
+	}
+	return row, nil
+}
+
+// value converts one decoded JSON value to a Parquet value for the column.
+func (col parquetColumn) value(v any) (parquet.Value, error) {
+	if col.edge {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return parquet.Value{}, fmt.Errorf("expected edge object, got %T", v)
+		}
+		return parquet.ByteArrayValue([]byte(fmt.Sprint(m["uid"]))), nil
+	}
+	if col.timestamp {
+		s, _ := v.(string)
+		ts, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return parquet.Value{}, err
+		}
+		return parquet.Int64Value(ts.UnixMicro()), nil
+	}
+	if col.decimal {
+		d, err := ParseDecimal(fmt.Sprint(v))
+		if err != nil {
+			return parquet.Value{}, err
+		}
+		return parquet.ByteArrayValue([]byte(d.String())), nil
+	}
+	if col.geo {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return parquet.Value{}, err
+		}
+		return parquet.ByteArrayValue(data), nil
+	}
+	switch col.kind {
+	case reflect.String:
+		return parquet.ByteArrayValue([]byte(fmt.Sprint(v))), nil
+	case reflect.Bool:
+		b, ok := v.(bool)
+		if !ok {
+			return parquet.Value{}, fmt.Errorf("expected bool, got %T", v)
+		}
+		return parquet.BooleanValue(b), nil
+	case reflect.Float32, reflect.Float64:
+		n, ok := v.(json.Number)
+		if !ok {
+			return parquet.Value{}, fmt.Errorf("expected number, got %T", v)
+		}
+		f, err := n.Float64()
+		return parquet.DoubleValue(f), err
+	default:
+		n, ok := v.(json.Number)
+		if !ok {
+			return parquet.Value{}, fmt.Errorf("expected number, got %T", v)
+		}
+		i, err := n.Int64()
+		return parquet.Int64Value(i), err
+	}
+}
+
+// fieldPredicate returns the Dgraph predicate a struct field maps to: an
+// explicit predicate= directive, else the json tag name, else the field name.
+// It returns "" for fields excluded from JSON.
+func fieldPredicate(sf reflect.StructField) string {
+	for _, directive := range strings.Fields(sf.Tag.Get("dgraph")) {
+		if strings.HasPrefix(directive, "predicate=") {
+			return strings.TrimPrefix(directive, "predicate=")
+		}
+	}
+	name := strings.Split(sf.Tag.Get("json"), ",")[0]
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return sf.Name
+	}
+	return name
+}
@@ -0,0 +1,142 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/require"
+)
+
+type ParquetFilm struct {
+	UID      string            `json:"uid,omitempty"`
+	Title    string            `json:"title,omitempty" dgraph:"index=exact"`
+	Year     int               `json:"year,omitempty"`
+	Rating   float64           `json:"rating,omitempty"`
+	Budget   mg.Decimal        `json:"budget,omitempty"`
+	Shot     mg.Point          `json:"shot,omitempty"`
+	Released time.Time         `json:"released,omitempty"`
+	Genres   []string          `json:"genres,omitempty"`
+	Director *ParquetDirector  `json:"director,omitempty"`
+	Cast     []ParquetDirector `json:"cast,omitempty"`
+	DType    []string          `json:"dgraph.type,omitempty"`
+}
+
+type ParquetDirector struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"name,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type parquetFilmRow struct {
+	UID      *string    `parquet:"uid,optional"`
+	Title    *string    `parquet:"title,optional"`
+	Year     *int64     `parquet:"year,optional"`
+	Rating   *float64   `parquet:"rating,optional"`
+	Budget   *string    `parquet:"budget,optional"`
+	Shot     *string    `parquet:"shot,optional"`
+	Released *time.Time `parquet:"released,optional,timestamp(microsecond)"`
+	Genres   []string   `parquet:"genres"`
+	Director *string    `parquet:"director,optional"`
+	Cast     []string   `parquet:"cast"`
+}
+
+func TestExportParquet(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "ExportParquetWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "ExportParquetWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			ctx := context.Background()
+			released := time.Date(1927, 1, 10, 0, 0, 0, 0, time.UTC)
+			films := []*ParquetFilm{
+				{
+					Title:    "Metropolis",
+					Year:     1927,
+					Rating:   8.3,
+					Budget:   mg.MustParseDecimal("5100000.01"),
+					Shot:     mg.NewPoint(13.09, 52.39),
+					Released: released,
+					Genres:   []string{"drama", "sci-fi"},
+					Director: &ParquetDirector{Name: "Fritz Lang"},
+					Cast:     []ParquetDirector{{Name: "Brigitte Helm"}, {Name: "Gustav Fröhlich"}},
+				},
+				{Title: "Untitled"},
+				{Title: "Sunrise", Year: 1927},
+			}
+			require.NoError(t, client.Insert(ctx, films))
+
+			var buf bytes.Buffer
+			// A batch smaller than the result set exercises pagination.
+			require.NoError(t, client.ExportParquet(ctx, &ParquetFilm{}, &buf,
+				mg.ParquetOptions{BatchSize: 2}))
+
+			rows, err := parquet.Read[parquetFilmRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			require.NoError(t, err)
+			require.Len(t, rows, len(films))
+
+			byTitle := map[string]parquetFilmRow{}
+			for _, r := range rows {
+				require.NotNil(t, r.Title)
+				byTitle[*r.Title] = r
+			}
+			m := byTitle["Metropolis"]
+			require.Equal(t, films[0].UID, *m.UID)
+			require.EqualValues(t, 1927, *m.Year)
+			require.InDelta(t, 8.3, *m.Rating, 1e-9)
+			require.Equal(t, "5100000.01", *m.Budget)
+			require.JSONEq(t, `{"type":"Point","coordinates":[13.09,52.39]}`, *m.Shot)
+			require.True(t, released.Equal(*m.Released))
+			sort.Strings(m.Genres)
+			require.Equal(t, []string{"drama", "sci-fi"}, m.Genres)
+			require.Equal(t, films[0].Director.UID, *m.Director)
+			require.ElementsMatch(t, []string{films[0].Cast[0].UID, films[0].Cast[1].UID}, m.Cast)
+
+			u := byTitle["Untitled"]
+			require.Nil(t, u.Year)
+			require.Nil(t, u.Budget)
+			require.Nil(t, u.Shot)
+			require.Nil(t, u.Director)
+			require.Empty(t, u.Cast)
+
+			var filtered bytes.Buffer
+			require.NoError(t, client.ExportParquet(ctx, &ParquetFilm{}, &filtered,
+				mg.ParquetOptions{Filter: "eq(title, $1)", Params: []any{"Sunrise"}}))
+			rows, err = parquet.Read[parquetFilmRow](bytes.NewReader(filtered.Bytes()), int64(filtered.Len()))
+			require.NoError(t, err)
+			require.Len(t, rows, 1)
+			require.Equal(t, "Sunrise", *rows[0].Title)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// ErrNotPassword is returned by SetPassword for a predicate the schema does
+// not declare of type password, whose values would be stored in plaintext.
+var ErrNotPassword = errors.New("modusgraph: not a password predicate")
+
+// predicateName matches the predicate names CheckPassword interpolates into
+// its query.
+var predicateName = regexp.MustCompile(`^[\w.~]+$`)
+
+// CheckPassword implements verifying candidate against the password predicate
+// of the node uid with Dgraph's checkpwd.
+func (c client) CheckPassword(ctx context.Context, uid, predicate, candidate string) (bool, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	if err := checkUIDs(uid); err != nil {
+		return false, err
+	}
+	if !predicateName.MatchString(predicate) {
+		return false, fmt.Errorf("invalid predicate %q", predicate)
+	}
+
+	client, err := c.pool.get()
+	if err != nil {
+		return false, err
+	}
+	defer c.pool.put(client)
+
+	query := fmt.Sprintf("query q($pwd: string) {\n  q(func: uid(%s)) {\n    ok: checkpwd(%s, $pwd)\n  }\n}\n",
+		uid, predicate)
+	txn := dg.NewReadOnlyTxnContext(ctx, client)
+	resp, err := txn.Txn().QueryWithVars(ctx, query, map[string]string{"$pwd": candidate})
+	if err != nil {
+		return false, fmt.Errorf("checking password %s: %w", predicate, err)
+	}
+	var result struct {
+		Q []struct {
+			OK bool `json:"ok"`
+		} `json:"q"`
+	}
+	if err := json.Unmarshal(resp.Json, &result); err != nil {
+		return false, err
+	}
+	return len(result.Q) > 0 && result.Q[0].OK, nil
+}
+
+// SetPassword implements storing password as the value of the password
+// predicate of the node uid, after checking the schema so that it is hashed.
+func (c client) SetPassword(ctx context.Context, uid, predicate, password string) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	if err := checkUIDs(uid); err != nil {
+		return err
+	}
+	schema, err := c.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+	if p, ok := schema.Predicate(predicate); !ok || p.Type != "password" {
+		return fmt.Errorf("%w: %s", ErrNotPassword, predicate)
+	}
+	data, err := json.Marshal(map[string]string{"uid": uid, predicate: password})
+	if err != nil {
+		return err
+	}
+	dgo, cleanup, err := c.DgraphClient()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	if _, err := dgo.NewTxn().Mutate(ctx, &api.Mutation{SetJson: data, CommitNow: true}); err != nil {
+		return fmt.Errorf("setting password %s: %w", predicate, err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type PasswordUser struct {
+	UID      string   `json:"uid,omitempty"`
+	Name     string   `json:"pw_name,omitempty" dgraph:"index=exact"`
+	Password string   `json:"pw_password,omitempty" dgraph:"type=password"`
+	DType    []string `json:"dgraph.type,omitempty"`
+}
+
+func TestCheckPassword(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "CheckPasswordWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "CheckPasswordWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+			require.NoError(t, client.UpdateSchema(ctx, &PasswordUser{}))
+
+			schema, err := client.GetSchema(ctx)
+			require.NoError(t, err)
+			pred, ok := schema.Predicate("pw_password")
+			require.True(t, ok)
+			require.Equal(t, "password", pred.Type)
+
+			ada := &PasswordUser{Name: "ada", Password: "analytical-engine"}
+			require.NoError(t, client.Insert(ctx, ada))
+
+			// The password is never read back.
+			var got PasswordUser
+			require.NoError(t, client.Get(ctx, &got, ada.UID))
+			require.Equal(t, "ada", got.Name)
+			require.Empty(t, got.Password)
+			var users []PasswordUser
+			require.NoError(t, client.Query(ctx, PasswordUser{}).Nodes(&users))
+			require.Len(t, users, 1)
+			require.Empty(t, users[0].Password)
+
+			match, err := client.CheckPassword(ctx, ada.UID, "pw_password", "analytical-engine")
+			require.NoError(t, err)
+			require.True(t, match)
+			match, err = client.CheckPassword(ctx, ada.UID, "pw_password", `difference" engine`)
+			require.NoError(t, err)
+			require.False(t, match)
+
+			// Updates that leave the field empty keep the stored password.
+			got.Name = "ada lovelace"
+			require.NoError(t, client.Update(ctx, &got))
+			match, err = client.CheckPassword(ctx, ada.UID, "pw_password", "analytical-engine")
+			require.NoError(t, err)
+			require.True(t, match)
+
+			got.Password = "difference-engine"
+			require.NoError(t, client.Update(ctx, &got))
+			match, err = client.CheckPassword(ctx, ada.UID, "pw_password", "analytical-engine")
+			require.NoError(t, err)
+			require.False(t, match)
+			match, err = client.CheckPassword(ctx, ada.UID, "pw_password", "difference-engine")
+			require.NoError(t, err)
+			require.True(t, match)
+
+			// Nodes without a password never match.
+			grace := &PasswordUser{Name: "grace"}
+			require.NoError(t, client.Insert(ctx, grace))
+			match, err = client.CheckPassword(ctx, grace.UID, "pw_password", "")
+			require.NoError(t, err)
+			require.False(t, match)
+
+			// SetPassword writes only the password, and only to a password
+			// predicate.
+			require.NoError(t, client.SetPassword(ctx, grace.UID, "pw_password", "cobol-compiler"))
+			match, err = client.CheckPassword(ctx, grace.UID, "pw_password", "cobol-compiler")
+			require.NoError(t, err)
+			require.True(t, match)
+			require.NoError(t, client.Get(ctx, &got, grace.UID))
+			require.Equal(t, "grace", got.Name)
+			require.ErrorIs(t, client.SetPassword(ctx, grace.UID, "pw_name", "cobol-compiler"), mg.ErrNotPassword)
+			require.ErrorIs(t, client.SetPassword(ctx, grace.UID, "pw_missing", "cobol-compiler"), mg.ErrNotPassword)
+
+			_, err = client.CheckPassword(ctx, ada.UID, "pw_password) { uid }", "x")
+			require.ErrorContains(t, err, "invalid predicate")
+			_, err = client.CheckPassword(ctx, "ada", "pw_password", "x")
+			require.ErrorContains(t, err, "invalid uid")
+		})
+	}
+}
@@ -0,0 +1,207 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// PathOptions configures Paths. The zero value finds the shortest path over
+// every uid predicate.
+type PathOptions struct {
+	// Predicates are the edges paths may follow. A ~ prefix follows a
+	// @reverse predicate backwards. It defaults to every uid predicate in the
+	// schema.
+	Predicates []string
+
+	// MaxDepth caps the number of hops in a path; 0 leaves it to Dgraph.
+	MaxDepth int
+
+	// MaxPaths caps the number of paths returned. It defaults to 1, the
+	// shortest path.
+	MaxPaths int
+
+	// WeightPredicate is the edge facet weighing each hop, such as the edge
+	// properties the Neo4j and SQL importers store. When it is set, edges
+	// without the facet are not followed; when empty, every hop weighs 1.
+	WeightPredicate string
+}
+
+// Path is one route found by Paths. Weight is the sum of its segments'.
+type Path struct {
+	Weight   float64
+	Segments []PathSegment
+}
+
+// PathSegment is one hop of a Path: the edge Predicate from node From to
+// node To. The types are the nodes' first dgraph.type, empty when untyped.
+type PathSegment struct {
+	From      string
+	FromType  string
+	Predicate string
+	To        string
+	ToType    string
+	Weight    float64
+}
+
+// Paths returns up to opts.MaxPaths loop-free paths from node from to node
+// to, lightest first, using Dgraph's k-shortest-path search. It returns no
+// paths when to cannot be reached. Use it for lineage and dependency
+// questions such as every way one package depends on another:
+//
+//	paths, err := client.Paths(ctx, app, lib, modusgraph.PathOptions{
+//	    Predicates: []string{"depends_on"},
+//	    MaxPaths:   100,
+//	})
+func (c client) Paths(ctx context.Context, from, to string, opts PathOptions) ([]Path, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	fromUID, err := ParseUID(from)
+	if err != nil {
+		return nil, err
+	}
+	toUID, err := ParseUID(to)
+	if err != nil {
+		return nil, err
+	}
+	preds := opts.Predicates
+	if len(preds) == 0 {
+		schema, err := c.GetSchema(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range schema.Predicates {
+			if p.Type == "uid" && !strings.HasPrefix(p.Name, "dgraph.") {
+				preds = append(preds, p.Name)
+			}
+		}
+		if len(preds) == 0 {
+			return nil, errors.New("modusgraph: the schema has no uid predicates to find paths over")
+		}
+	}
+
+	args := fmt.Sprintf("from: %s, to: %s, numpaths: %d", fromUID, toUID, max(opts.MaxPaths, 1))
+	if opts.MaxDepth > 0 {
+		args += fmt.Sprintf(", depth: %d", opts.MaxDepth)
+	}
+	var edges strings.Builder
+	for _, p := range preds {
+		edges.WriteString(" <" + p + ">")
+		if opts.WeightPredicate != "" {
+			edges.WriteString(" @facets(" + opts.WeightPredicate + ")")
+		}
+	}
+	data, err := c.QueryRaw(ctx, fmt.Sprintf("{ shortest(%s) {%s } }", args, edges.String()), nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Path []map[string]any `json:"_path_"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	paths := make([]Path, 0, len(resp.Path))
+	for _, raw := range resp.Path {
+		path, err := decodePath(raw, preds, opts.WeightPredicate)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	if err := c.typePathNodes(ctx, paths); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// typePathNodes fills in the node types of the segments of paths.
+func (c client) typePathNodes(ctx context.Context, paths []Path) error {
+	var uids []string
+	for _, p := range paths {
+		for _, s := range p.Segments {
+			uids = append(uids, s.From, s.To)
+		}
+	}
+	if len(uids) == 0 {
+		return nil
+	}
+	slices.Sort(uids)
+	uids = slices.Compact(uids)
+	data, err := c.QueryRaw(ctx,
+		fmt.Sprintf("{ nodes(func: uid(%s)) { uid dgraph.type } }", strings.Join(uids, ", ")), nil)
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Nodes []struct {
+			UID   string   `json:"uid"`
+			DType []string `json:"dgraph.type"`
+		} `json:"nodes"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return err
+	}
+	types := make(map[string]string, len(resp.Nodes))
+	for _, n := range resp.Nodes {
+		if len(n.DType) > 0 {
+			types[n.UID] = n.DType[0]
+		}
+	}
+	for _, p := range paths {
+		for i := range p.Segments {
+			p.Segments[i].FromType = types[p.Segments[i].From]
+			p.Segments[i].ToType = types[p.Segments[i].To]
+		}
+	}
+	return nil
+}
+
+// decodePath walks one _path_ entry of a shortest query, in which each node
+// holds the next under the predicate followed and the weight of that hop as
+// a "<predicate>|<facet>" member of the next node.
+func decodePath(node map[string]any, preds []string, facet string) (Path, error) {
+	var path Path
+	path.Weight, _ = node["_weight_"].(float64)
+	for {
+		from, _ := node["uid"].(string)
+		var (
+			pred string
+			next map[string]any
+		)
+		for _, p := range preds {
+			if n, ok := node[p].(map[string]any); ok {
+				pred, next = p, n
+				break
+			}
+		}
+		if next == nil {
+			return path, nil
+		}
+		to, _ := next["uid"].(string)
+		if from == "" || to == "" {
+			return Path{}, fmt.Errorf("modusgraph: unexpected path in response: %v", node)
+		}
+		weight := 1.0
+		if facet != "" {
+			if w, ok := next[pred+"|"+facet].(float64); ok {
+				weight = w
+			}
+		}
+		path.Segments = append(path.Segments, PathSegment{
+			From:      from,
+			Predicate: pred,
+			To:        to,
+			Weight:    weight,
+		})
+		node = next
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaths(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "PathsWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "PathsWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, err := mg.NewClient(tc.uri)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = client.DropAll(context.Background())
+				client.Close()
+				mg.Shutdown()
+			})
+			ctx := context.Background()
+
+			// app depends on lib directly (cost 5), through util (1.5 + 1)
+			// and through a vendored copy, whose vendors edge has no cost.
+			// lib depends back on app, which no path may loop through.
+			dg, cleanup, err := client.DgraphClient()
+			require.NoError(t, err)
+			defer cleanup()
+			require.NoError(t, dg.Alter(ctx, &api.Operation{Schema: `
+				name: string @index(exact) .
+				depends_on: [uid] @reverse .
+				vendors: [uid] .
+				type Package {
+					name
+					depends_on
+					vendors
+				}
+				type Vendored {
+					name
+					depends_on
+				}
+			`}))
+			resp, err := dg.NewTxn().Mutate(ctx, &api.Mutation{CommitNow: true, SetNquads: []byte(`
+				_:app <name> "app" .
+				_:app <dgraph.type> "Package" .
+				_:util <name> "util" .
+				_:util <dgraph.type> "Package" .
+				_:lib <name> "lib" .
+				_:lib <dgraph.type> "Package" .
+				_:copy <name> "lib-copy" .
+				_:copy <dgraph.type> "Vendored" .
+				_:app <depends_on> _:lib (cost=5) .
+				_:app <depends_on> _:util (cost=1.5) .
+				_:util <depends_on> _:lib (cost=1) .
+				_:app <vendors> _:copy .
+				_:copy <depends_on> _:lib (cost=0.5) .
+				_:lib <depends_on> _:app (cost=1) .
+			`)})
+			require.NoError(t, err)
+			app, util, lib, vendored := resp.Uids["app"], resp.Uids["util"], resp.Uids["lib"], resp.Uids["copy"]
+
+			// By default the fewest hops win, over every uid predicate.
+			paths, err := client.Paths(ctx, app, lib, mg.PathOptions{})
+			require.NoError(t, err)
+			require.Len(t, paths, 1)
+			require.Equal(t, 1.0, paths[0].Weight)
+			require.Equal(t, []mg.PathSegment{{
+				From: app, FromType: "Package", Predicate: "depends_on", To: lib, ToType: "Package", Weight: 1,
+			}}, paths[0].Segments)
+
+			// Every path comes back, shortest first.
+			paths, err = client.Paths(ctx, app, lib, mg.PathOptions{
+				Predicates: []string{"depends_on", "vendors"},
+				MaxPaths:   10,
+			})
+			require.NoError(t, err)
+			require.Len(t, paths, 3)
+			require.Equal(t, []float64{1, 2, 2}, []float64{paths[0].Weight, paths[1].Weight, paths[2].Weight})
+			vendorPath := paths[1]
+			if vendorPath.Segments[0].Predicate != "vendors" {
+				vendorPath = paths[2]
+			}
+			require.Equal(t, []mg.PathSegment{
+				{From: app, FromType: "Package", Predicate: "vendors", To: vendored, ToType: "Vendored", Weight: 1},
+				{From: vendored, FromType: "Vendored", Predicate: "depends_on", To: lib, ToType: "Package", Weight: 1},
+			}, vendorPath.Segments)
+
+			// Weighted, the lightest path comes first, and edges without the
+			// facet are not followed.
+			paths, err = client.Paths(ctx, app, lib, mg.PathOptions{
+				Predicates:      []string{"depends_on", "vendors"},
+				MaxPaths:        10,
+				WeightPredicate: "cost",
+			})
+			require.NoError(t, err)
+			require.Len(t, paths, 2)
+			require.Equal(t, 2.5, paths[0].Weight)
+			require.Equal(t, []mg.PathSegment{
+				{From: app, FromType: "Package", Predicate: "depends_on", To: util, ToType: "Package", Weight: 1.5},
+				{From: util, FromType: "Package", Predicate: "depends_on", To: lib, ToType: "Package", Weight: 1},
+			}, paths[0].Segments)
+			require.Equal(t, 5.0, paths[1].Weight)
+			require.Len(t, paths[1].Segments, 1)
+
+			// MaxDepth drops the longer paths, and reverse edges can be
+			// followed backwards.
+			paths, err = client.Paths(ctx, app, lib, mg.PathOptions{
+				Predicates:      []string{"depends_on", "vendors"},
+				MaxPaths:        10,
+				MaxDepth:        1,
+				WeightPredicate: "cost",
+			})
+			require.NoError(t, err)
+			require.Len(t, paths, 1)
+			require.Equal(t, 5.0, paths[0].Weight)
+			paths, err = client.Paths(ctx, util, app, mg.PathOptions{Predicates: []string{"~depends_on"}})
+			require.NoError(t, err)
+			require.Len(t, paths, 1)
+			require.Equal(t, "~depends_on", paths[0].Segments[0].Predicate)
+
+			// Unreachable nodes have no paths.
+			paths, err = client.Paths(ctx, util, vendored, mg.PathOptions{Predicates: []string{"depends_on"}})
+			require.NoError(t, err)
+			require.Empty(t, paths)
+
+			_, err = client.Paths(ctx, "app", lib, mg.PathOptions{})
+			require.ErrorIs(t, err, mg.ErrInvalidUID)
+		})
+	}
+}
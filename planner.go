@@ -0,0 +1,446 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dgraph-io/dgraph/v25/schema"
+	"github.com/dgraph-io/dgraph/v25/x"
+	"github.com/go-logr/logr"
+)
+
+// QueryHint steers how the embedded backend plans queries. Pass hints to the
+// client with WithHint.
+type QueryHint struct {
+	preferIndex string
+}
+
+// PreferIndex hints that a filter on predicate should drive the query: when a
+// query block roots at type(T) and its @filter ANDs in an indexed function on
+// predicate (eq, le, ge, lt, gt, between, the term/text matchers, regexp,
+// match, or a geo function), the embedded planner roots the block at that
+// function instead and moves type(T) into the filter. The result set is the
+// same; Dgraph resolves it through the predicate's index rather than scanning
+// every node of the type.
+//
+// The hint is ignored unless one of the predicate's indexes serves the
+// function at the root (a hash, exact or term index for eq, a sortable one
+// for the inequalities, trigram for regexp and match, term or fulltext for
+// the matchers, geo for the geo functions), so a stale hint can never turn a
+// working query into an error.
+func PreferIndex(predicate string) QueryHint {
+	return QueryHint{preferIndex: predicate}
+}
+
+// hintsKey renders the configured hints for the client dedup key.
+func hintsKey(hints []QueryHint) string {
+	preds := make([]string, 0, len(hints))
+	for _, h := range hints {
+		preds = append(preds, h.preferIndex)
+	}
+	sort.Strings(preds)
+	return strings.Join(preds, ",")
+}
+
+// sortableTokenizers are the index tokenizers that order values, which
+// Dgraph needs to evaluate an inequality at the root.
+var sortableTokenizers = []string{"exact", "int", "float", "bigfloat", "year", "month", "day", "hour"}
+
+// rootFuncTokenizers maps the filter functions Dgraph can evaluate at the
+// root through an index to the tokenizers that serve them.
+var rootFuncTokenizers = map[string][]string{
+	"eq":         append([]string{"hash", "term", "bool"}, sortableTokenizers...),
+	"le":         sortableTokenizers,
+	"ge":         sortableTokenizers,
+	"lt":         sortableTokenizers,
+	"gt":         sortableTokenizers,
+	"between":    sortableTokenizers,
+	"allofterms": {"term"},
+	"anyofterms": {"term"},
+	"alloftext":  {"fulltext"},
+	"anyoftext":  {"fulltext"},
+	"regexp":     {"trigram"},
+	"match":      {"trigram"},
+	"near":       {"geo"},
+	"within":     {"geo"},
+	"contains":   {"geo"},
+	"intersects": {"geo"},
+}
+
+// queryPlanner rewrites type-rooted query blocks per PreferIndex hints and
+// reports full type scans in debug mode, with any PredicateStats gathered
+// for the predicates filtered on. A nil *queryPlanner is a no-op.
+type queryPlanner struct {
+	prefer     map[string]bool
+	debug      bool
+	logger     logr.Logger
+	tokenizers func(predicate string) []string
+	stats      func(predicate string) (PredicateStats, bool)
+}
+
+func newQueryPlanner(o clientOptions, tokenizers func(string) []string,
+	stats func(string) (PredicateStats, bool)) *queryPlanner {
+	if len(o.queryHints) == 0 && !o.queryPlanDebug {
+		return nil
+	}
+	p := &queryPlanner{
+		prefer:     make(map[string]bool, len(o.queryHints)),
+		debug:      o.queryPlanDebug,
+		logger:     o.logger,
+		tokenizers: tokenizers,
+		stats:      stats,
+	}
+	for _, h := range o.queryHints {
+		if h.preferIndex != "" {
+			p.prefer[h.preferIndex] = true
+		}
+	}
+	return p
+}
+
+// plan returns q with every eligible block rewritten.
+func (p *queryPlanner) plan(q string) string {
+	if p == nil {
+		return q
+	}
+	var out strings.Builder
+	rest := q
+	for {
+		i := indexOutsideLiterals(rest, "(func:")
+		if i < 0 {
+			out.WriteString(rest)
+			return out.String()
+		}
+		out.WriteString(rest[:i])
+		consumed, rewritten := p.planBlock(rest[i:])
+		out.WriteString(rewritten)
+		rest = rest[i+consumed:]
+	}
+}
+
+// planBlock plans the block whose root begins at s ("(func: ..."). It returns
+// how many bytes of s it consumed and their replacement.
+func (p *queryPlanner) planBlock(s string) (int, string) {
+	rootEnd := matchParen(s, 0)
+	if rootEnd < 0 {
+		return len(s), s
+	}
+	rootArgs := s[len("(func:"):rootEnd]
+	rootFunc, rootTail := splitFirstArg(rootArgs)
+	typeName, ok := typeRoot(rootFunc)
+	if !ok {
+		return rootEnd + 1, s[:rootEnd+1]
+	}
+
+	after := s[rootEnd+1:]
+	trimmed := strings.TrimLeft(after, " \t\n")
+	if !strings.HasPrefix(trimmed, "@filter(") {
+		return rootEnd + 1, s[:rootEnd+1]
+	}
+	filterStart := rootEnd + 1 + (len(after) - len(trimmed))
+	filterEnd := matchParen(s, filterStart+len("@filter"))
+	if filterEnd < 0 {
+		return rootEnd + 1, s[:rootEnd+1]
+	}
+	filter := s[filterStart+len("@filter(") : filterEnd]
+	conjuncts, ok := splitAnd(filter)
+	consumed := filterEnd + 1
+
+	if ok {
+		for i, c := range conjuncts {
+			fn, pred := funcAndPredicate(c)
+			if !p.prefer[pred] || !p.servesRoot(fn, pred) {
+				continue
+			}
+			rest := append([]string{"type(" + typeName + ")"}, slices.Delete(slices.Clone(conjuncts), i, i+1)...)
+			p.logger.V(2).Info("Query planner rooted block at preferred index",
+				"type", typeName, "predicate", pred)
+			return consumed, "(func: " + c + rootTail + ") @filter(" + strings.Join(rest, " AND ") + ")"
+		}
+	}
+
+	if p.debug && scansType(conjuncts, ok) {
+		kv := []any{"type", typeName, "filter", strings.TrimSpace(filter)}
+		if stats := p.filterStats(conjuncts); len(stats) > 0 {
+			kv = append(kv, "predicates", stats)
+		}
+		p.logger.Info("Query fell back to a full type scan", kv...)
+	}
+	return consumed, s[:consumed]
+}
+
+// namespaceTokenizers returns the index tokenizers of a predicate in ns's
+// schema, as seen by the embedded engine.
+func namespaceTokenizers(ns *Namespace) func(string) []string {
+	return func(pred string) []string {
+		var names []string
+		for _, t := range schema.State().Tokenizer(context.Background(), x.NamespaceAttr(ns.ID(), pred)) {
+			names = append(names, t.Name())
+		}
+		return names
+	}
+}
+
+// servesRoot reports whether an index of pred lets Dgraph evaluate fn at the
+// root of a block.
+func (p *queryPlanner) servesRoot(fn, pred string) bool {
+	serving, ok := rootFuncTokenizers[fn]
+	if !ok {
+		return false
+	}
+	if p.tokenizers == nil {
+		return true
+	}
+	return slices.ContainsFunc(p.tokenizers(pred), func(t string) bool { return slices.Contains(serving, t) })
+}
+
+// filterStats summarizes the gathered statistics of the predicates a block's
+// filter conjuncts test.
+func (p *queryPlanner) filterStats(conjuncts []string) []string {
+	if p.stats == nil {
+		return nil
+	}
+	var out []string
+	for _, c := range conjuncts {
+		_, pred := funcAndPredicate(c)
+		if s, ok := p.stats(pred); ok && pred != "" {
+			out = append(out, s.String())
+		}
+	}
+	return out
+}
+
+// scansType reports whether a type-rooted block's filter constrains anything
+// beyond the has(dgraph.type) guard dgman always adds — that is, whether the
+// type scan is doing filtering work an index could have done.
+func scansType(conjuncts []string, split bool) bool {
+	if !split {
+		return true
+	}
+	for _, c := range conjuncts {
+		if strings.ReplaceAll(c, " ", "") != "has(dgraph.type)" {
+			return true
+		}
+	}
+	return false
+}
+
+// typeRoot reports whether a root function is type(Name).
+func typeRoot(f string) (string, bool) {
+	f = strings.TrimSpace(f)
+	if !strings.HasPrefix(f, "type(") || !strings.HasSuffix(f, ")") {
+		return "", false
+	}
+	name := strings.TrimSpace(f[len("type(") : len(f)-1])
+	if name == "" || strings.ContainsAny(name, "(), ") {
+		return "", false
+	}
+	return name, true
+}
+
+// funcAndPredicate splits a conjunct of the form fn(pred, ...) into the
+// lower-cased function name and its predicate, with any @lang suffix removed.
+// It returns empty strings for anything else (NOT terms, nested groups).
+func funcAndPredicate(c string) (string, string) {
+	open := strings.IndexByte(c, '(')
+	if open <= 0 || matchParen(c, open) != len(c)-1 {
+		return "", ""
+	}
+	fn := strings.ToLower(strings.TrimSpace(c[:open]))
+	if strings.ContainsAny(fn, " \t") {
+		return "", ""
+	}
+	pred, _ := splitFirstArg(c[open+1 : len(c)-1])
+	pred = strings.TrimSpace(pred)
+	if at := strings.IndexByte(pred, '@'); at >= 0 {
+		pred = pred[:at]
+	}
+	return fn, pred
+}
+
+// splitAnd splits a filter expression into its top-level AND conjuncts,
+// flattening parenthesized groups that are themselves pure conjunctions. It
+// returns ok=false when the expression has a top-level OR (or NOT), since no
+// single conjunct can then be hoisted to the root.
+func splitAnd(expr string) ([]string, bool) {
+	expr = strings.TrimSpace(expr)
+	parts, ok := splitTopLevel(expr)
+	if !ok {
+		return nil, false
+	}
+	var out []string
+	for _, part := range parts {
+		if strings.HasPrefix(part, "(") && matchParen(part, 0) == len(part)-1 {
+			if inner, ok := splitAnd(part[1 : len(part)-1]); ok {
+				out = append(out, inner...)
+				continue
+			}
+		}
+		out = append(out, part)
+	}
+	return out, true
+}
+
+// splitTopLevel splits expr on depth-0 AND keywords, failing on OR or NOT.
+func splitTopLevel(expr string) ([]string, bool) {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; c {
+		case '"':
+			i = skipString(expr, i)
+		case '/':
+			if prevNonSpace(expr, i) == ',' {
+				i = skipRegex(expr, i)
+			}
+		case '(':
+			depth++
+		case ')':
+			depth--
+		default:
+			if depth != 0 || !wordBoundary(expr, i) {
+				continue
+			}
+			switch {
+			case keywordAt(expr, i, "and"):
+				parts = append(parts, strings.TrimSpace(expr[start:i]))
+				i += len("and") - 1
+				start = i + 1
+			case keywordAt(expr, i, "or"), keywordAt(expr, i, "not"):
+				return nil, false
+			}
+		}
+	}
+	parts = append(parts, strings.TrimSpace(expr[start:]))
+	for _, p := range parts {
+		if p == "" {
+			return nil, false
+		}
+	}
+	return parts, true
+}
+
+func wordBoundary(s string, i int) bool {
+	return i == 0 || !isIdentByte(s[i-1])
+}
+
+func keywordAt(s string, i int, kw string) bool {
+	if len(s)-i < len(kw) || !strings.EqualFold(s[i:i+len(kw)], kw) {
+		return false
+	}
+	end := i + len(kw)
+	return end == len(s) || !isIdentByte(s[end])
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '.' || c == '~' || c == '@' ||
+		(c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// splitFirstArg splits a comma-separated argument list at its first depth-0
+// comma, returning the first argument and the remainder (comma included).
+func splitFirstArg(args string) (string, string) {
+	depth := 0
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case '"':
+			i = skipString(args, i)
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				return args[:i], args[i:]
+			}
+		}
+	}
+	return args, ""
+}
+
+// matchParen returns the index of the parenthesis or brace closing the one
+// at s[open], skipping string and regex literals, or -1 if unbalanced.
+func matchParen(s string, open int) int {
+	close := byte(')')
+	if s[open] == '{' {
+		close = '}'
+	}
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			i = skipString(s, i)
+		case '/':
+			if prevNonSpace(s, i) == ',' {
+				i = skipRegex(s, i)
+			}
+		case s[open]:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// indexOutsideLiterals is strings.Index that ignores matches inside string
+// literals.
+func indexOutsideLiterals(s, substr string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' {
+			i = skipString(s, i)
+			continue
+		}
+		if strings.HasPrefix(s[i:], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// skipString returns the index of the quote closing the string literal that
+// opens at s[i], honoring backslash escapes.
+func skipString(s string, i int) int {
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '"':
+			return j
+		}
+	}
+	return len(s) - 1
+}
+
+// skipRegex returns the index of the slash closing the regex literal that
+// opens at s[i].
+func skipRegex(s string, i int) int {
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '/':
+			return j
+		}
+	}
+	return len(s) - 1
+}
+
+func prevNonSpace(s string, i int) byte {
+	for j := i - 1; j >= 0; j-- {
+		if s[j] != ' ' && s[j] != '\t' && s[j] != '\n' {
+			return s[j]
+		}
+	}
+	return 0
+}
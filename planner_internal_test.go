@@ -0,0 +1,132 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryPlannerPreferIndex(t *testing.T) {
+	tokenizers := map[string][]string{
+		"name": {"exact", "term", "trigram"},
+		"code": {"hash"},
+		"rank": {"int"},
+	}
+	p := newQueryPlanner(clientOptions{
+		logger: logr.Discard(),
+		queryHints: []QueryHint{PreferIndex("name"), PreferIndex("plain"),
+			PreferIndex("code"), PreferIndex("rank")},
+	}, func(pred string) []string { return tokenizers[pred] }, nil)
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			"Hoists hinted eq",
+			`{ q(func: type(Film), first: 2) @filter(has(dgraph.type) AND eq(name, "x")) { uid } }`,
+			`{ q(func: eq(name, "x"), first: 2) @filter(type(Film) AND has(dgraph.type)) { uid } }`,
+		},
+		{
+			"Flattens nested conjunction",
+			`{ q(func: type(Film)) @filter(has(dgraph.type) AND (le(year, 3) AND anyofterms(name, "a b"))) { uid } }`,
+			`{ q(func: anyofterms(name, "a b")) @filter(type(Film) AND has(dgraph.type) AND le(year, 3)) { uid } }`,
+		},
+		{
+			"Ignores top-level OR",
+			`{ q(func: type(Film)) @filter(eq(name, "x") OR eq(year, 1)) { uid } }`,
+			`{ q(func: type(Film)) @filter(eq(name, "x") OR eq(year, 1)) { uid } }`,
+		},
+		{
+			"Ignores unindexed hint",
+			`{ q(func: type(Film)) @filter(eq(plain, "x")) { uid } }`,
+			`{ q(func: type(Film)) @filter(eq(plain, "x")) { uid } }`,
+		},
+		{
+			"Hoists eq on a hash index",
+			`{ q(func: type(Film)) @filter(eq(code, "x")) { uid } }`,
+			`{ q(func: eq(code, "x")) @filter(type(Film)) { uid } }`,
+		},
+		{
+			"Ignores inequality on a hash index",
+			`{ q(func: type(Film)) @filter(lt(code, "m")) { uid } }`,
+			`{ q(func: type(Film)) @filter(lt(code, "m")) { uid } }`,
+		},
+		{
+			"Ignores regexp without a trigram index",
+			`{ q(func: type(Film)) @filter(regexp(code, /x/)) { uid } }`,
+			`{ q(func: type(Film)) @filter(regexp(code, /x/)) { uid } }`,
+		},
+		{
+			"Ignores term matcher without a term index",
+			`{ q(func: type(Film)) @filter(anyofterms(rank, "1999")) { uid } }`,
+			`{ q(func: type(Film)) @filter(anyofterms(rank, "1999")) { uid } }`,
+		},
+		{
+			"Ignores text matcher without a fulltext index",
+			`{ q(func: type(Film)) @filter(alloftext(name, "x")) { uid } }`,
+			`{ q(func: type(Film)) @filter(alloftext(name, "x")) { uid } }`,
+		},
+		{
+			"Hoists the first conjunct an index serves",
+			`{ q(func: type(Film)) @filter(lt(code, "m") AND gt(rank, 1990)) { uid } }`,
+			`{ q(func: gt(rank, 1990)) @filter(type(Film) AND lt(code, "m")) { uid } }`,
+		},
+		{
+			"Ignores unhinted predicate",
+			`{ q(func: type(Film)) @filter(eq(year, 1)) { uid } }`,
+			`{ q(func: type(Film)) @filter(eq(year, 1)) { uid } }`,
+		},
+		{
+			"Keyword inside string literal",
+			`{ q(func: type(Film)) @filter(eq(name, "a OR b) AND")) { uid } }`,
+			`{ q(func: eq(name, "a OR b) AND")) @filter(type(Film)) { uid } }`,
+		},
+		{
+			"Regex literal",
+			`{ q(func: type(Film)) @filter(regexp(name, /^a(nd|or)$/i)) { uid } }`,
+			`{ q(func: regexp(name, /^a(nd|or)$/i)) @filter(type(Film)) { uid } }`,
+		},
+		{
+			"Non-type root untouched",
+			`{ q(func: uid(0x1)) @filter(eq(name, "x")) { uid } }`,
+			`{ q(func: uid(0x1)) @filter(eq(name, "x")) { uid } }`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, p.plan(tt.input))
+		})
+	}
+}
+
+func TestQueryPlannerDebugReportsTypeScans(t *testing.T) {
+	var logged []string
+	logger := funcr.New(func(prefix, args string) {
+		logged = append(logged, args)
+	}, funcr.Options{})
+	p := newQueryPlanner(clientOptions{logger: logger, queryPlanDebug: true}, nil, nil)
+
+	p.plan(`{ q(func: type(Film)) @filter(has(dgraph.type)) { uid } }`)
+	require.Empty(t, logged, "the has(dgraph.type) guard alone is not a fallback")
+
+	p.plan(`{ q(func: type(Film)) @filter(has(dgraph.type) AND eq(year, 1)) { uid } }`)
+	require.Len(t, logged, 1)
+	require.Contains(t, logged[0], "full type scan")
+	require.Contains(t, logged[0], `"type"="Film"`)
+}
+
+func TestQueryPlannerNilIsNoop(t *testing.T) {
+	require.Nil(t, newQueryPlanner(clientOptions{}, nil, nil))
+	var p *queryPlanner
+	q := `{ q(func: type(Film)) @filter(eq(name, "x")) { uid } }`
+	require.Equal(t, q, p.plan(q))
+}
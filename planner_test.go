@@ -0,0 +1,117 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+// TestQueryHints checks that PreferIndex never changes query results, and that
+// the embedded backend's debug mode reports the type scans it could not avoid.
+func TestQueryHints(t *testing.T) {
+	testCases := []struct {
+		name     string
+		uri      string
+		embedded bool
+		skip     bool
+	}{
+		{
+			name:     "QueryHintsWithFileURI",
+			uri:      "file://" + GetTempDir(t),
+			embedded: true,
+		},
+		{
+			name: "QueryHintsWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			var mu sync.Mutex
+			var logged []string
+			logger := funcr.New(func(prefix, args string) {
+				mu.Lock()
+				defer mu.Unlock()
+				logged = append(logged, args)
+			}, funcr.Options{})
+
+			client, err := mg.NewClient(tc.uri,
+				mg.WithAutoSchema(true),
+				mg.WithLogger(logger),
+				mg.WithHint(mg.PreferIndex("name")),
+				mg.WithQueryPlanDebug(true))
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = client.DropAll(context.Background())
+				client.Close()
+				mg.Shutdown()
+			})
+
+			ctx := context.Background()
+			for i := range 5 {
+				require.NoError(t, client.Insert(ctx, &TestEntity{
+					Name:        fmt.Sprintf("hinted-%d", i),
+					Description: "hint test",
+					CreatedAt:   time.Now(),
+				}))
+			}
+
+			var found []TestEntity
+			err = client.Query(ctx, TestEntity{}).
+				Filter(`eq(name, "hinted-3") AND anyofterms(description, "hint")`).
+				Nodes(&found)
+			require.NoError(t, err)
+			require.Len(t, found, 1)
+			require.Equal(t, "hinted-3", found[0].Name)
+
+			err = client.Query(ctx, TestEntity{}).
+				Filter(`anyofterms(description, "hint")`).
+				Nodes(&found)
+			require.NoError(t, err)
+			require.Len(t, found, 5)
+
+			// name has no trigram index, so regexp cannot root the block and
+			// the hint must not hoist it.
+			err = client.Query(ctx, TestEntity{}).
+				Filter(`regexp(name, /^hinted-[0-2]$/)`).
+				Nodes(&found)
+			require.NoError(t, err)
+			require.Len(t, found, 3)
+
+			if !tc.embedded {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			var scans []string
+			for _, l := range logged {
+				if strings.Contains(l, "full type scan") {
+					scans = append(scans, l)
+				}
+			}
+			require.Len(t, scans, 2, "only the unhinted and unservable queries should scan the type")
+			require.Contains(t, scans[0], "anyofterms(description")
+			require.Contains(t, scans[1], "regexp(name")
+		})
+	}
+}
@@ -0,0 +1,239 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/dgraph-io/dgraph/v25/posting"
+	"github.com/dgraph-io/dgraph/v25/protos/pb"
+	"github.com/dgraph-io/dgraph/v25/worker"
+	"github.com/dgraph-io/dgraph/v25/x"
+)
+
+// statsPageSize is how many nodes a remote client reads per query while
+// gathering predicate statistics.
+const statsPageSize = 1000
+
+// PredicateStats describes how much data one predicate holds, for query
+// tuning. A high MaxValues marks a super-node, whose edges every traversal
+// through it must read; a predicate with many Nodes and no index is slow to
+// filter on.
+type PredicateStats struct {
+	Name    string
+	Type    string
+	Indexed bool
+
+	// Nodes is how many nodes hold a value of the predicate, and Values
+	// how many values (or edges) they hold between them.
+	Nodes  int64
+	Values int64
+
+	// MaxValues is the most values any one node holds, and MaxValuesNode
+	// that node's UID.
+	MaxValues     int64
+	MaxValuesNode string
+
+	// AvgValueSize is the mean size of a value in bytes: as stored for
+	// embedded clients, as encoded in JSON for remote ones. Edges count 8.
+	AvgValueSize float64
+
+	// IndexBytes is the stored size of the predicate's index, reverse and
+	// count keys. Only embedded clients report it.
+	IndexBytes int64
+}
+
+// PredicateStats gathers statistics on every predicate in the schema, sorted
+// by name. Embedded clients read them from storage; remote clients page
+// through every node holding each predicate, so on large databases it is a
+// maintenance task rather than something to call per request.
+//
+// On embedded clients the result also annotates the full type scans that
+// WithQueryPlanDebug reports, with the statistics of the predicates filtered
+// on, until PredicateStats is next called.
+func (c client) PredicateStats(ctx context.Context) ([]PredicateStats, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	schema, err := c.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]PredicateStats, 0, len(schema.Predicates))
+	for _, p := range schema.Predicates {
+		s := PredicateStats{Name: p.Name, Type: p.Type, Indexed: len(p.Indexes) > 0}
+		if c.engine != nil {
+			err = c.storedPredicateStats(ctx, &s)
+		} else {
+			err = c.remotePredicateStats(ctx, p, &s)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("modusgraph: predicate stats: %s: %w", p.Name, err)
+		}
+		stats = append(stats, s)
+	}
+	if c.engine != nil {
+		byName := make(map[string]PredicateStats, len(stats))
+		for _, s := range stats {
+			byName[s.Name] = s
+		}
+		c.engine.predicateStats.Store(c.ns.ID(), byName)
+	}
+	return stats, nil
+}
+
+// storedPredicateStats fills in s from the embedded store at the current read
+// timestamp.
+func (c client) storedPredicateStats(ctx context.Context, s *PredicateStats) error {
+	if err := c.engine.rlock(ctx); err != nil {
+		return err
+	}
+	defer c.engine.mutex.RUnlock()
+	if !c.engine.isOpen.Load() {
+		return ErrClosedEngine
+	}
+	readTs := c.engine.z.readTs()
+	attr := x.NamespaceAttr(c.ns.ID(), s.Name)
+	pk := x.ParsedKey{Attr: attr}
+
+	var size int64
+	err := posting.MemLayerInstance.IterateDisk(ctx, posting.IterateDiskArgs{
+		Prefix:         pk.DataPrefix(),
+		AllVersions:    true,
+		ReadTs:         readTs,
+		CheckInclusion: func(uint64) error { return nil },
+		Function: func(l *posting.List, key x.ParsedKey) error {
+			var n int64
+			err := l.Iterate(readTs, 0, func(p *pb.Posting) error {
+				n++
+				if s.Type == "uid" {
+					size += 8
+				} else {
+					size += int64(len(p.Value))
+				}
+				return nil
+			})
+			if n > 0 {
+				s.record(string(UIDFromUint64(key.Uid)), n)
+			}
+			return err
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if s.Values > 0 {
+		s.AvgValueSize = float64(size) / float64(s.Values)
+	}
+
+	txn := worker.State.Pstore.NewTransactionAt(readTs, false)
+	defer txn.Discard()
+	for _, prefix := range [][]byte{pk.IndexPrefix(), pk.ReversePrefix(), pk.CountPrefix(false), pk.CountPrefix(true)} {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Prefix = prefix
+		it := txn.NewIterator(opts)
+		for it.Rewind(); it.Valid(); it.Next() {
+			if item := it.Item(); !item.IsDeletedOrExpired() {
+				s.IndexBytes += item.EstimatedSize()
+			}
+		}
+		it.Close()
+	}
+	return nil
+}
+
+// remotePredicateStats fills in s by paging through the nodes holding p.
+func (c client) remotePredicateStats(ctx context.Context, p PredicateInfo, s *PredicateStats) error {
+	field := "<" + p.Name + ">"
+	if p.Type == "uid" {
+		field += " { uid }"
+	}
+	var size int64
+	after := "0x0"
+	for {
+		q := fmt.Sprintf("{ nodes(func: has(<%s>), first: %d, after: %s) { uid %s } }",
+			p.Name, statsPageSize, after, field)
+		data, err := c.QueryRaw(ctx, q, nil)
+		if err != nil {
+			return err
+		}
+		var resp struct {
+			Nodes []map[string]json.RawMessage `json:"nodes"`
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return err
+		}
+		for _, node := range resp.Nodes {
+			if err := json.Unmarshal(node["uid"], &after); err != nil {
+				return err
+			}
+			values := []json.RawMessage{node[p.Name]}
+			if p.List {
+				if err := json.Unmarshal(node[p.Name], &values); err != nil {
+					return err
+				}
+			}
+			for _, v := range values {
+				if p.Type == "uid" {
+					size += 8
+				} else {
+					size += int64(len(v))
+				}
+			}
+			s.record(after, int64(len(values)))
+		}
+		if len(resp.Nodes) < statsPageSize {
+			break
+		}
+	}
+	if s.Values > 0 {
+		s.AvgValueSize = float64(size) / float64(s.Values)
+	}
+	return nil
+}
+
+// record counts a node holding n values.
+func (s *PredicateStats) record(uid string, n int64) {
+	s.Nodes++
+	s.Values += n
+	if n > s.MaxValues {
+		s.MaxValues, s.MaxValuesNode = n, uid
+	}
+}
+
+// String summarizes the statistics on one line, as the query planner logs
+// them.
+func (s PredicateStats) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %d nodes, %d values (max %d", s.Name, s.Nodes, s.Values, s.MaxValues)
+	if s.MaxValuesNode != "" {
+		sb.WriteString(" on " + s.MaxValuesNode)
+	}
+	fmt.Fprintf(&sb, "), %.1f bytes per value", s.AvgValueSize)
+	if s.Indexed {
+		fmt.Fprintf(&sb, ", index %d bytes", s.IndexBytes)
+	} else {
+		sb.WriteString(", unindexed")
+	}
+	return sb.String()
+}
+
+// namespaceStats looks up the statistics last gathered by PredicateStats in
+// ns, as seen by the embedded engine.
+func namespaceStats(ns *Namespace) func(string) (PredicateStats, bool) {
+	return func(pred string) (PredicateStats, bool) {
+		v, ok := ns.engine.predicateStats.Load(ns.ID())
+		if !ok {
+			return PredicateStats{}, false
+		}
+		s, ok := v.(map[string]PredicateStats)[pred]
+		return s, ok
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPredicateStats(t *testing.T) {
+	var logged []string
+	logger := funcr.New(func(prefix, args string) {
+		logged = append(logged, args)
+	}, funcr.Options{})
+	c, err := NewClient("file://"+t.TempDir(), WithLogger(logger), WithQueryPlanDebug(true))
+	require.NoError(t, err)
+	defer c.Close()
+	ctx := context.Background()
+
+	dg, cleanup, err := c.DgraphClient()
+	require.NoError(t, err)
+	defer cleanup()
+	require.NoError(t, dg.Alter(ctx, &api.Operation{Schema: `
+		name: string @index(exact) .
+		year: int .
+		genre: [uid] @reverse .
+		type Film {
+			name
+			year
+			genre
+		}
+	`}))
+	// Every film links to the one genre, a super-node of ~genre; the first
+	// film also links to a second genre.
+	_, err = dg.NewTxn().Mutate(ctx, &api.Mutation{CommitNow: true, SetNquads: []byte(`
+		_:scifi <name> "Science fiction" .
+		_:horror <name> "Horror" .
+		_:alien <name> "Alien" .
+		_:alien <year> "1979" .
+		_:alien <dgraph.type> "Film" .
+		_:alien <genre> _:scifi .
+		_:alien <genre> _:horror .
+		_:heat <name> "Heat" .
+		_:heat <year> "1995" .
+		_:heat <dgraph.type> "Film" .
+		_:heat <genre> _:scifi .
+		_:solaris <name> "Solaris" .
+		_:solaris <dgraph.type> "Film" .
+		_:solaris <genre> _:scifi .
+	`)})
+	require.NoError(t, err)
+	var alien struct {
+		Q []struct {
+			UID string `json:"uid"`
+		} `json:"q"`
+	}
+	data, err := c.QueryRaw(ctx, `{ q(func: eq(name, "Alien")) { uid } }`, nil)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &alien))
+	require.Len(t, alien.Q, 1)
+
+	stats, err := c.PredicateStats(ctx)
+	require.NoError(t, err)
+	byName := map[string]PredicateStats{}
+	for _, s := range stats {
+		byName[s.Name] = s
+	}
+	genre := byName["genre"]
+	require.Equal(t, int64(3), genre.Nodes)
+	require.Equal(t, int64(4), genre.Values)
+	require.Equal(t, int64(2), genre.MaxValues)
+	require.Equal(t, alien.Q[0].UID, genre.MaxValuesNode)
+	require.Equal(t, 8.0, genre.AvgValueSize)
+	require.Positive(t, genre.IndexBytes, "the reverse edges count as index")
+
+	name := byName["name"]
+	require.True(t, name.Indexed)
+	require.Equal(t, int64(5), name.Nodes)
+	require.Positive(t, name.AvgValueSize)
+	require.Positive(t, name.IndexBytes)
+	year := byName["year"]
+	require.False(t, year.Indexed)
+	require.Equal(t, int64(2), year.Values)
+	require.Zero(t, year.IndexBytes)
+
+	// Remote clients count the same nodes and values.
+	for _, p := range []PredicateInfo{{Name: "genre", Type: "uid", List: true}, {Name: "year", Type: "int"}} {
+		var remote PredicateStats
+		require.NoError(t, c.(client).remotePredicateStats(ctx, p, &remote))
+		local := byName[p.Name]
+		require.Equal(t, []int64{local.Nodes, local.Values, local.MaxValues},
+			[]int64{remote.Nodes, remote.Values, remote.MaxValues})
+	}
+
+	// The query planner's report of a full type scan carries the statistics
+	// of the predicates filtered on.
+	logged = nil
+	_, err = c.QueryRaw(ctx, `{ q(func: type(Film)) @filter(eq(year, 1979)) { uid } }`, nil)
+	require.NoError(t, err)
+	var report string
+	for _, l := range logged {
+		if strings.Contains(l, "full type scan") {
+			report = l
+		}
+	}
+	require.Contains(t, report, "year: 2 nodes, 2 values", "logged: %v", logged)
+	require.Contains(t, report, "unindexed")
+}
@@ -0,0 +1,117 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Environment variables read by ClientConfigFromEnv. Only URIEnv is
+// required. Credentials and TLS settings ride on the URI's parameters, such
+// as apikey, bearertoken and sslmode.
+const (
+	URIEnv                    = "MODUSGRAPH_URI"
+	AutoSchemaEnv             = "MODUSGRAPH_AUTO_SCHEMA"
+	PoolSizeEnv               = "MODUSGRAPH_POOL_SIZE"
+	CacheSizeMBEnv            = "MODUSGRAPH_CACHE_SIZE_MB"
+	NamespaceEnv              = "MODUSGRAPH_NAMESPACE"
+	TimeoutEnv                = "MODUSGRAPH_TIMEOUT"
+	ReadOnlyEnv               = "MODUSGRAPH_READ_ONLY"
+	MaxConcurrentQueriesEnv   = "MODUSGRAPH_MAX_CONCURRENT_QUERIES"
+	MaxConcurrentMutationsEnv = "MODUSGRAPH_MAX_CONCURRENT_MUTATIONS"
+	ChangelogEnv              = "MODUSGRAPH_CHANGELOG"
+)
+
+// ClientConfig is what NewClient needs to open a client: its connection URI
+// and options.
+type ClientConfig struct {
+	URI  string
+	Opts []ClientOpt
+}
+
+// envOptions maps each optional environment variable to the option its
+// value sets.
+var envOptions = []struct {
+	name  string
+	parse func(string) (ClientOpt, error)
+}{
+	{AutoSchemaEnv, func(v string) (ClientOpt, error) {
+		b, err := strconv.ParseBool(v)
+		return WithAutoSchema(b), err
+	}},
+	{PoolSizeEnv, func(v string) (ClientOpt, error) {
+		n, err := strconv.Atoi(v)
+		return WithPoolSize(n), err
+	}},
+	{CacheSizeMBEnv, func(v string) (ClientOpt, error) {
+		n, err := strconv.Atoi(v)
+		return WithCacheSizeMB(n), err
+	}},
+	{NamespaceEnv, func(v string) (ClientOpt, error) { return WithNamespace(v), nil }},
+	{TimeoutEnv, func(v string) (ClientOpt, error) {
+		d, err := time.ParseDuration(v)
+		return WithTimeout(d), err
+	}},
+	{ReadOnlyEnv, func(v string) (ClientOpt, error) {
+		b, err := strconv.ParseBool(v)
+		if !b {
+			return func(*clientOptions) {}, err
+		}
+		return WithReadOnly(), err
+	}},
+	{MaxConcurrentQueriesEnv, func(v string) (ClientOpt, error) {
+		n, err := strconv.Atoi(v)
+		return WithMaxConcurrentQueries(n), err
+	}},
+	{MaxConcurrentMutationsEnv, func(v string) (ClientOpt, error) {
+		n, err := strconv.Atoi(v)
+		return WithMaxConcurrentMutations(n), err
+	}},
+	{ChangelogEnv, func(v string) (ClientOpt, error) { return WithChangelog(v), nil }},
+}
+
+// ClientConfigFromEnv reads a ClientConfig from the environment: the URI
+// from URIEnv and an option for each other variable that is set. Unset or
+// empty variables leave NewClient's defaults alone. Options the environment
+// cannot express, such as a logger or validator, can be appended to the
+// result's Opts before it is passed on.
+func ClientConfigFromEnv() (ClientConfig, error) {
+	cfg := ClientConfig{URI: os.Getenv(URIEnv)}
+	if cfg.URI == "" {
+		return ClientConfig{}, fmt.Errorf("modusgraph: %s is not set", URIEnv)
+	}
+	for _, e := range envOptions {
+		v := os.Getenv(e.name)
+		if v == "" {
+			continue
+		}
+		opt, err := e.parse(v)
+		if err != nil {
+			return ClientConfig{}, fmt.Errorf("modusgraph: %s: %w", e.name, err)
+		}
+		cfg.Opts = append(cfg.Opts, opt)
+	}
+	return cfg, nil
+}
+
+// ProvideClient opens the client cfg describes, returning with it a cleanup
+// function that closes it. Its signature is that of a Wire provider, so a
+// Wire injector can build a Client from the environment with
+//
+//	wire.Build(modusgraph.ClientConfigFromEnv, modusgraph.ProvideClient)
+//
+// With fx, provide ClientConfigFromEnv and register the cleanup as an
+// OnStop hook; see the README for the few lines involved.
+func ProvideClient(cfg ClientConfig) (Client, func(), error) {
+	client, err := NewClient(cfg.URI, cfg.Opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, client.Close, nil
+}
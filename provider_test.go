@@ -0,0 +1,45 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type ProviderFilm struct {
+	UID   string   `json:"uid,omitempty"`
+	Title string   `json:"pv_title,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+func TestClientConfigFromEnv(t *testing.T) {
+	t.Setenv(mg.URIEnv, "")
+	_, err := mg.ClientConfigFromEnv()
+	require.ErrorContains(t, err, mg.URIEnv+" is not set")
+
+	t.Setenv(mg.URIEnv, "file://"+GetTempDir(t))
+	t.Setenv(mg.PoolSizeEnv, "many")
+	_, err = mg.ClientConfigFromEnv()
+	require.ErrorContains(t, err, mg.PoolSizeEnv)
+
+	t.Setenv(mg.PoolSizeEnv, "")
+	t.Setenv(mg.AutoSchemaEnv, "true")
+	t.Setenv(mg.TimeoutEnv, "5s")
+	t.Setenv(mg.ReadOnlyEnv, "true")
+	cfg, err := mg.ClientConfigFromEnv()
+	require.NoError(t, err)
+	require.Len(t, cfg.Opts, 3)
+
+	client, cleanup, err := mg.ProvideClient(cfg)
+	require.NoError(t, err)
+	t.Cleanup(mg.Shutdown)
+	defer cleanup()
+	require.ErrorIs(t, client.Insert(context.Background(), &ProviderFilm{Title: "Alien"}), mg.ErrReadOnly)
+}
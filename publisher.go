@@ -0,0 +1,146 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Publisher delivers change events to a downstream system. Publish must
+// return nil only once every event is accepted; an error makes the
+// EventRouter retry the whole slice, so events may be delivered more than
+// once.
+type Publisher interface {
+	Publish(ctx context.Context, events []ChangeEvent) error
+	Close() error
+}
+
+// natsFlushTimeout bounds the wait for the server's acknowledgement when the
+// Publish context has no deadline of its own.
+const natsFlushTimeout = 10 * time.Second
+
+// NATSPublisher publishes each event as one NATS message.
+type NATSPublisher struct {
+	conn    *nats.Conn
+	subject string
+}
+
+// NewNATSPublisher connects to the NATS server at url. subject may contain
+// the placeholders {type} and {op}, so "graph.{type}.{op}" publishes an
+// inserted Film to "graph.Film.insert"; events without a type use "_".
+//
+// Each message's body is the event as JSON and its Nats-Msg-Id header is the
+// event's Seq, which lets a JetStream stream discard redeliveries.
+func NewNATSPublisher(url, subject string, opts ...nats.Option) (*NATSPublisher, error) {
+	if subject == "" {
+		return nil, fmt.Errorf("modusgraph: NATS publisher requires a subject")
+	}
+	conn, err := nats.Connect(url, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("modusgraph: connecting to NATS: %w", err)
+	}
+	return &NATSPublisher{conn: conn, subject: subject}, nil
+}
+
+// Publish sends events and waits for the server to acknowledge receipt.
+func (p *NATSPublisher) Publish(ctx context.Context, events []ChangeEvent) error {
+	for _, ev := range events {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		msg := nats.NewMsg(p.subjectFor(ev))
+		msg.Data = data
+		msg.Header.Set(nats.MsgIdHdr, strconv.FormatUint(ev.Seq, 10))
+		if err := p.conn.PublishMsg(msg); err != nil {
+			return err
+		}
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		return p.conn.FlushTimeout(natsFlushTimeout)
+	}
+	return p.conn.FlushWithContext(ctx)
+}
+
+func (p *NATSPublisher) subjectFor(ev ChangeEvent) string {
+	typ := ev.Type
+	if typ == "" {
+		typ = "_"
+	}
+	return strings.NewReplacer("{type}", typ, "{op}", string(ev.Op)).Replace(p.subject)
+}
+
+// Close drains and closes the connection.
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
+
+// WebhookOptions configures NewWebhookPublisher. Header is added to every
+// request, e.g. for an Authorization token. Client defaults to
+// http.DefaultClient.
+type WebhookOptions struct {
+	Header http.Header
+	Client *http.Client
+}
+
+// WebhookPublisher POSTs batches of events to an HTTP endpoint.
+type WebhookPublisher struct {
+	url    string
+	header http.Header
+	client *http.Client
+}
+
+// NewWebhookPublisher creates a publisher that POSTs each batch to url as a
+// JSON array of events. Any response other than 2xx is a failure, and the
+// batch is retried.
+func NewWebhookPublisher(url string, opts WebhookOptions) *WebhookPublisher {
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &WebhookPublisher{url: url, header: opts.Header, client: client}
+}
+
+// Publish POSTs events in one request.
+func (p *WebhookPublisher) Publish(ctx context.Context, events []ChangeEvent) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range p.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("modusgraph: webhook %s returned %s", p.url, resp.Status)
+	}
+	return nil
+}
+
+// Close is a no-op; WebhookPublisher holds no connection of its own.
+func (p *WebhookPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,128 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// includeDirective starts a line naming a file whose contents replace it.
+const includeDirective = "#include"
+
+// ReadQuery reads a DQL query from r, replacing each include directive, a
+// line of the form
+//
+//	#include "fragments/film.dql"
+//
+// with the named file's contents. Relative paths are resolved against dir for
+// r and against the including file's directory for nested includes. Since #
+// starts a DQL comment, a query file keeps parsing on its own. Shared
+// fragments and var blocks can so live in one file and be included by every
+// query that needs them.
+func ReadQuery(r io.Reader, dir string) (string, error) {
+	var sb strings.Builder
+	if err := expandQuery(&sb, r, dir, nil); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// ReadQueryFile reads the DQL query in the file at path, expanding its
+// include directives as ReadQuery does.
+func ReadQueryFile(path string) (string, error) {
+	var sb strings.Builder
+	if err := includeQueryFile(&sb, path, nil); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// QueryFile runs the DQL query in the file at path, with its include
+// directives expanded, binding vars to the query's variables. A var may be
+// named with or without its $:
+//
+//	resp, err := client.QueryFile(ctx, "queries/films.dql", map[string]string{"title": "Alien"})
+//
+// runs a file declaring query films($title: string).
+func (c client) QueryFile(ctx context.Context, path string, vars map[string]string) ([]byte, error) {
+	q, err := ReadQueryFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return c.QueryRaw(ctx, q, QueryVars(vars))
+}
+
+// QueryVars returns vars with each name prefixed by $ as DQL expects, unless
+// it already is.
+func QueryVars(vars map[string]string) map[string]string {
+	if len(vars) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(vars))
+	for name, v := range vars {
+		if !strings.HasPrefix(name, "$") {
+			name = "$" + name
+		}
+		out[name] = v
+	}
+	return out
+}
+
+// includeQueryFile writes the expanded contents of the file at path to sb.
+// stack holds the files being included, to reject include cycles.
+func includeQueryFile(sb *strings.Builder, path string, stack []string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(stack, abs) {
+		return fmt.Errorf("modusgraph: query include cycle: %s", strings.Join(append(stack, abs), " -> "))
+	}
+	f, err := os.Open(abs)
+	if err != nil {
+		return fmt.Errorf("modusgraph: query file: %w", err)
+	}
+	defer f.Close()
+	return expandQuery(sb, f, filepath.Dir(abs), append(stack, abs))
+}
+
+// expandQuery copies r to sb line by line, replacing include directives with
+// the files they name, resolved against dir.
+func expandQuery(sb *strings.Builder, r io.Reader, dir string, stack []string) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		rest, ok := strings.CutPrefix(strings.TrimSpace(text), includeDirective)
+		if !ok || rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			sb.WriteString(text)
+			sb.WriteByte('\n')
+			continue
+		}
+		name := strings.TrimSpace(rest)
+		if unquoted, err := strconv.Unquote(name); err == nil {
+			name = unquoted
+		}
+		if name == "" {
+			return fmt.Errorf("modusgraph: query line %d: %s names no file", line, includeDirective)
+		}
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(dir, name)
+		}
+		if err := includeQueryFile(sb, name, stack); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
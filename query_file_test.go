@@ -0,0 +1,113 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type QueryFileFilm struct {
+	UID   string   `json:"uid,omitempty"`
+	Title string   `json:"qf_title,omitempty" dgraph:"index=exact"`
+	Year  int      `json:"qf_year,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+func writeQueryFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestQueryFile(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "QueryFileWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "QueryFileWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+			require.NoError(t, client.Insert(ctx, []*QueryFileFilm{
+				{Title: "Alien", Year: 1979},
+				{Title: "Heat", Year: 1995},
+			}))
+
+			dir := writeQueryFiles(t, map[string]string{
+				"film.dql": `query film($title: string) {
+					#include "fragments/fields.dql"
+				}`,
+				"fragments/fields.dql": `q(func: eq(qf_title, $title)) {
+					#include "year.dql"
+				}`,
+				"fragments/year.dql": "qf_title qf_year",
+			})
+			resp, err := client.QueryFile(ctx, filepath.Join(dir, "film.dql"), map[string]string{"title": "Heat"})
+			require.NoError(t, err)
+			require.JSONEq(t, `{"q":[{"qf_title":"Heat","qf_year":1995}]}`, string(resp))
+
+			// Names already carrying their $ bind as-is.
+			resp, err = client.QueryFile(ctx, filepath.Join(dir, "film.dql"), map[string]string{"$title": "Alien"})
+			require.NoError(t, err)
+			require.JSONEq(t, `{"q":[{"qf_title":"Alien","qf_year":1979}]}`, string(resp))
+		})
+	}
+}
+
+func TestReadQuery(t *testing.T) {
+	dir := writeQueryFiles(t, map[string]string{
+		"fields.dql": "uid name\n",
+		"a.dql":      "#include b.dql\n",
+		"b.dql":      "#include \"a.dql\"\n",
+	})
+
+	// Queries read from standard input resolve includes against the given
+	// directory; other comments are left alone.
+	src := "{ q(func: has(name)) {\n  #include \"fields.dql\"\n" +
+		"  # #include is only a directive at the start of a line\n} }\n"
+	q, err := mg.ReadQuery(strings.NewReader(src), dir)
+	require.NoError(t, err)
+	require.Equal(t, "{ q(func: has(name)) {\nuid name\n  # #include is only a directive at the start of a line\n} }\n", q)
+
+	_, err = mg.ReadQueryFile(filepath.Join(dir, "a.dql"))
+	require.ErrorContains(t, err, "include cycle")
+	_, err = mg.ReadQuery(strings.NewReader("#include missing.dql\n"), dir)
+	require.ErrorIs(t, err, os.ErrNotExist)
+	_, err = mg.ReadQuery(strings.NewReader("#include\n"), dir)
+	require.ErrorContains(t, err, "names no file")
+
+	require.Equal(t, map[string]string{"$a": "1", "$b": "2"}, mg.QueryVars(map[string]string{"a": "1", "$b": "2"}))
+	require.Nil(t, mg.QueryVars(nil))
+}
@@ -0,0 +1,164 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	dg "github.com/dolan-in/dgman/v2"
+	"github.com/matthewmcneely/modusgraph/queryopt"
+)
+
+// With applies query options, the same ones typed.Query.With takes:
+//
+//	films, err := modusgraph.Query[Film](ctx, client).
+//	    With(queryopt.Desc("title"), queryopt.Page{Limit: 10}, queryopt.Depth(1)).
+//	    All()
+func (tq *TypedQuery[T]) With(opts ...queryopt.Option) *TypedQuery[T] {
+	if o := queryopt.Resolve(opts...); o.Page.Limit > 0 || o.Page.Offset > 0 {
+		tq.limit, tq.offset = o.Page.Limit, o.Page.Offset
+	}
+	var model T
+	tq.q = ApplyQueryOptions(tq.client, tq.q, &model, opts...)
+	return tq
+}
+
+// ApplyQueryOptions applies opts to q, a query for model returned by
+// c.Query, and returns it. It gives each option the one meaning every query
+// builder shares:
+//
+//   - Order adds an orderasc or orderdesc clause.
+//   - Page sets first and offset; Cursor sets after.
+//   - Depth re-expands the results that many levels of edges, instead of
+//     the client's WithMaxDepth.
+//   - Fields loads only the named predicates of each result, with edges
+//     among them expanded to the depth.
+//   - Language loads each string predicate of the results in the first
+//     language of the chain that has a value.
+//
+// Query builders call it; code that builds dgman queries by hand can too.
+func ApplyQueryOptions(c Client, q *dg.Query, model any, opts ...queryopt.Option) *dg.Query {
+	if q == nil {
+		return nil
+	}
+	model = UnwrapSchema(model)
+	o := queryopt.Resolve(opts...)
+	if o.DepthSet || len(o.Fields) > 0 || len(o.Language) > 0 {
+		depth, fanout := 10, 0
+		if cl, ok := c.(client); ok {
+			depth, fanout = cl.options.maxEdgeTraversal, cl.options.maxEdgeFanout
+		}
+		if o.DepthSet {
+			depth = int(o.Depth)
+		}
+		if len(o.Fields) == 0 && len(o.Language) == 0 {
+			q = expand(q, model, depth, fanout)
+		} else {
+			q = q.Query(selectFields(model, o, depth, fanout))
+		}
+	}
+	for _, ord := range o.Order {
+		if ord.Desc {
+			q = q.OrderDesc(ord.Predicate)
+		} else {
+			q = q.OrderAsc(ord.Predicate)
+		}
+	}
+	if o.Page.Limit > 0 {
+		q = q.First(o.Page.Limit)
+	}
+	if o.Page.Offset > 0 {
+		q = q.Offset(o.Page.Offset)
+	}
+	if o.Cursor != "" {
+		q = q.After(string(o.Cursor))
+	}
+	return q
+}
+
+// selectFields renders a query body loading o.Fields of model, or all of
+// its predicates when none are named, with string predicates in o.Language
+// and edges expanded depth levels.
+func selectFields(model any, o queryopt.Options, depth, fanout int) string {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	fields := make(map[string]reflect.StructField)
+	var all []string
+	if t != nil && t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			pred := fieldPredicate(sf)
+			if !sf.IsExported() || pred == "" || pred == "uid" || pred == "dgraph.type" || isFacetField(sf) {
+				continue
+			}
+			fields[pred] = sf
+			if !strings.HasPrefix(pred, "~") {
+				all = append(all, pred)
+			}
+		}
+	}
+	preds := []string(o.Fields)
+	if len(preds) == 0 {
+		preds = all
+	}
+
+	first := ""
+	if fanout > 0 {
+		first = " (first: " + strconv.Itoa(fanout) + ")"
+	}
+	var b strings.Builder
+	b.WriteString("{\n\t\tuid\n\t\tdgraph.type")
+	seen := make(map[string]bool)
+	for _, pred := range preds {
+		if seen[pred] {
+			continue
+		}
+		seen[pred] = true
+		sf, known := fields[pred]
+		edge := known && isEdgeType(sf.Type)
+		if edge && depth <= 0 {
+			continue
+		}
+		name := pred
+		if jsonName := strings.Split(sf.Tag.Get("json"), ",")[0]; known && jsonName != "" {
+			name = jsonName
+		}
+		b.WriteString("\n\t\t")
+		switch {
+		case edge:
+			if name != pred {
+				b.WriteString(name + ": ")
+			}
+			b.WriteString(pred + first + facetsSelector(sf.Type, name) +
+				" {\n\t\t\tuid\n\t\t\tdgraph.type\n\t\t\texpand(_all_)" + first)
+			writeExpandLevels(&b, depth-1, first)
+			b.WriteString("\n\t\t}")
+		case known && len(o.Language) > 0 && sf.Type.Kind() == reflect.String:
+			b.WriteString(name + ": " + LangPredicate(pred, o.Language...) + facetsSelector(t, name))
+		case name != pred:
+			b.WriteString(name + ": " + pred + facetsSelector(t, name))
+		default:
+			b.WriteString(pred + facetsSelector(t, name))
+		}
+	}
+	b.WriteString("\n\t}")
+	return b.String()
+}
+
+// isEdgeType reports whether a field of type t holds edges to other nodes:
+// it is a struct, or a pointer to or slice of one, other than a value type
+// such as time.Time or Decimal that decodes itself.
+func isEdgeType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(reflect.TypeFor[json.Unmarshaler]())
+}
@@ -0,0 +1,126 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/queryopt"
+	"github.com/stretchr/testify/require"
+)
+
+type OptDirector struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"od_name,omitempty" dgraph:"index=exact"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type OptFilm struct {
+	UID      string       `json:"uid,omitempty"`
+	Title    string       `json:"of_title,omitempty" dgraph:"index=exact lang"`
+	Year     int          `json:"of_year,omitempty" dgraph:"index=int"`
+	Director *OptDirector `json:"of_director,omitempty"`
+	DType    []string     `json:"dgraph.type,omitempty"`
+}
+
+func TestQueryOptions(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "QueryOptionsWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "QueryOptionsWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			ctx := context.Background()
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			for year := 2001; year <= 2005; year++ {
+				film := &OptFilm{Title: "Film", Year: year, Director: &OptDirector{Name: "Varda"}}
+				require.NoError(t, client.Insert(ctx, film))
+			}
+			years := func(films []OptFilm) []int {
+				var out []int
+				for _, f := range films {
+					out = append(out, f.Year)
+				}
+				return out
+			}
+
+			films, err := mg.Query[OptFilm](ctx, client).
+				With(queryopt.Desc("of_year"), queryopt.Page{Limit: 2, Offset: 1}).
+				All()
+			require.NoError(t, err)
+			require.Equal(t, []int{2004, 2003}, years(films))
+
+			films, err = mg.Query[OptFilm](ctx, client).
+				With(queryopt.PageNumber(2, 2), queryopt.Asc("of_year")).
+				All()
+			require.NoError(t, err)
+			require.Equal(t, []int{2003, 2004}, years(films))
+
+			// A cursor starts after the UID, in UID order.
+			all, err := mg.Query[OptFilm](ctx, client).All()
+			require.NoError(t, err)
+			require.Len(t, all, 5)
+			require.NotNil(t, all[0].Director)
+			films, err = mg.Query[OptFilm](ctx, client).With(queryopt.Cursor(all[1].UID)).All()
+			require.NoError(t, err)
+			require.Len(t, films, 3)
+			require.Equal(t, all[2].UID, films[0].UID)
+
+			// Depth 0 loads the films without their edges.
+			films, err = mg.Query[OptFilm](ctx, client).With(queryopt.Depth(0)).All()
+			require.NoError(t, err)
+			require.Len(t, films, 5)
+			require.Nil(t, films[0].Director)
+			require.Equal(t, "Film", films[0].Title)
+
+			// Fields loads only the named predicates, expanding edges.
+			films, err = mg.Query[OptFilm](ctx, client).With(queryopt.Fields{"of_title"}).All()
+			require.NoError(t, err)
+			require.Equal(t, "Film", films[0].Title)
+			require.Zero(t, films[0].Year)
+			require.Nil(t, films[0].Director)
+			films, err = mg.Query[OptFilm](ctx, client).
+				With(queryopt.Fields{"of_year", "of_director"}, queryopt.Depth(1)).
+				All()
+			require.NoError(t, err)
+			require.Empty(t, films[0].Title)
+			require.NotZero(t, films[0].Year)
+			require.NotNil(t, films[0].Director)
+			require.Equal(t, "Varda", films[0].Director.Name)
+
+			// Language falls back to the untagged value at the end of the chain.
+			q := mg.ApplyQueryOptions(client, client.Query(ctx, &OptFilm{}), &OptFilm{},
+				queryopt.Language{"fr", "."})
+			require.Contains(t, q.String(), "of_title: of_title@fr:.")
+			films, err = mg.Query[OptFilm](ctx, client).With(queryopt.Language{"fr", "."}).All()
+			require.NoError(t, err)
+			require.Equal(t, "Film", films[0].Title)
+			require.NotNil(t, films[0].Director)
+		})
+	}
+}
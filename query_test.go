@@ -402,8 +402,8 @@ func TestReverseEdgeQuery(t *testing.T) {
 
 			schema, err := client.GetSchema(ctx)
 			require.NoError(t, err)
-			require.Contains(t, schema, "type Student")
-			require.Contains(t, schema, "type Class")
+			require.Contains(t, schema.String(), "type Student")
+			require.Contains(t, schema.String(), "type Class")
 
 			// We cannot use the 'contructor' style querying because graph
 			// querying uses the `expand(_all_)` operator, which does not
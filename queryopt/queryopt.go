@@ -0,0 +1,123 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package queryopt defines the options a modusGraph query takes: its order,
+// page, cursor, language, depth and fields. Each is a value of its own type,
+// so a call site can build a set of options once and hand it to any query
+// builder that accepts them, the core modusgraph.TypedQuery and typed.Query
+// alike:
+//
+//	opts := []queryopt.Option{
+//	    queryopt.Desc("release_date"),
+//	    queryopt.Page{Limit: 20, Offset: 40},
+//	    queryopt.Language{"fr", "."},
+//	}
+//	films, err := modusgraph.Query[Film](ctx, client).With(opts...).All()
+//	films, err := typed.NewClient[Film](client).Query(ctx).With(opts...).Nodes()
+//
+// The package has no dependencies, so generated code can alias its types
+// without importing the client.
+package queryopt
+
+// Option is one query option. Only the types of this package implement it.
+type Option interface {
+	apply(*Options)
+}
+
+// Options is a resolved set of options, read by query builders. Orders and
+// Fields accumulate across options; the others overwrite, the last wins.
+type Options struct {
+	Order    []Order
+	Page     Page
+	Cursor   Cursor
+	Language Language
+	Depth    Depth
+	DepthSet bool // whether a Depth option was given; Depth(0) is meaningful
+	Fields   Fields
+}
+
+// Resolve applies opts in order and returns the result.
+func Resolve(opts ...Option) Options {
+	var o Options
+	for _, opt := range opts {
+		if opt != nil {
+			opt.apply(&o)
+		}
+	}
+	return o
+}
+
+// Order sorts results by a predicate. Several Orders sort by each in turn.
+type Order struct {
+	Predicate string
+	Desc      bool
+}
+
+// Asc orders results ascending by predicate.
+func Asc(predicate string) Order {
+	return Order{Predicate: predicate}
+}
+
+// Desc orders results descending by predicate.
+func Desc(predicate string) Order {
+	return Order{Predicate: predicate, Desc: true}
+}
+
+func (ord Order) apply(o *Options) {
+	if ord.Predicate != "" {
+		o.Order = append(o.Order, ord)
+	}
+}
+
+// Page bounds the results: Limit caps how many are returned, 0 for no cap,
+// and Offset skips that many first.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// PageNumber returns page number n, counting from 1, of size results each.
+func PageNumber(n, size int) Page {
+	return Page{Limit: size, Offset: max(n-1, 0) * size}
+}
+
+func (p Page) apply(o *Options) {
+	o.Page = p
+}
+
+// Cursor starts results after the node with this UID, in UID order, for
+// pagination that stays stable while nodes are added.
+type Cursor string
+
+func (c Cursor) apply(o *Options) {
+	o.Cursor = c
+}
+
+// Language loads each string predicate in the first language of the chain
+// that has a value; "." stands for the untagged value, or any language when
+// it is last.
+type Language []string
+
+func (l Language) apply(o *Options) {
+	o.Language = l
+}
+
+// Depth sets how many levels of edges the query follows below each result.
+// 0 loads only the results' own predicates.
+type Depth int
+
+func (d Depth) apply(o *Options) {
+	o.Depth = d
+	o.DepthSet = true
+}
+
+// Fields limits the query to the named predicates of each result, which
+// decode into their fields; the other fields are left unset. Name a managed
+// reverse edge with its ~ prefix.
+type Fields []string
+
+func (f Fields) apply(o *Options) {
+	o.Fields = append(o.Fields, f...)
+}
@@ -0,0 +1,60 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package queryopt_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/matthewmcneely/modusgraph/queryopt"
+)
+
+func TestResolve(t *testing.T) {
+	o := queryopt.Resolve(
+		queryopt.Asc("title"),
+		queryopt.Page{Limit: 10},
+		queryopt.Fields{"title"},
+		nil,
+		queryopt.Desc("year"),
+		queryopt.Asc(""),
+		queryopt.PageNumber(3, 20),
+		queryopt.Cursor("0x1"),
+		queryopt.Language{"fr", "."},
+		queryopt.Fields{"year"},
+	)
+	want := queryopt.Options{
+		Order:    []queryopt.Order{{Predicate: "title"}, {Predicate: "year", Desc: true}},
+		Page:     queryopt.Page{Limit: 20, Offset: 40},
+		Cursor:   "0x1",
+		Language: queryopt.Language{"fr", "."},
+		Fields:   queryopt.Fields{"title", "year"},
+	}
+	if !reflect.DeepEqual(o, want) {
+		t.Fatalf("Resolve = %+v, want %+v", o, want)
+	}
+
+	if o := queryopt.Resolve(queryopt.Depth(0)); !o.DepthSet || o.Depth != 0 {
+		t.Fatalf("Depth(0) resolved to %+v, want a set depth of 0", o)
+	}
+	if o := queryopt.Resolve(); o.DepthSet {
+		t.Fatalf("no options resolved to a set depth")
+	}
+}
+
+func TestPageNumber(t *testing.T) {
+	for _, tc := range []struct {
+		n, size int
+		want    queryopt.Page
+	}{
+		{1, 20, queryopt.Page{Limit: 20}},
+		{2, 20, queryopt.Page{Limit: 20, Offset: 20}},
+		{0, 5, queryopt.Page{Limit: 5}},
+	} {
+		if got := queryopt.PageNumber(tc.n, tc.size); got != tc.want {
+			t.Errorf("PageNumber(%d, %d) = %+v, want %+v", tc.n, tc.size, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/dgraph/v25/chunker"
+)
+
+// RDFOptions configures ExportRDF and ImportRDF.
+//
+// UIDs makes ExportRDF write nodes as their UIDs (<0x2a>), as Dgraph's own
+// export does, instead of as blank nodes (_:0x2a). Blank nodes load into any
+// database as new nodes; UIDs load only where they already exist, unless the
+// loader assigns new ones (--new_uids, or NewUIDs here).
+//
+// NewUIDs makes ImportRDF treat explicit UIDs in the data like blank nodes,
+// as LoadOptions.NewUIDs does. BatchSize is how many N-Quads ImportRDF
+// writes per mutation; it defaults to 1000.
+type RDFOptions struct {
+	UIDs      bool
+	NewUIDs   bool
+	BatchSize int
+}
+
+// rdfTypes maps schema types to the RDF literal types Dgraph's loaders read.
+// Values of untyped (default) predicates are written as plain literals.
+var rdfTypes = map[string]string{
+	"string":        "xs:string",
+	"int":           "xs:int",
+	"float":         "xs:float",
+	"bool":          "xs:boolean",
+	"datetime":      "xs:dateTime",
+	"geo":           "geo:geojson",
+	"bigfloat":      "xs:decimal",
+	"float32vector": "xs:[]float32",
+}
+
+// ExportRDF writes every node of the database to w as RDF N-Quads, one line
+// per value or edge, in the format Dgraph's live and bulk loaders read. Each
+// value carries its schema type (^^<xs:int>) or language tag, and facets
+// follow it in parentheses, as in Dgraph's own export. Write the schema
+// alongside with ExportSchema for the loaders' schema file.
+//
+// Password predicates cannot be read, so their values are not exported. Like
+// Dump, ExportRDF reads through one read-only transaction.
+func (c client) ExportRDF(ctx context.Context, w io.Writer, opts RDFOptions) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	schema, err := c.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+	dg, cleanup, err := c.DgraphClient()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	txn := dg.NewReadOnlyTxn()
+
+	node := func(alias string) string {
+		if opts.UIDs {
+			return "<" + alias + ">"
+		}
+		return "_:" + alias
+	}
+	bw := bufio.NewWriter(w)
+	preds := append([]PredicateInfo{{Name: "dgraph.type", Type: "string", List: true}}, schema.Predicates...)
+	for _, p := range preds {
+		if p.Type == "password" {
+			continue
+		}
+		_, err := dumpPredicate(ctx, txn, p, func(r dumpRecord) error {
+			for _, v := range r.Values {
+				var object string
+				switch {
+				case v.Ref != "":
+					object = node(v.Ref)
+				case r.Lang != "":
+					object = rdfLiteral(v.Value) + "@" + r.Lang
+				case rdfTypes[p.Type] != "":
+					object = rdfLiteral(v.Value) + "^^<" + rdfTypes[p.Type] + ">"
+				default:
+					object = rdfLiteral(v.Value)
+				}
+				line := node(r.Node) + " <" + r.Predicate + "> " + object + rdfFacets(v.Facets) + " .\n"
+				if _, err := bw.WriteString(line); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("modusgraph: export rdf: %s: %w", p.Name, err)
+		}
+	}
+	return bw.Flush()
+}
+
+// rdfLiteral quotes a value read from a query response as an RDF literal.
+// Values that are not strings, such as numbers and GeoJSON, are quoted in
+// their JSON form.
+func rdfLiteral(v any) string {
+	s, ok := v.(string)
+	if !ok {
+		data, _ := json.Marshal(v)
+		s = string(data)
+	}
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
+
+// rdfFacets renders facets as an N-Quad facet list, " (k=v, ...)", sorted by
+// key. Numbers, booleans and RFC 3339 times are written bare, so they keep
+// their type; other strings are quoted.
+func rdfFacets(facets map[string]any) string {
+	if len(facets) == 0 {
+		return ""
+	}
+	keys := slices.Sorted(maps.Keys(facets))
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		value := fmt.Sprint(facets[k])
+		if s, ok := facets[k].(string); ok {
+			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+				value = rdfLiteral(s)
+			}
+		}
+		parts[i] = k + "=" + value
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// ImportRDF reads RDF N-Quads from r, gzipped or not, such as those written
+// by ExportRDF or Dgraph's export, and writes them in batches. Facets and
+// language tags are kept. Apply the schema first, with AlterSchema, so that
+// indexes and list predicates are in place.
+//
+// Each blank node (_:name) is assigned a UID the first time it is written,
+// and later references to it resolve to the same node. ImportRDF returns the
+// UID assigned to each, keyed by its label without the _: prefix, along with
+// the error that stopped it, if any.
+func (c client) ImportRDF(ctx context.Context, r io.Reader, opts RDFOptions) (map[string]string, error) {
+	if err := c.writable(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	r, err := gunzipped(r)
+	if err != nil {
+		return nil, fmt.Errorf("modusgraph: import rdf: %w", err)
+	}
+	dgo, cleanup, err := c.DgraphClient()
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	l := &fileLoader{
+		dgo:       dgo,
+		batchSize: opts.BatchSize,
+		newUIDs:   opts.NewUIDs,
+		uids:      map[string]string{},
+	}
+	if l.batchSize <= 0 {
+		l.batchSize = batchSize
+	}
+	_, err = l.load(ctx, bufio.NewReader(r), chunker.RdfFormat)
+	uids := make(map[string]string, len(l.uids))
+	for label, uid := range l.uids {
+		uids[strings.TrimPrefix(label, "_:")] = uid
+	}
+	if err != nil {
+		return uids, fmt.Errorf("modusgraph: import rdf: %w", err)
+	}
+	return uids, nil
+}
@@ -0,0 +1,159 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportRDF(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "ExportImportRDFWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "ExportImportRDFWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	const schema = `
+		name: string @index(exact) @lang .
+		born: datetime .
+		tags: [string] .
+		rating: float .
+		location: geo .
+		directed: [uid] @reverse .
+		spouse: uid .
+		type Person {
+			name
+			born
+			location
+			directed
+			spouse
+		}
+		type Film {
+			name
+			tags
+			rating
+		}
+	`
+	const data = `
+		_:scott <name> "Ridley \"Sir\" Scott" .
+		_:scott <name> "Sir Ridley Scott"@en .
+		_:scott <born> "1937-11-30T00:00:00Z" .
+		_:scott <location> "{'type':'Point','coordinates':[-0.12,51.5]}"^^<geo:geojson> .
+		_:scott <dgraph.type> "Person" .
+		_:giannini <name> "Giannina Facio" .
+		_:giannini <dgraph.type> "Person" .
+		_:scott <spouse> _:giannini (since=2015, wed=2015-06-01T00:00:00Z) .
+		_:alien <name> "Alien" .
+		_:alien <name> "Alien, le huitième passager"@fr .
+		_:alien <tags> "horror" (weight=0.9) .
+		_:alien <tags> "space" .
+		_:alien <rating> "8.5" .
+		_:alien <dgraph.type> "Film" .
+		_:scott <directed> _:alien (year=1979, lead="Weaver") .
+		_:prometheus <name> "Prométhée"@fr .
+		_:prometheus <dgraph.type> "Film" .
+		_:scott <directed> _:prometheus .
+	`
+	const query = `{
+		people(func: type(Person), orderasc: name) {
+			name name@en born location dgraph.type
+			spouse @facets { name }
+			directed (orderasc: rating) @facets { name name@fr tags @facets rating dgraph.type ~directed { name } }
+		}
+	}`
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, err := mg.NewClient(tc.uri)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = client.DropAll(context.Background())
+				client.Close()
+				mg.Shutdown()
+			})
+			ctx := context.Background()
+
+			dg, cleanup, err := client.DgraphClient()
+			require.NoError(t, err)
+			defer cleanup()
+			require.NoError(t, dg.Alter(ctx, &api.Operation{Schema: schema}))
+			_, err = dg.NewTxn().Mutate(ctx, &api.Mutation{CommitNow: true, SetNquads: []byte(data)})
+			require.NoError(t, err)
+			want, err := client.QueryRaw(ctx, query, nil)
+			require.NoError(t, err)
+
+			var rdf bytes.Buffer
+			require.NoError(t, client.ExportRDF(ctx, &rdf, mg.RDFOptions{}))
+			out := rdf.String()
+			require.Contains(t, out, `<name> "Ridley \"Sir\" Scott"^^<xs:string> .`)
+			require.Contains(t, out, `<name> "Sir Ridley Scott"@en .`)
+			require.Contains(t, out, `^^<xs:dateTime> .`)
+			require.Contains(t, out, `^^<geo:geojson> .`)
+			require.Contains(t, out, `<tags> "horror"^^<xs:string> (weight=0.9) .`)
+			require.Contains(t, out, ` (lead="Weaver", year=1979) .`)
+			require.Contains(t, out, ` (since=2015, wed=2015-06-01T00:00:00Z) .`)
+			for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+				require.True(t, strings.HasPrefix(line, "_:0x"), line)
+			}
+
+			// The export loads into an emptied database with the same
+			// values, facets and edges, each blank node becoming one node.
+			require.NoError(t, client.DropAll(ctx))
+			require.NoError(t, client.AlterSchema(ctx, schema))
+			uids, err := client.ImportRDF(ctx, bytes.NewReader(rdf.Bytes()), mg.RDFOptions{BatchSize: 4})
+			require.NoError(t, err)
+			require.Len(t, uids, 4)
+			got, err := client.QueryRaw(ctx, query, nil)
+			require.NoError(t, err)
+			require.JSONEq(t, string(want), string(got))
+
+			// With UIDs, nodes are written as themselves, and NewUIDs loads
+			// them as new nodes, gzipped or not.
+			rdf.Reset()
+			require.NoError(t, client.ExportRDF(ctx, &rdf, mg.RDFOptions{UIDs: true}))
+			require.True(t, strings.HasPrefix(rdf.String(), "<0x"), rdf.String())
+			var zipped bytes.Buffer
+			zw := gzip.NewWriter(&zipped)
+			_, err = zw.Write(rdf.Bytes())
+			require.NoError(t, err)
+			require.NoError(t, zw.Close())
+			require.NoError(t, client.DropAll(ctx))
+			require.NoError(t, client.AlterSchema(ctx, schema))
+			_, err = client.ImportRDF(ctx, &zipped, mg.RDFOptions{NewUIDs: true})
+			require.NoError(t, err)
+			got, err = client.QueryRaw(ctx, query, nil)
+			require.NoError(t, err)
+			require.JSONEq(t, string(want), string(got))
+
+			_, err = client.ImportRDF(ctx, strings.NewReader(`_:a <name> "unterminated .`), mg.RDFOptions{})
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+)
+
+type requestLoggerKey struct{}
+
+type actorKey struct{}
+
+// WithRequestLogger returns a copy of ctx carrying logger. Client calls made
+// with the returned context log to logger instead of the one configured with
+// WithLogger, so a request's messages can carry its own name and values.
+func WithRequestLogger(ctx context.Context, logger logr.Logger) context.Context {
+	return context.WithValue(ctx, requestLoggerKey{}, logger)
+}
+
+// WithActor returns a copy of ctx identifying the user or service on whose
+// behalf client calls made with it act. The actor is logged with each
+// message as "actor", stamped on the ChangeEvents of the mutations those
+// calls commit, and readable by Computed and StructValidator implementations
+// through ActorFromContext.
+func WithActor(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, actorKey{}, id)
+}
+
+// ActorFromContext returns the actor set on ctx by WithActor, if any.
+func ActorFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(actorKey{}).(string)
+	return id, ok
+}
+
+// log returns the logger for a call made with ctx: the request logger, or
+// the client's, with the actor attached when there is one.
+func (c client) log(ctx context.Context) logr.Logger {
+	logger := c.logger
+	if l, ok := ctx.Value(requestLoggerKey{}).(logr.Logger); ok {
+		logger = l
+	}
+	if id, ok := ActorFromContext(ctx); ok {
+		logger = logger.WithValues("actor", id)
+	}
+	return logger
+}
@@ -0,0 +1,108 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type AuditedNote struct {
+	UID       string   `json:"uid,omitempty"`
+	Text      string   `json:"anote_text,omitempty"`
+	CreatedBy string   `json:"anote_created_by,omitempty"`
+	DType     []string `json:"dgraph.type,omitempty"`
+}
+
+// ComputeFields records who wrote the note from the call's context.
+func (n *AuditedNote) ComputeFields(ctx context.Context) error {
+	if actor, ok := mg.ActorFromContext(ctx); ok {
+		n.CreatedBy = actor
+	}
+	return nil
+}
+
+func TestRequestContext(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "RequestContextWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "RequestContextWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, err := mg.NewClient(tc.uri, mg.WithAutoSchema(true), mg.WithChangelog(t.TempDir()))
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = client.DropAll(context.Background())
+				client.Close()
+				mg.Shutdown()
+			})
+			if strings.HasPrefix(tc.uri, "dgraph://") {
+				require.NoError(t, client.DropAll(context.Background()))
+			}
+
+			var mu sync.Mutex
+			var lines []string
+			logger := funcr.New(func(prefix, args string) {
+				mu.Lock()
+				defer mu.Unlock()
+				lines = append(lines, args)
+			}, funcr.Options{Verbosity: 2})
+
+			ctx := mg.WithActor(mg.WithRequestLogger(context.Background(), logger), "alice")
+			note := &AuditedNote{Text: "hello"}
+			require.NoError(t, client.Insert(ctx, note))
+			require.Equal(t, "alice", note.CreatedBy)
+
+			// Calls without a request context keep the client's logger.
+			require.NoError(t, client.Insert(context.Background(), &AuditedNote{Text: "anonymous"}))
+
+			mu.Lock()
+			logged := strings.Join(lines, "\n")
+			mu.Unlock()
+			require.Contains(t, logged, `"Insert successful"`)
+			require.Contains(t, logged, `"actor"="alice"`)
+			require.Equal(t, 1, strings.Count(logged, "Insert successful"))
+
+			var actors []string
+			readCtx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			for ev, err := range client.Changelog(readCtx, 0) {
+				require.NoError(t, err)
+				if ev.Type == "AuditedNote" {
+					actors = append(actors, ev.Actor)
+				}
+				if ev.Seq == 2 {
+					break
+				}
+			}
+			require.Equal(t, []string{"alice", ""}, actors)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ErrRequiredField is wrapped by the ValidationError returned when a write
+// leaves a field tagged dgraph:"required" at its zero value: an empty string,
+// zero number, false, nil pointer or empty slice. The check runs on Insert,
+// Upsert, Update, LoadOrStore and UpsertIf whether or not a validator is
+// configured.
+var ErrRequiredField = errors.New("modusgraph: required field is empty")
+
+// requiredFieldErrors returns the failures of the fields of sv tagged
+// dgraph:"required", including those of embedded structs, that are zero.
+func requiredFieldErrors(sv reflect.Value) ([]FieldError, []error) {
+	var fields []FieldError
+	var causes []error
+	t := sv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fv := sv.Field(i)
+		if sf.Anonymous && fv.Kind() == reflect.Struct && sf.Tag.Get("json") == "" {
+			f, c := requiredFieldErrors(fv)
+			fields = append(fields, f...)
+			causes = append(causes, c...)
+			continue
+		}
+		if !slices.Contains(strings.Fields(sf.Tag.Get("dgraph")), "required") || !isEmptyValue(fv) {
+			continue
+		}
+		path, pred := fieldPath(t, t.Name()+"."+sf.Name)
+		fields = append(fields, FieldError{
+			Path:       path,
+			Predicate:  pred,
+			Field:      t.Name() + "." + sf.Name,
+			Constraint: "required",
+		})
+		causes = append(causes, fmt.Errorf("%w: %s.%s", ErrRequiredField, t.Name(), sf.Name))
+	}
+	return fields, causes
+}
+
+// isEmptyValue reports whether v is zero, an empty slice or map, or a
+// Nullable without a value.
+func isEmptyValue(v reflect.Value) bool {
+	if v.Kind() != reflect.Ptr && v.CanInterface() {
+		if n, ok := v.Interface().(nullClearer); ok {
+			return !n.Valid()
+		}
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,95 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type RequiredAudit struct {
+	CreatedBy string `json:"req_created_by,omitempty" dgraph:"required"`
+}
+
+type RequiredTicket struct {
+	UID   string   `json:"uid,omitempty"`
+	Title string   `json:"req_title,omitempty" dgraph:"index=exact required"`
+	Tags  []string `json:"req_tags,omitempty" dgraph:"required"`
+	Notes string   `json:"req_notes,omitempty"`
+	RequiredAudit
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+func TestRequiredFields(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "RequiredFieldsWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "RequiredFieldsWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			ctx := context.Background()
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			// No validator is configured: the tag alone is enforced.
+			err := client.Insert(ctx, &RequiredTicket{Notes: "no title"})
+			require.ErrorIs(t, err, mg.ErrRequiredField)
+			var ve *mg.ValidationError
+			require.ErrorAs(t, err, &ve)
+			var paths []string
+			for _, f := range ve.Fields {
+				require.Equal(t, "required", f.Constraint)
+				paths = append(paths, f.Path)
+			}
+			require.Equal(t, []string{"req_title", "req_tags", "req_created_by"}, paths)
+			require.Equal(t, "req_title", ve.Fields[0].Predicate)
+
+			ticket := &RequiredTicket{
+				Title:         "printer jam",
+				Tags:          []string{"hardware"},
+				RequiredAudit: RequiredAudit{CreatedBy: "ops"},
+			}
+			require.NoError(t, client.Insert(ctx, ticket))
+
+			ticket.Title = ""
+			err = client.Update(ctx, ticket)
+			require.ErrorIs(t, err, mg.ErrRequiredField)
+			var got RequiredTicket
+			require.NoError(t, client.Get(ctx, &got, ticket.UID))
+			require.Equal(t, "printer jam", got.Title)
+
+			// Slices report each element's failures under its index.
+			err = client.Insert(ctx, &[]*RequiredTicket{
+				{Title: "ok", Tags: []string{"a"}, RequiredAudit: RequiredAudit{CreatedBy: "ops"}},
+				{Title: "no tags", RequiredAudit: RequiredAudit{CreatedBy: "ops"}},
+			})
+			require.ErrorAs(t, err, &ve)
+			require.Len(t, ve.Fields, 1)
+			require.Equal(t, "[1].req_tags", ve.Fields[0].Path)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"math/rand/v2"
+	"reflect"
 	"time"
 
 	"github.com/dgraph-io/dgo/v250"
@@ -87,6 +88,56 @@ func (p RetryPolicy) delay(attempt int) time.Duration {
 //	    return client.Insert(ctx, &entity)
 //	})
 func (c client) WithRetry(ctx context.Context, policy RetryPolicy, fn func() error) error {
+	return c.retry(ctx, policy, func(err error) bool { return errors.Is(err, dgo.ErrAborted) }, fn)
+}
+
+// WithUpsertRetry sets the policy Upsert and LoadOrStore follow when Dgraph
+// aborts them for conflicting with a concurrent transaction, typically
+// another upsert of the same unique key. Each attempt re-runs the match, so
+// a retried upsert updates the node the winner created instead of failing.
+// Both retry per DefaultRetryPolicy unless configured otherwise; a policy
+// with MaxRetries of 0 returns the abort to the caller.
+func WithUpsertRetry(policy RetryPolicy) ClientOpt {
+	return func(o *clientOptions) {
+		o.upsertRetry = policy
+	}
+}
+
+// retryUpsert runs fn, an upsert or conditional mutation of obj, retrying it
+// per the WithUpsertRetry policy. The UIDs of obj are put back after an abort,
+// since the attempt may have assigned ones its transaction never committed.
+func (c client) retryUpsert(ctx context.Context, obj any, fn func() error) error {
+	elems := structElems(obj)
+	uids := make([]string, len(elems))
+	for i, elem := range elems {
+		uids[i] = uidField(elem).String()
+	}
+	return c.retry(ctx, c.options.upsertRetry, isAbortedErr, func() error {
+		err := fn()
+		if isAbortedErr(err) {
+			for i, elem := range elems {
+				if f := uidField(elem); f.CanSet() {
+					f.SetString(uids[i])
+				}
+			}
+		}
+		return err
+	})
+}
+
+// uidField returns the UID field of a dgraph struct, or the zero Value if it
+// has none.
+func uidField(v reflect.Value) reflect.Value {
+	f := v.FieldByName("UID")
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return reflect.Value{}
+	}
+	return f
+}
+
+// retry calls fn until it succeeds, fails with an error aborted does not
+// accept, or policy.MaxRetries retries are spent, backing off between calls.
+func (c client) retry(ctx context.Context, policy RetryPolicy, aborted func(error) bool, fn func() error) error {
 	// A negative MaxRetries would make the loop run zero times and never call
 	// fn; clamp to zero so fn always runs at least once, as documented.
 	maxRetries := policy.MaxRetries
@@ -98,7 +149,7 @@ func (c client) WithRetry(ctx context.Context, policy RetryPolicy, fn func() err
 		if err == nil {
 			return nil
 		}
-		if !errors.Is(err, dgo.ErrAborted) || attempt >= maxRetries {
+		if !aborted(err) || attempt >= maxRetries {
 			return err
 		}
 		d := policy.delay(attempt)
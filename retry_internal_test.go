@@ -6,9 +6,12 @@
 package modusgraph
 
 import (
+	"context"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/dgraph-io/dgo/v250"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -86,3 +89,40 @@ func TestRetryPolicyDelayZeroJitter(t *testing.T) {
 		assert.Equal(t, 200*time.Millisecond, p.delay(1))
 	}
 }
+
+func TestRetryUpsertRestoresUIDs(t *testing.T) {
+	type node struct {
+		UID  string `json:"uid,omitempty"`
+		Name string `json:"name,omitempty"`
+	}
+	c := client{options: clientOptions{upsertRetry: RetryPolicy{MaxRetries: 2}}}
+	obj := &node{UID: "_:n", Name: "a"}
+
+	var seen []string
+	calls := 0
+	err := c.retryUpsert(context.Background(), obj, func() error {
+		seen = append(seen, obj.UID)
+		calls++
+		obj.UID = "0x99" // assigned by a transaction that is then aborted
+		if calls < 3 {
+			return fmt.Errorf("committing transaction: %w", dgo.ErrAborted)
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"_:n", "_:n", "_:n"}, seen, "each attempt starts from the original UID")
+	assert.Equal(t, "0x99", obj.UID, "the successful attempt's UID is kept")
+}
+
+func TestRetryUpsertGivesUp(t *testing.T) {
+	c := client{options: clientOptions{upsertRetry: RetryPolicy{MaxRetries: 1}}}
+	calls := 0
+	err := c.retryUpsert(context.Background(), &struct{ UID string }{}, func() error {
+		calls++
+		return dgo.ErrAborted
+	})
+
+	assert.ErrorIs(t, err, dgo.ErrAborted)
+	assert.Equal(t, 2, calls)
+}
@@ -212,3 +212,58 @@ func TestWithRetryNegativeMaxRetries(t *testing.T) {
 	assert.ErrorIs(t, err, expectedErr)
 	assert.Equal(t, 1, callCount, "negative MaxRetries should still call fn once")
 }
+
+// TestConcurrentUpsertsRetryAborts verifies that Upsert retries transactions
+// aborted by concurrent upserts of the same key on its own, so callers see
+// neither the abort nor a duplicate node.
+func TestConcurrentUpsertsRetryAborts(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "FileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "DgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			ctx := context.Background()
+			require.NoError(t, client.UpdateSchema(ctx, &RetryEntity{}))
+
+			const numWorkers = 8
+			var wg sync.WaitGroup
+			for w := range numWorkers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					entity := &RetryEntity{Name: "shared", Value: w + 1}
+					if err := client.Upsert(ctx, entity); err != nil {
+						t.Errorf("worker %d: %v", w, err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			var entities []RetryEntity
+			err := client.Query(ctx, RetryEntity{}).Filter(`eq(name, "shared")`).Nodes(&entities)
+			require.NoError(t, err)
+			require.Len(t, entities, 1, "concurrent upserts of one key should leave one node")
+		})
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"math/rand/v2"
+	"strings"
+
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// Sample returns n nodes drawn uniformly at random from those the query's
+// filters match, or all of them when fewer match. It reads only the UIDs of
+// the matches, picks n of them with a reservoir, and then loads just those,
+// so decoding costs grow with n rather than with the number of matches. Use
+// it for spot checks, training-set extraction and approximate statistics:
+//
+//	films, err := modusgraph.Query[Film](ctx, client).
+//	    Filter("ge(release_year, ?)", 1990).
+//	    Sample(100)
+//
+// The sample is returned in UID order unless the query is ordered. Limit,
+// Offset and cursors do not apply; each call draws a new sample.
+func (tq *TypedQuery[T]) Sample(n int) ([]T, error) {
+	if err := tq.prepare(); err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		return nil, nil
+	}
+	filter, err := tq.filter()
+	if err != nil {
+		return nil, err
+	}
+	var model T
+	q := dg.NewQuery().Model(&model).Name(uidsBlock).Query("{ uid }")
+	if filter != "" {
+		q.Filter(filter)
+	}
+	resp, err := tq.client.QueryRaw(tq.ctx, q.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	sample, err := reservoirUIDs(resp, n)
+	if err != nil || len(sample) == 0 {
+		return nil, err
+	}
+
+	uids := make([]string, len(sample))
+	for i, uid := range sample {
+		uids[i] = string(UIDFromUint64(uid))
+	}
+	var out []T
+	if err := tq.q.UID(strings.Join(uids, ", ")).First(0).Offset(0).After("").Nodes(&out); err != nil {
+		return nil, err
+	}
+	NormalizeTimes(tq.client, out)
+	return out, nil
+}
+
+// reservoirUIDs picks n of the UIDs in a Dgraph JSON response uniformly at
+// random, holding no more than n of them at a time.
+func reservoirUIDs(data []byte, n int) ([]uint64, error) {
+	sample := make([]uint64, 0, n)
+	seen := 0
+	err := scanUIDs(data, func(uid uint64) {
+		seen++
+		if len(sample) < n {
+			sample = append(sample, uid)
+		} else if i := rand.IntN(seen); i < n {
+			sample[i] = uid
+		}
+	})
+	return sample, err
+}
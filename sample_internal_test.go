@@ -0,0 +1,35 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservoirUIDsIsUniform(t *testing.T) {
+	resp := []byte(`{"uids":[{"uid":"0x1"},{"uid":"0x2"},{"uid":"0x3"},{"uid":"0x4"}]}`)
+	counts := map[uint64]int{}
+	const draws = 8000
+	for range draws {
+		sample, err := reservoirUIDs(resp, 2)
+		require.NoError(t, err)
+		require.Len(t, sample, 2)
+		require.NotEqual(t, sample[0], sample[1])
+		for _, uid := range sample {
+			counts[uid]++
+		}
+	}
+	// Each UID is in half of the samples.
+	for uid := uint64(1); uid <= 4; uid++ {
+		require.InDelta(t, draws/2, counts[uid], draws/20, "uid %d", uid)
+	}
+
+	sample, err := reservoirUIDs(resp, 10)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 2, 3, 4}, sample)
+}
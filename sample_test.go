@@ -0,0 +1,94 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type SampledFilm struct {
+	UID   string   `json:"uid,omitempty"`
+	Title string   `json:"sfilm_title,omitempty" dgraph:"index=exact"`
+	Year  int      `json:"sfilm_year,omitempty" dgraph:"index=int"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+func TestSample(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "SampleWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "SampleWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			ctx := context.Background()
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			var films []*SampledFilm
+			for i := range 50 {
+				films = append(films, &SampledFilm{Title: fmt.Sprintf("Film %02d", i), Year: 1950 + i})
+			}
+			require.NoError(t, mg.Insert(ctx, client, films...))
+
+			// Samples are distinct matching nodes, and repeated draws cover
+			// more than one subset.
+			seen := map[string]bool{}
+			for range 5 {
+				sample, err := mg.Query[SampledFilm](ctx, client).
+					Filter("ge(sfilm_year, ?)", 1980).
+					OrderAsc("sfilm_year").
+					Limit(1).
+					Sample(5)
+				require.NoError(t, err)
+				require.Len(t, sample, 5)
+				titles := map[string]bool{}
+				for i, f := range sample {
+					require.GreaterOrEqual(t, f.Year, 1980)
+					require.NotEmpty(t, f.Title)
+					if i > 0 {
+						require.Greater(t, f.Year, sample[i-1].Year)
+					}
+					titles[f.UID] = true
+					seen[f.UID] = true
+				}
+				require.Len(t, titles, 5)
+			}
+			require.Greater(t, len(seen), 5)
+
+			// Asking for more than match returns every match.
+			all, err := mg.Query[SampledFilm](ctx, client).Filter("lt(sfilm_year, ?)", 1955).Sample(10)
+			require.NoError(t, err)
+			require.Len(t, all, 5)
+
+			none, err := mg.Query[SampledFilm](ctx, client).Filter("gt(sfilm_year, ?)", 3000).Sample(10)
+			require.NoError(t, err)
+			require.Empty(t, none)
+		})
+	}
+}
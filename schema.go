@@ -0,0 +1,301 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dgraph-io/dgo/v250"
+	"github.com/dgraph-io/dgo/v250/protos/api"
+)
+
+// SchemaInfo is the live schema of a database as returned by GetSchema: its
+// predicates and node types, sorted by name. Dgraph's internal dgraph.*
+// predicates and types are left out.
+type SchemaInfo struct {
+	Predicates []PredicateInfo
+	Types      []TypeInfo
+}
+
+// PredicateInfo describes one predicate. Type is the scalar type, or
+// "uid" for edges; List marks [type] predicates. Indexes holds the index
+// tokenizers in DQL form, such as "exact", "term", or
+// `hnsw(metric: "cosine")`.
+type PredicateInfo struct {
+	Name       string
+	Type       string
+	List       bool
+	Indexes    []string
+	Upsert     bool
+	Unique     bool
+	Reverse    bool
+	Lang       bool
+	Count      bool
+	NoConflict bool
+}
+
+// TypeInfo describes one node type and the predicates it declares.
+type TypeInfo struct {
+	Name   string
+	Fields []string
+}
+
+// Predicate returns the predicate with the given name.
+func (s *SchemaInfo) Predicate(name string) (PredicateInfo, bool) {
+	for _, p := range s.Predicates {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return PredicateInfo{}, false
+}
+
+// Type returns the node type with the given name.
+func (s *SchemaInfo) Type(name string) (TypeInfo, bool) {
+	for _, t := range s.Types {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return TypeInfo{}, false
+}
+
+// String renders the schema in Dgraph Schema Definition Language, in a form
+// AlterSchema accepts.
+func (s *SchemaInfo) String() string {
+	var sb strings.Builder
+	for _, p := range s.Predicates {
+		sb.WriteString(p.String())
+		sb.WriteByte('\n')
+	}
+	for _, t := range s.Types {
+		sb.WriteString("type " + t.Name + " {\n")
+		for _, f := range t.Fields {
+			sb.WriteString("\t" + f + "\n")
+		}
+		sb.WriteString("}\n")
+	}
+	return sb.String()
+}
+
+// String renders the predicate as one line of Dgraph Schema Definition
+// Language.
+func (p PredicateInfo) String() string {
+	typ := p.Type
+	if p.List {
+		typ = "[" + typ + "]"
+	}
+	parts := []string{p.Name + ":", typ}
+	if len(p.Indexes) > 0 {
+		parts = append(parts, "@index("+strings.Join(p.Indexes, ", ")+")")
+	}
+	for _, d := range []struct {
+		set  bool
+		name string
+	}{
+		{p.Reverse, "@reverse"},
+		{p.Count, "@count"},
+		{p.Lang, "@lang"},
+		{p.Upsert, "@upsert"},
+		{p.Unique, "@unique"},
+		{p.NoConflict, "@noconflict"},
+	} {
+		if d.set {
+			parts = append(parts, d.name)
+		}
+	}
+	return strings.Join(parts, " ") + " ."
+}
+
+// schemaResponse is the JSON result of a DQL schema query.
+type schemaResponse struct {
+	Schema []struct {
+		Predicate  string   `json:"predicate"`
+		Type       string   `json:"type"`
+		List       bool     `json:"list"`
+		Tokenizer  []string `json:"tokenizer"`
+		Upsert     bool     `json:"upsert"`
+		Unique     bool     `json:"unique"`
+		Reverse    bool     `json:"reverse"`
+		Lang       bool     `json:"lang"`
+		Count      bool     `json:"count"`
+		NoConflict bool     `json:"no_conflict"`
+		IndexSpecs []struct {
+			Name    string `json:"name"`
+			Options []struct {
+				Key   string `json:"key"`
+				Value string `json:"value"`
+			} `json:"options"`
+		} `json:"index_specs"`
+	} `json:"schema"`
+	Types []struct {
+		Name   string `json:"name"`
+		Fields []struct {
+			Name string `json:"name"`
+		} `json:"fields"`
+	} `json:"types"`
+}
+
+// GetSchema reads the live schema into a SchemaInfo. Both backends report the
+// same structure: against the embedded engine, whose schema {} query lists
+// only types, the predicates are looked up by name.
+func (c client) GetSchema(ctx context.Context) (*SchemaInfo, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	var resp schemaResponse
+	if err := c.schemaQuery(ctx, "schema {}", &resp); err != nil {
+		return nil, err
+	}
+	if c.ns != nil && len(resp.Schema) == 0 {
+		names := c.ns.predicates()
+		if len(names) > 0 {
+			q := fmt.Sprintf("schema(pred: [%s]) {}", strings.Join(names, ", "))
+			var preds schemaResponse
+			if err := c.schemaQuery(ctx, q, &preds); err != nil {
+				return nil, err
+			}
+			resp.Schema = preds.Schema
+		}
+	}
+
+	s := &SchemaInfo{}
+	for _, p := range resp.Schema {
+		if isInternalName(p.Predicate) {
+			continue
+		}
+		ps := PredicateInfo{
+			Name:       p.Predicate,
+			Type:       p.Type,
+			List:       p.List,
+			Upsert:     p.Upsert,
+			Unique:     p.Unique,
+			Reverse:    p.Reverse,
+			Lang:       p.Lang,
+			Count:      p.Count,
+			NoConflict: p.NoConflict,
+		}
+		specs := map[string]string{}
+		for _, spec := range p.IndexSpecs {
+			opts := make([]string, len(spec.Options))
+			for i, o := range spec.Options {
+				opts[i] = fmt.Sprintf("%s: %q", o.Key, o.Value)
+			}
+			specs[spec.Name] = spec.Name + "(" + strings.Join(opts, ", ") + ")"
+		}
+		for _, tok := range p.Tokenizer {
+			name, _, _ := strings.Cut(tok, "(")
+			if spec, ok := specs[name]; ok {
+				tok = spec
+			}
+			ps.Indexes = append(ps.Indexes, tok)
+		}
+		s.Predicates = append(s.Predicates, ps)
+	}
+	for _, t := range resp.Types {
+		if isInternalName(t.Name) {
+			continue
+		}
+		ts := TypeInfo{Name: t.Name, Fields: make([]string, len(t.Fields))}
+		for i, f := range t.Fields {
+			ts.Fields[i] = f.Name
+		}
+		s.Types = append(s.Types, ts)
+	}
+	sort.Slice(s.Predicates, func(i, j int) bool { return s.Predicates[i].Name < s.Predicates[j].Name })
+	sort.Slice(s.Types, func(i, j int) bool { return s.Types[i].Name < s.Types[j].Name })
+	return s, nil
+}
+
+func (c client) schemaQuery(ctx context.Context, q string, out *schemaResponse) error {
+	raw, err := c.QueryRaw(ctx, q, nil)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// ExportSchema implements writing the live schema to w in Dgraph's .schema
+// text format.
+func (c client) ExportSchema(ctx context.Context, w io.Writer) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	schema, err := c.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, schema.String())
+	return err
+}
+
+// WriteModelSchema writes the schema UpdateSchema would apply for models to w
+// in Dgraph's .schema text format, without touching a database. Build steps
+// use it to commit a generated schema file next to the models, so that schema
+// changes show up in review and can be applied to external clusters.
+func WriteModelSchema(w io.Writer, models ...any) error {
+	s, err := modelSchema(models...)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, s.String())
+	return err
+}
+
+// createSchema applies the predicates and types declared by the struct tags
+// of models. Like dgman's CreateSchema, which it replaces, it requires a
+// dgraph.type field on each model and leaves predicates that already exist
+// untouched; predicates are rendered by PredicateInfo so that unique ints
+// are accepted by Dgraph.
+func createSchema(ctx context.Context, dgClient *dgo.Dgraph, models ...any) error {
+	for _, m := range models {
+		t := reflect.TypeOf(m)
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		hasDType := false
+		for i := 0; i < t.NumField() && !hasDType; i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			hasDType = strings.TrimSpace(name) == "dgraph.type"
+		}
+		if !hasDType {
+			return fmt.Errorf("missing required field DType []string `json:\"dgraph.type\"` in type %s", t.Name())
+		}
+	}
+
+	resp, err := dgClient.NewReadOnlyTxn().Query(ctx, "schema {}")
+	if err != nil {
+		return err
+	}
+	var live schemaResponse
+	if err := json.Unmarshal(resp.Json, &live); err != nil {
+		return err
+	}
+	existing := make(map[string]bool, len(live.Schema))
+	for _, p := range live.Schema {
+		existing[p.Predicate] = true
+	}
+
+	s, err := structSchema(models...)
+	if err != nil {
+		return err
+	}
+	s.Predicates = slices.DeleteFunc(s.Predicates, func(p PredicateInfo) bool { return existing[p.Name] })
+	if len(s.Predicates) == 0 && len(s.Types) == 0 {
+		return nil
+	}
+	return dgClient.Alter(ctx, &api.Operation{Schema: s.String()})
+}
+
+// isInternalName reports whether a predicate or type belongs to Dgraph itself.
+func isInternalName(name string) bool {
+	return strings.HasPrefix(name, "dgraph.")
+}
@@ -0,0 +1,302 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// SchemaDiff is the difference between the schema a set of models declares
+// and the live schema of a database, as computed by DiffSchema.
+type SchemaDiff struct {
+	// MissingPredicates are declared by the models but absent from the store.
+	MissingPredicates []PredicateInfo
+	// Mismatches are predicates present on both sides whose type, indexes or
+	// directives differ.
+	Mismatches []PredicateMismatch
+	// MissingTypes are node types declared by the models but absent from the
+	// store.
+	MissingTypes []TypeInfo
+	// TypeChanges are node types whose field lists differ.
+	TypeChanges []TypeChange
+	// OrphanedPredicates exist in the store but are not declared by any of
+	// the models.
+	OrphanedPredicates []PredicateInfo
+}
+
+// PredicateMismatch describes a predicate whose live definition differs from
+// the one the models declare. Differences names the aspects that differ:
+// "type", "list", "index", "reverse", "count", "lang", "upsert", "unique" or
+// "noconflict".
+type PredicateMismatch struct {
+	Name        string
+	Want        PredicateInfo
+	Have        PredicateInfo
+	Differences []string
+}
+
+// TypeChange describes a node type whose live field list differs from the
+// one the models declare.
+type TypeChange struct {
+	Name string
+	// MissingFields are declared by the model but not by the live type.
+	MissingFields []string
+	// ExtraFields are declared by the live type but not by the model.
+	ExtraFields []string
+}
+
+// Empty reports whether the models and the live schema agree. Orphaned
+// predicates are not taken into account, since a database is commonly
+// shared by more models than a single caller knows about.
+func (d *SchemaDiff) Empty() bool {
+	return len(d.MissingPredicates) == 0 && len(d.Mismatches) == 0 &&
+		len(d.MissingTypes) == 0 && len(d.TypeChanges) == 0
+}
+
+// String renders the diff as one line per finding, suitable for logging.
+func (d *SchemaDiff) String() string {
+	var sb strings.Builder
+	for _, p := range d.MissingPredicates {
+		fmt.Fprintf(&sb, "missing predicate: %s\n", p)
+	}
+	for _, m := range d.Mismatches {
+		fmt.Fprintf(&sb, "predicate %s differs (%s): want %q, have %q\n",
+			m.Name, strings.Join(m.Differences, ", "), m.Want.String(), m.Have.String())
+	}
+	for _, t := range d.MissingTypes {
+		fmt.Fprintf(&sb, "missing type: %s\n", t.Name)
+	}
+	for _, t := range d.TypeChanges {
+		fmt.Fprintf(&sb, "type %s differs: missing fields %v, extra fields %v\n",
+			t.Name, t.MissingFields, t.ExtraFields)
+	}
+	for _, p := range d.OrphanedPredicates {
+		fmt.Fprintf(&sb, "orphaned predicate: %s\n", p.Name)
+	}
+	return sb.String()
+}
+
+// DiffSchema compares the schema the given models would produce through
+// UpdateSchema with the live schema of the database behind client. Nothing
+// is altered; applications can log the result or refuse to start instead of
+// relying on AutoSchema. Pass every model the application uses, otherwise
+// their predicates are reported as orphaned.
+func DiffSchema(ctx context.Context, client Client, models ...any) (*SchemaDiff, error) {
+	live, err := client.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	want, err := modelSchema(models...)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &SchemaDiff{}
+	declared := make(map[string]bool, len(want.Predicates))
+	for _, w := range want.Predicates {
+		declared[w.Name] = true
+		have, ok := live.Predicate(w.Name)
+		if !ok {
+			diff.MissingPredicates = append(diff.MissingPredicates, w)
+			continue
+		}
+		if differences := comparePredicates(w, have); len(differences) > 0 {
+			diff.Mismatches = append(diff.Mismatches, PredicateMismatch{
+				Name: w.Name, Want: w, Have: have, Differences: differences,
+			})
+		}
+	}
+	for _, have := range live.Predicates {
+		if !declared[have.Name] {
+			diff.OrphanedPredicates = append(diff.OrphanedPredicates, have)
+		}
+	}
+	for _, w := range want.Types {
+		have, ok := live.Type(w.Name)
+		if !ok {
+			diff.MissingTypes = append(diff.MissingTypes, w)
+			continue
+		}
+		missing, extra := diffFields(w.Fields, have.Fields)
+		if len(missing) > 0 || len(extra) > 0 {
+			diff.TypeChanges = append(diff.TypeChanges, TypeChange{
+				Name: w.Name, MissingFields: missing, ExtraFields: extra,
+			})
+		}
+	}
+	return diff, nil
+}
+
+// modelSchema returns the schema UpdateSchema would apply for models,
+// including the shadow vector predicates of SimString fields.
+func modelSchema(models ...any) (*SchemaInfo, error) {
+	s, err := structSchema(models...)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range models {
+		for _, info := range collectSimFields(UnwrapSchema(m)) {
+			s.Predicates = append(s.Predicates, PredicateInfo{
+				Name: info.vecPredicate,
+				Type: "float32vector",
+				Indexes: []string{fmt.Sprintf(`hnsw(exponent: "%s", metric: "%s")`,
+					info.exponent, info.metric)},
+			})
+		}
+	}
+	sort.Slice(s.Predicates, func(i, j int) bool { return s.Predicates[i].Name < s.Predicates[j].Name })
+	return s, nil
+}
+
+// structSchema returns the predicates and types declared by the dgraph
+// struct tags of models. It fails on fields no Dgraph type can hold.
+func structSchema(models ...any) (*SchemaInfo, error) {
+	ts := dg.NewTypeSchema()
+	for _, m := range models {
+		ts.Marshal("", UnwrapSchema(m))
+	}
+	s := &SchemaInfo{}
+	for _, p := range ts.Schema {
+		s.Predicates = append(s.Predicates, predicateFromModel(p))
+	}
+	for name, fields := range ts.Types {
+		t := TypeInfo{Name: name}
+		for f := range fields {
+			t.Fields = append(t.Fields, f)
+		}
+		sort.Strings(t.Fields)
+		s.Types = append(s.Types, t)
+	}
+	if err := resolveSchemaTypes(s, models...); err != nil {
+		return nil, err
+	}
+	applyUniqueGroups(s, models...)
+	applyLocalized(s, models...)
+	sort.Slice(s.Predicates, func(i, j int) bool { return s.Predicates[i].Name < s.Predicates[j].Name })
+	sort.Slice(s.Types, func(i, j int) bool { return s.Types[i].Name < s.Types[j].Name })
+	return s, nil
+}
+
+// hnswBareOption matches an hnsw option whose value is not quoted, as in
+// hnsw(metric:cosine), which DQL does not accept either.
+var hnswBareOption = regexp.MustCompile(`(\w+):\s*([^\s",)]+)`)
+
+// predicateFromModel converts a dgman schema entry, applying the same
+// adjustments dgman makes when it renders the entry for Alter: unique
+// predicates imply @upsert, and unique strings get a hash index if they have
+// no exact one. Unlike dgman, ints are not given a hash index, which Dgraph
+// rejects for them; their int index serves the uniqueness check.
+func predicateFromModel(s *dg.Schema) PredicateInfo {
+	indexes := make([]string, len(s.Tokenizer))
+	for i, tok := range s.Tokenizer {
+		// Tags may quote hnsw option names, which DQL does not accept.
+		tok = strings.ReplaceAll(tok, `"metric":`, "metric:")
+		tok = strings.ReplaceAll(tok, `"exponent":`, "exponent:")
+		if strings.HasPrefix(tok, "hnsw(") {
+			tok = hnswBareOption.ReplaceAllString(tok, `$1:"$2"`)
+		}
+		indexes[i] = tok
+	}
+	p := PredicateInfo{
+		Name:       s.Predicate,
+		Type:       s.Type,
+		List:       s.List,
+		Indexes:    indexes,
+		Upsert:     s.Upsert || s.Unique,
+		Reverse:    s.Reverse,
+		Lang:       s.Lang,
+		Count:      s.Count,
+		NoConflict: s.Noconflict,
+	}
+	if strings.HasPrefix(p.Type, "[") {
+		p.Type = strings.Trim(p.Type, "[]")
+		p.List = true
+	}
+	if s.Unique {
+		p.Unique = p.Type == "int" || p.Type == "string"
+		hasIndex := false
+		for _, tok := range p.Indexes {
+			hasIndex = hasIndex || tok == "hash" || tok == "exact"
+		}
+		if !hasIndex && p.Type == "string" {
+			p.Indexes = append(p.Indexes, "hash")
+		}
+	}
+	return p
+}
+
+// comparePredicates lists the aspects in which have differs from want.
+func comparePredicates(want, have PredicateInfo) []string {
+	var differences []string
+	for _, c := range []struct {
+		name string
+		same bool
+	}{
+		{"type", want.Type == have.Type},
+		{"list", want.List == have.List},
+		{"index", strings.Join(normalizeIndexes(want.Indexes), " ") ==
+			strings.Join(normalizeIndexes(have.Indexes), " ")},
+		{"reverse", want.Reverse == have.Reverse},
+		{"count", want.Count == have.Count},
+		{"lang", want.Lang == have.Lang},
+		{"upsert", want.Upsert == have.Upsert},
+		{"unique", want.Unique == have.Unique},
+		{"noconflict", want.NoConflict == have.NoConflict},
+	} {
+		if !c.same {
+			differences = append(differences, c.name)
+		}
+	}
+	return differences
+}
+
+// normalizeIndexes returns the tokenizers sorted, with whitespace removed and
+// tokenizer options sorted, so that `hnsw(metric:"cosine")` from a struct tag
+// matches `hnsw(metric: "cosine")` as reported by Dgraph.
+func normalizeIndexes(indexes []string) []string {
+	out := make([]string, len(indexes))
+	for i, tok := range indexes {
+		tok = strings.Join(strings.Fields(tok), "")
+		if name, opts, ok := strings.Cut(tok, "("); ok {
+			parts := strings.Split(strings.TrimSuffix(opts, ")"), ",")
+			sort.Strings(parts)
+			tok = name + "(" + strings.Join(parts, ",") + ")"
+		}
+		out[i] = tok
+	}
+	sort.Strings(out)
+	return out
+}
+
+// diffFields returns the fields of want absent from have, and those of have
+// absent from want.
+func diffFields(want, have []string) (missing, extra []string) {
+	inHave := make(map[string]bool, len(have))
+	for _, f := range have {
+		inHave[f] = true
+	}
+	inWant := make(map[string]bool, len(want))
+	for _, f := range want {
+		inWant[f] = true
+		if !inHave[f] {
+			missing = append(missing, f)
+		}
+	}
+	for _, f := range have {
+		if !inWant[f] {
+			extra = append(extra, f)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(extra)
+	return missing, extra
+}
@@ -0,0 +1,108 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type DiffDirector struct {
+	UID   string      `json:"uid,omitempty"`
+	Name  string      `json:"diff_name,omitempty" dgraph:"index=exact,term unique"`
+	Films []*DiffFilm `json:"diff_films,omitempty" dgraph:"reverse count"`
+	DType []string    `json:"dgraph.type,omitempty"`
+}
+
+type DiffFilm struct {
+	UID    string   `json:"uid,omitempty"`
+	Title  string   `json:"diff_title,omitempty" dgraph:"index=hash"`
+	Rating float64  `json:"diff_rating,omitempty"`
+	DType  []string `json:"dgraph.type,omitempty"`
+}
+
+type DiffVenue struct {
+	UID      string   `json:"uid,omitempty"`
+	Name     string   `json:"diff_venue,omitempty" dgraph:"index=hash"`
+	Capacity float64  `json:"diff_capacity,omitempty" dgraph:"index=float"`
+	DType    []string `json:"dgraph.type,omitempty"`
+}
+
+func TestDiffSchema(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "DiffSchemaWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "DiffSchemaWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+
+			diff, err := mg.DiffSchema(ctx, client, &DiffDirector{})
+			require.NoError(t, err)
+			require.False(t, diff.Empty())
+			require.Len(t, diff.MissingPredicates, 4)
+			require.Len(t, diff.MissingTypes, 2)
+
+			require.NoError(t, client.UpdateSchema(ctx, &DiffDirector{}))
+			diff, err = mg.DiffSchema(ctx, client, &DiffDirector{})
+			require.NoError(t, err)
+			require.True(t, diff.Empty(), diff.String())
+
+			// Declare the venue predicates with definitions that disagree
+			// with DiffVenue.
+			require.NoError(t, client.AlterSchema(ctx, `
+diff_venue: string @index(exact) .
+diff_capacity: int .
+diff_orphan: string .
+type DiffVenue {
+	diff_venue
+	diff_orphan
+}
+`))
+			diff, err = mg.DiffSchema(ctx, client, &DiffDirector{}, &DiffVenue{})
+			require.NoError(t, err)
+			require.False(t, diff.Empty())
+			require.Empty(t, diff.MissingPredicates)
+			require.Empty(t, diff.MissingTypes)
+			require.Len(t, diff.Mismatches, 2)
+			require.Equal(t, "diff_capacity", diff.Mismatches[0].Name)
+			require.Equal(t, []string{"type", "index"}, diff.Mismatches[0].Differences)
+			require.Equal(t, "diff_venue", diff.Mismatches[1].Name)
+			require.Equal(t, []string{"index"}, diff.Mismatches[1].Differences)
+			require.Equal(t, []mg.TypeChange{{
+				Name:          "DiffVenue",
+				MissingFields: []string{"diff_capacity"},
+				ExtraFields:   []string{"diff_orphan"},
+			}}, diff.TypeChanges)
+			require.Len(t, diff.OrphanedPredicates, 1)
+			require.Equal(t, "diff_orphan", diff.OrphanedPredicates[0].Name)
+			require.Contains(t, diff.String(), "predicate diff_venue differs (index)")
+		})
+	}
+}
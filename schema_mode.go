@@ -0,0 +1,149 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrSchemaDrift is returned in SchemaStrict mode when the live schema does
+// not match the models. The wrapping error lists the differences.
+var ErrSchemaDrift = errors.New("modusgraph: live schema does not match models")
+
+// SchemaMode selects how a client reconciles the schema its models declare
+// with the live schema. See WithSchemaMode.
+type SchemaMode int
+
+const (
+	// SchemaManual leaves the schema alone; writes fail unless their type
+	// already exists. This is the default.
+	SchemaManual SchemaMode = iota
+	// SchemaStrict fails with ErrSchemaDrift when the live schema differs
+	// from the models in any way DiffSchema reports.
+	SchemaStrict
+	// SchemaWarn logs differences and carries on.
+	SchemaWarn
+	// SchemaAuto applies additive changes only: missing predicates, missing
+	// types and fields missing from existing types. Changes to existing
+	// predicates are logged, never applied.
+	SchemaAuto
+)
+
+func (m SchemaMode) String() string {
+	switch m {
+	case SchemaManual:
+		return "manual"
+	case SchemaStrict:
+		return "strict"
+	case SchemaWarn:
+		return "warn"
+	case SchemaAuto:
+		return "auto"
+	}
+	return fmt.Sprintf("SchemaMode(%d)", int(m))
+}
+
+// WithSchemaMode sets how the schema of written objects is reconciled with
+// the live schema; each Insert, Upsert and Update checks its object's model.
+// When models are given, NewClient also checks them up front, so that in
+// SchemaStrict mode a drifted database stops the application at startup.
+// WithAutoSchema(true), which applies every change including index and type
+// changes, takes precedence over this option.
+func WithSchemaMode(mode SchemaMode, models ...any) ClientOpt {
+	return func(o *clientOptions) {
+		o.schemaMode = mode
+		o.schemaModels = models
+	}
+}
+
+// schemaModeKey renders the schema mode and startup models for the client
+// dedup key.
+func schemaModeKey(mode SchemaMode, models []any) string {
+	names := make([]string, len(models))
+	for i, m := range models {
+		names[i] = getNodeType(UnwrapSchema(m))
+	}
+	return mode.String() + "[" + strings.Join(names, ",") + "]"
+}
+
+// checkStartupSchema checks the version given to WithSchemaVersion and
+// reconciles the models given to WithSchemaMode when the client is created.
+func (c client) checkStartupSchema() error {
+	if err := c.checkSchemaVersion(context.Background()); err != nil {
+		return err
+	}
+	if c.options.autoSchema || c.options.schemaMode == SchemaManual || len(c.options.schemaModels) == 0 {
+		return nil
+	}
+	return c.reconcileSchema(context.Background(), c.options.schemaModels...)
+}
+
+// reconcileSchema compares models with the live schema and acts on the
+// differences according to the client's SchemaMode.
+func (c client) reconcileSchema(ctx context.Context, models ...any) error {
+	diff, err := DiffSchema(ctx, c, models...)
+	if err != nil {
+		return fmt.Errorf("failed to diff schema: %w", err)
+	}
+	if diff.Empty() {
+		return nil
+	}
+
+	switch c.options.schemaMode {
+	case SchemaStrict:
+		return fmt.Errorf("%w:\n%s", ErrSchemaDrift, diff)
+	case SchemaWarn:
+		// Log each distinct drift once rather than on every write.
+		if _, seen := c.schemaWarned.LoadOrStore(diff.String(), true); !seen {
+			c.logger.Info("Schema drift detected", "diff", diff.String())
+		}
+		return nil
+	case SchemaAuto:
+		want, err := modelSchema(models...)
+		if err != nil {
+			return err
+		}
+		additive := additiveSchema(diff, want)
+		if additive != "" {
+			c.logger.V(1).Info("Applying additive schema changes", "schema", additive)
+			if err := c.AlterSchema(ctx, additive); err != nil {
+				return err
+			}
+		}
+		if len(diff.Mismatches) > 0 {
+			skipped := &SchemaDiff{Mismatches: diff.Mismatches}
+			if _, seen := c.schemaWarned.LoadOrStore(skipped.String(), true); !seen {
+				c.logger.Info("Schema drift not applied in auto mode", "diff", skipped.String())
+			}
+		}
+	}
+	return nil
+}
+
+// additiveSchema renders the parts of diff that only add to the schema. An
+// existing type gains its missing fields while keeping its other ones, since
+// a type definition replaces the previous one.
+func additiveSchema(diff *SchemaDiff, want *SchemaInfo) string {
+	add := &SchemaInfo{
+		Predicates: diff.MissingPredicates,
+		Types:      slices.Clone(diff.MissingTypes),
+	}
+	for _, tc := range diff.TypeChanges {
+		if len(tc.MissingFields) == 0 {
+			continue
+		}
+		t, _ := want.Type(tc.Name)
+		add.Types = append(add.Types, TypeInfo{
+			Name:   tc.Name,
+			Fields: append(slices.Clone(t.Fields), tc.ExtraFields...),
+		})
+	}
+	return add.String()
+}
@@ -0,0 +1,96 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type ModeFilm struct {
+	UID   string   `json:"uid,omitempty"`
+	Title string   `json:"mode_title,omitempty" dgraph:"index=exact"`
+	Year  int      `json:"mode_year,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+func TestSchemaMode(t *testing.T) {
+	uri := "file://" + GetTempDir(t)
+	ctx := context.Background()
+
+	// Strict refuses to start against a database lacking the model's schema.
+	_, err := mg.NewClient(uri, mg.WithSchemaMode(mg.SchemaStrict, &ModeFilm{}))
+	require.ErrorIs(t, err, mg.ErrSchemaDrift)
+	require.ErrorContains(t, err, "missing type: ModeFilm")
+	mg.Shutdown()
+
+	// An older schema: mode_title lacks its index and ModeFilm lacks mode_year.
+	client, err := mg.NewClient(uri)
+	require.NoError(t, err)
+	require.NoError(t, client.AlterSchema(ctx, `
+mode_title: string .
+mode_note: string .
+type ModeFilm {
+	mode_title
+	mode_note
+}
+`))
+	client.Close()
+	mg.Shutdown()
+
+	// Auto adds mode_year to the type but leaves the changed mode_title alone.
+	var logs []string
+	logger := funcr.New(func(prefix, args string) { logs = append(logs, args) }, funcr.Options{})
+	client, err = mg.NewClient(uri, mg.WithSchemaMode(mg.SchemaAuto, &ModeFilm{}), mg.WithLogger(logger))
+	require.NoError(t, err)
+	schema, err := client.GetSchema(ctx)
+	require.NoError(t, err)
+	film, ok := schema.Type("ModeFilm")
+	require.True(t, ok)
+	require.ElementsMatch(t, []string{"mode_title", "mode_year", "mode_note"}, film.Fields)
+	title, _ := schema.Predicate("mode_title")
+	require.Empty(t, title.Indexes)
+	require.NoError(t, client.Insert(ctx, &ModeFilm{Title: "Metropolis", Year: 1927}))
+	require.Len(t, filterLogs(logs, "not applied in auto mode"), 1)
+	client.Close()
+	mg.Shutdown()
+
+	// Warn starts despite the remaining drift and logs it once.
+	logs = nil
+	client, err = mg.NewClient(uri, mg.WithSchemaMode(mg.SchemaWarn, &ModeFilm{}), mg.WithLogger(logger))
+	require.NoError(t, err)
+	require.NoError(t, client.Insert(ctx, &ModeFilm{Title: "M", Year: 1931}))
+	drift := filterLogs(logs, "Schema drift detected")
+	require.Len(t, drift, 1)
+	require.Contains(t, drift[0], "predicate mode_title differs (index)")
+	client.Close()
+	mg.Shutdown()
+
+	// Strict still refuses, and so do its writes.
+	_, err = mg.NewClient(uri, mg.WithSchemaMode(mg.SchemaStrict, &ModeFilm{}))
+	require.ErrorIs(t, err, mg.ErrSchemaDrift)
+	mg.Shutdown()
+	client, err = mg.NewClient(uri, mg.WithSchemaMode(mg.SchemaStrict))
+	require.NoError(t, err)
+	require.ErrorIs(t, client.Insert(ctx, &ModeFilm{Title: "Nosferatu"}), mg.ErrSchemaDrift)
+	client.Close()
+	mg.Shutdown()
+}
+
+func filterLogs(logs []string, substr string) []string {
+	var out []string
+	for _, l := range logs {
+		if strings.Contains(l, substr) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
@@ -0,0 +1,162 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSchemaTyped(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "GetSchemaWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "GetSchemaWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			ctx := context.Background()
+			require.NoError(t, client.AlterSchema(ctx, `
+name: string @index(exact, term) @lang .
+email: string @index(hash) @unique @upsert .
+friend: [uid] @reverse @count .
+embedding: float32vector @index(hnsw(metric: "cosine")) .
+untyped: int .
+type Person {
+	name
+	email
+	friend
+}
+`))
+
+			schema, err := client.GetSchema(ctx)
+			require.NoError(t, err)
+
+			person, ok := schema.Type("Person")
+			require.True(t, ok)
+			require.Equal(t, []string{"name", "email", "friend"}, person.Fields)
+			_, ok = schema.Type("dgraph.graphql")
+			require.False(t, ok, "internal types are omitted")
+
+			name, ok := schema.Predicate("name")
+			require.True(t, ok)
+			require.Equal(t, mg.PredicateInfo{
+				Name: "name", Type: "string", Indexes: []string{"exact", "term"}, Lang: true,
+			}, name)
+
+			email, _ := schema.Predicate("email")
+			require.True(t, email.Unique)
+			require.True(t, email.Upsert)
+
+			friend, _ := schema.Predicate("friend")
+			require.Equal(t, "uid", friend.Type)
+			require.True(t, friend.List)
+			require.True(t, friend.Reverse)
+			require.True(t, friend.Count)
+
+			vec, _ := schema.Predicate("embedding")
+			require.Equal(t, []string{`hnsw(metric: "cosine")`}, vec.Indexes)
+
+			// Predicates outside any type are reported too.
+			untyped, ok := schema.Predicate("untyped")
+			require.True(t, ok)
+			require.Equal(t, "int", untyped.Type)
+			_, ok = schema.Predicate("dgraph.type")
+			require.False(t, ok, "internal predicates are omitted")
+
+			// The rendered schema is valid DQL that round-trips.
+			require.Contains(t, schema.String(), "friend: [uid] @reverse @count .")
+			require.NoError(t, client.AlterSchema(ctx, schema.String()))
+		})
+	}
+}
+
+type ExportBook struct {
+	UID    string   `json:"uid,omitempty"`
+	Title  string   `json:"export_title,omitempty" dgraph:"index=term,exact"`
+	Pages  int      `json:"export_pages,omitempty" dgraph:"index=int"`
+	Author string   `json:"export_author,omitempty" dgraph:"unique"`
+	DType  []string `json:"dgraph.type,omitempty"`
+}
+
+func TestExportSchema(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "ExportSchemaWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "ExportSchemaWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+
+			// The model schema needs no database.
+			var model strings.Builder
+			require.NoError(t, mg.WriteModelSchema(&model, &ExportBook{}))
+			require.Contains(t, model.String(), "export_author: string @index(hash) @upsert @unique .\n")
+			require.Contains(t, model.String(), "type ExportBook {\n")
+
+			require.NoError(t, client.AlterSchema(ctx, model.String()))
+			var exported strings.Builder
+			require.NoError(t, client.ExportSchema(ctx, &exported))
+			require.Contains(t, exported.String(), "export_pages: int @index(int) .\n")
+
+			// The export applies to an empty store and reproduces the schema.
+			before, err := client.GetSchema(ctx)
+			require.NoError(t, err)
+			require.NoError(t, client.DropAll(ctx))
+			require.NoError(t, client.AlterSchema(ctx, exported.String()))
+			after, err := client.GetSchema(ctx)
+			require.NoError(t, err)
+			require.Equal(t, before.String(), after.String())
+
+			diff, err := mg.DiffSchema(ctx, client, &ExportBook{})
+			require.NoError(t, err)
+			require.True(t, diff.Empty(), diff.String())
+		})
+	}
+}
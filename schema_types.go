@@ -0,0 +1,170 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// dgraphTypes lists the types Dgraph accepts for a predicate.
+var dgraphTypes = map[string]bool{
+	"default": true, "int": true, "float": true, "string": true, "bool": true,
+	"datetime": true, "geo": true, "uid": true, "password": true,
+	"bigfloat": true, "float32vector": true,
+}
+
+// predicateField is the struct field that declares a predicate.
+type predicateField struct {
+	owner reflect.Type
+	field reflect.StructField
+}
+
+// resolveSchemaTypes corrects the predicate types dgman derives for named Go
+// types. dgman types a string or bool field by the name of its Go type, so
+// `type Status string` would declare a predicate of type Status, which Dgraph
+// rejects; such predicates get the type of their kind instead. Fields of a
+// shape no Dgraph type holds, such as maps, channels and generic structs
+// without a SchemaType method, are reported as errors rather than left to
+// fail at Alter or to become edges to an unnamed type.
+func resolveSchemaTypes(s *SchemaInfo, models ...any) error {
+	fields := make(map[string]predicateField)
+	seen := make(map[reflect.Type]bool)
+	for _, m := range models {
+		collectPredicateFields(reflect.TypeOf(UnwrapSchema(m)), fields, seen)
+	}
+	for i := range s.Predicates {
+		p := &s.Predicates[i]
+		if dgraphTypes[p.Type] && p.Type != "uid" {
+			continue
+		}
+		f, ok := fields[p.Name]
+		if !ok {
+			continue
+		}
+		typ, list, err := kindSchemaType(f.field.Type)
+		if err != nil {
+			return fmt.Errorf("modusgraph: field %s.%s (%s): %w", f.owner.Name(), f.field.Name, p.Name, err)
+		}
+		if p.Type != "uid" {
+			p.Type, p.List = typ, list
+		}
+	}
+	// dgman declares a type for every struct an edge points to, generic
+	// instantiations included, whose names Dgraph does not accept.
+	s.Types = slices.DeleteFunc(s.Types, func(t TypeInfo) bool {
+		return strings.ContainsAny(t.Name, "[]")
+	})
+	return nil
+}
+
+// collectPredicateFields records the fields of t, its embedded structs and
+// the structs its edges point to by predicate. The first field declaring a
+// predicate wins, as it does in dgman.
+func collectPredicateFields(t reflect.Type, fields map[string]predicateField, seen map[reflect.Type]bool) {
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || seen[t] {
+		return
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if f.Anonymous {
+			collectPredicateFields(f.Type, fields, seen)
+			continue
+		}
+		pred := fieldPredicate(f)
+		if pred == "" {
+			continue
+		}
+		if _, ok := fields[pred]; !ok {
+			fields[pred] = predicateField{owner: t, field: f}
+		}
+		collectPredicateFields(f.Type, fields, seen)
+	}
+}
+
+// kindSchemaType returns the Dgraph type of values of Go type t from its
+// SchemaType method or its kind, and whether t is a list.
+func kindSchemaType(t reflect.Type) (typ string, list bool, err error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if st, ok := reflect.New(t).Interface().(dg.SchemaType); ok {
+		typ = st.SchemaType()
+		return strings.Trim(typ, "[]"), strings.HasPrefix(typ, "["), nil
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		elem := t.Elem()
+		for elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+		if k := elem.Kind(); k == reflect.Slice || k == reflect.Array {
+			return "", false, fmt.Errorf("%s is a list of lists, which Dgraph does not store", t)
+		}
+		typ, _, err = kindSchemaType(elem)
+		return typ, true, err
+	case reflect.String:
+		return "string", false, nil
+	case reflect.Bool:
+		return "bool", false, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int", false, nil
+	case reflect.Float32, reflect.Float64:
+		return "float", false, nil
+	case reflect.Interface:
+		return "uid", false, nil
+	case reflect.Struct:
+		if strings.Contains(t.Name(), "[") {
+			return "", false, fmt.Errorf("generic type %s has no Dgraph type; "+
+				"use Nullable, or give the type a SchemaType method", t)
+		}
+		return "uid", false, nil
+	}
+	return "", false, fmt.Errorf("%s has no Dgraph type; give it a SchemaType method", t)
+}
+
+// checkUIDFields returns an error when obj, or a struct its edges point to,
+// has a UID field of a named string type such as `type ID string`. dgman
+// assigns the blank node names of new nodes to plain string fields only.
+func checkUIDFields(obj any) error {
+	return checkUIDType(reflect.TypeOf(UnwrapSchema(obj)), make(map[reflect.Type]bool))
+}
+
+func checkUIDType(t reflect.Type, seen map[reflect.Type]bool) error {
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || seen[t] {
+		return nil
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if !f.Anonymous && fieldPredicate(f) == "uid" &&
+			f.Type.Kind() == reflect.String && f.Type != reflect.TypeFor[string]() {
+			return fmt.Errorf("modusgraph: UID field %s.%s must be a plain string, not %s", t.Name(), f.Name, f.Type)
+		}
+		if err := checkUIDType(f.Type, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
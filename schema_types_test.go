@@ -0,0 +1,131 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	TicketStatus string
+	TicketFlag   bool
+	TicketLabels []string
+	TicketCount  = int
+)
+
+type NamedTypeTicket struct {
+	UID      string         `json:"uid,omitempty"`
+	Status   TicketStatus   `json:"ticket_status,omitempty" dgraph:"index=exact"`
+	Urgent   TicketFlag     `json:"ticket_urgent,omitempty"`
+	Labels   TicketLabels   `json:"ticket_labels,omitempty" dgraph:"index=exact"`
+	History  []TicketStatus `json:"ticket_history,omitempty"`
+	Comments TicketCount    `json:"ticket_comments,omitempty" dgraph:"index=int"`
+	DType    []string       `json:"dgraph.type,omitempty"`
+}
+
+func TestNamedFieldTypes(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "NamedFieldTypesWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "NamedFieldTypesWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+
+			// Named types get the Dgraph type of their kind, not their name.
+			var model strings.Builder
+			require.NoError(t, mg.WriteModelSchema(&model, &NamedTypeTicket{}))
+			require.Contains(t, model.String(), "ticket_status: string @index(exact) .\n")
+			require.Contains(t, model.String(), "ticket_urgent: bool .\n")
+			require.Contains(t, model.String(), "ticket_labels: [string] @index(exact) .\n")
+			require.Contains(t, model.String(), "ticket_history: [string] .\n")
+
+			ticket := &NamedTypeTicket{
+				Status:   "open",
+				Urgent:   true,
+				Labels:   TicketLabels{"billing"},
+				History:  []TicketStatus{"new"},
+				Comments: 2,
+			}
+			require.NoError(t, client.Insert(ctx, ticket))
+
+			var got NamedTypeTicket
+			require.NoError(t, client.Get(ctx, &got, ticket.UID))
+			require.Equal(t, TicketStatus("open"), got.Status)
+			require.True(t, bool(got.Urgent))
+			require.Equal(t, TicketLabels{"billing"}, got.Labels)
+			require.Equal(t, []TicketStatus{"new"}, got.History)
+			require.Equal(t, 2, got.Comments)
+
+			open, err := mg.Query[NamedTypeTicket](ctx, client).Filter(`eq(ticket_status, "open")`).All()
+			require.NoError(t, err)
+			require.Len(t, open, 1)
+
+			diff, err := mg.DiffSchema(ctx, client, &NamedTypeTicket{})
+			require.NoError(t, err)
+			require.Empty(t, diff.Mismatches, diff.String())
+		})
+	}
+}
+
+// TicketOptional is a generic wrapper without a SchemaType method.
+type TicketOptional[T any] struct {
+	Value T
+}
+
+func TestUnsupportedFieldTypes(t *testing.T) {
+	type mapTicket struct {
+		UID   string            `json:"uid,omitempty"`
+		Meta  map[string]string `json:"ticket_meta,omitempty"`
+		DType []string          `json:"dgraph.type,omitempty"`
+	}
+	err := mg.WriteModelSchema(io.Discard, &mapTicket{})
+	require.ErrorContains(t, err, "field mapTicket.Meta (ticket_meta): map[string]string has no Dgraph type")
+
+	type genericTicket struct {
+		UID   string                 `json:"uid,omitempty"`
+		Due   TicketOptional[string] `json:"ticket_due,omitempty"`
+		DType []string               `json:"dgraph.type,omitempty"`
+	}
+	err = mg.WriteModelSchema(io.Discard, &genericTicket{})
+	require.ErrorContains(t, err, "generic type modusgraph_test.TicketOptional[string] has no Dgraph type")
+
+	type TicketID string
+	type idTicket struct {
+		UID   TicketID `json:"uid,omitempty"`
+		DType []string `json:"dgraph.type,omitempty"`
+	}
+	client, cleanup := CreateTestClient(t, "file://"+GetTempDir(t))
+	defer cleanup()
+	err = client.Insert(context.Background(), &idTicket{})
+	require.ErrorContains(t, err, "UID field idTicket.UID must be a plain string")
+}
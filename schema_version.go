@@ -0,0 +1,148 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+)
+
+// ErrSchemaTooNew is returned by NewClient when the schema version recorded
+// in the database is newer than the one given to WithSchemaVersion, that is
+// when an old binary connects to a database migrated by a newer one.
+var ErrSchemaTooNew = errors.New("modusgraph: database schema is newer than this binary supports")
+
+// Predicate and type of the node recording the schema version.
+const (
+	schemaVersionPredicate = "modusgraph.schema.version"
+	schemaVersionType      = "ModusGraphSchemaVersion"
+)
+
+const schemaVersionSchema = schemaVersionPredicate + `: int .
+type ` + schemaVersionType + ` {
+	` + schemaVersionPredicate + `
+}
+`
+
+// WithSchemaVersion declares the schema version this binary was built
+// against, typically the highest migration version it registers. NewClient
+// reads the version recorded in the database by SetSchemaVersion or the
+// migrate package and fails with ErrSchemaTooNew if it is higher, so that an
+// outdated deployment stops at startup instead of misreading or overwriting
+// data it does not model. A database with no recorded version, or an older
+// one, is accepted.
+func WithSchemaVersion(version int64) ClientOpt {
+	return func(o *clientOptions) {
+		o.schemaVersion = version
+	}
+}
+
+// checkSchemaVersion compares the version recorded in the database with the
+// one given to WithSchemaVersion.
+func (c client) checkSchemaVersion(ctx context.Context) error {
+	if c.options.schemaVersion == 0 {
+		return nil
+	}
+	stored, err := c.SchemaVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if stored > c.options.schemaVersion {
+		return fmt.Errorf("%w: database is at schema version %d, this binary supports up to %d; upgrade it before connecting",
+			ErrSchemaTooNew, stored, c.options.schemaVersion)
+	}
+	return nil
+}
+
+// SchemaVersion implements reading the recorded schema version, 0 if none.
+func (c client) SchemaVersion(ctx context.Context) (int64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	_, version, err := c.schemaVersionNode(ctx)
+	return version, err
+}
+
+// SetSchemaVersion implements recording the schema version.
+func (c client) SetSchemaVersion(ctx context.Context, version int64) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	if version <= 0 {
+		return fmt.Errorf("schema version must be positive, got %d", version)
+	}
+	if err := c.AlterSchema(ctx, schemaVersionSchema); err != nil {
+		return err
+	}
+	uid, _, err := c.schemaVersionNode(ctx)
+	if err != nil {
+		return err
+	}
+	if uid == "" {
+		uid = "_:version"
+	}
+	set, err := json.Marshal(map[string]any{
+		"uid":                  uid,
+		"dgraph.type":          schemaVersionType,
+		schemaVersionPredicate: version,
+	})
+	if err != nil {
+		return err
+	}
+
+	dgClient, err := c.pool.get()
+	if err != nil {
+		return err
+	}
+	defer c.pool.put(dgClient)
+	_, err = dgClient.NewTxn().Mutate(ctx, &api.Mutation{SetJson: set, CommitNow: true})
+	return err
+}
+
+// schemaVersionNode returns the uid and version of the node recording the
+// schema version, or an empty uid if there is none.
+func (c client) schemaVersionNode(ctx context.Context) (string, int64, error) {
+	// Databases that never recorded a version lack the predicate, which the
+	// embedded engine cannot query.
+	schema, err := c.GetSchema(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	if _, ok := schema.Predicate(schemaVersionPredicate); !ok {
+		return "", 0, nil
+	}
+
+	dgClient, err := c.pool.get()
+	if err != nil {
+		return "", 0, err
+	}
+	defer c.pool.put(dgClient)
+
+	query := fmt.Sprintf("{\n  v(func: has(%s), first: 1) {\n    uid\n    version: %s\n  }\n}\n",
+		schemaVersionPredicate, schemaVersionPredicate)
+	resp, err := dgClient.NewReadOnlyTxn().Query(ctx, query)
+	if err != nil {
+		return "", 0, err
+	}
+	var result struct {
+		V []struct {
+			UID     string `json:"uid"`
+			Version int64  `json:"version"`
+		} `json:"v"`
+	}
+	if err := json.Unmarshal(resp.Json, &result); err != nil {
+		return "", 0, err
+	}
+	if len(result.V) == 0 {
+		return "", 0, nil
+	}
+	return result.V[0].UID, result.V[0].Version, nil
+}
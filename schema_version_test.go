@@ -0,0 +1,54 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	uri := "file://" + GetTempDir(t)
+	ctx := context.Background()
+
+	// A database that never recorded a version is accepted.
+	client, err := mg.NewClient(uri, mg.WithSchemaVersion(3))
+	require.NoError(t, err)
+	version, err := client.SchemaVersion(ctx)
+	require.NoError(t, err)
+	require.Zero(t, version)
+
+	require.NoError(t, client.SetSchemaVersion(ctx, 3))
+	require.NoError(t, client.SetSchemaVersion(ctx, 5))
+	version, err = client.SchemaVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), version)
+	require.ErrorContains(t, client.SetSchemaVersion(ctx, 0), "must be positive")
+
+	// One node holds the version however often it is set.
+	resp, err := client.QueryRaw(ctx, `{ q(func: type(ModusGraphSchemaVersion)) { count(uid) } }`, nil)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"q":[{"count":1}]}`, string(resp))
+	client.Close()
+	mg.Shutdown()
+
+	// An older binary fails fast.
+	_, err = mg.NewClient(uri, mg.WithSchemaVersion(4))
+	require.ErrorIs(t, err, mg.ErrSchemaTooNew)
+	require.ErrorContains(t, err, "database is at schema version 5, this binary supports up to 4")
+	mg.Shutdown()
+
+	// The current and newer binaries, and unversioned clients, start.
+	for _, opts := range [][]mg.ClientOpt{{mg.WithSchemaVersion(5)}, {mg.WithSchemaVersion(6)}, nil} {
+		client, err = mg.NewClient(uri, opts...)
+		require.NoError(t, err)
+		client.Close()
+		mg.Shutdown()
+	}
+}
@@ -0,0 +1,219 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// hnswMetric finds the metric option of an hnsw index as Dgraph reports it.
+var hnswMetric = regexp.MustCompile(`metric:\s*"?(\w+)`)
+
+// SimilarTo fills obj with the k nodes of its type whose vectors in field are
+// nearest to vec, nearest first. obj is a pointer to a slice of structs or
+// struct pointers, or a pointer to a struct to load only the nearest node;
+// the latter returns dgman's ErrNodeNotFound when there is none.
+//
+// field is the predicate of a float32vector field with an hnsw index, such
+// as
+//
+//	Embedding *dg.VectorFloat32 `json:"embedding,omitempty" dgraph:"index=hnsw(metric:cosine)"`
+//
+// or of a SimString field tagged dgraph:"embedding", whose shadow vector is
+// searched. Nodes are ranked by the metric of the index. The index is searched
+// for k neighbours before they are filtered to obj's type, so fewer may be
+// returned when other types share the predicate.
+func (c client) SimilarTo(ctx context.Context, obj any, field string, vec []float32, k int) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	obj = UnwrapSchema(obj)
+	if err := checkPointer(obj); err != nil {
+		return err
+	}
+	dst := reflect.ValueOf(obj).Elem()
+	single := dst.Kind() == reflect.Struct
+	if !single && dst.Kind() != reflect.Slice {
+		return fmt.Errorf("modusgraph: SimilarTo: obj must point to a struct or slice, got %T", obj)
+	}
+	if k <= 0 {
+		dst.SetZero()
+		return nil
+	}
+	if single {
+		k = 1
+	}
+
+	pred := field
+	for _, f := range collectSimFieldInfoFromType(dst.Type()) {
+		if f.jsonPredicate == field {
+			pred = f.vecPredicate
+		}
+	}
+	schema, err := c.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+	p, ok := schema.Predicate(pred)
+	if !ok || p.Type != "float32vector" {
+		return fmt.Errorf("modusgraph: SimilarTo: %q is not a float32vector predicate", pred)
+	}
+	metric := ""
+	for _, idx := range p.Indexes {
+		if !strings.HasPrefix(idx, "hnsw") {
+			continue
+		}
+		metric = "euclidean"
+		if m := hnswMetric.FindStringSubmatch(idx); m != nil {
+			metric = m[1]
+		}
+	}
+	if metric == "" {
+		return fmt.Errorf("modusgraph: SimilarTo: %q has no hnsw index", pred)
+	}
+
+	// Read the neighbours' vectors to rank them, then load the nodes.
+	elem := dst.Type()
+	if !single {
+		elem = elem.Elem()
+	}
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	nodeType := dg.GetNodeType(reflect.New(elem).Interface())
+	q := fmt.Sprintf(`query nearest($vec: string) {
+	nearest(func: similar_to(<%s>, %d, $vec)) @filter(type(<%s>)) {
+		uid
+		vec: <%s>
+	}
+}`, pred, k, nodeType, pred)
+	resp, err := c.QueryRaw(ctx, q, map[string]string{"$vec": vectorToQueryString(vec)})
+	if err != nil {
+		return err
+	}
+	var result struct {
+		Nearest []struct {
+			UID string          `json:"uid"`
+			Vec json.RawMessage `json:"vec"`
+		} `json:"nearest"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return err
+	}
+	scores := make(map[string]float64, len(result.Nearest))
+	uids := make([]string, 0, len(result.Nearest))
+	for _, n := range result.Nearest {
+		stored, err := parseVector(n.Vec)
+		if err != nil {
+			return fmt.Errorf("modusgraph: SimilarTo: decode vector of %s: %w", n.UID, err)
+		}
+		scores[n.UID] = similarity(metric, vec, stored)
+		uids = append(uids, n.UID)
+	}
+	if len(uids) == 0 {
+		if single {
+			return dg.ErrNodeNotFound
+		}
+		dst.SetZero()
+		return nil
+	}
+
+	nodes := reflect.New(reflect.SliceOf(reflect.PointerTo(elem)))
+	client, err := c.pool.get()
+	if err != nil {
+		return err
+	}
+	defer c.pool.put(client)
+	txn := dg.NewReadOnlyTxnContext(ctx, client)
+	model := nodes.Interface()
+	if err := expand(txn.Get(model).UID(strings.Join(uids, ", ")), model,
+		c.options.maxEdgeTraversal, c.options.maxEdgeFanout).Nodes(); err != nil {
+		return err
+	}
+	found := nodes.Elem()
+	ranked := make([]reflect.Value, found.Len())
+	for i := range ranked {
+		ranked[i] = found.Index(i)
+	}
+	uidOf := func(v reflect.Value) string {
+		if f := v.Elem().FieldByName("UID"); f.Kind() == reflect.String {
+			return f.String()
+		}
+		return ""
+	}
+	slices.SortStableFunc(ranked, func(a, b reflect.Value) int {
+		return cmp.Compare(scores[uidOf(b)], scores[uidOf(a)])
+	})
+
+	if single {
+		if len(ranked) == 0 {
+			return dg.ErrNodeNotFound
+		}
+		dst.Set(ranked[0].Elem())
+	} else {
+		out := reflect.MakeSlice(dst.Type(), len(ranked), len(ranked))
+		for i, v := range ranked {
+			if out.Index(i).Kind() == reflect.Ptr {
+				out.Index(i).Set(v)
+			} else {
+				out.Index(i).Set(v.Elem())
+			}
+		}
+		dst.Set(out)
+	}
+	NormalizeTimes(c, obj)
+	return LoadLocalized(ctx, c, obj)
+}
+
+// similarity scores stored against query by metric, higher being nearer:
+// cosine similarity, the dot product, or the negated euclidean distance.
+func similarity(metric string, query, stored []float32) float64 {
+	var dot, qq, ss, dist float64
+	for i := range min(len(query), len(stored)) {
+		q, v := float64(query[i]), float64(stored[i])
+		dot += q * v
+		qq += q * q
+		ss += v * v
+		dist += (q - v) * (q - v)
+	}
+	switch metric {
+	case "dotproduct":
+		return dot
+	case "euclidean":
+		return -math.Sqrt(dist)
+	}
+	if qq == 0 || ss == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(qq*ss)
+}
+
+// parseVector decodes a float32vector from a query response, where it may be
+// a JSON array or a string holding one.
+func parseVector(raw json.RawMessage) ([]float32, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	if raw[0] == '"' {
+		var str string
+		if err := json.Unmarshal(raw, &str); err != nil {
+			return nil, err
+		}
+		raw = json.RawMessage(str)
+	}
+	var vec []float32
+	err := json.Unmarshal(raw, &vec)
+	return vec, err
+}
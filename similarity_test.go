@@ -0,0 +1,82 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	dg "github.com/dolan-in/dgman/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type SimilarTrack struct {
+	Title     string            `json:"title,omitempty" dgraph:"index=exact"`
+	Embedding *dg.VectorFloat32 `json:"track_embedding,omitempty" dgraph:"index=hnsw(metric:euclidean)"`
+
+	UID   string   `json:"uid,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+func TestSimilarTo(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "SimilarToWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "SimilarToWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+
+			tracks := []*SimilarTrack{
+				{Title: "near", Embedding: &dg.VectorFloat32{Values: []float32{1, 0, 0}}},
+				{Title: "nearer", Embedding: &dg.VectorFloat32{Values: []float32{0.1, 0, 0}}},
+				{Title: "far", Embedding: &dg.VectorFloat32{Values: []float32{5, 5, 5}}},
+			}
+			require.NoError(t, client.Insert(ctx, tracks))
+
+			// Results come back nearest first, not in UID order.
+			query := []float32{0, 0, 0}
+			var nearest []SimilarTrack
+			require.NoError(t, client.SimilarTo(ctx, &nearest, "track_embedding", query, 2))
+			require.Len(t, nearest, 2)
+			require.Equal(t, "nearer", nearest[0].Title)
+			require.Equal(t, "near", nearest[1].Title)
+			require.Equal(t, []float32{0.1, 0, 0}, nearest[0].Embedding.Values)
+
+			var all []*SimilarTrack
+			require.NoError(t, client.SimilarTo(ctx, &all, "track_embedding", []float32{4, 4, 4}, 10))
+			require.Len(t, all, 3)
+			require.Equal(t, "far", all[0].Title)
+
+			var one SimilarTrack
+			require.NoError(t, client.SimilarTo(ctx, &one, "track_embedding", []float32{1, 0.1, 0}, 5))
+			require.Equal(t, "near", one.Title)
+
+			require.ErrorContains(t, client.SimilarTo(ctx, &all, "title", query, 1),
+				`"title" is not a float32vector predicate`)
+		})
+	}
+}
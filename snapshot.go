@@ -0,0 +1,64 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// Snapshot is a read handle pinned to one timestamp. Every read through it
+// reads values as of that timestamp, however many writes land in between, so
+// the figures of a report built from several queries agree:
+//
+//	snap, err := client.Snapshot(ctx)
+//	open, err := modusgraph.Query[Order](snap.Context(ctx), client).Filter(`eq(status, "open")`).All()
+//	totals, err := snap.QueryRaw(ctx, `{ q(func: type(Order)) { total } }`, nil)
+//
+// Reads through a Snapshot have the limits of ReadAt: values are as of its
+// timestamp, while edges and root functions match as they do now. A Snapshot
+// is cheap and safe for concurrent use. On an embedded store with
+// WithHistoryRetention, reads through it fail with ErrReadTsDiscarded once it
+// is older than the retention period.
+type Snapshot struct {
+	client Client
+	ts     uint64
+}
+
+// Snapshot implements pinning a read handle to the latest committed state.
+func (c client) Snapshot(ctx context.Context) (*Snapshot, error) {
+	ts, err := c.ReadTs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{client: c, ts: ts}, nil
+}
+
+// Ts returns the timestamp s reads at.
+func (s *Snapshot) Ts() uint64 { return s.ts }
+
+// Context returns a copy of ctx under which client reads see s's state, for
+// APIs that take a context and a client, such as the generic Query and the
+// typed client.
+func (s *Snapshot) Context(ctx context.Context) context.Context {
+	return ReadAt(ctx, s.ts)
+}
+
+// Get is Client.Get at s's timestamp.
+func (s *Snapshot) Get(ctx context.Context, obj any, uid string, opts ...GetOpt) error {
+	return s.client.Get(s.Context(ctx), obj, uid, opts...)
+}
+
+// Query is Client.Query at s's timestamp.
+func (s *Snapshot) Query(ctx context.Context, model any) *dg.Query {
+	return s.client.Query(s.Context(ctx), model)
+}
+
+// QueryRaw is Client.QueryRaw at s's timestamp.
+func (s *Snapshot) QueryRaw(ctx context.Context, q string, vars map[string]string) ([]byte, error) {
+	return s.client.QueryRaw(s.Context(ctx), q, vars)
+}
@@ -0,0 +1,86 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/typed"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "SnapshotWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "SnapshotWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			ctx := context.Background()
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			first := &HistoryRecord{Name: "order-1", Status: "placed"}
+			require.NoError(t, client.Insert(ctx, &[]*HistoryRecord{first, {Name: "order-2", Status: "placed"}}))
+			snap, err := client.Snapshot(ctx)
+			require.NoError(t, err)
+
+			// Writes after the snapshot are invisible through it.
+			require.NoError(t, client.Update(ctx, &HistoryRecord{UID: first.UID, Name: "order-1", Status: "shipped"}))
+			require.NoError(t, client.Insert(ctx, &HistoryRecord{Name: "order-3", Status: "placed"}))
+
+			var got HistoryRecord
+			require.NoError(t, snap.Get(ctx, &got, first.UID))
+			require.Equal(t, "placed", got.Status)
+
+			var nodes []HistoryRecord
+			require.NoError(t, snap.Query(ctx, HistoryRecord{}).Nodes(&nodes))
+			require.Len(t, nodes, 2)
+
+			q := `{ q(func: type(HistoryRecord), orderasc: hist_name) { hist_name hist_status } }`
+			raw, err := snap.QueryRaw(ctx, q, nil)
+			require.NoError(t, err)
+			var result struct {
+				Q []HistoryRecord `json:"q"`
+			}
+			require.NoError(t, json.Unmarshal(raw, &result))
+			require.Len(t, result.Q, 2)
+			require.Equal(t, "placed", result.Q[0].Status)
+
+			records, err := typed.NewClient[HistoryRecord](client).Query(snap.Context(ctx)).
+				Filter(`eq(hist_status, "placed")`).
+				Nodes()
+			require.NoError(t, err)
+			require.Len(t, records, 2)
+
+			// The client itself reads the latest state.
+			all, err := mg.Query[HistoryRecord](ctx, client).All()
+			require.NoError(t, err)
+			require.Len(t, all, 3)
+		})
+	}
+}
@@ -0,0 +1,185 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/dgo/v250"
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// DeletePurge makes Delete remove soft-deletable nodes instead of marking
+// them deleted; see SoftDeletePredicate.
+func DeletePurge() DeleteOpt {
+	return func(o *deleteOptions) {
+		o.purge = true
+	}
+}
+
+// SoftDeletePredicate returns the predicate of the time field of model's
+// type tagged dgraph:"softdelete", and whether it has one:
+//
+//	DeletedAt *time.Time `json:"deleted_at,omitempty" dgraph:"softdelete"`
+//
+// Delete sets that predicate to the time of deletion on nodes of such a type
+// instead of removing them, unless given DeletePurge, and Undelete clears
+// it. Query, and typed clients' queries, leave out the nodes that have it
+// unless asked to include them. Get still loads them.
+func SoftDeletePredicate(model any) (string, bool) {
+	t := reflect.TypeOf(UnwrapSchema(model))
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return "", false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.IsExported() && ft == timeType && slices.Contains(strings.Fields(sf.Tag.Get("dgraph")), "softdelete") {
+			return fieldPredicate(sf), true
+		}
+	}
+	return "", false
+}
+
+// Undelete clears the soft-delete predicate of the nodes obj names, as
+// Delete takes them, restoring them to queries. Nodes of types without one
+// are left alone.
+func (c client) Undelete(ctx context.Context, obj any) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	if _, ok := obj.([]string); !ok {
+		c.policies.register(obj)
+	}
+	uids, err := deleteTargets(obj, false)
+	if err != nil {
+		return err
+	}
+	if err := checkUIDs(uids...); err != nil {
+		return err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	client, err := c.pool.get()
+	if err != nil {
+		c.log(ctx).Error(err, "Failed to get client from pool")
+		return err
+	}
+	defer c.pool.put(client)
+
+	tx := dg.NewTxnContext(ctx, client)
+	defer func() { _ = tx.Txn().Discard(ctx) }()
+	marked, err := softDeletable(ctx, tx, uids, c.policies.softDeletePredicates())
+	if err != nil {
+		return err
+	}
+	if len(marked) == 0 {
+		return nil
+	}
+	var nquads strings.Builder
+	for uid, pred := range marked {
+		fmt.Fprintf(&nquads, "<%s> <%s> * .\n", uid, pred)
+	}
+	if _, err := tx.Txn().Mutate(ctx, &api.Mutation{DelNquads: []byte(nquads.String())}); err != nil {
+		return fmt.Errorf("clearing soft deletes: %w", err)
+	}
+	return tx.Txn().Commit(ctx)
+}
+
+// deleteSoft is Delete when soft-deletable types are registered: it marks
+// the nodes of those types deleted and removes the others, in one
+// transaction.
+func (c client) deleteSoft(ctx context.Context, client *dgo.Dgraph, uids []string, o deleteOptions) error {
+	tx := dg.NewTxnContext(ctx, client)
+	defer func() { _ = tx.Txn().Discard(ctx) }()
+	uids, err := c.markDeleted(ctx, tx, uids)
+	if err != nil {
+		return err
+	}
+	switch rules := c.policies.deleteRules(); {
+	case len(uids) == 0:
+	case len(rules) > 0 || o.detach:
+		if _, _, err := c.deleteInTxn(ctx, tx, uids, rules, o.detach); err != nil {
+			return err
+		}
+	default:
+		if err := tx.DeleteNode(uids...); err != nil {
+			return err
+		}
+	}
+	if err := tx.Txn().Commit(ctx); err != nil {
+		return fmt.Errorf("committing delete: %w", err)
+	}
+	return nil
+}
+
+// markDeleted sets the soft-delete predicate of each of uids whose type has
+// one to the client's time, in tx, and returns the others, which are to be
+// removed.
+func (c client) markDeleted(ctx context.Context, tx *dg.TxnContext, uids []string) ([]string, error) {
+	marked, err := softDeletable(ctx, tx, uids, c.policies.softDeletePredicates())
+	if err != nil || len(marked) == 0 {
+		return uids, err
+	}
+	stamp := c.Now().UTC().Format(time.RFC3339Nano)
+	var nquads strings.Builder
+	for uid, pred := range marked {
+		fmt.Fprintf(&nquads, "<%s> <%s> %q^^<xs:dateTime> .\n", uid, pred, stamp)
+	}
+	if _, err := tx.Txn().Mutate(ctx, &api.Mutation{SetNquads: []byte(nquads.String())}); err != nil {
+		return nil, fmt.Errorf("marking nodes deleted: %w", err)
+	}
+	return slices.DeleteFunc(slices.Clone(uids), func(uid string) bool {
+		_, ok := marked[uid]
+		return ok
+	}), nil
+}
+
+// softDeletable returns the soft-delete predicate of each of uids whose
+// stored type has one in preds, by UID.
+func softDeletable(ctx context.Context, tx *dg.TxnContext, uids []string, preds map[string]string) (map[string]string,
+	error) {
+	if len(preds) == 0 || len(uids) == 0 {
+		return nil, nil
+	}
+	query := fmt.Sprintf("{\n  q(func: uid(%s)) { uid dgraph.type }\n}", strings.Join(uids, ", "))
+	resp, err := tx.Txn().Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("reading node types: %w", err)
+	}
+	var result struct {
+		Q []struct {
+			UID   string   `json:"uid"`
+			Types []string `json:"dgraph.type"`
+		} `json:"q"`
+	}
+	if err := json.Unmarshal(resp.Json, &result); err != nil {
+		return nil, fmt.Errorf("reading node types: %w", err)
+	}
+	marked := make(map[string]string)
+	for _, node := range result.Q {
+		for _, t := range node.Types {
+			if pred, ok := preds[t]; ok {
+				marked[node.UID] = pred
+				break
+			}
+		}
+	}
+	return marked, nil
+}
@@ -0,0 +1,139 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	dg "github.com/dolan-in/dgman/v2"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type SoftNote struct {
+	UID       string     `json:"uid,omitempty"`
+	Title     string     `json:"sd_title,omitempty" dgraph:"index=exact"`
+	DeletedAt *time.Time `json:"sd_deleted_at,omitempty" dgraph:"softdelete"`
+	DType     []string   `json:"dgraph.type,omitempty"`
+}
+
+func TestSoftDelete(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "SoftDeleteWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "SoftDeleteWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+
+			pred, ok := mg.SoftDeletePredicate(&SoftNote{})
+			require.True(t, ok)
+			require.Equal(t, "sd_deleted_at", pred)
+
+			draft := &SoftNote{Title: "draft"}
+			final := &SoftNote{Title: "final"}
+			scratch := &SoftNote{Title: "scratch"}
+			require.NoError(t, mg.Insert(ctx, client, draft, final, scratch))
+			titles := func(q *mg.TypedQuery[SoftNote]) []string {
+				t.Helper()
+				notes, err := q.OrderAsc("sd_title").All()
+				require.NoError(t, err)
+				var titles []string
+				for _, n := range notes {
+					titles = append(titles, n.Title)
+				}
+				return titles
+			}
+
+			// Delete marks the node; queries leave it out, Get still loads it.
+			before := time.Now().Add(-time.Second)
+			require.NoError(t, client.Delete(ctx, draft))
+			got, err := mg.Get[SoftNote](ctx, client, draft.UID)
+			require.NoError(t, err)
+			require.NotNil(t, got.DeletedAt)
+			require.True(t, got.DeletedAt.After(before))
+			require.Equal(t, []string{"final", "scratch"}, titles(mg.Query[SoftNote](ctx, client)))
+			require.Equal(t, []string{"draft", "final", "scratch"},
+				titles(mg.Query[SoftNote](ctx, client).IncludeDeleted()))
+			require.Equal(t, []string{"final"},
+				titles(mg.Query[SoftNote](ctx, client).Filter(`eq(sd_title, "final")`)))
+
+			// So do deletes by UID and in transactions.
+			require.NoError(t, client.DeleteByUID(ctx, final.UID))
+			tx, err := client.Tx(ctx)
+			require.NoError(t, err)
+			require.NoError(t, tx.Delete(ctx, scratch))
+			require.NoError(t, tx.Commit(ctx))
+			require.Empty(t, titles(mg.Query[SoftNote](ctx, client)))
+
+			// Undelete brings nodes back.
+			require.NoError(t, client.Undelete(ctx, []string{draft.UID, final.UID}))
+			require.Equal(t, []string{"draft", "final"}, titles(mg.Query[SoftNote](ctx, client)))
+			got, err = mg.Get[SoftNote](ctx, client, draft.UID)
+			require.NoError(t, err)
+			require.Nil(t, got.DeletedAt)
+
+			// DeletePurge removes them for good.
+			require.NoError(t, client.Delete(ctx, draft, mg.DeletePurge()))
+			_, err = mg.Get[SoftNote](ctx, client, draft.UID)
+			require.ErrorIs(t, err, dg.ErrNodeNotFound)
+			require.Equal(t, []string{"final", "scratch"},
+				titles(mg.Query[SoftNote](ctx, client).IncludeDeleted()))
+		})
+	}
+}
+
+func TestSoftDeleteByModelType(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	client, err := mg.NewClient("file://"+dir, mg.WithAutoSchema(true))
+	require.NoError(t, err)
+	draft := &SoftNote{Title: "draft"}
+	final := &SoftNote{Title: "final"}
+	require.NoError(t, mg.Insert(ctx, client, draft, final))
+	client.Close()
+
+	// A fresh client reads the softdelete field from the struct it is given
+	// to delete, and from then on applies it to DeleteByUID too.
+	deletedAt := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	client, err = mg.NewClient("file://"+dir, mg.WithClock(func() time.Time { return deletedAt }))
+	require.NoError(t, err)
+	defer client.Close()
+	tx, err := client.Tx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Delete(ctx, &SoftNote{UID: draft.UID}))
+	require.NoError(t, tx.Commit(ctx))
+	require.NoError(t, client.DeleteByUID(ctx, final.UID))
+
+	for _, uid := range []string{draft.UID, final.UID} {
+		got, err := mg.Get[SoftNote](ctx, client, uid)
+		require.NoError(t, err)
+		require.NotNil(t, got.DeletedAt, "%s was removed instead of marked deleted", uid)
+		require.True(t, got.DeletedAt.Equal(deletedAt))
+	}
+}
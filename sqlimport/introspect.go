@@ -0,0 +1,243 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package sqlimport
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Dialect is the SQL database a Schema was read from. It selects the
+// catalog queries Introspect runs and how Import quotes identifiers.
+type Dialect int
+
+const (
+	SQLite Dialect = iota
+	Postgres
+	MySQL
+)
+
+func (d Dialect) String() string {
+	switch d {
+	case SQLite:
+		return "sqlite"
+	case Postgres:
+		return "postgres"
+	case MySQL:
+		return "mysql"
+	}
+	return fmt.Sprintf("Dialect(%d)", int(d))
+}
+
+// Column is a table column. Type is the declared SQL type as the database
+// reports it.
+type Column struct {
+	Name     string
+	Type     string
+	Nullable bool
+}
+
+// ForeignKey references RefColumns of RefTable from Columns, pairwise.
+type ForeignKey struct {
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+}
+
+// Table is a table's columns and keys.
+type Table struct {
+	Name        string
+	Columns     []Column
+	PrimaryKey  []string
+	ForeignKeys []ForeignKey
+}
+
+// Schema is a database's tables, as read by Introspect. It can be edited
+// before Import, for example to declare foreign keys the database lacks.
+type Schema struct {
+	Dialect Dialect
+	Tables  []Table
+}
+
+// Table returns the named table, or nil.
+func (s *Schema) Table(name string) *Table {
+	for i := range s.Tables {
+		if s.Tables[i].Name == name {
+			return &s.Tables[i]
+		}
+	}
+	return nil
+}
+
+// catalog holds a dialect's introspection queries. Each takes the table name
+// as its only parameter except tables, which takes none.
+type catalog struct {
+	// tables returns one table name per row.
+	tables string
+	// columns returns name, declared type, and 'YES' or 'NO' for nullable.
+	columns string
+	// primaryKey returns the primary key's column names in key order.
+	primaryKey string
+	// foreignKeys returns constraint name, column, referenced table, and
+	// referenced column, ordered by constraint then position. The referenced
+	// column may be empty, meaning the referenced table's primary key.
+	foreignKeys string
+}
+
+var catalogs = map[Dialect]catalog{
+	SQLite: {
+		tables: `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`,
+		columns: `SELECT name, type, CASE WHEN "notnull" = 0 THEN 'YES' ELSE 'NO' END
+			FROM pragma_table_info(?) ORDER BY cid`,
+		primaryKey: `SELECT name FROM pragma_table_info(?) WHERE pk > 0 ORDER BY pk`,
+		foreignKeys: `SELECT CAST(id AS TEXT), "from", "table", COALESCE("to", '')
+			FROM pragma_foreign_key_list(?) ORDER BY id, seq`,
+	},
+	Postgres: {
+		tables: `SELECT table_name FROM information_schema.tables
+			WHERE table_schema = current_schema() AND table_type = 'BASE TABLE' ORDER BY table_name`,
+		columns: `SELECT column_name, data_type, is_nullable FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = $1 ORDER BY ordinal_position`,
+		primaryKey: `SELECT kcu.column_name FROM information_schema.table_constraints tc
+			JOIN information_schema.key_column_usage kcu ON kcu.constraint_schema = tc.constraint_schema
+				AND kcu.constraint_name = tc.constraint_name
+			WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = current_schema() AND tc.table_name = $1
+			ORDER BY kcu.ordinal_position`,
+		foreignKeys: `SELECT c.conname, a.attname, rt.relname, ra.attname
+			FROM pg_constraint c
+			JOIN pg_class t ON t.oid = c.conrelid
+			JOIN pg_namespace n ON n.oid = t.relnamespace
+			JOIN pg_class rt ON rt.oid = c.confrelid
+			CROSS JOIN LATERAL unnest(c.conkey, c.confkey) WITH ORDINALITY AS k(col, refcol, pos)
+			JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.col
+			JOIN pg_attribute ra ON ra.attrelid = c.confrelid AND ra.attnum = k.refcol
+			WHERE c.contype = 'f' AND n.nspname = current_schema() AND t.relname = $1
+			ORDER BY c.conname, k.pos`,
+	},
+	MySQL: {
+		tables: `SELECT table_name FROM information_schema.tables
+			WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE' ORDER BY table_name`,
+		columns: `SELECT column_name, data_type, is_nullable FROM information_schema.columns
+			WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position`,
+		primaryKey: `SELECT column_name FROM information_schema.key_column_usage
+			WHERE table_schema = DATABASE() AND table_name = ? AND constraint_name = 'PRIMARY'
+			ORDER BY ordinal_position`,
+		foreignKeys: `SELECT constraint_name, column_name, referenced_table_name, referenced_column_name
+			FROM information_schema.key_column_usage
+			WHERE table_schema = DATABASE() AND table_name = ? AND referenced_table_name IS NOT NULL
+			ORDER BY constraint_name, ordinal_position`,
+	},
+}
+
+// Introspect reads the tables, columns, primary keys, and foreign keys of
+// the database db is connected to: the main database for SQLite, the
+// current schema for Postgres, and the current database for MySQL. db may
+// use any database/sql driver for the dialect.
+func Introspect(ctx context.Context, db *sql.DB, d Dialect) (*Schema, error) {
+	cat, ok := catalogs[d]
+	if !ok {
+		return nil, fmt.Errorf("sqlimport: unsupported dialect %v", d)
+	}
+	names, err := queryStrings(ctx, db, cat.tables)
+	if err != nil {
+		return nil, fmt.Errorf("sqlimport: listing tables: %w", err)
+	}
+	s := &Schema{Dialect: d}
+	for _, name := range names {
+		t := Table{Name: name}
+		rows, err := db.QueryContext(ctx, cat.columns, name)
+		if err != nil {
+			return nil, fmt.Errorf("sqlimport: reading columns of %s: %w", name, err)
+		}
+		for rows.Next() {
+			var c Column
+			var nullable string
+			if err := rows.Scan(&c.Name, &c.Type, &nullable); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("sqlimport: reading columns of %s: %w", name, err)
+			}
+			c.Nullable = strings.EqualFold(nullable, "YES")
+			t.Columns = append(t.Columns, c)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("sqlimport: reading columns of %s: %w", name, err)
+		}
+		if t.PrimaryKey, err = queryStrings(ctx, db, cat.primaryKey, name); err != nil {
+			return nil, fmt.Errorf("sqlimport: reading primary key of %s: %w", name, err)
+		}
+		if t.ForeignKeys, err = readForeignKeys(ctx, db, cat.foreignKeys, name); err != nil {
+			return nil, fmt.Errorf("sqlimport: reading foreign keys of %s: %w", name, err)
+		}
+		s.Tables = append(s.Tables, t)
+	}
+	// SQLite lets a foreign key omit the referenced columns to mean the
+	// referenced table's primary key.
+	for i := range s.Tables {
+		for j := range s.Tables[i].ForeignKeys {
+			fk := &s.Tables[i].ForeignKeys[j]
+			if allEmpty(fk.RefColumns) {
+				if ref := s.Table(fk.RefTable); ref != nil {
+					fk.RefColumns = ref.PrimaryKey
+				}
+			}
+		}
+	}
+	return s, nil
+}
+
+// allEmpty reports whether every element of cols is empty.
+func allEmpty(cols []string) bool {
+	for _, c := range cols {
+		if c != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func queryStrings(ctx context.Context, db *sql.DB, q string, args ...any) ([]string, error) {
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func readForeignKeys(ctx context.Context, db *sql.DB, q, table string) ([]ForeignKey, error) {
+	rows, err := db.QueryContext(ctx, q, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var fks []ForeignKey
+	last := ""
+	for rows.Next() {
+		var name, col, refTable, refCol string
+		if err := rows.Scan(&name, &col, &refTable, &refCol); err != nil {
+			return nil, err
+		}
+		if len(fks) == 0 || name != last {
+			fks = append(fks, ForeignKey{RefTable: refTable})
+			last = name
+		}
+		fk := &fks[len(fks)-1]
+		fk.Columns = append(fk.Columns, col)
+		fk.RefColumns = append(fk.RefColumns, refCol)
+	}
+	return fks, rows.Err()
+}
@@ -0,0 +1,745 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package sqlimport loads a relational database into modusGraph so it can
+// be explored as a graph.
+//
+// Introspect reads the schema of a SQLite, Postgres, or MySQL database
+// through database/sql, then Import copies its rows:
+//
+//	db, err := sql.Open("sqlite", "shop.db")
+//	schema, err := sqlimport.Introspect(ctx, db, sqlimport.SQLite)
+//	res, err := sqlimport.Import(ctx, client, db, schema, sqlimport.Options{})
+//
+// Each table becomes a Dgraph type named after the table in PascalCase, and
+// each row a node of that type. Columns become predicates named
+// <Type>.<column>. Each foreign key becomes a uid edge with @reverse, named
+// after its column with any _id suffix removed, so order_items.order_id
+// becomes OrderItems.order. A join table, one with two foreign keys whose
+// primary key is made of their columns, becomes a [uid] edge between the two
+// tables it joins instead of a type, and its remaining columns become facets.
+package sqlimport
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	mg "github.com/matthewmcneely/modusgraph"
+)
+
+// Options configures Import.
+type Options struct {
+	// Tables restricts the import to the named tables. Foreign keys into a
+	// table outside the set are imported as plain columns.
+	Tables []string
+	// BatchSize is how many rows or edges are written per transaction; it
+	// defaults to 1000.
+	BatchSize int
+	// InferForeignKeys treats a column named <name>_id as a foreign key to
+	// the table called <name> or its plural when the database declares no
+	// constraint for it, which is common in SQLite and MySQL MyISAM schemas.
+	// The table must have a single-column primary key.
+	InferForeignKeys bool
+	// KeepJoinTables imports join tables as types instead of edges.
+	KeepJoinTables bool
+}
+
+// Result reports what Import wrote. Dangling counts foreign key values that
+// matched no imported row; their edges were skipped.
+type Result struct {
+	Nodes    int
+	Edges    int
+	Dangling int
+}
+
+const defaultBatchSize = 1000
+
+// Import applies the Dgraph schema derived from s, writes one node per row,
+// and then links the nodes along foreign keys and join tables. Each batch
+// commits on its own, so a failed import can leave a partial graph behind;
+// import into an empty database and DropAll to retry.
+func Import(ctx context.Context, client mg.Client, db *sql.DB, s *Schema, opts Options) (*Result, error) {
+	p, err := newPlan(s, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.AlterSchema(ctx, p.dql()); err != nil {
+		return nil, fmt.Errorf("sqlimport: applying schema: %w", err)
+	}
+	dgo, cleanup, err := client.DgraphClient()
+	defer cleanup()
+	if err != nil {
+		return nil, err
+	}
+	im := &importer{
+		ctx:   ctx,
+		db:    db,
+		plan:  p,
+		batch: opts.BatchSize,
+		keys:  map[string]map[string]string{},
+		res:   &Result{},
+		mutate: func(objs []map[string]any) (map[string]string, error) {
+			body, err := json.Marshal(objs)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := dgo.NewTxn().Mutate(ctx, &api.Mutation{SetJson: body, CommitNow: true})
+			if err != nil {
+				return nil, err
+			}
+			return resp.Uids, nil
+		},
+	}
+	if im.batch <= 0 {
+		im.batch = defaultBatchSize
+	}
+	for _, t := range p.types {
+		if err := im.loadRows(t); err != nil {
+			return im.res, err
+		}
+	}
+	if err := im.linkRows(); err != nil {
+		return im.res, err
+	}
+	for _, j := range p.joins {
+		if err := im.loadJoin(j); err != nil {
+			return im.res, err
+		}
+	}
+	return im.res, nil
+}
+
+// kind is the Dgraph scalar type a column maps to.
+type kind int
+
+const (
+	kindString kind = iota
+	kindInt
+	kindFloat
+	kindBool
+	kindDateTime
+)
+
+var kindNames = [...]string{
+	kindString: "string", kindInt: "int", kindFloat: "float", kindBool: "bool", kindDateTime: "datetime",
+}
+
+// sqlKinds maps the first word of a declared SQL type to its kind.
+var sqlKinds = map[string]kind{
+	"int": kindInt, "integer": kindInt, "bigint": kindInt, "smallint": kindInt, "tinyint": kindInt,
+	"mediumint": kindInt, "int2": kindInt, "int4": kindInt, "int8": kindInt,
+	"serial": kindInt, "bigserial": kindInt, "smallserial": kindInt,
+	"real": kindFloat, "float": kindFloat, "float4": kindFloat, "float8": kindFloat,
+	"double": kindFloat, "numeric": kindFloat, "decimal": kindFloat,
+	"bool": kindBool, "boolean": kindBool,
+	"date": kindDateTime, "datetime": kindDateTime, "timestamp": kindDateTime, "timestamptz": kindDateTime,
+}
+
+func columnKind(d Dialect, sqlType string) kind {
+	t := strings.ToLower(sqlType)
+	word, _, _ := strings.Cut(strings.Fields(t + " x")[0], "(")
+	if k, ok := sqlKinds[word]; ok {
+		return k
+	}
+	// SQLite gives any type name containing INT integer affinity.
+	if d == SQLite && strings.Contains(t, "int") {
+		return kindInt
+	}
+	return kindString
+}
+
+// field is a column imported as a predicate or facet.
+type field struct {
+	column string
+	pred   string
+	kind   kind
+}
+
+// link is a foreign key imported as a uid edge.
+type link struct {
+	pred string
+	fk   ForeignKey
+}
+
+// typePlan is how a table becomes a type.
+type typePlan struct {
+	table  *Table
+	typ    string
+	fields []field
+	links  []link
+	// keys are the column lists other tables reference, each joined by ",".
+	keys []string
+}
+
+// joinPlan is how a join table becomes an edge from the from table's rows
+// to the to table's rows.
+type joinPlan struct {
+	table    *Table
+	pred     string
+	from, to ForeignKey
+	facets   []field
+}
+
+type plan struct {
+	dialect Dialect
+	types   []*typePlan
+	joins   []*joinPlan
+	// edges maps each edge predicate to whether it is a list.
+	edges map[string]bool
+	// indexed are the single-column primary key predicates.
+	indexed map[string]bool
+}
+
+func newPlan(s *Schema, opts Options) (*plan, error) {
+	if _, ok := catalogs[s.Dialect]; !ok {
+		return nil, fmt.Errorf("sqlimport: unsupported dialect %v", s.Dialect)
+	}
+	included := func(name string) bool {
+		return s.Table(name) != nil && (len(opts.Tables) == 0 || slices.Contains(opts.Tables, name))
+	}
+	for _, name := range opts.Tables {
+		if s.Table(name) == nil {
+			return nil, fmt.Errorf("sqlimport: no table %s", name)
+		}
+	}
+
+	// Collect each imported table's foreign keys into imported tables.
+	fks := map[string][]ForeignKey{}
+	for i := range s.Tables {
+		t := &s.Tables[i]
+		if !included(t.Name) {
+			continue
+		}
+		for _, fk := range t.ForeignKeys {
+			if included(fk.RefTable) && len(fk.Columns) == len(fk.RefColumns) {
+				fks[t.Name] = append(fks[t.Name], fk)
+			}
+		}
+		if opts.InferForeignKeys {
+			fks[t.Name] = append(fks[t.Name], inferForeignKeys(s, t, fks[t.Name], included)...)
+		}
+	}
+	referenced := map[string]bool{}
+	for _, list := range fks {
+		for _, fk := range list {
+			referenced[fk.RefTable] = true
+		}
+	}
+
+	p := &plan{dialect: s.Dialect, edges: map[string]bool{}, indexed: map[string]bool{}}
+	byTable := map[string]*typePlan{}
+	for i := range s.Tables {
+		t := &s.Tables[i]
+		if !included(t.Name) {
+			continue
+		}
+		if !opts.KeepJoinTables && !referenced[t.Name] && isJoinTable(t, fks[t.Name]) {
+			// The edge runs from the table whose key columns come first.
+			from, to := fks[t.Name][0], fks[t.Name][1]
+			if columnIndex(t, to.Columns[0]) < columnIndex(t, from.Columns[0]) {
+				from, to = to, from
+			}
+			p.joins = append(p.joins, &joinPlan{table: t, from: from, to: to})
+			continue
+		}
+		tp := &typePlan{table: t, typ: typeName(t.Name)}
+		linked := map[string]bool{}
+		for _, fk := range fks[t.Name] {
+			for _, c := range fk.Columns {
+				linked[c] = true
+			}
+		}
+		used := map[string]bool{}
+		for _, c := range t.Columns {
+			if linked[c.Name] && !slices.Contains(t.PrimaryKey, c.Name) {
+				continue
+			}
+			f := field{column: c.Name, pred: tp.typ + "." + c.Name, kind: columnKind(s.Dialect, c.Type)}
+			tp.fields = append(tp.fields, f)
+			used[c.Name] = true
+			if len(t.PrimaryKey) == 1 && t.PrimaryKey[0] == c.Name {
+				p.indexed[f.pred] = true
+			}
+		}
+		for _, fk := range fks[t.Name] {
+			name := linkName(fk)
+			for used[name] {
+				name += "_"
+			}
+			used[name] = true
+			l := link{pred: tp.typ + "." + name, fk: fk}
+			tp.links = append(tp.links, l)
+			p.edges[l.pred] = false
+		}
+		p.types = append(p.types, tp)
+		byTable[t.Name] = tp
+	}
+	addKey := func(table string, cols []string) {
+		tp := byTable[table]
+		key := strings.Join(cols, ",")
+		if !slices.Contains(tp.keys, key) {
+			tp.keys = append(tp.keys, key)
+		}
+	}
+	for _, tp := range p.types {
+		for _, l := range tp.links {
+			addKey(l.fk.RefTable, l.fk.RefColumns)
+		}
+	}
+	for _, j := range p.joins {
+		from := byTable[j.from.RefTable]
+		j.pred = from.typ + "." + j.table.Name
+		p.edges[j.pred] = true
+		addKey(j.from.RefTable, j.from.RefColumns)
+		addKey(j.to.RefTable, j.to.RefColumns)
+		for _, c := range j.table.Columns {
+			if !slices.Contains(j.from.Columns, c.Name) && !slices.Contains(j.to.Columns, c.Name) {
+				j.facets = append(j.facets, field{column: c.Name, pred: c.Name, kind: columnKind(s.Dialect, c.Type)})
+			}
+		}
+	}
+	return p, nil
+}
+
+// inferForeignKeys returns foreign keys for t's <name>_id columns that no
+// declared foreign key covers.
+func inferForeignKeys(s *Schema, t *Table, declared []ForeignKey, included func(string) bool) []ForeignKey {
+	covered := map[string]bool{}
+	for _, fk := range declared {
+		for _, c := range fk.Columns {
+			covered[c] = true
+		}
+	}
+	var out []ForeignKey
+	for _, c := range t.Columns {
+		lower := strings.ToLower(c.Name)
+		name, ok := strings.CutSuffix(lower, "_id")
+		if !ok || name == "" || covered[c.Name] || slices.Equal(t.PrimaryKey, []string{c.Name}) {
+			continue
+		}
+		candidates := []string{name, name + "s", name + "es"}
+		if base, ok := strings.CutSuffix(name, "y"); ok {
+			candidates = append(candidates, base+"ies")
+		}
+		for i := range s.Tables {
+			ref := &s.Tables[i]
+			if !slices.Contains(candidates, strings.ToLower(ref.Name)) || len(ref.PrimaryKey) != 1 || !included(ref.Name) {
+				continue
+			}
+			out = append(out, ForeignKey{Columns: []string{c.Name}, RefTable: ref.Name, RefColumns: ref.PrimaryKey})
+			break
+		}
+	}
+	return out
+}
+
+// isJoinTable reports whether t only links two tables: it has exactly two
+// foreign keys and any primary key is made of their columns.
+func isJoinTable(t *Table, fks []ForeignKey) bool {
+	if len(fks) != 2 {
+		return false
+	}
+	for _, c := range t.PrimaryKey {
+		if !slices.Contains(fks[0].Columns, c) && !slices.Contains(fks[1].Columns, c) {
+			return false
+		}
+	}
+	return true
+}
+
+func columnIndex(t *Table, name string) int {
+	return slices.IndexFunc(t.Columns, func(c Column) bool { return c.Name == name })
+}
+
+// linkName names a foreign key's edge after its column without an _id
+// suffix, or after the referenced table for composite keys.
+func linkName(fk ForeignKey) string {
+	if len(fk.Columns) == 1 {
+		c := fk.Columns[0]
+		for _, suffix := range []string{"_id", "_ID", "Id", "ID"} {
+			if name, ok := strings.CutSuffix(c, suffix); ok && name != "" {
+				return name
+			}
+		}
+		return c
+	}
+	return fk.RefTable
+}
+
+// typeName converts a table name to a PascalCase type name: order_items ->
+// OrderItems.
+func typeName(table string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range table {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+		}
+		b.WriteRune(r)
+		upper = false
+	}
+	if b.Len() == 0 {
+		return "Table"
+	}
+	return b.String()
+}
+
+// dql renders the plan's schema in Dgraph Schema Definition Language.
+func (p *plan) dql() string {
+	var b strings.Builder
+	for _, t := range p.types {
+		for _, f := range t.fields {
+			if p.indexed[f.pred] {
+				fmt.Fprintf(&b, "<%s>: %s @index(%s) .\n", f.pred, kindNames[f.kind], indexTokenizer(f.kind))
+			} else {
+				fmt.Fprintf(&b, "<%s>: %s .\n", f.pred, kindNames[f.kind])
+			}
+		}
+		for _, l := range t.links {
+			fmt.Fprintf(&b, "<%s>: uid @reverse .\n", l.pred)
+		}
+	}
+	for _, j := range p.joins {
+		fmt.Fprintf(&b, "<%s>: [uid] @reverse .\n", j.pred)
+	}
+	for _, t := range p.types {
+		fmt.Fprintf(&b, "type <%s> {\n", t.typ)
+		for _, f := range t.fields {
+			fmt.Fprintf(&b, "  <%s>\n", f.pred)
+		}
+		for _, l := range t.links {
+			fmt.Fprintf(&b, "  <%s>\n", l.pred)
+		}
+		for _, j := range p.joins {
+			if j.from.RefTable == t.table.Name {
+				fmt.Fprintf(&b, "  <%s>\n", j.pred)
+			}
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+func indexTokenizer(k kind) string {
+	switch k {
+	case kindString:
+		return "exact"
+	case kindDateTime:
+		return "hour"
+	}
+	return kindNames[k]
+}
+
+func (p *plan) quote(ident string) string {
+	if p.dialect == MySQL {
+		return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
+
+// importer holds the state of one Import.
+type importer struct {
+	ctx    context.Context
+	db     *sql.DB
+	plan   *plan
+	batch  int
+	mutate func([]map[string]any) (map[string]string, error)
+	// keys maps table + "\x00" + key columns to key values to UID.
+	keys map[string]map[string]string
+	// pending are the foreign key edges of the loaded rows.
+	pending []pendingLink
+	res     *Result
+}
+
+type pendingLink struct {
+	from string
+	pred string
+	// key is the referenced table and columns, and value the row's values.
+	key, value string
+}
+
+// selectRows runs SELECT cols FROM table and calls fn with each row's
+// values by column name.
+func (im *importer) selectRows(table string, cols []string, fn func(map[string]any) error) error {
+	quoted := make([]string, len(cols))
+	for i, c := range cols {
+		quoted[i] = im.plan.quote(c)
+	}
+	q := "SELECT " + strings.Join(quoted, ", ") + " FROM " + im.plan.quote(table)
+	rows, err := im.db.QueryContext(im.ctx, q)
+	if err != nil {
+		return fmt.Errorf("sqlimport: reading %s: %w", table, err)
+	}
+	defer rows.Close()
+	vals := make([]any, len(cols))
+	ptrs := make([]any, len(cols))
+	for i := range vals {
+		ptrs[i] = &vals[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return fmt.Errorf("sqlimport: reading %s: %w", table, err)
+		}
+		row := make(map[string]any, len(cols))
+		for i, c := range cols {
+			row[c] = vals[i]
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("sqlimport: reading %s: %w", table, err)
+	}
+	return nil
+}
+
+func (im *importer) loadRows(t *typePlan) error {
+	cols := make([]string, len(t.table.Columns))
+	for i, c := range t.table.Columns {
+		cols[i] = c.Name
+	}
+	var objs []map[string]any
+	var rows []map[string]any
+	flush := func() error {
+		if len(objs) == 0 {
+			return nil
+		}
+		uids, err := im.mutate(objs)
+		if err != nil {
+			return fmt.Errorf("sqlimport: writing %s rows: %w", t.table.Name, err)
+		}
+		for i, row := range rows {
+			uid := uids[fmt.Sprintf("r%d", i)]
+			for _, key := range t.keys {
+				if v, ok := keyValue(row, strings.Split(key, ",")); ok {
+					k := t.table.Name + "\x00" + key
+					if im.keys[k] == nil {
+						im.keys[k] = map[string]string{}
+					}
+					im.keys[k][v] = uid
+				}
+			}
+			for _, l := range t.links {
+				if v, ok := keyValue(row, l.fk.Columns); ok {
+					im.pending = append(im.pending, pendingLink{
+						from:  uid,
+						pred:  l.pred,
+						key:   l.fk.RefTable + "\x00" + strings.Join(l.fk.RefColumns, ","),
+						value: v,
+					})
+				}
+			}
+		}
+		im.res.Nodes += len(objs)
+		objs, rows = objs[:0], rows[:0]
+		return nil
+	}
+	err := im.selectRows(t.table.Name, cols, func(row map[string]any) error {
+		obj := map[string]any{"uid": fmt.Sprintf("_:r%d", len(objs)), "dgraph.type": t.typ}
+		for _, f := range t.fields {
+			v, err := convert(row[f.column], f.kind)
+			if err != nil {
+				return fmt.Errorf("sqlimport: %s.%s: %w", t.table.Name, f.column, err)
+			}
+			if v != nil {
+				obj[f.pred] = v
+			}
+		}
+		objs = append(objs, obj)
+		rows = append(rows, row)
+		if len(objs) == im.batch {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// linkRows writes the foreign key edges collected by loadRows.
+func (im *importer) linkRows() error {
+	var objs []map[string]any
+	for i, l := range im.pending {
+		to, ok := im.keys[l.key][l.value]
+		if !ok {
+			im.res.Dangling++
+		} else {
+			objs = append(objs, map[string]any{"uid": l.from, l.pred: map[string]any{"uid": to}})
+		}
+		if len(objs) == im.batch || (i == len(im.pending)-1 && len(objs) > 0) {
+			if _, err := im.mutate(objs); err != nil {
+				return fmt.Errorf("sqlimport: writing edges: %w", err)
+			}
+			im.res.Edges += len(objs)
+			objs = objs[:0]
+		}
+	}
+	im.pending = nil
+	return nil
+}
+
+func (im *importer) loadJoin(j *joinPlan) error {
+	cols := append(slices.Clone(j.from.Columns), j.to.Columns...)
+	for _, f := range j.facets {
+		cols = append(cols, f.column)
+	}
+	fromKey := j.from.RefTable + "\x00" + strings.Join(j.from.RefColumns, ",")
+	toKey := j.to.RefTable + "\x00" + strings.Join(j.to.RefColumns, ",")
+	var objs []map[string]any
+	flush := func() error {
+		if len(objs) == 0 {
+			return nil
+		}
+		if _, err := im.mutate(objs); err != nil {
+			return fmt.Errorf("sqlimport: writing %s edges: %w", j.table.Name, err)
+		}
+		im.res.Edges += len(objs)
+		objs = objs[:0]
+		return nil
+	}
+	err := im.selectRows(j.table.Name, cols, func(row map[string]any) error {
+		fv, fok := keyValue(row, j.from.Columns)
+		tv, tok := keyValue(row, j.to.Columns)
+		if !fok || !tok {
+			return nil
+		}
+		from, fok := im.keys[fromKey][fv]
+		to, tok := im.keys[toKey][tv]
+		if !fok || !tok {
+			im.res.Dangling++
+			return nil
+		}
+		target := map[string]any{"uid": to}
+		for _, f := range j.facets {
+			v, err := convert(row[f.column], f.kind)
+			if err != nil {
+				return fmt.Errorf("sqlimport: %s.%s: %w", j.table.Name, f.column, err)
+			}
+			if v != nil {
+				target[j.pred+"|"+f.pred] = v
+			}
+		}
+		objs = append(objs, map[string]any{"uid": from, j.pred: []any{target}})
+		if len(objs) == im.batch {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// keyValue encodes the values of cols in row as a lookup key. It reports
+// false if any value is NULL.
+func keyValue(row map[string]any, cols []string) (string, bool) {
+	parts := make([]string, len(cols))
+	for i, c := range cols {
+		switch v := row[c].(type) {
+		case nil:
+			return "", false
+		case []byte:
+			parts[i] = string(v)
+		case time.Time:
+			parts[i] = v.UTC().Format(time.RFC3339Nano)
+		default:
+			parts[i] = fmt.Sprint(v)
+		}
+	}
+	return strings.Join(parts, "\x00"), true
+}
+
+// sqlTimeLayouts are the text forms drivers return temporal values in.
+var sqlTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// convert coerces a scanned value to k for a mutation. NULL becomes nil.
+func convert(v any, k kind) (any, error) {
+	if b, ok := v.([]byte); ok {
+		if k == kindString && !utf8.Valid(b) {
+			return base64.StdEncoding.EncodeToString(b), nil
+		}
+		v = string(b)
+	}
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case time.Time:
+		if k == kindDateTime || k == kindString {
+			return v.Format(time.RFC3339Nano), nil
+		}
+	case int64:
+		switch k {
+		case kindInt:
+			return v, nil
+		case kindFloat:
+			return float64(v), nil
+		case kindBool:
+			return v != 0, nil
+		}
+	case float64:
+		if k == kindFloat {
+			return v, nil
+		}
+	case bool:
+		if k == kindBool {
+			return v, nil
+		}
+	case string:
+		switch k {
+		case kindString:
+			return v, nil
+		case kindInt:
+			if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return i, nil
+			}
+		case kindFloat:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f, nil
+			}
+		case kindBool:
+			switch strings.ToLower(v) {
+			case "t", "true", "1", "y", "yes":
+				return true, nil
+			case "f", "false", "0", "n", "no":
+				return false, nil
+			}
+		case kindDateTime:
+			for _, layout := range sqlTimeLayouts {
+				if t, err := time.Parse(layout, v); err == nil {
+					return t.Format(time.RFC3339Nano), nil
+				}
+			}
+		}
+	}
+	if k == kindString {
+		return fmt.Sprint(v), nil
+	}
+	return nil, fmt.Errorf("cannot store %v (%T) as %s", v, v, kindNames[k])
+}
@@ -0,0 +1,158 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package sqlimport_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/sqlimport"
+	_ "modernc.org/sqlite"
+)
+
+const shopSchema = `
+CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT NOT NULL, vip BOOLEAN, joined DATE);
+CREATE TABLE products (sku TEXT PRIMARY KEY, title TEXT, price REAL);
+CREATE TABLE orders (id INTEGER PRIMARY KEY, customer_id INTEGER REFERENCES customers(id), placed_at DATETIME);
+CREATE TABLE order_items (
+	order_id INTEGER REFERENCES orders,
+	product_sku TEXT REFERENCES products(sku),
+	quantity INTEGER,
+	PRIMARY KEY (order_id, product_sku)
+);
+CREATE TABLE reviews (id INTEGER PRIMARY KEY, customer_id INTEGER, stars INTEGER);
+INSERT INTO customers VALUES (1, 'Ada', 1, '2024-01-02'), (2, 'Grace', 0, NULL);
+INSERT INTO products VALUES ('A-1', 'Anvil', 19.5), ('B-2', 'Bell', 4);
+INSERT INTO orders VALUES (10, 1, '2024-03-04 05:06:07'), (11, 2, NULL), (12, 99, NULL);
+INSERT INTO order_items VALUES (10, 'A-1', 2), (10, 'B-2', 1), (11, 'B-2', 5);
+INSERT INTO reviews VALUES (100, 1, 5);
+`
+
+func openShop(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "shop.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(shopSchema); err != nil {
+		t.Fatalf("creating shop database: %v", err)
+	}
+	return db
+}
+
+func TestIntrospect(t *testing.T) {
+	s, err := sqlimport.Introspect(context.Background(), openShop(t), sqlimport.SQLite)
+	if err != nil {
+		t.Fatalf("Introspect: %v", err)
+	}
+	var names []string
+	for _, table := range s.Tables {
+		names = append(names, table.Name)
+	}
+	if !slices.Equal(names, []string{"customers", "order_items", "orders", "products", "reviews"}) {
+		t.Fatalf("tables = %v", names)
+	}
+	items := s.Table("order_items")
+	if !slices.Equal(items.PrimaryKey, []string{"order_id", "product_sku"}) {
+		t.Errorf("order_items primary key = %v", items.PrimaryKey)
+	}
+	if len(items.ForeignKeys) != 2 {
+		t.Fatalf("order_items foreign keys = %+v", items.ForeignKeys)
+	}
+	for _, fk := range items.ForeignKeys {
+		if fk.RefTable == "orders" && !slices.Equal(fk.RefColumns, []string{"id"}) {
+			t.Errorf("implicit reference to orders resolved to %v, want its primary key", fk.RefColumns)
+		}
+	}
+	if c := s.Table("customers").Columns[1]; c.Name != "name" || c.Type != "TEXT" || c.Nullable {
+		t.Errorf("customers.name = %+v", c)
+	}
+}
+
+func TestImport(t *testing.T) {
+	conn, err := modusgraph.NewClient("file://" + t.TempDir())
+	if err != nil {
+		t.Fatalf("modusgraph.NewClient: %v", err)
+	}
+	t.Cleanup(conn.Close)
+
+	ctx := context.Background()
+	db := openShop(t)
+	s, err := sqlimport.Introspect(ctx, db, sqlimport.SQLite)
+	if err != nil {
+		t.Fatalf("Introspect: %v", err)
+	}
+	res, err := sqlimport.Import(ctx, conn, db, s, sqlimport.Options{BatchSize: 2, InferForeignKeys: true})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	// 2 customers, 2 products, 3 orders, 1 review; order_items is a join table.
+	// Edges: 2 order->customer, 1 review->customer, 3 order_items.
+	if *res != (sqlimport.Result{Nodes: 8, Edges: 6, Dangling: 1}) {
+		t.Fatalf("result = %+v", *res)
+	}
+
+	raw, err := conn.QueryRaw(ctx, `{
+		customers(func: type(Customers), orderasc: Customers.id) {
+			Customers.name Customers.vip Customers.joined
+			orders: ~Orders.customer {
+				Orders.id Orders.placed_at
+				Orders.order_items (orderasc: Products.sku) @facets(quantity) { Products.title Products.price }
+			}
+			reviews: ~Reviews.customer { Reviews.stars }
+		}
+	}`, nil)
+	if err != nil {
+		t.Fatalf("QueryRaw: %v", err)
+	}
+	var got struct {
+		Customers []struct {
+			Name   string `json:"Customers.name"`
+			VIP    bool   `json:"Customers.vip"`
+			Joined string `json:"Customers.joined"`
+			Orders []struct {
+				ID       int    `json:"Orders.id"`
+				PlacedAt string `json:"Orders.placed_at"`
+				Items    []struct {
+					Title    string  `json:"Products.title"`
+					Price    float64 `json:"Products.price"`
+					Quantity int     `json:"Orders.order_items|quantity"`
+				} `json:"Orders.order_items"`
+			} `json:"orders"`
+			Reviews []struct {
+				Stars int `json:"Reviews.stars"`
+			} `json:"reviews"`
+		} `json:"customers"`
+	}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(got.Customers) != 2 {
+		t.Fatalf("customers = %s", raw)
+	}
+	ada := got.Customers[0]
+	if ada.Name != "Ada" || !ada.VIP || ada.Joined[:10] != "2024-01-02" {
+		t.Errorf("Ada = %+v", ada)
+	}
+	if len(ada.Orders) != 1 || ada.Orders[0].ID != 10 || ada.Orders[0].PlacedAt[:19] != "2024-03-04T05:06:07" {
+		t.Fatalf("Ada's orders = %s", raw)
+	}
+	items := ada.Orders[0].Items
+	if len(items) != 2 || items[0].Title != "Anvil" || items[0].Quantity != 2 || items[1].Price != 4 {
+		t.Errorf("Ada's order items = %+v", items)
+	}
+	if len(ada.Reviews) != 1 || ada.Reviews[0].Stars != 5 {
+		t.Errorf("Ada's reviews = %+v", ada.Reviews)
+	}
+	if grace := got.Customers[1]; grace.VIP || len(grace.Orders) != 1 || grace.Orders[0].Items[0].Quantity != 5 {
+		t.Errorf("Grace = %+v", grace)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+
+	dg "github.com/dolan-in/dgman/v2"
+	"github.com/matthewmcneely/modusgraph/queryopt"
+)
+
+// defaultStreamPageSize is the page size QueryStream and TypedQuery.Stream
+// use when given none.
+const defaultStreamPageSize = 100
+
+// QueryStream implements streaming the nodes of model's type a page at a
+// time.
+func (c client) QueryStream(ctx context.Context, model any, pageSize int,
+	opts ...queryopt.Option) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		model = UnwrapSchema(model)
+		t := reflect.TypeOf(model)
+		for t != nil && t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			yield(nil, fmt.Errorf("modusgraph: QueryStream takes a struct model, not %T", model))
+			return
+		}
+		q := ApplyQueryOptions(c, c.Query(ctx, model), model, opts...)
+		if q == nil {
+			yield(nil, errors.New("modusgraph: query has no connection"))
+			return
+		}
+		page := queryopt.Resolve(opts...).Page
+		err := streamPages(c, q, t, page.Limit, page.Offset, pageSize, func(row reflect.Value) bool {
+			return yield(row.Addr().Interface(), nil)
+		})
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// Stream runs the query a page of pageSize results at a time, 100 when
+// pageSize is 0 or less, and yields each T as it goes, so a large result set
+// is never held in memory at once. Limit caps the results streamed in all
+// and Offset is where they start. Every page is read from one read-only
+// transaction, so writes made while iterating cannot make it skip or repeat
+// results. The iteration stops at the first error, which it yields.
+func (tq *TypedQuery[T]) Stream(pageSize int) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		if err := tq.prepare(); err != nil {
+			yield(zero, err)
+			return
+		}
+		rows := func(row reflect.Value) bool {
+			return yield(row.Interface().(T), nil)
+		}
+		err := streamPages(tq.client, tq.q, reflect.TypeFor[T](), tq.limit, tq.offset, pageSize, rows)
+		if err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// streamPages runs q a page of size results at a time, starting at offset
+// and stopping after limit results when limit is positive. It decodes each
+// page into a slice of rowType and passes the rows to fn in turn, stopping
+// when fn returns false.
+func streamPages(c Client, q *dg.Query, rowType reflect.Type, limit, offset, size int,
+	fn func(reflect.Value) bool) error {
+	if size <= 0 {
+		size = defaultStreamPageSize
+	}
+	for {
+		n := size
+		if limit > 0 && limit < n {
+			n = limit
+		}
+		rows := reflect.New(reflect.SliceOf(rowType))
+		if err := q.Offset(offset).First(n).Nodes(rows.Interface()); err != nil {
+			return err
+		}
+		NormalizeTimes(c, rows.Interface())
+		page := rows.Elem()
+		for i := range page.Len() {
+			if !fn(page.Index(i)) {
+				return nil
+			}
+		}
+		if limit > 0 {
+			if limit -= page.Len(); limit <= 0 {
+				return nil
+			}
+		}
+		if page.Len() < n {
+			return nil
+		}
+		offset += page.Len()
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/queryopt"
+	"github.com/stretchr/testify/require"
+)
+
+type StreamItem struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"stream_item_name,omitempty" dgraph:"index=exact"`
+	Rank  int      `json:"stream_item_rank,omitempty" dgraph:"index=int"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+func TestQueryStream(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "QueryStreamWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "QueryStreamWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+
+			items := make([]*StreamItem, 25)
+			for i := range items {
+				items[i] = &StreamItem{Name: fmt.Sprintf("item-%02d", i), Rank: i + 1}
+			}
+			require.NoError(t, mg.Insert(ctx, client, items...))
+
+			// Every node, over several pages.
+			var names []string
+			for item, err := range mg.Query[StreamItem](ctx, client).OrderAsc("stream_item_rank").Stream(10) {
+				require.NoError(t, err)
+				names = append(names, item.Name)
+			}
+			require.Len(t, names, 25)
+			require.Equal(t, "item-00", names[0])
+			require.Equal(t, "item-24", names[24])
+
+			// Limit and Offset bound the stream, filters apply.
+			var ranks []int
+			for item, err := range mg.Query[StreamItem](ctx, client).
+				Filter("ge(stream_item_rank, ?)", 3).
+				OrderAsc("stream_item_rank").
+				Offset(2).
+				Limit(12).
+				Stream(5) {
+				require.NoError(t, err)
+				ranks = append(ranks, item.Rank)
+			}
+			require.Len(t, ranks, 12)
+			require.Equal(t, 5, ranks[0])
+			require.Equal(t, 16, ranks[11])
+
+			// Breaking out stops the stream.
+			seen := 0
+			for _, err := range mg.Query[StreamItem](ctx, client).Stream(4) {
+				require.NoError(t, err)
+				if seen++; seen == 6 {
+					break
+				}
+			}
+			require.Equal(t, 6, seen)
+
+			// Pages come from one snapshot: a delete made while iterating
+			// does not shift the later pages.
+			streamed := 0
+			for item, err := range mg.Query[StreamItem](ctx, client).OrderAsc("stream_item_rank").Stream(5) {
+				require.NoError(t, err)
+				if streamed++; streamed == 1 {
+					require.NoError(t, client.Delete(ctx, []string{item.UID}))
+				}
+			}
+			require.Equal(t, 25, streamed)
+
+			// The untyped form yields pointers and takes query options.
+			var got []*StreamItem
+			for v, err := range client.QueryStream(ctx, StreamItem{}, 3,
+				queryopt.Desc("stream_item_rank"), queryopt.Page{Limit: 7}, queryopt.Fields{"stream_item_rank"}) {
+				require.NoError(t, err)
+				got = append(got, v.(*StreamItem))
+			}
+			require.Len(t, got, 7)
+			require.Equal(t, 25, got[0].Rank)
+			require.Equal(t, 19, got[6].Rank)
+			require.Empty(t, got[0].Name)
+
+			for _, err := range client.QueryStream(ctx, "not a struct", 0) {
+				require.ErrorContains(t, err, "QueryStream takes a struct model")
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"google.golang.org/grpc"
+)
+
+// ErrUnknownPredicate is returned by writes that set a predicate missing from
+// the schema when the client was created with WithStrictPredicates. The
+// wrapping error names the predicates. Nothing is written.
+var ErrUnknownPredicate = errors.New("modusgraph: mutation sets a predicate not in the schema")
+
+// WithStrictPredicates makes every write fail with ErrUnknownPredicate when
+// it sets a predicate the schema does not declare, instead of letting Dgraph
+// create the predicate on the fly. This covers Insert, Upsert and Update as
+// well as InsertRaw and mutations sent through DgraphClient, so a typo in a
+// raw mutation or a struct field that drifted from the schema fails loudly.
+// Predicates enter the schema through AlterSchema, UpdateSchema, WithAutoSchema
+// or WithSchemaMode(SchemaAuto), which keep working as before; pair this
+// option with SchemaManual or SchemaStrict to keep models from extending the
+// schema implicitly. Deletions are never checked.
+func WithStrictPredicates() ClientOpt {
+	return func(o *clientOptions) {
+		o.strictPredicates = true
+	}
+}
+
+// predicateGuard enforces WithStrictPredicates. For remote clients it caches
+// the predicates of the live schema, reloading them when a mutation names
+// one it has not seen, since another client may have added it, and after
+// this client alters the schema. Embedded clients read the schema state
+// directly instead.
+type predicateGuard struct {
+	mu    sync.Mutex
+	known map[string]bool
+}
+
+// check returns an ErrUnknownPredicate error if mus set a predicate known
+// does not report.
+func (g *predicateGuard) check(mus []*api.Mutation, known func(string) bool) error {
+	var unknown []string
+	for _, pred := range setPredicates(mus) {
+		if !isInternalName(pred) && !known(pred) {
+			unknown = append(unknown, pred)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownPredicate, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// checkNamespace checks mus against the predicates of the embedded namespace
+// ns.
+func (g *predicateGuard) checkNamespace(ns *Namespace, mus []*api.Mutation) error {
+	if g == nil || len(mus) == 0 {
+		return nil
+	}
+	names := ns.predicates()
+	return g.check(mus, func(pred string) bool {
+		_, ok := slices.BinarySearch(names, pred)
+		return ok
+	})
+}
+
+// unaryInterceptor checks remote (dgraph://) mutations before they are sent.
+func (g *predicateGuard) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any,
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		switch method {
+		case api.Dgraph_Alter_FullMethodName:
+			g.mu.Lock()
+			g.known = nil
+			g.mu.Unlock()
+		case api.Dgraph_Query_FullMethodName:
+			if r, ok := req.(*api.Request); ok && len(r.Mutations) > 0 {
+				if err := g.checkRemote(ctx, r.Mutations, cc, invoker, opts); err != nil {
+					return err
+				}
+			}
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// checkRemote checks mus against the cached schema, reloading it once if
+// they name a predicate it lacks.
+func (g *predicateGuard) checkRemote(ctx context.Context, mus []*api.Mutation,
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts []grpc.CallOption) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	known := func(pred string) bool { return g.known[pred] }
+	if g.known != nil {
+		if err := g.check(mus, known); err == nil {
+			return nil
+		}
+	}
+
+	resp := &api.Response{}
+	req := &api.Request{Query: "schema {}", ReadOnly: true}
+	if err := invoker(ctx, api.Dgraph_Query_FullMethodName, req, resp, cc, opts...); err != nil {
+		return fmt.Errorf("reading schema: %w", err)
+	}
+	var schema schemaResponse
+	if err := json.Unmarshal(resp.Json, &schema); err != nil {
+		return err
+	}
+	g.known = make(map[string]bool, len(schema.Schema))
+	for _, p := range schema.Schema {
+		g.known[p.Predicate] = true
+	}
+	return g.check(mus, known)
+}
+
+// setPredicates returns the predicates mus set, without language tags.
+func setPredicates(mus []*api.Mutation) []string {
+	var preds []string
+	for _, mu := range mus {
+		if len(mu.SetJson) == 0 && len(mu.SetNquads) == 0 {
+			continue
+		}
+		sets := []*api.Mutation{{SetJson: mu.SetJson, SetNquads: mu.SetNquads}}
+		for _, ev := range mutationEvents(sets, nil) {
+			for _, pred := range ev.Predicates {
+				pred, _, _ = strings.Cut(pred, "@")
+				if !slices.Contains(preds, pred) {
+					preds = append(preds, pred)
+				}
+			}
+		}
+	}
+	slices.Sort(preds)
+	return preds
+}
@@ -0,0 +1,112 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type StrictItem struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"strict_name,omitempty" dgraph:"index=exact"`
+	Notes string   `json:"strict_notes,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+func TestStrictPredicates(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "StrictPredicatesWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "StrictPredicatesWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, err := mg.NewClient(tc.uri, mg.WithStrictPredicates())
+			require.NoError(t, err)
+			defer func() {
+				if strings.HasPrefix(tc.uri, "dgraph://") {
+					_ = client.DropAll(context.Background())
+				}
+				client.Close()
+				mg.Shutdown()
+			}()
+			ctx := context.Background()
+			if strings.HasPrefix(tc.uri, "dgraph://") {
+				require.NoError(t, client.DropAll(ctx))
+			}
+			require.NoError(t, client.AlterSchema(ctx, `
+				strict_name: string @index(exact) .
+				type StrictItem {
+					strict_name
+				}`))
+
+			// Fields the schema declares are written as usual.
+			item := &StrictItem{Name: "lamp"}
+			require.NoError(t, client.Insert(ctx, item))
+
+			// A field missing from the schema fails the whole write.
+			err = client.Insert(ctx, &StrictItem{Name: "desk", Notes: "oak"})
+			require.ErrorIs(t, err, mg.ErrUnknownPredicate)
+			require.ErrorContains(t, err, "strict_notes")
+			item.Notes = "brass"
+			require.ErrorIs(t, client.Update(ctx, item), mg.ErrUnknownPredicate)
+
+			// So does a typo in a raw mutation.
+			dgClient, release, err := client.DgraphClient()
+			require.NoError(t, err)
+			_, err = dgClient.NewTxn().Mutate(ctx, &api.Mutation{
+				SetNquads: []byte(`_:n <strict_nmae> "chair" .`),
+				CommitNow: true,
+			})
+			release()
+			require.ErrorIs(t, err, mg.ErrUnknownPredicate)
+			require.ErrorContains(t, err, "strict_nmae")
+
+			var items []StrictItem
+			require.NoError(t, client.Query(ctx, StrictItem{}).Nodes(&items))
+			require.Len(t, items, 1)
+			require.Equal(t, "lamp", items[0].Name)
+
+			// Once the schema declares the predicate the write goes through.
+			require.NoError(t, client.AlterSchema(ctx, `
+				strict_notes: string .
+				type StrictItem {
+					strict_name
+					strict_notes
+				}`))
+			require.NoError(t, client.Update(ctx, item))
+			var got StrictItem
+			require.NoError(t, client.Get(ctx, &got, item.UID))
+			require.Equal(t, "brass", got.Notes)
+
+			// Deletions are not checked.
+			require.NoError(t, client.Delete(ctx, []string{item.UID}))
+		})
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// subscribeBatchSize is how many changelog events Subscribe filters at once.
+const subscribeBatchSize = 100
+
+// resumeTokenPrefix versions the ResumeToken format.
+const resumeTokenPrefix = "v1."
+
+// ResumeToken marks a position in the changelog. A subscription started with
+// an event's token delivers the matching changes committed after it. Tokens
+// are opaque; store them as strings.
+type ResumeToken string
+
+// ResumeToken returns the token resuming after ev.
+func (ev ChangeEvent) ResumeToken() ResumeToken {
+	return ResumeToken(resumeTokenPrefix + strconv.FormatUint(ev.Seq, 10))
+}
+
+// seq returns the changelog position of t.
+func (t ResumeToken) seq() (uint64, error) {
+	s, ok := strings.CutPrefix(string(t), resumeTokenPrefix)
+	if !ok {
+		return 0, fmt.Errorf("modusgraph: invalid resume token %q", t)
+	}
+	seq, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("modusgraph: invalid resume token %q", t)
+	}
+	return seq, nil
+}
+
+// SubscriptionSpec selects the changes a Subscribe call delivers. Empty
+// fields select everything.
+type SubscriptionSpec struct {
+	// Types limits the subscription to nodes of these types. Updates that do
+	// not state the type are matched on the node's dgraph.type.
+	Types []string
+
+	// Filter is a DQL filter expression the node must satisfy, e.g.
+	// `ge(year, $1)`, evaluated when the event is delivered. Params fills
+	// its $1, $2, ... placeholders.
+	Filter string
+	Params []any
+
+	// Predicates limits the subscription to changes touching at least one of
+	// these predicates.
+	Predicates []string
+
+	// ResumeAfter resumes after the event whose ResumeToken it is. When
+	// empty, only changes committed after Subscribe is called are delivered.
+	ResumeAfter ResumeToken
+}
+
+// Subscribe delivers the committed changes matching spec, in changelog order,
+// waiting for new commits once caught up. The iteration runs until ctx is
+// done (yielding ctx's error last), the consumer stops, or reading or
+// filtering fails; in every case the ResumeToken of the last event received
+// resumes the subscription without gaps.
+//
+// Whole-node deletes carry no type or predicates and the node can no longer
+// be queried, so they are delivered to every subscription. Requires
+// WithChangelog.
+func (c client) Subscribe(ctx context.Context, spec SubscriptionSpec) iter.Seq2[ChangeEvent, error] {
+	return func(yield func(ChangeEvent, error) bool) {
+		if c.changes == nil {
+			yield(ChangeEvent{}, ErrChangelogDisabled)
+			return
+		}
+		after, _ := c.changes.head()
+		if spec.ResumeAfter != "" {
+			var err error
+			if after, err = spec.ResumeAfter.seq(); err != nil {
+				yield(ChangeEvent{}, err)
+				return
+			}
+		}
+		for {
+			_, wake := c.changes.head()
+			events, err := c.changes.readAfter(after, subscribeBatchSize)
+			if err != nil {
+				yield(ChangeEvent{}, err)
+				return
+			}
+			if len(events) == 0 {
+				select {
+				case <-ctx.Done():
+					yield(ChangeEvent{}, ctx.Err())
+					return
+				case <-wake:
+					continue
+				}
+			}
+			matched, err := c.matchSubscription(ctx, spec, events)
+			if err != nil {
+				yield(ChangeEvent{}, err)
+				return
+			}
+			for _, ev := range matched {
+				if !yield(ev, nil) {
+					return
+				}
+			}
+			after = events[len(events)-1].Seq
+		}
+	}
+}
+
+// matchSubscription returns the events spec selects, in order.
+func (c client) matchSubscription(ctx context.Context, spec SubscriptionSpec,
+	events []ChangeEvent) ([]ChangeEvent, error) {
+	if len(spec.Types) > 0 {
+		if err := c.resolveEventTypes(ctx, events); err != nil {
+			return nil, err
+		}
+	}
+	var matched, queried []ChangeEvent
+	for _, ev := range events {
+		if wholeNodeDelete(ev) {
+			matched = append(matched, ev)
+			continue
+		}
+		if len(spec.Types) > 0 && !slices.Contains(spec.Types, ev.Type) {
+			continue
+		}
+		if len(spec.Predicates) > 0 && !slices.ContainsFunc(ev.Predicates, func(p string) bool {
+			return slices.Contains(spec.Predicates, p)
+		}) {
+			continue
+		}
+		if spec.Filter != "" && ev.UID == "" {
+			continue
+		}
+		matched = append(matched, ev)
+		queried = append(queried, ev)
+	}
+	if spec.Filter == "" || len(queried) == 0 {
+		return matched, nil
+	}
+	filtered, err := c.filterEvents(ctx, queried, spec.Filter, spec.Params)
+	if err != nil {
+		return nil, err
+	}
+	pass := make(map[uint64]bool, len(filtered))
+	for _, ev := range filtered {
+		pass[ev.Seq] = true
+	}
+	return slices.DeleteFunc(matched, func(ev ChangeEvent) bool {
+		return !wholeNodeDelete(ev) && !pass[ev.Seq]
+	}), nil
+}
@@ -0,0 +1,164 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type SubscribedFilm struct {
+	UID   string   `json:"uid,omitempty"`
+	Title string   `json:"title,omitempty" dgraph:"index=exact"`
+	Year  int      `json:"year,omitempty" dgraph:"index=int"`
+	Genre string   `json:"genre,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+// subscribed is one pair yielded by Subscribe.
+type subscribed struct {
+	ev  mg.ChangeEvent
+	err error
+}
+
+// subscribe runs a subscription in the background, sending what it yields
+// on the returned channel.
+func subscribe(ctx context.Context, client mg.Client, spec mg.SubscriptionSpec) <-chan subscribed {
+	ch := make(chan subscribed, 100)
+	go func() {
+		for ev, err := range client.Subscribe(ctx, spec) {
+			ch <- subscribed{ev, err}
+		}
+	}()
+	return ch
+}
+
+func receive(t *testing.T, ch <-chan subscribed) subscribed {
+	t.Helper()
+	select {
+	case s := <-ch:
+		return s
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for a change event")
+		return subscribed{}
+	}
+}
+
+func receiveEvent(t *testing.T, ch <-chan subscribed) mg.ChangeEvent {
+	t.Helper()
+	s := receive(t, ch)
+	require.NoError(t, s.err)
+	return s.ev
+}
+
+func TestSubscribe(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "SubscribeWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "SubscribeWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, err := mg.NewClient(tc.uri, mg.WithAutoSchema(true), mg.WithChangelog(t.TempDir()))
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = client.DropAll(context.Background())
+				client.Close()
+				mg.Shutdown()
+			})
+			ctx := context.Background()
+			setNquads := func(nquads string) {
+				dg, cleanup, err := client.DgraphClient()
+				require.NoError(t, err)
+				defer cleanup()
+				_, err = dg.NewTxn().Mutate(ctx, &api.Mutation{SetNquads: []byte(nquads), CommitNow: true})
+				require.NoError(t, err)
+			}
+
+			// Changes committed before subscribing are not delivered.
+			early := &SubscribedFilm{Title: "Nosferatu", Year: 2022}
+			require.NoError(t, client.Insert(ctx, early))
+
+			spec := mg.SubscriptionSpec{
+				Types:      []string{"SubscribedFilm"},
+				Filter:     "ge(year, $1)",
+				Params:     []any{2000},
+				Predicates: []string{"title"},
+			}
+			subCtx, cancel := context.WithCancel(ctx)
+			events := subscribe(subCtx, client, spec)
+
+			old := &SubscribedFilm{Title: "Metropolis", Year: 1927}
+			recent := &SubscribedFilm{Title: "Memento", Year: 2000}
+			require.NoError(t, client.Insert(ctx, old))
+			require.NoError(t, client.Insert(ctx, recent))
+			setNquads(`<` + recent.UID + `> <genre> "noir" .`)
+			setNquads(`<` + recent.UID + `> <title> "Memento (Remastered)" .`)
+			require.NoError(t, client.Delete(ctx, []string{old.UID}))
+
+			inserted := receiveEvent(t, events)
+			require.Equal(t, mg.ChangeInsert, inserted.Op)
+			require.Equal(t, recent.UID, inserted.UID)
+			updated := receiveEvent(t, events)
+			require.Equal(t, mg.ChangeUpdate, updated.Op)
+			require.Equal(t, recent.UID, updated.UID)
+			require.Equal(t, "SubscribedFilm", updated.Type)
+			require.Equal(t, []string{"title"}, updated.Predicates)
+			deleted := receiveEvent(t, events)
+			require.Equal(t, mg.ChangeDelete, deleted.Op)
+			require.Equal(t, old.UID, deleted.UID)
+			require.Less(t, inserted.Seq, updated.Seq)
+			require.Less(t, updated.Seq, deleted.Seq)
+
+			cancel()
+			require.ErrorIs(t, receive(t, events).err, context.Canceled)
+
+			// Resuming after the insert delivers the rest again.
+			resumeCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			spec.ResumeAfter = inserted.ResumeToken()
+			resumed := subscribe(resumeCtx, client, spec)
+			require.Equal(t, updated.Seq, receiveEvent(t, resumed).Seq)
+			require.Equal(t, deleted.Seq, receiveEvent(t, resumed).Seq)
+
+			// A malformed token ends the subscription with an error.
+			for _, err := range client.Subscribe(ctx, mg.SubscriptionSpec{ResumeAfter: "bogus"}) {
+				require.ErrorContains(t, err, "invalid resume token")
+			}
+		})
+	}
+}
+
+func TestSubscribeRequiresChangelog(t *testing.T) {
+	client, err := mg.NewClient("file://" + GetTempDir(t))
+	require.NoError(t, err)
+	defer client.Close()
+	for _, err := range client.Subscribe(context.Background(), mg.SubscriptionSpec{}) {
+		require.ErrorIs(t, err, mg.ErrChangelogDisabled)
+	}
+}
@@ -0,0 +1,183 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package sync
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	mg "github.com/matthewmcneely/modusgraph"
+)
+
+// maxRequestBytes bounds the request bodies Handler reads.
+const maxRequestBytes = 64 << 20
+
+type readRequest struct {
+	UID        string   `json:"uid"`
+	Predicates []string `json:"predicates"`
+}
+
+type writeRequest struct {
+	Actor string `json:"actor"`
+	Write Write  `json:"write"`
+}
+
+type writeResponse struct {
+	UIDs map[string]string `json:"uids"`
+}
+
+// Handler serves store to NewHTTPStore clients, so stores in other processes
+// can replicate with it. It answers GET /changes, POST /read and POST
+// /write; mount it under a prefix with http.StripPrefix. It does no
+// authentication of its own, so wrap it in whatever guards the service
+// already uses.
+func Handler(store Store) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /changes", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		after, err := strconv.ParseUint(cmp.Or(q.Get("after"), "0"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid after: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		limit, err := strconv.Atoi(cmp.Or(q.Get("limit"), "0"))
+		if err != nil {
+			http.Error(w, "invalid limit: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		events, err := store.Changes(r.Context(), after, limit)
+		respond(w, events, err)
+	})
+	mux.HandleFunc("POST /read", func(w http.ResponseWriter, r *http.Request) {
+		var req readRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+		node, err := store.Read(r.Context(), req.UID, req.Predicates)
+		respond(w, node, err)
+	})
+	mux.HandleFunc("POST /write", func(w http.ResponseWriter, r *http.Request) {
+		var req writeRequest
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+		uids, err := store.Write(r.Context(), req.Actor, req.Write)
+		respond(w, writeResponse{UIDs: uids}, err)
+	})
+	return mux
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request, v any) bool {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	if err == nil {
+		err = decodeJSON(data, v)
+	}
+	if err != nil {
+		http.Error(w, "invalid request: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func respond(w http.ResponseWriter, v any, err error) {
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// HTTPStoreOptions configures NewHTTPStore. Header is added to every
+// request, e.g. for an Authorization token. Client defaults to
+// http.DefaultClient.
+type HTTPStoreOptions struct {
+	Header http.Header
+	Client *http.Client
+}
+
+// HTTPStore is a Store served by Handler in another process.
+type HTTPStore struct {
+	url    string
+	header http.Header
+	client *http.Client
+}
+
+// NewHTTPStore returns a Store calling the Handler mounted at url.
+func NewHTTPStore(url string, opts HTTPStoreOptions) *HTTPStore {
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPStore{url: strings.TrimSuffix(url, "/"), header: opts.Header, client: client}
+}
+
+// Changes fetches the remote store's changes.
+func (s *HTTPStore) Changes(ctx context.Context, after uint64, limit int) ([]mg.ChangeEvent, error) {
+	q := url.Values{}
+	q.Set("after", strconv.FormatUint(after, 10))
+	q.Set("limit", strconv.Itoa(limit))
+	var events []mg.ChangeEvent
+	err := s.do(ctx, http.MethodGet, "/changes?"+q.Encode(), nil, &events)
+	return events, err
+}
+
+// Read reads a node of the remote store.
+func (s *HTTPStore) Read(ctx context.Context, uid string, preds []string) (Node, error) {
+	var node Node
+	err := s.do(ctx, http.MethodPost, "/read", readRequest{UID: uid, Predicates: preds}, &node)
+	return node, err
+}
+
+// Write writes to the remote store.
+func (s *HTTPStore) Write(ctx context.Context, actor string, w Write) (map[string]string, error) {
+	var resp writeResponse
+	err := s.do(ctx, http.MethodPost, "/write", writeRequest{Actor: actor, Write: w}, &resp)
+	return resp.UIDs, err
+}
+
+func (s *HTTPStore) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.url+path, body)
+	if err != nil {
+		return err
+	}
+	for k, v := range s.header {
+		req.Header[k] = v
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("modusgraph/sync: %s %s returned %s: %s",
+			method, s.url+path, resp.Status, strings.TrimSpace(string(data)))
+	}
+	return decodeJSON(data, out)
+}
@@ -0,0 +1,185 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package sync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	mg "github.com/matthewmcneely/modusgraph"
+)
+
+// Store is one side of a replication: a feed of committed changes and reads
+// and writes of single nodes. NewClientStore adapts a modusgraph.Client, and
+// NewHTTPStore reaches a Store that Handler serves from another process.
+type Store interface {
+	// Changes returns up to limit committed changes with Seq greater than
+	// after, without waiting for new ones.
+	Changes(ctx context.Context, after uint64, limit int) ([]mg.ChangeEvent, error)
+
+	// Read returns a node's types and its values of preds. Edges are read
+	// as {"uid": ...} objects. Predicates without a value are absent from
+	// Values.
+	Read(ctx context.Context, uid string, preds []string) (Node, error)
+
+	// Write applies w in one transaction, stamping its changes with actor,
+	// and returns the UIDs assigned to its blank nodes keyed by label
+	// without the "_:" prefix.
+	Write(ctx context.Context, actor string, w Write) (map[string]string, error)
+}
+
+// Node is a node's types and predicate values as read by a Store.
+type Node struct {
+	Types  []string       `json:"types,omitempty"`
+	Values map[string]any `json:"values,omitempty"`
+}
+
+// Write is a set of Dgraph JSON mutation objects. Delete is applied before
+// Set, so deleting a predicate and setting it replaces all its values.
+type Write struct {
+	Delete []map[string]any `json:"delete,omitempty"`
+	Set    []map[string]any `json:"set,omitempty"`
+}
+
+// ClientStore is a Store over a modusgraph.Client created WithChangelog.
+type ClientStore struct {
+	client mg.Client
+
+	mu    sync.Mutex
+	preds map[string]bool // known predicates, true for uid edges
+}
+
+// NewClientStore returns a Store reading and writing c. c must have been
+// created WithChangelog.
+func NewClientStore(c mg.Client) *ClientStore {
+	return &ClientStore{client: c}
+}
+
+// Changes reads the client's changelog.
+func (s *ClientStore) Changes(ctx context.Context, after uint64, limit int) ([]mg.ChangeEvent, error) {
+	return s.client.ReadChangelog(ctx, after, limit)
+}
+
+// Read queries the node, reading uid predicates as their targets' UIDs.
+func (s *ClientStore) Read(ctx context.Context, uid string, preds []string) (Node, error) {
+	edges, err := s.edges(ctx, preds)
+	if err != nil {
+		return Node{}, err
+	}
+	var sel strings.Builder
+	sel.WriteString("uid dgraph.type")
+	for _, p := range preds {
+		name, lang, tagged := strings.Cut(p, "@")
+		sel.WriteString(" <" + name + ">")
+		if tagged {
+			sel.WriteString("@" + lang)
+		}
+		if edges[name] {
+			sel.WriteString(" { uid }")
+		}
+	}
+	data, err := s.client.QueryRaw(ctx, fmt.Sprintf("{ n(func: uid(%s)) { %s } }", uid, sel.String()), nil)
+	if err != nil {
+		return Node{}, err
+	}
+	var resp struct {
+		N []map[string]any `json:"n"`
+	}
+	if err := decodeJSON(data, &resp); err != nil {
+		return Node{}, err
+	}
+	node := Node{Values: map[string]any{}}
+	if len(resp.N) == 0 {
+		return node, nil
+	}
+	if types, ok := resp.N[0]["dgraph.type"].([]any); ok {
+		for _, t := range types {
+			if t, ok := t.(string); ok {
+				node.Types = append(node.Types, t)
+			}
+		}
+	}
+	for _, p := range preds {
+		if v, ok := resp.N[0][p]; ok {
+			node.Values[p] = v
+		}
+	}
+	return node, nil
+}
+
+// edges reports which of preds are uid predicates, reloading the schema
+// when one of them is not known yet.
+func (s *ClientStore) edges(ctx context.Context, preds []string) (map[string]bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range preds {
+		name, _, _ := strings.Cut(p, "@")
+		if _, ok := s.preds[name]; ok {
+			continue
+		}
+		schema, err := s.client.GetSchema(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.preds = make(map[string]bool, len(schema.Predicates))
+		for _, ps := range schema.Predicates {
+			s.preds[ps.Name] = ps.Type == "uid"
+		}
+		break
+	}
+	return s.preds, nil
+}
+
+// Write applies w through one transaction made on behalf of actor.
+func (s *ClientStore) Write(ctx context.Context, actor string, w Write) (map[string]string, error) {
+	ctx = mg.WithActor(ctx, actor)
+	dg, cleanup, err := s.client.DgraphClient()
+	defer cleanup()
+	if err != nil {
+		return nil, err
+	}
+	txn := dg.NewTxn()
+	defer func() { _ = txn.Discard(ctx) }()
+	if len(w.Delete) > 0 {
+		data, err := json.Marshal(w.Delete)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := txn.Mutate(ctx, &api.Mutation{DeleteJson: data}); err != nil {
+			return nil, err
+		}
+	}
+	uids := map[string]string{}
+	if len(w.Set) > 0 {
+		data, err := json.Marshal(w.Set)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := txn.Mutate(ctx, &api.Mutation{SetJson: data})
+		if err != nil {
+			return nil, err
+		}
+		for label, uid := range resp.Uids {
+			uids[strings.TrimPrefix(label, "_:")] = uid
+		}
+	}
+	if err := txn.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return uids, nil
+}
+
+// decodeJSON unmarshals data keeping numbers exact.
+func decodeJSON(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
@@ -0,0 +1,564 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package sync replicates changes between two modusGraph stores in both
+// directions. A Replicator reads each store's changelog and copies the
+// changed predicates to the other, creating counterparts for new nodes and
+// rewriting edges between them. When both stores changed a predicate since
+// it was last replicated, a Resolver merges the two values, by default
+// keeping the later write.
+//
+// Replicating one hub with many spokes, each pair under its own Name, keeps
+// every store converging on the same graph. Handler and NewHTTPStore carry a
+// Store between processes, so embedded spokes can reach an embedded hub:
+//
+//	hub := sync.NewHTTPStore("https://hub.example.com/sync", sync.HTTPStoreOptions{})
+//	r, err := sync.New(sync.NewClientStore(local), hub, sync.Options{
+//	    Name:  "spoke-7",
+//	    State: "/var/lib/app/hub.sync",
+//	})
+//	go r.Run(ctx)
+package sync
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	mg "github.com/matthewmcneely/modusgraph"
+)
+
+// ActorPrefix prefixes the actor a Replicator stamps on its writes, followed
+// by its Name. A Replicator skips the changes carrying its own actor, so its
+// writes are not echoed back.
+const ActorPrefix = "modusgraph-sync:"
+
+const (
+	// defaultBatchSize is how many changes are read per Changes call.
+	defaultBatchSize = 100
+
+	// defaultInterval is how often Run polls the stores for changes.
+	defaultInterval = time.Second
+
+	// maxRetryInterval caps the backoff between failed rounds.
+	maxRetryInterval = time.Minute
+)
+
+// Version is one store's value of a predicate. Value is nil when the
+// predicate has none; Time is the commit time of the change that set it,
+// zero when unknown.
+type Version struct {
+	Value any
+	Time  time.Time
+}
+
+// Resolver merges the values of a predicate changed in both stores since it
+// was last replicated, returning the value to write to both. A nil result
+// deletes the predicate. Edge values are {"uid": ...} objects in the first
+// store's UIDs.
+type Resolver func(pred string, a, b Version) any
+
+// LastWriterWins is the default Resolver: the value with the later commit
+// time wins, and a tie goes to the first store.
+func LastWriterWins(_ string, a, b Version) any {
+	if b.Time.After(a.Time) {
+		return b.Value
+	}
+	return a.Value
+}
+
+// Options configures New.
+type Options struct {
+	// Name identifies the replication and must be unique among those
+	// writing to either store. Its writes carry the actor ActorPrefix+Name.
+	Name string
+
+	// State is the file recording how far each changelog has been read,
+	// which nodes of the two stores correspond, and the last value
+	// replicated for each of their predicates.
+	State string
+
+	// BatchSize is how many changes are read per Changes call; it defaults
+	// to 100.
+	BatchSize int
+
+	// Interval is how often Run looks for new changes; it defaults to one
+	// second.
+	Interval time.Duration
+
+	// Resolve merges conflicting values; it defaults to LastWriterWins.
+	// Resolvers overrides it for the predicates it lists.
+	Resolve   Resolver
+	Resolvers map[string]Resolver
+
+	// Logger receives the errors Run retries.
+	Logger logr.Logger
+}
+
+// Replicator keeps two stores in sync.
+type Replicator struct {
+	stores [2]Store
+	opts   Options
+	actor  string
+	state  state
+}
+
+// state is what a Replicator persists between rounds.
+type state struct {
+	// After is the Seq of the last change handled in each store.
+	After [2]uint64 `json:"after"`
+	Links []*link   `json:"links,omitempty"`
+
+	byUID [2]map[string]*link
+}
+
+// link pairs corresponding nodes of the two stores. Base holds the last
+// value replicated for each predicate, in the first store's UIDs.
+type link struct {
+	UIDs [2]string      `json:"uids"`
+	Base map[string]any `json:"base,omitempty"`
+}
+
+// storeError marks errors from a Store, which Run retries.
+type storeError struct{ err error }
+
+func (e storeError) Error() string { return e.err.Error() }
+func (e storeError) Unwrap() error { return e.err }
+
+// sideLabels prefix the blank node labels naming a store's node in the
+// other store's writes.
+var sideLabels = [2]string{"a", "b"}
+
+// New creates a Replicator between a and b, resuming from opts.State when
+// the file exists.
+func New(a, b Store, opts Options) (*Replicator, error) {
+	if a == nil || b == nil {
+		return nil, errors.New("modusgraph/sync: two stores are required")
+	}
+	if opts.Name == "" {
+		return nil, errors.New("modusgraph/sync: a Name is required")
+	}
+	if opts.State == "" {
+		return nil, errors.New("modusgraph/sync: a State file is required")
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultInterval
+	}
+	if opts.Resolve == nil {
+		opts.Resolve = LastWriterWins
+	}
+	r := &Replicator{stores: [2]Store{a, b}, opts: opts, actor: ActorPrefix + opts.Name}
+	data, err := os.ReadFile(opts.State)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(data, &r.state); err != nil {
+			return nil, fmt.Errorf("modusgraph/sync: invalid state %s: %w", opts.State, err)
+		}
+	}
+	r.state.index()
+	return r, nil
+}
+
+// Run replicates until ctx is done, looking for new changes every Interval.
+// A round failed by either store is retried with backoff, so Run returns
+// only ctx's error or a state file error.
+func (r *Replicator) Run(ctx context.Context) error {
+	wait := r.opts.Interval
+	for {
+		_, err := r.Sync(ctx)
+		var se storeError
+		switch {
+		case err == nil:
+			wait = r.opts.Interval
+		case errors.As(err, &se) && ctx.Err() == nil:
+			r.opts.Logger.Error(err, "Replication round failed; retrying", "name", r.opts.Name, "backoff", wait)
+		default:
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		if err != nil {
+			wait = min(wait*2, maxRetryInterval)
+		}
+	}
+}
+
+// Sync runs one round: it reads the changes committed to both stores since
+// the last round and replicates them in commit time order, returning how
+// many it replicated.
+func (r *Replicator) Sync(ctx context.Context) (int, error) {
+	var events [2][]mg.ChangeEvent
+	for side, store := range r.stores {
+		after := r.state.After[side]
+		for {
+			batch, err := store.Changes(ctx, after, r.opts.BatchSize)
+			if err != nil {
+				return 0, storeError{fmt.Errorf("reading changes of store %s: %w", sideLabels[side], err)}
+			}
+			events[side] = append(events[side], batch...)
+			if len(batch) < r.opts.BatchSize {
+				break
+			}
+			after = batch[len(batch)-1].Seq
+		}
+	}
+	pending := pendingChanges(events, r.actor)
+
+	n := 0
+	var err error
+	for i := [2]int{}; err == nil && (i[0] < len(events[0]) || i[1] < len(events[1])); {
+		side := 0
+		if i[0] == len(events[0]) ||
+			(i[1] < len(events[1]) && events[1][i[1]].CommittedAt.Before(events[0][i[0]].CommittedAt)) {
+			side = 1
+		}
+		ev := events[side][i[side]]
+		i[side]++
+		if ev.Actor != r.actor && ev.UID != "" {
+			var linked bool
+			if linked, err = r.apply(ctx, side, ev, pending); err != nil {
+				break
+			}
+			n++
+			if linked {
+				r.state.After[side] = ev.Seq
+				err = r.save()
+				continue
+			}
+		}
+		r.state.After[side] = ev.Seq
+	}
+	if serr := r.save(); err == nil {
+		err = serr
+	}
+	return n, err
+}
+
+// pendingChanges indexes the commit time of the last change to each
+// predicate of each node, by store, leaving out the changes made by actor.
+// Whole-node deletes are indexed under "".
+func pendingChanges(events [2][]mg.ChangeEvent, actor string) [2]map[string]map[string]time.Time {
+	var pending [2]map[string]map[string]time.Time
+	for side, evs := range events {
+		pending[side] = map[string]map[string]time.Time{}
+		for _, ev := range evs {
+			if ev.Actor == actor {
+				continue
+			}
+			preds := pending[side][ev.UID]
+			if preds == nil {
+				preds = map[string]time.Time{}
+				pending[side][ev.UID] = preds
+			}
+			if ev.Op == mg.ChangeDelete && len(ev.Predicates) == 0 {
+				preds[""] = ev.CommittedAt
+			}
+			for _, p := range ev.Predicates {
+				preds[p] = ev.CommittedAt
+			}
+		}
+	}
+	return pending
+}
+
+// apply replicates one change from store from to the other, reporting
+// whether it linked new nodes.
+func (r *Replicator) apply(ctx context.Context, from int, ev mg.ChangeEvent,
+	pending [2]map[string]map[string]time.Time) (bool, error) {
+	to := 1 - from
+	l := r.state.byUID[from][ev.UID]
+
+	if ev.Op == mg.ChangeDelete && len(ev.Predicates) == 0 {
+		if l == nil {
+			return false, nil
+		}
+		if _, err := r.write(ctx, to, Write{Delete: []map[string]any{{"uid": l.UIDs[to]}}}); err != nil {
+			return false, err
+		}
+		r.state.unlink(l)
+		return false, nil
+	}
+
+	src, err := r.read(ctx, from, ev.UID, ev.Predicates)
+	if err != nil {
+		return false, err
+	}
+	if l == nil && len(src.Values) == 0 {
+		return false, nil // deleted before it was ever replicated
+	}
+	var dst Node
+	if l != nil {
+		if dst, err = r.read(ctx, to, l.UIDs[to], ev.Predicates); err != nil {
+			return false, err
+		}
+	}
+
+	// Work in the first store's UIDs, so values of both sides compare.
+	var writes [2]map[string]any
+	final := map[string]any{}
+	for _, p := range ev.Predicates {
+		mine, theirs := r.toFirst(from, src.Values[p]), r.toFirst(to, dst.Values[p])
+		v := mine
+		switch {
+		case l == nil:
+		case sameValue(mine, theirs):
+			final[p] = mine
+			continue
+		case !sameValue(theirs, l.Base[p]):
+			// The other store changed it too since the last replication.
+			var versions [2]Version
+			versions[from] = Version{Value: mine, Time: ev.CommittedAt}
+			versions[to] = Version{Value: theirs, Time: pending[to][l.UIDs[to]][p]}
+			v = r.resolver(p)(p, versions[0], versions[1])
+			if !sameValue(v, mine) {
+				writes[from] = setValue(writes[from], p, v)
+			}
+			if sameValue(v, theirs) {
+				final[p] = v
+				continue
+			}
+		}
+		writes[to] = setValue(writes[to], p, v)
+		final[p] = v
+	}
+
+	linked := false
+	for _, side := range []int{to, from} {
+		if writes[side] == nil {
+			continue
+		}
+		ref := "_:" + sideLabels[from] + ev.UID
+		w := Write{}
+		if l != nil {
+			ref = l.UIDs[side]
+			del := map[string]any{"uid": ref}
+			for p := range writes[side] {
+				del[p] = nil
+			}
+			w.Delete = append(w.Delete, del)
+		}
+		set := map[string]any{"uid": ref}
+		for p, v := range writes[side] {
+			if v != nil {
+				set[p] = r.fromFirst(side, v)
+			}
+		}
+		if len(src.Types) > 0 {
+			set["dgraph.type"] = src.Types
+		}
+		if len(set) > 1 {
+			w.Set = append(w.Set, set)
+		}
+		uids, err := r.write(ctx, side, w)
+		if err != nil {
+			return false, err
+		}
+		for label, uid := range uids {
+			for origin, prefix := range sideLabels {
+				if other, ok := strings.CutPrefix(label, prefix); ok && origin != side {
+					var uidsOf [2]string
+					uidsOf[origin], uidsOf[side] = other, uid
+					r.state.link(uidsOf)
+					linked = true
+				}
+			}
+		}
+	}
+
+	if l = r.state.byUID[from][ev.UID]; l != nil {
+		if l.Base == nil {
+			l.Base = map[string]any{}
+		}
+		for _, p := range ev.Predicates {
+			delete(l.Base, p)
+			if v := r.fromFirst(0, final[p]); v != nil {
+				l.Base[p] = v
+			}
+		}
+	}
+	return linked, nil
+}
+
+func setValue(values map[string]any, pred string, v any) map[string]any {
+	if values == nil {
+		values = map[string]any{}
+	}
+	values[pred] = v
+	return values
+}
+
+func (r *Replicator) resolver(pred string) Resolver {
+	if res, ok := r.opts.Resolvers[pred]; ok {
+		return res
+	}
+	return r.opts.Resolve
+}
+
+func (r *Replicator) read(ctx context.Context, side int, uid string, preds []string) (Node, error) {
+	node, err := r.stores[side].Read(ctx, uid, preds)
+	if err != nil {
+		return Node{}, storeError{fmt.Errorf("reading %s from store %s: %w", uid, sideLabels[side], err)}
+	}
+	return node, nil
+}
+
+func (r *Replicator) write(ctx context.Context, side int, w Write) (map[string]string, error) {
+	uids, err := r.stores[side].Write(ctx, r.actor, w)
+	if err != nil {
+		return nil, storeError{fmt.Errorf("writing to store %s: %w", sideLabels[side], err)}
+	}
+	return uids, nil
+}
+
+// toFirst rewrites the edges of a value read from side into the first
+// store's UIDs. A target not linked yet becomes a blank node naming it.
+func (r *Replicator) toFirst(side int, v any) any {
+	if side == 0 {
+		return v
+	}
+	return mapUIDs(v, func(uid string) string {
+		if l := r.state.byUID[1][uid]; l != nil {
+			return l.UIDs[0]
+		}
+		return "_:" + sideLabels[1] + uid
+	})
+}
+
+// fromFirst rewrites the edges of a value in the first store's UIDs into
+// side's. Blank nodes naming a node of side resolve to it, and those naming
+// a linked node to its counterpart.
+func (r *Replicator) fromFirst(side int, v any) any {
+	return mapUIDs(v, func(uid string) string {
+		origin, node := 0, uid
+		for s, prefix := range sideLabels {
+			if rest, ok := strings.CutPrefix(uid, "_:"+prefix); ok {
+				origin, node = s, rest
+			}
+		}
+		if origin == side {
+			return node
+		}
+		if l := r.state.byUID[origin][node]; l != nil {
+			return l.UIDs[side]
+		}
+		return "_:" + sideLabels[origin] + node
+	})
+}
+
+// save writes the state atomically.
+func (r *Replicator) save() error {
+	data, err := json.Marshal(&r.state)
+	if err != nil {
+		return err
+	}
+	tmp := r.opts.State + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.opts.State)
+}
+
+func (s *state) index() {
+	s.byUID = [2]map[string]*link{{}, {}}
+	for _, l := range s.Links {
+		s.byUID[0][l.UIDs[0]] = l
+		s.byUID[1][l.UIDs[1]] = l
+	}
+}
+
+func (s *state) link(uids [2]string) {
+	if s.byUID[0][uids[0]] != nil || s.byUID[1][uids[1]] != nil {
+		return
+	}
+	l := &link{UIDs: uids}
+	s.Links = append(s.Links, l)
+	s.byUID[0][uids[0]] = l
+	s.byUID[1][uids[1]] = l
+}
+
+func (s *state) unlink(l *link) {
+	s.Links = slices.DeleteFunc(s.Links, func(x *link) bool { return x == l })
+	delete(s.byUID[0], l.UIDs[0])
+	delete(s.byUID[1], l.UIDs[1])
+}
+
+// mapUIDs rewrites the UIDs of the edges in v with ref.
+func mapUIDs(v any, ref func(string) string) any {
+	switch v := v.(type) {
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = mapUIDs(e, ref)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			out[k] = e
+		}
+		if uid, ok := v["uid"].(string); ok {
+			out["uid"] = ref(uid)
+		}
+		return out
+	}
+	return v
+}
+
+// sameValue compares predicate values as JSON, ignoring list order.
+func sameValue(a, b any) bool {
+	return reflect.DeepEqual(canonical(a), canonical(b))
+}
+
+func canonical(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := decodeJSON(data, &out); err != nil {
+		return v
+	}
+	return sortLists(out)
+}
+
+func sortLists(v any) any {
+	switch v := v.(type) {
+	case []any:
+		if len(v) == 0 {
+			return nil
+		}
+		for i, e := range v {
+			v[i] = sortLists(e)
+		}
+		slices.SortFunc(v, func(a, b any) int {
+			da, _ := json.Marshal(a)
+			db, _ := json.Marshal(b)
+			return cmp.Compare(string(da), string(db))
+		})
+		return v
+	case map[string]any:
+		for k, e := range v {
+			v[k] = sortLists(e)
+		}
+	}
+	return v
+}
@@ -0,0 +1,416 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package sync_test
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	mgsync "github.com/matthewmcneely/modusgraph/sync"
+	"github.com/stretchr/testify/require"
+)
+
+// clock hands out increasing commit times shared by the stores of a test.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) tick() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(time.Second)
+	return c.now
+}
+
+// memStore is an in-memory Store with a changelog.
+type memStore struct {
+	clock *clock
+
+	mu     sync.Mutex
+	prefix string
+	next   int
+	nodes  map[string]map[string]any
+	events []mg.ChangeEvent
+}
+
+func newMemStore(c *clock, prefix string) *memStore {
+	return &memStore{clock: c, prefix: prefix, nodes: map[string]map[string]any{}}
+}
+
+func (m *memStore) Changes(_ context.Context, after uint64, limit int) ([]mg.ChangeEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if after >= uint64(len(m.events)) {
+		return nil, nil
+	}
+	events := m.events[after:]
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return slices.Clone(events), nil
+}
+
+func (m *memStore) Read(_ context.Context, uid string, preds []string) (mgsync.Node, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	node := mgsync.Node{Values: map[string]any{}}
+	values := m.nodes[uid]
+	if types, ok := values["dgraph.type"].([]string); ok {
+		node.Types = types
+	}
+	for _, p := range preds {
+		if v, ok := values[p]; ok {
+			node.Values[p] = v
+		}
+	}
+	return node, nil
+}
+
+func (m *memStore) Write(_ context.Context, actor string, w mgsync.Write) (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, obj := range w.Delete {
+		uid := obj["uid"].(string)
+		if len(obj) == 1 {
+			delete(m.nodes, uid)
+			m.record(actor, mg.ChangeDelete, uid, nil)
+			continue
+		}
+		var preds []string
+		for p := range obj {
+			if p != "uid" {
+				delete(m.nodes[uid], p)
+				preds = append(preds, p)
+			}
+		}
+		m.record(actor, mg.ChangeDelete, uid, preds)
+	}
+	uids := map[string]string{}
+	for _, obj := range w.Set {
+		uid, op := m.resolve(obj["uid"].(string), uids)
+		var preds []string
+		for p, v := range obj {
+			switch p {
+			case "uid":
+			case "dgraph.type":
+				m.nodes[uid][p] = toStrings(v)
+			default:
+				m.nodes[uid][p] = m.resolveEdges(v, uids)
+				preds = append(preds, p)
+			}
+		}
+		m.record(actor, op, uid, preds)
+	}
+	return uids, nil
+}
+
+func toStrings(v any) []string {
+	switch v := v.(type) {
+	case []string:
+		return v
+	case []any:
+		out := make([]string, len(v))
+		for i, e := range v {
+			out[i] = e.(string)
+		}
+		return out
+	}
+	return nil
+}
+
+func (m *memStore) resolve(ref string, uids map[string]string) (string, mg.ChangeOp) {
+	label, blank := strings.CutPrefix(ref, "_:")
+	if !blank {
+		if m.nodes[ref] == nil {
+			m.nodes[ref] = map[string]any{}
+		}
+		return ref, mg.ChangeUpdate
+	}
+	if uid, ok := uids[label]; ok {
+		return uid, mg.ChangeUpdate
+	}
+	m.next++
+	uid := fmt.Sprintf("0x%s%d", m.prefix, m.next)
+	uids[label] = uid
+	m.nodes[uid] = map[string]any{}
+	return uid, mg.ChangeInsert
+}
+
+func (m *memStore) resolveEdges(v any, uids map[string]string) any {
+	switch v := v.(type) {
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = m.resolveEdges(e, uids)
+		}
+		return out
+	case map[string]any:
+		uid, _ := m.resolve(v["uid"].(string), uids)
+		return map[string]any{"uid": uid}
+	}
+	return v
+}
+
+func (m *memStore) record(actor string, op mg.ChangeOp, uid string, preds []string) {
+	slices.Sort(preds)
+	m.events = append(m.events, mg.ChangeEvent{
+		Seq:         uint64(len(m.events) + 1),
+		Op:          op,
+		UID:         uid,
+		Predicates:  preds,
+		CommittedAt: m.clock.tick(),
+		Actor:       actor,
+	})
+}
+
+// put makes a local change, creating a node when uid is empty.
+func (m *memStore) put(t *testing.T, uid string, values map[string]any) string {
+	t.Helper()
+	obj := maps.Clone(values)
+	obj["uid"] = uid
+	if uid == "" {
+		obj["uid"] = "_:new"
+	}
+	uids, err := m.Write(context.Background(), "", mgsync.Write{Set: []map[string]any{obj}})
+	require.NoError(t, err)
+	if uid == "" {
+		return uids["new"]
+	}
+	return uid
+}
+
+func (m *memStore) get(uid, pred string) any {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nodes[uid][pred]
+}
+
+// find returns the UID of the node whose pred is value.
+func (m *memStore) find(pred string, value any) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for uid, values := range m.nodes {
+		if values[pred] == value {
+			return uid
+		}
+	}
+	return ""
+}
+
+func newReplicator(t *testing.T, a, b mgsync.Store, opts mgsync.Options) *mgsync.Replicator {
+	t.Helper()
+	if opts.Name == "" {
+		opts.Name = "test"
+	}
+	if opts.State == "" {
+		opts.State = filepath.Join(t.TempDir(), "state.json")
+	}
+	r, err := mgsync.New(a, b, opts)
+	require.NoError(t, err)
+	return r
+}
+
+func sync1(t *testing.T, r *mgsync.Replicator) int {
+	t.Helper()
+	n, err := r.Sync(context.Background())
+	require.NoError(t, err)
+	return n
+}
+
+func TestReplicateBothWays(t *testing.T) {
+	c := &clock{}
+	a, b := newMemStore(c, "a"), newMemStore(c, "b")
+	r := newReplicator(t, a, b, mgsync.Options{})
+
+	director := a.put(t, "", map[string]any{"name": "Scott", "dgraph.type": []string{"Person"}})
+	film := a.put(t, "", map[string]any{
+		"title":       "Alien",
+		"director":    map[string]any{"uid": director},
+		"dgraph.type": []string{"Film"},
+	})
+	require.Equal(t, 2, sync1(t, r))
+
+	bFilm, bDirector := b.find("title", "Alien"), b.find("name", "Scott")
+	require.NotEmpty(t, bFilm)
+	require.NotEmpty(t, bDirector)
+	require.Equal(t, map[string]any{"uid": bDirector}, b.get(bFilm, "director"))
+	require.Equal(t, []string{"Film"}, b.get(bFilm, "dgraph.type"))
+
+	// A change on the second store flows back to the first, edges included.
+	writer := b.put(t, "", map[string]any{"name": "O'Bannon"})
+	b.put(t, bFilm, map[string]any{"title": "Alien (1979)", "writer": map[string]any{"uid": writer}})
+	require.Equal(t, 2, sync1(t, r))
+	require.Equal(t, "Alien (1979)", a.get(film, "title"))
+	aWriter := a.find("name", "O'Bannon")
+	require.Equal(t, map[string]any{"uid": aWriter}, a.get(film, "writer"))
+
+	// The replicator's own writes are not echoed back.
+	require.Zero(t, sync1(t, r))
+	require.Len(t, a.nodes, 3)
+	require.Len(t, b.nodes, 3)
+
+	// Deleting a node deletes its counterpart.
+	_, err := a.Write(context.Background(), "", mgsync.Write{Delete: []map[string]any{{"uid": aWriter}}})
+	require.NoError(t, err)
+	require.Equal(t, 1, sync1(t, r))
+	require.Empty(t, b.find("name", "O'Bannon"))
+}
+
+func TestLastWriterWins(t *testing.T) {
+	c := &clock{}
+	a, b := newMemStore(c, "a"), newMemStore(c, "b")
+	r := newReplicator(t, a, b, mgsync.Options{})
+	film := a.put(t, "", map[string]any{"title": "Alien", "year": 1979})
+	sync1(t, r)
+	bFilm := b.find("title", "Alien")
+
+	// Both change the title; the later write wins in both stores, whichever
+	// store it was made in.
+	a.put(t, film, map[string]any{"title": "Alien (A)"})
+	b.put(t, bFilm, map[string]any{"title": "Alien (B)"})
+	sync1(t, r)
+	require.Equal(t, "Alien (B)", a.get(film, "title"))
+	require.Equal(t, "Alien (B)", b.get(bFilm, "title"))
+
+	b.put(t, bFilm, map[string]any{"title": "Alien (B2)"})
+	a.put(t, film, map[string]any{"title": "Alien (A2)"})
+	sync1(t, r)
+	require.Equal(t, "Alien (A2)", a.get(film, "title"))
+	require.Equal(t, "Alien (A2)", b.get(bFilm, "title"))
+
+	// Changes to different predicates of one node both survive.
+	a.put(t, film, map[string]any{"title": "Alien (A3)"})
+	b.put(t, bFilm, map[string]any{"year": 1980})
+	sync1(t, r)
+	require.Equal(t, "Alien (A3)", b.get(bFilm, "title"))
+	require.EqualValues(t, 1980, a.get(film, "year"))
+	require.Zero(t, sync1(t, r))
+}
+
+func TestResolvers(t *testing.T) {
+	c := &clock{}
+	a, b := newMemStore(c, "a"), newMemStore(c, "b")
+	union := func(_ string, x, y mgsync.Version) any {
+		var tags []any
+		for _, v := range []any{x.Value, y.Value} {
+			vs, _ := v.([]any)
+			for _, tag := range vs {
+				if !slices.Contains(tags, tag) {
+					tags = append(tags, tag)
+				}
+			}
+		}
+		return tags
+	}
+	r := newReplicator(t, a, b, mgsync.Options{
+		Resolvers: map[string]mgsync.Resolver{"tags": union},
+	})
+	film := a.put(t, "", map[string]any{"title": "Alien", "tags": []any{"horror"}})
+	sync1(t, r)
+	bFilm := b.find("title", "Alien")
+
+	a.put(t, film, map[string]any{"title": "Alien (A)", "tags": []any{"horror", "space"}})
+	b.put(t, bFilm, map[string]any{"title": "Alien (B)", "tags": []any{"horror", "classic"}})
+	sync1(t, r)
+	for _, tags := range []any{a.get(film, "tags"), b.get(bFilm, "tags")} {
+		require.ElementsMatch(t, []any{"horror", "space", "classic"}, tags)
+	}
+	require.Equal(t, "Alien (B)", a.get(film, "title"))
+}
+
+func TestHubAndSpoke(t *testing.T) {
+	c := &clock{}
+	hub := newMemStore(c, "h")
+	srv := httptest.NewServer(http.StripPrefix("/sync", mgsync.Handler(hub)))
+	defer srv.Close()
+	spoke1, spoke2 := newMemStore(c, "s"), newMemStore(c, "t")
+	r1 := newReplicator(t, spoke1, mgsync.NewHTTPStore(srv.URL+"/sync", mgsync.HTTPStoreOptions{}),
+		mgsync.Options{Name: "spoke-1"})
+	r2 := newReplicator(t, spoke2, mgsync.NewHTTPStore(srv.URL+"/sync", mgsync.HTTPStoreOptions{}),
+		mgsync.Options{Name: "spoke-2"})
+
+	film := spoke1.put(t, "", map[string]any{"title": "Alien", "year": 1979})
+	sync1(t, r1)
+	sync1(t, r2)
+	film2 := spoke2.find("title", "Alien")
+	require.NotEmpty(t, film2)
+	require.Equal(t, "1979", fmt.Sprint(spoke2.get(film2, "year")))
+
+	spoke2.put(t, film2, map[string]any{"year": 1980})
+	sync1(t, r2)
+	sync1(t, r1)
+	require.Equal(t, "1980", fmt.Sprint(spoke1.get(film, "year")))
+	require.Len(t, hub.nodes, 1)
+
+	// Each spoke skips only its own writes to the hub.
+	require.Zero(t, sync1(t, r1))
+	require.Zero(t, sync1(t, r2))
+}
+
+func TestNewValidatesOptions(t *testing.T) {
+	c := &clock{}
+	a, b := newMemStore(c, "a"), newMemStore(c, "b")
+	_, err := mgsync.New(a, b, mgsync.Options{State: filepath.Join(t.TempDir(), "s")})
+	require.ErrorContains(t, err, "Name is required")
+	_, err = mgsync.New(a, b, mgsync.Options{Name: "x"})
+	require.ErrorContains(t, err, "State file is required")
+}
+
+type syncDirector struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"name,omitempty" dgraph:"index=exact"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type syncFilm struct {
+	UID      string        `json:"uid,omitempty"`
+	Title    string        `json:"title,omitempty" dgraph:"index=exact"`
+	Director *syncDirector `json:"director,omitempty"`
+	DType    []string      `json:"dgraph.type,omitempty"`
+}
+
+func TestClientStore(t *testing.T) {
+	client, err := mg.NewClient("file://"+t.TempDir(), mg.WithAutoSchema(true), mg.WithChangelog(t.TempDir()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		client.Close()
+		mg.Shutdown()
+	})
+	ctx := context.Background()
+	film := &syncFilm{Title: "Alien", Director: &syncDirector{Name: "Scott"}}
+	require.NoError(t, client.Insert(ctx, film))
+
+	remote := newMemStore(&clock{now: time.Now()}, "r")
+	state := filepath.Join(t.TempDir(), "state.json")
+	r := newReplicator(t, mgsync.NewClientStore(client), remote, mgsync.Options{State: state})
+	sync1(t, r)
+	rFilm, rDirector := remote.find("title", "Alien"), remote.find("name", "Scott")
+	require.NotEmpty(t, rFilm)
+	require.Equal(t, map[string]any{"uid": rDirector}, remote.get(rFilm, "director"))
+
+	remote.put(t, rFilm, map[string]any{"title": "Aliens"})
+	require.Equal(t, 1, sync1(t, r))
+	var got syncFilm
+	require.NoError(t, client.Get(ctx, &got, film.UID))
+	require.Equal(t, "Aliens", got.Title)
+
+	// The write to the client is not echoed, and a new replicator resumes
+	// from the saved state.
+	require.Zero(t, sync1(t, r))
+	r = newReplicator(t, mgsync.NewClientStore(client), remote, mgsync.Options{State: state})
+	require.Zero(t, sync1(t, r))
+}
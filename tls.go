@@ -0,0 +1,80 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+)
+
+// WithTLSConfig secures remote connections with cfg, in place of the TLS
+// settings of the URI. Set cfg.Certificates to present a client certificate
+// to clusters that require mutual TLS, and cfg.RootCAs to trust a private CA:
+//
+//	cert, err := tls.LoadX509KeyPair("client.crt", "client.key")
+//	...
+//	client, err := modusgraph.NewClient("dgraph+tls://dgraph.internal:9080",
+//	    modusgraph.WithTLSConfig(&tls.Config{RootCAs: pool, Certificates: []tls.Certificate{cert}}))
+//
+// Ignored for embedded (file://) URIs.
+func WithTLSConfig(cfg *tls.Config) ClientOpt {
+	return func(o *clientOptions) {
+		o.tlsConfig = cfg
+	}
+}
+
+// uriTLSConfig returns the TLS configuration of a remote URI's sslmode,
+// cacert, cert and key parameters, or nil for a plaintext connection.
+//
+// sslmode=require encrypts without verifying the server; verify-ca verifies
+// it against cacert, a PEM file, or the system roots. cert and key name PEM
+// files holding a client certificate for mutual TLS.
+func uriTLSConfig(params url.Values, sslMode string) (*tls.Config, error) {
+	caFile, certFile, keyFile := params.Get("cacert"), params.Get("cert"), params.Get("key")
+	var cfg *tls.Config
+	switch sslMode {
+	case "disable":
+		if caFile != "" || certFile != "" || keyFile != "" {
+			return nil, errors.New("invalid connection string: cacert, cert and key require TLS, not sslmode=disable")
+		}
+		return nil, nil
+	case "require":
+		if caFile != "" {
+			return nil, errors.New("invalid connection string: cacert requires sslmode=verify-ca")
+		}
+		cfg = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // sslmode=require does not verify, by design
+	case "verify-ca":
+		cfg = &tls.Config{}
+		if caFile != "" {
+			pem, err := os.ReadFile(caFile)
+			if err != nil {
+				return nil, fmt.Errorf("reading cacert: %w", err)
+			}
+			cfg.RootCAs = x509.NewCertPool()
+			if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("reading cacert: no PEM certificates in %s", caFile)
+			}
+		}
+	default:
+		return nil, fmt.Errorf(
+			"invalid SSL mode: %s (must be one of disable, require, verify-ca)", sslMode)
+	}
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("invalid connection string: cert and key must be provided together")
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
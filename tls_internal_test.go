@@ -0,0 +1,170 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// tlsServer is a Dgraph stand-in that answers every query with no results.
+type tlsServer struct {
+	api.UnimplementedDgraphServer
+}
+
+func (tlsServer) CheckVersion(context.Context, *api.Check) (*api.Version, error) {
+	return &api.Version{Tag: "test"}, nil
+}
+
+func (tlsServer) Query(context.Context, *api.Request) (*api.Response, error) {
+	return &api.Response{Json: []byte(`{"q":[]}`), Txn: &api.TxnContext{StartTs: 1}}, nil
+}
+
+// testCert is a certificate and key signed by parent, or self-signed when
+// parent is nil, with their PEM files written to dir.
+type testCert struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certFile string
+	keyFile  string
+}
+
+func newTestCert(t *testing.T, dir, name string, parent *testCert, tmpl *x509.Certificate) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl.SerialNumber = big.NewInt(time.Now().UnixNano())
+	tmpl.Subject = pkix.Name{CommonName: name}
+	tmpl.NotBefore = time.Now().Add(-time.Hour)
+	tmpl.NotAfter = time.Now().Add(time.Hour)
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	tc := &testCert{
+		cert:     cert,
+		key:      key,
+		certFile: filepath.Join(dir, name+".crt"),
+		keyFile:  filepath.Join(dir, name+".key"),
+	}
+	require.NoError(t, os.WriteFile(tc.certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	require.NoError(t, os.WriteFile(tc.keyFile, keyPEM, 0o600))
+	return tc
+}
+
+func (tc *testCert) pair(t *testing.T) tls.Certificate {
+	t.Helper()
+	pair, err := tls.LoadX509KeyPair(tc.certFile, tc.keyFile)
+	require.NoError(t, err)
+	return pair
+}
+
+// startMTLSServer serves tlsServer on a local port, requiring client
+// certificates signed by ca, and returns its host:port.
+func startMTLSServer(t *testing.T, ca, server *testCert) string {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{server.pair(t)},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})))
+	api.RegisterDgraphServer(srv, tlsServer{})
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, dir, "ca", nil, &x509.Certificate{
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+	server := newTestCert(t, dir, "server", ca, &x509.Certificate{
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	clientCert := newTestCert(t, dir, "client", ca, &x509.Certificate{
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	addr := startMTLSServer(t, ca, server)
+
+	ctx := context.Background()
+	query := func(c Client) error {
+		_, err := c.QueryRaw(ctx, `{ q(func: uid(0x1)) { uid } }`, nil)
+		return err
+	}
+
+	t.Run("URIParameters", func(t *testing.T) {
+		c, err := NewClient("dgraph+tls://" + addr + "?cacert=" + ca.certFile +
+			"&cert=" + clientCert.certFile + "&key=" + clientCert.keyFile)
+		require.NoError(t, err)
+		defer c.Close()
+		require.NoError(t, query(c))
+	})
+
+	t.Run("WithoutClientCertificate", func(t *testing.T) {
+		c, err := NewClient("dgraph+tls://" + addr + "?cacert=" + ca.certFile)
+		require.NoError(t, err)
+		defer c.Close()
+		require.Error(t, query(c))
+	})
+
+	t.Run("WithTLSConfig", func(t *testing.T) {
+		pool := x509.NewCertPool()
+		pool.AddCert(ca.cert)
+		c, err := NewClient("dgraph://"+addr, WithTLSConfig(&tls.Config{
+			RootCAs:      pool,
+			Certificates: []tls.Certificate{clientCert.pair(t)},
+		}))
+		require.NoError(t, err)
+		defer c.Close()
+		require.NoError(t, query(c))
+	})
+
+	t.Run("InvalidParameters", func(t *testing.T) {
+		for uri, want := range map[string]string{
+			"dgraph://" + addr + "?cacert=" + ca.certFile:                       "sslmode=disable",
+			"dgraph+tls://" + addr + "?sslmode=require&cacert=" + ca.certFile:   "requires sslmode=verify-ca",
+			"dgraph+tls://" + addr + "?cert=" + clientCert.certFile:             "provided together",
+			"dgraph+tls://" + addr + "?cacert=" + filepath.Join(dir, "missing"): "reading cacert",
+			"dgraph+tls://" + addr + "?cacert=" + clientCert.keyFile:            "no PEM certificates",
+			"dgraph+tls://" + addr + "?sslmode=verify-full":                     "invalid SSL mode",
+		} {
+			_, err := parseDgraphConn(uri)
+			require.ErrorContains(t, err, want, uri)
+		}
+	})
+}
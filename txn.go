@@ -0,0 +1,361 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dgraph-io/dgo/v250"
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/dgraph-io/dgraph/v25/protos/pb"
+	"github.com/dgraph-io/dgraph/v25/query"
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// ErrTxnFinished is returned by the operations of a Txn after its Commit or
+// Discard.
+var ErrTxnFinished = errors.New("modusgraph: transaction already committed or discarded")
+
+// Txn is a transaction spanning several operations, returned by Client.Tx.
+// Its writes take effect together when Commit succeeds, or not at all:
+//
+//	tx, err := client.Tx(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//	defer tx.Discard(ctx)
+//	book.Title, author.Name = "Dune", "Frank Herbert"
+//	if err := tx.Update(ctx, book); err != nil {
+//	    return err
+//	}
+//	if err := tx.Update(ctx, author); err != nil {
+//	    return err
+//	}
+//	return tx.Commit(ctx)
+//
+// Insert, Update and Delete prepare, validate and write objects as the
+// Client methods of the same names do. A write that fails part way discards
+// the transaction, so that none of it can be committed.
+//
+// On a Dgraph cluster, Commit fails with an error wrapping dgo.ErrAborted
+// when a concurrent transaction conflicts with this one; the caller then
+// runs the whole transaction again. Reads through a Txn see its own
+// uncommitted writes. An embedded (file://) store does no commit-time
+// conflict check: concurrent transactions all commit, and where they write
+// the same predicate of a node the last to commit wins. It applies a Txn's
+// writes only when it commits, so reads through it, and the unique_group
+// checks of its writes, see the committed graph.
+//
+// A Txn holds one of the client's connections until it ends, and is safe
+// for concurrent use, though its operations run one at a time.
+type Txn struct {
+	c  client
+	dc *dgo.Dgraph
+	tx *dg.TxnContext
+	// staged buffers the writes on an embedded store; nil on a cluster.
+	staged *stagedTxn
+
+	mu   sync.Mutex
+	done bool
+}
+
+// Tx implements starting a multi-operation transaction.
+func (c client) Tx(ctx context.Context) (*Txn, error) {
+	if err := c.writable(); err != nil {
+		return nil, err
+	}
+	dc, err := c.pool.get()
+	if err != nil {
+		c.log(ctx).Error(err, "Failed to get client from pool")
+		return nil, err
+	}
+	t := &Txn{c: c, dc: dc}
+	if c.engine != nil {
+		t.staged = &stagedTxn{}
+	}
+	t.tx = dg.NewTxnContext(t.context(ctx), dc)
+	return t, nil
+}
+
+// context returns ctx carrying t's staged writes, if any, and makes it the
+// context of t's operations.
+func (t *Txn) context(ctx context.Context) context.Context {
+	if t.staged != nil {
+		ctx = context.WithValue(ctx, stagedTxnKey{}, t.staged)
+	}
+	if t.tx != nil {
+		t.tx.WithContext(ctx)
+	}
+	return ctx
+}
+
+// lock locks t for an operation, failing with ErrTxnFinished once it ended.
+func (t *Txn) lock() error {
+	t.mu.Lock()
+	if t.done {
+		t.mu.Unlock()
+		return ErrTxnFinished
+	}
+	return nil
+}
+
+// Insert is Client.Insert within t.
+func (t *Txn) Insert(ctx context.Context, obj any) error {
+	return t.write(ctx, obj, "Insert")
+}
+
+// Update is Client.Update within t.
+func (t *Txn) Update(ctx context.Context, obj any) error {
+	return t.write(ctx, obj, "Update")
+}
+
+func (t *Txn) write(ctx context.Context, obj any, operation string) error {
+	if err := t.lock(); err != nil {
+		return err
+	}
+	defer t.mu.Unlock()
+	c := t.c
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	obj = UnwrapSchema(obj)
+	if err := c.applyComputed(ctx, obj); err != nil {
+		return err
+	}
+	NormalizeTimes(c, obj)
+	if err := c.validateStruct(ctx, obj); err != nil {
+		return err
+	}
+	if err := c.prepare(ctx, obj); err != nil {
+		return err
+	}
+
+	ctx = t.context(ctx)
+	uids, err := c.mutateIn(ctx, t.tx, obj, func(tx *dg.TxnContext, obj any) ([]string, error) {
+		defer c.lockUniqueGroups(obj)()
+		if err := c.checkUniqueGroups(ctx, tx, obj, ""); err != nil {
+			return nil, err
+		}
+		return tx.MutateBasic(obj)
+	})
+	if err != nil {
+		_ = t.end(ctx)
+		return err
+	}
+	c.log(ctx).V(2).Info(operation+" in transaction successful", "uidCount", len(uids))
+	return nil
+}
+
+// Delete is Client.Delete within t, applying the client's ondelete and
+// softdelete policies, including those of obj's type.
+func (t *Txn) Delete(ctx context.Context, obj any, opts ...DeleteOpt) error {
+	var o deleteOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	uids, err := deleteTargets(obj, o.cascade)
+	if err != nil {
+		return err
+	}
+	if _, ok := obj.([]string); !ok {
+		t.c.policies.register(obj)
+	}
+	if err := checkUIDs(uids...); err != nil {
+		return err
+	}
+	if err := t.lock(); err != nil {
+		return err
+	}
+	defer t.mu.Unlock()
+	ctx, cancel := t.c.withTimeout(ctx)
+	defer cancel()
+
+	ctx = t.context(ctx)
+	if !o.purge {
+		uids, err = t.c.markDeleted(ctx, t.tx, uids)
+	}
+	switch rules := t.c.policies.deleteRules(); {
+	case err != nil || len(uids) == 0:
+	case len(rules) > 0 || o.detach:
+		_, _, err = t.c.deleteInTxn(ctx, t.tx, uids, rules, o.detach)
+	default:
+		err = t.tx.DeleteNode(uids...)
+	}
+	if err != nil {
+		_ = t.end(ctx)
+	}
+	return err
+}
+
+// Get is Client.Get within t.
+func (t *Txn) Get(ctx context.Context, obj any, uid string, opts ...GetOpt) error {
+	obj = UnwrapSchema(obj)
+	if err := checkPointer(obj); err != nil {
+		return err
+	}
+	if err := checkUIDs(uid); err != nil {
+		return err
+	}
+	if err := t.lock(); err != nil {
+		return err
+	}
+	defer t.mu.Unlock()
+	ctx, cancel := t.c.withTimeout(ctx)
+	defer cancel()
+
+	t.context(ctx)
+	if err := getNode(t.tx, obj, uid, t.c.getOptions(opts)); err != nil {
+		return err
+	}
+	NormalizeTimes(t.c, obj)
+	return LoadLocalized(ctx, t.c, obj)
+}
+
+// Query is Client.Query within t. The query runs with ctx when it is
+// executed, and fails with dgo.ErrFinished once t has ended.
+func (t *Txn) Query(ctx context.Context, model any) *dg.Query {
+	model = UnwrapSchema(model)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.context(ctx)
+	return expand(t.tx.Get(model), model, t.c.options.maxEdgeTraversal, t.c.options.maxEdgeFanout)
+}
+
+// QueryRaw is Client.QueryRaw within t.
+func (t *Txn) QueryRaw(ctx context.Context, q string, vars map[string]string) ([]byte, error) {
+	if err := t.lock(); err != nil {
+		return nil, err
+	}
+	defer t.mu.Unlock()
+	ctx, cancel := t.c.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := t.tx.Txn().QueryWithVars(t.context(ctx), q, vars)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetJson(), nil
+}
+
+// Commit applies the writes of t. Whether or not it succeeds, t ends:
+// later operations fail with ErrTxnFinished.
+func (t *Txn) Commit(ctx context.Context) error {
+	if err := t.lock(); err != nil {
+		return err
+	}
+	defer t.mu.Unlock()
+	ctx, cancel := t.c.withTimeout(ctx)
+	defer cancel()
+
+	t.done = true
+	defer t.c.pool.put(t.dc)
+	if err := t.tx.Txn().Commit(t.context(ctx)); err != nil {
+		return fmt.Errorf("committing transaction: %w", uniqueConflict(err))
+	}
+	return nil
+}
+
+// Discard drops the writes of t and ends it. It does nothing once t has
+// ended, so it can be deferred right after Client.Tx.
+func (t *Txn) Discard(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.done {
+		return nil
+	}
+	return t.end(ctx)
+}
+
+// end discards t; the caller holds t.mu.
+func (t *Txn) end(ctx context.Context) error {
+	t.done = true
+	defer t.c.pool.put(t.dc)
+	return t.tx.Txn().Discard(t.context(ctx))
+}
+
+// stagedTxnKey is the context key of a Txn's stagedTxn.
+type stagedTxnKey struct{}
+
+// stagedTxn holds the writes of a Txn on an embedded store, which otherwise
+// applies each mutation as it arrives. Mutations are converted to edges
+// when they are made, with uids assigned to their new nodes, and applied
+// in one transaction when the Txn commits.
+type stagedTxn struct {
+	mu    sync.Mutex
+	edges []*pb.DirectedEdge
+	// records log the staged mutations to the changelog on commit.
+	records []func() error
+}
+
+// stagedFrom returns the stagedTxn ctx carries, if any.
+func stagedFrom(ctx context.Context) *stagedTxn {
+	s, _ := ctx.Value(stagedTxnKey{}).(*stagedTxn)
+	return s
+}
+
+// mutate applies ms, or stages them when ctx carries a Txn's writes.
+func (c *embeddedDgraphClient) mutate(ctx context.Context, ms []*api.Mutation) (map[string]uint64, error) {
+	s := stagedFrom(ctx)
+	if s == nil {
+		return c.ns.Mutate(ctx, ms)
+	}
+	c.engine.mutex.Lock()
+	defer c.engine.mutex.Unlock()
+	dms, newUids, err := c.engine.parseMutations(ctx, ms)
+	if err != nil {
+		return nil, err
+	}
+	edges, err := query.ToDirectedEdges(dms, newUids)
+	if err != nil {
+		return nil, fmt.Errorf("error converting to directed edges: %w", err)
+	}
+	s.mu.Lock()
+	s.edges = append(s.edges, edges...)
+	s.mu.Unlock()
+	return newUids, nil
+}
+
+// record logs a mutation request to the changelog, or defers that to the
+// commit of the Txn whose writes ctx carries.
+func (c *embeddedDgraphClient) record(ctx context.Context, in *api.Request, resp *api.Response) error {
+	if c.changes == nil {
+		return nil
+	}
+	s := stagedFrom(ctx)
+	if s == nil {
+		return c.changes.record(ctx, in, resp, true, c.now())
+	}
+	s.mu.Lock()
+	s.records = append(s.records, func() error { return c.changes.record(ctx, in, resp, true, c.now()) })
+	s.mu.Unlock()
+	return nil
+}
+
+// commitStaged applies the writes of s, or drops them when aborted.
+func (c *embeddedDgraphClient) commitStaged(ctx context.Context, s *stagedTxn, aborted bool) error {
+	s.mu.Lock()
+	edges, records := s.edges, s.records
+	s.edges, s.records = nil, nil
+	s.mu.Unlock()
+	if aborted || len(edges) == 0 {
+		return nil
+	}
+
+	c.engine.mutex.Lock()
+	err := c.engine.applyEdges(ctx, c.ns, edges, nil)
+	c.engine.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := record(); err != nil {
+			return fmt.Errorf("recording changelog: %w", err)
+		}
+	}
+	return nil
+}
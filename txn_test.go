@@ -0,0 +1,140 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type TxnAuthor struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"txn_author_name,omitempty" dgraph:"index=exact"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type TxnBook struct {
+	UID    string     `json:"uid,omitempty"`
+	Title  string     `json:"txn_book_title,omitempty" dgraph:"index=exact"`
+	Author *TxnAuthor `json:"txn_book_author,omitempty"`
+	DType  []string   `json:"dgraph.type,omitempty"`
+}
+
+func TestTxn(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "TxnWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "TxnWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+			remote := strings.HasPrefix(tc.uri, "dgraph://")
+
+			countBooks := func() int {
+				books, err := mg.Query[TxnBook](ctx, client).All()
+				require.NoError(t, err)
+				return len(books)
+			}
+
+			// Commit makes both writes visible together.
+			author := &TxnAuthor{Name: "Frank Herbert"}
+			book := &TxnBook{Title: "Dune", Author: author}
+			tx, err := client.Tx(ctx)
+			require.NoError(t, err)
+			require.NoError(t, tx.Insert(ctx, author))
+			require.NoError(t, tx.Insert(ctx, book))
+			require.NotEmpty(t, book.UID)
+			require.Zero(t, countBooks(), "uncommitted writes must not be visible outside the transaction")
+			if remote {
+				var own TxnBook
+				require.NoError(t, tx.Get(ctx, &own, book.UID))
+				require.Equal(t, "Dune", own.Title)
+			}
+			require.NoError(t, tx.Commit(ctx))
+			require.Equal(t, 1, countBooks())
+
+			var got TxnBook
+			require.NoError(t, client.Get(ctx, &got, book.UID))
+			require.Equal(t, "Dune", got.Title)
+			require.NotNil(t, got.Author)
+			require.Equal(t, author.UID, got.Author.UID)
+
+			// A finished transaction rejects further operations.
+			require.ErrorIs(t, tx.Insert(ctx, &TxnBook{Title: "Late"}), mg.ErrTxnFinished)
+			require.ErrorIs(t, tx.Commit(ctx), mg.ErrTxnFinished)
+			require.NoError(t, tx.Discard(ctx))
+
+			// Discard drops both updates.
+			tx, err = client.Tx(ctx)
+			require.NoError(t, err)
+			book.Title, author.Name = "Dune Messiah", "F. Herbert"
+			require.NoError(t, tx.Update(ctx, book))
+			require.NoError(t, tx.Update(ctx, author))
+			require.NoError(t, tx.Discard(ctx))
+
+			var after TxnBook
+			require.NoError(t, client.Get(ctx, &after, book.UID))
+			require.Equal(t, "Dune", after.Title)
+			var afterAuthor TxnAuthor
+			require.NoError(t, client.Get(ctx, &afterAuthor, author.UID))
+			require.Equal(t, "Frank Herbert", afterAuthor.Name)
+
+			// Reads through a transaction see the committed graph.
+			tx, err = client.Tx(ctx)
+			require.NoError(t, err)
+			defer func() { _ = tx.Discard(ctx) }()
+			var titles []TxnBook
+			require.NoError(t, tx.Query(ctx, TxnBook{}).Nodes(&titles))
+			require.Len(t, titles, 1)
+			raw, err := tx.QueryRaw(ctx, `{ q(func: type(TxnAuthor)) { txn_author_name } }`, nil)
+			require.NoError(t, err)
+			require.Contains(t, string(raw), "Frank Herbert")
+
+			// Deletes commit with the other writes.
+			require.NoError(t, tx.Delete(ctx, []string{book.UID, author.UID}))
+			require.NoError(t, tx.Insert(ctx, &TxnBook{Title: "Children of Dune"}))
+			require.NoError(t, tx.Commit(ctx))
+			books, err := mg.Query[TxnBook](ctx, client).All()
+			require.NoError(t, err)
+			require.Len(t, books, 1)
+			require.Equal(t, "Children of Dune", books[0].Title)
+		})
+	}
+}
+
+func TestTxnReadOnly(t *testing.T) {
+	client, cleanup := CreateTestClient(t, "file://"+GetTempDir(t))
+	defer cleanup()
+
+	readOnly, err := client.With(mg.WithReadOnly())
+	require.NoError(t, err)
+	_, err = readOnly.Tx(context.Background())
+	require.ErrorIs(t, err, mg.ErrReadOnly)
+}
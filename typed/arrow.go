@@ -0,0 +1,324 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package typed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// arrowColumn is one scalar field of T projected into an Arrow column.
+type arrowColumn struct {
+	predicate string
+	field     arrow.Field
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// Arrow executes the query as a scalar projection and returns the result as a
+// single Arrow record batch with one row per matching node. The columns are
+// uid (string) followed by each scalar field of T in declaration order, named
+// after its Dgraph predicate and typed string, int64, float64, bool, or
+// timestamp[us, UTC]; all are nullable. Edges, lists, and vectors are not
+// projected — the query selects only the scalar predicates, so no edge data
+// is fetched.
+//
+// The response is decoded token by token straight into the column builders,
+// without materializing a T (or a map) per row, so analytical scans pay for the
+// columns rather than for reflection. ctx governs the execution; the caller
+// must Release the returned record.
+func (qb *Query[T]) Arrow(ctx context.Context) (rec arrow.RecordBatch, err error) {
+	if err := qb.usable(); err != nil {
+		return nil, err
+	}
+	ctx, span := currentTracer().StartSpan(ctx, "query", entityName[T]())
+	defer func() { span.End(err) }()
+
+	schema, columns := arrowSchema(reflect.TypeFor[T]())
+	selection := make([]string, len(columns))
+	for i, col := range columns {
+		selection[i] = col.predicate
+	}
+	qb.q.Query("{ " + strings.Join(selection, " ") + " }")
+
+	var raw []byte
+	block := ""
+	if len(qb.edges) > 0 {
+		req := dg.NewQueryBlock(qb.edgeBlocks(false)...)
+		if qb.varsMap != nil {
+			req.Vars(qb.varsFuncDef, qb.varsMap)
+		}
+		raw, err = qb.conn.QueryRaw(ctx, req.String(), qb.varsMap)
+		block = edgeDataBlock
+	} else {
+		raw, err = qb.conn.QueryRaw(ctx, qb.q.String(), qb.varsMap)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+	if err := decodeArrowRows(raw, block, columns, b); err != nil {
+		return nil, fmt.Errorf("typed: decoding Arrow rows: %w", err)
+	}
+	return b.NewRecordBatch(), nil
+}
+
+// arrowSchema derives the Arrow schema for T's scalar fields, uid first.
+func arrowSchema(t reflect.Type) (*arrow.Schema, []arrowColumn) {
+	columns := []arrowColumn{{
+		predicate: "uid",
+		field:     arrow.Field{Name: "uid", Type: arrow.BinaryTypes.String, Nullable: true},
+	}}
+	seen := map[string]bool{"uid": true}
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		pred := arrowPredicate(sf)
+		if pred == "" || seen[pred] || pred == "dgraph.type" || strings.HasPrefix(pred, "~") {
+			continue
+		}
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		var dt arrow.DataType
+		switch {
+		case ft == timeType:
+			dt = arrow.FixedWidthTypes.Timestamp_us
+		case ft.Kind() == reflect.String:
+			dt = arrow.BinaryTypes.String
+		case ft.Kind() == reflect.Bool:
+			dt = arrow.FixedWidthTypes.Boolean
+		case ft.Kind() >= reflect.Int && ft.Kind() <= reflect.Uint32:
+			dt = arrow.PrimitiveTypes.Int64
+		case ft.Kind() == reflect.Float32 || ft.Kind() == reflect.Float64:
+			dt = arrow.PrimitiveTypes.Float64
+		default:
+			continue // edges, lists, vectors, maps
+		}
+		seen[pred] = true
+		columns = append(columns, arrowColumn{
+			predicate: pred,
+			field:     arrow.Field{Name: pred, Type: dt, Nullable: true},
+		})
+	}
+	fields := make([]arrow.Field, len(columns))
+	for i, col := range columns {
+		fields[i] = col.field
+	}
+	return arrow.NewSchema(fields, nil), columns
+}
+
+// arrowPredicate returns the predicate a field is stored under: predicate=
+// when present, else the json name.
+func arrowPredicate(sf reflect.StructField) string {
+	for part := range strings.FieldsSeq(sf.Tag.Get("dgraph")) {
+		if p, ok := strings.CutPrefix(part, "predicate="); ok {
+			return p
+		}
+	}
+	name := strings.Split(sf.Tag.Get("json"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return sf.Name
+	}
+	return name
+}
+
+// decodeArrowRows streams the rows of the named response block (or the first
+// array-valued block when block is "") into b.
+func decodeArrowRows(raw []byte, block string, columns []arrowColumn, b *array.RecordBuilder) error {
+	var blocks map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return err
+	}
+	body, ok := blocks[block]
+	if block == "" {
+		for _, v := range blocks {
+			if firstNonSpace(v) == '[' {
+				body, ok = v, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+	index := make(map[string]int, len(columns))
+	for i, col := range columns {
+		index[col.predicate] = i
+	}
+	filled := make([]bool, len(columns))
+	for dec.More() {
+		if err := decodeArrowRow(dec, index, columns, filled, b); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// decodeArrowRow appends one row object to the builders, null-filling absent
+// columns.
+func decodeArrowRow(dec *json.Decoder, index map[string]int, columns []arrowColumn,
+	filled []bool, b *array.RecordBuilder) error {
+	clear(filled)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		i, ok := index[key]
+		if !ok || filled[i] {
+			if err := skipValue(dec); err != nil {
+				return err
+			}
+			continue
+		}
+		tok, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		if d, isDelim := tok.(json.Delim); isDelim {
+			// A scalar predicate answered with a list (a [type] predicate
+			// declared as a scalar field): consume it and leave the cell null.
+			if err := skipRest(dec, d); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := appendArrowValue(b.Field(i), columns[i].field.Type, tok); err != nil {
+			return fmt.Errorf("column %s: %w", key, err)
+		}
+		filled[i] = true
+	}
+	for i, ok := range filled {
+		if !ok {
+			b.Field(i).AppendNull()
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+func appendArrowValue(fb array.Builder, dt arrow.DataType, tok json.Token) error {
+	if tok == nil {
+		fb.AppendNull()
+		return nil
+	}
+	switch dt.ID() {
+	case arrow.STRING:
+		fb.(*array.StringBuilder).Append(fmt.Sprint(tok))
+	case arrow.BOOL:
+		v, ok := tok.(bool)
+		if !ok {
+			return fmt.Errorf("expected bool, got %T", tok)
+		}
+		fb.(*array.BooleanBuilder).Append(v)
+	case arrow.INT64:
+		n, ok := tok.(json.Number)
+		if !ok {
+			return fmt.Errorf("expected number, got %T", tok)
+		}
+		v, err := n.Int64()
+		if err != nil {
+			return err
+		}
+		fb.(*array.Int64Builder).Append(v)
+	case arrow.FLOAT64:
+		n, ok := tok.(json.Number)
+		if !ok {
+			return fmt.Errorf("expected number, got %T", tok)
+		}
+		v, err := n.Float64()
+		if err != nil {
+			return err
+		}
+		fb.(*array.Float64Builder).Append(v)
+	case arrow.TIMESTAMP:
+		s, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("expected datetime string, got %T", tok)
+		}
+		ts, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return err
+		}
+		fb.(*array.TimestampBuilder).AppendTime(ts)
+	default:
+		return fmt.Errorf("unsupported Arrow type %s", dt)
+	}
+	return nil
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("expected %q, got %v", want, tok)
+	}
+	return nil
+}
+
+// skipValue consumes the next complete value from dec.
+func skipValue(dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); ok {
+		return skipRest(dec, d)
+	}
+	return nil
+}
+
+// skipRest consumes the remainder of an object or array whose opening
+// delimiter has already been read.
+func skipRest(dec *json.Decoder, open json.Delim) error {
+	if open != '{' && open != '[' {
+		return nil
+	}
+	for depth := 1; depth > 0; {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if d, ok := tok.(json.Delim); ok {
+			switch d {
+			case '{', '[':
+				depth++
+			default:
+				depth--
+			}
+		}
+	}
+	return nil
+}
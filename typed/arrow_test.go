@@ -0,0 +1,94 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package typed_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/matthewmcneely/modusgraph/typed"
+)
+
+// reading is a scalar-only record with a nullable field, exercising every
+// column type Arrow projects.
+type reading struct {
+	UID     string    `json:"uid,omitempty"`
+	DType   []string  `json:"dgraph.type,omitempty"`
+	Sensor  string    `json:"sensor,omitempty" dgraph:"index=exact"`
+	Value   float64   `json:"value,omitempty"`
+	Count   int       `json:"count,omitempty"`
+	Ok      bool      `json:"ok,omitempty"`
+	TakenAt time.Time `json:"takenAt,omitempty"`
+	Owner   *owner    `json:"owner,omitempty"`
+}
+
+func TestQuery_ArrowProjectsScalarColumns(t *testing.T) {
+	ctx := context.Background()
+	c := typed.NewClient[reading](newConn(t))
+	taken := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, r := range []*reading{
+		{Sensor: "a", Value: 1.5, Count: 3, Ok: true, TakenAt: taken},
+		{Sensor: "b", Value: 2.5},
+	} {
+		if err := c.Add(ctx, r); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	rec, err := c.Query(ctx).OrderAsc("sensor").Arrow(ctx)
+	if err != nil {
+		t.Fatalf("Arrow: %v", err)
+	}
+	defer rec.Release()
+
+	wantCols := []string{"uid", "sensor", "value", "count", "ok", "takenAt"}
+	if int(rec.NumCols()) != len(wantCols) {
+		t.Fatalf("NumCols = %d, want %d (%v)", rec.NumCols(), len(wantCols), rec.Schema())
+	}
+	for i, name := range wantCols {
+		if got := rec.ColumnName(i); got != name {
+			t.Errorf("column %d = %q, want %q", i, got, name)
+		}
+	}
+	if rec.NumRows() != 2 {
+		t.Fatalf("NumRows = %d, want 2", rec.NumRows())
+	}
+
+	sensors := rec.Column(1).(*array.String)
+	values := rec.Column(2).(*array.Float64)
+	counts := rec.Column(3).(*array.Int64)
+	oks := rec.Column(4).(*array.Boolean)
+	takenAts := rec.Column(5).(*array.Timestamp)
+	if sensors.Value(0) != "a" || sensors.Value(1) != "b" {
+		t.Errorf("sensor = [%q %q], want [a b]", sensors.Value(0), sensors.Value(1))
+	}
+	if values.Value(0) != 1.5 || values.Value(1) != 2.5 {
+		t.Errorf("value = [%v %v], want [1.5 2.5]", values.Value(0), values.Value(1))
+	}
+	if counts.Value(0) != 3 || !counts.IsNull(1) {
+		t.Errorf("count row 1 should be null; got %v", counts)
+	}
+	if !oks.Value(0) || !oks.IsNull(1) {
+		t.Errorf("ok = %v, want [true null]", oks)
+	}
+	if got := takenAts.Value(0).ToTime(arrow.Microsecond); !got.Equal(taken) {
+		t.Errorf("takenAt = %v, want %v", got, taken)
+	}
+	if !takenAts.IsNull(1) {
+		t.Errorf("takenAt row 1 should be null")
+	}
+}
+
+func TestQuery_ArrowDetachedReturnsError(t *testing.T) {
+	_, err := typed.NewDetachedQuery[reading]().Arrow(context.Background())
+	if !errors.Is(err, typed.ErrDetachedQuery) {
+		t.Fatalf("Arrow() error = %v, want ErrDetachedQuery", err)
+	}
+}
@@ -19,22 +19,36 @@ type Client[T any] struct {
 	conn modusgraph.Client
 }
 
-// NewClient binds a Client[T] to conn.
+// NewClient binds a Client[T] to conn, registering the ondelete policies of
+// T for conn's Delete.
 func NewClient[T any](conn modusgraph.Client) *Client[T] {
+	conn.RegisterModels(new(T))
 	return &Client[T]{conn: conn}
 }
 
-// Get loads the T with the given UID.
-func (c *Client[T]) Get(ctx context.Context, uid string) (rec *T, err error) {
+// Get loads the T with the given UID. opts override the connection's edge
+// depth and fan-out limits for this call.
+func (c *Client[T]) Get(ctx context.Context, uid string, opts ...modusgraph.GetOpt) (rec *T, err error) {
 	ctx, span := currentTracer().StartSpan(ctx, "get", entityName[T]())
 	defer func() { span.End(err) }()
 	var out T
-	if err = c.conn.Get(ctx, &out, uid); err != nil {
+	if err = c.conn.Get(ctx, &out, uid, opts...); err != nil {
 		return nil, err
 	}
 	return &out, nil
 }
 
+// GetByUniqueGroup loads the T whose values for the fields tagged
+// dgraph:"unique_group=<group>" equal those set in key, filling in key.
+func (c *Client[T]) GetByUniqueGroup(ctx context.Context, group string, key *T) (rec *T, err error) {
+	ctx, span := currentTracer().StartSpan(ctx, "getByUniqueGroup", entityName[T]())
+	defer func() { span.End(err) }()
+	if err = c.conn.GetByUniqueGroup(ctx, key, group); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
 // Add inserts a new T. modusgraph writes the assigned UID back into rec.
 func (c *Client[T]) Add(ctx context.Context, rec *T) (err error) {
 	ctx, span := currentTracer().StartSpan(ctx, "add", entityName[T]())
@@ -57,6 +71,16 @@ func (c *Client[T]) Upsert(ctx context.Context, rec *T, predicates ...string) (e
 	return c.conn.Upsert(ctx, rec, predicates...)
 }
 
+// UpsertIf updates the T whose key predicate equals rec's only if it
+// satisfies cond, inserting rec if there is none, and reports whether rec was
+// written. With an empty key, the first field tagged dgraph:"upsert" is used.
+func (c *Client[T]) UpsertIf(ctx context.Context, rec *T, key string,
+	cond modusgraph.Condition) (applied bool, err error) {
+	ctx, span := currentTracer().StartSpan(ctx, "upsertIf", entityName[T]())
+	defer func() { span.End(err) }()
+	return c.conn.UpsertIf(ctx, rec, key, cond)
+}
+
 // LoadOrStore stores rec only if no node matches the upsert predicates,
 // returning the resulting record and loaded=true when one already existed.
 // Insert-if-absent (compare sync.Map.LoadOrStore). With no predicates, the
@@ -86,18 +110,52 @@ func (c *Client[T]) LoadAndDelete(ctx context.Context, key any, predicates ...st
 	return &out, true, nil
 }
 
-// Delete removes the T with the given UID.
+// Delete removes the T with the given UID, or marks it deleted when T has a
+// dgraph:"softdelete" field; see Restore and Purge.
 func (c *Client[T]) Delete(ctx context.Context, uid string) (err error) {
 	ctx, span := currentTracer().StartSpan(ctx, "delete", entityName[T]())
 	defer func() { span.End(err) }()
 	return c.conn.Delete(ctx, []string{uid})
 }
 
+// Restore brings back the T with the given UID that Delete marked deleted;
+// T must have a dgraph:"softdelete" field.
+func (c *Client[T]) Restore(ctx context.Context, uid string) (err error) {
+	ctx, span := currentTracer().StartSpan(ctx, "restore", entityName[T]())
+	defer func() { span.End(err) }()
+	return c.conn.Undelete(ctx, []string{uid})
+}
+
+// Purge removes the T with the given UID for good, even when T has a
+// dgraph:"softdelete" field and Delete would only mark it deleted.
+func (c *Client[T]) Purge(ctx context.Context, uid string) (err error) {
+	ctx, span := currentTracer().StartSpan(ctx, "purge", entityName[T]())
+	defer func() { span.End(err) }()
+	return c.conn.Delete(ctx, []string{uid}, modusgraph.DeletePurge())
+}
+
+// FindSimilar returns the k records whose vectors in the hnsw-indexed
+// predicate field are nearest to vec, nearest first. field names a
+// float32vector field, or a SimString field tagged dgraph:"embedding".
+func (c *Client[T]) FindSimilar(ctx context.Context, field string, vec []float32, k int) (recs []T, err error) {
+	ctx, span := currentTracer().StartSpan(ctx, "findSimilar", entityName[T]())
+	defer func() { span.End(err) }()
+	if err = c.conn.SimilarTo(ctx, &recs, field, vec, k); err != nil {
+		return nil, err
+	}
+	return recs, nil
+}
+
 // Query returns a typed query builder for T. conn and ctx are carried so the
 // builder can run a WhereEdge pre-pass (see Query.WhereEdge) if one is needed.
 func (c *Client[T]) Query(ctx context.Context) *Query[T] {
 	var z T
-	return &Query[T]{q: c.conn.Query(ctx, &z), conn: c.conn, ctx: ctx}
+	qb := &Query[T]{q: c.conn.Query(ctx, &z), conn: c.conn, ctx: ctx}
+	if pred, ok := modusgraph.SoftDeletePredicate(&z); ok {
+		qb.addFilter("NOT has("+pred+")", nil)
+		qb.deleted = true
+	}
+	return qb
 }
 
 // defaultPageSize is the page size IterNodes uses to page through results.
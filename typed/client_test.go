@@ -7,8 +7,11 @@ package typed_test
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
+	dg "github.com/dolan-in/dgman/v2"
 	"github.com/matthewmcneely/modusgraph"
 	"github.com/matthewmcneely/modusgraph/typed"
 )
@@ -108,6 +111,60 @@ func TestClient_Delete(t *testing.T) {
 	}
 }
 
+// memo is deleted softly: Delete stamps DeletedAt.
+type memo struct {
+	UID       string     `json:"uid,omitempty"`
+	DType     []string   `json:"dgraph.type,omitempty"`
+	Text      string     `json:"memo_text,omitempty"`
+	DeletedAt *time.Time `json:"memo_deleted_at,omitempty" dgraph:"softdelete"`
+}
+
+func TestClient_SoftDeleteRestorePurge(t *testing.T) {
+	ctx := context.Background()
+	c := typed.NewClient[memo](newConn(t))
+
+	m := &memo{Text: "call back"}
+	if err := c.Add(ctx, m); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	count := func(q *typed.Query[memo]) int {
+		t.Helper()
+		recs, err := q.Nodes()
+		if err != nil {
+			t.Fatalf("Nodes: %v", err)
+		}
+		return len(recs)
+	}
+
+	if err := c.Delete(ctx, m.UID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	got, err := c.Get(ctx, m.UID)
+	if err != nil || got.DeletedAt == nil {
+		t.Fatalf("Get after Delete = %+v, %v; want the memo marked deleted", got, err)
+	}
+	if n := count(c.Query(ctx)); n != 0 {
+		t.Fatalf("Query after Delete found %d memos, want 0", n)
+	}
+	if n := count(c.Query(ctx).IncludeDeleted()); n != 1 {
+		t.Fatalf("Query.IncludeDeleted found %d memos, want 1", n)
+	}
+
+	if err := c.Restore(ctx, m.UID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if n := count(c.Query(ctx)); n != 1 {
+		t.Fatalf("Query after Restore found %d memos, want 1", n)
+	}
+
+	if err := c.Purge(ctx, m.UID); err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if _, err := c.Get(ctx, m.UID); err == nil {
+		t.Fatal("Get after Purge returned no error; expected not-found")
+	}
+}
+
 func TestClient_IterPagesThroughAllRecords(t *testing.T) {
 	ctx := context.Background()
 	c := typed.NewClient[widget](newConn(t))
@@ -179,6 +236,35 @@ func TestClient_Upsert(t *testing.T) {
 	}
 }
 
+// screening carries a composite key: a film is shown once per room and slot.
+type screening struct {
+	UID   string   `json:"uid,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+	Room  string   `json:"room,omitempty" dgraph:"unique_group=room_slot"`
+	Slot  int      `json:"slot,omitempty" dgraph:"unique_group=room_slot"`
+	Film  string   `json:"film,omitempty"`
+}
+
+func TestClient_GetByUniqueGroup(t *testing.T) {
+	ctx := context.Background()
+	c := typed.NewClient[screening](newConn(t))
+
+	if err := c.Add(ctx, &screening{Room: "A", Slot: 1, Film: "Metropolis"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := c.Add(ctx, &screening{Room: "A", Slot: 1, Film: "Sunrise"}); !errors.Is(err, modusgraph.ErrUniqueConflict) {
+		t.Fatalf("Add duplicate: got %v, want ErrUniqueConflict", err)
+	}
+
+	got, err := c.GetByUniqueGroup(ctx, "room_slot", &screening{Room: "A", Slot: 1})
+	if err != nil {
+		t.Fatalf("GetByUniqueGroup: %v", err)
+	}
+	if got.Film != "Metropolis" {
+		t.Fatalf("GetByUniqueGroup Film = %q, want Metropolis", got.Film)
+	}
+}
+
 func TestClient_IterStopsOnConsumerBreak(t *testing.T) {
 	ctx := context.Background()
 	c := typed.NewClient[widget](newConn(t))
@@ -207,3 +293,36 @@ func TestClient_IterStopsOnConsumerBreak(t *testing.T) {
 		t.Fatalf("Iter yielded %d records after break at 10, want 10", seen)
 	}
 }
+
+// film carries a vector field for FindSimilar.
+type film struct {
+	UID       string            `json:"uid,omitempty"`
+	DType     []string          `json:"dgraph.type,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Embedding *dg.VectorFloat32 `json:"film_embedding,omitempty" dgraph:"index=hnsw(metric:cosine)"`
+}
+
+func TestClient_FindSimilar(t *testing.T) {
+	ctx := context.Background()
+	c := typed.NewClient[film](newConn(t))
+	for title, vec := range map[string][]float32{
+		"Alien":  {1, 0.1},
+		"Aliens": {1, 0.3},
+		"Heat":   {0, 1},
+	} {
+		if err := c.Add(ctx, &film{Title: title, Embedding: &dg.VectorFloat32{Values: vec}}); err != nil {
+			t.Fatalf("Add %s: %v", title, err)
+		}
+	}
+
+	films, err := c.FindSimilar(ctx, "film_embedding", []float32{1, 0.35}, 2)
+	if err != nil {
+		t.Fatalf("FindSimilar: %v", err)
+	}
+	if len(films) != 2 || films[0].Title != "Aliens" || films[1].Title != "Alien" {
+		t.Fatalf("FindSimilar = %+v, want Aliens then Alien", films)
+	}
+	if _, err := c.FindSimilar(ctx, "title", []float32{1, 0}, 1); err == nil {
+		t.Fatal("FindSimilar on a string predicate succeeded")
+	}
+}
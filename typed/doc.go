@@ -37,7 +37,7 @@
 //
 //	people := typed.NewClient[Person](client)
 //	person, err := people.Query(ctx).
-//		Filter("eq(name, $1)", "Alice").
+//		Filter("eq(name, ?)", "Alice").
 //		First()
 //	// person is *Person; nil when nothing matched.
 //
@@ -65,12 +65,22 @@
 // (the substrate behind generated By<Field> and Or combinators), and the search
 // subpackage merges ordered result sets by ID.
 //
+// The options of the queryopt package (Order, Page, Cursor, Language, Depth,
+// Fields) apply through With with the meaning they have on
+// modusgraph.TypedQuery. Generated page options alias those types rather
+// than defining their own, so one set of options composes across
+// handwritten and generated call sites.
+//
 // # Trust boundary
 //
-// dgman binds $N parameter values safely: a value passed as a Filter, OrGroup,
-// or WhereEdge parameter is escaped and cannot break out of its position, so
-// untrusted values belong in parameters — pass a user-supplied name as
-// eq(name, $1) with the name in $1, never formatted into the expression string.
+// Parameter values are bound safely: a value passed as a Filter, OrGroup, or
+// WhereEdge parameter is rendered as a DQL literal of its own type by
+// modusgraph.BindFilter and cannot break out of its position, so untrusted
+// values belong in parameters — pass a user-supplied name as eq(name, ?) (or
+// eq(name, $1)) with the name as the parameter, never formatted into the
+// expression string with fmt.Sprintf. A parameter of a type that has no DQL
+// literal, such as a struct or a nil pointer, fails the terminal with
+// modusgraph.ErrFilterParam instead of being formatted.
 //
 // The surrounding strings are not escaped. Filter expressions, RootFunc and UID
 // roots, WhereEdge predicates, order clauses, and MultiQuery block names are
@@ -0,0 +1,58 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/matthewmcneely/modusgraph"
+)
+
+// Decimal is one comparison term for a decimal-valued predicate: Op is one
+// of eq, lt, le, gt or ge, applied to Value.
+type Decimal struct {
+	Op    string
+	Value modusgraph.Decimal
+}
+
+// decimalOps maps the prefixes ParseDecimal accepts to comparison
+// functions, longest first.
+var decimalOps = []struct{ prefix, op string }{
+	{">=", "ge"}, {"<=", "le"}, {">", "gt"}, {"<", "lt"}, {"=", "eq"},
+}
+
+// ParseDecimal parses "value" or an operator followed by a value, such as
+// ">=10.50" or "<100", into a Decimal. A bare value compares for equality.
+func ParseDecimal(s string) (Decimal, error) {
+	op := "eq"
+	for _, o := range decimalOps {
+		if rest, ok := strings.CutPrefix(s, o.prefix); ok {
+			op, s = o.op, rest
+			break
+		}
+	}
+	v, err := modusgraph.ParseDecimal(strings.TrimSpace(s))
+	if err != nil {
+		return Decimal{}, err
+	}
+	return Decimal{Op: op, Value: v}, nil
+}
+
+// CompareDecimal adds one op(predicate, value) group per term, so the terms
+// AND together into a range. The value binds as a string literal, which
+// dgraph compares exactly against a bigfloat predicate. An empty terms
+// slice is a no-op; a term with an unknown Op panics.
+func (b *Builder) CompareDecimal(predicate string, terms []Decimal) {
+	for _, t := range terms {
+		switch t.Op {
+		case "eq", "lt", "le", "gt", "ge":
+		default:
+			panic(fmt.Sprintf("filter: unknown decimal comparison %q", t.Op))
+		}
+		b.groups = append(b.groups, fmt.Sprintf("%s(%s, %s)", t.Op, predicate, b.param(t.Value)))
+	}
+}
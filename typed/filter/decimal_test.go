@@ -0,0 +1,60 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/typed/filter"
+)
+
+func TestParseDecimal(t *testing.T) {
+	for in, want := range map[string]filter.Decimal{
+		"19.99":   {Op: "eq", Value: modusgraph.MustParseDecimal("19.99")},
+		"=19.990": {Op: "eq", Value: modusgraph.MustParseDecimal("19.99")},
+		">=10.50": {Op: "ge", Value: modusgraph.MustParseDecimal("10.5")},
+		"<= 100":  {Op: "le", Value: modusgraph.MustParseDecimal("100")},
+		">0":      {Op: "gt", Value: modusgraph.MustParseDecimal("0")},
+		"<-1e2":   {Op: "lt", Value: modusgraph.MustParseDecimal("-100")},
+	} {
+		got, err := filter.ParseDecimal(in)
+		if err != nil {
+			t.Fatalf("ParseDecimal(%q): %v", in, err)
+		}
+		if got != want {
+			t.Fatalf("ParseDecimal(%q) = %+v, want %+v", in, got, want)
+		}
+	}
+	for _, in := range []string{"", ">=", "=>5", "abc"} {
+		if _, err := filter.ParseDecimal(in); err == nil {
+			t.Fatalf("ParseDecimal(%q): expected an error", in)
+		}
+	}
+}
+
+func TestBuilder_CompareDecimalANDsTerms(t *testing.T) {
+	lo, _ := filter.ParseDecimal(">=10")
+	hi, _ := filter.ParseDecimal("<20.5")
+	b := &filter.Builder{}
+	b.RequiredEq("currency", "EUR")
+	b.CompareDecimal("total", []filter.Decimal{lo, hi})
+	expr, params := b.Build()
+	if want := "eq(currency, $1) AND ge(total, $2) AND lt(total, $3)"; expr != want {
+		t.Fatalf("expected %q, got %q", want, expr)
+	}
+	if len(params) != 3 || params[1] != lo.Value || params[2] != hi.Value {
+		t.Fatalf("expected decimal params, got %v", params)
+	}
+}
+
+func TestBuilder_CompareDecimalNoTermsIsNoop(t *testing.T) {
+	b := &filter.Builder{}
+	b.CompareDecimal("total", nil)
+	if expr, params := b.Build(); expr != "" || params != nil {
+		t.Fatalf("expected empty expr/params, got %q / %v", expr, params)
+	}
+}
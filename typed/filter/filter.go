@@ -7,13 +7,16 @@
 // for composing dgraph @filter clauses on generated <Entity>Query types.
 //
 // Generated By<Field> methods accept []UUID or []String and feed them into
-// Builder.EqGroupUUID / Builder.EqGroupString. Consumers can also build
+// Builder.EqGroupUUID / Builder.EqGroupString; decimal fields take []Decimal
+// comparisons for Builder.CompareDecimal. Consumers can also build
 // custom expressions directly with Builder for cases the generator does not
 // cover (multi-predicate joins, non-equality operators, domain defaults).
 package filter
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -121,3 +124,42 @@ func (b *Builder) Build() (string, []any) {
 	}
 	return strings.Join(b.groups, " AND "), b.params
 }
+
+// Or adds one group that ORs the combined expression of each sub-builder,
+// renumbering their parameters into b's. Sub-builders with no groups are
+// skipped; an Or of only empty sub-builders is a no-op. Since a sub-builder
+// may itself hold Or and Not groups, builders nest into boolean expressions
+// of any depth.
+func (b *Builder) Or(subs ...*Builder) {
+	parts := make([]string, 0, len(subs))
+	for _, s := range subs {
+		if expr := b.adopt(s); expr != "" {
+			parts = append(parts, "("+expr+")")
+		}
+	}
+	if len(parts) == 0 {
+		return
+	}
+	b.groups = append(b.groups, "("+strings.Join(parts, " OR ")+")")
+}
+
+// Not adds one group that negates the combined expression of sub. A sub with
+// no groups is a no-op.
+func (b *Builder) Not(sub *Builder) {
+	if expr := b.adopt(sub); expr != "" {
+		b.groups = append(b.groups, "NOT ("+expr+")")
+	}
+}
+
+// placeholder matches the $N parameters Builder writes.
+var placeholder = regexp.MustCompile(`\$(\d+)`)
+
+// adopt returns sub's combined expression with each of its parameters
+// appended to b's and its placeholders renumbered to match.
+func (b *Builder) adopt(sub *Builder) string {
+	expr, params := sub.Build()
+	return placeholder.ReplaceAllStringFunc(expr, func(m string) string {
+		i, _ := strconv.Atoi(m[1:])
+		return b.param(params[i-1])
+	})
+}
@@ -133,3 +133,42 @@ func TestBuilder_PositionalParamsAreSequential(t *testing.T) {
 		}
 	}
 }
+
+func TestBuilder_OrNot(t *testing.T) {
+	var inner filter.Builder
+	inner.RequiredEq("status", "archived")
+
+	var alt filter.Builder
+	alt.RequiredEq("owner", "bob")
+	alt.Not(&inner)
+
+	var b filter.Builder
+	b.RequiredEq("tenant", "acme")
+	b.Or(new(filter.Builder), &filter.Builder{})
+	b.Or(func() *filter.Builder {
+		var s filter.Builder
+		s.RequiredEq("owner", "alice")
+		return &s
+	}(), &alt)
+
+	expr, params := b.Build()
+	want := "eq(tenant, $1) AND ((eq(owner, $2)) OR (eq(owner, $3) AND NOT (eq(status, $4))))"
+	if expr != want {
+		t.Errorf("expr = %q, want %q", expr, want)
+	}
+	wantParams := []any{"acme", "alice", "bob", "archived"}
+	if len(params) != len(wantParams) {
+		t.Fatalf("params = %v, want %v", params, wantParams)
+	}
+	for i := range wantParams {
+		if params[i] != wantParams[i] {
+			t.Errorf("params[%d] = %v, want %v", i, params[i], wantParams[i])
+		}
+	}
+
+	var empty filter.Builder
+	empty.Not(&filter.Builder{})
+	if expr, _ := empty.Build(); expr != "" {
+		t.Errorf("Not of an empty builder = %q, want no group", expr)
+	}
+}
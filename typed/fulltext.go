@@ -0,0 +1,98 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package typed
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/matthewmcneely/modusgraph/typed/search"
+)
+
+// SearchOptions configures Search.
+type SearchOptions struct {
+	// Any matches records holding any of the text's terms (anyoftext)
+	// instead of all of them (alloftext).
+	Any bool
+	// Lang is the language whose stop words and stemming apply when ranking
+	// and highlighting, English when empty.
+	Lang string
+	// Limit caps the number of hits returned, after ranking; 0 returns all.
+	Limit int
+	// MinScore drops hits scoring below it.
+	MinScore float64
+	// Unranked keeps hits in the order Dgraph returns them instead of
+	// ranking them by Score.
+	Unranked bool
+	// Pre and Post enclose the matched words in Highlight; "<em>" and
+	// "</em>" when both are empty.
+	Pre, Post string
+}
+
+// SearchHit is one record found by Search.
+type SearchHit[T any] struct {
+	Record T
+	// Score rates the match between 0 and 1, as search.Score does.
+	Score float64
+	// Highlight is the searched field's value with its matched words
+	// enclosed in the options' Pre and Post.
+	Highlight string
+}
+
+// Search returns the records whose field, a string field with a fulltext
+// index named by Go name, JSON name or predicate, matches text under
+// alloftext (or anyoftext with opts.Any), ranked best first. Dgraph does not
+// rank fulltext matches, so hits are scored and highlighted client-side with
+// the index's own tokenizer; see the search package.
+func (c *Client[T]) Search(ctx context.Context, field, text string,
+	opts SearchOptions) (hits []SearchHit[T], err error) {
+	ctx, span := currentTracer().StartSpan(ctx, "search", entityName[T]())
+	defer func() { span.End(err) }()
+	sf, ok := importField(reflect.TypeFor[T](), field)
+	if !ok || sf.Type.Kind() != reflect.String {
+		return nil, fmt.Errorf("typed: Search: %s has no string field %q", entityName[T](), field)
+	}
+	pred := arrowPredicate(sf)
+
+	q := c.Query(ctx)
+	if opts.Any {
+		q.WhereAnyOfText(pred, text)
+	} else {
+		q.WhereAllOfText(pred, text)
+	}
+	recs, err := q.Nodes()
+	if err != nil {
+		return nil, err
+	}
+
+	pre, post := opts.Pre, opts.Post
+	if pre == "" && post == "" {
+		pre, post = "<em>", "</em>"
+	}
+	hits = make([]SearchHit[T], 0, len(recs))
+	for _, rec := range recs {
+		value := reflect.ValueOf(rec).FieldByIndex(sf.Index).String()
+		score := search.Score(value, text, opts.Lang)
+		if score < opts.MinScore {
+			continue
+		}
+		hits = append(hits, SearchHit[T]{
+			Record:    rec,
+			Score:     score,
+			Highlight: search.Highlight(value, text, opts.Lang, pre, post),
+		})
+	}
+	if !opts.Unranked {
+		slices.SortStableFunc(hits, func(a, b SearchHit[T]) int { return cmp.Compare(b.Score, a.Score) })
+	}
+	if opts.Limit > 0 && len(hits) > opts.Limit {
+		hits = hits[:opts.Limit]
+	}
+	return hits, nil
+}
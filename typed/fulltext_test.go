@@ -0,0 +1,74 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package typed_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/matthewmcneely/modusgraph/typed"
+)
+
+// article has a fulltext-indexed field for Search.
+type article struct {
+	UID   string   `json:"uid,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+	Title string   `json:"title,omitempty"`
+	Body  string   `json:"body,omitempty" dgraph:"index=fulltext"`
+}
+
+func TestClient_Search(t *testing.T) {
+	ctx := context.Background()
+	c := typed.NewClient[article](newConn(t))
+	for title, body := range map[string]string{
+		"one":   "A graph stores nodes.",
+		"both":  "Graphs of graphs store nodes and edges.",
+		"other": "Tables store rows.",
+	} {
+		if err := c.Add(ctx, &article{Title: title, Body: body}); err != nil {
+			t.Fatalf("Add %s: %v", title, err)
+		}
+	}
+
+	// alloftext needs both terms; the article repeating one ranks first.
+	hits, err := c.Search(ctx, "Body", "graph nodes", typed.SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(hits) != 2 || hits[0].Record.Title != "both" || hits[1].Record.Title != "one" {
+		t.Fatalf("Search = %+v, want both then one", hits)
+	}
+	if want := "<em>Graphs</em> of <em>graphs</em> store <em>nodes</em> and edges."; hits[0].Highlight != want {
+		t.Fatalf("Highlight = %q, want %q", hits[0].Highlight, want)
+	}
+	if hits[0].Score <= hits[1].Score {
+		t.Fatalf("scores %v, %v are not ranked", hits[0].Score, hits[1].Score)
+	}
+
+	// anyoftext matches either term; MinScore and Limit trim the ranking.
+	hits, err = c.Search(ctx, "body", "graph rows", typed.SearchOptions{Any: true, Pre: "*", Post: "*"})
+	if err != nil {
+		t.Fatalf("Search any: %v", err)
+	}
+	if len(hits) != 3 || hits[0].Record.Title != "both" {
+		t.Fatalf("Search any = %+v, want 3 hits led by both", hits)
+	}
+	for _, h := range hits {
+		if h.Record.Title == "other" && h.Highlight != "Tables store *rows*." {
+			t.Fatalf("Highlight = %q, want Tables store *rows*.", h.Highlight)
+		}
+	}
+	hits, err = c.Search(ctx, "body", "graph rows", typed.SearchOptions{Any: true, MinScore: 0.3, Limit: 1})
+	if err != nil || len(hits) != 1 || hits[0].Record.Title != "both" {
+		t.Fatalf("Search with MinScore and Limit = %+v, %v; want both", hits, err)
+	}
+
+	if _, err := c.Search(ctx, "missing", "graph", typed.SearchOptions{}); err == nil ||
+		!strings.Contains(err.Error(), `no string field "missing"`) {
+		t.Fatalf("Search on a missing field: err = %v", err)
+	}
+}
@@ -0,0 +1,414 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package typed
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ImportFormat names an input format of Import.
+type ImportFormat string
+
+const (
+	// CSV is comma-separated values with a header row naming the columns.
+	CSV ImportFormat = "csv"
+	// JSON is an array of objects in T's JSON form.
+	JSON ImportFormat = "json"
+)
+
+// defaultImportBatch is the batch size Import uses when given none.
+const defaultImportBatch = 100
+
+// ImportOptions configures Import.
+type ImportOptions struct {
+	// Format of the input. When empty, input starting with [ is read as JSON
+	// and anything else as CSV.
+	Format ImportFormat
+	// Columns maps CSV headers to the fields they fill, named by Go field
+	// name, JSON name or predicate. Headers it leaves out are matched to
+	// fields by those names, ignoring case. Import fails before writing
+	// anything when a header matches no field.
+	Columns map[string]string
+	// Key is the predicate rows are upserted on. When empty, the first field
+	// tagged dgraph:"unique" or dgraph:"upsert" is used.
+	Key string
+	// BatchSize is how many rows are upserted per transaction; 100 when 0.
+	BatchSize int
+}
+
+// ImportSummary counts the rows Import wrote and the rows it could not.
+type ImportSummary struct {
+	Inserted int
+	Updated  int
+	Failed   int
+	// Errors holds the error of each failed row, in input order.
+	Errors []ImportError
+}
+
+// String summarizes the counts, as in "3 inserted, 1 updated, 0 failed".
+func (s ImportSummary) String() string {
+	return fmt.Sprintf("%d inserted, %d updated, %d failed", s.Inserted, s.Updated, s.Failed)
+}
+
+// ImportError is the error of one row Import could not write. Row is the
+// row's 1-based position in the input, not counting the CSV header.
+type ImportError struct {
+	Row int
+	Err error
+}
+
+func (e ImportError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e ImportError) Unwrap() error { return e.Err }
+
+// Import reads rows of T from r, as CSV or a JSON array, and upserts them on
+// their key predicate in batches, so rows whose key is already stored update
+// that T and the rest are inserted. A row that cannot be decoded, has no
+// key, or fails to write is counted as failed and the import goes on: a
+// batch that fails is retried a row at a time to find the rows at fault.
+//
+// CSV cells are converted to the type of the field their column fills:
+// strings, numbers, booleans, times in RFC 3339 or 2006-01-02 form, and
+// pointers to them. Cells for other fields are read as JSON. Empty cells
+// leave their field unset.
+//
+// Import returns an error only for input it cannot read at all, such as
+// malformed CSV or JSON, along with the summary of the rows imported before
+// it.
+func (c *Client[T]) Import(ctx context.Context, r io.Reader, opts ImportOptions) (sum ImportSummary, err error) {
+	ctx, span := currentTracer().StartSpan(ctx, "import", entityName[T]())
+	defer func() { span.End(err) }()
+	t := reflect.TypeFor[T]()
+	keyField, err := importKey(t, opts.Key)
+	if err != nil {
+		return sum, err
+	}
+	size := opts.BatchSize
+	if size <= 0 {
+		size = defaultImportBatch
+	}
+	br := bufio.NewReader(r)
+	format := opts.Format
+	if format == "" {
+		format = CSV
+		for {
+			b, err := br.ReadByte()
+			if err != nil {
+				break
+			}
+			if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+				if b == '[' {
+					format = JSON
+				}
+				_ = br.UnreadByte()
+				break
+			}
+		}
+	}
+
+	im := &importer[T]{client: c, ctx: ctx, key: keyField, sum: &sum, size: size}
+	switch format {
+	case CSV:
+		err = im.readCSV(br, opts.Columns)
+	case JSON:
+		err = im.readJSON(br)
+	default:
+		err = fmt.Errorf("typed: Import: unknown format %q", format)
+	}
+	if err != nil {
+		return sum, err
+	}
+	return sum, im.flush()
+}
+
+// importKey returns the field of t holding the key predicate, or the first
+// field tagged unique or upsert when key is "".
+func importKey(t reflect.Type, key string) (reflect.StructField, error) {
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		if key != "" {
+			if arrowPredicate(sf) == key {
+				return sf, nil
+			}
+			continue
+		}
+		for part := range strings.FieldsSeq(sf.Tag.Get("dgraph")) {
+			if part == "unique" || part == "upsert" {
+				return sf, nil
+			}
+		}
+	}
+	if key != "" {
+		return reflect.StructField{}, fmt.Errorf("typed: Import: %s has no field for key %q", t.Name(), key)
+	}
+	return reflect.StructField{}, fmt.Errorf("typed: Import: %s has no field tagged unique or upsert; "+
+		"set ImportOptions.Key", t.Name())
+}
+
+// importRow is one decoded row and its position in the input.
+type importRow[T any] struct {
+	n   int
+	rec T
+}
+
+// importer batches decoded rows and upserts them.
+type importer[T any] struct {
+	client *Client[T]
+	ctx    context.Context
+	key    reflect.StructField
+	sum    *ImportSummary
+	size   int
+	batch  []importRow[T]
+	keys   map[string]bool
+}
+
+func (im *importer[T]) fail(n int, err error) {
+	im.sum.Failed++
+	im.sum.Errors = append(im.sum.Errors, ImportError{Row: n, Err: err})
+}
+
+// add queues rec, flushing the batch when it is full or already holds a row
+// with the same key, which one transaction cannot upsert twice.
+func (im *importer[T]) add(n int, rec T) error {
+	key := reflect.ValueOf(&rec).Elem().FieldByIndex(im.key.Index)
+	if key.IsZero() {
+		im.fail(n, fmt.Errorf("no %s", arrowPredicate(im.key)))
+		return nil
+	}
+	k := fmt.Sprint(key.Interface())
+	if im.keys[k] {
+		if err := im.flush(); err != nil {
+			return err
+		}
+	}
+	if im.keys == nil {
+		im.keys = map[string]bool{}
+	}
+	im.keys[k] = true
+	im.batch = append(im.batch, importRow[T]{n: n, rec: rec})
+	if len(im.batch) >= im.size {
+		return im.flush()
+	}
+	return nil
+}
+
+// flush upserts the queued rows in one transaction, or one at a time when
+// that fails. It returns an error only when ctx is done.
+func (im *importer[T]) flush() error {
+	if len(im.batch) == 0 {
+		return nil
+	}
+	defer func() {
+		im.batch = im.batch[:0]
+		clear(im.keys)
+	}()
+	keys := make([]any, len(im.batch))
+	for i, row := range im.batch {
+		keys[i] = reflect.ValueOf(&row.rec).Elem().FieldByIndex(im.key.Index).Interface()
+	}
+	pred := arrowPredicate(im.key)
+	stored, err := im.client.Query(im.ctx).Filter("eq(<"+pred+">, $1)", keys).Nodes()
+	if err != nil {
+		return err
+	}
+	exists := make(map[string]bool, len(stored))
+	for i := range stored {
+		exists[fmt.Sprint(reflect.ValueOf(&stored[i]).Elem().FieldByIndex(im.key.Index).Interface())] = true
+	}
+	count := func(row importRow[T]) {
+		if exists[fmt.Sprint(reflect.ValueOf(&row.rec).Elem().FieldByIndex(im.key.Index).Interface())] {
+			im.sum.Updated++
+		} else {
+			im.sum.Inserted++
+		}
+	}
+
+	recs := make([]T, len(im.batch))
+	for i, row := range im.batch {
+		recs[i] = row.rec
+	}
+	if err := im.client.conn.Upsert(im.ctx, &recs, pred); err == nil {
+		for _, row := range im.batch {
+			count(row)
+		}
+		return nil
+	}
+	for _, row := range im.batch {
+		if err := im.ctx.Err(); err != nil {
+			return err
+		}
+		if err := im.client.conn.Upsert(im.ctx, &row.rec, pred); err != nil {
+			im.fail(row.n, err)
+			continue
+		}
+		count(row)
+	}
+	return nil
+}
+
+// readJSON decodes the rows of a JSON array, counting a row whose values do
+// not fit T as failed.
+func (im *importer[T]) readJSON(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return fmt.Errorf("typed: Import: JSON input must be an array of objects")
+	}
+	for n := 1; dec.More(); n++ {
+		var rec T
+		if err := dec.Decode(&rec); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				return fmt.Errorf("typed: Import: row %d: %w", n, err)
+			}
+			im.fail(n, err)
+			continue
+		}
+		if err := im.add(n, rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readCSV decodes the rows of CSV with a header, filling the field each
+// column maps to.
+func (im *importer[T]) readCSV(r io.Reader, columns map[string]string) error {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("typed: Import: reading CSV header: %w", err)
+	}
+	t := reflect.TypeFor[T]()
+	fields := make([]reflect.StructField, len(header))
+	for i, h := range header {
+		name := strings.TrimSpace(h)
+		if mapped, ok := columns[name]; ok {
+			name = mapped
+		}
+		sf, ok := importField(t, name)
+		if !ok {
+			return fmt.Errorf("typed: Import: CSV column %q matches no field of %s", h, t.Name())
+		}
+		fields[i] = sf
+	}
+	for n := 1; ; n++ {
+		cells, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) && errors.Is(err, csv.ErrFieldCount) {
+				im.fail(n, err)
+				continue
+			}
+			return fmt.Errorf("typed: Import: %w", err)
+		}
+		var rec T
+		v := reflect.ValueOf(&rec).Elem()
+		var cellErr error
+		for i, cell := range cells {
+			if err := setCell(v.FieldByIndex(fields[i].Index), cell); err != nil {
+				cellErr = fmt.Errorf("column %q: %w", header[i], err)
+				break
+			}
+		}
+		if cellErr != nil {
+			im.fail(n, cellErr)
+			continue
+		}
+		if err := im.add(n, rec); err != nil {
+			return err
+		}
+	}
+}
+
+// importField finds the field of t named name by Go name, JSON name or
+// predicate, ignoring case.
+func importField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		jsonName := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if strings.EqualFold(sf.Name, name) || strings.EqualFold(jsonName, name) ||
+			strings.EqualFold(arrowPredicate(sf), name) {
+			return sf, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// setCell converts a CSV cell to the type of field and stores it there.
+func setCell(field reflect.Value, cell string) error {
+	if cell == "" {
+		return nil
+	}
+	if field.Kind() == reflect.Pointer {
+		elem := reflect.New(field.Type().Elem())
+		if err := setCell(elem.Elem(), cell); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+	if field.Type() == timeType {
+		for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+			if ts, err := time.Parse(layout, cell); err == nil {
+				field.Set(reflect.ValueOf(ts))
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not an RFC 3339 time or date", cell)
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(cell)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(cell, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(cell, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(cell, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return json.Unmarshal([]byte(cell), field.Addr().Interface())
+	}
+	return nil
+}
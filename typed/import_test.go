@@ -0,0 +1,101 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package typed_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/matthewmcneely/modusgraph/typed"
+)
+
+func TestClient_ImportCSV(t *testing.T) {
+	ctx := context.Background()
+	c := typed.NewClient[gadget](newConn(t))
+	if err := c.Add(ctx, &gadget{Label: "sprocket", Stock: 1}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	// "Item" is mapped to Label; "stock" matches its field by JSON name. The
+	// third row has no key, the fourth a stock that is not a number, and
+	// the sixth repeats a key of the same batch.
+	csv := "Item,stock\n" +
+		"sprocket,10\n" +
+		"cog,5\n" +
+		",7\n" +
+		"gear,lots\n" +
+		"flange,2\n" +
+		"cog,6\n"
+	sum, err := c.Import(ctx, strings.NewReader(csv), typed.ImportOptions{
+		Columns:   map[string]string{"Item": "Label"},
+		BatchSize: 10,
+	})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if sum.Inserted != 2 || sum.Updated != 2 || sum.Failed != 2 {
+		t.Fatalf("summary = %s, want 2 inserted, 2 updated, 2 failed", sum)
+	}
+	if len(sum.Errors) != 2 || sum.Errors[0].Row != 3 || sum.Errors[1].Row != 4 {
+		t.Fatalf("errors = %v, want rows 3 and 4", sum.Errors)
+	}
+
+	stock := map[string]int{}
+	nodes, err := c.Query(ctx).Nodes()
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	for _, g := range nodes {
+		stock[g.Label] = g.Stock
+	}
+	want := map[string]int{"sprocket": 10, "cog": 6, "flange": 2}
+	if len(stock) != len(want) {
+		t.Fatalf("stored %v, want %v", stock, want)
+	}
+	for label, n := range want {
+		if stock[label] != n {
+			t.Fatalf("stored %v, want %v", stock, want)
+		}
+	}
+
+	if _, err := c.Import(ctx, strings.NewReader("Item\nx\n"), typed.ImportOptions{}); err == nil ||
+		!strings.Contains(err.Error(), `"Item" matches no field`) {
+		t.Fatalf("Import with an unmapped column: err = %v", err)
+	}
+}
+
+func TestClient_ImportJSON(t *testing.T) {
+	ctx := context.Background()
+	conn := newConn(t)
+	c := typed.NewClient[gadget](conn)
+	if err := c.Add(ctx, &gadget{Label: "cog", Stock: 1}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	// The format is detected from the leading [. The batch of one row at a
+	// time still counts the stored key as an update.
+	input := ` [{"label": "cog", "stock": 3}, {"label": "gear", "stock": "x"}, {"label": "nut", "stock": 4}]`
+	sum, err := c.Import(ctx, strings.NewReader(input), typed.ImportOptions{Key: "label", BatchSize: 1})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if sum.String() != "1 inserted, 1 updated, 1 failed" || sum.Errors[0].Row != 2 {
+		t.Fatalf("summary = %s %v, want 1 inserted, 1 updated, 1 failed at row 2", sum, sum.Errors)
+	}
+	cog, err := c.Query(ctx).Filter("eq(label, $1)", "cog").First()
+	if err != nil || cog == nil || cog.Stock != 3 {
+		t.Fatalf("cog = %+v, %v; want stock 3", cog, err)
+	}
+
+	if _, err := c.Import(ctx, strings.NewReader(`[{"label": `), typed.ImportOptions{Format: typed.JSON}); err == nil {
+		t.Fatal("Import of truncated JSON succeeded")
+	}
+	if _, err := typed.NewClient[widget](conn).Import(ctx, strings.NewReader("[]"), typed.ImportOptions{}); err == nil ||
+		!strings.Contains(err.Error(), "no field tagged unique or upsert") {
+		t.Fatalf("Import without a key field: err = %v", err)
+	}
+}
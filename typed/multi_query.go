@@ -88,6 +88,9 @@ func (mq *MultiQuery[T]) Execute(ctx context.Context) (map[string][]T, error) {
 	rawBlocks := make([]*dg.Query, 0, len(mq.names))
 	for _, name := range mq.names {
 		block := mq.blocks[name]
+		if block.err != nil {
+			return nil, fmt.Errorf("multi_query: block %q: %w", name, block.err)
+		}
 		if len(block.edges) != 0 {
 			return nil, fmt.Errorf(
 				"multi_query: block %q carries WhereEdge constraints; "+
@@ -0,0 +1,82 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package typed
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/matthewmcneely/modusgraph"
+)
+
+// Page is one page of a cursor-paged query, returned by Query.Page.
+type Page[T any] struct {
+	// Nodes holds the page's records, in UID order.
+	Nodes []T
+	// NextCursor is the UID of the last record in Nodes, to pass to the next
+	// Page call. It is "" when Nodes is empty.
+	NextCursor string
+	// HasMore reports whether records remain after this page.
+	HasMore bool
+}
+
+// Page executes the query and returns up to size records with UIDs greater
+// than cursor, or from the start when cursor is "". A size of 0 or less uses
+// the default page size of IterNodes.
+//
+// Unlike Offset, a cursor names the last record already seen rather than
+// how many came before it, so nodes added or deleted between calls cannot
+// make the next page skip or repeat records. The cursor follows UID order,
+// so Page fails on a query ordered by OrderAsc or OrderDesc, and on a cursor
+// that is not a UID. Page is a terminal and overwrites any Limit or After
+// set on the query.
+func (qb *Query[T]) Page(cursor string, size int) (page Page[T], err error) {
+	if err := qb.usable(); err != nil {
+		return Page[T]{}, err
+	}
+	uidField, ok := reflect.TypeFor[T]().FieldByName("UID")
+	if !ok || uidField.Type.Kind() != reflect.String {
+		return Page[T]{}, fmt.Errorf("typed: Page needs a string UID field on %s", entityName[T]())
+	}
+	if qb.ordered {
+		return Page[T]{}, fmt.Errorf("typed: Page cannot be combined with OrderAsc or OrderDesc")
+	}
+	if cursor != "" {
+		uid, err := modusgraph.ParseUID(cursor)
+		if err != nil {
+			return Page[T]{}, fmt.Errorf("typed: Page cursor: %w", err)
+		}
+		cursor = string(uid)
+	}
+	_, span := currentTracer().StartSpan(qb.ctx, "query", entityName[T]())
+	defer func() { span.End(err) }()
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	if cursor != "" {
+		qb.q.After(cursor)
+	}
+	// One record more than the page tells whether another page follows.
+	qb.q.First(size + 1)
+	var out []T
+	if len(qb.edges) > 0 {
+		out, _, err = qb.runEdge(false)
+	} else {
+		err = qb.q.Nodes(&out)
+	}
+	if err != nil {
+		return Page[T]{}, err
+	}
+	if len(out) > size {
+		page.HasMore = true
+		out = out[:size]
+	}
+	page.Nodes = out
+	if len(out) > 0 {
+		page.NextCursor = reflect.ValueOf(&out[len(out)-1]).Elem().FieldByIndex(uidField.Index).String()
+	}
+	return page, nil
+}
@@ -0,0 +1,129 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package typed_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/typed"
+)
+
+func TestQuery_PageWalksAllRecords(t *testing.T) {
+	ctx := context.Background()
+	c := typed.NewClient[widget](newConn(t))
+
+	const n = 7
+	for i := range n {
+		if err := c.Add(ctx, &widget{Name: "w", Qty: i + 1}); err != nil {
+			t.Fatalf("Add %d: %v", i, err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	cursor, pages := "", 0
+	for {
+		page, err := c.Query(ctx).Page(cursor, 3)
+		if err != nil {
+			t.Fatalf("Page(%q): %v", cursor, err)
+		}
+		pages++
+		for _, w := range page.Nodes {
+			if seen[w.UID] {
+				t.Fatalf("UID %s returned twice", w.UID)
+			}
+			if cursor != "" && w.UID <= cursor {
+				t.Fatalf("Page(%q) returned UID %s, not past the cursor", cursor, w.UID)
+			}
+			seen[w.UID] = true
+		}
+		if !page.HasMore {
+			break
+		}
+		if page.NextCursor != page.Nodes[len(page.Nodes)-1].UID {
+			t.Fatalf("NextCursor = %q, want the last UID %q", page.NextCursor, page.Nodes[len(page.Nodes)-1].UID)
+		}
+		cursor = page.NextCursor
+	}
+	if len(seen) != n || pages != 3 {
+		t.Fatalf("paged %d records in %d pages, want %d in 3", len(seen), pages, n)
+	}
+}
+
+func TestQuery_PageStableWhileMutating(t *testing.T) {
+	ctx := context.Background()
+	c := typed.NewClient[widget](newConn(t))
+
+	for i := range 6 {
+		if err := c.Add(ctx, &widget{Name: "w", Qty: i + 1}); err != nil {
+			t.Fatalf("Add %d: %v", i, err)
+		}
+	}
+
+	first, err := c.Query(ctx).Page("", 3)
+	if err != nil {
+		t.Fatalf("first Page: %v", err)
+	}
+	if len(first.Nodes) != 3 || !first.HasMore {
+		t.Fatalf("first page has %d records, HasMore=%v; want 3 and true", len(first.Nodes), first.HasMore)
+	}
+
+	// Deleting a record already seen would shift an offset by one; the
+	// cursor still starts right after the last record returned.
+	if err := c.Delete(ctx, first.Nodes[0].UID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	second, err := c.Query(ctx).Page(first.NextCursor, 3)
+	if err != nil {
+		t.Fatalf("second Page: %v", err)
+	}
+	if len(second.Nodes) != 3 {
+		t.Fatalf("second page has %d records, want 3", len(second.Nodes))
+	}
+	for _, w := range second.Nodes {
+		for _, s := range first.Nodes {
+			if w.UID == s.UID {
+				t.Fatalf("UID %s returned on both pages", w.UID)
+			}
+		}
+	}
+	if second.HasMore {
+		t.Fatal("second page reports more records, want none")
+	}
+
+	empty, err := c.Query(ctx).Page(second.NextCursor, 3)
+	if err != nil {
+		t.Fatalf("empty Page: %v", err)
+	}
+	if len(empty.Nodes) != 0 || empty.HasMore || empty.NextCursor != "" {
+		t.Fatalf("page past the end = %+v, want an empty page", empty)
+	}
+}
+
+func TestQuery_PageDetached(t *testing.T) {
+	if _, err := typed.NewDetachedQuery[widget]().Page("", 10); !errors.Is(err, typed.ErrDetachedQuery) {
+		t.Errorf("Page() error = %v, want ErrDetachedQuery", err)
+	}
+}
+
+func TestQuery_PageRejectsBadInput(t *testing.T) {
+	ctx := context.Background()
+	c := typed.NewClient[widget](newConn(t))
+
+	for _, cursor := range []string{"0x1) { uid } q2(func: has(name)", "12", "0xZZ"} {
+		if _, err := c.Query(ctx).Page(cursor, 3); !errors.Is(err, modusgraph.ErrInvalidUID) {
+			t.Errorf("Page(%q) error = %v, want ErrInvalidUID", cursor, err)
+		}
+	}
+	if _, err := c.Query(ctx).OrderAsc("qty").Page("", 3); err == nil {
+		t.Error("Page on an ordered query succeeded, want an error")
+	}
+	if _, err := c.Query(ctx).OrderDesc("qty").Page("", 3); err == nil {
+		t.Error("Page on a descending query succeeded, want an error")
+	}
+}
@@ -17,10 +17,11 @@ import (
 
 	dg "github.com/dolan-in/dgman/v2"
 	"github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/queryopt"
 )
 
 // ErrDetachedQuery is returned by a terminal (Nodes, First, NodesAndCount,
-// IterNodes) called on a detached query — one built with NewDetachedQuery,
+// IterNodes, Arrow) called on a detached query — one built with NewDetachedQuery,
 // which has no connection and no underlying dgman query. A detached query
 // exists only to capture a filter sub-scope for OrGroup or WhereEdge; it has no
 // execution path. Terminals return this error (testable with errors.Is) rather
@@ -39,7 +40,7 @@ const (
 // Query is a fluent, type-safe query builder over records of type T. Builder
 // methods return *Query[T] for chaining, except As, Var, and GroupBy, which
 // change the result shape and transition to *RawQuery; terminal methods
-// (Nodes, First, IterNodes) execute the query and decode typed results.
+// (Nodes, First, IterNodes, Page) execute the query and decode typed results.
 //
 // A Query is single-use. Builder methods mutate the underlying query in place
 // and return the same *Query, so a Query value should be built as one chain
@@ -48,8 +49,9 @@ const (
 // keeps mutating — the same underlying query.
 //
 // Repeated builder calls do not all behave the same way. Limit, Offset, After,
-// Cascade, Name, RootFunc, and Vars overwrite: the last call wins. Filter,
-// OrderAsc, OrderDesc, and WhereEdge accumulate: each call adds to the query.
+// PageSize, Cascade, Name, RootFunc, and Vars overwrite: the last call wins. Filter,
+// OrGroup, NotGroup, OrderAsc, OrderDesc, and WhereEdge accumulate: each call
+// adds to the query.
 // Accumulated Filter fragments AND together (see CombinedFilter, OrGroup).
 //
 // Limit and Offset additionally record the bounds that IterNodes pages
@@ -60,8 +62,12 @@ type Query[T any] struct {
 	ctx     context.Context   // carried for the WhereEdge pre-pass query
 	limit   int               // caller-set row cap; 0 = unbounded
 	offset  int               // caller-set starting offset; 0 = none
+	page    int               // IterNodes page size set by PageSize; 0 = defaultPageSize
 	edges   []edgeFilter      // accumulated WhereEdge constraints; empty = none
 	filters []filterFrag      // accumulated @filter fragments, ANDed; empty = none
+	err     error             // first filter parameter that failed to bind; returned by terminals
+	deleted bool              // filters[0] leaves out soft-deleted records; cleared by IncludeDeleted
+	ordered bool              // OrderAsc or OrderDesc was called; Page refuses to run
 
 	// customRootExpr is the caller's root narrowing (set by UID or RootFunc), or
 	// "" if none. The WhereEdge var block roots at it, so the matched UIDs are the
@@ -100,8 +106,10 @@ func NewDetachedQuery[T any]() *Query[T] {
 	return &Query[T]{}
 }
 
-// Filter adds a dgraph @filter expression. params bind to placeholders.
-// Repeated calls accumulate: every fragment ANDs together.
+// Filter adds a dgraph @filter expression. params bind to its ? or $N
+// placeholders, each rendered as a DQL literal of its type (see
+// modusgraph.BindFilter); a parameter that cannot be bound fails the
+// terminal. Repeated calls accumulate: every fragment ANDs together.
 func (qb *Query[T]) Filter(filter string, params ...any) *Query[T] {
 	qb.addFilter(filter, params)
 	return qb
@@ -118,10 +126,17 @@ func (qb *Query[T]) addFilter(expr string, params []any) {
 	if expr == "" {
 		return
 	}
+	// Fragments keep their ? placeholders rewritten to $N and their params
+	// as given; the params are rendered as literals only on the way to dgman.
+	expr, _, err := modusgraph.BindFilter(expr, params...)
+	if err != nil {
+		qb.fail(err)
+		return
+	}
 	qb.filters = append(qb.filters, filterFrag{expr: expr, params: params})
 	if qb.q != nil {
 		combined, cp := combineAnd(qb.filters)
-		qb.q.Filter(combined, cp...)
+		qb.q.Filter(combined, literals(cp)...)
 	}
 }
 
@@ -165,6 +180,9 @@ func (qb *Query[T]) OrGroup(subs ...*Query[T]) *Query[T] {
 	parts := make([]string, 0, len(subs))
 	var params []any
 	for _, s := range subs {
+		if s.err != nil {
+			qb.fail(s.err)
+		}
 		e, p := s.CombinedFilter()
 		if e == "" {
 			continue
@@ -179,14 +197,58 @@ func (qb *Query[T]) OrGroup(subs ...*Query[T]) *Query[T] {
 	return qb
 }
 
+// NotGroup adds one @filter group that negates the combined filter of sub, a
+// detached Query[T] as for OrGroup, and ANDs it with the receiver's other
+// filters. A sub with an empty filter is a no-op rather than matching
+// nothing. Since a sub's own OrGroup and NotGroup calls are part of its
+// combined filter, detached queries nest into boolean expressions of any
+// depth:
+//
+//	// NOT (name == "a" OR (qty >= 5 AND NOT name == "b"))
+//	q.NotGroup(NewDetachedQuery[T]().OrGroup(
+//		NewDetachedQuery[T]().Filter(`eq(name, "a")`),
+//		NewDetachedQuery[T]().Filter(`ge(qty, 5)`).NotGroup(
+//			NewDetachedQuery[T]().Filter(`eq(name, "b")`)),
+//	))
+//
+// It is the substrate behind the generated <Entity>Query.Not combinator.
+func (qb *Query[T]) NotGroup(sub *Query[T]) *Query[T] {
+	if sub.err != nil {
+		qb.fail(sub.err)
+	}
+	e, p := sub.CombinedFilter()
+	if e == "" {
+		return qb
+	}
+	qb.addFilter("NOT ("+e+")", p)
+	return qb
+}
+
+// IncludeDeleted makes the query also return the records Delete marked
+// deleted, when T has a dgraph:"softdelete" field (see
+// modusgraph.SoftDeletePredicate), which it otherwise leaves out.
+func (qb *Query[T]) IncludeDeleted() *Query[T] {
+	if qb.deleted {
+		qb.deleted = false
+		qb.filters = qb.filters[1:]
+		if qb.q != nil {
+			combined, cp := combineAnd(qb.filters)
+			qb.q.Filter(combined, literals(cp)...)
+		}
+	}
+	return qb
+}
+
 // OrderAsc orders results ascending by clause.
 func (qb *Query[T]) OrderAsc(clause string) *Query[T] {
+	qb.ordered = true
 	qb.q.OrderAsc(clause)
 	return qb
 }
 
 // OrderDesc orders results descending by clause.
 func (qb *Query[T]) OrderDesc(clause string) *Query[T] {
+	qb.ordered = true
 	qb.q.OrderDesc(clause)
 	return qb
 }
@@ -206,6 +268,14 @@ func (qb *Query[T]) Offset(n int) *Query[T] {
 	return qb
 }
 
+// PageSize sets how many records each page IterNodes fetches holds, in place
+// of the default 50. Larger pages mean fewer round-trips and more records in
+// memory at once. A size of 0 or less restores the default.
+func (qb *Query[T]) PageSize(n int) *Query[T] {
+	qb.page = n
+	return qb
+}
+
 // After returns results with UID greater than uid (cursor pagination).
 func (qb *Query[T]) After(uid string) *Query[T] {
 	qb.q.After(uid)
@@ -263,10 +333,36 @@ func (qb *Query[T]) Vars(funcDef string, vars map[string]string) *Query[T] {
 // every edge constraint). It is the substrate behind the generated
 // <Entity>Query.Where<Edge> methods.
 func (qb *Query[T]) WhereEdge(predicate, filter string, params ...any) *Query[T] {
+	filter, _, err := modusgraph.BindFilter(filter, params...)
+	if err != nil {
+		qb.fail(err)
+		return qb
+	}
 	qb.edges = append(qb.edges, edgeFilter{predicate: predicate, filter: filter, params: params})
 	return qb
 }
 
+// fail records the first filter parameter error for the terminal to return.
+func (qb *Query[T]) fail(err error) {
+	if qb.err == nil {
+		qb.err = err
+	}
+}
+
+// literals renders params already checked by BindFilter as DQL literals.
+func literals(params []any) []any {
+	_, bound, _ := modusgraph.BindFilter("", params...)
+	return bound
+}
+
+// usable returns the error a terminal reports instead of executing.
+func (qb *Query[T]) usable() error {
+	if qb.q == nil {
+		return ErrDetachedQuery
+	}
+	return qb.err
+}
+
 // WhereAnyOfText adds an @filter(anyoftext(predicate, $1)) clause. It
 // accumulates and ANDs with other filters like Filter.
 func (qb *Query[T]) WhereAnyOfText(predicate, term string) *Query[T] {
@@ -281,6 +377,35 @@ func (qb *Query[T]) WhereAllOfText(predicate, term string) *Query[T] {
 	return qb
 }
 
+// WhereNear adds an @filter(near(predicate, p, meters)) clause, matching
+// nodes whose geo predicate lies within meters of p. It accumulates and ANDs
+// with other filters like Filter.
+func (qb *Query[T]) WhereNear(predicate string, p modusgraph.Point, meters float64) *Query[T] {
+	qb.addFilter(fmt.Sprintf("near(%s, $1, $2)", predicate), []any{p, meters})
+	return qb
+}
+
+// WhereWithin adds an @filter(within(predicate, area)) clause, matching nodes
+// whose geo predicate lies inside area, a Polygon or MultiPolygon.
+func (qb *Query[T]) WhereWithin(predicate string, area modusgraph.Geometry) *Query[T] {
+	qb.addFilter(fmt.Sprintf("within(%s, $1)", predicate), []any{area})
+	return qb
+}
+
+// WhereContains adds an @filter(contains(predicate, g)) clause, matching
+// nodes whose geo predicate, an area, contains g, a Point or Polygon.
+func (qb *Query[T]) WhereContains(predicate string, g modusgraph.Geometry) *Query[T] {
+	qb.addFilter(fmt.Sprintf("contains(%s, $1)", predicate), []any{g})
+	return qb
+}
+
+// WhereIntersects adds an @filter(intersects(predicate, area)) clause,
+// matching nodes whose geo predicate, an area, overlaps area.
+func (qb *Query[T]) WhereIntersects(predicate string, area modusgraph.Geometry) *Query[T] {
+	qb.addFilter(fmt.Sprintf("intersects(%s, $1)", predicate), []any{area})
+	return qb
+}
+
 // As names the query block as a dgraph query variable. dgraph requires such a
 // variable be consumed by another block, which a single-block typed query
 // cannot do, so As transitions out of the typed query: it returns a *RawQuery,
@@ -308,8 +433,8 @@ func (qb *Query[T]) GroupBy(predicate string) *RawQuery {
 
 // Nodes executes the query and returns all matching records.
 func (qb *Query[T]) Nodes() (out []T, err error) {
-	if qb.q == nil {
-		return nil, ErrDetachedQuery
+	if err := qb.usable(); err != nil {
+		return nil, err
 	}
 	_, span := currentTracer().StartSpan(qb.ctx, "query", entityName[T]())
 	defer func() { span.End(err) }()
@@ -326,8 +451,8 @@ func (qb *Query[T]) Nodes() (out []T, err error) {
 // First executes the query with an implicit Limit(1) and returns the first
 // record, or (nil, nil) if the query matched no rows.
 func (qb *Query[T]) First() (rec *T, err error) {
-	if qb.q == nil {
-		return nil, ErrDetachedQuery
+	if err := qb.usable(); err != nil {
+		return nil, err
 	}
 	_, span := currentTracer().StartSpan(qb.ctx, "query", entityName[T]())
 	defer func() { span.End(err) }()
@@ -363,16 +488,20 @@ func (qb *Query[T]) First() (rec *T, err error) {
 // fresh snapshot. On error it yields a final (nil, err) and stops.
 func (qb *Query[T]) IterNodes() iter.Seq2[*T, error] {
 	return func(yield func(*T, error) bool) {
-		if qb.q == nil {
-			yield(nil, ErrDetachedQuery)
+		if err := qb.usable(); err != nil {
+			yield(nil, err)
 			return
 		}
 		_, span := currentTracer().StartSpan(qb.ctx, "query", entityName[T]())
 		var ferr error
 		defer func() { span.End(ferr) }()
+		pageSize := defaultPageSize
+		if qb.page > 0 {
+			pageSize = qb.page
+		}
 		remaining := qb.limit // 0 = unbounded
-		for off := qb.offset; ; off += defaultPageSize {
-			size := defaultPageSize
+		for off := qb.offset; ; off += pageSize {
+			size := pageSize
 			if remaining > 0 && remaining < size {
 				size = remaining // shrink the last page so it can't overshoot the cap
 			}
@@ -430,12 +559,31 @@ func (qb *Query[T]) All(depth int) *Query[T] {
 	return qb
 }
 
+// With applies query options, the same ones modusgraph.TypedQuery.With
+// takes, so one set composes across builders. A Page records its bounds for
+// IterNodes as Limit and Offset do; a Cursor is After.
+func (qb *Query[T]) With(opts ...queryopt.Option) *Query[T] {
+	if qb.q == nil {
+		return qb
+	}
+	o := queryopt.Resolve(opts...)
+	if o.Page.Limit > 0 {
+		qb.limit = o.Page.Limit
+	}
+	if o.Page.Offset > 0 {
+		qb.offset = o.Page.Offset
+	}
+	var model T
+	qb.q = modusgraph.ApplyQueryOptions(qb.conn, qb.q, &model, opts...)
+	return qb
+}
+
 // NodesAndCount executes the query and returns the matching records together
 // with the total count (useful for pagination totals). Like Nodes, it runs the
 // WhereEdge pre-pass first when edge constraints are present.
 func (qb *Query[T]) NodesAndCount() (out []T, count int, err error) {
-	if qb.q == nil {
-		return nil, 0, ErrDetachedQuery
+	if err := qb.usable(); err != nil {
+		return nil, 0, err
 	}
 	_, span := currentTracer().StartSpan(qb.ctx, "query", entityName[T]())
 	defer func() { span.End(err) }()
@@ -574,7 +722,7 @@ func (qb *Query[T]) edgeBlocks(withCount bool) []*dg.Query {
 	if userExpr != "" {
 		dataExpr = "(" + userExpr + ") AND uid(" + edgeVarName + ")"
 	}
-	qb.q.Filter(dataExpr, userParams...).Name(edgeDataBlock)
+	qb.q.Filter(dataExpr, literals(userParams)...).Name(edgeDataBlock)
 
 	blocks := []*dg.Query{qb.edgeVarBlock(), qb.q}
 	if withCount {
@@ -594,7 +742,7 @@ func (qb *Query[T]) edgeVarBlock() *dg.Query {
 	if qb.customRootExpr != "" {
 		v.RootFunc(qb.customRootExpr)
 	}
-	v.As(edgeVarName).Var().Cascade().Query(body, params...)
+	v.As(edgeVarName).Var().Cascade().Query(body, literals(params)...)
 	return v
 }
 
@@ -606,7 +754,7 @@ func (qb *Query[T]) edgeCountBlock(userExpr string, userParams []any) *dg.Query
 	c := qb.conn.Query(qb.ctx, &z)
 	c.RootFunc("uid(" + edgeVarName + ")")
 	if userExpr != "" {
-		c.Filter(userExpr, userParams...)
+		c.Filter(userExpr, literals(userParams)...)
 	}
 	c.Query("{ count(uid) }").Name(edgeCountBlock)
 	return c
@@ -14,6 +14,7 @@ import (
 	dg "github.com/dolan-in/dgman/v2"
 	"github.com/go-logr/logr/funcr"
 	"github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/queryopt"
 	"github.com/matthewmcneely/modusgraph/typed"
 )
 
@@ -237,6 +238,68 @@ func TestQuery_CombinedFilterShiftsPlaceholders(t *testing.T) {
 	}
 }
 
+// TestQuery_GeoFilters verifies that the geo helpers render Dgraph's geo
+// functions with the geometry bound as a parameter and ANDed like Filter.
+func TestQuery_GeoFilters(t *testing.T) {
+	p := modusgraph.NewPoint(-122.4, 37.8)
+	area := modusgraph.MustPolygon([]modusgraph.Point{
+		modusgraph.NewPoint(0, 0), modusgraph.NewPoint(1, 0), modusgraph.NewPoint(1, 1),
+	})
+	q := typed.NewDetachedQuery[widget]().
+		WhereNear("loc", p, 500).
+		WhereWithin("loc", area).
+		WhereContains("zone", p).
+		WhereIntersects("zone", area)
+	expr, params := q.CombinedFilter()
+	const want = "(near(loc, $1, $2)) AND (within(loc, $3)) AND (contains(zone, $4)) AND (intersects(zone, $5))"
+	if expr != want {
+		t.Fatalf("CombinedFilter expr = %q, want %q", expr, want)
+	}
+	if len(params) != 5 || params[0] != p || params[1] != 500.0 {
+		t.Fatalf("CombinedFilter params = %v, want the point, 500 and the geometries", params)
+	}
+}
+
+// TestQuery_FilterQuestionMarkPlaceholders verifies that ? placeholders bind
+// in order and that a hostile value stays inside its string literal.
+func TestQuery_FilterQuestionMarkPlaceholders(t *testing.T) {
+	ctx := context.Background()
+	c := typed.NewClient[widget](newConn(t))
+
+	hostile := `beta") OR has(name) OR eq(name, "`
+	for _, w := range []widget{{Name: "alpha", Qty: 9}, {Name: "beta", Qty: 9}, {Name: hostile, Qty: 3}} {
+		if err := c.Add(ctx, &w); err != nil {
+			t.Fatalf("Add %+v: %v", w, err)
+		}
+	}
+
+	got, err := c.Query(ctx).Filter("eq(name, ?) AND ge(qty, ?)", "beta", 5).Nodes()
+	if err != nil {
+		t.Fatalf("Filter Nodes: %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "beta" {
+		t.Fatalf("got %+v, want only beta", got)
+	}
+
+	got, err = c.Query(ctx).Filter("eq(name, ?)", hostile).Nodes()
+	if err != nil {
+		t.Fatalf("Filter Nodes: %v", err)
+	}
+	if len(got) != 1 || got[0].Name != hostile {
+		t.Fatalf("hostile value matched %+v, want only the node holding it", got)
+	}
+
+	// A value with no DQL literal fails the terminal instead of being formatted.
+	_, err = c.Query(ctx).Filter("eq(name, ?)", struct{ Name string }{"beta"}).Nodes()
+	if !errors.Is(err, modusgraph.ErrFilterParam) {
+		t.Fatalf("struct param error = %v, want ErrFilterParam", err)
+	}
+	_, err = c.Query(ctx).Filter("eq(name, ?) AND eq(qty, ?)", "beta").First()
+	if !errors.Is(err, modusgraph.ErrFilterParam) {
+		t.Fatalf("missing param error = %v, want ErrFilterParam", err)
+	}
+}
+
 // TestQuery_CombinedFilterParenthesizesFragments pins the precedence guarantee:
 // a fragment that contains OR must stay grouped when it is ANDed with another
 // fragment. Without per-fragment parentheses the expression would render as
@@ -293,6 +356,70 @@ func TestQuery_OrGroup(t *testing.T) {
 	}
 }
 
+func TestQuery_NotGroup(t *testing.T) {
+	ctx := context.Background()
+	c := typed.NewClient[widget](newConn(t))
+	for _, w := range []widget{
+		{Name: "alpha", Qty: 9},
+		{Name: "beta", Qty: 9},
+		{Name: "gamma", Qty: 1},
+	} {
+		if err := c.Add(ctx, &w); err != nil {
+			t.Fatalf("Add %+v: %v", w, err)
+		}
+	}
+
+	// NOT name == "alpha": beta and gamma.
+	got, err := c.Query(ctx).NotGroup(
+		typed.NewDetachedQuery[widget]().Filter(`eq(name, $1)`, "alpha"),
+	).Nodes()
+	if err != nil {
+		t.Fatalf("NotGroup Nodes: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("NOT alpha returned %d rows, want 2", len(got))
+	}
+
+	// Nested: qty >= 5 AND NOT (name == "gamma" OR (qty >= 5 AND NOT name == "beta"))
+	// excludes alpha through the inner group, leaving only beta.
+	q := c.Query(ctx).
+		Filter(`ge(qty, ?)`, 5).
+		NotGroup(typed.NewDetachedQuery[widget]().OrGroup(
+			typed.NewDetachedQuery[widget]().Filter(`eq(name, ?)`, "gamma"),
+			typed.NewDetachedQuery[widget]().Filter(`ge(qty, ?)`, 5).NotGroup(
+				typed.NewDetachedQuery[widget]().Filter(`eq(name, ?)`, "beta")),
+		))
+	expr, params := q.CombinedFilter()
+	const want = `(ge(qty, $1)) AND (NOT (((((eq(name, $2))) OR ((ge(qty, $3)) AND (NOT ((eq(name, $4)))))))))`
+	if expr != want || len(params) != 4 {
+		t.Fatalf("CombinedFilter = %q with %d params, want %q with 4", expr, len(params), want)
+	}
+	got, err = q.Nodes()
+	if err != nil {
+		t.Fatalf("nested Nodes: %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "beta" {
+		t.Fatalf("nested NOT group returned %+v, want [beta/9]", got)
+	}
+
+	// An empty sub is a no-op, not a filter that matches nothing.
+	got, err = c.Query(ctx).NotGroup(typed.NewDetachedQuery[widget]()).Nodes()
+	if err != nil {
+		t.Fatalf("empty NotGroup Nodes: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("empty NotGroup returned %d rows, want 3", len(got))
+	}
+
+	// A sub's parameter error reaches the terminal.
+	_, err = c.Query(ctx).NotGroup(
+		typed.NewDetachedQuery[widget]().Filter(`eq(name, ?)`, struct{}{}),
+	).Nodes()
+	if !errors.Is(err, modusgraph.ErrFilterParam) {
+		t.Fatalf("NotGroup with a bad parameter: err = %v, want ErrFilterParam", err)
+	}
+}
+
 func TestQuery_OrderAscDesc(t *testing.T) {
 	ctx := context.Background()
 	c := typed.NewClient[widget](newConn(t))
@@ -856,6 +983,58 @@ func TestIterNodes_OneQueryPerPage(t *testing.T) {
 	}
 }
 
+func TestIterNodes_PageSize(t *testing.T) {
+	ctx := context.Background()
+	var queriesExecuted int
+	c := typed.NewClient[widget](newCountingConn(t, &queriesExecuted))
+	const n = 25 // ceil(25/10) = 3 page queries
+	for i := range n {
+		if err := c.Add(ctx, &widget{Name: "w", Qty: i + 1}); err != nil {
+			t.Fatalf("Add %d: %v", i, err)
+		}
+	}
+	before := queriesExecuted
+	seen := 0
+	for _, err := range c.Query(ctx).PageSize(10).IterNodes() {
+		if err != nil {
+			t.Fatalf("IterNodes yielded error: %v", err)
+		}
+		seen++
+	}
+	if seen != n {
+		t.Fatalf("PageSize(10).IterNodes() streamed %d records, want %d", seen, n)
+	}
+	if delta := queriesExecuted - before; delta != 3 {
+		t.Fatalf("PageSize(10).IterNodes() over %d records ran %d queries, want 3", n, delta)
+	}
+}
+
+func TestIterNodes_WritesWhileIterating(t *testing.T) {
+	ctx := context.Background()
+	c := typed.NewClient[widget](newConn(t))
+	const n = 12
+	for i := range n {
+		if err := c.Add(ctx, &widget{Name: "w", Qty: i + 1}); err != nil {
+			t.Fatalf("Add %d: %v", i, err)
+		}
+	}
+	// Pages after the write read the same snapshot as the first one.
+	seen := 0
+	for w, err := range c.Query(ctx).OrderAsc("qty").PageSize(5).IterNodes() {
+		if err != nil {
+			t.Fatalf("IterNodes yielded error: %v", err)
+		}
+		if seen++; seen == 1 {
+			if err := c.Delete(ctx, w.UID); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+		}
+	}
+	if seen != n {
+		t.Fatalf("IterNodes with a delete mid-stream streamed %d records, want %d", seen, n)
+	}
+}
+
 func TestIterNodes_YieldsErrorAndStops(t *testing.T) {
 	ctx := context.Background()
 	c := typed.NewClient[widget](newConn(t))
@@ -1424,3 +1603,60 @@ func TestQuery_StringRendersDQL(t *testing.T) {
 		t.Fatalf("String() = %q, want it to mention the widget type", dql)
 	}
 }
+
+func TestQuery_WithOptions(t *testing.T) {
+	ctx := context.Background()
+	conn := newConn(t)
+	c := typed.NewClient[widget](conn)
+	for i := 1; i <= 10; i++ {
+		if err := c.Add(ctx, &widget{Name: "w", Qty: i}); err != nil {
+			t.Fatalf("Add %d: %v", i, err)
+		}
+	}
+
+	// One set of options means the same to both builders.
+	opts := []queryopt.Option{queryopt.Desc("qty"), queryopt.Page{Limit: 3, Offset: 2}}
+	got, err := c.Query(ctx).With(opts...).Nodes()
+	if err != nil {
+		t.Fatalf("With Nodes: %v", err)
+	}
+	core, err := modusgraph.Query[widget](ctx, conn).With(opts...).All()
+	if err != nil {
+		t.Fatalf("core With All: %v", err)
+	}
+	if len(got) != 3 || got[0].Qty != 8 || got[2].Qty != 6 {
+		t.Fatalf("With(Desc, Page) returned %+v, want qty 8, 7, 6", got)
+	}
+	for i := range got {
+		if got[i].UID != core[i].UID {
+			t.Fatalf("typed and core results differ at %d: %s vs %s", i, got[i].UID, core[i].UID)
+		}
+	}
+
+	// A Page bounds IterNodes as Limit and Offset do.
+	seen := 0
+	for rec, err := range c.Query(ctx).With(queryopt.Asc("qty"), queryopt.PageNumber(2, 4)).IterNodes() {
+		if err != nil {
+			t.Fatalf("IterNodes yielded error: %v", err)
+		}
+		if rec.Qty != 5+seen {
+			t.Fatalf("IterNodes row %d has qty %d, want %d", seen, rec.Qty, 5+seen)
+		}
+		seen++
+	}
+	if seen != 4 {
+		t.Fatalf("PageNumber(2, 4).IterNodes() streamed %d records, want 4", seen)
+	}
+
+	// Fields leaves the other fields unset.
+	rec, err := c.Query(ctx).With(queryopt.Fields{"qty"}, queryopt.Asc("qty")).First()
+	if err != nil {
+		t.Fatalf("Fields First: %v", err)
+	}
+	if rec == nil || rec.Name != "" || rec.Qty != 1 {
+		t.Fatalf("Fields{qty} returned %+v, want only uid and qty", rec)
+	}
+
+	// A detached query ignores options rather than panicking.
+	typed.NewDetachedQuery[widget]().With(queryopt.Depth(1))
+}
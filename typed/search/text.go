@@ -0,0 +1,89 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/dgraph-io/dgraph/v25/tok"
+)
+
+// Terms returns the distinct fulltext terms of text as Dgraph's fulltext
+// index derives them: lowercased, without stop words, and stemmed for lang,
+// English when lang is empty. Two texts match under alloftext or anyoftext
+// exactly when their terms do.
+func Terms(text, lang string) []string {
+	terms, _ := tok.GetTokenizerForLang(tok.FullTextTokenizer{}, lang).Tokens(text)
+	return terms
+}
+
+// Score rates how well text matches query between 0 and 1, for ranking the
+// results of a fulltext search. Each query term text contains adds to the
+// score, a term repeated in text adds more than one seen once, and terms it
+// lacks add nothing. Score is 0 when query has no terms.
+func Score(text, query, lang string) float64 {
+	want := Terms(query, lang)
+	if len(want) == 0 {
+		return 0
+	}
+	counts := map[string]int{}
+	for _, w := range words(text) {
+		for _, term := range Terms(text[w[0]:w[1]], lang) {
+			counts[term]++
+		}
+	}
+	var score float64
+	for _, term := range want {
+		n := float64(counts[term])
+		score += n / (n + 1)
+	}
+	return score / float64(len(want))
+}
+
+// Highlight returns text with each word whose term matches one of query's
+// enclosed in pre and post, such as "<em>" and "</em>".
+func Highlight(text, query, lang, pre, post string) string {
+	want := map[string]bool{}
+	for _, term := range Terms(query, lang) {
+		want[term] = true
+	}
+	var b strings.Builder
+	last := 0
+	for _, w := range words(text) {
+		terms := Terms(text[w[0]:w[1]], lang)
+		if len(terms) == 0 || !want[terms[0]] {
+			continue
+		}
+		b.WriteString(text[last:w[0]])
+		b.WriteString(pre)
+		b.WriteString(text[w[0]:w[1]])
+		b.WriteString(post)
+		last = w[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// words returns the byte offsets of the runs of letters and digits in text.
+func words(text string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range text {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(text)})
+	}
+	return spans
+}
@@ -0,0 +1,47 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package search_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/matthewmcneely/modusgraph/typed/search"
+)
+
+func TestTerms(t *testing.T) {
+	// Stop words are dropped and the rest stemmed, as the fulltext index does.
+	if got, want := search.Terms("The Running Dogs", ""), []string{"dog", "run"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Terms = %v, want %v", got, want)
+	}
+}
+
+func TestScore(t *testing.T) {
+	cases := []struct {
+		text, query string
+		want        float64
+	}{
+		{"dogs run", "dog", 0.5},
+		{"dogs run with dogs", "dog", 2.0 / 3},
+		{"dogs run", "dog cat", 0.25},
+		{"birds fly", "dog", 0},
+		{"dogs run", "the", 0},
+	}
+	for _, c := range cases {
+		if got := search.Score(c.text, c.query, ""); got != c.want {
+			t.Errorf("Score(%q, %q) = %v, want %v", c.text, c.query, got, c.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	// "ran" is not stemmed to "run", so the index would not match it either.
+	got := search.Highlight("The dog ran; dogs, running, chased cats.", "run dog", "", "[", "]")
+	want := "The [dog] ran; [dogs], [running], chased cats."
+	if got != want {
+		t.Fatalf("Highlight = %q, want %q", got, want)
+	}
+}
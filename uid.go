@@ -0,0 +1,133 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidUID is returned for a string that is not a node UID, such as one
+// passed to Get, Delete or CheckPassword or decoded into a UID.
+var ErrInvalidUID = errors.New("modusgraph: invalid uid")
+
+// UID identifies a node: "0x" followed by the node's nonzero number in hex,
+// as Dgraph reports it. The zero UID, "", is a node not yet stored. Use it
+// for identifiers that travel outside the graph, in API types, URLs and
+// messages, where decoding a UID checks it; the UID fields of stored structs
+// stay strings, which dgman writes assigned UIDs into:
+//
+//	type FilmRef struct {
+//	    ID modusgraph.UID `json:"id"`
+//	}
+//
+//	ref := FilmRef{ID: modusgraph.UIDOf(film)}
+type UID string
+
+// ParseUID returns s as a UID in canonical lowercase form, or an error
+// wrapping ErrInvalidUID if s is not one.
+func ParseUID(s string) (UID, error) {
+	n, ok := parseHexUID(s)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidUID, s)
+	}
+	return UIDFromUint64(n), nil
+}
+
+// UIDOf returns the UID field of obj, a struct or pointer to one, or the
+// zero UID if it has none.
+func UIDOf(obj any) UID {
+	v := reflect.Indirect(reflect.ValueOf(UnwrapSchema(obj)))
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	if f := v.FieldByName("UID"); f.IsValid() && f.Kind() == reflect.String {
+		return UID(f.String())
+	}
+	return ""
+}
+
+// UIDFromUint64 returns the UID of node number n, as returned by UIDs.
+func UIDFromUint64(n uint64) UID {
+	return UID("0x" + strconv.FormatUint(n, 16))
+}
+
+// String returns the UID as a string.
+func (u UID) String() string {
+	return string(u)
+}
+
+// IsZero reports whether u is unset.
+func (u UID) IsZero() bool {
+	return u == ""
+}
+
+// Valid reports whether u is a node UID.
+func (u UID) Valid() bool {
+	_, ok := parseHexUID(string(u))
+	return ok
+}
+
+// Uint64 returns the node number of u, or 0 if u is not a valid UID.
+func (u UID) Uint64() uint64 {
+	n, _ := parseHexUID(string(u))
+	return n
+}
+
+// MarshalJSON encodes u as a JSON string. It fails for a value that is
+// neither zero nor a valid UID.
+func (u UID) MarshalJSON() ([]byte, error) {
+	if !u.IsZero() && !u.Valid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidUID, string(u))
+	}
+	return json.Marshal(string(u))
+}
+
+// UnmarshalJSON decodes a JSON string into u, rejecting a malformed UID.
+func (u *UID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidUID, data)
+	}
+	if s == "" {
+		*u = ""
+		return nil
+	}
+	parsed, err := ParseUID(s)
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// parseHexUID parses "0x" followed by up to 16 hex digits, rejecting 0x0.
+func parseHexUID(s string) (uint64, bool) {
+	digits, ok := strings.CutPrefix(strings.ToLower(s), "0x")
+	if !ok || digits == "" || len(digits) > 16 {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(digits, 16, 64)
+	if err != nil || n == 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// checkUIDs returns an error wrapping ErrInvalidUID for the first of uids
+// that is not a valid UID.
+func checkUIDs(uids ...string) error {
+	for _, uid := range uids {
+		if _, ok := parseHexUID(uid); !ok {
+			return fmt.Errorf("%w: %q", ErrInvalidUID, uid)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type UIDFilm struct {
+	UID   string   `json:"uid,omitempty"`
+	Title string   `json:"uidfilm_title,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type UIDFilmRef struct {
+	ID    mg.UID `json:"id,omitempty"`
+	Title string `json:"title,omitempty"`
+}
+
+func TestParseUID(t *testing.T) {
+	for _, s := range []string{"0x1", "0X1A", "0xffffffffffffffff"} {
+		uid, err := mg.ParseUID(s)
+		require.NoError(t, err, s)
+		require.True(t, uid.Valid())
+	}
+	uid, err := mg.ParseUID("0X001A")
+	require.NoError(t, err)
+	require.Equal(t, mg.UID("0x1a"), uid)
+	require.Equal(t, uint64(26), uid.Uint64())
+	require.Equal(t, uid, mg.UIDFromUint64(26))
+
+	for _, s := range []string{"", "0x", "0x0", "1a", "0xg", "0x10000000000000000", "0x1) OR has(name"} {
+		_, err := mg.ParseUID(s)
+		require.ErrorIs(t, err, mg.ErrInvalidUID, s)
+	}
+
+	var zero mg.UID
+	require.True(t, zero.IsZero())
+	require.False(t, zero.Valid())
+	require.False(t, mg.UID("_:film").Valid())
+
+	require.Equal(t, mg.UID("0x7"), mg.UIDOf(&UIDFilm{UID: "0x7"}))
+	require.Equal(t, mg.UID("0x7"), mg.UIDOf(UIDFilm{UID: "0x7"}))
+	require.True(t, mg.UIDOf("not a struct").IsZero())
+}
+
+func TestUIDJSON(t *testing.T) {
+	data, err := json.Marshal(UIDFilmRef{ID: "0x2a", Title: "x"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":"0x2a","title":"x"}`, string(data))
+	data, err = json.Marshal(UIDFilmRef{Title: "unsaved"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"title":"unsaved"}`, string(data))
+	_, err = json.Marshal(UIDFilmRef{ID: "garbage"})
+	require.ErrorIs(t, err, mg.ErrInvalidUID)
+
+	var ref UIDFilmRef
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"0X2A"}`), &ref))
+	require.Equal(t, mg.UID("0x2a"), ref.ID)
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"id":"nope"}`), &ref), mg.ErrInvalidUID)
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"id":42}`), &ref), mg.ErrInvalidUID)
+}
+
+func TestUIDArguments(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "UIDArgumentsWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "UIDArgumentsWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			ctx := context.Background()
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			film := &UIDFilm{Title: "Alien"}
+			require.NoError(t, client.Insert(ctx, film))
+			uid := mg.UIDOf(film)
+			require.True(t, uid.Valid())
+			var got UIDFilm
+			require.NoError(t, client.Get(ctx, &got, uid.String()))
+			require.Equal(t, "Alien", got.Title)
+
+			// Malformed UIDs are rejected before they reach a query.
+			require.ErrorIs(t, client.Get(ctx, &got, "0x1) { uid } me(func: has(uidfilm_title)"), mg.ErrInvalidUID)
+			require.ErrorIs(t, client.Get(ctx, &got, ""), mg.ErrInvalidUID)
+			require.ErrorIs(t, client.Delete(ctx, []string{uid.String(), "* * ."}), mg.ErrInvalidUID)
+			_, err := client.CheckPassword(ctx, "0x1 OR 1", "pwd", "x")
+			require.ErrorIs(t, err, mg.ErrInvalidUID)
+
+			require.NoError(t, client.Get(ctx, &got, uid.String()))
+			require.NoError(t, client.Delete(ctx, []string{uid.String()}))
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// uidsBlock is the block name UIDs renders and scans for.
+const uidsBlock = "uids"
+
+// UIDs returns the UIDs of every node of model's type that matches filter,
+// without decoding any node bodies. filter is a dgraph @filter expression whose
+// ? or $N placeholders bind to params as BindFilter describes; an empty filter
+// matches every node of the type.
+//
+// The query selects only uid, and the response is scanned in place rather than
+// unmarshalled, so the only allocation proportional to the result is the
+// returned slice. It is the primitive for joins, bulk deletes, and counting
+// pipelines that need identities but not data.
+func (c client) UIDs(ctx context.Context, model any, filter string, params ...any) ([]uint64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	model = UnwrapSchema(model)
+	q := dg.NewQuery().Model(model).Name(uidsBlock).Query("{ uid }")
+	if filter != "" {
+		filter, params, err := BindFilter(filter, params...)
+		if err != nil {
+			return nil, err
+		}
+		q.Filter(filter, params...)
+	}
+	resp, err := c.QueryRaw(ctx, q.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	// Each match costs at least len(`{"uid":"0x0"},`) bytes of response.
+	return appendUIDs(make([]uint64, 0, len(resp)/14), resp)
+}
+
+var uidKey = []byte(`"uid"`)
+
+// appendUIDs scans a Dgraph JSON response for "uid":"0x..." members and
+// appends each parsed UID to dst. It does not allocate beyond growing dst.
+func appendUIDs(dst []uint64, data []byte) ([]uint64, error) {
+	err := scanUIDs(data, func(uid uint64) { dst = append(dst, uid) })
+	return dst, err
+}
+
+// scanUIDs calls fn with each "uid":"0x..." member of a Dgraph JSON
+// response, in order, without allocating.
+func scanUIDs(data []byte, fn func(uint64)) error {
+	for {
+		i := bytes.Index(data, uidKey)
+		if i < 0 {
+			return nil
+		}
+		data = skipSpace(data[i+len(uidKey):])
+		if len(data) == 0 || data[0] != ':' {
+			continue // "uid" appeared as a value, not a key
+		}
+		data = skipSpace(data[1:])
+		if len(data) < 3 || data[0] != '"' || data[1] != '0' || (data[2] != 'x' && data[2] != 'X') {
+			return fmt.Errorf("modusgraph: malformed uid in response")
+		}
+		data = data[3:]
+		var uid uint64
+		n := 0
+		for ; n < len(data) && data[n] != '"'; n++ {
+			d, ok := hexDigit(data[n])
+			if !ok || n >= 16 {
+				return fmt.Errorf("modusgraph: malformed uid in response")
+			}
+			uid = uid<<4 | uint64(d)
+		}
+		if n == 0 || n == len(data) {
+			return fmt.Errorf("modusgraph: malformed uid in response")
+		}
+		fn(uid)
+		data = data[n+1:]
+	}
+}
+
+func skipSpace(b []byte) []byte {
+	for len(b) > 0 && (b[0] == ' ' || b[0] == '\t' || b[0] == '\n' || b[0] == '\r') {
+		b = b[1:]
+	}
+	return b
+}
+
+func hexDigit(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
@@ -0,0 +1,49 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendUIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []uint64
+		wantErr bool
+	}{
+		{"Empty block", `{"uids":[]}`, nil, false},
+		{"Compact", `{"uids":[{"uid":"0x1"},{"uid":"0x2a"},{"uid":"0xFFFF"}]}`, []uint64{1, 42, 65535}, false},
+		{"Whitespace", "{\"uids\": [ { \"uid\" : \"0x10\" } ]}", []uint64{16}, false},
+		{"Max uid", `{"uids":[{"uid":"0xffffffffffffffff"}]}`, []uint64{^uint64(0)}, false},
+		{"Not hex", `{"uids":[{"uid":"0xzz"}]}`, nil, true},
+		{"Missing prefix", `{"uids":[{"uid":"12"}]}`, nil, true},
+		{"Overflow", `{"uids":[{"uid":"0x1ffffffffffffffff"}]}`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := appendUIDs(nil, []byte(tt.input))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAppendUIDsDoesNotAllocate(t *testing.T) {
+	data := []byte(`{"uids":[{"uid":"0x1"},{"uid":"0x2"},{"uid":"0x3"},{"uid":"0x4"}]}`)
+	dst := make([]uint64, 0, 8)
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = appendUIDs(dst[:0], data)
+	})
+	require.Zero(t, allocs)
+}
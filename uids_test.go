@@ -0,0 +1,78 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientUIDs(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "UIDsWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "UIDsWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			ctx := context.Background()
+			entities := make([]*TestEntity, 6)
+			for i := range entities {
+				entities[i] = &TestEntity{
+					Name:        fmt.Sprintf("uids-%d", i),
+					Description: map[bool]string{true: "even", false: "odd"}[i%2 == 0],
+					CreatedAt:   time.Now(),
+				}
+			}
+			require.NoError(t, client.Insert(ctx, entities))
+
+			all, err := client.UIDs(ctx, &TestEntity{}, "")
+			require.NoError(t, err)
+			require.Len(t, all, len(entities))
+
+			even, err := client.UIDs(ctx, &TestEntity{}, "eq(description, $1)", "even")
+			require.NoError(t, err)
+			want := make([]uint64, 0, 3)
+			for i, e := range entities {
+				if i%2 == 0 {
+					uid, err := strconv.ParseUint(e.UID[2:], 16, 64)
+					require.NoError(t, err)
+					want = append(want, uid)
+				}
+			}
+			require.ElementsMatch(t, want, even)
+
+			none, err := client.UIDs(ctx, &TestEntity{}, "eq(name, $1)", "missing")
+			require.NoError(t, err)
+			require.Empty(t, none)
+		})
+	}
+}
@@ -0,0 +1,352 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// uniqueGroupTag matches each unique_group=<name> token of a dgraph tag. A
+// field may belong to several groups by repeating the token.
+var uniqueGroupTag = regexp.MustCompile(`(?:^|[\s,])unique_group=([\w.]+)`)
+
+// uniqueGroup is a set of predicates whose combined values must be unique
+// among the nodes of a type, declared by tagging each of its fields
+// dgraph:"unique_group=<name>".
+type uniqueGroup struct {
+	name       string
+	predicates []string
+	fields     []int
+}
+
+// uniqueGroups returns the unique groups declared by the fields of struct
+// type t, in order of first appearance.
+func uniqueGroups(t reflect.Type) []uniqueGroup {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var groups []uniqueGroup
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		for _, m := range uniqueGroupTag.FindAllStringSubmatch(field.Tag.Get("dgraph"), -1) {
+			j := slices.IndexFunc(groups, func(g uniqueGroup) bool { return g.name == m[1] })
+			if j < 0 {
+				groups = append(groups, uniqueGroup{name: m[1]})
+				j = len(groups) - 1
+			}
+			groups[j].predicates = append(groups[j].predicates, fieldPredicate(field))
+			groups[j].fields = append(groups[j].fields, i)
+		}
+	}
+	return groups
+}
+
+// findUniqueGroup returns the group of obj's type called name.
+func findUniqueGroup(obj any, name string) (uniqueGroup, bool) {
+	for _, g := range uniqueGroups(reflect.TypeOf(obj)) {
+		if g.name == name {
+			return g, true
+		}
+	}
+	return uniqueGroup{}, false
+}
+
+// applyUniqueGroups adjusts the predicates of s that belong to the unique
+// groups of models: each needs an index for the eq lookups that enforce the
+// group, and @upsert so that a Dgraph cluster aborts one of two concurrent
+// transactions writing the same values.
+func applyUniqueGroups(s *SchemaInfo, models ...any) {
+	for _, m := range models {
+		for _, g := range uniqueGroups(reflect.TypeOf(UnwrapSchema(m))) {
+			for _, pred := range g.predicates {
+				i := slices.IndexFunc(s.Predicates, func(p PredicateInfo) bool { return p.Name == pred })
+				if i < 0 {
+					continue
+				}
+				p := &s.Predicates[i]
+				p.Upsert = true
+				if p.Type == "string" && !slices.Contains(p.Indexes, "hash") && !slices.Contains(p.Indexes, "exact") {
+					p.Indexes = append(p.Indexes, "hash")
+				} else if len(p.Indexes) == 0 {
+					if tok, ok := defaultTokenizers[p.Type]; ok {
+						p.Indexes = []string{tok}
+					}
+				}
+			}
+		}
+	}
+}
+
+// defaultTokenizers are the indexes given to unique group predicates of
+// non-string types that declare none.
+var defaultTokenizers = map[string]string{
+	"int":      "int",
+	"float":    "float",
+	"bool":     "bool",
+	"datetime": "hour",
+}
+
+// structElems returns the structs obj points to: one for a pointer to a
+// struct, one per element for a slice of struct pointers.
+func structElems(obj any) []reflect.Value {
+	val := reflect.ValueOf(obj)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+	var structs []reflect.Value
+	switch val.Kind() {
+	case reflect.Slice:
+		for i := 0; i < val.Len(); i++ {
+			elem := val.Index(i)
+			if elem.Kind() == reflect.Ptr {
+				elem = elem.Elem()
+			}
+			if elem.Kind() == reflect.Struct {
+				structs = append(structs, elem)
+			}
+		}
+	case reflect.Struct:
+		structs = append(structs, val)
+	}
+	return structs
+}
+
+// hasUniqueGroups reports whether the type of obj declares unique groups.
+func hasUniqueGroups(obj any) bool {
+	return len(uniqueGroups(reflect.TypeOf(obj))) > 0
+}
+
+// lockUniqueGroups serializes the check-then-write of objects with unique
+// groups on the embedded engine, which applies mutations immediately and so
+// cannot abort the loser of two concurrent writes the way a cluster does. It
+// returns the function that releases the lock.
+func (c client) lockUniqueGroups(obj any) func() {
+	if c.engine == nil || !hasUniqueGroups(obj) {
+		return func() {}
+	}
+	c.engine.uniqueMu.Lock()
+	return c.engine.uniqueMu.Unlock
+}
+
+// checkUniqueGroups fails with ErrUniqueConflict if writing obj would give
+// another node of its type the same values for any of its unique groups,
+// either in the store or earlier in the same slice. A node that holds the
+// same value of the upsert predicate match is not a conflict, since an
+// Upsert matching on it updates that node.
+func (c client) checkUniqueGroups(ctx context.Context, tx *dg.TxnContext, obj any, match string) error {
+	seen := make(map[string]string)
+	for _, sv := range structElems(obj) {
+		groups := uniqueGroups(sv.Type())
+		if len(groups) == 0 {
+			continue
+		}
+		nodeType := getNodeType(sv.Interface())
+		uid := uidOf(sv.Addr().Interface())
+		for _, g := range groups {
+			values, ok := uniqueGroupValues(sv, g)
+			if !ok {
+				continue
+			}
+			key := fmt.Sprintf("%s|%s|%v", nodeType, g.name, values)
+			if prev, dup := seen[key]; dup {
+				return uniqueGroupConflict(nodeType, g, values, prev)
+			}
+			seen[key] = uid
+
+			var exclude map[string]any
+			if match != "" {
+				if v, ok := predicateValue(sv, match); ok {
+					exclude = map[string]any{match: v}
+				}
+			}
+			query, vars := uniqueGroupQuery(nodeType, g, values, uid, exclude)
+			resp, err := tx.Txn().QueryWithVars(ctx, query, vars)
+			if err != nil {
+				return fmt.Errorf("checking unique group %s: %w", g.name, err)
+			}
+			existing, err := extractUIDFromDgraphQueryResult(resp.Json)
+			if err != nil {
+				return err
+			}
+			if existing != "" {
+				return uniqueGroupConflict(nodeType, g, values, existing)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveUniqueGroup sets the UID of each struct in obj that has none to the
+// node of its type holding the same values for group, so that the following
+// write updates that node instead of inserting a new one.
+func (c client) resolveUniqueGroup(ctx context.Context, tx *dg.TxnContext, obj any, group string) error {
+	for _, sv := range structElems(obj) {
+		g, ok := findUniqueGroup(sv.Addr().Interface(), group)
+		if !ok {
+			return fmt.Errorf("type %s has no unique group %s", sv.Type().Name(), group)
+		}
+		uidField := sv.FieldByName("UID")
+		if !uidField.IsValid() || uidField.Kind() != reflect.String {
+			return fmt.Errorf("type %s has no UID field", sv.Type().Name())
+		}
+		if isUIDValue(uidField.String()) {
+			continue
+		}
+		values, _ := uniqueGroupValues(sv, g)
+		query, vars := uniqueGroupQuery(getNodeType(sv.Interface()), g, values, "", nil)
+		resp, err := tx.Txn().QueryWithVars(ctx, query, vars)
+		if err != nil {
+			return fmt.Errorf("resolving unique group %s: %w", g.name, err)
+		}
+		existing, err := extractUIDFromDgraphQueryResult(resp.Json)
+		if err != nil {
+			return err
+		}
+		if existing != "" {
+			uidField.SetString(existing)
+		}
+	}
+	return nil
+}
+
+// GetByUniqueGroup implements loading the node whose values for the named
+// unique group equal those of obj.
+func (c client) GetByUniqueGroup(ctx context.Context, obj any, group string) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	obj = UnwrapSchema(obj)
+	if err := checkPointer(obj); err != nil {
+		return err
+	}
+	g, ok := findUniqueGroup(obj, group)
+	if !ok {
+		return fmt.Errorf("type %s has no unique group %s", getNodeType(obj), group)
+	}
+
+	dgClient, err := c.pool.get()
+	if err != nil {
+		return err
+	}
+	defer c.pool.put(dgClient)
+
+	values, _ := uniqueGroupValues(reflect.ValueOf(obj).Elem(), g)
+	query, vars := uniqueGroupQuery(getNodeType(obj), g, values, "", nil)
+	txn := dg.NewReadOnlyTxnContext(ctx, dgClient)
+	resp, err := txn.Txn().QueryWithVars(ctx, query, vars)
+	if err != nil {
+		return err
+	}
+	uid, err := extractUIDFromDgraphQueryResult(resp.Json)
+	if err != nil {
+		return err
+	}
+	if uid == "" {
+		return dg.ErrNodeNotFound
+	}
+	if err := expand(txn.Get(obj).UID(uid), obj, c.options.maxEdgeTraversal, c.options.maxEdgeFanout).Node(); err != nil {
+		return err
+	}
+	return LoadLocalized(ctx, c, obj)
+}
+
+// uniqueGroupValues returns the values of the fields of group in sv, and
+// false when all of them are zero, in which case the group is not enforced.
+func uniqueGroupValues(sv reflect.Value, g uniqueGroup) ([]any, bool) {
+	values := make([]any, len(g.fields))
+	set := false
+	for i, f := range g.fields {
+		fv := sv.Field(f)
+		set = set || !fv.IsZero()
+		values[i] = fv.Interface()
+	}
+	return values, set
+}
+
+// predicateValue returns the value of the field of sv stored as predicate.
+func predicateValue(sv reflect.Value, predicate string) (any, bool) {
+	t := sv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if fieldPredicate(t.Field(i)) == predicate {
+			fv := sv.Field(i)
+			return fv.Interface(), !fv.IsZero()
+		}
+	}
+	return nil, false
+}
+
+// uniqueGroupQuery builds the query for the first node of nodeType holding
+// values for the predicates of g, other than the node uid and the nodes
+// whose predicates equal those of exclude.
+func uniqueGroupQuery(nodeType string, g uniqueGroup, values []any, uid string,
+	exclude map[string]any) (string, map[string]string) {
+	vars := make(map[string]string)
+	var decls []string
+	addVar := func(v any) string {
+		name := fmt.Sprintf("$v%d", len(vars))
+		varType, text := queryVar(v)
+		vars[name] = text
+		decls = append(decls, name+": "+varType)
+		return name
+	}
+
+	conditions := make([]string, len(g.predicates))
+	for i, pred := range g.predicates {
+		conditions[i] = fmt.Sprintf("eq(%s, %s)", pred, addVar(values[i]))
+	}
+	filter := strings.Join(conditions, " AND ")
+	if isUIDValue(uid) {
+		filter += fmt.Sprintf(" AND NOT uid(%s)", uid)
+	}
+	for pred, v := range exclude {
+		filter += fmt.Sprintf(" AND NOT eq(%s, %s)", pred, addVar(v))
+	}
+
+	query := fmt.Sprintf("query q(%s) {\n  q(func: type(%s), first: 1) @filter(%s) {\n    uid\n  }\n}\n",
+		strings.Join(decls, ", "), nodeType, filter)
+	return query, vars
+}
+
+// queryVar returns the DQL variable type and text of a field value.
+func queryVar(v any) (string, string) {
+	switch v := v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "int", fmt.Sprint(v)
+	case float32, float64:
+		return "float", fmt.Sprint(v)
+	case bool:
+		return "bool", fmt.Sprint(v)
+	case time.Time:
+		return "string", v.Format(time.RFC3339Nano)
+	}
+	return "string", fmt.Sprint(v)
+}
+
+// isUIDValue reports whether uid names an existing node rather than being
+// empty or a blank node.
+func isUIDValue(uid string) bool {
+	return strings.HasPrefix(uid, "0x")
+}
+
+// uniqueGroupConflict reports values of group as already held by uid.
+func uniqueGroupConflict(nodeType string, g uniqueGroup, values []any, uid string) error {
+	return uniqueConflictError{&UniqueError{
+		NodeType: nodeType,
+		Field:    g.name,
+		Value:    values,
+		UID:      uid,
+	}}
+}
@@ -0,0 +1,225 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/dgraph-io/dgo/v250"
+	dg "github.com/dolan-in/dgman/v2"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type UniqueAccount struct {
+	UID    string   `json:"uid,omitempty"`
+	Email  string   `json:"acct_email,omitempty" dgraph:"index=exact unique"`
+	Number int      `json:"acct_number,omitempty" dgraph:"index=int unique"`
+	Note   string   `json:"acct_note,omitempty"`
+	DType  []string `json:"dgraph.type,omitempty"`
+}
+
+func TestUniqueConflict(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "UniqueConflictWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "UniqueConflictWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+			require.NoError(t, client.UpdateSchema(ctx, &UniqueAccount{}))
+
+			// Concurrent inserts of the same email: exactly one wins.
+			const writers = 8
+			errs := make([]error, writers)
+			var wg sync.WaitGroup
+			for i := range writers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs[i] = client.Insert(ctx, &UniqueAccount{
+						Email:  "ada@example.com",
+						Number: 100 + i,
+						Note:   fmt.Sprintf("writer %d", i),
+					})
+				}()
+			}
+			wg.Wait()
+			succeeded := 0
+			for _, err := range errs {
+				if err == nil {
+					succeeded++
+					continue
+				}
+				require.ErrorIs(t, err, mg.ErrUniqueConflict)
+				var uniqueErr *mg.UniqueError
+				require.ErrorAs(t, err, &uniqueErr)
+				require.Equal(t, "acct_email", uniqueErr.Field)
+			}
+			require.Equal(t, 1, succeeded)
+
+			// Int predicates are enforced as well.
+			winner := &UniqueAccount{Email: "grace@example.com", Number: 42}
+			require.NoError(t, client.Insert(ctx, winner))
+			err := client.Insert(ctx, &UniqueAccount{Email: "linus@example.com", Number: 42})
+			require.ErrorIs(t, err, mg.ErrUniqueConflict)
+			var uniqueErr *mg.UniqueError
+			require.ErrorAs(t, err, &uniqueErr)
+			require.Equal(t, "acct_number", uniqueErr.Field)
+
+			// Updating a node keeps its own value.
+			winner.Note = "updated"
+			require.NoError(t, client.Update(ctx, winner))
+
+			var accounts []UniqueAccount
+			require.NoError(t, client.Query(ctx, UniqueAccount{}).Nodes(&accounts))
+			require.Len(t, accounts, 2)
+		})
+	}
+}
+
+type GroupFilm struct {
+	UID    string   `json:"uid,omitempty"`
+	Title  string   `json:"grp_title,omitempty" dgraph:"index=exact unique_group=title_year"`
+	Year   int      `json:"grp_year,omitempty" dgraph:"unique_group=title_year"`
+	Rating float64  `json:"grp_rating,omitempty"`
+	DType  []string `json:"dgraph.type,omitempty"`
+}
+
+func TestUniqueGroup(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "UniqueGroupWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "UniqueGroupWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx := context.Background()
+			require.NoError(t, client.UpdateSchema(ctx, &GroupFilm{}))
+
+			// Group members are indexed for the lookups and marked @upsert.
+			schema, err := client.GetSchema(ctx)
+			require.NoError(t, err)
+			year, ok := schema.Predicate("grp_year")
+			require.True(t, ok)
+			require.Equal(t, []string{"int"}, year.Indexes)
+			require.True(t, year.Upsert)
+
+			metropolis := &GroupFilm{Title: "Metropolis", Year: 1927}
+			require.NoError(t, client.Insert(ctx, metropolis))
+			require.NoError(t, client.Insert(ctx, &GroupFilm{Title: "Metropolis", Year: 2001}))
+			require.NoError(t, client.Insert(ctx, &GroupFilm{Title: "Sunrise", Year: 1927}))
+
+			// Only the combination has to be unique.
+			err = client.Insert(ctx, &GroupFilm{Title: "Metropolis", Year: 1927})
+			require.ErrorIs(t, err, mg.ErrUniqueConflict)
+			var uniqueErr *mg.UniqueError
+			require.ErrorAs(t, err, &uniqueErr)
+			require.Equal(t, "title_year", uniqueErr.Field)
+			require.Equal(t, metropolis.UID, uniqueErr.UID)
+
+			// Duplicates within one batch are caught too.
+			err = client.Insert(ctx, []*GroupFilm{
+				{Title: "Faust", Year: 1926},
+				{Title: "Faust", Year: 1926},
+			})
+			require.ErrorIs(t, err, mg.ErrUniqueConflict)
+
+			// Updating a node to another node's values conflicts; keeping its
+			// own values does not.
+			sunrise := &GroupFilm{Title: "Sunrise", Year: 1927}
+			require.NoError(t, client.GetByUniqueGroup(ctx, sunrise, "title_year"))
+			sunrise.Title = "Metropolis"
+			require.ErrorIs(t, client.Update(ctx, sunrise), mg.ErrUniqueConflict)
+			sunrise.Title = "Sunrise"
+			sunrise.Rating = 8.1
+			require.NoError(t, client.Update(ctx, sunrise))
+
+			// Upserting on the group updates the node holding its values.
+			require.NoError(t, client.Upsert(ctx, &GroupFilm{Title: "Metropolis", Year: 1927, Rating: 8.3}, "title_year"))
+			found := &GroupFilm{Title: "Metropolis", Year: 1927}
+			require.NoError(t, client.GetByUniqueGroup(ctx, found, "title_year"))
+			require.Equal(t, metropolis.UID, found.UID)
+			require.Equal(t, 8.3, found.Rating)
+
+			require.NoError(t, client.Upsert(ctx, &GroupFilm{Title: "Nosferatu", Year: 1922}, "title_year"))
+
+			// Concurrent writers of the same combination: exactly one wins. A
+			// Dgraph cluster may abort a loser instead of reporting the conflict.
+			const writers = 8
+			errs := make([]error, writers)
+			var wg sync.WaitGroup
+			for i := range writers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs[i] = client.Insert(ctx, &GroupFilm{Title: "Häxan", Year: 1922})
+				}()
+			}
+			wg.Wait()
+			succeeded := 0
+			for _, err := range errs {
+				if err == nil {
+					succeeded++
+					continue
+				}
+				require.True(t, errors.Is(err, mg.ErrUniqueConflict) || errors.Is(err, dgo.ErrAborted), err)
+			}
+			require.Equal(t, 1, succeeded)
+
+			var films []GroupFilm
+			require.NoError(t, client.Query(ctx, GroupFilm{}).Nodes(&films))
+			require.Len(t, films, 5)
+
+			err = client.GetByUniqueGroup(ctx, &GroupFilm{Title: "Faust", Year: 1926}, "title_year")
+			require.ErrorIs(t, err, dg.ErrNodeNotFound)
+			err = client.GetByUniqueGroup(ctx, &GroupFilm{Title: "Faust"}, "title")
+			require.ErrorContains(t, err, "no unique group")
+		})
+	}
+}
@@ -0,0 +1,305 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	dg "github.com/dolan-in/dgman/v2"
+)
+
+// Condition is a DQL filter evaluated against the stored node by UpsertIf.
+// Build one with Eq, Lt, Le, Gt, Ge, Has or Cond, and combine them with And,
+// Or and Not. Values are bound as parameters under the rules of BindFilter,
+// never formatted into the expression.
+type Condition struct {
+	expr   string // uses ? placeholders
+	params []any
+}
+
+// Cond is a condition from an arbitrary DQL filter expression, such as
+// Cond("lt(version, ?) OR NOT has(version)", v). Parameters bind to ? or $N
+// placeholders as in BindFilter.
+func Cond(expr string, params ...any) Condition {
+	// Conditions combine by concatenating ? placeholders, so number $N
+	// placeholders by their order in the expression instead.
+	var ordered []any
+	positional := true
+	rewritten := rewritePlaceholders(expr, func(tok string) string {
+		n, _ := strconv.Atoi(tok[1:])
+		if tok == "?" || n < 1 || n > len(params) {
+			positional = false
+			return tok
+		}
+		ordered = append(ordered, params[n-1])
+		return "?"
+	})
+	if positional && len(ordered) > 0 {
+		return Condition{expr: rewritten, params: ordered}
+	}
+	return Condition{expr: expr, params: params}
+}
+
+// Eq holds when predicate equals value.
+func Eq(predicate string, value any) Condition {
+	return Cond("eq("+predicate+", ?)", value)
+}
+
+// Lt holds when predicate is less than value.
+func Lt(predicate string, value any) Condition {
+	return Cond("lt("+predicate+", ?)", value)
+}
+
+// Le holds when predicate is less than or equal to value.
+func Le(predicate string, value any) Condition {
+	return Cond("le("+predicate+", ?)", value)
+}
+
+// Gt holds when predicate is greater than value.
+func Gt(predicate string, value any) Condition {
+	return Cond("gt("+predicate+", ?)", value)
+}
+
+// Ge holds when predicate is greater than or equal to value.
+func Ge(predicate string, value any) Condition {
+	return Cond("ge("+predicate+", ?)", value)
+}
+
+// Has holds when the node has a value for predicate.
+func Has(predicate string) Condition {
+	return Cond("has(" + predicate + ")")
+}
+
+// And holds when every one of conds holds.
+func And(conds ...Condition) Condition {
+	return joinConditions(" AND ", conds)
+}
+
+// Or holds when any one of conds holds.
+func Or(conds ...Condition) Condition {
+	return joinConditions(" OR ", conds)
+}
+
+// Not holds when cond does not.
+func Not(cond Condition) Condition {
+	return Condition{expr: "NOT (" + cond.expr + ")", params: cond.params}
+}
+
+func joinConditions(op string, conds []Condition) Condition {
+	var joined Condition
+	parts := make([]string, len(conds))
+	for i, c := range conds {
+		parts[i] = "(" + c.expr + ")"
+		joined.params = append(joined.params, c.params...)
+	}
+	joined.expr = strings.Join(parts, op)
+	return joined
+}
+
+// String returns the condition's expression with its placeholders.
+func (c Condition) String() string {
+	return c.expr
+}
+
+// render returns the condition as DQL with its parameters in place.
+func (c Condition) render() (string, error) {
+	if strings.TrimSpace(c.expr) == "" {
+		return "", fmt.Errorf("%w: empty condition", ErrFilterParam)
+	}
+	expr, lits, err := BindFilter(c.expr, c.params...)
+	if err != nil {
+		return "", err
+	}
+	var missing error
+	expr = rewritePlaceholders(expr, func(tok string) string {
+		n, _ := strconv.Atoi(tok[1:])
+		if n < 1 || n > len(lits) {
+			missing = fmt.Errorf("%w: %s has no parameter", ErrFilterParam, tok)
+			return tok
+		}
+		return string(lits[n-1].(filterLiteral))
+	})
+	return expr, missing
+}
+
+// errConditionFailed stops UpsertIf's write when the stored node fails the
+// condition.
+var errConditionFailed = errors.New("condition not met")
+
+// UpsertIf implements a compare-and-set upsert: the node of obj's type whose
+// key predicate equals obj's is updated only if it satisfies cond, and
+// inserted if there is none. It reports whether obj was written.
+func (c client) UpsertIf(ctx context.Context, obj any, key string, cond Condition) (applied bool, err error) {
+	if err := c.writable(); err != nil {
+		return false, err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	obj = UnwrapSchema(obj)
+	if err := checkPointer(obj); err != nil {
+		return false, err
+	}
+	sv := reflect.ValueOf(obj).Elem()
+	if sv.Kind() != reflect.Struct {
+		return false, errors.New("UpsertIf: object must be a pointer to a struct")
+	}
+	if key == "" {
+		key = firstUpsertPredicate(obj)
+	}
+	if key == "" {
+		return false, fmt.Errorf("UpsertIf: no key predicate (pass one or tag a field dgraph:\"upsert\")")
+	}
+	keyValue, ok := predicateValue(sv, key)
+	if !ok {
+		return false, fmt.Errorf("UpsertIf: %s has no value for key predicate %s", sv.Type().Name(), key)
+	}
+	match, err := Eq(key, keyValue).render()
+	if err != nil {
+		return false, err
+	}
+	condition, err := cond.render()
+	if err != nil {
+		return false, err
+	}
+
+	return c.upsertWhere(ctx, obj, "UpsertIf", match, condition, key, upsertOptions{})
+}
+
+// UpsertOpt restricts an UpsertWhere.
+type UpsertOpt func(*upsertOptions)
+
+type upsertOptions struct {
+	insertOnly bool
+	updateOnly bool
+}
+
+// InsertOnly makes UpsertWhere write only when no node matches: an
+// insert-if-not-exists.
+func InsertOnly() UpsertOpt {
+	return func(o *upsertOptions) {
+		o.insertOnly = true
+	}
+}
+
+// UpdateOnly makes UpsertWhere write only when a node matches: an
+// update-if-exists.
+func UpdateOnly() UpsertOpt {
+	return func(o *upsertOptions) {
+		o.updateOnly = true
+	}
+}
+
+// UpsertWhere implements an upsert matching the stored node with a
+// condition rather than an upsert predicate.
+func (c client) UpsertWhere(ctx context.Context, obj any, match Condition,
+	opts ...UpsertOpt) (applied bool, err error) {
+	if err := c.writable(); err != nil {
+		return false, err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	obj = UnwrapSchema(obj)
+	if err := checkPointer(obj); err != nil {
+		return false, err
+	}
+	if reflect.ValueOf(obj).Elem().Kind() != reflect.Struct {
+		return false, errors.New("UpsertWhere: object must be a pointer to a struct")
+	}
+	var o upsertOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.insertOnly && o.updateOnly {
+		return false, errors.New("UpsertWhere: InsertOnly and UpdateOnly exclude each other")
+	}
+	expr, err := match.render()
+	if err != nil {
+		return false, err
+	}
+	return c.upsertWhere(ctx, obj, "UpsertWhere", expr, "", "", o)
+}
+
+// upsertWhere writes obj, a pointer to a struct, over the node of its type
+// matching the DQL filter match, if that node also satisfies condition, or
+// inserts it when no node matches. An empty condition always holds. key is
+// the upsert predicate match compares, if any, which is not checked against
+// obj's unique groups. It reports whether obj was written.
+func (c client) upsertWhere(ctx context.Context, obj any, operation, match, condition, key string,
+	o upsertOptions) (bool, error) {
+	sv := reflect.ValueOf(obj).Elem()
+	if err := c.applyComputed(ctx, obj); err != nil {
+		return false, err
+	}
+	NormalizeTimes(c, obj)
+	if err := c.validateStruct(ctx, obj); err != nil {
+		return false, err
+	}
+
+	// The embedded engine does no commit-time conflict check, so serialize
+	// the read-check-write section as LoadAndDelete does.
+	if c.engine != nil && c.consumeMu != nil {
+		c.consumeMu.Lock()
+		defer c.consumeMu.Unlock()
+	}
+
+	nodeType := getNodeType(obj)
+	checked := match
+	if condition != "" {
+		checked = fmt.Sprintf("%s AND (%s)", match, condition)
+	}
+	query := fmt.Sprintf(`{
+  q(func: type(%s), first: 2) @filter(%s) { uid }
+  c(func: type(%s), first: 1) @filter(%s) { uid }
+}`, nodeType, match, nodeType, checked)
+
+	err := c.retryUpsert(ctx, obj, func() error {
+		return c.process(ctx, obj, operation, func(tx *dg.TxnContext, obj any) ([]string, error) {
+			defer c.lockUniqueGroups(obj)()
+			resp, err := tx.Txn().Query(ctx, query)
+			if err != nil {
+				return nil, fmt.Errorf("evaluating condition: %w", err)
+			}
+			var found struct {
+				Q []struct{ UID string } `json:"q"`
+				C []struct{ UID string } `json:"c"`
+			}
+			if err := json.Unmarshal(resp.Json, &found); err != nil {
+				return nil, err
+			}
+			switch {
+			case len(found.Q) > 1:
+				return nil, fmt.Errorf("%s: more than one %s matches %s", operation, nodeType, match)
+			case len(found.Q) == 0:
+				// No node matches yet: insert.
+				if o.updateOnly {
+					return nil, errConditionFailed
+				}
+			case len(found.C) == 0 || o.insertOnly:
+				return nil, errConditionFailed
+			default:
+				uid := sv.FieldByName("UID")
+				if !uid.IsValid() || uid.Kind() != reflect.String {
+					return nil, fmt.Errorf("type %s has no UID field", sv.Type().Name())
+				}
+				uid.SetString(found.C[0].UID)
+			}
+			if err := c.checkUniqueGroups(ctx, tx, obj, key); err != nil {
+				return nil, err
+			}
+			return tx.MutateBasic(obj)
+		})
+	})
+	if errors.Is(err, errConditionFailed) {
+		return false, nil
+	}
+	return err == nil, err
+}
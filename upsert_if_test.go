@@ -0,0 +1,189 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type VersionedFilm struct {
+	UID     string   `json:"uid,omitempty"`
+	FilmID  string   `json:"vfilm_id,omitempty" dgraph:"index=exact upsert"`
+	Title   string   `json:"vfilm_title,omitempty"`
+	Version int      `json:"vfilm_version,omitempty" dgraph:"index=int"`
+	DType   []string `json:"dgraph.type,omitempty"`
+}
+
+func TestUpsertIf(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "UpsertIfWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "UpsertIfWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			ctx := context.Background()
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			newer := func(f *VersionedFilm) mg.Condition {
+				return mg.Lt("vfilm_version", f.Version)
+			}
+
+			// With no node holding the key, the film is inserted.
+			film := &VersionedFilm{FilmID: "f1", Title: "Draft", Version: 1}
+			applied, err := client.UpsertIf(ctx, film, "vfilm_id", newer(film))
+			require.NoError(t, err)
+			require.True(t, applied)
+			require.NotEmpty(t, film.UID)
+
+			// A newer version replaces it.
+			v3 := &VersionedFilm{FilmID: "f1", Title: "Final", Version: 3}
+			applied, err = client.UpsertIf(ctx, v3, "", newer(v3))
+			require.NoError(t, err)
+			require.True(t, applied)
+			require.Equal(t, film.UID, v3.UID)
+
+			// A stale version is dropped and leaves the stored node alone.
+			v2 := &VersionedFilm{FilmID: "f1", Title: "Stale", Version: 2}
+			applied, err = client.UpsertIf(ctx, v2, "vfilm_id", newer(v2))
+			require.NoError(t, err)
+			require.False(t, applied)
+			require.Empty(t, v2.UID)
+
+			var got VersionedFilm
+			require.NoError(t, client.Get(ctx, &got, film.UID))
+			require.Equal(t, "Final", got.Title)
+			require.Equal(t, 3, got.Version)
+
+			// Conditions compose, and hostile values stay parameters.
+			v4 := &VersionedFilm{FilmID: "f1", Title: "Recut", Version: 4}
+			cond := mg.And(newer(v4), mg.Or(
+				mg.Eq("vfilm_title", `Final") OR has(vfilm_id`),
+				mg.Cond(`eq(vfilm_title, $1)`, "Final"),
+			))
+			applied, err = client.UpsertIf(ctx, v4, "vfilm_id", cond)
+			require.NoError(t, err)
+			require.True(t, applied)
+			applied, err = client.UpsertIf(ctx, &VersionedFilm{FilmID: "f1", Version: 9}, "vfilm_id",
+				mg.Eq("vfilm_title", `x") OR has(vfilm_id`))
+			require.NoError(t, err)
+			require.False(t, applied)
+
+			var films []VersionedFilm
+			require.NoError(t, client.Query(ctx, VersionedFilm{}).Nodes(&films))
+			require.Len(t, films, 1)
+			require.Equal(t, "Recut", films[0].Title)
+
+			_, err = client.UpsertIf(ctx, &VersionedFilm{FilmID: "f1"}, "vfilm_id", mg.Cond("lt(vfilm_version, ?)"))
+			require.ErrorIs(t, err, mg.ErrFilterParam)
+			_, err = client.UpsertIf(ctx, &VersionedFilm{Title: "no key"}, "vfilm_id", mg.Has("vfilm_title"))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestUpsertWhere(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "UpsertWhereWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "UpsertWhereWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			ctx := context.Background()
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+
+			// Update-only does nothing while no node matches.
+			film := &VersionedFilm{FilmID: "w1", Title: "Draft", Version: 1}
+			applied, err := client.UpsertWhere(ctx, film, mg.Eq("vfilm_id", "w1"), mg.UpdateOnly())
+			require.NoError(t, err)
+			require.False(t, applied)
+			require.Empty(t, film.UID)
+
+			// Insert-only inserts, then leaves the node alone.
+			applied, err = client.UpsertWhere(ctx, film, mg.Eq("vfilm_id", "w1"), mg.InsertOnly())
+			require.NoError(t, err)
+			require.True(t, applied)
+			require.NotEmpty(t, film.UID)
+
+			again := &VersionedFilm{FilmID: "w1", Title: "Again", Version: 2}
+			applied, err = client.UpsertWhere(ctx, again, mg.Eq("vfilm_id", "w1"), mg.InsertOnly())
+			require.NoError(t, err)
+			require.False(t, applied)
+			require.Empty(t, again.UID)
+
+			// Update-only updates the node a compound condition matches.
+			update := &VersionedFilm{FilmID: "w1", Title: "Final", Version: 3}
+			applied, err = client.UpsertWhere(ctx, update,
+				mg.And(mg.Eq("vfilm_id", "w1"), mg.Lt("vfilm_version", 3)), mg.UpdateOnly())
+			require.NoError(t, err)
+			require.True(t, applied)
+			require.Equal(t, film.UID, update.UID)
+
+			var got VersionedFilm
+			require.NoError(t, client.Get(ctx, &got, film.UID))
+			require.Equal(t, "Final", got.Title)
+			require.Equal(t, 3, got.Version)
+
+			// Without options it inserts or updates.
+			other := &VersionedFilm{FilmID: "w2", Title: "Other", Version: 1}
+			applied, err = client.UpsertWhere(ctx, other, mg.Eq("vfilm_id", "w2"))
+			require.NoError(t, err)
+			require.True(t, applied)
+			require.NotEqual(t, film.UID, other.UID)
+
+			var films []VersionedFilm
+			require.NoError(t, client.Query(ctx, VersionedFilm{}).Nodes(&films))
+			require.Len(t, films, 2)
+
+			_, err = client.UpsertWhere(ctx, &VersionedFilm{Title: "any"}, mg.Has("vfilm_id"))
+			require.ErrorContains(t, err, "more than one VersionedFilm")
+			_, err = client.UpsertWhere(ctx, &VersionedFilm{}, mg.Has("vfilm_id"), mg.InsertOnly(), mg.UpdateOnly())
+			require.Error(t, err)
+			_, err = client.UpsertWhere(ctx, VersionedFilm{}, mg.Has("vfilm_id"))
+			require.Error(t, err)
+		})
+	}
+}
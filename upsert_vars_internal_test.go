@@ -0,0 +1,63 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformUpsertQueryNamedBlocks(t *testing.T) {
+	query, vars := transformUpsertQuery(`{
+  people(func: has(name)) @filter(eq(team, "x")) { p as uid }
+  q_1_0(func: eq(id, "a")) { u_1_0 as uid }
+}`)
+	require.NotContains(t, query, " as uid")
+	require.Equal(t, map[string]string{"people": "p", "q_1_0": "u_1_0"}, vars)
+}
+
+func TestTransformUpsertQueryVarBlocks(t *testing.T) {
+	query, vars := transformUpsertQuery(`{
+  v as var(func: eq(name, "a) b")) @filter(has(team)) @cascade
+  w as var(func: has(team)) { team }
+  x as var(func: has(team)) { member { uid } uid }
+}`)
+	require.Equal(t, `{
+  v(func: eq(name, "a) b")) @filter(has(team)) @cascade { uid }
+  w(func: has(team)) { uid team }
+  x(func: has(team)) { member { uid } uid }
+}`, query)
+	require.Empty(t, vars)
+}
+
+func TestExpandNquadVars(t *testing.T) {
+	vars := map[string][]string{"p": {"0x1", "0x2"}, "t": {"0x9"}, "none": nil}
+	nquads := []byte(`uid(p) <team> uid(t) .
+uid(none) <team> "x" .
+uid(none) <member> uid(p) .
+_:new <name> "uid(p) stays" .
+uid(p) <label> "a" .`)
+
+	// Set text gives a variable that matched nothing one new node.
+	require.Equal(t, `<0x1> <team> <0x9> .
+<0x2> <team> <0x9> .
+_:uid.none <team> "x" .
+_:uid.none <member> <0x1> .
+_:uid.none <member> <0x2> .
+_:new <name> "uid(p) stays" .
+<0x1> <label> "a" .
+<0x2> <label> "a" .
+`, string(expandNquadVars(nquads, vars, true)))
+
+	// Delete text drops its lines.
+	require.Equal(t, `<0x1> <team> <0x9> .
+<0x2> <team> <0x9> .
+_:new <name> "uid(p) stays" .
+<0x1> <label> "a" .
+<0x2> <label> "a" .
+`, string(expandNquadVars(nquads, vars, false)))
+}
@@ -0,0 +1,487 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/go-logr/logr"
+)
+
+// SyncConflict describes predicates of a node that changed both locally and
+// upstream since the node was last synced.
+//
+// Local, Remote and Base hold the conflicting predicates' values: the local
+// store's, the upstream's, and those the last sync wrote upstream. A
+// predicate without a value is absent from the map. Edges are {"uid": ...}
+// objects in upstream UIDs; a local target not yet synced has a blank node
+// UID.
+type SyncConflict struct {
+	Event     ChangeEvent
+	RemoteUID string
+	Local     map[string]any
+	Remote    map[string]any
+	Base      map[string]any
+}
+
+// ConflictPolicy resolves a SyncConflict, returning the values to write
+// upstream for its predicates. A predicate missing from the result is
+// deleted upstream. An error is retried like a failed write, so a policy that
+// cannot decide yet can return one to hold the sync back.
+type ConflictPolicy func(ctx context.Context, conflict SyncConflict) (map[string]any, error)
+
+// LocalWins is the ConflictPolicy that overwrites upstream with the local
+// values. It is the default.
+func LocalWins(_ context.Context, conflict SyncConflict) (map[string]any, error) {
+	return conflict.Local, nil
+}
+
+// RemoteWins is the ConflictPolicy that keeps the upstream values.
+func RemoteWins(_ context.Context, conflict SyncConflict) (map[string]any, error) {
+	return conflict.Remote, nil
+}
+
+// UpstreamSyncOptions configures NewUpstreamSync.
+//
+// Checkpoint is the file recording the Seq of the last synced event, and
+// State the file mapping local UIDs to upstream ones along with the values
+// last written for each node. They default to upstream.checkpoint and
+// upstream.state in the WithChangelog directory.
+//
+// BatchSize caps how many events are read at once; it defaults to 100.
+// Conflict resolves predicates changed on both sides; it defaults to
+// LocalWins.
+type UpstreamSyncOptions struct {
+	Checkpoint string
+	State      string
+	BatchSize  int
+	Conflict   ConflictPolicy
+}
+
+// UpstreamSync replays the changes of a local client, typically a file://
+// store WithChangelog, to an upstream client such as a dgraph:// cluster. The
+// local store stays the primary: writes succeed offline and queue in the
+// changelog, and Run pushes them upstream in order, retrying with backoff
+// until the upstream is reachable again.
+//
+// Each event copies the current local values of the predicates it names, so
+// replaying an event twice or out of date converges on the local state.
+// Nodes created locally are created upstream and their UIDs mapped, and edges
+// are rewritten to the mapped UIDs. Whole-node deletes delete the mapped
+// node. A node whose predicates changed upstream since they were last synced
+// goes through the ConflictPolicy.
+//
+// The upstream needs the local schema. Like KafkaSink, delivery is
+// at-least-once; a crash between an upstream commit and the state write can
+// create an inserted node twice.
+type UpstreamSync struct {
+	local    client
+	upstream Client
+	cursor   changeCursor
+	state    string
+	conflict ConflictPolicy
+	logger   logr.Logger
+	nodes    map[string]*syncNode
+}
+
+// syncNode is the upstream counterpart of a local node.
+type syncNode struct {
+	UID  string         `json:"uid"`
+	Base map[string]any `json:"base,omitempty"`
+}
+
+// syncLabelPrefix prefixes the blank node label of a local UID in upstream
+// mutations.
+const syncLabelPrefix = "local"
+
+// NewUpstreamSync creates a sync from local to upstream. local must have been
+// created WithChangelog. Call Run to start syncing.
+func NewUpstreamSync(local, upstream Client, opts UpstreamSyncOptions) (*UpstreamSync, error) {
+	mc, ok := local.(client)
+	if !ok || mc.changes == nil {
+		return nil, ErrChangelogDisabled
+	}
+	if upstream == nil {
+		return nil, errors.New("modusgraph: upstream sync requires an upstream client")
+	}
+	s := &UpstreamSync{
+		local:    mc,
+		upstream: upstream,
+		cursor:   newChangeCursor(mc.changes, opts.Checkpoint, "upstream.checkpoint", opts.BatchSize),
+		state:    opts.State,
+		conflict: opts.Conflict,
+		logger:   mc.logger,
+		nodes:    map[string]*syncNode{},
+	}
+	if s.state == "" {
+		s.state = filepath.Join(filepath.Dir(mc.changes.path), "upstream.state")
+	}
+	if s.conflict == nil {
+		s.conflict = LocalWins
+	}
+	data, err := os.ReadFile(s.state)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(data, &s.nodes); err != nil {
+			return nil, fmt.Errorf("modusgraph: invalid upstream sync state %s: %w", s.state, err)
+		}
+	}
+	return s, nil
+}
+
+// Run syncs events from the one after the checkpoint onward, waiting for new
+// commits once caught up, until ctx is done. A failed event is retried with
+// backoff rather than skipped, so Run returns only ctx's error or a changelog,
+// checkpoint or state I/O error. Run must not be called concurrently.
+func (s *UpstreamSync) Run(ctx context.Context) error {
+	return s.cursor.run(ctx, func(ctx context.Context, events []ChangeEvent) error {
+		var edges map[string]bool
+		err := retryDelivery(ctx, s.logger, func() error {
+			var err error
+			edges, err = s.edges(ctx)
+			return err
+		}, "sync", "upstream", "step", "schema")
+		if err != nil {
+			return err
+		}
+		for _, ev := range events {
+			var saveErr error
+			err := retryDelivery(ctx, s.logger, func() error {
+				synced, err := s.apply(ctx, ev, edges)
+				if synced {
+					saveErr = s.save()
+				}
+				return err
+			}, "sync", "upstream", "seq", ev.Seq, "uid", ev.UID)
+			if saveErr != nil {
+				return saveErr
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Pending returns how many committed events have not been synced yet.
+func (s *UpstreamSync) Pending() (uint64, error) {
+	after, err := s.cursor.read()
+	if err != nil {
+		return 0, err
+	}
+	head, _ := s.cursor.changes.head()
+	if after >= head {
+		return 0, nil
+	}
+	return head - after, nil
+}
+
+// edges returns the local uid predicates.
+func (s *UpstreamSync) edges(ctx context.Context) (map[string]bool, error) {
+	schema, err := s.local.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	edges := map[string]bool{}
+	for _, p := range schema.Predicates {
+		if p.Type == "uid" {
+			edges[p.Name] = true
+		}
+	}
+	return edges, nil
+}
+
+// apply writes one event upstream, reporting whether it changed the sync
+// state.
+func (s *UpstreamSync) apply(ctx context.Context, ev ChangeEvent, edges map[string]bool) (bool, error) {
+	if ev.UID == "" {
+		s.logger.V(1).Info("Skipping change without a UID", "seq", ev.Seq)
+		return false, nil
+	}
+	node := s.nodes[ev.UID]
+	if ev.Op == ChangeDelete && len(ev.Predicates) == 0 && node == nil {
+		return false, nil
+	}
+
+	dg, cleanup, err := s.upstream.DgraphClient()
+	defer cleanup()
+	if err != nil {
+		return false, err
+	}
+	txn := dg.NewTxn()
+	defer func() { _ = txn.Discard(ctx) }()
+
+	if ev.Op == ChangeDelete && len(ev.Predicates) == 0 {
+		del, err := json.Marshal(map[string]any{"uid": node.UID})
+		if err != nil {
+			return false, err
+		}
+		if _, err := txn.Mutate(ctx, &api.Mutation{DeleteJson: del, CommitNow: true}); err != nil {
+			return false, err
+		}
+		delete(s.nodes, ev.UID)
+		return true, nil
+	}
+
+	types, local, err := readSyncNode(ctx, func(q string) ([]byte, error) {
+		return s.local.QueryRaw(ctx, q, nil)
+	}, ev.UID, ev.Predicates, edges)
+	if err != nil {
+		return false, fmt.Errorf("reading local node %s: %w", ev.UID, err)
+	}
+	if node == nil && len(local) == 0 {
+		return false, nil // deleted before it was ever synced
+	}
+	for p, v := range local {
+		local[p] = mapSyncUIDs(v, s.remoteRef)
+	}
+
+	write := local
+	if node != nil {
+		_, remote, err := readSyncNode(ctx, func(q string) ([]byte, error) {
+			resp, err := txn.Query(ctx, q)
+			return resp.GetJson(), err
+		}, node.UID, ev.Predicates, edges)
+		if err != nil {
+			return false, fmt.Errorf("reading upstream node %s: %w", node.UID, err)
+		}
+		var conflicts []string
+		for _, p := range ev.Predicates {
+			if !sameSyncValue(remote[p], node.Base[p]) && !sameSyncValue(remote[p], local[p]) {
+				conflicts = append(conflicts, p)
+			}
+		}
+		if len(conflicts) > 0 {
+			resolved, err := s.conflict(ctx, SyncConflict{
+				Event:     ev,
+				RemoteUID: node.UID,
+				Local:     pickSyncValues(local, conflicts),
+				Remote:    pickSyncValues(remote, conflicts),
+				Base:      pickSyncValues(node.Base, conflicts),
+			})
+			if err != nil {
+				return false, fmt.Errorf("resolving conflict on %s: %w", ev.UID, err)
+			}
+			write = maps.Clone(local)
+			for _, p := range conflicts {
+				delete(write, p)
+				if v, ok := resolved[p]; ok {
+					write[p] = v
+				}
+			}
+		}
+
+		// Clear the predicates first so lists are replaced, not appended to.
+		del := map[string]any{"uid": node.UID}
+		for _, p := range ev.Predicates {
+			del[p] = nil
+		}
+		data, err := json.Marshal(del)
+		if err != nil {
+			return false, err
+		}
+		if _, err := txn.Mutate(ctx, &api.Mutation{DeleteJson: data}); err != nil {
+			return false, err
+		}
+	}
+
+	ref := "_:" + syncLabelPrefix + ev.UID
+	if node != nil {
+		ref = node.UID
+	}
+	var uids map[string]string
+	if len(write) > 0 {
+		set := maps.Clone(write)
+		set["uid"] = ref
+		if len(types) > 0 {
+			set["dgraph.type"] = types
+		}
+		data, err := json.Marshal(set)
+		if err != nil {
+			return false, err
+		}
+		resp, err := txn.Mutate(ctx, &api.Mutation{SetJson: data})
+		if err != nil {
+			return false, err
+		}
+		uids = resp.Uids
+	}
+	if err := txn.Commit(ctx); err != nil {
+		return false, err
+	}
+
+	for label, uid := range uids {
+		if localUID, ok := strings.CutPrefix(strings.TrimPrefix(label, "_:"), syncLabelPrefix); ok {
+			if s.nodes[localUID] == nil {
+				s.nodes[localUID] = &syncNode{UID: uid}
+			}
+		}
+	}
+	node = s.nodes[ev.UID]
+	if node.Base == nil {
+		node.Base = map[string]any{}
+	}
+	for _, p := range ev.Predicates {
+		delete(node.Base, p)
+		if v, ok := write[p]; ok {
+			node.Base[p] = mapSyncUIDs(v, func(uid string) string {
+				if label, ok := strings.CutPrefix(uid, "_:"); ok {
+					return uids[label]
+				}
+				return uid
+			})
+		}
+	}
+	return true, nil
+}
+
+// remoteRef maps a local UID to its upstream UID, or to a blank node that
+// creates it.
+func (s *UpstreamSync) remoteRef(uid string) string {
+	if node, ok := s.nodes[uid]; ok {
+		return node.UID
+	}
+	return "_:" + syncLabelPrefix + uid
+}
+
+// save writes the sync state atomically.
+func (s *UpstreamSync) save() error {
+	data, err := json.Marshal(s.nodes)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.state, data)
+}
+
+// readSyncNode reads a node's types and its values of preds through query,
+// with edges read as their targets' UIDs.
+func readSyncNode(ctx context.Context, query func(string) ([]byte, error), uid string,
+	preds []string, edges map[string]bool) ([]string, map[string]any, error) {
+	var sel strings.Builder
+	sel.WriteString("uid dgraph.type")
+	for _, p := range preds {
+		name, lang, tagged := strings.Cut(p, "@")
+		sel.WriteString(" <" + name + ">")
+		if tagged {
+			sel.WriteString("@" + lang)
+		}
+		if edges[name] {
+			sel.WriteString(" { uid }")
+		}
+	}
+	data, err := query(fmt.Sprintf("{ n(func: uid(%s)) { %s } }", uid, sel.String()))
+	if err != nil {
+		return nil, nil, err
+	}
+	var resp struct {
+		N []map[string]any `json:"n"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, nil, err
+	}
+	values := map[string]any{}
+	if len(resp.N) == 0 {
+		return nil, values, nil
+	}
+	var types []string
+	if ts, ok := resp.N[0]["dgraph.type"].([]any); ok {
+		for _, t := range ts {
+			if t, ok := t.(string); ok {
+				types = append(types, t)
+			}
+		}
+	}
+	for _, p := range preds {
+		if v, ok := resp.N[0][p]; ok {
+			values[p] = v
+		}
+	}
+	return types, values, nil
+}
+
+// mapSyncUIDs rewrites the UIDs of the edges in v with ref.
+func mapSyncUIDs(v any, ref func(string) string) any {
+	switch v := v.(type) {
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = mapSyncUIDs(e, ref)
+		}
+		return out
+	case map[string]any:
+		out := maps.Clone(v)
+		if uid, ok := v["uid"].(string); ok {
+			out["uid"] = ref(uid)
+		}
+		return out
+	}
+	return v
+}
+
+// sameSyncValue compares predicate values as JSON, ignoring list order.
+func sameSyncValue(a, b any) bool {
+	return reflect.DeepEqual(canonicalSyncValue(a), canonicalSyncValue(b))
+}
+
+func canonicalSyncValue(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return sortSyncLists(out)
+}
+
+func sortSyncLists(v any) any {
+	switch v := v.(type) {
+	case []any:
+		if len(v) == 0 {
+			return nil
+		}
+		for i, e := range v {
+			v[i] = sortSyncLists(e)
+		}
+		slices.SortFunc(v, func(a, b any) int {
+			da, _ := json.Marshal(a)
+			db, _ := json.Marshal(b)
+			return cmp.Compare(string(da), string(db))
+		})
+		return v
+	case map[string]any:
+		for k, e := range v {
+			v[k] = sortSyncLists(e)
+		}
+	}
+	return v
+}
+
+func pickSyncValues(values map[string]any, preds []string) map[string]any {
+	out := map[string]any{}
+	for _, p := range preds {
+		if v, ok := values[p]; ok {
+			out[p] = v
+		}
+	}
+	return out
+}
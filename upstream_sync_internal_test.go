@@ -0,0 +1,174 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/dgo/v250"
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	"github.com/stretchr/testify/require"
+)
+
+type SyncDirector struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"name,omitempty" dgraph:"index=exact"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type SyncFilm struct {
+	UID      string        `json:"uid,omitempty"`
+	Title    string        `json:"title,omitempty" dgraph:"index=exact"`
+	Tags     []string      `json:"tags,omitempty"`
+	Director *SyncDirector `json:"director,omitempty"`
+	DType    []string      `json:"dgraph.type,omitempty"`
+}
+
+// namespaceClient returns a client of a new namespace of c's engine, standing
+// in for a separate upstream cluster.
+func namespaceClient(t *testing.T, c client) client {
+	ns, err := c.engine.CreateNamespace()
+	require.NoError(t, err)
+	up := c
+	up.ns = ns
+	up.changes = nil
+	up.pool = newClientPool(1, func() (*dgo.Dgraph, error) {
+		//nolint:staticcheck // dgo.NewDgraphClient is deprecated but required for embedded client
+		return dgo.NewDgraphClient(newEmbeddedDgraphClient(c.engine, ns)), nil
+	}, c.logger)
+	return up
+}
+
+// syncAll runs s until every committed change has been synced.
+func syncAll(t *testing.T, s *UpstreamSync) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+	require.Eventually(t, func() bool {
+		n, err := s.Pending()
+		return err == nil && n == 0
+	}, 10*time.Second, 20*time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
+
+func upstreamFilms(t *testing.T, up Client) []SyncFilm {
+	t.Helper()
+	raw, err := up.QueryRaw(context.Background(),
+		`{ f(func: type(SyncFilm)) { uid title tags director { uid name } } }`, nil)
+	require.NoError(t, err)
+	var resp struct{ F []SyncFilm }
+	require.NoError(t, json.Unmarshal(raw, &resp))
+	return resp.F
+}
+
+func TestUpstreamSync(t *testing.T) {
+	logDir := t.TempDir()
+	c, err := NewClient("file://"+t.TempDir(), WithAutoSchema(true), WithChangelog(logDir))
+	require.NoError(t, err)
+	defer c.Close()
+	up := namespaceClient(t, c.(client))
+	ctx := context.Background()
+	require.NoError(t, up.UpdateSchema(ctx, &SyncFilm{}, &SyncDirector{}))
+
+	film := &SyncFilm{Title: "Alien", Tags: []string{"horror", "space"}, Director: &SyncDirector{Name: "Scott"}}
+	require.NoError(t, c.Insert(ctx, film))
+
+	// While the upstream is unreachable the changes stay queued.
+	offline, err := NewClient("dgraph://" + freeAddr(t))
+	require.NoError(t, err)
+	defer offline.Close()
+	s, err := NewUpstreamSync(c, offline, UpstreamSyncOptions{})
+	require.NoError(t, err)
+	runCtx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+	require.ErrorIs(t, s.Run(runCtx), context.DeadlineExceeded)
+	cancel()
+	pending, err := s.Pending()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, pending)
+
+	// Once it is back, a sync over the same checkpoint catches up.
+	var conflicts []SyncConflict
+	s, err = NewUpstreamSync(c, up, UpstreamSyncOptions{
+		Conflict: func(ctx context.Context, conflict SyncConflict) (map[string]any, error) {
+			conflicts = append(conflicts, conflict)
+			return RemoteWins(ctx, conflict)
+		},
+	})
+	require.NoError(t, err)
+	syncAll(t, s)
+	films := upstreamFilms(t, up)
+	require.Len(t, films, 1)
+	remote := films[0]
+	require.Equal(t, "Alien", remote.Title)
+	require.ElementsMatch(t, []string{"horror", "space"}, remote.Tags)
+	require.NotNil(t, remote.Director)
+	require.Equal(t, "Scott", remote.Director.Name)
+	require.NotEqual(t, film.UID, remote.UID)
+
+	// Updates go to the mapped node, whose lists end up matching the local
+	// ones.
+	film.Title = "Aliens"
+	film.Tags = []string{"action"}
+	require.NoError(t, c.Update(ctx, film))
+	syncAll(t, s)
+	films = upstreamFilms(t, up)
+	require.Len(t, films, 1)
+	require.Equal(t, remote.UID, films[0].UID)
+	require.Equal(t, "Aliens", films[0].Title)
+	require.ElementsMatch(t, []string{"horror", "space", "action"}, films[0].Tags)
+
+	// Removing a value locally replaces the whole list upstream.
+	dg, cleanup, err := c.DgraphClient()
+	require.NoError(t, err)
+	_, err = dg.NewTxn().Mutate(ctx, &api.Mutation{
+		DelNquads: []byte(`<` + film.UID + `> <tags> "horror" .`),
+		CommitNow: true,
+	})
+	cleanup()
+	require.NoError(t, err)
+	syncAll(t, s)
+	require.ElementsMatch(t, []string{"space", "action"}, upstreamFilms(t, up)[0].Tags)
+	require.Empty(t, conflicts)
+
+	// A title changed on both sides goes to the policy, which keeps the
+	// upstream's.
+	require.NoError(t, up.Update(ctx, &SyncFilm{UID: remote.UID, Title: "Alien 2"}))
+	film.Title = "Aliens: Special Edition"
+	require.NoError(t, c.Update(ctx, film))
+	syncAll(t, s)
+	require.Len(t, conflicts, 1)
+	require.Equal(t, remote.UID, conflicts[0].RemoteUID)
+	require.Equal(t, map[string]any{"title": "Aliens: Special Edition"}, conflicts[0].Local)
+	require.Equal(t, map[string]any{"title": "Alien 2"}, conflicts[0].Remote)
+	require.Equal(t, map[string]any{"title": "Aliens"}, conflicts[0].Base)
+	require.Equal(t, "Alien 2", upstreamFilms(t, up)[0].Title)
+
+	// Deleting the local node deletes the upstream one.
+	require.NoError(t, c.Delete(ctx, []string{film.UID}))
+	syncAll(t, s)
+	require.Empty(t, upstreamFilms(t, up))
+
+	// A restarted sync resumes from its checkpoint and state.
+	s, err = NewUpstreamSync(c, up, UpstreamSyncOptions{})
+	require.NoError(t, err)
+	require.Len(t, s.nodes, 1)
+	pending, err = s.Pending()
+	require.NoError(t, err)
+	require.Zero(t, pending)
+}
+
+func TestUpstreamSyncRequiresChangelog(t *testing.T) {
+	c, err := NewClient("file://" + t.TempDir())
+	require.NoError(t, err)
+	defer c.Close()
+	_, err = NewUpstreamSync(c, c, UpstreamSyncOptions{})
+	require.ErrorIs(t, err, ErrChangelogDisabled)
+}
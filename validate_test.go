@@ -104,6 +104,32 @@ func TestClientWithValidator(t *testing.T) {
 				assert.Empty(t, user.UID, "UID should not be assigned for failed validation")
 			})
 
+			t.Run("InvalidEntityReportsEveryField", func(t *testing.T) {
+				users := []*ValidatableUser{
+					{Name: "Ann", Email: "ann@example.com", Status: "active"},
+					{Name: "B", Email: "not-an-email", Age: 150, Status: "active"},
+				}
+
+				err := client.Insert(ctx, &users)
+				var ve *mg.ValidationError
+				require.ErrorAs(t, err, &ve)
+				require.Len(t, ve.Fields, 3)
+				assert.Equal(t, mg.FieldError{
+					Path: "[1].name", Predicate: "name", Field: "ValidatableUser.Name",
+					Constraint: "min", Param: "2", Value: "B",
+				}, ve.Fields[0])
+				assert.Equal(t, "[1].email", ve.Fields[1].Path)
+				assert.Equal(t, "email", ve.Fields[1].Constraint)
+				assert.Equal(t, "[1].age", ve.Fields[2].Path)
+				assert.Equal(t, "lte", ve.Fields[2].Constraint)
+				assert.Equal(t, "130", ve.Fields[2].Param)
+
+				// The validator's own error stays reachable.
+				var fieldErrs validator.ValidationErrors
+				require.ErrorAs(t, err, &fieldErrs)
+				assert.Empty(t, users[0].UID, "No element should be written")
+			})
+
 			t.Run("PartialInvalidEntityShouldFail", func(t *testing.T) {
 				user := ValidatableUser{
 					Name:   "J", // Invalid: name too short (min=2)
@@ -0,0 +1,134 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationError is returned by Insert, Upsert, Update and LoadOrStore when
+// the configured validator rejects a value or a dgraph:"required" field is
+// empty. It lists every failing field
+// rather than the first, so an API can map each failure back to the request
+// field it came from. errors.As still reaches the validator's own error
+// through Unwrap.
+type ValidationError struct {
+	Fields []FieldError
+
+	causes []error
+}
+
+// FieldError describes one failed constraint.
+type FieldError struct {
+	// Path is the field's location in the JSON encoding of the value, such as
+	// "email" or "friends[1].name". When a slice is written, paths start with
+	// the element index, as in "[2].email".
+	Path string `json:"path"`
+	// Predicate is the Dgraph predicate the field is stored under.
+	Predicate string `json:"predicate"`
+	// Field is the Go struct namespace, such as "User.Friends[1].Name".
+	Field string `json:"field"`
+	// Constraint is the name of the failed rule, such as "required" or "max".
+	Constraint string `json:"constraint"`
+	// Param is the rule's parameter, such as "100" for max=100, if any.
+	Param string `json:"param,omitempty"`
+	// Value is the rejected value.
+	Value any `json:"value,omitempty"`
+}
+
+// Error returns the validator's own messages, one per line.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.causes))
+	for i, err := range e.causes {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the validator errors the fields were read from.
+func (e *ValidationError) Unwrap() []error {
+	return e.causes
+}
+
+// newValidationError converts err into a ValidationError with paths resolved
+// against t, prefixed with prefix. Errors the validator did not report per
+// field are returned unchanged.
+func newValidationError(err error, t reflect.Type, prefix string) error {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+	ve := &ValidationError{causes: []error{err}}
+	for _, fe := range fieldErrs {
+		path, pred := fieldPath(t, fe.StructNamespace())
+		ve.Fields = append(ve.Fields, FieldError{
+			Path:       prefix + path,
+			Predicate:  pred,
+			Field:      fe.StructNamespace(),
+			Constraint: fe.Tag(),
+			Param:      fe.Param(),
+			Value:      fe.Value(),
+		})
+	}
+	return ve
+}
+
+// merge appends the fields of other, whose paths already carry their prefix.
+func (e *ValidationError) merge(other *ValidationError) {
+	e.Fields = append(e.Fields, other.Fields...)
+	e.causes = append(e.causes, other.causes...)
+}
+
+// fieldPath maps a validator struct namespace such as "User.Friends[1].Name"
+// onto the JSON path and predicate of the field it names, following the json
+// and dgraph tags of t. Segments that no longer match a field of t, as when a
+// SelfValidator validated a mirror struct, keep their Go names.
+func fieldPath(t reflect.Type, namespace string) (path, predicate string) {
+	segments := strings.Split(namespace, ".")[1:]
+	var b strings.Builder
+	for _, seg := range segments {
+		name, index, _ := strings.Cut(seg, "[")
+		if index != "" {
+			index = "[" + index
+		}
+		for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice ||
+			t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+			t = t.Elem()
+		}
+
+		jsonName, pred := name, name
+		var sf reflect.StructField
+		found := false
+		if t != nil && t.Kind() == reflect.Struct {
+			sf, found = t.FieldByName(name)
+		}
+		if found {
+			if sf.Anonymous && sf.Tag.Get("json") == "" {
+				// Embedded structs are flattened in JSON.
+				t = sf.Type
+				continue
+			}
+			if tag := strings.Split(sf.Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+				jsonName = tag
+			}
+			pred = fieldPredicate(sf)
+			t = sf.Type
+		} else {
+			t = nil
+		}
+
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(jsonName + index)
+		predicate = pred
+	}
+	return b.String(), predicate
+}
@@ -0,0 +1,47 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+type pathBase struct {
+	Label string `json:"label,omitempty"`
+}
+
+type pathFriend struct {
+	Nick string `json:"nick,omitempty" dgraph:"predicate=friend_nick"`
+}
+
+type pathUser struct {
+	pathBase
+	Friends []*pathFriend `json:"friends,omitempty"`
+	Home    pathFriend
+	Skip    string `json:"-"`
+}
+
+func TestFieldPath(t *testing.T) {
+	typ := reflect.TypeOf(pathUser{})
+	tests := []struct {
+		namespace string
+		path      string
+		predicate string
+	}{
+		{"pathUser.pathBase.Label", "label", "label"},
+		{"pathUser.Friends[2].Nick", "friends[2].nick", "friend_nick"},
+		{"pathUser.Home.Nick", "Home.nick", "friend_nick"},
+		{"pathUser.Skip", "Skip", ""},
+		{"mirror.Extra.Deep", "Extra.Deep", "Deep"},
+	}
+	for _, tt := range tests {
+		path, pred := fieldPath(typ, tt.namespace)
+		if path != tt.path || pred != tt.predicate {
+			t.Errorf("fieldPath(%q) = %q, %q; want %q, %q", tt.namespace, path, pred, tt.path, tt.predicate)
+		}
+	}
+}
@@ -0,0 +1,391 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package vectorstore adapts a modusGraph client to the LangChainGo
+// vectorstores.VectorStore interface, so RAG pipelines can keep their
+// documents in the embedded graph.
+//
+// Each document becomes a node of Options.Type holding its text, its
+// embedding in an hnsw-indexed float32vector predicate, and its metadata.
+// Metadata is stored twice: whole, as a JSON string returned with search
+// results, and flattened, as an exact-indexed list of key=value entries that
+// WithFilters matches against.
+//
+//	store, err := vectorstore.New(ctx, client, embedder, vectorstore.Options{})
+//	ids, err := store.AddDocuments(ctx, docs)
+//	docs, err := store.SimilaritySearch(ctx, "what is a graph?", 4,
+//	    vectorstores.WithFilters(map[string]any{"source": "wiki"}))
+package vectorstore
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dgraph-io/dgo/v250/protos/api"
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// Options names the type and predicates documents are stored under. Zero
+// values select the defaults, so stores sharing a database must differ in
+// Type and predicate names.
+type Options struct {
+	// Type is the Dgraph type of document nodes. It defaults to "Document".
+	Type string
+	// ContentPredicate holds the document text. It defaults to "content".
+	ContentPredicate string
+	// VectorPredicate holds the embedding. It defaults to "embedding".
+	VectorPredicate string
+	// MetadataPredicate holds the metadata as a JSON object. It defaults to
+	// "metadata"; the flattened filter entries go in "<MetadataPredicate>_kv".
+	MetadataPredicate string
+	// NamespacePredicate holds the namespace set by WithNameSpace. It
+	// defaults to "doc_namespace"; Dgraph reserves "namespace" in mutations.
+	NamespacePredicate string
+	// Metric is the hnsw distance metric: "cosine", "euclidean", or
+	// "dotproduct". It defaults to "cosine".
+	Metric string
+	// Overfetch multiplies the neighbours read from the index when a search
+	// is filtered, since the index ranks before filters apply. It defaults
+	// to 4.
+	Overfetch int
+}
+
+const defaultOverfetch = 4
+
+// Store is a vectorstores.VectorStore backed by a modusGraph client.
+type Store struct {
+	client   mg.Client
+	embedder embeddings.Embedder
+	opts     Options
+}
+
+var _ vectorstores.VectorStore = (*Store)(nil)
+
+// New returns a Store that embeds with embedder and applies the schema for
+// its predicates to client.
+func New(ctx context.Context, client mg.Client, embedder embeddings.Embedder, opts Options) (*Store, error) {
+	opts.Type = cmp.Or(opts.Type, "Document")
+	opts.ContentPredicate = cmp.Or(opts.ContentPredicate, "content")
+	opts.VectorPredicate = cmp.Or(opts.VectorPredicate, "embedding")
+	opts.MetadataPredicate = cmp.Or(opts.MetadataPredicate, "metadata")
+	opts.NamespacePredicate = cmp.Or(opts.NamespacePredicate, "doc_namespace")
+	opts.Metric = cmp.Or(opts.Metric, "cosine")
+	opts.Overfetch = cmp.Or(opts.Overfetch, defaultOverfetch)
+	switch opts.Metric {
+	case "cosine", "euclidean", "dotproduct":
+	default:
+		return nil, fmt.Errorf("vectorstore: unknown metric %q", opts.Metric)
+	}
+
+	s := &Store{client: client, embedder: embedder, opts: opts}
+	if err := client.AlterSchema(ctx, s.schema()); err != nil {
+		return nil, fmt.Errorf("vectorstore: apply schema: %w", err)
+	}
+	return s, nil
+}
+
+func (s *Store) kvPredicate() string {
+	return s.opts.MetadataPredicate + "_kv"
+}
+
+func (s *Store) schema() string {
+	o := s.opts
+	return fmt.Sprintf(`%s: string .
+%s: float32vector @index(hnsw(metric: "%s")) .
+%s: string .
+%s: [string] @index(exact) .
+%s: string @index(exact) .
+type %s {
+	%s
+	%s
+	%s
+	%s
+	%s
+}
+`, o.ContentPredicate, o.VectorPredicate, o.Metric, o.MetadataPredicate, s.kvPredicate(), o.NamespacePredicate,
+		o.Type, o.ContentPredicate, o.VectorPredicate, o.MetadataPredicate, s.kvPredicate(), o.NamespacePredicate)
+}
+
+func (s *Store) options(options []vectorstores.Option) (vectorstores.Options, error) {
+	o := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&o)
+	}
+	if o.Embedder == nil {
+		o.Embedder = s.embedder
+	}
+	if o.Embedder == nil {
+		return o, errors.New("vectorstore: no embedder; pass one to New or use vectorstores.WithEmbedder")
+	}
+	return o, nil
+}
+
+// AddDocuments embeds docs and stores each as a node, returning their UIDs
+// in order. Documents the WithDeduplicater function reports as duplicates are
+// skipped and have no entry in the result.
+func (s *Store) AddDocuments(ctx context.Context, docs []schema.Document,
+	options ...vectorstores.Option) ([]string, error) {
+	o, err := s.options(options)
+	if err != nil {
+		return nil, err
+	}
+	if o.Deduplicater != nil {
+		docs = slices.DeleteFunc(slices.Clone(docs), func(d schema.Document) bool {
+			return o.Deduplicater(ctx, d)
+		})
+	}
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	texts := make([]string, len(docs))
+	for i, d := range docs {
+		texts[i] = d.PageContent
+	}
+	vectors, err := o.Embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("vectorstore: embed documents: %w", err)
+	}
+	if len(vectors) != len(docs) {
+		return nil, fmt.Errorf("vectorstore: embedder returned %d vectors for %d documents", len(vectors), len(docs))
+	}
+
+	nodes := make([]map[string]any, len(docs))
+	for i, d := range docs {
+		node := map[string]any{
+			"uid":                   "_:doc" + strconv.Itoa(i),
+			"dgraph.type":           s.opts.Type,
+			s.opts.ContentPredicate: d.PageContent,
+			s.opts.VectorPredicate:  vectorString(vectors[i]),
+		}
+		if len(d.Metadata) > 0 {
+			meta, err := json.Marshal(d.Metadata)
+			if err != nil {
+				return nil, fmt.Errorf("vectorstore: document %d metadata: %w", i, err)
+			}
+			node[s.opts.MetadataPredicate] = string(meta)
+			kv, err := metadataEntries(d.Metadata)
+			if err != nil {
+				return nil, fmt.Errorf("vectorstore: document %d metadata: %w", i, err)
+			}
+			node[s.kvPredicate()] = kv
+		}
+		if o.NameSpace != "" {
+			node[s.opts.NamespacePredicate] = o.NameSpace
+		}
+		nodes[i] = node
+	}
+	body, err := json.Marshal(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	dgo, cleanup, err := s.client.DgraphClient()
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	resp, err := dgo.NewTxn().Mutate(ctx, &api.Mutation{SetJson: body, CommitNow: true})
+	if err != nil {
+		return nil, fmt.Errorf("vectorstore: store documents: %w", err)
+	}
+	ids := make([]string, len(docs))
+	for i := range docs {
+		ids[i] = resp.Uids["doc"+strconv.Itoa(i)]
+	}
+	return ids, nil
+}
+
+// SimilaritySearch returns the numDocuments documents nearest to query,
+// most similar first, with Score set to their similarity: cosine similarity,
+// the dot product, or 1/(1+distance) for euclidean. WithScoreThreshold drops
+// results scoring below it, WithNameSpace restricts the search to documents
+// added under the same namespace, and WithFilters takes a map[string]any of
+// metadata values a document must all match.
+func (s *Store) SimilaritySearch(ctx context.Context, query string, numDocuments int,
+	options ...vectorstores.Option) ([]schema.Document, error) {
+	o, err := s.options(options)
+	if err != nil {
+		return nil, err
+	}
+	if numDocuments <= 0 {
+		return nil, nil
+	}
+	filter, vars, err := s.filter(o)
+	if err != nil {
+		return nil, err
+	}
+	vec, err := o.Embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("vectorstore: embed query: %w", err)
+	}
+
+	k := numDocuments
+	if filter != "" {
+		k *= s.opts.Overfetch
+	}
+	vars["$vec"] = vectorString(vec)
+	decl := make([]string, 0, len(vars))
+	for name := range vars {
+		decl = append(decl, name+": string")
+	}
+	sort.Strings(decl)
+	q := fmt.Sprintf(`query docs(%s) {
+	docs(func: similar_to(<%s>, %d, $vec)) @filter(type(<%s>)%s) {
+		uid
+		content: <%s>
+		vector: <%s>
+		metadata: <%s>
+	}
+}`, strings.Join(decl, ", "), s.opts.VectorPredicate, k, s.opts.Type, filter,
+		s.opts.ContentPredicate, s.opts.VectorPredicate, s.opts.MetadataPredicate)
+
+	raw, err := s.client.QueryRaw(ctx, q, vars)
+	if err != nil {
+		return nil, fmt.Errorf("vectorstore: search: %w", err)
+	}
+	var resp struct {
+		Docs []struct {
+			Content  string          `json:"content"`
+			Vector   json.RawMessage `json:"vector"`
+			Metadata string          `json:"metadata"`
+		} `json:"docs"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("vectorstore: decode search: %w", err)
+	}
+
+	docs := make([]schema.Document, 0, len(resp.Docs))
+	for _, d := range resp.Docs {
+		stored, err := parseVector(d.Vector)
+		if err != nil {
+			return nil, fmt.Errorf("vectorstore: decode vector: %w", err)
+		}
+		score := s.score(vec, stored)
+		if o.ScoreThreshold != 0 && score < o.ScoreThreshold {
+			continue
+		}
+		doc := schema.Document{PageContent: d.Content, Score: score}
+		if d.Metadata != "" {
+			if err := json.Unmarshal([]byte(d.Metadata), &doc.Metadata); err != nil {
+				return nil, fmt.Errorf("vectorstore: decode metadata: %w", err)
+			}
+		}
+		docs = append(docs, doc)
+	}
+	sort.SliceStable(docs, func(i, j int) bool { return docs[i].Score > docs[j].Score })
+	if len(docs) > numDocuments {
+		docs = docs[:numDocuments]
+	}
+	return docs, nil
+}
+
+// filter renders the namespace and metadata conditions of o as " AND ..."
+// terms for the search's @filter, with their values as query variables.
+func (s *Store) filter(o vectorstores.Options) (string, map[string]string, error) {
+	vars := map[string]string{}
+	var terms []string
+	if o.NameSpace != "" {
+		vars["$ns"] = o.NameSpace
+		terms = append(terms, fmt.Sprintf("eq(<%s>, $ns)", s.opts.NamespacePredicate))
+	}
+	if o.Filters != nil {
+		filters, ok := o.Filters.(map[string]any)
+		if !ok {
+			return "", nil, fmt.Errorf("vectorstore: filters must be map[string]any, got %T", o.Filters)
+		}
+		entries, err := metadataEntries(filters)
+		if err != nil {
+			return "", nil, fmt.Errorf("vectorstore: filters: %w", err)
+		}
+		for i, entry := range entries {
+			name := "$f" + strconv.Itoa(i)
+			vars[name] = entry
+			terms = append(terms, fmt.Sprintf("eq(<%s>, %s)", s.kvPredicate(), name))
+		}
+	}
+	if len(terms) == 0 {
+		return "", vars, nil
+	}
+	return " AND " + strings.Join(terms, " AND "), vars, nil
+}
+
+// metadataEntries flattens the scalar values of m into sorted key=value
+// entries, the value JSON-encoded so that 1 and "1" stay distinct. Nested
+// objects and arrays are not filterable and are left out.
+func metadataEntries(m map[string]any) ([]string, error) {
+	entries := make([]string, 0, len(m))
+	for key, value := range m {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", key, err)
+		}
+		if len(encoded) > 0 && (encoded[0] == '{' || encoded[0] == '[') {
+			continue
+		}
+		entries = append(entries, key+"="+string(encoded))
+	}
+	sort.Strings(entries)
+	return entries, nil
+}
+
+func (s *Store) score(query, stored []float32) float32 {
+	var dot, qq, ss, dist float64
+	for i := range min(len(query), len(stored)) {
+		q, v := float64(query[i]), float64(stored[i])
+		dot += q * v
+		qq += q * q
+		ss += v * v
+		dist += (q - v) * (q - v)
+	}
+	switch s.opts.Metric {
+	case "dotproduct":
+		return float32(dot)
+	case "euclidean":
+		return float32(1 / (1 + math.Sqrt(dist)))
+	}
+	if qq == 0 || ss == 0 {
+		return 0
+	}
+	return float32(dot / math.Sqrt(qq*ss))
+}
+
+// vectorString formats vec the way Dgraph parses float32vector values.
+func vectorString(vec []float32) string {
+	parts := make([]string, len(vec))
+	for i, v := range vec {
+		parts[i] = strconv.FormatFloat(float64(v), 'g', -1, 32)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// parseVector decodes a float32vector from a query response, where it may be
+// a JSON array or a string holding one.
+func parseVector(raw json.RawMessage) ([]float32, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var vec []float32
+	if raw[0] == '"' {
+		var str string
+		if err := json.Unmarshal(raw, &str); err != nil {
+			return nil, err
+		}
+		raw = json.RawMessage(str)
+	}
+	err := json.Unmarshal(raw, &vec)
+	return vec, err
+}
@@ -0,0 +1,148 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package vectorstore_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/matthewmcneely/modusgraph"
+	"github.com/matthewmcneely/modusgraph/vectorstore"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// fakeEmbedder maps known texts to fixed vectors.
+type fakeEmbedder map[string][]float32
+
+func (f fakeEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vecs := make([][]float32, len(texts))
+	for i, text := range texts {
+		vec, err := f.EmbedQuery(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		vecs[i] = vec
+	}
+	return vecs, nil
+}
+
+func (f fakeEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	vec, ok := f[text]
+	if !ok {
+		return nil, fmt.Errorf("no vector for %q", text)
+	}
+	return vec, nil
+}
+
+var embedder = fakeEmbedder{
+	"graphs store nodes and edges": {0.9, 0.1, 0.1},
+	"tables store rows":            {0.1, 0.9, 0.1},
+	"vectors capture meaning":      {0.1, 0.1, 0.9},
+	"edges between nodes":          {0.8, 0.2, 0.1},
+	"rows and columns":             {0.2, 0.8, 0.1},
+}
+
+func newStore(t *testing.T) (*vectorstore.Store, context.Context) {
+	t.Helper()
+	client, err := modusgraph.NewClient("file://" + t.TempDir())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	ctx := context.Background()
+	store, err := vectorstore.New(ctx, client, embedder, vectorstore.Options{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ids, err := store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "graphs store nodes and edges", Metadata: map[string]any{"source": "wiki", "page": 1}},
+		{PageContent: "tables store rows", Metadata: map[string]any{"source": "book", "page": 7}},
+		{PageContent: "vectors capture meaning", Metadata: map[string]any{"source": "wiki", "page": 3}},
+	})
+	if err != nil {
+		t.Fatalf("AddDocuments: %v", err)
+	}
+	if len(ids) != 3 || ids[0] == "" || ids[2] == "" {
+		t.Fatalf("AddDocuments ids = %v", ids)
+	}
+	return store, ctx
+}
+
+func TestSimilaritySearch(t *testing.T) {
+	store, ctx := newStore(t)
+
+	docs, err := store.SimilaritySearch(ctx, "edges between nodes", 2)
+	if err != nil {
+		t.Fatalf("SimilaritySearch: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("got %d documents, want 2", len(docs))
+	}
+	if docs[0].PageContent != "graphs store nodes and edges" {
+		t.Errorf("nearest = %q", docs[0].PageContent)
+	}
+	if docs[0].Score < docs[1].Score || docs[0].Score < 0.9 {
+		t.Errorf("scores = %v, %v", docs[0].Score, docs[1].Score)
+	}
+	if docs[0].Metadata["source"] != "wiki" || docs[0].Metadata["page"] != float64(1) {
+		t.Errorf("metadata = %v", docs[0].Metadata)
+	}
+}
+
+func TestSimilaritySearchFilters(t *testing.T) {
+	store, ctx := newStore(t)
+
+	docs, err := store.SimilaritySearch(ctx, "rows and columns", 3,
+		vectorstores.WithFilters(map[string]any{"source": "wiki"}))
+	if err != nil {
+		t.Fatalf("SimilaritySearch: %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("got %d documents, want the 2 wiki documents", len(docs))
+	}
+	for _, d := range docs {
+		if d.Metadata["source"] != "wiki" {
+			t.Errorf("filtered result from %v", d.Metadata["source"])
+		}
+	}
+
+	docs, err = store.SimilaritySearch(ctx, "rows and columns", 3,
+		vectorstores.WithFilters(map[string]any{"source": "wiki", "page": 3}))
+	if err != nil {
+		t.Fatalf("SimilaritySearch: %v", err)
+	}
+	if len(docs) != 1 || docs[0].PageContent != "vectors capture meaning" {
+		t.Errorf("page filter returned %v", docs)
+	}
+
+	docs, err = store.SimilaritySearch(ctx, "rows and columns", 3, vectorstores.WithScoreThreshold(0.9))
+	if err != nil {
+		t.Fatalf("SimilaritySearch: %v", err)
+	}
+	if len(docs) != 1 || docs[0].PageContent != "tables store rows" {
+		t.Errorf("score threshold returned %v", docs)
+	}
+}
+
+func TestNameSpace(t *testing.T) {
+	store, ctx := newStore(t)
+
+	_, err := store.AddDocuments(ctx, []schema.Document{{PageContent: "rows and columns"}},
+		vectorstores.WithNameSpace("tenant-a"))
+	if err != nil {
+		t.Fatalf("AddDocuments: %v", err)
+	}
+	docs, err := store.SimilaritySearch(ctx, "tables store rows", 5, vectorstores.WithNameSpace("tenant-a"))
+	if err != nil {
+		t.Fatalf("SimilaritySearch: %v", err)
+	}
+	if len(docs) != 1 || docs[0].PageContent != "rows and columns" {
+		t.Errorf("namespaced search returned %v", docs)
+	}
+}
@@ -0,0 +1,315 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+	badgerpb "github.com/dgraph-io/badger/v4/pb"
+	"github.com/dgraph-io/dgraph/v25/worker"
+	"github.com/dgraph-io/dgraph/v25/x"
+)
+
+// defaultWatchInterval is how often a remote client's Watch polls unless
+// WithWatchInterval says otherwise.
+const defaultWatchInterval = 2 * time.Second
+
+// watchPageSize is how many nodes Watch reads per query.
+const watchPageSize = 1000
+
+// WithWatchInterval sets how often Watch polls a dgraph:// cluster for
+// changes. The default is two seconds. An embedded (file://) store is not
+// polled: Watch follows its writes as they are made.
+func WithWatchInterval(d time.Duration) ClientOpt {
+	return func(o *clientOptions) {
+		o.watchInterval = d
+	}
+}
+
+// Watch delivers an event for every node of the given types created,
+// changed or deleted after it is called, by whatever writer, until ctx is
+// done (yielding ctx's error last), the consumer stops, or a read fails. No
+// types means every type in the schema when Watch starts.
+//
+// Unlike Subscribe it needs no changelog: it compares each node with the
+// values it last saw, so it reports the result of writes, not the writes
+// themselves. An insert lists the node's predicates, an update those whose
+// values changed, and a delete none; a node that loses its watched type is
+// deleted too. Events carry no Seq or Actor, and CommittedAt is when the
+// change was seen. On an embedded store Watch follows the store's writes
+// and reads back only the nodes they touched; on a cluster it reads all
+// nodes of the types every WithWatchInterval, so several writes between two
+// polls make one event. Either way it keeps the values of every watched
+// node in memory.
+func (c client) Watch(ctx context.Context, types ...string) iter.Seq2[ChangeEvent, error] {
+	return func(yield func(ChangeEvent, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		var touched *touchedNodes
+		if c.engine != nil {
+			touched = followStore(ctx)
+		}
+		w := &watcher{c: c, types: types}
+		if len(w.types) == 0 {
+			schema, err := c.GetSchema(ctx)
+			if err != nil {
+				yield(ChangeEvent{}, err)
+				return
+			}
+			for _, t := range schema.Types {
+				if !isInternalName(t.Name) {
+					w.types = append(w.types, t.Name)
+				}
+			}
+		}
+		if _, err := w.poll(ctx, nil); err != nil {
+			yield(ChangeEvent{}, err)
+			return
+		}
+		interval := c.options.watchInterval
+		if interval <= 0 {
+			interval = defaultWatchInterval
+		}
+		for {
+			var uids []string
+			if touched != nil {
+				select {
+				case <-ctx.Done():
+					yield(ChangeEvent{}, ctx.Err())
+					return
+				case <-touched.wake:
+				}
+				if uids = touched.take(); len(uids) == 0 {
+					continue
+				}
+			} else {
+				select {
+				case <-ctx.Done():
+					yield(ChangeEvent{}, ctx.Err())
+					return
+				case <-time.After(interval):
+				}
+			}
+			events, err := w.poll(ctx, uids)
+			if err != nil {
+				yield(ChangeEvent{}, err)
+				return
+			}
+			for _, ev := range events {
+				if !yield(ev, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// touchedNodes collects the UIDs of the nodes an embedded store's writes
+// touched until Watch takes them.
+type touchedNodes struct {
+	mu   sync.Mutex
+	uids map[uint64]struct{}
+	wake chan struct{} // signalled when uids gains members
+}
+
+// followStore subscribes to the writes of the embedded store's data keys
+// until ctx is done.
+func followStore(ctx context.Context) *touchedNodes {
+	t := &touchedNodes{uids: make(map[uint64]struct{}), wake: make(chan struct{}, 1)}
+	go func() {
+		_ = worker.State.Pstore.Subscribe(ctx, func(kvs *badger.KVList) error {
+			t.mu.Lock()
+			n := len(t.uids)
+			for _, kv := range kvs.Kv {
+				if pk, err := x.Parse(kv.Key); err == nil && pk.IsData() {
+					t.uids[pk.Uid] = struct{}{}
+				}
+			}
+			grew := len(t.uids) > n
+			t.mu.Unlock()
+			if grew {
+				select {
+				case t.wake <- struct{}{}:
+				default:
+				}
+			}
+			return nil
+		}, []badgerpb.Match{{Prefix: []byte{x.DefaultPrefix}}})
+	}()
+	return t
+}
+
+// take returns and forgets the touched UIDs.
+func (t *touchedNodes) take() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	uids := make([]string, 0, len(t.uids))
+	for uid := range t.uids {
+		uids = append(uids, string(UIDFromUint64(uid)))
+	}
+	clear(t.uids)
+	return uids
+}
+
+// watchedNode is what Watch last saw of a node: its watched type and the
+// JSON of each of its predicates.
+type watchedNode struct {
+	typ    string
+	values map[string]string
+}
+
+// watcher holds the nodes a Watch call has seen, by UID.
+type watcher struct {
+	c     client
+	types []string
+	nodes map[string]watchedNode
+}
+
+// poll reads the given nodes, or all nodes of the watched types when uids is
+// nil, and returns the changes since they were last read, ordered by UID.
+// The first poll only records the nodes.
+func (w *watcher) poll(ctx context.Context, uids []string) ([]ChangeEvent, error) {
+	read := make(map[string]watchedNode)
+	checked := uids
+	if uids == nil {
+		for _, t := range w.types {
+			if err := w.scanType(ctx, t, read); err != nil {
+				return nil, err
+			}
+		}
+		checked = slices.Collect(maps.Keys(read))
+		for uid := range w.nodes {
+			if _, ok := read[uid]; !ok {
+				checked = append(checked, uid)
+			}
+		}
+	} else {
+		for chunk := range slices.Chunk(uids, watchPageSize) {
+			q := fmt.Sprintf("{\n  q(func: uid(%s)) { uid dgraph.type expand(_all_) { uid } }\n}",
+				strings.Join(chunk, ", "))
+			if _, err := w.read(ctx, q, read); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if w.nodes == nil {
+		w.nodes = read
+		return nil, nil
+	}
+
+	slices.SortFunc(checked, func(a, b string) int {
+		ua, _ := strconv.ParseUint(strings.TrimPrefix(a, "0x"), 16, 64)
+		ub, _ := strconv.ParseUint(strings.TrimPrefix(b, "0x"), 16, 64)
+		return cmp.Compare(ua, ub)
+	})
+	now := w.c.Now().UTC()
+	var events []ChangeEvent
+	for _, uid := range checked {
+		old, had := w.nodes[uid]
+		cur, has := read[uid]
+		switch {
+		case has && !had:
+			events = append(events, ChangeEvent{Op: ChangeInsert, Type: cur.typ, UID: uid,
+				Predicates: slices.Sorted(maps.Keys(cur.values)), CommittedAt: now})
+		case had && !has:
+			events = append(events, ChangeEvent{Op: ChangeDelete, Type: old.typ, UID: uid, CommittedAt: now})
+			delete(w.nodes, uid)
+			continue
+		case has:
+			var changed []string
+			for pred, v := range cur.values {
+				if old.values[pred] != v {
+					changed = append(changed, pred)
+				}
+			}
+			for pred := range old.values {
+				if _, ok := cur.values[pred]; !ok {
+					changed = append(changed, pred)
+				}
+			}
+			if len(changed) == 0 && cur.typ == old.typ {
+				continue
+			}
+			slices.Sort(changed)
+			events = append(events, ChangeEvent{Op: ChangeUpdate, Type: cur.typ, UID: uid,
+				Predicates: changed, CommittedAt: now})
+		default:
+			continue
+		}
+		w.nodes[uid] = cur
+	}
+	return events, nil
+}
+
+// scanType reads every node of type t into read, a page at a time.
+func (w *watcher) scanType(ctx context.Context, t string, read map[string]watchedNode) error {
+	after := "0x0"
+	for {
+		q := fmt.Sprintf("{\n  q(func: type(%s), first: %d, after: %s) { uid dgraph.type expand(_all_) { uid } }\n}",
+			t, watchPageSize, after)
+		last, err := w.read(ctx, q, read)
+		if err != nil {
+			return err
+		}
+		if last == "" {
+			return nil
+		}
+		after = last
+	}
+}
+
+// read runs q and records the nodes of a watched type it returns in read. It
+// returns the UID of the last node of a full page, for paging on.
+func (w *watcher) read(ctx context.Context, q string, read map[string]watchedNode) (string, error) {
+	data, err := w.c.QueryRaw(ctx, q, nil)
+	if err != nil {
+		return "", fmt.Errorf("watching %s: %w", strings.Join(w.types, ", "), err)
+	}
+	var resp struct {
+		Q []map[string]json.RawMessage `json:"q"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return "", fmt.Errorf("watching %s: %w", strings.Join(w.types, ", "), err)
+	}
+	var uid string
+	for _, fields := range resp.Q {
+		var nodeTypes []string
+		if err := json.Unmarshal(fields["uid"], &uid); err != nil {
+			return "", err
+		}
+		if raw, ok := fields["dgraph.type"]; ok {
+			if err := json.Unmarshal(raw, &nodeTypes); err != nil {
+				return "", err
+			}
+		}
+		i := slices.IndexFunc(w.types, func(t string) bool { return slices.Contains(nodeTypes, t) })
+		if i < 0 {
+			continue
+		}
+		node := watchedNode{typ: w.types[i], values: make(map[string]string, len(fields))}
+		for pred, v := range fields {
+			if pred != "uid" && pred != "dgraph.type" {
+				node.values[pred] = string(v)
+			}
+		}
+		read[uid] = node
+	}
+	if len(resp.Q) < watchPageSize {
+		return "", nil
+	}
+	return uid, nil
+}
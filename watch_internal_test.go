@@ -0,0 +1,55 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestWatcherPoll covers the full scans a remote client's Watch polls with.
+func TestWatcherPoll(t *testing.T) {
+	c, err := NewClient("file://"+t.TempDir(), WithAutoSchema(true))
+	require.NoError(t, err)
+	defer c.Close()
+	ctx := context.Background()
+
+	kept := &SyncFilm{Title: "Alien"}
+	gone := &SyncFilm{Title: "Heat"}
+	require.NoError(t, c.Insert(ctx, []*SyncFilm{kept, gone}))
+	w := &watcher{c: c.(client), types: []string{"SyncFilm"}}
+	events, err := w.poll(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	// Several writes between two polls make one event per node.
+	added := &SyncFilm{Title: "Ran"}
+	require.NoError(t, c.Insert(ctx, added))
+	kept.Title = "Aliens"
+	require.NoError(t, c.Update(ctx, kept))
+	kept.Title = "Alien 3"
+	require.NoError(t, c.Update(ctx, kept))
+	require.NoError(t, c.Delete(ctx, []string{gone.UID}))
+	events, err = w.poll(ctx, nil)
+	require.NoError(t, err)
+
+	byUID := make(map[string]ChangeEvent)
+	for _, ev := range events {
+		byUID[ev.UID] = ev
+	}
+	require.Len(t, byUID, 3)
+	require.Equal(t, ChangeUpdate, byUID[kept.UID].Op)
+	require.Equal(t, []string{"title"}, byUID[kept.UID].Predicates)
+	require.Equal(t, ChangeInsert, byUID[added.UID].Op)
+	require.Equal(t, "SyncFilm", byUID[added.UID].Type)
+	require.Equal(t, ChangeDelete, byUID[gone.UID].Op)
+
+	events, err = w.poll(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
@@ -0,0 +1,143 @@
+/*
+ * SPDX-FileCopyrightText: © 2017-2026 Istari Digital, Inc.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package modusgraph_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	mg "github.com/matthewmcneely/modusgraph"
+	"github.com/stretchr/testify/require"
+)
+
+type WatchItem struct {
+	UID   string   `json:"uid,omitempty"`
+	Name  string   `json:"wi_name,omitempty" dgraph:"index=exact"`
+	Count int      `json:"wi_count,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+type WatchOther struct {
+	UID   string   `json:"uid,omitempty"`
+	Label string   `json:"wo_label,omitempty"`
+	DType []string `json:"dgraph.type,omitempty"`
+}
+
+func TestWatch(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+		skip bool
+	}{
+		{
+			name: "WatchWithFileURI",
+			uri:  "file://" + GetTempDir(t),
+		},
+		{
+			name: "WatchWithDgraphURI",
+			uri:  "dgraph://" + os.Getenv("MODUSGRAPH_TEST_ADDR"),
+			skip: os.Getenv("MODUSGRAPH_TEST_ADDR") == "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.skip {
+				t.Skipf("Skipping %s: MODUSGRAPH_TEST_ADDR not set", tc.name)
+				return
+			}
+
+			client, cleanup := CreateTestClient(t, tc.uri)
+			defer cleanup()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// Watch only sees changes made after it has read the nodes, so
+			// bump a node already there until the first event shows up.
+			ping := &WatchItem{Name: "ping"}
+			require.NoError(t, client.Insert(ctx, ping))
+			other := &WatchOther{Label: "ignored"}
+			require.NoError(t, client.Insert(ctx, other))
+
+			events := make(chan mg.ChangeEvent, 100)
+			done := make(chan error, 1)
+			go func() {
+				for ev, err := range client.Watch(ctx, "WatchItem") {
+					if err != nil {
+						done <- err
+						return
+					}
+					events <- ev
+				}
+			}()
+			next := func() mg.ChangeEvent {
+				t.Helper()
+				select {
+				case ev := <-events:
+					return ev
+				case err := <-done:
+					t.Fatalf("Watch stopped: %v", err)
+				case <-time.After(30 * time.Second):
+					t.Fatal("timed out waiting for a change")
+				}
+				return mg.ChangeEvent{}
+			}
+			require.Eventually(t, func() bool {
+				ping.Count++
+				require.NoError(t, client.Update(ctx, ping))
+				select {
+				case ev := <-events:
+					require.Equal(t, mg.ChangeUpdate, ev.Op)
+					require.Equal(t, ping.UID, ev.UID)
+					return true
+				case <-time.After(500 * time.Millisecond):
+					return false
+				}
+			}, 30*time.Second, 10*time.Millisecond)
+			for len(events) > 0 {
+				<-events
+			}
+
+			item := &WatchItem{Name: "first", Count: 1}
+			require.NoError(t, client.Insert(ctx, item))
+			ev := next()
+			require.Equal(t, mg.ChangeInsert, ev.Op)
+			require.Equal(t, "WatchItem", ev.Type)
+			require.Equal(t, item.UID, ev.UID)
+			require.Equal(t, []string{"wi_count", "wi_name"}, ev.Predicates)
+
+			// Changes to other types are not delivered.
+			other.Label = "still ignored"
+			require.NoError(t, client.Update(ctx, other))
+
+			item.Count = 2
+			require.NoError(t, client.Update(ctx, item))
+			ev = next()
+			require.Equal(t, mg.ChangeUpdate, ev.Op)
+			require.Equal(t, item.UID, ev.UID)
+			require.Equal(t, []string{"wi_count"}, ev.Predicates)
+
+			require.NoError(t, client.Delete(ctx, []string{item.UID}))
+			ev = next()
+			require.Equal(t, mg.ChangeDelete, ev.Op)
+			require.Equal(t, "WatchItem", ev.Type)
+			require.Equal(t, item.UID, ev.UID)
+			require.Empty(t, ev.Predicates)
+
+			cancel()
+			select {
+			case err := <-done:
+				require.ErrorIs(t, err, context.Canceled)
+			case ev := <-events:
+				t.Fatalf("unexpected event %+v", ev)
+			case <-time.After(30 * time.Second):
+				t.Fatal("Watch did not stop")
+			}
+		})
+	}
+}